@@ -0,0 +1,163 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-flutter-desktop/go-flutter"
+	"github.com/pkg/errors"
+)
+
+// assetsKey is set by hover at compile-time when encrypt-assets is enabled in
+// hover.yaml. The kernel_blob.bin of the flutter assets is then shipped
+// encrypted, and decrypted to the user cache directory on launch.
+var assetsKey string
+
+func init() {
+	if assetsKey == "" {
+		return
+	}
+	assetsPath, err := decryptAssets()
+	if err != nil {
+		fmt.Printf("failed to decrypt the flutter assets: %v\n", err)
+		os.Exit(1)
+	}
+	options = append(options, flutter.ProjectAssetsPath(assetsPath))
+}
+
+// decryptAssets returns a flutter_assets directory with the decrypted
+// kernel_blob.bin, linking to the other assets. It is reused by the next
+// launches of the same build.
+func decryptAssets() (string, error) {
+	execPath, err := os.Executable()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to resolve executable path")
+	}
+	execPath, err = filepath.EvalSymlinks(execPath)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to eval symlinks for executable path")
+	}
+	assetsPath := filepath.Join(filepath.Dir(execPath), "flutter_assets")
+	encrypted, err := ioutil.ReadFile(filepath.Join(assetsPath, "kernel_blob.bin.enc"))
+	if err != nil {
+		return "", err
+	}
+
+	name := flutter.ProjectName
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(execPath), ".exe")
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(encrypted)
+	decryptedPath := filepath.Join(cacheDir, name, "flutter_assets-"+hex.EncodeToString(sum[:8]))
+	if _, err := os.Stat(filepath.Join(decryptedPath, "kernel_blob.bin")); err == nil {
+		return decryptedPath, nil
+	}
+
+	key, err := hex.DecodeString(assetsKey)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid key")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	if len(encrypted) < gcm.NonceSize() {
+		return "", errors.New("kernel_blob.bin.enc is truncated")
+	}
+	kernel, err := gcm.Open(nil, encrypted[:gcm.NonceSize()], encrypted[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decrypt kernel_blob.bin.enc")
+	}
+
+	// the assets of the previous versions of the app
+	previousPaths, _ := filepath.Glob(filepath.Join(cacheDir, name, "flutter_assets-*"))
+	for _, previousPath := range previousPaths {
+		os.RemoveAll(previousPath)
+	}
+	err = os.MkdirAll(filepath.Dir(decryptedPath), 0700)
+	if err != nil {
+		return "", err
+	}
+	// several instances of the app may be starting
+	partialPath, err := ioutil.TempDir(filepath.Dir(decryptedPath), "partial-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(partialPath)
+	entries, err := ioutil.ReadDir(assetsPath)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if entry.Name() == "kernel_blob.bin.enc" {
+			continue
+		}
+		err = linkOrCopy(filepath.Join(assetsPath, entry.Name()), filepath.Join(partialPath, entry.Name()))
+		if err != nil {
+			return "", err
+		}
+	}
+	err = ioutil.WriteFile(filepath.Join(partialPath, "kernel_blob.bin"), kernel, 0600)
+	if err != nil {
+		return "", err
+	}
+	err = os.Rename(partialPath, decryptedPath)
+	if err != nil {
+		if _, statErr := os.Stat(filepath.Join(decryptedPath, "kernel_blob.bin")); statErr == nil {
+			return decryptedPath, nil
+		}
+		return "", err
+	}
+	return decryptedPath, nil
+}
+
+// linkOrCopy links an asset, or copies it when symbolic links aren't
+// available (e.g. on windows without the developer mode).
+func linkOrCopy(src, dst string) error {
+	if os.Symlink(src, dst) == nil {
+		return nil
+	}
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, relativePath)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0700)
+		}
+		in, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(out, in)
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	})
+}
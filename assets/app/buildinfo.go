@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-flutter-desktop/go-flutter"
+	"github.com/go-flutter-desktop/go-flutter/plugin"
+)
+
+// buildInfo is set by hover at compile-time to the base64 of the JSON
+// describing the build: the version, the git commit, the build time and the
+// versions of hover, the engine and go-flutter. The same JSON is written to
+// assets/build_info.json next to the executable.
+var buildInfo string
+
+func init() {
+	if buildInfo == "" {
+		return
+	}
+	data, err := base64.StdEncoding.DecodeString(buildInfo)
+	if err != nil {
+		fmt.Printf("build info disabled: %v\n", err)
+		return
+	}
+	var info map[string]interface{}
+	err = json.Unmarshal(data, &info)
+	if err != nil {
+		fmt.Printf("build info disabled: %v\n", err)
+		return
+	}
+	options = append(options, flutter.AddPlugin(&buildInfoPlugin{info: info}))
+}
+
+// buildInfoPlugin returns the build info to dart, for an about or diagnostics
+// screen, with the method get of the hover/build-info channel: a map of
+// version, commit, buildTime, hoverVersion, engineVersion, goFlutterVersion,
+// os, arch and debug.
+type buildInfoPlugin struct {
+	info map[string]interface{}
+}
+
+func (p *buildInfoPlugin) InitPlugin(messenger plugin.BinaryMessenger) error {
+	channel := plugin.NewMethodChannel(messenger, "hover/build-info", plugin.StandardMethodCodec{})
+	channel.HandleFunc("get", p.handleGet)
+	return nil
+}
+
+func (p *buildInfoPlugin) handleGet(arguments interface{}) (interface{}, error) {
+	// the standard method codec encodes maps of interface{} keys
+	reply := make(map[interface{}]interface{}, len(p.info))
+	for key, value := range p.info {
+		reply[key] = value
+	}
+	return reply, nil
+}
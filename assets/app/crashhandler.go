@@ -0,0 +1,128 @@
+//go:build go1.23
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/go-flutter-desktop/go-flutter"
+)
+
+// crashReportURL may be set by hover at compile-time, using the
+// crash-report-url of hover.yaml. Crash reports are POSTed to it as JSON on
+// the next launch of the app.
+var crashReportURL string
+
+// pendingCrashReport receives the output of the go runtime when the app
+// crashes: unrecovered panics, and fatal signals such as a segmentation fault
+// in the flutter engine. It is empty when the app exits normally.
+const pendingCrashReport = "pending.log"
+
+func init() {
+	dir, err := crashReportsDir()
+	if err != nil {
+		fmt.Printf("crash handler disabled: %v\n", err)
+		return
+	}
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		fmt.Printf("crash handler disabled: %v\n", err)
+		return
+	}
+	pendingPath := filepath.Join(dir, pendingCrashReport)
+	if info, err := os.Stat(pendingPath); err == nil && info.Size() > 0 {
+		reportPath := filepath.Join(dir, "crash-"+info.ModTime().UTC().Format("20060102T150405Z")+".log")
+		err = os.Rename(pendingPath, reportPath)
+		if err == nil && crashReportURL != "" {
+			go uploadCrashReport(reportPath)
+		}
+	}
+	// Appending lets several instances of the app share the file.
+	pending, err := os.OpenFile(pendingPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		fmt.Printf("crash handler disabled: %v\n", err)
+		return
+	}
+	err = debug.SetCrashOutput(pending, debug.CrashOptions{})
+	if err != nil {
+		fmt.Printf("crash handler disabled: %v\n", err)
+	}
+	pending.Close()
+}
+
+// crashReportsDir returns the directory the crash reports are written to:
+// ~/.local/state/<app>/crashes on linux, ~/Library/Logs/<app>/crashes on
+// darwin and %LOCALAPPDATA%\<app>\crashes on windows.
+func crashReportsDir() (string, error) {
+	name := flutter.ProjectName
+	if name == "" {
+		execPath, err := os.Executable()
+		if err != nil {
+			return "", err
+		}
+		name = strings.TrimSuffix(filepath.Base(execPath), ".exe")
+	}
+	switch runtime.GOOS {
+	case "windows":
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, name, "crashes"), nil
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, "Library", "Logs", name, "crashes"), nil
+	default:
+		if state := os.Getenv("XDG_STATE_HOME"); state != "" {
+			return filepath.Join(state, name, "crashes"), nil
+		}
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, ".local", "state", name, "crashes"), nil
+	}
+}
+
+// uploadCrashReport sends a crash report to crashReportURL. Uploaded reports
+// are kept, with an .uploaded suffix.
+func uploadCrashReport(reportPath string) {
+	report, err := ioutil.ReadFile(reportPath)
+	if err != nil {
+		return
+	}
+	body, err := json.Marshal(map[string]string{
+		"name":    flutter.ProjectName,
+		"version": flutter.ProjectVersion,
+		"os":      runtime.GOOS,
+		"arch":    runtime.GOARCH,
+		"time":    time.Now().UTC().Format(time.RFC3339),
+		"report":  string(report),
+	})
+	if err != nil {
+		return
+	}
+	client := http.Client{Timeout: 30 * time.Second}
+	res, err := client.Post(crashReportURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		fmt.Printf("failed to upload crash report: %v\n", err)
+		return
+	}
+	res.Body.Close()
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		os.Rename(reportPath, reportPath+".uploaded")
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/go-flutter-desktop/go-flutter"
+)
+
+// firstRunURL may be set by hover at compile-time, when the survey of
+// hover.yaml is opted in. It is opened in the browser on the first launch of
+// the app by each user.
+var firstRunURL string
+
+func init() {
+	if firstRunURL == "" {
+		return
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return
+	}
+	markerPath := filepath.Join(configDir, flutter.ProjectName, "first-run")
+	if _, err := os.Stat(markerPath); !os.IsNotExist(err) {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(markerPath), 0755) != nil {
+		return
+	}
+	marker, err := os.Create(markerPath)
+	if err != nil {
+		return
+	}
+	marker.Close()
+
+	var open *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		open = exec.Command("rundll32", "url.dll,FileProtocolHandler", firstRunURL)
+	case "darwin":
+		open = exec.Command("open", firstRunURL)
+	default:
+		open = exec.Command("xdg-open", firstRunURL)
+	}
+	open.Start()
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// integrityCheck is set by hover at compile-time when integrity.manifest is
+// enabled in hover.yaml, and integrityPublicKey to the base64 of the public
+// key signing the manifest when integrity.key is set. The files next to the
+// executable are checked against integrity.json when the app starts, so a
+// corrupted or tampered install is reported instead of crashing later.
+var (
+	integrityCheck     string
+	integrityPublicKey string
+)
+
+func init() {
+	if integrityCheck != "true" {
+		return
+	}
+	err := checkIntegrity()
+	if err != nil {
+		fmt.Printf("the installation of the app is damaged, reinstall it: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func checkIntegrity() error {
+	execPath, err := os.Executable()
+	if err != nil {
+		return errors.Wrap(err, "failed to resolve executable path")
+	}
+	execPath, err = filepath.EvalSymlinks(execPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to eval symlinks for executable path")
+	}
+	dir := filepath.Dir(execPath)
+	data, err := ioutil.ReadFile(filepath.Join(dir, "integrity.json"))
+	if err != nil {
+		return err
+	}
+	if integrityPublicKey != "" {
+		err = verifyIntegritySignature(data, filepath.Join(dir, "integrity.json.sig"))
+		if err != nil {
+			return err
+		}
+	}
+	var manifest struct {
+		Files map[string]string `json:"files"`
+	}
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return errors.Wrap(err, "invalid integrity.json")
+	}
+	var damaged []string
+	for name, sum := range manifest.Files {
+		actual, err := sha256File(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil || actual != sum {
+			damaged = append(damaged, name)
+		}
+	}
+	if len(damaged) > 0 {
+		sort.Strings(damaged)
+		return errors.Errorf("%s missing or modified", strings.Join(damaged, ", "))
+	}
+	return nil
+}
+
+// verifyIntegritySignature verifies the Ed25519 signature of the manifest, or
+// the ECDSA signature of its sha256.
+func verifyIntegritySignature(data []byte, signaturePath string) error {
+	der, err := base64.StdEncoding.DecodeString(integrityPublicKey)
+	if err != nil {
+		return errors.Wrap(err, "invalid public key")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return errors.Wrap(err, "invalid public key")
+	}
+	encoded, err := ioutil.ReadFile(signaturePath)
+	if err != nil {
+		return err
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return errors.Wrap(err, "invalid integrity.json.sig")
+	}
+	var valid bool
+	switch publicKey := publicKey.(type) {
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(publicKey, data, sig)
+	case *ecdsa.PublicKey:
+		var rs struct{ R, S *big.Int }
+		if _, err := asn1.Unmarshal(sig, &rs); err == nil {
+			digest := sha256.Sum256(data)
+			valid = ecdsa.Verify(publicKey, digest[:], rs.R, rs.S)
+		}
+	}
+	if !valid {
+		return errors.New("integrity.json isn't signed by the publisher of the app")
+	}
+	return nil
+}
+
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
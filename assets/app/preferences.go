@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/go-flutter-desktop/go-flutter"
+	"github.com/go-flutter-desktop/go-flutter/plugin"
+	"github.com/pkg/errors"
+)
+
+// preferencesID is set by hover at compile-time to <organization>.<package>:
+// the GSettings schema on linux, the defaults domain on darwin and the
+// registry key in HKEY_CURRENT_USER\Software on windows. The packages install
+// the defaults of the preferences of hover.yaml: the GSettings schema of the
+// linux packages, Defaults.plist in the darwin bundle and the Defaults subkey
+// in the windows-msi package.
+var preferencesID string
+
+func init() {
+	if preferencesID == "" {
+		return
+	}
+	options = append(options, flutter.AddPlugin(&preferencesPlugin{}))
+}
+
+// preferencesPlugin reads and writes the preferences of the app from dart,
+// with the methods get ({'key': 'theme'}) and set ({'key': 'theme', 'value':
+// 'dark'}) of the hover/preferences channel. The values are strings, get
+// returns null for the preferences without value.
+type preferencesPlugin struct{}
+
+func (p *preferencesPlugin) InitPlugin(messenger plugin.BinaryMessenger) error {
+	channel := plugin.NewMethodChannel(messenger, "hover/preferences", plugin.StandardMethodCodec{})
+	channel.HandleFunc("get", p.handleGet)
+	channel.HandleFunc("set", p.handleSet)
+	return nil
+}
+
+func (p *preferencesPlugin) handleGet(arguments interface{}) (interface{}, error) {
+	args, _ := arguments.(map[interface{}]interface{})
+	key, _ := args["key"].(string)
+	if key == "" {
+		return nil, errors.New("missing key")
+	}
+	var out []byte
+	var err error
+	switch runtime.GOOS {
+	case "linux":
+		out, err = exec.Command("gsettings", "get", preferencesID, key).Output()
+	case "darwin":
+		out, err = exec.Command("defaults", "read", preferencesID, key).Output()
+		if err != nil {
+			// the app bundle has the defaults in Contents/Resources/Defaults.plist
+			var execPath string
+			execPath, err = os.Executable()
+			if err == nil {
+				defaultsPath := filepath.Join(filepath.Dir(execPath), "..", "Resources", "Defaults")
+				out, err = exec.Command("defaults", "read", defaultsPath, key).Output()
+			}
+		}
+	case "windows":
+		// the package installs the defaults in a subkey, the upgrades don't
+		// reset the values of the user
+		if value, ok := regQuery(`HKCU\Software\`+preferencesID, key); ok {
+			return value, nil
+		}
+		if value, ok := regQuery(`HKCU\Software\`+preferencesID+`\Defaults`, key); ok {
+			return value, nil
+		}
+		return nil, nil
+	}
+	if err != nil {
+		// the key has no value
+		return nil, nil
+	}
+	value := strings.TrimSpace(string(out))
+	// a GVariant on linux, the strings are quoted
+	if runtime.GOOS == "linux" && len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		value = strings.NewReplacer(`\'`, `'`, `\\`, `\`).Replace(value[1 : len(value)-1])
+	}
+	return value, nil
+}
+
+// regQuery reads a value of a registry key with reg.exe.
+func regQuery(registryKey, name string) (string, bool) {
+	out, err := exec.Command("reg", "query", registryKey, "/v", name).Output()
+	if err != nil {
+		return "", false
+	}
+	// <name>    REG_SZ    <value>
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), "    ", 3)
+		if len(fields) == 3 && fields[0] == name {
+			return strings.TrimSpace(fields[2]), true
+		}
+	}
+	return "", false
+}
+
+func (p *preferencesPlugin) handleSet(arguments interface{}) (interface{}, error) {
+	args, _ := arguments.(map[interface{}]interface{})
+	key, _ := args["key"].(string)
+	if key == "" {
+		return nil, errors.New("missing key")
+	}
+	value := fmt.Sprint(args["value"])
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux":
+		// the strings must be quoted, the type is in the schema
+		out, err := exec.Command("gsettings", "range", preferencesID, key).Output()
+		if err != nil {
+			return nil, errors.Wrapf(err, "unknown preference %s", key)
+		}
+		if strings.TrimSpace(string(out)) == "type s" {
+			value = "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+		}
+		cmd = exec.Command("gsettings", "set", preferencesID, key, value)
+	case "darwin":
+		cmd = exec.Command("defaults", "write", preferencesID, key, value)
+	case "windows":
+		cmd = exec.Command("reg", "add", `HKCU\Software\`+preferencesID, "/v", key, "/d", value, "/f")
+	default:
+		return nil, errors.Errorf("preferences aren't supported on %s", runtime.GOOS)
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, errors.Errorf("failed to set the preference %s: %s", key, strings.TrimSpace(string(out)))
+	}
+	return nil, nil
+}
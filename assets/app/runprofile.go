@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/go-flutter-desktop/go-flutter"
+)
+
+// HOVER_RUN_WINDOW_SIZE is set by `hover run --profile` to the window-size of
+// the run profile of hover.yaml, overriding the initial dimensions of
+// options.go.
+func init() {
+	size := os.Getenv("HOVER_RUN_WINDOW_SIZE")
+	if size == "" {
+		return
+	}
+	var width, height int
+	_, err := fmt.Sscanf(size, "%dx%d", &width, &height)
+	if err != nil {
+		fmt.Printf("invalid HOVER_RUN_WINDOW_SIZE %s: %v\n", size, err)
+		return
+	}
+	options = append(options, flutter.WindowInitialDimensions(width, height))
+}
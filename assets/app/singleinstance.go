@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/go-flutter-desktop/go-flutter"
+	"github.com/go-flutter-desktop/go-flutter/plugin"
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// singleInstanceID is set by hover at compile-time to <organization>.<package>
+// when single-instance is set in hover.yaml, followed by .debug for the debug
+// builds so `hover run` doesn't forward to an installed app. The later
+// launches of the app forward their arguments to the first instance through a
+// socket, and exit.
+var singleInstanceID string
+
+func init() {
+	if singleInstanceID == "" {
+		return
+	}
+	path := singleInstanceSocketPath()
+	if conn, err := net.Dial("unix", path); err == nil {
+		wd, _ := os.Getwd()
+		err = json.NewEncoder(conn).Encode(singleInstanceActivation{Args: os.Args[1:], WorkingDirectory: wd})
+		conn.Close()
+		if err == nil {
+			os.Exit(0)
+		}
+		fmt.Printf("failed to activate the running instance: %v\n", err)
+	}
+	// the socket of an instance that crashed is left behind
+	os.Remove(path)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		fmt.Printf("single instance disabled: %v\n", err)
+		return
+	}
+	options = append(options, flutter.AddPlugin(&singleInstancePlugin{listener: listener}))
+}
+
+// singleInstanceSocketPath returns the path of the socket of the first
+// instance, in a directory of the user.
+func singleInstanceSocketPath() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	name := singleInstanceID + ".sock"
+	if dir == "" {
+		dir = os.TempDir()
+		if runtime.GOOS == "linux" {
+			// /tmp is shared by the users
+			name = fmt.Sprintf("%s-%d.sock", singleInstanceID, os.Getuid())
+		}
+	}
+	return filepath.Join(dir, name)
+}
+
+// singleInstanceActivation is sent by a later launch to the first instance.
+type singleInstanceActivation struct {
+	Args             []string `json:"args"`
+	WorkingDirectory string   `json:"workingDirectory"`
+}
+
+// singleInstancePlugin calls the activate method of the hover/single-instance
+// channel with the arguments ({'args': [...], 'workingDirectory': '...'}) of
+// the later launches. The window is focused by calling the focus method of
+// the channel from dart, the window can only be focused from the main thread.
+type singleInstancePlugin struct {
+	listener net.Listener
+	channel  *plugin.MethodChannel
+	window   *glfw.Window
+}
+
+func (p *singleInstancePlugin) InitPlugin(messenger plugin.BinaryMessenger) error {
+	p.channel = plugin.NewMethodChannel(messenger, "hover/single-instance", plugin.StandardMethodCodec{})
+	p.channel.HandleFuncSync("focus", p.handleFocus)
+	go p.accept()
+	return nil
+}
+
+func (p *singleInstancePlugin) InitPluginGLFW(window *glfw.Window) error {
+	p.window = window
+	return nil
+}
+
+func (p *singleInstancePlugin) accept() {
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			fmt.Printf("single instance stopped: %v\n", err)
+			return
+		}
+		var activation singleInstanceActivation
+		err = json.NewDecoder(conn).Decode(&activation)
+		conn.Close()
+		if err != nil {
+			fmt.Printf("invalid activation of the single instance: %v\n", err)
+			continue
+		}
+		args := make([]interface{}, len(activation.Args))
+		for i, arg := range activation.Args {
+			args[i] = arg
+		}
+		err = p.channel.InvokeMethod("activate", map[interface{}]interface{}{
+			"args":             args,
+			"workingDirectory": activation.WorkingDirectory,
+		})
+		if err != nil {
+			fmt.Printf("failed to activate the single instance: %v\n", err)
+		}
+	}
+}
+
+func (p *singleInstancePlugin) handleFocus(arguments interface{}) (interface{}, error) {
+	if p.window == nil {
+		return nil, nil
+	}
+	if p.window.GetAttrib(glfw.Iconified) == glfw.True {
+		p.window.Restore()
+	}
+	p.window.Show()
+	p.window.Focus()
+	return nil, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// When dbus-activatable is set in hover.yaml, the first instance owns the
+// DBus name singleInstanceID and implements the org.freedesktop.Application
+// interface the desktops call to launch the app, the .desktop files of the
+// linux packages are DBusActivatable. The activations are forwarded to the
+// first instance like the later launches, through its socket: this file sorts
+// after singleinstance.go, whose init listens to the socket first.
+func init() {
+	if singleInstanceID == "" {
+		return
+	}
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		fmt.Printf("dbus activation disabled: %v\n", err)
+		return
+	}
+	path := dbus.ObjectPath("/" + strings.NewReplacer(".", "/", "-", "_").Replace(singleInstanceID))
+	err = conn.Export(dbusApplication{}, path, "org.freedesktop.Application")
+	if err != nil {
+		fmt.Printf("dbus activation disabled: %v\n", err)
+		conn.Close()
+		return
+	}
+	reply, err := conn.RequestName(singleInstanceID, dbus.NameFlagDoNotQueue)
+	if err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		fmt.Printf("dbus activation disabled: the name %s is owned by another process\n", singleInstanceID)
+		conn.Close()
+	}
+}
+
+// dbusApplication implements org.freedesktop.Application. The URIs opened
+// with the app are the arguments of the activation, the actions aren't
+// supported and activate the app.
+type dbusApplication struct{}
+
+func (dbusApplication) Activate(platformData map[string]dbus.Variant) *dbus.Error {
+	return dbusActivate(nil)
+}
+
+func (dbusApplication) Open(uris []string, platformData map[string]dbus.Variant) *dbus.Error {
+	return dbusActivate(uris)
+}
+
+func (dbusApplication) ActivateAction(action string, parameter []dbus.Variant, platformData map[string]dbus.Variant) *dbus.Error {
+	return dbusActivate(nil)
+}
+
+func dbusActivate(args []string) *dbus.Error {
+	conn, err := net.Dial("unix", singleInstanceSocketPath())
+	if err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	defer conn.Close()
+	wd, _ := os.Getwd()
+	err = json.NewEncoder(conn).Encode(singleInstanceActivation{Args: args, WorkingDirectory: wd})
+	if err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// The first instance holds a mutex named after singleInstanceID while it
+// runs, the app-mutex of the windows-inno installer, which asks to close the
+// app before installing or uninstalling it.
+func init() {
+	if singleInstanceID == "" {
+		return
+	}
+	name, err := syscall.UTF16PtrFromString(singleInstanceID)
+	if err != nil {
+		return
+	}
+	// the handle is released when the app exits
+	syscall.NewLazyDLL("kernel32.dll").NewProc("CreateMutexW").Call(0, 0, uintptr(unsafe.Pointer(name)))
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"os"
+	"runtime"
+)
+
+// wmClass is set by hover at compile-time to the wm-class of hover.yaml, the
+// StartupWMClass of the .desktop file of the linux packages.
+var wmClass string
+
+// On X11, GLFW names the window instance after RESOURCE_NAME. GNOME and KDE
+// match the StartupWMClass of the .desktop file against it, grouping the
+// windows of the app with its launcher in the dock and the taskbar.
+func init() {
+	if runtime.GOOS != "linux" || wmClass == "" || os.Getenv("RESOURCE_NAME") != "" {
+		return
+	}
+	os.Setenv("RESOURCE_NAME", wmClass)
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/enginecache"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/flutterversion"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// assertAOTSupported checks that the dart code can be compiled ahead-of-time
+// for the target. gen_snapshot compiles for the platform it runs on, the AOT
+// builds can't be cross-compiled.
+func assertAOTSupported(targetOS string) {
+	if !build.AOT() {
+		return
+	}
+	if buildDebug {
+		log.Errorf("The --aot and --debug flags are not compatible.")
+		os.Exit(1)
+	}
+	if buildDocker || targetOS != runtime.GOOS || build.TargetArch() != runtime.GOARCH {
+		log.Errorf("The AOT builds can't be cross-compiled, build for %s on a %s-%s host without --docker.", targetOS+"-"+build.TargetArch(), targetOS, build.TargetArch())
+		os.Exit(1)
+	}
+	if buildEncryptAssets || config.GetConfig().EncryptAssets {
+		log.Warnf("The AOT builds don't ship the kernel_blob.bin, encrypt-assets is ignored.")
+	}
+}
+
+// buildAOTSnapshot compiles the dart code of the app ahead-of-time to the AOT
+// library next to the executable, where go-flutter loads it from when the
+// engine runs AOT compiled code. The kernel_blob.bin of the flutter assets
+// isn't used by the release engine and is removed.
+func buildAOTSnapshot(targetOS string) {
+	flutterRoot := flutterversion.FlutterRoot()
+	dartSdkPath := filepath.Join(flutterRoot, "bin", "cache", "dart-sdk")
+	var frontendServerPath string
+	for _, path := range []string{
+		filepath.Join(dartSdkPath, "bin", "snapshots", "frontend_server_aot.dart.snapshot"),
+		filepath.Join(dartSdkPath, "bin", "snapshots", "frontend_server.dart.snapshot"),
+		filepath.Join(flutterRoot, "bin", "cache", "artifacts", "engine", targetOS+"-"+enginecache.PlatformArch(build.TargetArch()), "frontend_server.dart.snapshot"),
+	} {
+		if fileutils.IsFileExists(path) {
+			frontendServerPath = path
+			break
+		}
+	}
+	if frontendServerPath == "" {
+		log.Errorf("Failed to find the frontend_server snapshot of the Flutter SDK in %s, run `%s` first.", flutterRoot, log.Au().Magenta("flutter precache"))
+		os.Exit(1)
+	}
+
+	kernelSnapshotPath := filepath.Join(build.BuildPath, "build", "aot", filepath.Base(build.OutputDirectoryPath(targetOS)), "kernel_snapshot.dill")
+	err := os.MkdirAll(filepath.Dir(kernelSnapshotPath), 0775)
+	if err != nil {
+		log.Errorf("Failed to create the AOT directory: %v", err)
+		os.Exit(1)
+	}
+	cmdKernelSnapshot := exec.Command(build.OutputBinary(filepath.Join(dartSdkPath, "bin", "dart"), runtime.GOOS),
+		frontendServerPath,
+		"--sdk-root", filepath.Join(flutterRoot, "bin", "cache", "artifacts", "engine", "common", "flutter_patched_sdk_product")+string(filepath.Separator),
+		"--target=flutter",
+		"--aot",
+		"--tfa",
+		"-Ddart.vm.product=true",
+		"--packages", filepath.Join(".dart_tool", "package_config.json"),
+		"--output-dill", kernelSnapshotPath,
+		buildTarget,
+	)
+	cmdKernelSnapshot.Stderr = os.Stderr
+	cmdKernelSnapshot.Stdout = os.Stdout
+	log.Infof("Compiling the dart code ahead-of-time")
+	err = cmdKernelSnapshot.Run()
+	if err != nil {
+		log.Errorf("Failed to compile the kernel snapshot: %v", err)
+		os.Exit(1)
+	}
+
+	genSnapshotArgs := []string{
+		"--snapshot_kind=app-aot-elf",
+		"--elf=" + filepath.Join(build.OutputDirectoryPath(targetOS), build.AOTLibrary),
+	}
+	if buildObfuscate {
+		symbolsPath := filepath.Join(build.BuildPath, "build", "symbols", filepath.Base(build.OutputDirectoryPath(targetOS)))
+		err = os.MkdirAll(symbolsPath, 0775)
+		if err != nil {
+			log.Errorf("Failed to create the symbols directory: %v", err)
+			os.Exit(1)
+		}
+		genSnapshotArgs = append(genSnapshotArgs,
+			"--obfuscate",
+			"--dwarf-stack-traces",
+			"--save-debugging-info="+filepath.Join(symbolsPath, "app.symbols"),
+		)
+	}
+	genSnapshotArgs = append(genSnapshotArgs, kernelSnapshotPath)
+	cmdGenSnapshot := exec.Command(enginecache.GenSnapshotPath(engineCachePath, targetOS), genSnapshotArgs...)
+	cmdGenSnapshot.Stderr = os.Stderr
+	cmdGenSnapshot.Stdout = os.Stdout
+	err = cmdGenSnapshot.Run()
+	if err != nil {
+		log.Errorf("Failed to generate the AOT snapshot: %v", err)
+		os.Exit(1)
+	}
+
+	err = os.Remove(filepath.Join(build.OutputDirectoryPath(targetOS), "flutter_assets", "kernel_blob.bin"))
+	if err != nil && !os.IsNotExist(err) {
+		log.Errorf("Failed to remove kernel_blob.bin: %v", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/licenses"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+)
+
+var auditLicensesNotices string
+
+func init() {
+	auditLicensesCmd.Flags().StringVar(&auditLicensesNotices, "notices", "", "Write the licenses of all dependencies to this file, e.g. THIRD_PARTY_NOTICES, to bundle with the app.")
+	auditCmd.AddCommand(auditLicensesCmd)
+	rootCmd.AddCommand(auditCmd)
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Audit the dependencies of the app",
+}
+
+var auditLicensesCmd = &cobra.Command{
+	Use:   "licenses",
+	Short: "List the licenses of the go modules and pub packages, and flag those denied by the license policy",
+	Long: "List the licenses of the go modules the app is built with and of the pub packages of the flutter project.\n" +
+		"Dependencies with a license denied by the license-policy of go/hover.yaml (by default the copyleft licenses), or with an unknown license, are flagged and make the command fail.",
+	Run: func(cmd *cobra.Command, args []string) {
+		assertHoverInitialized()
+
+		goModules, err := licenses.GoModules(build.BuildPath)
+		if err != nil {
+			log.Errorf("Failed to list the go modules: %v", err)
+			os.Exit(1)
+		}
+		pubPackages, err := licenses.PubPackages(pubspec.GetPubSpec().Name)
+		if err != nil {
+			log.Errorf("Failed to list the pub packages: %v", err)
+			os.Exit(1)
+		}
+		dependencies := append(goModules, pubPackages...)
+
+		policy := config.GetConfig().LicensePolicy
+		deny := policy.Deny
+		if deny == nil {
+			deny = licenses.Copyleft
+		}
+		denied := make(map[string]bool)
+		for _, license := range deny {
+			denied[license] = true
+		}
+		exceptions := make(map[string]bool)
+		for _, exception := range policy.Exceptions {
+			exceptions[exception] = true
+		}
+
+		var flagged []licenses.Package
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, dependency := range dependencies {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", dependency.Source, dependency.Name, dependency.Version, dependency.License)
+			if exceptions[dependency.Name] {
+				continue
+			}
+			if denied[dependency.License] || (dependency.License == licenses.Unknown && !policy.AllowUnknown) {
+				flagged = append(flagged, dependency)
+			}
+		}
+		w.Flush()
+
+		if auditLicensesNotices != "" {
+			writeNotices(auditLicensesNotices, dependencies)
+		}
+
+		if len(flagged) > 0 {
+			for _, dependency := range flagged {
+				log.Warnf("%s %s: %s license", dependency.Source, dependency.Name, dependency.License)
+			}
+			log.Errorf("%d dependencies don't comply with the license policy. Review them, and add them to license-policy.exceptions in go/hover.yaml once approved.", len(flagged))
+			os.Exit(1)
+		}
+		log.Infof("The %d dependencies comply with the license policy.", len(dependencies))
+	},
+}
+
+func writeNotices(path string, dependencies []licenses.Package) {
+	var b strings.Builder
+	b.WriteString("This application uses the following third party software.\n")
+	for _, dependency := range dependencies {
+		b.WriteString("\n" + strings.Repeat("-", 80) + "\n\n")
+		b.WriteString(strings.TrimSpace(dependency.Name+" "+dependency.Version) + "\n")
+		b.WriteString("License: " + dependency.License + "\n\n")
+		if dependency.LicenseText != "" {
+			b.WriteString(strings.TrimSpace(dependency.LicenseText) + "\n")
+		}
+	}
+	err := ioutil.WriteFile(path, []byte(b.String()), 0644)
+	if err != nil {
+		log.Errorf("Failed to write %s: %v", path, err)
+		os.Exit(1)
+	}
+	log.Infof("Wrote the licenses of the dependencies to %s", path)
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/go-flutter-desktop/hover/internal/enginecache"
 
@@ -18,9 +19,12 @@ import (
 	"github.com/go-flutter-desktop/hover/internal/androidmanifest"
 	"github.com/go-flutter-desktop/hover/internal/build"
 	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/explain"
 	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/flutterversion"
 	"github.com/go-flutter-desktop/hover/internal/log"
 	"github.com/go-flutter-desktop/hover/internal/pubspec"
+	"github.com/go-flutter-desktop/hover/internal/timings"
 	"github.com/go-flutter-desktop/hover/internal/versioncheck"
 )
 
@@ -33,6 +37,7 @@ var (
 	buildCachePath       string
 	buildOpenGlVersion   string
 	buildEngineVersion   string
+	buildDartDefines     []string // <name>=<value>, set by the run profiles
 
 	// `hover build`-only build flags
 	buildDocker                 bool
@@ -40,42 +45,62 @@ var (
 	buildVersionNumber          string
 	buildSkipEngineDownload     bool
 	buildSkipFlutterBuildBundle bool
+	buildLocalEngine            string
+	buildArch                   string
+	buildEncryptAssets          bool
+	buildObfuscate              bool
+	buildAOT                    bool
+	buildTimings                string
+	buildWindowsConsole         string
 )
 
 const mingwGccBinName = "x86_64-w64-mingw32-gcc"
 const clangBinName = "o32-clang"
 
+// crossCompilers are the C compilers of the non-amd64 targets, by target OS
+// and architecture.
+var crossCompilers = map[string]string{
+	"linux-arm64":   "aarch64-linux-gnu-gcc",
+	"windows-arm64": "aarch64-w64-mingw32-gcc",
+	"darwin-arm64":  "oa64-clang",
+}
+
 var engineCachePath string
 
 func init() {
 	buildCmd.PersistentFlags().StringVarP(&buildTarget, "target", "t", config.BuildTargetDefault, "The main entry-point file of the application.")
 	buildCmd.PersistentFlags().StringVarP(&buildGoFlutterBranch, "branch", "b", config.BuildBranchDefault, "The 'go-flutter' version to use. (@master or @v0.20.0 for example)")
 	buildCmd.PersistentFlags().StringVar(&buildEngineVersion, "engine-version", config.BuildEngineDefault, "The flutter engine version to use.")
+	buildCmd.PersistentFlags().StringVar(&buildLocalEngine, "local-engine", "", "The path of a locally built flutter engine to use instead of downloading it (e.g. engine/src/out/host_release).")
 	buildCmd.PersistentFlags().StringVar(&buildCachePath, "cache-path", "", "The path that hover uses to cache dependencies such as the Flutter engine .so/.dll (defaults to the standard user cache directory)")
 	buildCmd.PersistentFlags().StringVar(&buildOpenGlVersion, "opengl", config.BuildOpenGlVersionDefault, "The OpenGL version specified here is only relevant for external texture plugin (i.e. video_plugin).\nIf 'none' is provided, texture won't be supported. Note: the Flutter Engine still needs a OpenGL compatible context.")
 	buildCmd.PersistentFlags().StringVar(&buildVersionNumber, "version-number", "", "Override the version number used in build and packaging. You may use it with $(git describe --tags)")
 	buildCmd.PersistentFlags().BoolVar(&buildDebug, "debug", false, "Build a debug version of the app.")
+	buildCmd.PersistentFlags().StringVar(&buildArch, "arch", build.DefaultTargetArch, "The architecture to build for: amd64 or arm64. The outputs of each architecture are kept in their own directory, e.g. go/build/outputs/linux-arm64.")
+	buildCmd.PersistentFlags().BoolVar(&buildEncryptAssets, "encrypt-assets", false, "Encrypt the compiled dart code (flutter_assets/kernel_blob.bin), decrypted by go/cmd/assetsdecrypt.go when the app starts. Can be enabled with encrypt-assets in go/hover.yaml.")
+	buildCmd.PersistentFlags().BoolVar(&buildObfuscate, "obfuscate", false, "Pass --obfuscate to the Dart compiler, with the symbols written to go/build/symbols. Flutter only obfuscates the code compiled ahead-of-time.")
+	buildCmd.PersistentFlags().BoolVar(&buildAOT, "aot", false, "Compile the dart code ahead-of-time and run it with a release engine, instead of shipping the kernel to a debug engine. The AOT builds can't be cross-compiled.")
 	buildCmd.PersistentFlags().BoolVar(&buildDocker, "docker", false, "Execute the go build and packaging in a docker container. The Flutter build is always run locally.")
 	buildCmd.PersistentFlags().BoolVar(&buildSkipEngineDownload, "skip-engine-download", false, "Skip donwloading the Flutter Engine and artifacts.")
 	buildCmd.PersistentFlags().BoolVar(&buildSkipFlutterBuildBundle, "skip-flutter-build-bundle", false, "Skip the 'flutter build bundle' step.")
+	buildCmd.PersistentFlags().BoolVar(&buildSkipSigning, "skip-signing", false, "Don't sign the windows executable and packages, nor notarize the darwin packages, even when configured in go/hover.yaml.")
+	buildCmd.PersistentFlags().BoolVar(&buildSkipPreflight, "skip-preflight", false, "Skip checking the free space and permissions of the output and temporary directories before building.")
+	buildCmd.PersistentFlags().BoolVar(&buildProvenance, "provenance", false, "Write a SLSA provenance attestation of the artifacts to go/build/provenance.")
+	buildCmd.PersistentFlags().StringVar(&buildTimings, "timings", "text", "Print the time spent in each phase of the build, compared to the previous builds: text, json or none. The history is kept in go/build/timings.json.")
+	buildCmd.PersistentFlags().StringVar(&buildWindowsConsole, "windows-console", "", "When the windows executable opens a console window showing the logs of the app: debug (only the --debug builds), always or never. Overrides windows-console of go/hover.yaml.")
+	buildCmd.PersistentFlags().BoolVar(&packaging.NoCache, "no-packaging-cache", false, "Always run the packaging, even when its inputs didn't change since a previous build.")
+	buildCmd.PersistentFlags().BoolVar(&packaging.NoVersionInFilename, "no-version-in-filename", false, "Leave the version out of the artifact names in go/build/outputs, e.g. myapp-amd64.deb, for stable download links.")
 	buildCmd.AddCommand(buildLinuxCmd)
-	buildCmd.AddCommand(buildLinuxSnapCmd)
-	buildCmd.AddCommand(buildLinuxDebCmd)
-	buildCmd.AddCommand(buildLinuxAppImageCmd)
-	buildCmd.AddCommand(buildLinuxRpmCmd)
-	buildCmd.AddCommand(buildLinuxPkgCmd)
 	buildCmd.AddCommand(buildDarwinCmd)
-	buildCmd.AddCommand(buildDarwinBundleCmd)
-	buildCmd.AddCommand(buildDarwinPkgCmd)
-	buildCmd.AddCommand(buildDarwinDmgCmd)
 	buildCmd.AddCommand(buildWindowsCmd)
-	buildCmd.AddCommand(buildWindowsMsiCmd)
+	buildCmd.AddCommand(buildFreebsdCmd)
 	rootCmd.AddCommand(buildCmd)
 }
 
 var buildCmd = &cobra.Command{
-	Use:   "build",
-	Short: "Build a desktop release",
+	Use:               "build",
+	Short:             "Build a desktop release",
+	ValidArgsFunction: completeSubcommands,
 }
 
 var buildLinuxCmd = &cobra.Command{
@@ -86,46 +111,6 @@ var buildLinuxCmd = &cobra.Command{
 	},
 }
 
-var buildLinuxSnapCmd = &cobra.Command{
-	Use:   "linux-snap",
-	Short: "Build a desktop release for linux and package it for snap",
-	Run: func(cmd *cobra.Command, args []string) {
-		subcommandBuild("linux", packaging.LinuxSnapTask)
-	},
-}
-
-var buildLinuxDebCmd = &cobra.Command{
-	Use:   "linux-deb",
-	Short: "Build a desktop release for linux and package it for deb",
-	Run: func(cmd *cobra.Command, args []string) {
-		subcommandBuild("linux", packaging.LinuxDebTask)
-	},
-}
-
-var buildLinuxAppImageCmd = &cobra.Command{
-	Use:   "linux-appimage",
-	Short: "Build a desktop release for linux and package it for AppImage",
-	Run: func(cmd *cobra.Command, args []string) {
-		subcommandBuild("linux", packaging.LinuxAppImageTask)
-	},
-}
-
-var buildLinuxRpmCmd = &cobra.Command{
-	Use:   "linux-rpm",
-	Short: "Build a desktop release for linux and package it for rpm",
-	Run: func(cmd *cobra.Command, args []string) {
-		subcommandBuild("linux", packaging.LinuxRpmTask)
-	},
-}
-
-var buildLinuxPkgCmd = &cobra.Command{
-	Use:   "linux-pkg",
-	Short: "Build a desktop release for linux and package it for pacman pkg",
-	Run: func(cmd *cobra.Command, args []string) {
-		subcommandBuild("linux", packaging.LinuxPkgTask)
-	},
-}
-
 var buildDarwinCmd = &cobra.Command{
 	Use:   "darwin",
 	Short: "Build a desktop release for darwin",
@@ -134,30 +119,6 @@ var buildDarwinCmd = &cobra.Command{
 	},
 }
 
-var buildDarwinBundleCmd = &cobra.Command{
-	Use:   "darwin-bundle",
-	Short: "Build a desktop release for darwin and package it for OSX bundle",
-	Run: func(cmd *cobra.Command, args []string) {
-		subcommandBuild("darwin", packaging.DarwinBundleTask)
-	},
-}
-
-var buildDarwinPkgCmd = &cobra.Command{
-	Use:   "darwin-pkg",
-	Short: "Build a desktop release for darwin and package it for OSX pkg installer",
-	Run: func(cmd *cobra.Command, args []string) {
-		subcommandBuild("darwin", packaging.DarwinPkgTask)
-	},
-}
-
-var buildDarwinDmgCmd = &cobra.Command{
-	Use:   "darwin-dmg",
-	Short: "Build a desktop release for darwin and package it for OSX dmg",
-	Run: func(cmd *cobra.Command, args []string) {
-		subcommandBuild("darwin", packaging.DarwinDmgTask)
-	},
-}
-
 var buildWindowsCmd = &cobra.Command{
 	Use:   "windows",
 	Short: "Build a desktop release for windows",
@@ -166,11 +127,11 @@ var buildWindowsCmd = &cobra.Command{
 	},
 }
 
-var buildWindowsMsiCmd = &cobra.Command{
-	Use:   "windows-msi",
-	Short: "Build a desktop release for windows and package it for msi",
+var buildFreebsdCmd = &cobra.Command{
+	Use:   "freebsd",
+	Short: "Build a desktop release for freebsd",
 	Run: func(cmd *cobra.Command, args []string) {
-		subcommandBuild("windows", packaging.WindowsMsiTask)
+		subcommandBuild("freebsd", packaging.NoopTask)
 	},
 }
 
@@ -178,11 +139,45 @@ var buildWindowsMsiCmd = &cobra.Command{
 func subcommandBuild(targetOS string, packagingTask packaging.Task) {
 	assertHoverInitialized()
 	packagingTask.AssertInitialized()
+	build.SetTargetArch(buildArch)
+	build.SetAOT(buildAOT)
+	assertAOTSupported(targetOS)
+	assertFreebsdSupported(targetOS)
+	if buildTimings != "text" && buildTimings != "json" && buildTimings != "none" {
+		log.Errorf("Invalid --timings %s, use text, json or none.", buildTimings)
+		os.Exit(1)
+	}
+	switch buildWindowsConsole {
+	case "", config.WindowsConsoleDebug, config.WindowsConsoleAlways, config.WindowsConsoleNever:
+	default:
+		log.Errorf("Invalid --windows-console %s, use debug, always or never.", buildWindowsConsole)
+		os.Exit(1)
+	}
+
+	if buildWithWebhooks(targetOS, packagingTask) {
+		return
+	}
+	steps := postBuildSteps(targetOS, packagingTask)
+	signer := newBuildSigner(targetOS)
+	notarizer := newBuildNotarizer(steps)
+	provenanceKey := loadProvenanceKey()
+	assertBuildPreflight(targetOS, packagingTask)
+	warnWindowsFilesystem()
+	assertAssetsDecryptShim()
+	assertIntegrityShim()
+	assertFirstRunShim()
+	assertWMClassShim(targetOS)
+	assertBuildInfoShim()
+	assertSingleInstanceShim()
+	assertDBusActivationShim(targetOS)
+	startedOn := time.Now()
 
 	if !buildSkipFlutterBuildBundle {
 		cleanBuildOutputsDir(targetOS)
 		buildFlutterBundle(targetOS)
 	}
+	assertLocales(targetOS)
+	checkFontLicenses(targetOS)
 	if buildDocker {
 		var buildFlags []string
 		buildFlags = append(buildFlags, commonFlags()...)
@@ -194,10 +189,52 @@ func subcommandBuild(targetOS string, packagingTask packaging.Task) {
 		if buildDebug {
 			buildFlags = append(buildFlags, "--debug")
 		}
+		if buildArch != build.DefaultTargetArch {
+			buildFlags = append(buildFlags, "--arch", buildArch)
+		}
+		if buildEncryptAssets {
+			buildFlags = append(buildFlags, "--encrypt-assets")
+		}
+		if packaging.NoCache {
+			buildFlags = append(buildFlags, "--no-packaging-cache")
+		}
+		if packaging.NoVersionInFilename {
+			buildFlags = append(buildFlags, "--no-version-in-filename")
+		}
+		if buildWindowsConsole != "" {
+			buildFlags = append(buildFlags, "--windows-console", buildWindowsConsole)
+		}
+		// the build in the container is timed as a whole
+		buildFlags = append(buildFlags, "--timings", "none")
+		// the credentials, jsign and notarytool aren't available in the
+		// container, the artifacts are signed and notarized afterwards.
+		buildFlags = append(buildFlags, "--skip-signing")
+		if plugins, _ := listFFIPlugins(targetOS); len(plugins) > 0 {
+			log.Warnf("The native libraries of the FFI plugins aren't built in the docker container, build without --docker to bundle them.")
+		}
+		stopTiming := timings.Start(timings.DockerBuild)
 		dockerHoverBuild(targetOS, packagingTask, buildFlags, nil)
+		stopTiming()
+		runPostBuildSteps(steps, signer, notarizer, targetOS, packagingTask, true)
 	} else {
 		buildGoBinary(targetOS, nil)
-		packagingTask.Pack(buildVersionNumber)
+		runPostBuildSteps(steps, signer, notarizer, targetOS, packagingTask, false)
+	}
+	checkSizeBudgets(targetOS, packagingTask)
+	writeProvenance(provenanceKey, targetOS, targetName(targetOS, packagingTask), startedOn)
+	reportTimings(targetName(targetOS, packagingTask), startedOn)
+}
+
+// assertFreebsdSupported asserts the freebsd builds are run on a freebsd
+// host, for its architecture: cgo has no cross-compiler for freebsd, and the
+// docker image is a linux one.
+func assertFreebsdSupported(targetOS string) {
+	if targetOS != "freebsd" {
+		return
+	}
+	if buildDocker || runtime.GOOS != "freebsd" || build.TargetArch() != runtime.GOARCH {
+		log.Errorf("The freebsd builds can't be cross-compiled, build for %s on a freebsd-%s host without --docker.", targetOS+"-"+build.TargetArch(), build.TargetArch())
+		os.Exit(1)
 	}
 }
 
@@ -226,11 +263,46 @@ func initBuildParameters(targetOS string) {
 		buildVersionNumber = pubspec.GetPubSpec().GetVersion()
 	}
 
+	if buildLocalEngine == "" && config.GetConfig().LocalEngine != "" {
+		buildLocalEngine = config.GetConfig().LocalEngine
+	}
+
+	stopTiming := timings.Start(timings.EngineDownload)
 	if buildSkipEngineDownload {
 		engineCachePath = enginecache.EngineCachePath(targetOS, buildCachePath)
+	} else if buildLocalEngine != "" {
+		engineCachePath = enginecache.UseLocalEngine(targetOS, buildCachePath, buildLocalEngine, buildEngineVersion)
 	} else {
 		engineCachePath = enginecache.ValidateOrUpdateEngine(targetOS, buildEngineVersion)
 	}
+	stopTiming()
+	recordKnownProject()
+
+	// the flutter framework version matters when the flutter bundle is built,
+	// which is never the case in the docker container.
+	if !buildSkipFlutterBuildBundle {
+		assertEngineCompatibility()
+	}
+}
+
+// assertEngineCompatibility asserts the engine is the one required by the
+// flutter framework. Incompatible versions build apps crashing on launch.
+func assertEngineCompatibility() {
+	if os.Getenv("HOVER_IGNORE_ENGINE_COMPATIBILITY") == "true" {
+		return
+	}
+	flutterVersion := flutterversion.FlutterFrameworkVersion()
+
+	// An engine version set in the configuration was already warned about,
+	// and the local engines are checked when copied to the cache.
+	cachedEngineVersion := enginecache.CachedEngineVersion(engineCachePath)
+	if buildEngineVersion == config.BuildEngineDefault && buildLocalEngine == "" {
+		requiredEngineVersion := flutterversion.FlutterRequiredEngineVersion()
+		if cachedEngineVersion != requiredEngineVersion {
+			log.ErrorCodef(explain.EngineVersionMismatch, "Flutter %s requires the engine %s, but the engine in %s is %q.\n       Build without `%s` to download the engine required by flutter.", flutterVersion, requiredEngineVersion, engineCachePath, cachedEngineVersion, log.Au().Magenta("--skip-engine-download"))
+			os.Exit(1)
+		}
+	}
 }
 
 func commonFlags() []string {
@@ -300,11 +372,13 @@ func buildFlutterBundle(targetOS string) {
 		log.Printf("listing available plugins:")
 		if hoverPluginGet(true) {
 			// TODO: change this so that it only logs when there are plugins missing..
-			log.Infof(fmt.Sprintf("Run `%s` to update plugins", log.Au().Magenta("hover plugins get")))
+			log.Infof("Run `%s` to update plugins", log.Au().Magenta("hover plugins get"))
 		}
 	}
 
 	checkFlutterChannel()
+	assertFlutterVersion()
+	defer timings.Start(timings.FlutterBuild)()
 
 	var flutterBuildBundleArgs = []string{
 		"build", "bundle",
@@ -314,6 +388,16 @@ func buildFlutterBundle(targetOS string) {
 	if buildDebug {
 		flutterBuildBundleArgs = append(flutterBuildBundleArgs, "--track-widget-creation")
 	}
+	for _, define := range buildDartDefines {
+		flutterBuildBundleArgs = append(flutterBuildBundleArgs, "--dart-define="+define)
+	}
+	// the AOT snapshot is obfuscated by gen_snapshot
+	if buildObfuscate && !build.AOT() {
+		flutterBuildBundleArgs = append(flutterBuildBundleArgs,
+			"--obfuscate",
+			"--split-debug-info", filepath.Join(build.BuildPath, "build", "symbols", filepath.Base(build.OutputDirectoryPath(targetOS))),
+		)
+	}
 	cmdFlutterBuildBundle := exec.Command(build.FlutterBin(), flutterBuildBundleArgs...)
 	cmdFlutterBuildBundle.Stderr = os.Stderr
 	cmdFlutterBuildBundle.Stdout = os.Stdout
@@ -321,9 +405,16 @@ func buildFlutterBundle(targetOS string) {
 	log.Infof("Building flutter bundle")
 	err = cmdFlutterBuildBundle.Run()
 	if err != nil {
-		log.Errorf("Flutter build failed: %v", err)
+		log.ErrorCodef(explain.FlutterBuildFailed, "Flutter build failed: %v", err)
 		os.Exit(1)
 	}
+	if encryptAssets() {
+		err = encryptFlutterAssets(targetOS)
+		if err != nil {
+			log.Errorf("Failed to encrypt the flutter assets: %v", err)
+			os.Exit(1)
+		}
+	}
 }
 
 func buildGoBinary(targetOS string, vmArguments []string) {
@@ -332,6 +423,7 @@ func buildGoBinary(targetOS string, vmArguments []string) {
 	}
 	initBuildParameters(targetOS)
 
+	stopTiming := timings.Start(timings.Copy)
 	fileutils.CopyDir(build.IntermediatesDirectoryPath(targetOS), build.OutputDirectoryPath(targetOS))
 
 	outputEngineFile := filepath.Join(build.OutputDirectoryPath(targetOS), build.EngineFilename(targetOS))
@@ -357,6 +449,13 @@ func buildGoBinary(targetOS string, vmArguments []string) {
 		filepath.Join(build.BuildPath, "assets"),
 		filepath.Join(build.OutputDirectoryPath(targetOS), "assets"),
 	)
+	stopTiming()
+
+	if build.AOT() && !buildSkipFlutterBuildBundle {
+		stopTiming = timings.Start(timings.FlutterBuild)
+		buildAOTSnapshot(targetOS)
+		stopTiming()
+	}
 
 	wd, err := os.Getwd()
 	if err != nil {
@@ -406,7 +505,7 @@ func buildGoBinary(targetOS string, vmArguments []string) {
 		log.Warnf("The '--opengl=none' flag makes go-flutter incompatible with texture plugins!")
 	}
 
-	if !buildDebug && targetOS == "linux" {
+	if !buildDebug && (targetOS == "linux" || targetOS == "freebsd") {
 		err = exec.Command("strip", "-s", outputEngineFile).Run()
 		if err != nil {
 			log.Errorf("Failed to strip %s: %v", outputEngineFile, err)
@@ -425,24 +524,35 @@ func buildGoBinary(targetOS string, vmArguments []string) {
 	cmdGoBuild.Stdout = os.Stdout
 
 	log.Infof("Compiling 'go-flutter' and plugins")
+	stopTiming = timings.Start(timings.GoBuild)
 	err = cmdGoBuild.Run()
+	stopTiming()
 	if err != nil {
-		log.Errorf("Go build failed: %v", err)
+		log.ErrorCodef(explain.GoBuildFailed, "Go build failed: %v", err)
 		os.Exit(1)
 	}
+	bundleFFILibraries(targetOS)
+	writeBuildInfo(targetOS)
+	if integrityCheck() {
+		err = packaging.WriteIntegrityManifest(build.OutputDirectoryPath(targetOS), targetOS)
+		if err != nil {
+			log.Errorf("Failed to write the integrity manifest: %v", err)
+			os.Exit(1)
+		}
+	}
 	log.Infof("Successfully compiled")
 }
 
 func buildEnv(targetOS string, engineCachePath string) []string {
 	var cgoLdflags string
 
-	outputDirPath := filepath.Join("build", "outputs", targetOS)
+	outputDirPath := filepath.Join("build", "outputs", filepath.Base(build.OutputDirectoryPath(targetOS)))
 
 	switch targetOS {
 	case "darwin":
 		cgoLdflags = fmt.Sprintf("-F%s -Wl,-rpath,@executable_path", engineCachePath)
 		cgoLdflags = fmt.Sprintf("%s -F%s -L%s", cgoLdflags, outputDirPath, outputDirPath)
-	case "linux":
+	case "linux", "freebsd":
 		cgoLdflags = fmt.Sprintf("-L%s -L%s", engineCachePath, outputDirPath)
 	case "windows":
 		cgoLdflags = fmt.Sprintf("-L%s -L%s", engineCachePath, outputDirPath)
@@ -454,22 +564,49 @@ func buildEnv(targetOS string, engineCachePath string) []string {
 		"GO111MODULE=on",
 		"CGO_LDFLAGS=" + cgoLdflags,
 		"GOOS=" + targetOS,
-		"GOARCH=amd64",
+		"GOARCH=" + build.TargetArch(),
 		"CGO_ENABLED=1",
 	}
-	if runtime.GOOS == "linux" {
+	if cc := crossCompiler(targetOS); cc != "" {
+		env = append(env,
+			"CC="+cc,
+		)
+	}
+	return env
+}
+
+// crossCompiler returns the C compiler used to cross-compile for a target,
+// empty when the C compiler of the go environment is used.
+func crossCompiler(targetOS string) string {
+	if crossCompiler, ok := crossCompilers[targetOS+"-"+build.TargetArch()]; ok {
+		if runtime.GOOS != targetOS || runtime.GOARCH != build.TargetArch() {
+			return crossCompiler
+		}
+	} else if runtime.GOOS == "linux" {
 		if targetOS == "windows" {
-			env = append(env,
-				"CC="+mingwGccBinName,
-			)
+			return mingwGccBinName
 		}
 		if targetOS == "darwin" {
-			env = append(env,
-				"CC="+clangBinName,
-			)
+			return clangBinName
 		}
 	}
-	return env
+	return ""
+}
+
+// windowsConsole returns whether the windows executable is built with a
+// console window, showing the logs of the app.
+func windowsConsole() bool {
+	mode := buildWindowsConsole
+	if mode == "" {
+		mode = config.GetConfig().GetWindowsConsole()
+	}
+	switch mode {
+	case config.WindowsConsoleAlways:
+		return true
+	case config.WindowsConsoleNever:
+		return false
+	}
+	return buildDebug
 }
 
 func buildCommand(targetOS string, vmArguments []string, outputBinaryPath string) []string {
@@ -484,13 +621,46 @@ func buildCommand(targetOS string, vmArguments []string, outputBinaryPath string
 		vmArguments = append(vmArguments, "--disable-dart-asserts")
 		vmArguments = append(vmArguments, "--disable-observatory")
 
-		if targetOS == "windows" {
-			ldflags = append(ldflags, "-H=windowsgui")
+		// the debug symbols are moved to a companion package when packaging
+		if !config.GetConfig().SplitPackages.DebugSymbols {
+			ldflags = append(ldflags, "-s")
+			ldflags = append(ldflags, "-w")
 		}
-		ldflags = append(ldflags, "-s")
-		ldflags = append(ldflags, "-w")
+	}
+	if targetOS == "windows" && !windowsConsole() {
+		ldflags = append(ldflags, "-H=windowsgui")
 	}
 	ldflags = append(ldflags, fmt.Sprintf("-X main.vmArguments=%s", strings.Join(vmArguments, ";")))
+	if firstRunURL, _ := config.GetConfig().GetSurveyURLs(); firstRunURL != "" {
+		ldflags = append(ldflags, fmt.Sprintf("-X main.firstRunURL=%s", firstRunURL))
+	}
+	if crashReportURL := config.GetConfig().CrashReportURL; crashReportURL != "" {
+		ldflags = append(ldflags, fmt.Sprintf("-X main.crashReportURL=%s", crashReportURL))
+	}
+	if encryptAssets() {
+		ldflags = append(ldflags, fmt.Sprintf("-X main.assetsKey=%s", assetsKey(targetOS)))
+	}
+	if integrityCheck() {
+		ldflags = append(ldflags, "-X main.integrityCheck=true")
+		if publicKey := packaging.IntegrityPublicKey(); publicKey != "" {
+			ldflags = append(ldflags, fmt.Sprintf("-X main.integrityPublicKey=%s", publicKey))
+		}
+	}
+	if len(config.GetConfig().Preferences) > 0 {
+		ldflags = append(ldflags, fmt.Sprintf("-X main.preferencesID=%s", packaging.PreferencesID(pubspec.GetPubSpec().Name)))
+	}
+	if config.GetConfig().SingleInstance {
+		singleInstanceID := packaging.SingleInstanceID(pubspec.GetPubSpec().Name)
+		if buildDebug {
+			// hover run doesn't forward to an installed release of the app
+			singleInstanceID += ".debug"
+		}
+		ldflags = append(ldflags, fmt.Sprintf("-X main.singleInstanceID=%s", singleInstanceID))
+	}
+	if targetOS == "linux" {
+		ldflags = append(ldflags, fmt.Sprintf("-X main.wmClass=%s", config.GetConfig().GetWMClass(pubspec.GetPubSpec().Name)))
+	}
+	ldflags = append(ldflags, buildInfoLdflag(targetOS, currentTag))
 	// overwrite go-flutter build-constants values
 	ldflags = append(ldflags, fmt.Sprintf(
 		"-X github.com/go-flutter-desktop/go-flutter.ProjectVersion=%s "+
@@ -502,10 +672,16 @@ func buildCommand(targetOS string, vmArguments []string, outputBinaryPath string
 		pubspec.GetPubSpec().Name,
 		androidmanifest.AndroidOrganizationName()))
 
+	tags := "opengl" + buildOpenGlVersion
+	// go-gl/glfw builds the wayland backend of GLFW instead of the X11 one
+	if targetOS == "linux" && config.GetConfig().GetDisplayServer() == config.DisplayServerWayland {
+		tags += ",wayland"
+	}
+
 	outputCommand := []string{
 		"go",
 		"build",
-		"-tags=opengl" + buildOpenGlVersion,
+		"-tags=" + tags,
 		"-o", outputBinaryPath,
 		"-v",
 	}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/enginecache"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/versioncheck"
+)
+
+// buildInfoShimPath hands the build info to dart, added on init and on the
+// first build of the projects created before.
+var buildInfoShimPath = filepath.Join(build.BuildPath, "cmd", "buildinfo.go")
+
+// buildStartedOn is the build time of the build info, unless
+// SOURCE_DATE_EPOCH is set.
+var buildStartedOn = time.Now()
+
+// appBuildInfo describes a build to the app, embedded in the executable and
+// written to assets/build_info.json in the build output.
+type appBuildInfo struct {
+	Version          string `json:"version"`
+	Commit           string `json:"commit,omitempty"`
+	BuildTime        string `json:"buildTime"`
+	HoverVersion     string `json:"hoverVersion"`
+	EngineVersion    string `json:"engineVersion,omitempty"`
+	GoFlutterVersion string `json:"goFlutterVersion"`
+	OS               string `json:"os"`
+	Arch             string `json:"arch"`
+	Debug            bool   `json:"debug"`
+}
+
+// assertBuildInfoShim adds the build info shim to the project.
+func assertBuildInfoShim() {
+	if fileutils.IsFileExists(buildInfoShimPath) {
+		return
+	}
+	fileutils.CopyAsset("app/buildinfo.go", buildInfoShimPath, fileutils.AssetsBox())
+	log.Infof("Added %s, handing the build info to dart. Add it to git too.", buildInfoShimPath)
+}
+
+// buildInfoJSON returns the build info of the current build.
+func buildInfoJSON(targetOS, goFlutterVersion string) []byte {
+	buildTime, err := build.BuildTime(buildStartedOn)
+	if err != nil {
+		log.Errorf("%v", err)
+		os.Exit(1)
+	}
+	// the commit is empty outside of a git repository
+	commit, _ := gitOutput("rev-parse", "HEAD")
+	info := appBuildInfo{
+		Version:          buildVersionNumber,
+		Commit:           commit,
+		BuildTime:        buildTime.Format(time.RFC3339),
+		HoverVersion:     hoverVersion(),
+		EngineVersion:    strings.TrimSpace(enginecache.CachedEngineVersion(engineCachePath)),
+		GoFlutterVersion: goFlutterVersion,
+		OS:               targetOS,
+		Arch:             build.TargetArch(),
+		Debug:            buildDebug,
+	}
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		log.Errorf("Failed to encode the build info: %v", err)
+		os.Exit(1)
+	}
+	return data
+}
+
+// buildInfoLdflag sets the build info of the shim, in base64 as the ldflags
+// are split on spaces.
+func buildInfoLdflag(targetOS, goFlutterVersion string) string {
+	return "-X main.buildInfo=" + base64.StdEncoding.EncodeToString(buildInfoJSON(targetOS, goFlutterVersion))
+}
+
+// writeBuildInfo writes the build info to the assets of the build output.
+func writeBuildInfo(targetOS string) {
+	goFlutterVersion, err := versioncheck.CurrentGoFlutterTag(build.BuildPath)
+	if err != nil {
+		log.Errorf("%v", err)
+		os.Exit(1)
+	}
+	path := filepath.Join(build.OutputDirectoryPath(targetOS), "assets", "build_info.json")
+	err = ioutil.WriteFile(path, append(buildInfoJSON(targetOS, goFlutterVersion), '\n'), 0644)
+	if err != nil {
+		log.Errorf("Failed to write %s: %v", path, err)
+		os.Exit(1)
+	}
+}
@@ -58,7 +58,7 @@ func upgradeGoFlutter(targetOS string, engineCachePath string) (err error) {
 	switch targetOS {
 	case "darwin":
 		cgoLdflags = fmt.Sprintf("-F%s -Wl,-rpath,@executable_path", engineCachePath)
-	case "linux":
+	case "linux", "freebsd":
 		cgoLdflags = fmt.Sprintf("-L%s", engineCachePath)
 	case "windows":
 		cgoLdflags = fmt.Sprintf("-L%s", engineCachePath)
@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/enginecache"
+	"github.com/go-flutter-desktop/hover/internal/explain"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+var (
+	cacheGcMaxAge          string
+	cacheGcDryRun          bool
+	cachePrefetchAOT       bool
+	cachePrefetchJobs      int
+	cachePrefetchRateLimit string
+)
+
+// temporaryDirectoryPrefixes are the prefixes of the temporary directories
+// hover creates. They are left behind by failed builds.
+var temporaryDirectoryPrefixes = []string{
+	"hover-build-",
+	"hover-engine-download",
+	"hover-upgrade-packaging",
+	"hover-publish-",
+}
+
+func init() {
+	cacheGcCmd.Flags().StringVar(&buildCachePath, "cache-path", "", "The path that hover uses to cache dependencies such as the Flutter engine .so/.dll (defaults to the standard user cache directory)")
+	cacheGcCmd.Flags().StringVar(&cacheGcMaxAge, "max-age", "30d", "The age of the unused engines, packaging caches and temporary directories to remove, in days (30d) or as a duration (12h).")
+	cacheGcCmd.Flags().BoolVar(&cacheGcDryRun, "dry-run", false, "List what would be removed, without removing anything.")
+	cacheCmd.AddCommand(cacheGcCmd)
+	cachePrefetchCmd.Flags().StringVar(&buildCachePath, "cache-path", "", "The path that hover uses to cache dependencies such as the Flutter engine .so/.dll (defaults to the standard user cache directory)")
+	cachePrefetchCmd.Flags().BoolVar(&cachePrefetchAOT, "aot", false, "Download the release engines of the AOT builds.")
+	cachePrefetchCmd.Flags().IntVar(&cachePrefetchJobs, "jobs", 3, "The number of engines downloaded at the same time.")
+	cachePrefetchCmd.Flags().StringVar(&cachePrefetchRateLimit, "rate-limit", "", "The combined download rate of the engines, in bytes per second with an optional K, M or G suffix (e.g. 2M).")
+	cacheCmd.AddCommand(cachePrefetchCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the hover cache",
+}
+
+var cacheGcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove the engines unused by the known projects and the old packaging caches",
+	Long: "Remove the engines that aren't used by any known project and weren't used for longer than --max-age, and the packaging caches, engine backups and temporary build directories older than --max-age.\n" +
+		"The known projects are the projects built with the cache since this version of hover.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		maxAge, err := parseAge(cacheGcMaxAge)
+		if err != nil {
+			log.Errorf("%v", err)
+			os.Exit(1)
+		}
+		if buildCachePath == "" && config.GetConfig().CachePath != "" {
+			buildCachePath = config.GetConfig().CachePath
+		}
+		if buildCachePath == "" {
+			buildCachePath = enginecache.DefaultCachePath()
+		}
+		reclaimed, err := cacheGc(buildCachePath, time.Now().Add(-maxAge))
+		if err != nil {
+			log.Errorf("Cache garbage collection failed: %v", err)
+			os.Exit(1)
+		}
+		if cacheGcDryRun {
+			log.Infof("%s would be reclaimed", formatSize(reclaimed))
+		} else {
+			log.Infof("Reclaimed %s", formatSize(reclaimed))
+		}
+	},
+}
+
+var cachePrefetchCmd = &cobra.Command{
+	Use:   "prefetch [target...]",
+	Short: "Download the engines of several targets at once",
+	Long: "Download the engines of the targets that aren't cached at the engine version of flutter, or the engine-version of go/hover.yaml, concurrently.\n" +
+		"The targets are <os>[-<arch>], e.g. linux or windows-arm64, and default to linux, darwin and windows for amd64.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			args = []string{"linux", "darwin", "windows"}
+		}
+		var engines []enginecache.Engine
+		seen := make(map[enginecache.Engine]bool)
+		for _, target := range args {
+			engine, err := parsePrefetchTarget(target)
+			if err != nil {
+				log.Errorf("%v", err)
+				os.Exit(1)
+			}
+			engine.AOT = cachePrefetchAOT
+			if !seen[engine] {
+				seen[engine] = true
+				engines = append(engines, engine)
+			}
+		}
+		rateLimit, err := parseRateLimit(cachePrefetchRateLimit)
+		if err != nil {
+			log.Errorf("%v", err)
+			os.Exit(1)
+		}
+		if buildCachePath == "" && config.GetConfig().CachePath != "" {
+			buildCachePath = config.GetConfig().CachePath
+		}
+		if buildCachePath == "" {
+			buildCachePath = enginecache.DefaultCachePath()
+		}
+		err = enginecache.Prefetch(engines, buildCachePath, config.GetConfig().Engine, cachePrefetchJobs, rateLimit)
+		if err != nil {
+			log.ErrorCodef(explain.EngineDownloadFailed, "%v", err)
+			os.Exit(1)
+		}
+		log.Infof("The engines are in %s", enginecache.EnginesPath(buildCachePath))
+	},
+}
+
+// parsePrefetchTarget parses a target of hover cache prefetch, <os>[-<arch>].
+func parsePrefetchTarget(target string) (enginecache.Engine, error) {
+	targetOS, arch := build.SplitTargetArch(target)
+	if arch == "" {
+		arch = build.DefaultTargetArch
+	}
+	switch targetOS {
+	case "linux", "darwin", "windows":
+	default:
+		return enginecache.Engine{}, errors.Errorf("invalid target %s, use <os>[-<arch>] with linux, darwin or windows and amd64 or arm64", target)
+	}
+	return enginecache.Engine{OS: targetOS, Arch: arch}, nil
+}
+
+// parseRateLimit parses a rate in bytes per second with an optional K, M or G
+// suffix, empty for none.
+func parseRateLimit(rate string) (int64, error) {
+	if rate == "" {
+		return 0, nil
+	}
+	value, multiplier := rate, int64(1)
+	switch strings.ToUpper(rate[len(rate)-1:]) {
+	case "K":
+		multiplier = 1 << 10
+	case "M":
+		multiplier = 1 << 20
+	case "G":
+		multiplier = 1 << 30
+	}
+	if multiplier > 1 {
+		value = value[:len(value)-1]
+	}
+	bytesPerSecond, err := strconv.ParseFloat(value, 64)
+	if err != nil || bytesPerSecond <= 0 {
+		return 0, errors.Errorf("invalid rate limit %q", rate)
+	}
+	return int64(bytesPerSecond * float64(multiplier)), nil
+}
+
+// cacheGc removes the unused engines, and the packaging caches and temporary
+// directories last modified before a time. It returns the reclaimed space.
+func cacheGc(cachePath string, before time.Time) (uint64, error) {
+	var reclaimed uint64
+	remove := func(path, reason string) {
+		size := dirSize(path)
+		if cacheGcDryRun {
+			log.Printf("Would remove %s (%s): %s", path, reason, formatSize(size))
+			reclaimed += size
+			return
+		}
+		err := os.RemoveAll(path)
+		if err != nil {
+			log.Warnf("Failed to remove %s: %v", path, err)
+			return
+		}
+		log.Printf("Removed %s (%s): %s", path, reason, formatSize(size))
+		reclaimed += size
+	}
+
+	// the projects that don't exist anymore are forgotten
+	knownProjects, err := enginecache.KnownProjects(cachePath)
+	if err != nil {
+		return 0, err
+	}
+	var projects []enginecache.KnownProject
+	for _, project := range knownProjects {
+		if fileutils.IsFileExists(filepath.Join(project.Path, "pubspec.yaml")) {
+			projects = append(projects, project)
+		}
+	}
+	if !cacheGcDryRun && len(projects) != len(knownProjects) {
+		err = enginecache.SaveKnownProjects(cachePath, projects)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	engineDirectoryPath := enginecache.EnginesPath(cachePath)
+	engines, err := ioutil.ReadDir(engineDirectoryPath)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, errors.Wrap(err, "failed to list the engines")
+	}
+	for _, engine := range engines {
+		if !engine.IsDir() {
+			continue
+		}
+		path := filepath.Join(engineDirectoryPath, engine.Name())
+		if strings.HasSuffix(engine.Name(), ".switch-channel-backup") {
+			if engine.ModTime().Before(before) {
+				remove(path, "backup of a switch-channel")
+			}
+			continue
+		}
+		version := enginecache.CachedEngineVersion(path)
+		used := false
+		for _, project := range projects {
+			if project.Engines[engine.Name()] == version {
+				used = true
+				break
+			}
+		}
+		// the builds touch the engine they use, an engine that isn't
+		// recorded by a project may still be used by a project built with a
+		// previous version of hover, or from another cache path
+		if !used && engine.ModTime().Before(before) {
+			remove(path, "engine unused by the known projects")
+		}
+	}
+
+	for _, project := range projects {
+		packagingCachePath := filepath.Join(project.Path, build.BuildPath, "build", "packaging-cache")
+		formats, err := ioutil.ReadDir(packagingCachePath)
+		if err != nil {
+			continue
+		}
+		for _, format := range formats {
+			entries, err := ioutil.ReadDir(filepath.Join(packagingCachePath, format.Name()))
+			if err != nil {
+				continue
+			}
+			for _, entry := range entries {
+				if entry.ModTime().Before(before) {
+					remove(filepath.Join(packagingCachePath, format.Name(), entry.Name()), "old packaging cache")
+				}
+			}
+		}
+	}
+
+	tmpDirs := []string{os.TempDir()}
+	if tmpDir := config.GetConfig().GetTmpDir(); tmpDir != os.TempDir() {
+		tmpDirs = append(tmpDirs, tmpDir)
+	}
+	for _, tmpDir := range tmpDirs {
+		files, err := ioutil.ReadDir(tmpDir)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			if !file.IsDir() || !file.ModTime().Before(before) {
+				continue
+			}
+			for _, prefix := range temporaryDirectoryPrefixes {
+				if strings.HasPrefix(file.Name(), prefix) {
+					remove(filepath.Join(tmpDir, file.Name()), "old temporary directory")
+					break
+				}
+			}
+		}
+	}
+	return reclaimed, nil
+}
+
+// recordKnownProject records the engine used by the project, so it is kept by
+// hover cache gc, and marks the engine as recently used.
+func recordKnownProject() {
+	version := enginecache.CachedEngineVersion(engineCachePath)
+	if version == "" {
+		return
+	}
+	now := time.Now()
+	os.Chtimes(engineCachePath, now, now)
+	wd, err := os.Getwd()
+	if err != nil {
+		log.Warnf("Failed to record the project in the engine cache: %v", err)
+		return
+	}
+	// the engine may not be in buildCachePath, engineCachePath is
+	// <cache path>/hover/engine/<target OS>[-<arch>]
+	cachePath := filepath.Dir(filepath.Dir(filepath.Dir(engineCachePath)))
+	err = enginecache.RecordProject(cachePath, wd, filepath.Base(engineCachePath), version)
+	if err != nil {
+		log.Warnf("Failed to record the project in the engine cache: %v", err)
+	}
+}
+
+// parseAge parses a number of days (30d) or a duration (12h).
+func parseAge(age string) (time.Duration, error) {
+	if strings.HasSuffix(age, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(age, "d"))
+		if err != nil {
+			return 0, errors.Errorf("invalid age %q", age)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	duration, err := time.ParseDuration(age)
+	if err != nil {
+		return 0, errors.Errorf("invalid age %q", age)
+	}
+	return duration, nil
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		age     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30d", 30 * 24 * time.Hour, false},
+		{"0d", 0, false},
+		{"12h", 12 * time.Hour, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"d", 0, true},
+		{"1.5d", 0, true},
+		{"30", 0, true},
+		{"", 0, true},
+	}
+	for _, test := range tests {
+		got, err := parseAge(test.age)
+		if (err != nil) != test.wantErr {
+			t.Errorf("parseAge(%q) error = %v, want error %v", test.age, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("parseAge(%q) = %v, want %v", test.age, got, test.want)
+		}
+	}
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/explain"
 	"github.com/go-flutter-desktop/hover/internal/flutterversion"
 	"github.com/go-flutter-desktop/hover/internal/log"
 	"github.com/go-flutter-desktop/hover/internal/pubspec"
@@ -38,7 +40,7 @@ func assertHoverInitialized() {
 		if hoverMigrateDesktopToGo() {
 			return
 		}
-		log.Errorf("Directory '%s' is missing. Please init go-flutter first: %s", build.BuildPath, log.Au().Magenta("hover init"))
+		log.ErrorCodef(explain.NotInitialized, "Directory '%s' is missing. Please init go-flutter first: %s", build.BuildPath, log.Au().Magenta("hover init"))
 		os.Exit(1)
 	}
 	if err != nil {
@@ -49,6 +51,10 @@ func assertHoverInitialized() {
 
 func checkFlutterChannel() {
 	channel := flutterversion.FlutterChannel()
+	if config.GetConfig().FlutterChannel != "" {
+		// the project chose its channel
+		return
+	}
 	ignoreWarning := os.Getenv("HOVER_IGNORE_CHANNEL_WARNING")
 	if channel != "beta" && ignoreWarning != "true" {
 		log.Warnf("⚠ The go-flutter project tries to stay compatible with the beta channel of Flutter.")
@@ -56,6 +62,29 @@ func checkFlutterChannel() {
 	}
 }
 
+// assertFlutterVersion asserts the Flutter SDK in use satisfies the channel
+// set in go/hover.yaml and the flutter version constraint of pubspec.yaml.
+func assertFlutterVersion() {
+	if channel := config.GetConfig().FlutterChannel; channel != "" && channel != flutterversion.FlutterChannel() {
+		log.ErrorCodef(explain.FlutterChannelMismatch, "The project uses the %s channel of Flutter, but %s is on the %s channel.\n       Switch channel with `%s`, or use another Flutter SDK with the flutter-path in go/hover.yaml or the --flutter-path flag.", channel, build.FlutterBin(), flutterversion.FlutterChannel(), log.Au().Magenta("flutter channel "+channel))
+		os.Exit(1)
+	}
+	constraint, ok := pubspec.GetPubSpec().Environment["flutter"]
+	if !ok {
+		return
+	}
+	version := flutterversion.FlutterFrameworkVersion()
+	satisfied, err := flutterversion.SatisfiesConstraint(version, constraint)
+	if err != nil {
+		log.Warnf("Cannot check the Flutter version against the `%s` constraint of pubspec.yaml: %v", constraint, err)
+		return
+	}
+	if !satisfied {
+		log.ErrorCodef(explain.FlutterVersionMismatch, "Flutter %s of %s doesn't satisfy the `%s` constraint of pubspec.yaml.\n       Use another Flutter SDK with the flutter-path in go/hover.yaml or the --flutter-path flag.", version, build.FlutterBin(), constraint)
+		os.Exit(1)
+	}
+}
+
 // hoverMigrateDesktopToGo migrates from old hover buildPath directory to the new one ("desktop" -> "go")
 func hoverMigrateDesktopToGo() bool {
 	oldBuildPath := "desktop"
@@ -155,7 +184,7 @@ func initializeGoModule(projectPath string) {
 func findPubcachePath() (string, error) {
 	var path string
 	switch runtime.GOOS {
-	case "darwin", "linux":
+	case "darwin", "linux", "freebsd":
 		home, err := os.UserHomeDir()
 		if err != nil {
 			return "", errors.Wrap(err, "failed to resolve user home dir")
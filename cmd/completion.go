@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+var completionShells = []string{"bash", "fish"}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
+
+var completionCmd = &cobra.Command{
+	Use:   "completion <shell>",
+	Short: "Print the shell completion script of hover",
+	Long: "Print the completion script of hover for bash or fish, e.g. `source <(hover completion bash)` in ~/.bashrc, or `hover completion fish > ~/.config/fish/completions/hover.fish`.\n" +
+		"The packaging formats of `hover build`, `hover init-packaging` and the commands taking packaging formats are completed by hover when the tab key is pressed, including the custom formats of the project and of the global formats directory.",
+	ValidArgs: completionShells,
+	Args:      cobra.ExactValidArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+		switch args[0] {
+		case "bash":
+			err = rootCmd.GenBashCompletion(os.Stdout)
+		case "fish":
+			err = rootCmd.GenFishCompletion(os.Stdout, true)
+		}
+		if err != nil {
+			log.Errorf("Failed to generate the %s completion script: %v", args[0], err)
+			os.Exit(1)
+		}
+	},
+}
+
+// isCompletionRequest returns whether hover runs to complete the command
+// line of a shell, its output being the completions.
+func isCompletionRequest() bool {
+	return len(os.Args) > 1 && (os.Args[1] == cobra.ShellCompRequestCmd || os.Args[1] == cobra.ShellCompNoDescRequestCmd)
+}
+
+// completeSubcommands completes the subcommands of a command, which cobra
+// adds to the completions of the function. The bash script generated by
+// cobra then asks hover for them instead of listing the subcommands known
+// when it was generated, the custom packaging formats being added later.
+func completeSubcommands(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+var configMigrateDryRun bool
+
+func init() {
+	configMigrateCmd.Flags().BoolVar(&configMigrateDryRun, "dry-run", false, "Print the changes instead of writing them.")
+	configCmd.AddCommand(configMigrateCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage go/hover.yaml",
+}
+
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Rewrite go/hover.yaml written by an older hover to the current schema",
+	Long: "Rewrite go/hover.yaml written by an older hover to the current schema, keeping its comments: the renamed keys are renamed, the obsolete keys are removed and the new required keys are added with a default value.\n" +
+		"The license is detected from the LICENSE file of the project. The changes are printed as a diff, use --dry-run to review them first.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertInFlutterProject()
+		assertHoverInitialized()
+
+		path := filepath.Join(build.BuildPath, "hover.yaml")
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			log.Errorf("Failed to read %s: %v", path, err)
+			os.Exit(1)
+		}
+		version := config.FileSchemaVersion(content)
+		if version > config.SchemaVersion {
+			log.Errorf("%s has the schema %d of a newer hover, this hover only knows the schema %d: upgrade hover.", path, version, config.SchemaVersion)
+			os.Exit(1)
+		}
+		if version == config.SchemaVersion {
+			log.Infof("%s already has the current schema %d.", path, config.SchemaVersion)
+			return
+		}
+		migrated := config.Migrate(content)
+		printDiff(path, content, migrated)
+		if configMigrateDryRun {
+			return
+		}
+		err = ioutil.WriteFile(path, migrated, 0644)
+		if err != nil {
+			log.Errorf("Failed to write %s: %v", path, err)
+			os.Exit(1)
+		}
+		log.Infof("Migrated %s from the schema %d to the schema %d", path, version, config.SchemaVersion)
+		var migratedConfig config.Config
+		if yaml.Unmarshal(migrated, &migratedConfig) == nil && migratedConfig.License == "" {
+			log.Warnf("Fill in the license of %s, it couldn't be detected from the LICENSE file.", path)
+		}
+	},
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/go-flutter-desktop/hover/internal/artifactdiff"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+var diffLimit int
+
+func init() {
+	diffCmd.Flags().IntVar(&diffLimit, "limit", 50, "The maximum number of added, removed and changed files listed each, 0 to list all.")
+	rootCmd.AddCommand(diffCmd)
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <artifactA> <artifactB>",
+	Short: "Compare the files and metadata of two packaged artifacts",
+	Long: "Compare two packaged artifacts, or two build output directories, and print the metadata fields (such as the version of the package) and the files which changed between them, with their sizes, largest changes first.\n" +
+		"The deb, rpm, snap, AppImage, zip, msix, nupkg, apk and tar packages are read, the other formats are compared as a whole. The files are changed when their content or permissions differ.\n" +
+		"The command exits with an error when the artifacts differ, so it can check that builds are reproducible.",
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		before, err := artifactdiff.Read(args[0])
+		if err != nil {
+			log.Errorf("%v", err)
+			os.Exit(1)
+		}
+		after, err := artifactdiff.Read(args[1])
+		if err != nil {
+			log.Errorf("%v", err)
+			os.Exit(1)
+		}
+		if before.Format == "" || after.Format == "" {
+			log.Printf("The files of the artifacts can't be read, comparing them as a whole")
+		}
+
+		diff := artifactdiff.Compare(before, after)
+		if diff.Identical() {
+			log.Infof("The artifacts are identical (%d files)", diff.Unchanged)
+			return
+		}
+		if len(diff.Metadata) > 0 {
+			fmt.Println("Metadata:")
+			for _, change := range diff.Metadata {
+				fmt.Printf("  %s: %s -> %s\n", change.Field, diffValue(change.Before), diffValue(change.After))
+			}
+		}
+		printDiffChanges("Added", "+", diff.Added)
+		printDiffChanges("Removed", "-", diff.Removed)
+		printDiffChanges("Changed", "~", diff.Changed)
+
+		var sizeBefore, sizeAfter int64
+		if before.Format == "directory" {
+			for _, entry := range before.Entries {
+				sizeBefore += entry.Size
+			}
+			for _, entry := range after.Entries {
+				sizeAfter += entry.Size
+			}
+		} else {
+			sizeBefore, sizeAfter = before.Size, after.Size
+		}
+		fmt.Printf("%d added, %d removed, %d changed, %d unchanged files. Size %s -> %s (%s)\n", len(diff.Added), len(diff.Removed), len(diff.Changed), diff.Unchanged, formatDiffSize(sizeBefore), formatDiffSize(sizeAfter), formatSizeDelta(sizeAfter-sizeBefore))
+		os.Exit(1)
+	},
+}
+
+func printDiffChanges(title, marker string, changes []artifactdiff.Change) {
+	if len(changes) == 0 {
+		return
+	}
+	fmt.Printf("%s:\n", title)
+	for i, change := range changes {
+		if diffLimit > 0 && i == diffLimit {
+			fmt.Printf("  ... and %d more, list them with --limit 0\n", len(changes)-diffLimit)
+			break
+		}
+		name := change.Path
+		if name == "" {
+			name = "(the artifact)"
+		}
+		switch {
+		case change.Before == nil:
+			fmt.Printf("  %s %s (%s)\n", marker, name, formatDiffSize(change.After.Size))
+		case change.After == nil:
+			fmt.Printf("  %s %s (%s)\n", marker, name, formatDiffSize(change.Before.Size))
+		default:
+			fmt.Printf("  %s %s (%s)\n", marker, name, describeEntryChange(*change.Before, *change.After))
+		}
+	}
+}
+
+// describeEntryChange describes how a file changed between the artifacts.
+func describeEntryChange(before, after artifactdiff.Entry) string {
+	var changes []string
+	if before.Size != after.Size {
+		changes = append(changes, fmt.Sprintf("%s -> %s, %s", formatDiffSize(before.Size), formatDiffSize(after.Size), formatSizeDelta(after.Size-before.Size)))
+	} else if before.SHA256 != after.SHA256 {
+		changes = append(changes, "content, same size")
+	}
+	if before.Link != after.Link {
+		changes = append(changes, fmt.Sprintf("link %s -> %s", diffValue(before.Link), diffValue(after.Link)))
+	}
+	if before.Mode != after.Mode {
+		changes = append(changes, fmt.Sprintf("mode %04o -> %04o", before.Mode, after.Mode))
+	}
+	return strings.Join(changes, "; ")
+}
+
+func diffValue(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return strings.Replace(value, "\n", " ", -1)
+}
+
+func formatDiffSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", size)
+}
+
+func formatSizeDelta(delta int64) string {
+	if delta < 0 {
+		return "-" + formatDiffSize(-delta)
+	}
+	return "+" + formatDiffSize(delta)
+}
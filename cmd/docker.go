@@ -11,6 +11,7 @@ import (
 
 	"github.com/go-flutter-desktop/hover/cmd/packaging"
 	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
 	"github.com/go-flutter-desktop/hover/internal/log"
 	"github.com/go-flutter-desktop/hover/internal/logstreamer"
 )
@@ -50,6 +51,8 @@ func dockerHoverBuild(targetOS string, packagingTask packaging.Task, buildFlags
 		"--mount", "type=bind,source=" + engineCacheDir + ",target=/root/.cache/hover/engine",
 		"--mount", "type=bind,source=" + dockerGoCacheDir + ",target=/go-cache",
 		"--env", "GOCACHE=/go-cache",
+		// the webhooks are notified outside of the container
+		"--env", webhooksNotifiedEnv + "=true",
 	}
 	if runtime.GOOS != "windows" {
 		currentUser, err := user.Current()
@@ -66,6 +69,9 @@ func dockerHoverBuild(targetOS string, packagingTask packaging.Task, buildFlags
 	if goprivate := os.Getenv("GOPRIVATE"); goprivate != "" {
 		dockerArgs = append(dockerArgs, "--env", "GOPRIVATE="+goprivate)
 	}
+	if nightly := os.Getenv(config.NightlyEnv); nightly != "" {
+		dockerArgs = append(dockerArgs, "--env", config.NightlyEnv+"="+nightly)
+	}
 	if len(vmArguments) > 0 {
 		// I (GeertJohan) am not too happy with this, it make the hover inside
 		// the container aware of it being inside the container. But for now
@@ -76,12 +82,7 @@ func dockerHoverBuild(targetOS string, packagingTask packaging.Task, buildFlags
 		dockerArgs = append(dockerArgs, "--env", "HOVER_IN_DOCKER_BUILD_VMARGS="+strings.Join(vmArguments, ","))
 	}
 
-	version := hoverVersion()
-	if version == "(devel)" {
-		version = "latest"
-	}
-	dockerImage := "goflutter/hover:" + version
-	dockerArgs = append(dockerArgs, dockerImage)
+	dockerArgs = append(dockerArgs, dockerImage())
 	targetOSAndPackaging := targetOS
 	if packName := packagingTask.Name(); packName != "" {
 		targetOSAndPackaging += "-" + packName
@@ -103,3 +104,12 @@ func dockerHoverBuild(targetOS string, packagingTask packaging.Task, buildFlags
 	}
 	log.Infof("Docker run completed")
 }
+
+// dockerImage returns the image of the hover version in use.
+func dockerImage() string {
+	version := hoverVersion()
+	if version == "(devel)" {
+		version = "latest"
+	}
+	return "goflutter/hover:" + version
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/go-flutter-desktop/hover/internal/build"
 	"github.com/go-flutter-desktop/hover/internal/config"
 	"github.com/go-flutter-desktop/hover/internal/flutterversion"
+	"github.com/go-flutter-desktop/hover/internal/hostenv"
 	"github.com/go-flutter-desktop/hover/internal/log"
 )
 
@@ -37,8 +38,17 @@ var doctorCmd = &cobra.Command{
 
 		version := hoverVersion()
 		log.Infof("Hover version %s running on %s", version, runtime.GOOS)
+		switch {
+		case hostenv.IsWSL() && hostenv.HasWSLg():
+			log.Infof("Running in WSL, the apps are displayed with WSLg")
+		case hostenv.IsWSL():
+			log.Infof("Running in WSL without WSLg, the apps need an X server on windows")
+		case hostenv.IsDevContainer():
+			log.Infof("Running in a dev container, the apps need the display server of the host")
+		}
+		warnWindowsFilesystem()
 
-		log.Infof("Sharing flutter version")
+		log.Infof("Sharing flutter version of %s", build.FlutterBin())
 		cmdFlutterVersion := exec.Command(build.FlutterBin(), "--version")
 		cmdFlutterVersion.Stderr = os.Stderr
 		cmdFlutterVersion.Stdout = os.Stdout
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// assetsDecryptPath is the shim decrypting the flutter assets when the app
+// starts, added to the project on the first build with encrypt-assets.
+var assetsDecryptPath = filepath.Join(build.BuildPath, "cmd", "assetsdecrypt.go")
+
+// encryptAssets returns whether the kernel_blob.bin of the flutter assets is
+// encrypted, with --encrypt-assets or encrypt-assets in go/hover.yaml. The
+// debug builds, used by hover run, aren't encrypted, and the AOT builds don't
+// ship the kernel.
+func encryptAssets() bool {
+	return !buildDebug && !build.AOT() && (buildEncryptAssets || config.GetConfig().EncryptAssets)
+}
+
+// assetsKeyPath returns the file keeping the key the flutter assets of a
+// target were encrypted with, for the go build. It is outside of the build
+// outputs, so it isn't packaged.
+func assetsKeyPath(targetOS string) string {
+	return filepath.Join(build.BuildPath, "build", "assets-keys", filepath.Base(build.OutputDirectoryPath(targetOS)))
+}
+
+// assertAssetsDecryptShim adds the decryption shim to the project when the
+// flutter assets are encrypted.
+func assertAssetsDecryptShim() {
+	if !encryptAssets() || fileutils.IsFileExists(assetsDecryptPath) {
+		return
+	}
+	fileutils.CopyAsset("app/assetsdecrypt.go", assetsDecryptPath, fileutils.AssetsBox())
+	log.Infof("Added %s, decrypting the flutter assets when the app starts. Add it to git too.", assetsDecryptPath)
+}
+
+// encryptFlutterAssets encrypts the kernel_blob.bin of the flutter assets of
+// a target with AES-GCM, using a new key for every flutter build. The key is
+// compiled in the executable, the encryption only keeps the dart code from
+// being trivially extracted from the packages.
+func encryptFlutterAssets(targetOS string) error {
+	kernelPath := filepath.Join(build.OutputDirectoryPath(targetOS), "flutter_assets", "kernel_blob.bin")
+	kernel, err := ioutil.ReadFile(kernelPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to read kernel_blob.bin")
+	}
+	key := make([]byte, 32)
+	_, err = rand.Read(key)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate the key")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate the nonce")
+	}
+	err = ioutil.WriteFile(kernelPath+".enc", gcm.Seal(nonce, nonce, kernel, nil), 0644)
+	if err != nil {
+		return errors.Wrap(err, "failed to write kernel_blob.bin.enc")
+	}
+	err = os.Remove(kernelPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove kernel_blob.bin")
+	}
+	err = os.MkdirAll(filepath.Dir(assetsKeyPath(targetOS)), 0700)
+	if err != nil {
+		return errors.Wrap(err, "failed to save the key")
+	}
+	err = ioutil.WriteFile(assetsKeyPath(targetOS), []byte(hex.EncodeToString(key)+"\n"), 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to save the key")
+	}
+	return nil
+}
+
+// assetsKey returns the key the flutter assets of a target were encrypted
+// with.
+func assetsKey(targetOS string) string {
+	key, err := ioutil.ReadFile(assetsKeyPath(targetOS))
+	if os.IsNotExist(err) {
+		log.Errorf("The flutter assets of %s weren't encrypted, build without --skip-flutter-build-bundle once.", filepath.Base(build.OutputDirectoryPath(targetOS)))
+		os.Exit(1)
+	}
+	if err != nil {
+		log.Errorf("Failed to read the key of the flutter assets: %v", err)
+		os.Exit(1)
+	}
+	return strings.TrimSpace(string(key))
+}
@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/go-flutter-desktop/hover/cmd/packaging"
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/enginecache"
+	"github.com/go-flutter-desktop/hover/internal/flutterversion"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+)
+
+var (
+	envArch string
+	envAOT  bool
+	envJSON bool
+)
+
+// secretPattern matches the names of the environment variables and template
+// data redacted by hover env.
+var secretPattern = regexp.MustCompile(`(?i)(secret|token|password|passwd|credentials?|key|pin)$`)
+
+// envVariables are the variables of the environment changing the behavior of
+// hover, besides the HOVER_ ones.
+var envVariables = []string{"FLUTTER_STORAGE_BASE_URL", "GOPROXY", "GOPRIVATE", "SOURCE_DATE_EPOCH", "NO_COLOR"}
+
+func init() {
+	envCmd.Flags().StringVar(&envArch, "arch", build.DefaultTargetArch, "The architecture of the target: amd64 or arm64.")
+	envCmd.Flags().BoolVar(&envAOT, "aot", false, "Show the release engine of the AOT builds.")
+	envCmd.Flags().BoolVar(&envJSON, "json", false, "Print the environment in JSON.")
+	rootCmd.AddCommand(envCmd)
+}
+
+var envCmd = &cobra.Command{
+	Use:   "env [linux|darwin|windows|freebsd]",
+	Short: "Print the environment hover uses to build for a target",
+	Long: "Print the engine, cache paths, Flutter SDK, toolchain, docker image, configuration files and template data hover uses to build for a target, the host OS by default.\n" +
+		"The environment variables and template data that look like secrets are redacted.",
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		assertInFlutterProject()
+		targetOS := runtime.GOOS
+		if len(args) == 1 {
+			targetOS = args[0]
+		}
+		switch targetOS {
+		case "linux", "darwin", "windows", "freebsd":
+		default:
+			log.Errorf("Unknown target %s, use linux, darwin, windows or freebsd.", targetOS)
+			os.Exit(1)
+		}
+		build.SetTargetArch(envArch)
+		build.SetAOT(envAOT)
+
+		if envJSON {
+			// keep the warnings out of the json
+			stdout := os.Stdout
+			os.Stdout = os.Stderr
+			env := resolveEnv(targetOS)
+			os.Stdout = stdout
+			out, err := json.MarshalIndent(env, "", "  ")
+			if err != nil {
+				log.Errorf("Failed to encode the environment: %v", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(out))
+			return
+		}
+		resolveEnv(targetOS).print()
+	},
+}
+
+// hoverEnv is the environment hover uses to build for a target.
+type hoverEnv struct {
+	Target       string            `json:"target"`
+	HoverVersion string            `json:"hoverVersion"`
+	Flutter      flutterEnv        `json:"flutter"`
+	Engine       engineEnv         `json:"engine"`
+	Toolchain    toolchainEnv      `json:"toolchain"`
+	DockerImage  string            `json:"dockerImage"`
+	ConfigFiles  []string          `json:"configFiles"` // In order of precedence, the last one wins
+	Environment  map[string]string `json:"environment"`
+	TemplateData map[string]string `json:"templateData"`
+}
+
+type flutterEnv struct {
+	Bin             string `json:"bin"`
+	Root            string `json:"root"`
+	Version         string `json:"version"`
+	Channel         string `json:"channel"`
+	Revision        string `json:"revision"`
+	RequiredChannel string `json:"requiredChannel,omitempty"`
+}
+
+type engineEnv struct {
+	Version       string `json:"version"`
+	LocalEngine   string `json:"localEngine,omitempty"`
+	CachePath     string `json:"cachePath"`
+	Path          string `json:"path"`
+	CachedVersion string `json:"cachedVersion"`
+}
+
+type toolchainEnv struct {
+	Go        string `json:"go"`
+	GoVersion string `json:"goVersion"`
+	CC        string `json:"cc"`
+}
+
+// resolveEnv resolves the environment of a target like hover build does,
+// without downloading the engine.
+func resolveEnv(targetOS string) hoverEnv {
+	c := config.GetConfig()
+	env := hoverEnv{
+		Target:       targetOS + "-" + build.TargetArch(),
+		HoverVersion: hoverVersion(),
+		DockerImage:  dockerImage(),
+		Environment:  make(map[string]string),
+	}
+
+	env.Flutter = flutterEnv{
+		Bin:             build.FlutterBin(),
+		Root:            flutterversion.FlutterRoot(),
+		Version:         flutterversion.FlutterFrameworkVersion(),
+		Channel:         flutterversion.FlutterChannel(),
+		Revision:        flutterversion.FlutterFrameworkRevision(),
+		RequiredChannel: c.FlutterChannel,
+	}
+
+	env.Engine.Version = flutterversion.FlutterRequiredEngineVersion()
+	if c.Engine != "" {
+		env.Engine.Version = c.Engine
+	}
+	env.Engine.LocalEngine = c.LocalEngine
+	env.Engine.CachePath = c.CachePath
+	if env.Engine.CachePath == "" {
+		env.Engine.CachePath = enginecache.DefaultCachePath()
+	}
+	env.Engine.Path = enginecache.EngineCachePath(targetOS, env.Engine.CachePath)
+	env.Engine.CachedVersion = enginecache.CachedEngineVersion(env.Engine.Path)
+
+	// hover fails when go is missing, hover env reports it
+	env.Toolchain.Go, _ = exec.LookPath("go")
+	if env.Toolchain.Go != "" {
+		out, err := exec.Command(env.Toolchain.Go, "env", "GOVERSION", "CC").Output()
+		if err == nil {
+			lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+			env.Toolchain.GoVersion = lines[0]
+			if len(lines) > 1 {
+				env.Toolchain.CC = strings.TrimSpace(lines[1])
+			}
+		}
+	}
+	if cc := crossCompiler(targetOS); cc != "" {
+		env.Toolchain.CC = cc
+	}
+	env.ConfigFiles = config.Files()
+
+	for _, variable := range os.Environ() {
+		parts := strings.SplitN(variable, "=", 2)
+		if !strings.HasPrefix(parts[0], "HOVER_") && !containsString(envVariables, parts[0]) {
+			continue
+		}
+		env.Environment[parts[0]] = redact(parts[0], parts[1])
+	}
+
+	projectName := pubspec.GetPubSpec().Name
+	env.TemplateData = packaging.TemplateData(projectName, pubspec.GetPubSpec().GetVersion())
+	for key, value := range env.TemplateData {
+		env.TemplateData[key] = redact(key, value)
+	}
+	return env
+}
+
+func redact(name, value string) string {
+	if value != "" && secretPattern.MatchString(name) {
+		return "<redacted>"
+	}
+	return value
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (env hoverEnv) print() {
+	printSection := func(title string, values [][2]string) {
+		fmt.Println(log.Au().Bold(title))
+		for _, value := range values {
+			if value[1] == "" {
+				value[1] = "-"
+			}
+			fmt.Printf("  %-18s %s\n", value[0]+":", value[1])
+		}
+	}
+	printMap := func(title string, values map[string]string) {
+		var keys []string
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var pairs [][2]string
+		for _, key := range keys {
+			pairs = append(pairs, [2]string{key, strings.ReplaceAll(values[key], "\n", `\n`)})
+		}
+		printSection(title, pairs)
+	}
+
+	printSection("hover", [][2]string{
+		{"version", env.HoverVersion},
+		{"target", env.Target},
+		{"docker image", env.DockerImage},
+		{"config files", strings.Join(env.ConfigFiles, ", ")},
+	})
+	printSection("flutter", [][2]string{
+		{"bin", env.Flutter.Bin},
+		{"root", env.Flutter.Root},
+		{"version", env.Flutter.Version},
+		{"channel", env.Flutter.Channel},
+		{"revision", env.Flutter.Revision},
+		{"required channel", env.Flutter.RequiredChannel},
+	})
+	printSection("engine", [][2]string{
+		{"version", env.Engine.Version},
+		{"local engine", env.Engine.LocalEngine},
+		{"cache path", env.Engine.CachePath},
+		{"path", env.Engine.Path},
+		{"cached version", env.Engine.CachedVersion},
+	})
+	printSection("toolchain", [][2]string{
+		{"go", env.Toolchain.Go},
+		{"go version", env.Toolchain.GoVersion},
+		{"cc", env.Toolchain.CC},
+	})
+	printMap("environment", env.Environment)
+	printMap("template data", env.TemplateData)
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+var (
+	execOS   string
+	execArch string
+	execAOT  bool
+)
+
+func init() {
+	execCmd.Flags().StringVar(&execOS, "os", runtime.GOOS, "The target OS of the build environment: linux, darwin, windows or freebsd.")
+	execCmd.Flags().StringVar(&execArch, "arch", build.DefaultTargetArch, "The architecture of the target: amd64 or arm64.")
+	execCmd.Flags().BoolVar(&execAOT, "aot", false, "Use the release engine of the AOT builds.")
+	execCmd.Flags().StringVar(&buildCachePath, "cache-path", "", "The path that hover uses to cache dependencies such as the Flutter engine .so/.dll (defaults to the standard user cache directory)")
+	execCmd.Flags().StringVar(&buildEngineVersion, "engine-version", "", "The flutter engine version to use.")
+	execCmd.Flags().StringVar(&buildLocalEngine, "local-engine", "", "The path of a locally built flutter engine to use instead of downloading it (e.g. engine/src/out/host_release).")
+	rootCmd.AddCommand(execCmd)
+}
+
+var execCmd = &cobra.Command{
+	Use:   "exec -- <command> [arguments...]",
+	Short: "Run a command in the go directory with the environment of the go build",
+	Long: "Run a command in the go directory with the environment hover uses to build go-flutter: GOOS, GOARCH, CGO_ENABLED, CGO_LDFLAGS with the engine library paths and the C cross-compiler, the directory of the Flutter SDK prepended to PATH and, for the host OS, the engine directory prepended to the library path.\n" +
+		"It makes tools compiling the go code with cgo work, e.g. `hover exec -- go vet ./...`, staticcheck or an editor running gopls. The engine is downloaded when it isn't cached.",
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		assertInFlutterProject()
+		assertHoverInitialized()
+		switch execOS {
+		case "linux", "darwin", "windows", "freebsd":
+		default:
+			log.Errorf("Unknown target OS %s, use linux, darwin, windows or freebsd.", execOS)
+			os.Exit(1)
+		}
+		build.SetTargetArch(execArch)
+		build.SetAOT(execAOT)
+		// the flutter framework isn't built, its compatibility with the engine
+		// doesn't matter.
+		buildSkipFlutterBuildBundle = true
+		initBuildParameters(execOS)
+
+		execCommand := exec.Command(args[0], args[1:]...)
+		execCommand.Dir = build.BuildPath
+		execCommand.Env = append(os.Environ(), buildEnv(execOS, engineCachePath)...)
+		path := prependPath(filepath.Dir(build.FlutterBin()), os.Getenv("PATH"))
+		if execOS == runtime.GOOS && execArch == runtime.GOARCH {
+			// the programs built by the command, e.g. go test, find the engine
+			switch execOS {
+			case "linux", "freebsd":
+				execCommand.Env = append(execCommand.Env, "LD_LIBRARY_PATH="+prependPath(engineCachePath, os.Getenv("LD_LIBRARY_PATH")))
+			case "darwin":
+				execCommand.Env = append(execCommand.Env, "DYLD_FRAMEWORK_PATH="+prependPath(engineCachePath, os.Getenv("DYLD_FRAMEWORK_PATH")))
+			case "windows":
+				path = prependPath(engineCachePath, path)
+			}
+		}
+		execCommand.Env = append(execCommand.Env, "PATH="+path)
+		execCommand.Stdin = os.Stdin
+		execCommand.Stdout = os.Stdout
+		execCommand.Stderr = os.Stderr
+		err := execCommand.Run()
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			os.Exit(exitErr.ExitCode())
+		}
+		if err != nil {
+			log.Errorf("Failed to run %s: %v", args[0], err)
+			os.Exit(1)
+		}
+	},
+}
+
+// prependPath prepends a directory to a list of paths, e.g. PATH.
+func prependPath(dir, paths string) string {
+	if paths == "" {
+		return dir
+	}
+	return dir + string(os.PathListSeparator) + paths
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/go-flutter-desktop/hover/internal/explain"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+}
+
+var explainCmd = &cobra.Command{
+	Use:   "explain [error-code]",
+	Short: "Explain an error of hover, e.g. hover explain E0301",
+	Long: "Print the description, common causes and fixes of an error of hover, from the code of the error (e.g. error[E0301]).\n" +
+		"Without a code, list the codes of all the errors.",
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			for _, explanation := range explain.All() {
+				fmt.Printf("%s  %s\n", log.Au().Bold(explanation.Code), explanation.Title)
+			}
+			return
+		}
+		explanation, ok := explain.Lookup(args[0])
+		if !ok {
+			log.Errorf("Unknown error code %s, run `%s` to list the error codes.", args[0], log.Au().Magenta("hover explain"))
+			os.Exit(1)
+		}
+		printExplanation(explanation)
+	},
+}
+
+func printExplanation(explanation explain.Explanation) {
+	fmt.Printf("%s %s\n\n", log.Au().Bold(explanation.Code+":"), log.Au().Bold(explanation.Title))
+	fmt.Println(wrap(explanation.Description, 80, ""))
+	printList := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Printf("\n%s\n", log.Au().Bold(title))
+		for _, item := range items {
+			fmt.Println("  - " + strings.TrimPrefix(wrap(item, 76, "    "), "    "))
+		}
+	}
+	printList("Common causes:", explanation.Causes)
+	printList("Fixes:", explanation.Fixes)
+}
+
+// wrap wraps a text at a width, the lines are prefixed with indent.
+func wrap(text string, width int, indent string) string {
+	var lines []string
+	line := indent
+	for _, word := range strings.Fields(text) {
+		if len(line) > len(indent) && len(line)+1+len(word) > width+len(indent) {
+			lines = append(lines, line)
+			line = indent
+		}
+		if len(line) > len(indent) {
+			line += " "
+		}
+		line += word
+	}
+	return strings.Join(append(lines, line), "\n")
+}
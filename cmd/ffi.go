@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+)
+
+// ffiPlugin is a pub package whose dart code loads a native library with
+// dart:ffi, declared with ffiPlugin: true for a platform in its pubspec.yaml.
+type ffiPlugin struct {
+	name string
+	path string
+}
+
+// flutterPlatform returns the name of a target in the platforms of the
+// plugins, empty when flutter has no such platform.
+func flutterPlatform(targetOS string) string {
+	switch targetOS {
+	case "darwin":
+		return "macos"
+	case "linux", "windows":
+		return targetOS
+	}
+	return ""
+}
+
+// sharedLibraryExtension returns the file extension of the shared libraries
+// of a target.
+func sharedLibraryExtension(targetOS string) string {
+	switch targetOS {
+	case "darwin":
+		return ".dylib"
+	case "windows":
+		return ".dll"
+	}
+	return ".so"
+}
+
+// listFFIPlugins returns the FFI plugins of pubspec.lock for a target.
+func listFFIPlugins(targetOS string) ([]ffiPlugin, error) {
+	platform := flutterPlatform(targetOS)
+	if platform == "" {
+		return nil, nil
+	}
+	pubLock, err := readPubSpecLock()
+	if err != nil {
+		return nil, err
+	}
+	pubcachePath, err := findPubcachePath()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find path for pub-cache")
+	}
+	var plugins []ffiPlugin
+	for name, entry := range pubLock.Packages {
+		packagePath, err := entry.locate(name, pubcachePath)
+		if err != nil {
+			return nil, err
+		}
+		if packagePath == "" {
+			continue
+		}
+		packagePubspec, err := pubspec.ReadPubSpecFile(filepath.Join(packagePath, "pubspec.yaml"))
+		if err != nil {
+			continue
+		}
+		plugin, _ := packagePubspec.Flutter["plugin"].(map[interface{}]interface{})
+		platforms, _ := plugin["platforms"].(map[interface{}]interface{})
+		platformConfig, _ := platforms[platform].(map[interface{}]interface{})
+		if ffi, _ := platformConfig["ffiPlugin"].(bool); ffi {
+			plugins = append(plugins, ffiPlugin{name: name, path: packagePath})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].name < plugins[j].name })
+	return plugins, nil
+}
+
+// bundleFFILibraries builds the native libraries of the FFI plugins and
+// copies them to the build output, next to the executable and the engine
+// where the dart code opens them: lib<name>.so on linux, <name>.dll on
+// windows and <name>.framework/<name> on darwin. The libraries are built
+// with cmake from the src directory of the plugins, the prebuilt libraries of
+// the platform directory of the plugins are copied as they are.
+func bundleFFILibraries(targetOS string) {
+	plugins, err := listFFIPlugins(targetOS)
+	if err != nil {
+		log.Warnf("Skipping the FFI plugins: %v", err)
+		return
+	}
+	for _, plugin := range plugins {
+		var libraries []string
+		if fileutils.IsFileExists(filepath.Join(plugin.path, "src", "CMakeLists.txt")) {
+			built, err := buildFFILibrary(plugin, targetOS)
+			if err != nil {
+				log.Errorf("Failed to build the native library of the FFI plugin %s: %v", plugin.name, err)
+				os.Exit(1)
+			}
+			libraries = append(libraries, built...)
+		}
+		libraries = append(libraries, findSharedLibraries(filepath.Join(plugin.path, flutterPlatform(targetOS)), targetOS)...)
+		if len(libraries) == 0 {
+			log.Warnf("The FFI plugin %s has no native library for %s, neither a src/CMakeLists.txt nor prebuilt libraries in its %s directory.", plugin.name, targetOS, flutterPlatform(targetOS))
+			continue
+		}
+		for _, library := range libraries {
+			destination := filepath.Join(build.OutputDirectoryPath(targetOS), filepath.Base(library))
+			if targetOS == "darwin" {
+				// the dart code opens <name>.framework/<name> on darwin
+				name := strings.TrimPrefix(strings.TrimSuffix(filepath.Base(library), ".dylib"), "lib")
+				destination = filepath.Join(build.OutputDirectoryPath(targetOS), name+".framework", name)
+				err := os.MkdirAll(filepath.Dir(destination), 0755)
+				if err != nil {
+					log.Errorf("Failed to create the framework of %s: %v", plugin.name, err)
+					os.Exit(1)
+				}
+			}
+			fileutils.CopyFile(library, destination)
+			log.Infof("Bundled %s of the FFI plugin %s", filepath.Base(library), plugin.name)
+		}
+	}
+}
+
+// buildFFILibrary builds the src directory of a FFI plugin with cmake, in
+// go/build/ffi so the later builds are incremental, and returns the built
+// shared libraries.
+func buildFFILibrary(plugin ffiPlugin, targetOS string) ([]string, error) {
+	buildDir, err := filepath.Abs(filepath.Join(build.BuildPath, "build", "ffi", plugin.name+"-"+targetOS+"-"+build.TargetArch()))
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"-S", filepath.Join(plugin.path, "src"), "-B", buildDir, "-DCMAKE_BUILD_TYPE=Release"}
+	if runtime.GOOS != targetOS || runtime.GOARCH != build.TargetArch() {
+		args = append(args, "-DCMAKE_SYSTEM_NAME="+map[string]string{"linux": "Linux", "windows": "Windows", "darwin": "Darwin"}[targetOS])
+		if cc := crossCompiler(targetOS); cc != "" {
+			args = append(args, "-DCMAKE_C_COMPILER="+cc)
+		}
+	}
+	log.Printf("Building the native library of the FFI plugin %s", plugin.name)
+	for _, cmdArgs := range [][]string{args, {"--build", buildDir, "--config", "Release"}} {
+		cmdCmake := exec.Command(build.CmakeBin(), cmdArgs...)
+		cmdCmake.Stdout = os.Stdout
+		cmdCmake.Stderr = os.Stderr
+		err := cmdCmake.Run()
+		if err != nil {
+			return nil, err
+		}
+	}
+	libraries := findSharedLibraries(buildDir, targetOS)
+	if len(libraries) == 0 {
+		return nil, errors.New("cmake didn't build a shared library")
+	}
+	return libraries, nil
+}
+
+// findSharedLibraries returns the shared libraries of a target in a
+// directory, and its subdirectories but the cmake ones.
+func findSharedLibraries(dir, targetOS string) []string {
+	var libraries []string
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() && info.Name() == "CMakeFiles" {
+			return filepath.SkipDir
+		}
+		if info.Mode().IsRegular() && filepath.Ext(path) == sharedLibraryExtension(targetOS) {
+			libraries = append(libraries, path)
+		}
+		return nil
+	})
+	return libraries
+}
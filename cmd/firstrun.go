@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// firstRunShimPath opens the first-run URL of the survey on the first launch
+// of the app, added to the project on the first build opting in to it.
+var firstRunShimPath = filepath.Join(build.BuildPath, "cmd", "firstrun.go")
+
+// assertFirstRunShim adds the first-run shim to the project when the survey
+// of go/hover.yaml is opted in with a first-run URL.
+func assertFirstRunShim() {
+	if firstRunURL, _ := config.GetConfig().GetSurveyURLs(); firstRunURL == "" || fileutils.IsFileExists(firstRunShimPath) {
+		return
+	}
+	fileutils.CopyAsset("app/firstrun.go", firstRunShimPath, fileutils.AssetsBox())
+	log.Infof("Added %s, opening the first-run URL on the first launch of the app. Add it to git too.", firstRunShimPath)
+}
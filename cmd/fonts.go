@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/fonts"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// checkFontLicenses warns about the fonts of the flutter assets that don't
+// allow being embedded in the app, or have no license metadata, before they
+// are distributed. The fonts in the exceptions of the license-policy of
+// go/hover.yaml were reviewed and aren't checked.
+func checkFontLicenses(targetOS string) {
+	assetsPath := filepath.Join(build.OutputDirectoryPath(targetOS), "flutter_assets")
+	exceptions := make(map[string]bool)
+	for _, exception := range config.GetConfig().LicensePolicy.Exceptions {
+		exceptions[filepath.ToSlash(exception)] = true
+	}
+	filepath.Walk(assetsPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".ttf", ".otf", ".ttc":
+		default:
+			return nil
+		}
+		relativePath, err := filepath.Rel(assetsPath, path)
+		if err != nil {
+			return nil
+		}
+		relativePath = filepath.ToSlash(relativePath)
+		if exceptions[relativePath] || exceptions[filepath.Base(path)] {
+			return nil
+		}
+		fontsOfFile, err := fonts.Read(path)
+		if err != nil {
+			log.Warnf("Failed to read the font %s: %v", relativePath, err)
+			return nil
+		}
+		for _, font := range fontsOfFile {
+			name := relativePath
+			if font.Name != "" {
+				name += " (" + font.Name + ")"
+			}
+			switch restriction := font.Restriction(); {
+			case font.FsType&fonts.RestrictedLicense != 0:
+				log.Warnf("The font %s has %s: it must not be distributed without the permission of its owner.", name, restriction)
+			case restriction != "":
+				log.Warnf("The font %s only allows %s, check that its license allows bundling it with the app.", name, restriction)
+			}
+			if font.License == "" && font.LicenseURL == "" {
+				log.Warnf("The font %s has no license metadata, check its license before distributing it.", name)
+			}
+		}
+		if hasFontIssue(fontsOfFile) {
+			log.Warnf("Add %s to the exceptions of the license-policy in go/hover.yaml once reviewed.", relativePath)
+		}
+		return nil
+	})
+}
+
+func hasFontIssue(fontsOfFile []fonts.Font) bool {
+	for _, font := range fontsOfFile {
+		if font.Restriction() != "" || (font.License == "" && font.LicenseURL == "") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/enginecache"
+	"github.com/go-flutter-desktop/hover/internal/hostenv"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// warnWindowsFilesystem warns when the project or the engine cache is on a
+// drive of the windows host in WSL, where the flutter and go builds are many
+// times slower than on the linux filesystem.
+func warnWindowsFilesystem() {
+	if !hostenv.IsWSL() {
+		return
+	}
+	wd, err := os.Getwd()
+	if err == nil && hostenv.IsWindowsFilesystem(wd) {
+		log.Warnf("The project is on a drive of the windows host (%s), which WSL reads and writes slowly. Move it to the linux filesystem, e.g. %s, for faster builds.", wd, filepath.Join("~", filepath.Base(wd)))
+	}
+	cachePath := buildCachePath
+	if cachePath == "" {
+		cachePath = config.GetConfig().CachePath
+	}
+	if cachePath == "" {
+		cachePath = enginecache.DefaultCachePath()
+	}
+	if hostenv.IsWindowsFilesystem(cachePath) {
+		log.Warnf("The engine cache %s is on a drive of the windows host, set cache-path in %s to a directory of the linux filesystem.", cachePath, filepath.Join(build.BuildPath, "hover.yaml"))
+	}
+}
+
+// runDisplayEnv returns the environment variables connecting the app started
+// by hover run to a display server. In WSL, the app is displayed by WSLg when
+// no other X server is set with DISPLAY. In a dev container, the display
+// server of the host must be forwarded.
+func runDisplayEnv() []string {
+	if hostenv.HasDisplay() {
+		return nil
+	}
+	switch {
+	case hostenv.HasWSLg():
+		log.Infof("Displaying the app with WSLg")
+		return hostenv.WSLgDisplayEnv()
+	case hostenv.IsWSL():
+		log.Errorf("WSL has no display server to start the app with. Update WSL with `%s` on windows to get WSLg, or start an X server on windows and set DISPLAY.", log.Au().Magenta("wsl --update"))
+		os.Exit(1)
+	case hostenv.IsDevContainer():
+		log.Errorf("The dev container has no display server to start the app with. Forward the X11 socket of the host: mount /tmp/.X11-unix in the container and set DISPLAY in its remoteEnv, or add a desktop to the container such as the desktop-lite feature.")
+		os.Exit(1)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/go-flutter-desktop/hover/cmd/packaging"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+)
+
+func init() {
+	rootCmd.AddCommand(checkIdentityCmd)
+}
+
+// identitySources describes where the expected identity values come from.
+var identitySources = map[string]string{
+	packaging.IdentityApplicationName:  "application-name in go/hover.yaml",
+	packaging.IdentityPackageName:      "package-name in go/hover.yaml",
+	packaging.IdentityExecutableName:   "executable-name in go/hover.yaml",
+	packaging.IdentityBundleIdentifier: "the android organization name and package-name in go/hover.yaml",
+	packaging.IdentityURLSchemes:       "url-schemes in go/hover.yaml",
+}
+
+var checkIdentityCmd = &cobra.Command{
+	Use:   "check-identity",
+	Short: "Check that the initialized packaging formats identify the app consistently",
+	Long: "Cross-check the application name, package name, executable name, bundle identifier and URL schemes used in the configuration files of the initialized packaging formats.\n" +
+		"Formats identifying the app differently break updaters and OS integrations.",
+	Run: func(cmd *cobra.Command, args []string) {
+		assertHoverInitialized()
+
+		version := pubspec.GetPubSpec().GetVersion()
+		var checked, mismatches int
+		for _, name := range packagingFormatNames() {
+			for _, value := range packagingTasks[name].Identity(version) {
+				checked++
+				if value.Value != value.Expected {
+					mismatches++
+					log.Warnf("%s is '%s', expected '%s' from %s", value.Location, value.Value, value.Expected, identitySources[value.Kind])
+				}
+			}
+		}
+		if checked == 0 {
+			log.Infof("No packaging format is initialized, run `%s` first.", log.Au().Magenta("hover init-packaging <format>"))
+			return
+		}
+		if mismatches > 0 {
+			log.Warnf("Found %d identity mismatches in the packaging configuration files.", mismatches)
+			return
+		}
+		log.Infof("The packaging formats identify the app consistently.")
+	},
+}
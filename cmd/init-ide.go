@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+)
+
+var initIDEEditors = []string{"vscode", "goland"}
+
+func init() {
+	initIDECmd.Flags().StringVar(&buildCachePath, "cache-path", "", "The path that hover uses to cache dependencies such as the Flutter engine .so/.dll (defaults to the standard user cache directory)")
+	initIDECmd.Flags().StringVar(&buildEngineVersion, "engine-version", "", "The flutter engine version to use.")
+	initIDECmd.Flags().StringVar(&buildLocalEngine, "local-engine", "", "The path of a locally built flutter engine to use instead of downloading it (e.g. engine/src/out/host_release).")
+	initIDECmd.Flags().StringVar(&runObservatoryPort, "observatory-port", "50300", "The observatory port of the app started by the editor, the Dart debugger attaches to it.")
+	rootCmd.AddCommand(initIDECmd)
+}
+
+var initIDECmd = &cobra.Command{
+	Use:   "init-ide <editor>",
+	Short: "Generate the configuration of an editor to work on the go code and debug the app",
+	Long: "Generate the configuration of an editor (vscode or goland) wired to the build of hover run: the go tools environment with the engine library paths, the build tags and ldflags of the app, and debug configurations starting the app with delve or attaching to it.\n" +
+		"The configuration is generated for the host OS and architecture, and must be generated again when the engine or the build settings of go/hover.yaml change. The engine is downloaded when it isn't cached.",
+	ValidArgs: initIDEEditors,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("requires one argument, the editor")
+		}
+		for _, editor := range initIDEEditors {
+			if args[0] == editor {
+				return nil
+			}
+		}
+		return errors.Errorf("unknown editor '%s', must be one of: %s", args[0], strings.Join(initIDEEditors, ", "))
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		assertInFlutterProject()
+		assertHoverInitialized()
+		targetOS := runtime.GOOS
+		// the app is built like by hover run, with the flutter bundle built by
+		// hover run.
+		buildDebug = true
+		buildSkipFlutterBuildBundle = true
+		initBuildParameters(targetOS)
+
+		switch args[0] {
+		case "vscode":
+			initVSCode(targetOS)
+		case "goland":
+			initGoLand(targetOS)
+		}
+		log.Infof("The debug configurations start the go binary built next to the one of hover run, build the flutter bundle with `hover run` first.")
+	},
+}
+
+// ideBuild is the build of the app by an editor, the one of hover run. The
+// paths of the project start with goDir, the go directory written with the
+// variables of the editor.
+type ideBuild struct {
+	env          map[string]string
+	tags         string
+	ldflags      string
+	outputDir    string
+	outputBinary string
+}
+
+func newIDEBuild(targetOS, goDir string) ideBuild {
+	outputDirPath := filepath.Join("build", "outputs", filepath.Base(build.OutputDirectoryPath(targetOS)))
+	editorOutputDirPath := goDir + "/" + filepath.ToSlash(outputDirPath)
+
+	b := ideBuild{
+		env:          make(map[string]string),
+		outputDir:    editorOutputDirPath,
+		outputBinary: editorOutputDirPath + "/" + build.OutputBinary(pubspec.GetPubSpec().Name+"-debug", targetOS),
+	}
+	for _, variable := range buildEnv(targetOS, engineCachePath) {
+		parts := strings.SplitN(variable, "=", 2)
+		value := parts[1]
+		for _, flag := range []string{"-L", "-F"} {
+			value = strings.ReplaceAll(value, flag+outputDirPath, flag+editorOutputDirPath)
+		}
+		b.env[parts[0]] = value
+	}
+	for _, arg := range buildCommand(targetOS, runVMArguments(), "") {
+		if strings.HasPrefix(arg, "-tags=") {
+			b.tags = strings.TrimPrefix(arg, "-tags=")
+		}
+		if strings.HasPrefix(arg, "-ldflags=") {
+			b.ldflags = strings.Join(strings.Fields(strings.TrimPrefix(arg, "-ldflags=")), " ")
+		}
+	}
+	return b
+}
+
+// envNames returns the names of the environment variables in order.
+func (b ideBuild) envNames() []string {
+	var names []string
+	for name := range b.env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// initVSCode merges the hover settings and launch configurations into the
+// .vscode directory, for the Go and Dart extensions.
+func initVSCode(targetOS string) {
+	b := newIDEBuild(targetOS, "${workspaceFolder}/go")
+
+	settingsPath := filepath.Join(".vscode", "settings.json")
+	settings := readVSCodeFile(settingsPath)
+	// the go tools compile the go code with cgo, like the go build
+	settings["go.toolsEnvVars"] = b.env
+	settings["go.buildTags"] = b.tags
+	writeVSCodeFile(settingsPath, settings)
+
+	launchPath := filepath.Join(".vscode", "launch.json")
+	launch := readVSCodeFile(launchPath)
+	if _, ok := launch["version"]; !ok {
+		launch["version"] = "0.2.0"
+	}
+	configurations, _ := launch["configurations"].([]interface{})
+	for _, configuration := range []map[string]interface{}{
+		{
+			"name":       "hover: debug go",
+			"type":       "go",
+			"request":    "launch",
+			"mode":       "debug",
+			"program":    "${workspaceFolder}/go/cmd",
+			"cwd":        "${workspaceFolder}/go",
+			"buildFlags": fmt.Sprintf("-tags=%s -ldflags='%s'", b.tags, b.ldflags),
+			"output":     b.outputBinary,
+			"env":        b.env,
+		},
+		{
+			"name":      "hover: attach go",
+			"type":      "go",
+			"request":   "attach",
+			"mode":      "local",
+			"processId": "${command:pickGoProcess}",
+		},
+		{
+			"name":         "hover: attach dart",
+			"type":         "dart",
+			"request":      "attach",
+			"vmServiceUri": "http://127.0.0.1:" + runObservatoryPort + "/",
+		},
+	} {
+		configurations = replaceVSCodeConfiguration(configurations, configuration)
+	}
+	launch["configurations"] = configurations
+	writeVSCodeFile(launchPath, launch)
+
+	log.Infof("Updated %s and %s", settingsPath, launchPath)
+}
+
+// replaceVSCodeConfiguration replaces the launch configuration with the same
+// name, or appends it.
+func replaceVSCodeConfiguration(configurations []interface{}, configuration map[string]interface{}) []interface{} {
+	for i, existing := range configurations {
+		if existing, ok := existing.(map[string]interface{}); ok && existing["name"] == configuration["name"] {
+			configurations[i] = configuration
+			return configurations
+		}
+	}
+	return append(configurations, configuration)
+}
+
+// readVSCodeFile reads a VS Code JSON file, empty when it doesn't exist.
+func readVSCodeFile(path string) map[string]interface{} {
+	content := make(map[string]interface{})
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return content
+	}
+	if err != nil {
+		log.Errorf("Failed to read %s: %v", path, err)
+		os.Exit(1)
+	}
+	err = json.Unmarshal(stripJSONComments(data), &content)
+	if err != nil {
+		log.Errorf("Failed to decode %s: %v", path, err)
+		os.Exit(1)
+	}
+	if !bytes.Equal(stripJSONComments(data), data) {
+		log.Warnf("The comments of %s are removed", path)
+	}
+	return content
+}
+
+func writeVSCodeFile(path string, content map[string]interface{}) {
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(content)
+	if err != nil {
+		log.Errorf("Failed to encode %s: %v", path, err)
+		os.Exit(1)
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		log.Errorf("Failed to create %s: %v", filepath.Dir(path), err)
+		os.Exit(1)
+	}
+	err = ioutil.WriteFile(path, data.Bytes(), 0644)
+	if err != nil {
+		log.Errorf("Failed to write %s: %v", path, err)
+		os.Exit(1)
+	}
+}
+
+// stripJSONComments removes the comments and trailing commas VS Code allows
+// in its JSON files.
+func stripJSONComments(data []byte) []byte {
+	var out []byte
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString:
+			if c == '\\' && i+1 < len(data) {
+				out = append(out, c)
+				i++
+				c = data[i]
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			i--
+			continue
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end == -1 {
+				i = len(data)
+			} else {
+				i += end + 3
+			}
+			continue
+		case c == '}' || c == ']':
+			trimmed := bytes.TrimRight(out, " \t\r\n")
+			if len(trimmed) > 0 && trimmed[len(trimmed)-1] == ',' {
+				out = append(trimmed[:len(trimmed)-1], out[len(trimmed):]...)
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// initGoLand writes the hover run configurations into .idea/runConfigurations.
+func initGoLand(targetOS string) {
+	b := newIDEBuild(targetOS, "$PROJECT_DIR$/go")
+	var env []map[string]string
+	for _, name := range b.envNames() {
+		env = append(env, map[string]string{"name": name, "value": b.env[name]})
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		log.Errorf("Failed to get the working directory: %v", err)
+		os.Exit(1)
+	}
+	templateData := map[string]interface{}{
+		"module":          filepath.Base(wd),
+		"goParameters":    fmt.Sprintf("-tags=%s -ldflags=\"%s\"", b.tags, b.ldflags),
+		"outputDir":       b.outputDir,
+		"env":             env,
+		"observatoryPort": runObservatoryPort,
+	}
+
+	dir := filepath.Join(".idea", "runConfigurations")
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		log.Errorf("Failed to create %s: %v", dir, err)
+		os.Exit(1)
+	}
+	for _, file := range []string{"hover_run.xml", "hover_debug_go.xml"} {
+		fileutils.ExecuteTemplateFromAssetsBox("ide/goland/"+file+".tmpl", filepath.Join(dir, file), fileutils.AssetsBox(), templateData)
+	}
+	log.Infof("Added the run configurations hover run and hover debug go to %s", dir)
+	log.Infof("Set the build tags %s in Settings | Go | Build Tags & Vendoring for the code analysis.", b.tags)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"github.com/spf13/cobra"
 
@@ -14,7 +15,14 @@ import (
 	"github.com/go-flutter-desktop/hover/internal/pubspec"
 )
 
+var initCrashHandler bool
+var initPreferences bool
+var initSingleInstance bool
+
 func init() {
+	initCmd.Flags().BoolVar(&initCrashHandler, "crash-handler", false, "Add a crash handler to the app, writing the go panics and fatal signals to crash reports.")
+	initCmd.Flags().BoolVar(&initPreferences, "preferences", false, "Add a preferences helper to the app, reading and writing the preferences of go/hover.yaml from dart (GSettings, defaults or the registry).")
+	initCmd.Flags().BoolVar(&initSingleInstance, "single-instance", false, "Run a single instance of the app, the later launches are forwarded to it and focus its window.")
 	rootCmd.AddCommand(initCmd)
 }
 
@@ -67,12 +75,24 @@ var initCmd = &cobra.Command{
 
 		fileutils.CopyAsset("app/main.go", filepath.Join(desktopCmdPath, "main.go"), fileutils.AssetsBox())
 		fileutils.CopyAsset("app/options.go", filepath.Join(desktopCmdPath, "options.go"), fileutils.AssetsBox())
+		fileutils.CopyAsset("app/wmclass.go", filepath.Join(desktopCmdPath, "wmclass.go"), fileutils.AssetsBox())
+		fileutils.CopyAsset("app/buildinfo.go", filepath.Join(desktopCmdPath, "buildinfo.go"), fileutils.AssetsBox())
+		if initCrashHandler {
+			fileutils.CopyAsset("app/crashhandler.go", filepath.Join(desktopCmdPath, "crashhandler.go"), fileutils.AssetsBox())
+		}
+		if initPreferences {
+			fileutils.CopyAsset("app/preferences.go", filepath.Join(desktopCmdPath, "preferences.go"), fileutils.AssetsBox())
+		}
+		if initSingleInstance {
+			addSingleInstanceShim()
+		}
 		fileutils.CopyAsset("app/icon.png", filepath.Join(desktopAssetsPath, "icon.png"), fileutils.AssetsBox())
 		fileutils.CopyAsset("app/gitignore", filepath.Join(build.BuildPath, ".gitignore"), fileutils.AssetsBox())
 		fileutils.ExecuteTemplateFromAssetsBox("app/hover.yaml.tmpl", filepath.Join(build.BuildPath, "hover.yaml"), fileutils.AssetsBox(), map[string]string{
 			"applicationName": emptyConfig.GetApplicationName(projectName),
 			"executableName":  emptyConfig.GetExecutableName(projectName),
 			"packageName":     emptyConfig.GetPackageName(projectName),
+			"singleInstance":  strconv.FormatBool(initSingleInstance),
 		})
 
 		initializeGoModule(projectPath)
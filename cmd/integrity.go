@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// integrityShimPath checks the integrity manifest when the app starts, added
+// to the project on the first build with integrity.manifest.
+var integrityShimPath = filepath.Join(build.BuildPath, "cmd", "integrity.go")
+
+// integrityCheck returns whether the release builds and the packages have an
+// integrity manifest, checked when the app starts. The debug builds, used by
+// hover run, aren't checked.
+func integrityCheck() bool {
+	return !buildDebug && config.GetConfig().Integrity.Manifest
+}
+
+// assertIntegrityShim adds the integrity check shim to the project when the
+// integrity manifest is enabled.
+func assertIntegrityShim() {
+	if !integrityCheck() || fileutils.IsFileExists(integrityShimPath) {
+		return
+	}
+	fileutils.CopyAsset("app/integrity.go", integrityShimPath, fileutils.AssetsBox())
+	log.Infof("Added %s, checking the integrity manifest when the app starts. Add it to git too.", integrityShimPath)
+}
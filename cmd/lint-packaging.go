@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"os"
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+	"github.com/go-flutter-desktop/hover/internal/report"
+)
+
+func init() {
+	addReportFlags(lintPackagingCmd)
+	rootCmd.AddCommand(lintPackagingCmd)
+}
+
+var lintPackagingCmd = &cobra.Command{
+	Use:   "lint-packaging [format...]",
+	Short: "Check the configuration files of the initialized packaging formats",
+	Long: "Check the configuration files in go/packaging without building or packaging the app, by default of all the initialized packaging formats.\n" +
+		"The file names and templates are executed with the template data of the current configuration, reporting the unknown template data and template errors, the files used by the packaging script that are missing, the files declaring other URL schemes than url-schemes of go/hover.yaml, and the scripts that wouldn't be executable in the package.",
+	ValidArgsFunction: completePackagingFormats,
+	Args: func(cmd *cobra.Command, args []string) error {
+		for _, arg := range args {
+			if _, ok := packagingTasks[arg]; !ok {
+				return errors.Errorf("unknown packaging format '%s'", arg)
+			}
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		assertReportFormat()
+		assertHoverInitialized()
+
+		names := args
+		if len(names) == 0 {
+			for _, name := range packagingFormatNames() {
+				if packagingTasks[name].IsInitialized() {
+					names = append(names, name)
+				}
+			}
+		}
+		if len(names) == 0 {
+			log.Infof("No packaging format is initialized, run `%s` first.", log.Au().Magenta("hover init-packaging <format>"))
+			return
+		}
+
+		version := pubspec.GetPubSpec().GetVersion()
+		suite := report.Suite{Name: "lint-packaging"}
+		var problems int
+		for _, name := range names {
+			task := packagingTasks[name]
+			if !task.IsInitialized() {
+				log.Warnf("%s is not initialized for packaging.", name)
+				suite.Cases = append(suite.Cases, report.Case{Name: name, Skipped: "not initialized for packaging"})
+				continue
+			}
+			startedOn := time.Now()
+			lintCase := report.Case{Name: name}
+			for _, problem := range task.Lint(version) {
+				problems++
+				log.Warnf("go/%s: %s", problem.File, problem.Message)
+				lintCase.Problems = append(lintCase.Problems, report.Problem{File: path.Join("go", problem.File), Message: problem.Message})
+			}
+			lintCase.Time = time.Since(startedOn)
+			suite.Cases = append(suite.Cases, lintCase)
+		}
+		writeReport(suite)
+		if problems > 0 {
+			log.Errorf("Found %d problems in the packaging configuration files.", problems)
+			os.Exit(1)
+		}
+		log.Infof("The configuration files of %d packaging formats are valid.", len(names))
+	},
+}
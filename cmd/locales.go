@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// localePattern matches the locale names, e.g. en, pt_BR, zh-Hant-TW.
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}([_-][A-Z][a-z]{3})?([_-]([A-Z]{2}|[0-9]{3}))?$`)
+
+// translationDirectories are the names of the asset directories holding
+// translations, e.g. assets/translations/fr.json.
+var translationDirectories = map[string]bool{
+	"i18n":         true,
+	"l10n":         true,
+	"lang":         true,
+	"langs":        true,
+	"languages":    true,
+	"locales":      true,
+	"translations": true,
+}
+
+// translationExtensions are the extensions of the translation files.
+var translationExtensions = map[string]bool{
+	".arb":  true,
+	".json": true,
+	".yaml": true,
+	".yml":  true,
+	".po":   true,
+}
+
+// assertLocales checks that the locales of go/hover.yaml have translations,
+// in the .arb files of the flutter gen-l10n or in the flutter assets, and
+// warns about the translations of locales that aren't declared.
+func assertLocales(targetOS string) {
+	if len(config.GetConfig().Locales) == 0 {
+		return
+	}
+	var declared []string
+	for _, locale := range config.GetConfig().Locales {
+		if !localePattern.MatchString(locale) {
+			log.Errorf("Invalid locale %s in go/hover.yaml, expected a language code with an optional script and region, e.g. en or pt_BR.", locale)
+			os.Exit(1)
+		}
+		declared = append(declared, normalizeLocale(locale))
+	}
+
+	found := make(map[string][]string)
+	arbLocales(found)
+	assetLocales(filepath.Join(build.OutputDirectoryPath(targetOS), "flutter_assets"), found)
+
+	var missing []string
+	for _, locale := range declared {
+		translated := false
+		for resourceLocale := range found {
+			// the app falls back to the translations of the language
+			if resourceLocale == locale || strings.HasPrefix(resourceLocale, locale+"_") || strings.HasPrefix(locale, resourceLocale+"_") {
+				translated = true
+				break
+			}
+		}
+		if !translated {
+			missing = append(missing, locale)
+		}
+	}
+
+	var undeclared []string
+	for resourceLocale := range found {
+		isDeclared := false
+		for _, locale := range declared {
+			if resourceLocale == locale || strings.HasPrefix(resourceLocale, locale+"_") || strings.HasPrefix(locale, resourceLocale+"_") {
+				isDeclared = true
+				break
+			}
+		}
+		if !isDeclared {
+			undeclared = append(undeclared, resourceLocale)
+		}
+	}
+	sort.Strings(undeclared)
+	for _, locale := range undeclared {
+		log.Warnf("The locale %s has translations (%s) but isn't in the locales of go/hover.yaml.", locale, strings.Join(found[locale], ", "))
+	}
+
+	if len(missing) > 0 {
+		log.Errorf("Missing translations for the locales of go/hover.yaml: %s", strings.Join(missing, ", "))
+		log.Errorf("Add their .arb files or translation assets, or remove them from the locales.")
+		os.Exit(1)
+	}
+}
+
+// normalizeLocale returns a locale with underscores, as in the .arb files.
+func normalizeLocale(locale string) string {
+	return strings.ReplaceAll(locale, "-", "_")
+}
+
+// arbLocales adds the locales of the .arb files of the arb-dir of l10n.yaml,
+// lib/l10n by default. The @@locale of a file takes precedence over its name.
+func arbLocales(found map[string][]string) {
+	arbDir := filepath.Join("lib", "l10n")
+	if content, err := ioutil.ReadFile("l10n.yaml"); err == nil {
+		var l10n struct {
+			ArbDir string `yaml:"arb-dir"`
+		}
+		err = yaml.Unmarshal(content, &l10n)
+		if err != nil {
+			log.Warnf("Failed to decode l10n.yaml: %v", err)
+		} else if l10n.ArbDir != "" {
+			arbDir = filepath.FromSlash(l10n.ArbDir)
+		}
+	}
+	paths, _ := filepath.Glob(filepath.Join(arbDir, "*.arb"))
+	for _, path := range paths {
+		locale := fileLocale(path)
+		if content, err := ioutil.ReadFile(path); err == nil {
+			var arb struct {
+				Locale string `json:"@@locale"`
+			}
+			if json.Unmarshal(content, &arb) == nil && arb.Locale != "" {
+				locale = normalizeLocale(arb.Locale)
+			}
+		}
+		if locale != "" {
+			found[locale] = append(found[locale], path)
+		}
+	}
+}
+
+// assetLocales adds the locales of the translation files of the flutter
+// assets, in the translation directories. The locale is the name of the file
+// (fr.json, app_fr.arb) or of its directory (translations/fr/app.json).
+func assetLocales(assetsPath string, found map[string][]string) {
+	filepath.Walk(assetsPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !translationExtensions[filepath.Ext(path)] {
+			return nil
+		}
+		relativePath, err := filepath.Rel(assetsPath, path)
+		if err != nil {
+			return nil
+		}
+		segments := strings.Split(filepath.ToSlash(relativePath), "/")
+		for i, segment := range segments[:len(segments)-1] {
+			if !translationDirectories[strings.ToLower(segment)] {
+				continue
+			}
+			locale := fileLocale(path)
+			if locale == "" && i+2 < len(segments) && localePattern.MatchString(segments[i+1]) {
+				locale = normalizeLocale(segments[i+1])
+			}
+			if locale != "" {
+				found[locale] = append(found[locale], filepath.Join("flutter_assets", relativePath))
+			}
+			break
+		}
+		return nil
+	})
+}
+
+// fileLocale returns the locale of a translation file named after it, e.g.
+// fr.json or app_pt_BR.arb, empty when the name has no locale.
+func fileLocale(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	parts := strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' })
+	// the longest suffix that is a locale, e.g. pt_BR before BR
+	for i := 0; i < len(parts); i++ {
+		locale := strings.Join(parts[i:], "_")
+		if localePattern.MatchString(locale) {
+			return locale
+		}
+	}
+	return ""
+}
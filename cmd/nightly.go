@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/publish"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+)
+
+var (
+	nightlyDocker      bool
+	nightlySkipPublish bool
+)
+
+func init() {
+	nightlyCmd.Flags().BoolVar(&nightlyDocker, "docker", false, "Execute the go build and packaging of the nightly builds in a docker container.")
+	nightlyCmd.Flags().BoolVar(&nightlySkipPublish, "skip-publish", false, "Only build the nightly channel, without publishing it.")
+	rootCmd.AddCommand(nightlyCmd)
+}
+
+var nightlyCmd = &cobra.Command{
+	Use:   "nightly",
+	Short: "Build and publish the nightly channel of the application",
+	Long: "Build the targets and packaging formats of nightly.builds in go/hover.yaml for each of nightly.arches, with a date-based version, and publish them to the nightly destinations.\n" +
+		"The nightly channel has its own application name, executable name and package name, so it's installed next to the stable channel.\n" +
+		"The artifacts are uploaded to nightly.destination like hover publish does, the linux-snap is released to the nightly.snap-channel of the Snap Store (edge by default), and a GitHub pre-release of nightly.github-release is created with the artifacts.\n" +
+		"The command never prompts, so it can be run by a scheduler: it exits with an error when a build or a destination fails, and nothing is published when a build failed.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// the name overrides apply to this process and to the builds
+		os.Setenv(config.NightlyEnv, "true")
+		assertHoverInitialized()
+		nightly := config.GetConfig().Nightly
+		if len(nightly.Builds) == 0 {
+			log.Errorf("No nightly builds are configured, set nightly.builds in go/hover.yaml, e.g. [linux-deb, linux-snap].")
+			os.Exit(1)
+		}
+		for _, name := range nightly.Builds {
+			if !isBuildSubcommand(name) {
+				log.Errorf("Unknown nightly build '%s' in go/hover.yaml, use a target or packaging format of `%s`.", name, log.Au().Magenta("hover build"))
+				os.Exit(1)
+			}
+		}
+		buildTime, err := nightlyBuildTime()
+		if err != nil {
+			log.Errorf("%v", err)
+			os.Exit(1)
+		}
+		version := nightlyVersion(pubspec.GetPubSpec().GetVersion(), buildTime)
+		log.Infof("Building the nightly version %s", version)
+
+		startedOn := time.Now()
+		var failed []string
+		for _, arch := range config.GetConfig().GetNightlyArches() {
+			for _, name := range nightly.Builds {
+				err := runNightlyBuild(name, arch, version)
+				if err != nil {
+					log.Warnf("The nightly build %s (%s) failed: %v", name, arch, err)
+					failed = append(failed, name+"-"+arch)
+				}
+			}
+		}
+		if len(failed) > 0 {
+			log.Errorf("The nightly builds %s failed, nothing was published.", strings.Join(failed, ", "))
+			os.Exit(1)
+		}
+		if nightlySkipPublish {
+			log.Infof("Built the nightly version %s", version)
+			return
+		}
+
+		artifacts := nightlyArtifacts(nightly.Builds, version, startedOn)
+		if len(artifacts) == 0 {
+			log.Errorf("The nightly builds have no packaging outputs to publish, add packaging formats to nightly.builds in go/hover.yaml.")
+			os.Exit(1)
+		}
+		publishNightly(nightly, version, buildTime, artifacts)
+	},
+}
+
+// isBuildSubcommand returns whether hover build has a subcommand, a target or
+// a packaging format.
+func isBuildSubcommand(name string) bool {
+	for _, subcommand := range buildCmd.Commands() {
+		if subcommand.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// nightlyBuildTime returns the time the nightly version is derived from, the
+// current time unless SOURCE_DATE_EPOCH is set.
+func nightlyBuildTime() (time.Time, error) {
+	return build.BuildTime(time.Now())
+}
+
+// nightlyVersion returns the version of a nightly build: the version of
+// pubspec.yaml without its pre-release and build metadata, followed by the
+// nightly pre-release of the build time, e.g. 1.2.3-nightly.202401310200
+// for 1.2.3+4.
+func nightlyVersion(version string, buildTime time.Time) string {
+	version = strings.SplitN(strings.SplitN(version, "+", 2)[0], "-", 2)[0]
+	return version + "-nightly." + buildTime.Format("200601021504")
+}
+
+// runNightlyBuild runs hover build for a target or packaging format of the
+// nightly builds, in a child hover process as a failing build exits the
+// process.
+func runNightlyBuild(name, arch, version string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	args := []string{"build", name, "--arch", arch, "--version-number", version}
+	if nightlyDocker {
+		args = append(args, "--docker")
+	}
+	log.Printf("Running hover %s", strings.Join(args, " "))
+	cmdBuild := exec.Command(executable, args...)
+	cmdBuild.Stdout = os.Stdout
+	cmdBuild.Stderr = os.Stderr
+	return cmdBuild.Run()
+}
+
+// nightlyArtifacts returns the packaging outputs of the nightly builds,
+// written since they started.
+func nightlyArtifacts(builds []string, version string, startedOn time.Time) []publish.Artifact {
+	all, err := publish.ListArtifacts(filepath.Join(build.BuildPath, "build", "outputs"), version)
+	if err != nil {
+		log.Errorf("Failed to find the packaging outputs: %v", err)
+		os.Exit(1)
+	}
+	var artifacts []publish.Artifact
+	for _, artifact := range all {
+		if !containsString(builds, artifact.Format) {
+			continue
+		}
+		info, err := os.Stat(artifact.LocalPath())
+		if err != nil || info.ModTime().Before(startedOn) {
+			continue
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts
+}
+
+// publishNightly publishes the artifacts of a nightly version to the
+// destinations configured in go/hover.yaml.
+func publishNightly(nightly config.NightlyConfig, version string, buildTime time.Time, artifacts []publish.Artifact) {
+	applicationName := config.GetConfig().GetApplicationName(pubspec.GetPubSpec().Name)
+	var published bool
+	if nightly.Destination != "" {
+		target, err := publish.NewTarget(nightly.Destination, false)
+		if err != nil {
+			log.Errorf("Invalid nightly destination: %v", err)
+			os.Exit(1)
+		}
+		err = publish.Publish(target, applicationName, version, artifacts)
+		if err != nil {
+			log.Errorf("Publishing the nightly version failed: %v", err)
+			os.Exit(1)
+		}
+		log.Infof("Published the nightly version %s to %s", version, nightly.Destination)
+		published = true
+	}
+	var snaps int
+	for _, artifact := range artifacts {
+		if artifact.Format != "linux-snap" {
+			continue
+		}
+		err := publish.UploadSnap(artifact, config.GetConfig().GetNightlySnapChannel())
+		if err != nil {
+			log.Errorf("%v", err)
+			os.Exit(1)
+		}
+		snaps++
+	}
+	if snaps > 0 {
+		log.Infof("Released the nightly snap to the %s channel", config.GetConfig().GetNightlySnapChannel())
+		published = true
+	}
+	if nightly.GithubRelease != "" {
+		notes := fmt.Sprintf("Nightly build of %s, built on %s.", applicationName, buildTime.Format("2006-01-02 15:04 MST"))
+		err := publish.CreateGithubPrerelease(nightly.GithubRelease, "v"+version, applicationName+" "+version, notes, artifacts)
+		if err != nil {
+			log.Errorf("%v", err)
+			os.Exit(1)
+		}
+		log.Infof("Created the GitHub pre-release v%s of %s", version, nightly.GithubRelease)
+		published = true
+	}
+	if !published {
+		log.Warnf("No nightly destination is configured, set nightly.destination, nightly.github-release or add linux-snap to nightly.builds in go/hover.yaml.")
+	}
+}
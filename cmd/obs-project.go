@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/go-flutter-desktop/hover/cmd/packaging"
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/publish"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+)
+
+var (
+	obsProjectVersionNumber string
+	obsProjectArch          string
+	obsProjectOutput        string
+	obsProjectSourceURL     string
+	obsProjectUpload        string
+)
+
+func init() {
+	obsProjectCmd.Flags().StringVar(&obsProjectVersionNumber, "version-number", "", "Override the version number of the package.")
+	obsProjectCmd.Flags().StringVar(&obsProjectArch, "arch", build.DefaultTargetArch, "The architecture of the linux build output to package: amd64 or arm64.")
+	obsProjectCmd.Flags().StringVar(&obsProjectOutput, "output", filepath.Join(build.BuildPath, "build", "obs"), "The directory the OBS package is written to.")
+	obsProjectCmd.Flags().StringVar(&obsProjectSourceURL, "source-url", "", "The URL of the directory the tarball is published in, OBS downloads it instead of the tarball being uploaded.")
+	obsProjectCmd.Flags().StringVar(&obsProjectUpload, "upload", "", "The OBS project the package is committed to with osc, e.g. home:me.")
+	rootCmd.AddCommand(obsProjectCmd)
+}
+
+var obsProjectCmd = &cobra.Command{
+	Use:   "obs-project",
+	Short: "Write an Open Build Service package of the linux build, from the linux-rpm templates",
+	Long: "Write the spec of the linux-rpm templates, a tarball of the linux build output and a _service file verifying the tarball to go/build/obs, an Open Build Service package building the rpm packages of several distributions.\n" +
+		"Build the app with `hover build linux` first. With --upload, the package is committed to an OBS project with osc, and created when it doesn't exist.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertHoverInitialized()
+		packaging.LinuxRpmTask.AssertInitialized()
+		build.SetTargetArch(obsProjectArch)
+		projectName := pubspec.GetPubSpec().Name
+		executableName := config.GetConfig().GetExecutableName(projectName)
+		if !fileutils.IsFileExists(build.OutputBinaryPath(executableName, "linux")) {
+			log.Errorf("No linux build of %s, run `%s` first.", obsProjectArch, log.Au().Magenta("hover build linux --arch "+obsProjectArch))
+			os.Exit(1)
+		}
+
+		version := obsProjectVersionNumber
+		if version == "" {
+			version = pubspec.GetPubSpec().GetVersion()
+		}
+		err := os.RemoveAll(obsProjectOutput)
+		if err != nil {
+			log.Errorf("Failed to clean %s: %v", obsProjectOutput, err)
+			os.Exit(1)
+		}
+		files, err := packaging.WriteObsProject(obsProjectOutput, version, obsProjectSourceURL)
+		if err != nil {
+			log.Errorf("Failed to write the OBS package: %v", err)
+			os.Exit(1)
+		}
+		log.Infof("Wrote the OBS package to %s", obsProjectOutput)
+
+		if obsProjectUpload == "" {
+			return
+		}
+		packageName := config.GetConfig().GetPackageName(projectName)
+		err = publish.UpdateObsPackage(obsProjectUpload, packageName, config.GetConfig().GetApplicationName(projectName), version, obsProjectOutput, files)
+		if err != nil {
+			log.Errorf("%v", err)
+			os.Exit(1)
+		}
+		log.Infof("Committed the OBS package %s/%s", obsProjectUpload, packageName)
+	},
+}
@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/tcnksm/go-latest"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/flutterversion"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// goFlutterModule is the module path of go-flutter.
+const goFlutterModule = "github.com/go-flutter-desktop/go-flutter"
+
+var outdatedApply bool
+
+func init() {
+	outdatedCmd.Flags().BoolVar(&outdatedApply, "apply", false, "Update the outdated dependencies and verify the app still builds for the current platform.")
+	rootCmd.AddCommand(outdatedCmd)
+}
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "List the dependencies of the go side of the project which have newer versions",
+	Long: "List the newer versions of go-flutter and of the go implementations of the plugins in go/go.mod, of the engine pinned with engine-version in go/hover.yaml when flutter requires another one, and of hover itself.\n" +
+		"With --apply, the dependencies are updated and the app is built for the current platform to verify it. When the build fails, go.mod, go.sum and go/hover.yaml are restored. hover is updated last, with `go install`.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertInFlutterProject()
+		assertHoverInitialized()
+
+		var dependencies []outdatedDependency
+		goModules, err := outdatedGoModules()
+		if err != nil {
+			log.Warnf("Skipping the go modules: %v", err)
+		}
+		dependencies = append(dependencies, goModules...)
+		if engine := outdatedEngine(); engine != nil {
+			dependencies = append(dependencies, *engine)
+		}
+		hover, err := outdatedHover()
+		if err != nil {
+			log.Warnf("Skipping hover: %v", err)
+		}
+		if hover != nil {
+			dependencies = append(dependencies, *hover)
+		}
+
+		if len(dependencies) == 0 {
+			log.Infof("The dependencies are up to date.")
+			return
+		}
+		for _, dependency := range dependencies {
+			log.Infof("     %s: %s -> %s", dependency.name, dependency.current, dependency.latest)
+		}
+		if !outdatedApply {
+			log.Infof("Run `%s` to update them.", log.Au().Magenta("hover outdated --apply"))
+			return
+		}
+
+		err = applyOutdated(dependencies)
+		if err != nil {
+			log.Errorf("Updating the dependencies failed: %v", err)
+			os.Exit(1)
+		}
+		if hover != nil {
+			err = hover.apply()
+			if err != nil {
+				log.Errorf("Updating hover failed: %v", err)
+				os.Exit(1)
+			}
+			log.Infof("Updated hover to %s", hover.latest)
+		}
+	},
+}
+
+// outdatedDependency is a dependency of the project with a newer version,
+// and how to update it.
+type outdatedDependency struct {
+	name    string
+	current string
+	latest  string
+	project bool // whether the update changes the project, and is verified by a build
+	apply   func() error
+}
+
+// outdatedGoModules returns go-flutter and the modules of the imported go
+// plugins which have a newer version than the one of go.mod.
+func outdatedGoModules() ([]outdatedDependency, error) {
+	cmdGoList := exec.Command(build.GoBin(), "list", "-m", "-u", "-f", "{{.Path}} {{.Version}}{{with .Update}} {{.Version}}{{end}}", "all")
+	cmdGoList.Dir = build.BuildPath
+	cmdGoList.Env = append(os.Environ(), "GO111MODULE=on")
+	cmdGoList.Stderr = os.Stderr
+	output, err := cmdGoList.Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the updates of the go modules")
+	}
+	modules := map[string][]string{}
+	var modulePaths []string
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 {
+			modules[fields[0]] = fields[1:]
+			modulePaths = append(modulePaths, fields[0])
+		}
+	}
+
+	names := map[string]string{goFlutterModule: "go-flutter"}
+	imports, err := filepath.Glob(filepath.Join(build.BuildPath, "cmd", "import-*-plugin.go"))
+	if err != nil {
+		return nil, err
+	}
+	for _, importFile := range imports {
+		pluginName := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(importFile), "import-"), "-plugin.go")
+		importPath, err := readPluginGoImport(importFile, pluginName)
+		if err != nil {
+			log.Warnf("Skipping the plugin %s: %v", pluginName, err)
+			continue
+		}
+		if module := moduleOf(importPath, modulePaths); module != "" {
+			names[module] = "plugin " + pluginName
+		}
+	}
+
+	var dependencies []outdatedDependency
+	for module, name := range names {
+		versions := modules[module]
+		if len(versions) < 2 {
+			continue
+		}
+		module, version := module, versions[1]
+		dependencies = append(dependencies, outdatedDependency{
+			name:    name + " (" + module + ")",
+			current: versions[0],
+			latest:  version,
+			project: true,
+			apply: func() error {
+				cmdGoGet := exec.Command(build.GoBin(), "get", "-d", module+"@"+version)
+				cmdGoGet.Dir = build.BuildPath
+				cmdGoGet.Env = append(os.Environ(), "GO111MODULE=on")
+				cmdGoGet.Stdout = os.Stdout
+				cmdGoGet.Stderr = os.Stderr
+				return errors.Wrapf(cmdGoGet.Run(), "go get %s@%s failed", module, version)
+			},
+		})
+	}
+	sort.Slice(dependencies, func(i, j int) bool { return dependencies[i].name < dependencies[j].name })
+	return dependencies, nil
+}
+
+// outdatedEngine returns the engine pinned with engine-version in
+// go/hover.yaml when the flutter installation requires another engine.
+func outdatedEngine() *outdatedDependency {
+	pinned := config.GetConfig().Engine
+	if pinned == "" {
+		return nil
+	}
+	required := flutterversion.FlutterRequiredEngineVersion()
+	if required == "" || required == pinned {
+		return nil
+	}
+	return &outdatedDependency{
+		name:    "engine-version (go/hover.yaml)",
+		current: pinned,
+		latest:  required,
+		project: true,
+		apply: func() error {
+			hoverConfigPath := filepath.Join(build.BuildPath, "hover.yaml")
+			hoverConfig, err := ioutil.ReadFile(hoverConfigPath)
+			if err != nil {
+				return err
+			}
+			return ioutil.WriteFile(hoverConfigPath, config.SetValue(hoverConfig, "engine-version", required), 0644)
+		},
+	}
+}
+
+// outdatedHover returns hover when a newer release is tagged on GitHub.
+// The development builds of hover have no version to compare.
+func outdatedHover() (*outdatedDependency, error) {
+	current := hoverVersion()
+	if current == "" || current == "(devel)" {
+		log.Printf("hover is a development build, skipping its update check")
+		return nil, nil
+	}
+	githubTag := &latest.GithubTag{
+		Owner:             "go-flutter-desktop",
+		Repository:        "hover",
+		FixVersionStrFunc: latest.DeleteFrontV(),
+	}
+	res, err := latest.Check(githubTag, strings.TrimPrefix(current, "v"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check the latest release of hover")
+	}
+	if !res.Outdated {
+		return nil, nil
+	}
+	version := "v" + res.Current
+	return &outdatedDependency{
+		name:    "hover",
+		current: current,
+		latest:  version,
+		apply: func() error {
+			cmdGoInstall := exec.Command(build.GoBin(), "install", "github.com/go-flutter-desktop/hover@"+version)
+			// outside of the go module of the app
+			cmdGoInstall.Dir = os.TempDir()
+			cmdGoInstall.Env = append(os.Environ(), "GO111MODULE=on")
+			cmdGoInstall.Stdout = os.Stdout
+			cmdGoInstall.Stderr = os.Stderr
+			return cmdGoInstall.Run()
+		},
+	}, nil
+}
+
+// applyOutdated updates the dependencies of the project and builds the app
+// for the current platform. When an update or the build fails, go.mod, go.sum
+// and go/hover.yaml are restored.
+func applyOutdated(dependencies []outdatedDependency) (err error) {
+	backups := make(map[string][]byte)
+	for _, path := range []string{filepath.Join(build.BuildPath, "hover.yaml"), filepath.Join(build.BuildPath, "go.mod"), filepath.Join(build.BuildPath, "go.sum")} {
+		content, err := ioutil.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to back up %s", path)
+		}
+		backups[path] = content
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		log.Warnf("Restoring go.mod, go.sum and go/hover.yaml")
+		for path, content := range backups {
+			if content == nil {
+				os.Remove(path)
+				continue
+			}
+			if restoreErr := ioutil.WriteFile(path, content, 0644); restoreErr != nil {
+				log.Warnf("Failed to restore %s: %v", path, restoreErr)
+			}
+		}
+	}()
+
+	var updated bool
+	for _, dependency := range dependencies {
+		if !dependency.project {
+			continue
+		}
+		log.Printf("Updating %s to %s", dependency.name, dependency.latest)
+		err = dependency.apply()
+		if err != nil {
+			return err
+		}
+		updated = true
+	}
+	if !updated {
+		return nil
+	}
+
+	// the build runs in a child hover process as a failing build exits the
+	// process
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	log.Printf("Verifying the updates with hover build %s", runtime.GOOS)
+	cmdBuild := exec.Command(executable, "build", runtime.GOOS)
+	cmdBuild.Stdout = os.Stdout
+	cmdBuild.Stderr = os.Stderr
+	err = cmdBuild.Run()
+	if err != nil {
+		return errors.Wrap(err, "the verification build failed")
+	}
+	log.Infof("Updated the dependencies, the app builds for %s", runtime.GOOS)
+	return nil
+}
@@ -1,112 +1,179 @@
 package cmd
 
 import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/go-flutter-desktop/hover/cmd/packaging"
+	"github.com/go-flutter-desktop/hover/internal/log"
 )
 
 func init() {
-	initPackagingCmd.AddCommand(initLinuxSnapCmd)
-	initPackagingCmd.AddCommand(initLinuxDebCmd)
-	initPackagingCmd.AddCommand(initLinuxAppImageCmd)
-	initPackagingCmd.AddCommand(initLinuxRpmCmd)
-	initPackagingCmd.AddCommand(initLinuxPkgCmd)
-	initPackagingCmd.AddCommand(initWindowsMsiCmd)
-	initPackagingCmd.AddCommand(initDarwinBundleCmd)
-	initPackagingCmd.AddCommand(initDarwinPkgCmd)
-	initPackagingCmd.AddCommand(initDarwinDmgCmd)
+	for _, format := range packagingFormats {
+		addPackagingFormat(format)
+	}
 	rootCmd.AddCommand(initPackagingCmd)
+	rootCmd.AddCommand(upgradePackagingCmd)
 }
 
-var initPackagingCmd = &cobra.Command{
-	Use:   "init-packaging",
-	Short: "Create configuration files for a packaging format",
+// packagingFormat is a packaging format of `hover init-packaging` and `hover
+// build`.
+type packagingFormat struct {
+	name          string         // Name of the packaging format: OS-TYPE
+	task          packaging.Task // Packaging task of the format
+	configuration string         // What `hover init-packaging` creates the configuration files for, e.g. "snap packaging"
+	packaging     string         // What `hover build` does with the build, e.g. "package it for snap"
 }
 
-var initLinuxSnapCmd = &cobra.Command{
-	Use:   "linux-snap",
-	Short: "Create configuration files for snap packaging",
-	Run: func(cmd *cobra.Command, args []string) {
-		assertHoverInitialized()
-
-		packaging.LinuxSnapTask.Init()
-	},
+// packagingFormats are the packaging formats of hover, the custom formats
+// are added by addCustomPackagingFormats.
+var packagingFormats = []packagingFormat{
+	{"linux-snap", packaging.LinuxSnapTask, "snap packaging", "package it for snap"},
+	{"linux-deb", packaging.LinuxDebTask, "deb packaging", "package it for deb"},
+	{"linux-deb-src", packaging.LinuxDebSrcTask, "debian source packaging", "package it as a debian source package"},
+	{"linux-appimage", packaging.LinuxAppImageTask, "AppImage packaging", "package it for AppImage"},
+	{"linux-appdir", packaging.LinuxAppDirTask, "AppDir packaging", "package it as an AppDir, without making the AppImage"},
+	{"linux-runimage", packaging.LinuxRunImageTask, "self-extracting squashfs packaging (experimental)", "package it as a self-extracting squashfs (experimental)"},
+	{"linux-flatpak", packaging.LinuxFlatpakTask, "flatpak packaging", "package it for flatpak"},
+	{"linux-rpm", packaging.LinuxRpmTask, "rpm packaging", "package it for rpm"},
+	{"linux-pkg", packaging.LinuxPkgTask, "pacman pkg packaging", "package it for pacman pkg"},
+	{"linux-pacman", packaging.LinuxPacmanTask, "pacman packaging with install scriptlets", "package it for pacman with install scriptlets"},
+	{"linux-aur", packaging.LinuxAurTask, "AUR packaging", "package it as a tarball for the AUR"},
+	{"linux-kiosk", packaging.LinuxKioskTask, "kiosk deb packaging", "package it as a kiosk deb"},
+	{"linux-overlay", packaging.LinuxOverlayTask, "rootfs overlay packaging", "package it as a rootfs overlay for image builders"},
+	{"linux-tar", packaging.LinuxTarTask, "tar.gz archive packaging", "package it as a tar.gz archive"},
+	{"linux-nix", packaging.LinuxNixTask, "the nix derivation and flake", "package it as a nix derivation and flake"},
+	{"linux-run", packaging.LinuxRunTask, "the makeself self-extracting installer", "package it as a makeself self-extracting installer"},
+	{"linux-apk", packaging.LinuxApkTask, "Alpine apk packaging", "package it as an Alpine apk"},
+	{"windows-msi", packaging.WindowsMsiTask, "msi packaging", "package it for msi"},
+	{"windows-msix", packaging.WindowsMsixTask, "msix packaging", "package it for msix"},
+	{"windows-portable", packaging.WindowsPortableTask, "portable folder packaging", "package it as a portable zip"},
+	{"windows-nsis", packaging.WindowsNsisTask, "NSIS installer packaging", "package it as a NSIS installer"},
+	{"windows-inno", packaging.WindowsInnoTask, "Inno Setup packaging", "package it for Inno Setup"},
+	{"windows-choco", packaging.WindowsChocoTask, "Chocolatey packaging", "package it for Chocolatey"},
+	{"windows-winget", packaging.WindowsWingetTask, "the winget manifests", "generate the winget manifests of its msi"},
+	{"windows-scoop", packaging.WindowsScoopTask, "the Scoop manifest", "generate the Scoop manifest of its portable zip"},
+	{"darwin-cask", packaging.DarwinCaskTask, "the Homebrew cask", "generate the Homebrew cask of its dmg"},
+	{"darwin-bundle", packaging.DarwinBundleTask, "OSX bundle packaging", "package it for OSX bundle"},
+	{"darwin-pkg", packaging.DarwinPkgTask, "OSX pkg installer packaging", "package it for OSX pkg installer"},
+	{"darwin-dmg", packaging.DarwinDmgTask, "OSX dmg packaging", "package it for OSX dmg"},
+	{"darwin-appstore", packaging.DarwinAppStoreTask, "Mac App Store packaging", "package it for the Mac App Store"},
+	{"freebsd-pkg", packaging.FreebsdPkgTask, "freebsd pkg packaging", "package it for pkg"},
 }
 
-var initLinuxDebCmd = &cobra.Command{
-	Use:   "linux-deb",
-	Short: "Create configuration files for deb packaging",
-	Run: func(cmd *cobra.Command, args []string) {
-		assertHoverInitialized()
-
-		packaging.LinuxDebTask.Init()
-	},
+// packagingTasks contains the packaging tasks by packaging format name.
+var packagingTasks = map[string]packaging.Task{}
+
+// addPackagingFormat adds the `hover init-packaging` and `hover build`
+// subcommands of a packaging format.
+func addPackagingFormat(format packagingFormat) {
+	packagingTasks[format.name] = format.task
+	targetOS := strings.SplitN(format.name, "-", 2)[0]
+	initPackagingCmd.AddCommand(&cobra.Command{
+		Use:   format.name,
+		Short: "Create configuration files for " + format.configuration,
+		Run: func(cmd *cobra.Command, args []string) {
+			assertHoverInitialized()
+
+			format.task.Init()
+		},
+	})
+	buildCmd.AddCommand(&cobra.Command{
+		Use:   format.name,
+		Short: "Build a desktop release for " + targetOS + " and " + format.packaging,
+		Run: func(cmd *cobra.Command, args []string) {
+			subcommandBuild(targetOS, format.task)
+		},
+	})
 }
 
-var initLinuxAppImageCmd = &cobra.Command{
-	Use:   "linux-appimage",
-	Short: "Create configuration files for AppImage packaging",
-	Run: func(cmd *cobra.Command, args []string) {
-		assertHoverInitialized()
-
-		packaging.LinuxAppImageTask.Init()
-	},
+// addCustomPackagingFormats adds the custom packaging formats of go/packaging
+// and of the global formats directory, defined by their hover-format.yaml.
+// They are read before the command runs, to be completed by the shell and
+// listed by `hover build --help`.
+func addCustomPackagingFormats() {
+	formats, problems := packaging.CustomFormats()
+	// the shell would take the warnings for completions
+	warn := !isCompletionRequest()
+	for _, problem := range problems {
+		if warn {
+			log.Warnf("Ignoring a custom packaging format: %v", problem)
+		}
+	}
+	for _, format := range formats {
+		if _, ok := packagingTasks[format.Name]; ok {
+			if warn {
+				log.Warnf("Ignoring the custom packaging format of %s, hover has a %s packaging format.", format.Directory, format.Name)
+			}
+			continue
+		}
+		addPackagingFormat(packagingFormat{
+			name:          format.Name,
+			task:          format.Task,
+			configuration: "the custom format packaging it as " + format.Description,
+			packaging:     "package it as " + format.Description + " (custom format)",
+		})
+	}
 }
-var initLinuxRpmCmd = &cobra.Command{
-	Use:   "linux-rpm",
-	Short: "Create configuration files for rpm packaging",
-	Run: func(cmd *cobra.Command, args []string) {
-		assertHoverInitialized()
 
-		packaging.LinuxRpmTask.Init()
-	},
+// completePackagingFormats completes the packaging format arguments of a
+// command, leaving out the ones already given.
+func completePackagingFormats(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	given := make(map[string]bool, len(args))
+	for _, arg := range args {
+		given[arg] = true
+	}
+	var names []string
+	for _, name := range packagingFormatNames() {
+		if !given[name] && strings.HasPrefix(name, toComplete) {
+			names = append(names, name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
 }
-var initLinuxPkgCmd = &cobra.Command{
-	Use:   "linux-pkg",
-	Short: "Create configuration files for pacman pkg packaging",
-	Run: func(cmd *cobra.Command, args []string) {
-		assertHoverInitialized()
 
-		packaging.LinuxPkgTask.Init()
-	},
+func packagingFormatNames() []string {
+	var names []string
+	for name := range packagingTasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
-var initWindowsMsiCmd = &cobra.Command{
-	Use:   "windows-msi",
-	Short: "Create configuration files for msi packaging",
-	Run: func(cmd *cobra.Command, args []string) {
-		assertHoverInitialized()
 
-		packaging.WindowsMsiTask.Init()
-	},
+var initPackagingCmd = &cobra.Command{
+	Use:               "init-packaging",
+	Short:             "Create configuration files for a packaging format",
+	ValidArgsFunction: completeSubcommands,
 }
 
-var initDarwinBundleCmd = &cobra.Command{
-	Use:   "darwin-bundle",
-	Short: "Create configuration files for OSX bundle packaging",
-	Run: func(cmd *cobra.Command, args []string) {
-		assertHoverInitialized()
-
-		packaging.DarwinBundleTask.Init()
+var upgradePackagingCmd = &cobra.Command{
+	Use:   "upgrade-packaging <format>",
+	Short: "Update the configuration files of a packaging format to the current hover templates",
+	Long: "Update the configuration files of a packaging format to the current hover templates.\n" +
+		"Changes made to the hover templates since the packaging format was initialized are merged into the files in go/packaging/<format>.\n" +
+		"Conflicting changes are marked in the files and must be resolved manually.",
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completePackagingFormats(cmd, args, toComplete)
 	},
-}
-
-var initDarwinPkgCmd = &cobra.Command{
-	Use:   "darwin-pkg",
-	Short: "Create configuration files for OSX pkg installer packaging",
-	Run: func(cmd *cobra.Command, args []string) {
-		assertHoverInitialized()
-
-		packaging.DarwinPkgTask.Init()
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("requires one argument, the packaging format")
+		}
+		if _, ok := packagingTasks[args[0]]; !ok {
+			return errors.Errorf("unknown packaging format '%s', must be one of: %s", args[0], strings.Join(packagingFormatNames(), ", "))
+		}
+		return nil
 	},
-}
-
-var initDarwinDmgCmd = &cobra.Command{
-	Use:   "darwin-dmg",
-	Short: "Create configuration files for OSX dmg packaging",
 	Run: func(cmd *cobra.Command, args []string) {
 		assertHoverInitialized()
 
-		packaging.DarwinDmgTask.Init()
+		packagingTasks[args[0]].Upgrade()
 	},
 }
@@ -0,0 +1,198 @@
+package packaging
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-flutter-desktop/hover/internal/androidmanifest"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// oarsAttributePattern matches the content rating attributes of OARS 1.1,
+// e.g. violence-cartoon.
+var oarsAttributePattern = regexp.MustCompile(`^[a-z]+-[a-z-]+$`)
+
+// appstreamID returns the AppStream component id of the app, its reverse-DNS
+// name, which is the app-id of the linux-flatpak package too.
+func appstreamID(packageName string) string {
+	return androidmanifest.AndroidOrganizationName() + "." + packageName
+}
+
+// writeAppStreamMetainfo writes the AppStream metainfo of the app to a
+// directory, from appstream of go/hover.yaml and pubspec.yaml. The software
+// centers, such as GNOME Software and KDE Discover, list the apps by their
+// metainfo. The desktopID is the name of the installed .desktop file.
+func writeAppStreamMetainfo(dir string, data map[string]string, desktopID string) {
+	appstream := config.GetConfig().AppStream
+	summary := appstream.Summary
+	if summary == "" {
+		summary = data["description"]
+	}
+	if strings.ContainsAny(summary, "\n\r") {
+		log.Errorf("The appstream.summary of go/hover.yaml must be a single line, longer texts belong to appstream.description.")
+		os.Exit(1)
+	}
+	description := appstream.Description
+	if len(description) == 0 {
+		description = []string{data["description"]}
+	}
+	metadataLicense := appstream.MetadataLicense
+	if metadataLicense == "" {
+		metadataLicense = "CC0-1.0"
+	}
+
+	content := []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<component type="desktop-application">`,
+		`  <id>` + fileutils.XMLEscape(data["appstreamID"]) + `</id>`,
+		`  <metadata_license>` + fileutils.XMLEscape(metadataLicense) + `</metadata_license>`,
+	}
+	if data["license"] != "NOASSERTION" {
+		content = append(content, `  <project_license>`+fileutils.XMLEscape(data["license"])+`</project_license>`)
+	}
+	content = append(content,
+		`  <name>`+fileutils.XMLEscape(data["applicationName"])+`</name>`,
+		`  <summary>`+fileutils.XMLEscape(summary)+`</summary>`,
+		`  <developer id="`+fileutils.XMLEscape(data["organizationName"])+`">`,
+		`    <name>`+fileutils.XMLEscape(data["author"])+`</name>`,
+		`  </developer>`,
+		`  <description>`,
+	)
+	content = append(content, appstreamParagraphs(description, "    ")...)
+	content = append(content,
+		`  </description>`,
+		`  <launchable type="desktop-id">`+fileutils.XMLEscape(desktopID)+`</launchable>`,
+	)
+	if data["homepage"] != "" {
+		content = append(content, `  <url type="homepage">`+fileutils.XMLEscape(data["homepage"])+`</url>`)
+	}
+
+	if len(appstream.Screenshots) > 0 {
+		content = append(content, `  <screenshots>`)
+		for i, screenshot := range appstream.Screenshots {
+			if !strings.HasPrefix(screenshot.Image, "https://") {
+				log.Errorf("The image %q of appstream.screenshots in go/hover.yaml must be a https URL, the software centers download the screenshots.", screenshot.Image)
+				os.Exit(1)
+			}
+			if i == 0 {
+				content = append(content, `    <screenshot type="default">`)
+			} else {
+				content = append(content, `    <screenshot>`)
+			}
+			content = append(content, `      <image>`+fileutils.XMLEscape(screenshot.Image)+`</image>`)
+			if screenshot.Caption != "" {
+				content = append(content, `      <caption>`+fileutils.XMLEscape(screenshot.Caption)+`</caption>`)
+			}
+			content = append(content, `    </screenshot>`)
+		}
+		content = append(content, `  </screenshots>`)
+	}
+
+	content = append(content, `  <releases>`)
+	for _, release := range appstreamReleases(appstream.Releases, data) {
+		if len(release.Description) == 0 {
+			content = append(content, `    <release version="`+fileutils.XMLEscape(release.Version)+`" date="`+release.Date+`"/>`)
+			continue
+		}
+		content = append(content,
+			`    <release version="`+fileutils.XMLEscape(release.Version)+`" date="`+release.Date+`">`,
+			`      <description>`,
+		)
+		content = append(content, appstreamParagraphs(release.Description, "        ")...)
+		content = append(content, `      </description>`, `    </release>`)
+	}
+	content = append(content, `  </releases>`)
+
+	if len(appstream.ContentRating) == 0 {
+		content = append(content, `  <content_rating type="oars-1.1"/>`)
+	} else {
+		content = append(content, `  <content_rating type="oars-1.1">`)
+		attributes := make([]string, 0, len(appstream.ContentRating))
+		for attribute := range appstream.ContentRating {
+			attributes = append(attributes, attribute)
+		}
+		sort.Strings(attributes)
+		for _, attribute := range attributes {
+			value := appstream.ContentRating[attribute]
+			switch {
+			case !oarsAttributePattern.MatchString(attribute):
+				log.Errorf("Invalid appstream.content-rating attribute %q in go/hover.yaml, use the OARS 1.1 attributes, e.g. violence-cartoon.", attribute)
+				os.Exit(1)
+			case value != "none" && value != "mild" && value != "moderate" && value != "intense":
+				log.Errorf("Invalid appstream.content-rating value %q of %s in go/hover.yaml, use none, mild, moderate or intense.", value, attribute)
+				os.Exit(1)
+			}
+			content = append(content, `    <content_attribute id="`+attribute+`">`+value+`</content_attribute>`)
+		}
+		content = append(content, `  </content_rating>`)
+	}
+	content = append(content, `</component>`)
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		log.Errorf("Failed to create %s: %v", dir, err)
+		os.Exit(1)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, data["appstreamID"]+".metainfo.xml"), []byte(strings.Join(content, "\n")+"\n"), 0644)
+	if err != nil {
+		log.Errorf("Could not write the AppStream metainfo: %v", err)
+		os.Exit(1)
+	}
+}
+
+// appstreamParagraphs returns the paragraphs of a description of the
+// metainfo.
+func appstreamParagraphs(paragraphs []string, indent string) []string {
+	var content []string
+	for _, paragraph := range paragraphs {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph != "" {
+			content = append(content, indent+`<p>`+fileutils.XMLEscape(paragraph)+`</p>`)
+		}
+	}
+	return content
+}
+
+// appstreamReleases returns the releases of the metainfo, newest first. The
+// version being packaged is added, dated today, when appstream.releases of
+// go/hover.yaml doesn't list it.
+func appstreamReleases(releases []config.AppStreamReleaseConfig, data map[string]string) []config.AppStreamReleaseConfig {
+	var listed bool
+	for _, release := range releases {
+		if _, err := time.Parse("2006-01-02", release.Date); err != nil {
+			log.Errorf("The date %q of the release %s in appstream.releases of go/hover.yaml must be a YYYY-MM-DD date.", release.Date, release.Version)
+			os.Exit(1)
+		}
+		if release.Version == data["version"] {
+			listed = true
+		}
+	}
+	if listed {
+		return releases
+	}
+	current := config.AppStreamReleaseConfig{
+		Version: data["version"],
+		Date:    executeStringTemplate("appstream release date", `{{date "2006-01-02"}}`, data),
+	}
+	return append([]config.AppStreamReleaseConfig{current}, releases...)
+}
+
+// checkAppStreamInstalled warns when the template of a packaging format
+// doesn't install the AppStream metainfo, as it predates it.
+func checkAppStreamInstalled(packagingFormat, path, packageName string) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Errorf("Failed to read the %s template output: %v", packagingFormat, err)
+		os.Exit(1)
+	}
+	if !strings.Contains(string(content), appstreamID(packageName)+".metainfo.xml") {
+		log.Warnf("The %s package doesn't install the AppStream metainfo, the software centers won't list the app. Run `%s` to add it to the template.", packagingFormat, log.Au().Magenta("hover upgrade-packaging "+packagingFormat))
+	}
+}
@@ -0,0 +1,44 @@
+package packaging
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+var (
+	debArchitecture      = regexp.MustCompile(`(?m)^Architecture: *(\S*)`)
+	pkgbuildArchitecture = regexp.MustCompile(`(?m)^arch=\(["']?([^"')]*)`)
+	rpmBuildArchitecture = regexp.MustCompile(`\$RPM_BUILD_DIR/\S*\.(\w+)/\*`)
+)
+
+// assertTemplateArch returns generateBuildFiles checking the architecture of
+// a file rendered from the templates of a packaging format. The templates of
+// the projects initialized before hover built for several architectures
+// hardcode amd64. The file may use {{.packageName}}.
+func assertTemplateArch(packagingFormat, fileTemplate string, pattern *regexp.Regexp, gnuArch bool) func(packageName, tmpPath string) {
+	return func(packageName, tmpPath string) {
+		file := executeStringTemplate(packagingFormat+" architecture file", fileTemplate, map[string]string{"packageName": packageName})
+		content, err := ioutil.ReadFile(filepath.Join(tmpPath, file))
+		if err != nil {
+			log.Errorf("Failed to read %s: %v", file, err)
+			os.Exit(1)
+		}
+		match := pattern.FindSubmatch(content)
+		if match == nil {
+			return
+		}
+		arch := build.TargetArch()
+		if gnuArch {
+			arch = build.TargetGnuArch()
+		}
+		if string(match[1]) != arch {
+			log.Errorf("%s of %s is made for %s, not %s. Run `%s` to package for the target architecture.", file, packagingFormat, match[1], arch, log.Au().Magenta("hover upgrade-packaging "+packagingFormat))
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package packaging
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// hoverBuild identifies the build of hover in the hash of the packaging
+// inputs, the packaging tasks may package the same inputs differently in
+// another build of hover.
+var hoverBuild struct {
+	once sync.Once
+	id   string
+}
+
+// hoverBuildID returns the module version of hover, or the sha256 of the hover
+// executable for the development builds, which have no version.
+func hoverBuildID() string {
+	hoverBuild.once.Do(func() {
+		if buildInfo, ok := debug.ReadBuildInfo(); ok && buildInfo.Main.Version != "" && buildInfo.Main.Version != "(devel)" {
+			hoverBuild.id = buildInfo.Main.Version
+			return
+		}
+		executable, err := os.Executable()
+		if err == nil {
+			hoverBuild.id, err = fileutils.SHA256File(executable)
+		}
+		if err != nil {
+			log.Errorf("Failed to identify the hover executable for the packaging cache: %v", err)
+			os.Exit(1)
+		}
+	})
+	return hoverBuild.id
+}
+
+// NoCache disables the packaging cache, the packaging tasks always run.
+var NoCache bool
+
+// NoVersionInFilename leaves the version out of the artifact names in the
+// output directory, for all the packaging formats of the build.
+var NoVersionInFilename bool
+
+// packagingCachePath returns the directory where the artifacts of a packaging
+// format are cached, by hash of the inputs of the packaging task.
+func packagingCachePath(packagingFormat string) string {
+	return filepath.Join(build.BuildPath, "build", "packaging-cache", packagingFormat)
+}
+
+// inputsHash hashes everything the artifact of a packaging task is made from:
+// the files staged in the temporary directory, which are the build output,
+// the outputs of the tasks it depends on and the rendered templates and
+// generated files, the rendered packaging script, the template data and the
+// build of hover, which packages them. The configuration of go/hover.yaml needs no hashing of its own, it's rendered
+// in the staged files. The templates formatting the current time change the
+// hash, unless SOURCE_DATE_EPOCH is set.
+func (t *packagingTask) inputsHash(tmpPath string, templateData map[string]string) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "hover %s\n", hoverBuildID())
+	fmt.Fprintf(h, "script %q\n", executeStringTemplate(t.packagingFormatName+" packaging script", t.packagingScriptTemplate, templateData))
+	fmt.Fprintf(h, "executable files %q\n", t.executableFiles)
+	if t.splitPackages != nil {
+		splitPackages := config.GetConfig().SplitPackages
+		fmt.Fprintf(h, "split packages %t %q\n", splitPackages.DebugSymbols, splitPackages.Data)
+	}
+
+	keys := make([]string, 0, len(templateData))
+	for key := range templateData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(h, "data %s=%q\n", key, templateData[key])
+	}
+
+	err := hashDir(h, "staged files", tmpPath)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashDir hashes the paths, modes and contents of the files in a directory.
+// filepath.Walk visits the files in lexical order.
+func hashDir(h hash.Hash, name, dir string) error {
+	fmt.Fprintf(h, "dir %s\n", name)
+	return filepath.Walk(fileutils.LongPath(dir), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(fileutils.LongPath(dir), path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%q %s\n", filepath.ToSlash(relativePath), info.Mode())
+		switch mode := info.Mode(); {
+		case mode&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "-> %q\n", link)
+		case mode.IsRegular():
+			return hashFile(h, relativePath, path)
+		}
+		return nil
+	})
+}
+
+func hashFile(h hash.Hash, name, path string) error {
+	file, err := os.Open(fileutils.LongPath(path))
+	if err != nil {
+		return errors.Wrapf(err, "failed to hash %s", name)
+	}
+	defer file.Close()
+	_, err = io.Copy(h, file)
+	if err != nil {
+		return errors.Wrapf(err, "failed to hash %s", name)
+	}
+	fmt.Fprintf(h, "\n")
+	return nil
+}
+
+// restoreCachedArtifact copies the artifacts cached for the hash to the output
+// directory of the packaging format. It returns false when there is none.
+func (t *packagingTask) restoreCachedArtifact(inputsHash, outputFileName string) bool {
+	cachedFilePath := fileutils.LongPath(filepath.Join(packagingCachePath(t.packagingFormatName), inputsHash, outputFileName))
+	if _, err := os.Stat(cachedFilePath); err != nil {
+		return false
+	}
+	outputDirectoryPath := build.OutputDirectoryPath(t.packagingFormatName)
+	err := os.RemoveAll(outputDirectoryPath)
+	if err != nil {
+		log.Errorf("Failed to clean output directory %s: %v", outputDirectoryPath, err)
+		os.Exit(1)
+	}
+	// the companion packages of split-packages are cached with the package
+	artifacts, err := ioutil.ReadDir(filepath.Dir(cachedFilePath))
+	if err != nil {
+		log.Errorf("Failed to list the cached artifacts of %s: %v", t.packagingFormatName, err)
+		os.Exit(1)
+	}
+	for _, artifact := range artifacts {
+		copyArtifact(filepath.Join(filepath.Dir(cachedFilePath), artifact.Name()), filepath.Join(build.OutputDirectoryPath(t.packagingFormatName), artifact.Name()))
+	}
+	// hover cache gc removes the entries by time of last use
+	now := time.Now()
+	os.Chtimes(filepath.Dir(cachedFilePath), now, now)
+	return true
+}
+
+// cacheArtifact copies artifacts of the output directory of the packaging
+// format to the cache. The artifacts aren't linked, they may be signed in
+// place.
+func (t *packagingTask) cacheArtifact(inputsHash string, outputFileNames ...string) {
+	cacheDirectoryPath := filepath.Join(packagingCachePath(t.packagingFormatName), inputsHash)
+	err := os.MkdirAll(fileutils.LongPath(cacheDirectoryPath), 0775)
+	if err != nil {
+		log.Errorf("Failed to create packaging cache directory %s: %v", cacheDirectoryPath, err)
+		os.Exit(1)
+	}
+	for _, outputFileName := range outputFileNames {
+		copyArtifact(filepath.Join(build.OutputDirectoryPath(t.packagingFormatName), outputFileName), filepath.Join(cacheDirectoryPath, outputFileName))
+	}
+}
+
+// copyArtifact copies an artifact, which is a directory for darwin-bundle.
+func copyArtifact(src, dst string) {
+	if fileutils.IsDirectory(src) {
+		fileutils.CopyDir(src, dst)
+	} else {
+		fileutils.CopyFile(src, dst)
+	}
+}
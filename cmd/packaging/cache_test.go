@@ -0,0 +1,68 @@
+package packaging
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInputsHash(t *testing.T) {
+	tmpPath, err := ioutil.TempDir("", "hover-packaging-cache-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpPath)
+	err = ioutil.WriteFile(filepath.Join(tmpPath, "app"), []byte("app"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hoverBuild.once.Do(func() {})
+	hoverBuild.id = "v0.0.1"
+	task := &packagingTask{
+		packagingFormatName:     "linux-test",
+		packagingScriptTemplate: "package {{.projectName}}",
+	}
+	data := map[string]string{"projectName": "app"}
+	inputsHash := func() string {
+		hash, err := task.inputsHash(tmpPath, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+	want := inputsHash()
+	if got := inputsHash(); got != want {
+		t.Errorf("inputsHash isn't stable: %s, then %s", want, got)
+	}
+
+	tests := []struct {
+		name   string
+		change func()
+	}{
+		{"hover build", func() { hoverBuild.id = "v0.0.2" }},
+		{"packaging script", func() { task.packagingScriptTemplate = "package {{.projectName}} again" }},
+		{"template data", func() { data["projectName"] = "other" }},
+		{"staged file", func() {
+			err := ioutil.WriteFile(filepath.Join(tmpPath, "app"), []byte("changed"), 0755)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}},
+		{"staged file mode", func() {
+			err := os.Chmod(filepath.Join(tmpPath, "app"), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}},
+	}
+	for _, test := range tests {
+		test.change()
+		got := inputsHash()
+		if got == want {
+			t.Errorf("%s: the hash didn't change", test.name)
+		}
+		want = got
+	}
+}
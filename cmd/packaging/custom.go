@@ -0,0 +1,150 @@
+package packaging
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+)
+
+// CustomFormatFile is the file defining a custom packaging format, in its
+// directory of go/packaging or of the global formats directory.
+const CustomFormatFile = "hover-format.yaml"
+
+// customFormatNamePattern matches the names of the custom packaging formats,
+// OS-TYPE like the formats of hover.
+var customFormatNamePattern = regexp.MustCompile(`^(linux|darwin|windows|freebsd)-[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// customFormatConfig is the content of the hover-format.yaml of a custom
+// packaging format. The other files of its directory are templates, executed
+// in the temporary directory the packaging script runs in.
+type customFormatConfig struct {
+	Description          string   `yaml:"description"`            // What the app is packaged as, e.g. "a squashfs image"
+	PackagingScript      string   `yaml:"packaging-script"`       // Template of the command packaging the app, it makes {{.packageName}}-{{.version}}.<output-file-extension>
+	OutputFileExtension  string   `yaml:"output-file-extension"`  // File extension of the packaged app, e.g. squashfs
+	BuildOutputDirectory string   `yaml:"build-output-directory"` // Path to copy the build output of the app to, defaults to build
+	ExecutableFiles      []string `yaml:"executable-files"`       // Files that should be executable
+	Downloads            bool     `yaml:"downloads"`              // Whether the packaging script downloads files, it's retried when it fails on a network error
+}
+
+// CustomFormat is a packaging format defined by a hover-format.yaml.
+type CustomFormat struct {
+	Name        string // Name of the packaging format: OS-TYPE
+	Description string // What the app is packaged as, e.g. "a squashfs image"
+	Directory   string // Directory of the hover-format.yaml
+	Task        Task
+}
+
+// GlobalFormatsPath returns the directory of the custom packaging formats
+// shared by the projects of the user: HOVER_FORMATS_PATH, by default
+// hover/formats in the user configuration directory (e.g. ~/.config).
+func GlobalFormatsPath() string {
+	if path := os.Getenv("HOVER_FORMATS_PATH"); path != "" {
+		return path
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "hover", "formats")
+}
+
+// CustomFormats returns the custom packaging formats, sorted by name: the
+// directories of go/packaging and of the global formats directory with a
+// hover-format.yaml. The directory of go/packaging takes precedence, it's
+// the copy `hover init-packaging` made of a global format. The invalid
+// definitions are returned as problems, so they don't break the other
+// formats.
+func CustomFormats() ([]CustomFormat, []error) {
+	formats := make(map[string]CustomFormat)
+	var problems []error
+	for _, dir := range []string{GlobalFormatsPath(), packagingPath} {
+		if dir == "" {
+			continue
+		}
+		paths, _ := filepath.Glob(filepath.Join(dir, "*", CustomFormatFile))
+		for _, path := range paths {
+			format, err := readCustomFormat(filepath.Dir(path))
+			if err != nil {
+				problems = append(problems, err)
+				continue
+			}
+			formats[format.Name] = format
+		}
+	}
+	var names []string
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sorted := make([]CustomFormat, 0, len(names))
+	for _, name := range names {
+		sorted = append(sorted, formats[name])
+	}
+	return sorted, problems
+}
+
+// readCustomFormat reads the hover-format.yaml of a custom packaging format,
+// named after its directory.
+func readCustomFormat(dir string) (CustomFormat, error) {
+	name := filepath.Base(dir)
+	path := filepath.Join(dir, CustomFormatFile)
+	if !customFormatNamePattern.MatchString(name) {
+		return CustomFormat{}, errors.Errorf("the directory of %s must be named OS-TYPE, the OS being linux, darwin, windows or freebsd, e.g. linux-squashfs", path)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return CustomFormat{}, errors.Wrapf(err, "failed to read %s", path)
+	}
+	var formatConfig customFormatConfig
+	err = yaml.UnmarshalStrict(content, &formatConfig)
+	if err != nil {
+		return CustomFormat{}, errors.Wrapf(err, "failed to decode %s", path)
+	}
+	formatConfig.OutputFileExtension = strings.TrimPrefix(formatConfig.OutputFileExtension, ".")
+	switch {
+	case strings.TrimSpace(formatConfig.PackagingScript) == "":
+		return CustomFormat{}, errors.Errorf("%s has no packaging-script", path)
+	case formatConfig.OutputFileExtension == "":
+		return CustomFormat{}, errors.Errorf("%s has no output-file-extension", path)
+	case strings.ContainsAny(formatConfig.Description, "\n\r"):
+		return CustomFormat{}, errors.Errorf("the description of %s must be a single line", path)
+	}
+	if formatConfig.Description == "" {
+		formatConfig.Description = "a " + formatConfig.OutputFileExtension + " package"
+	}
+	if formatConfig.BuildOutputDirectory == "" {
+		formatConfig.BuildOutputDirectory = "build"
+	}
+	return CustomFormat{
+		Name:        name,
+		Description: formatConfig.Description,
+		Directory:   dir,
+		Task: &packagingTask{
+			packagingFormatName:       name,
+			customFormatDirectory:     dir,
+			executableFiles:           formatConfig.ExecutableFiles,
+			buildOutputDirectory:      formatConfig.BuildOutputDirectory,
+			packagingScriptTemplate:   formatConfig.PackagingScript,
+			packagingScriptDownloads:  formatConfig.Downloads,
+			outputFileExtension:       formatConfig.OutputFileExtension,
+			outputFileContainsVersion: true,
+			outputFileContainsArch:    true,
+		},
+	}, nil
+}
+
+// initCustomFormat copies the directory of a custom packaging format of the
+// global formats directory to go/packaging, where it's built from and can be
+// modified and added to git.
+func (t *packagingTask) initCustomFormat() {
+	createPackagingFormatDirectory(t.packagingFormatName)
+	fileutils.CopyDir(t.customFormatDirectory, packagingFormatPath(t.packagingFormatName))
+}
@@ -0,0 +1,86 @@
+package packaging
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+)
+
+// DarwinAppStoreTask packaging for darwin as the signed pkg of the sandboxed
+// bundle, submitted to the Mac App Store
+var DarwinAppStoreTask = &packagingTask{
+	packagingFormatName: "darwin-appstore",
+	dependsOn: map[*packagingTask]string{
+		DarwinBundleTask: "bundle",
+	},
+	templateFiles: map[string]string{
+		"darwin-appstore/entitlements.plist.tmpl": "entitlements.plist.tmpl",
+	},
+	generateBuildFiles: darwinAppStoreBundle,
+	// the libraries are signed before the app sealing them, the app is
+	// installed to /Applications
+	packagingScriptTemplate:       "find {{shellquote .applicationName \".app/Contents\"}} -name '*.dylib' -exec codesign --force --sign {{shellquote .appstoreApplicationIdentity}} {} + && find {{shellquote .applicationName \".app/Contents\"}} -name '*.framework' -prune -exec codesign --force --sign {{shellquote .appstoreApplicationIdentity}} {} + && codesign --force --sign {{shellquote .appstoreApplicationIdentity}} --entitlements entitlements.plist {{shellquote .applicationName \".app\"}} && productbuild --component {{shellquote .applicationName \".app\"}} /Applications --sign {{shellquote .appstoreInstallerIdentity}} {{shellquote .applicationName \" \" .version \".pkg\"}}",
+	outputFileExtension:           "pkg",
+	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
+	outputFileUsesApplicationName: true,
+	identity: []identityProperty{
+		{"entitlements.plist", "com.apple.application-identifier", IdentityBundleIdentifier, regexp.MustCompile(`<key>com.apple.application-identifier</key>\s*<string>[^.<]*\.(.*?)</string>`), true},
+	},
+}
+
+// darwinAppStoreBundle copies the bundle of darwin-bundle to the
+// <application name>.app submitted to the App Store, with its category and
+// provisioning profile. The bundle is copied, signing changes the files.
+func darwinAppStoreBundle(packageName, tmpPath string) {
+	appStore := config.GetConfig().AppStore
+	if appStore.ApplicationIdentity == "" || appStore.InstallerIdentity == "" {
+		log.Errorf("Set the application-identity and installer-identity of appstore in go/hover.yaml to the names of the signing identities of the keychain.")
+		os.Exit(1)
+	}
+	if config.GetConfig().GetAppStoreTeamID() == "" {
+		log.Errorf("Set the team-id of appstore in go/hover.yaml, it isn't in the application-identity.")
+		os.Exit(1)
+	}
+	if appStore.ProvisioningProfile == "" || !fileutils.IsFileExists(appStore.ProvisioningProfile) {
+		log.Errorf("The provisioning-profile of appstore in go/hover.yaml must be the Mac App Store provisioning profile of the app, %s doesn't exist.", appStore.ProvisioningProfile)
+		os.Exit(1)
+	}
+
+	bundles, _ := filepath.Glob(filepath.Join(tmpPath, "bundle", "*.app"))
+	if len(bundles) != 1 {
+		log.Errorf("Expected the bundle of darwin-bundle in the darwin-appstore bundle directory, found %d bundles", len(bundles))
+		os.Exit(1)
+	}
+	bundlePath := filepath.Join(tmpPath, config.GetConfig().GetApplicationName(pubspec.GetPubSpec().Name)+".app")
+	fileutils.CopyDir(bundles[0], bundlePath)
+	fileutils.CopyFile(appStore.ProvisioningProfile, filepath.Join(bundlePath, "Contents", "embedded.provisionprofile"))
+
+	infoPlistPath := filepath.Join(bundlePath, "Contents", "Info.plist")
+	infoPlist, err := ioutil.ReadFile(infoPlistPath)
+	if err != nil {
+		log.Errorf("Failed to read the Info.plist of the bundle: %v", err)
+		os.Exit(1)
+	}
+	if !strings.Contains(string(infoPlist), "<key>LSApplicationCategoryType</key>") {
+		end := strings.LastIndex(string(infoPlist), "</dict>")
+		if end == -1 {
+			log.Errorf("Failed to find the dict of the Info.plist of the bundle")
+			os.Exit(1)
+		}
+		category := "    <key>LSApplicationCategoryType</key>\n        <string>" + fileutils.XMLEscape(config.GetConfig().GetAppStoreCategory()) + "</string>\n    "
+		infoPlist = []byte(string(infoPlist[:end]) + category + string(infoPlist[end:]))
+		err = ioutil.WriteFile(infoPlistPath, infoPlist, 0644)
+		if err != nil {
+			log.Errorf("Failed to write the Info.plist of the bundle: %v", err)
+			os.Exit(1)
+		}
+	}
+}
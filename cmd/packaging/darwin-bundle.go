@@ -1,5 +1,12 @@
 package packaging
 
+import (
+	"path/filepath"
+	"regexp"
+
+	"github.com/go-flutter-desktop/hover/internal/config"
+)
+
 // DarwinBundleTask packaging for darwin as bundle
 var DarwinBundleTask = &packagingTask{
 	packagingFormatName: "darwin-bundle",
@@ -8,8 +15,26 @@ var DarwinBundleTask = &packagingTask{
 	},
 	executableFiles:               []string{},
 	buildOutputDirectory:          "{{.applicationName}} {{.version}}.app/Contents/MacOS",
-	packagingScriptTemplate:       "mkdir -p \"{{.applicationName}} {{.version}}.app/Contents/Resources\" && png2icns \"{{.applicationName}} {{.version}}.app/Contents/Resources/icon.icns\" \"{{.applicationName}} {{.version}}.app/Contents/MacOS/assets/icon.png\"",
+	packagingScriptTemplate:       "mkdir -p {{shellquote .applicationName \" \" .version \".app/Contents/Resources\"}} && png2icns {{shellquote .applicationName \" \" .version \".app/Contents/Resources/icon.icns\"}} {{shellquote .applicationName \" \" .version \".app/Contents/MacOS/assets/icon.png\"}}",
 	outputFileExtension:           "app",
 	outputFileContainsVersion:     true,
 	outputFileUsesApplicationName: true,
+	generateBuildFiles: func(packageName, tmpPath string) {
+		bundles, _ := filepath.Glob(filepath.Join(tmpPath, "*.app"))
+		for _, bundle := range bundles {
+			if len(config.GetConfig().Preferences) > 0 {
+				writeDefaultsPlist(filepath.Join(bundle, "Contents", "Resources", "Defaults.plist"))
+			}
+			if len(config.GetConfig().FileAssociations) > 0 {
+				checkDarwinDocumentTypes(filepath.Join(bundle, "Contents", "Info.plist"))
+				writeDarwinDocumentIcons(bundle)
+			}
+		}
+	},
+	identity: []identityProperty{
+		{"{{.applicationName}} {{.version}}.app/Contents/Info.plist", "CFBundleName", IdentityApplicationName, regexp.MustCompile(`<key>CFBundleName</key>\s*<string>(.*?)</string>`), true},
+		{"{{.applicationName}} {{.version}}.app/Contents/Info.plist", "CFBundleExecutable", IdentityExecutableName, regexp.MustCompile(`<key>CFBundleExecutable</key>\s*<string>(.*?)</string>`), true},
+		{"{{.applicationName}} {{.version}}.app/Contents/Info.plist", "CFBundleIdentifier", IdentityBundleIdentifier, regexp.MustCompile(`<key>CFBundleIdentifier</key>\s*<string>(.*?)</string>`), true},
+		{"{{.applicationName}} {{.version}}.app/Contents/Info.plist", "CFBundleURLSchemes", IdentityURLSchemes, regexp.MustCompile(`<key>CFBundleURLSchemes</key>\s*<array>((?:\s*<string>[^<]*</string>)*)`), true},
+	},
 }
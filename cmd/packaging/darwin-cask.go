@@ -0,0 +1,97 @@
+package packaging
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+)
+
+// DarwinCaskTask packaging for darwin as the Homebrew cask of the dmg
+var DarwinCaskTask = &packagingTask{
+	packagingFormatName: "darwin-cask",
+	dependsOn: map[*packagingTask]string{
+		DarwinDmgTask: "dmg",
+	},
+	templateFiles: map[string]string{
+		"darwin-cask/cask.rb.tmpl": "{{.packageName}}.rb.tmpl",
+	},
+	generateBuildFiles: func(packageName, tmpPath string) {
+		dmgPaths, _ := filepath.Glob(filepath.Join(tmpPath, "dmg", "*.dmg"))
+		if len(dmgPaths) != 1 {
+			log.Errorf("Expected the dmg of darwin-dmg in the cask dmg directory, found %d files", len(dmgPaths))
+			os.Exit(1)
+		}
+		checksum, err := fileutils.SHA256File(dmgPaths[0])
+		if err != nil {
+			log.Errorf("Failed to compute the checksum of %s: %v", filepath.Base(dmgPaths[0]), err)
+			os.Exit(1)
+		}
+		err = fillHomebrewCask(filepath.Join(tmpPath, packageName+".rb"), checksum)
+		if err != nil {
+			log.Errorf("%v", err)
+			os.Exit(1)
+		}
+	},
+	// the cask is complete once its sha256 is filled in
+	packagingScriptTemplate:       "",
+	outputFileExtension:           "rb",
+	outputFileContainsVersion:     false,
+	outputFileContainsArch:        false,
+	outputFileUsesApplicationName: false,
+	identity: []identityProperty{
+		{"{{.packageName}}.rb", "name", IdentityApplicationName, regexp.MustCompile(`(?m)^\s*name "(.*?)"`), false},
+	},
+}
+
+var homebrewCaskSha256 = regexp.MustCompile(`(?m)^([ \t]*)sha256 .*$`)
+
+// fillHomebrewCask fills in the checksum of the dmg in a cask, and checks
+// that its url is set.
+func fillHomebrewCask(caskPath, checksum string) error {
+	content, err := ioutil.ReadFile(caskPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", filepath.Base(caskPath))
+	}
+	if !homebrewCaskSha256.Match(content) {
+		return errors.Errorf("the cask %s has no sha256 to fill in", filepath.Base(caskPath))
+	}
+	if regexp.MustCompile(`(?m)^\s*url ""`).Match(content) {
+		return errors.Errorf("the url of the cask %s is empty, set the url of homebrew in go/hover.yaml to the URL the dmg is published at", filepath.Base(caskPath))
+	}
+	content = homebrewCaskSha256.ReplaceAll(content, []byte(`${1}sha256 "`+checksum+`"`))
+	return ioutil.WriteFile(caskPath, content, 0644)
+}
+
+// HomebrewCaskToken returns the token of the cask, the name of its file in
+// the Casks directory of a tap.
+func HomebrewCaskToken() string {
+	projectName := pubspec.GetPubSpec().Name
+	data := DarwinCaskTask.getTemplateData(projectName, pubspec.GetPubSpec().GetVersion())
+	return strings.ToLower(strings.ReplaceAll(data["packageName"], "_", "-"))
+}
+
+// WriteHomebrewCask writes the cask of a published dmg to the Casks
+// directory of a tap. An empty url keeps the url of homebrew in
+// go/hover.yaml.
+func WriteHomebrewCask(dir, url, sha256 string) error {
+	projectName := pubspec.GetPubSpec().Name
+	data := DarwinCaskTask.getTemplateData(projectName, pubspec.GetPubSpec().GetVersion())
+	if url != "" {
+		data["homebrewUrl"] = url
+	}
+	caskPath := filepath.Join(dir, "Casks", HomebrewCaskToken()+".rb")
+	err := os.MkdirAll(filepath.Dir(caskPath), 0755)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the Casks directory")
+	}
+	fileutils.ExecuteTemplateFromFile(filepath.Join(packagingFormatPath(DarwinCaskTask.packagingFormatName), "{{.packageName}}.rb.tmpl"), caskPath, data)
+	return fillHomebrewCask(caskPath, sha256)
+}
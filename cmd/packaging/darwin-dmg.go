@@ -6,9 +6,10 @@ var DarwinDmgTask = &packagingTask{
 	dependsOn: map[*packagingTask]string{
 		DarwinBundleTask: "dmgdir",
 	},
-	packagingScriptTemplate:       "ln -sf /Applications dmgdir/Applications && genisoimage -V {{.packageName}} -D -R -apple -no-pad -o \"{{.applicationName}} {{.version}}.dmg\" dmgdir",
+	packagingScriptTemplate:       "ln -sf /Applications dmgdir/Applications && genisoimage -V {{shellquote .packageName}} -D -R -apple -no-pad -o {{shellquote .applicationName \" \" .version \".dmg\"}} dmgdir",
 	outputFileExtension:           "dmg",
 	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
 	outputFileUsesApplicationName: true,
 	skipAssertInitialized:         true,
 }
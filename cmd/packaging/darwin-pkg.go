@@ -1,5 +1,23 @@
 package packaging
 
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-flutter-desktop/hover/internal/androidmanifest"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/explain"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+)
+
 // DarwinPkgTask packaging for darwin as pkg
 var DarwinPkgTask = &packagingTask{
 	packagingFormatName: "darwin-pkg",
@@ -10,8 +28,226 @@ var DarwinPkgTask = &packagingTask{
 		"darwin-pkg/PackageInfo.tmpl":  "flat/base.pkg/PackageInfo.tmpl",
 		"darwin-pkg/Distribution.tmpl": "flat/Distribution.tmpl",
 	},
-	packagingScriptTemplate:       "(cd flat/root && find . | cpio -o --format odc --owner 0:80 | gzip -c ) > flat/base.pkg/Payload && mkbom -u 0 -g 80 flat/root flat/base.pkg/Bom && (cd flat && xar --compression none -cf \"../{{.applicationName}} {{.version}}.pkg\" * )",
+	generateBuildFiles:            darwinPkgScripts,
+	packagingScriptTemplate:       "(cd flat/root && find . | cpio -o --format odc --owner 0:80 | gzip -c ) > flat/base.pkg/Payload && mkbom -u 0 -g 80 flat/root flat/base.pkg/Bom && if [ -d scripts ]; then (cd scripts && find . | cpio -o --format odc --owner 0:80 | gzip -c ) > flat/base.pkg/Scripts; fi && (cd flat && xar --compression none -cf {{shellquote \"../\" .applicationName \" \" .version \".pkg\"}} * )",
 	outputFileExtension:           "pkg",
 	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
 	outputFileUsesApplicationName: true,
+	identity: []identityProperty{
+		{"flat/Distribution", "title", IdentityApplicationName, regexp.MustCompile(`<title>(.*?)</title>`), true},
+		{"flat/base.pkg/PackageInfo", "CFBundleIdentifier", IdentityBundleIdentifier, regexp.MustCompile(`CFBundleIdentifier="(.*?)"`), true},
+	},
+}
+
+// darwinPkgScripts writes the launchd jobs and the uninstall script of
+// go/hover.yaml to the payload.
+func darwinPkgScripts(packageName, tmpPath string) {
+	darwinPkgLaunchdJobs(packageName, tmpPath)
+	darwinPkgUninstallScript(packageName, tmpPath)
+}
+
+// darwinPkgBundleName returns the name of the app bundle of the payload,
+// which contains the version.
+func darwinPkgBundleName(tmpPath string) string {
+	applicationsPath := filepath.Join(tmpPath, "flat", "root", "Applications")
+	bundles, err := ioutil.ReadDir(applicationsPath)
+	if err != nil {
+		log.Errorf("Failed to read %s: %v", applicationsPath, err)
+		os.Exit(1)
+	}
+	if len(bundles) != 1 || !strings.HasSuffix(bundles[0].Name(), ".app") {
+		log.Errorf("Failed to find the app bundle in %s", applicationsPath)
+		os.Exit(1)
+	}
+	return bundles[0].Name()
+}
+
+// darwinPkgLaunchdJobs writes the plists of the launchd jobs of go/hover.yaml
+// to the payload, and the postinstall script loading them.
+func darwinPkgLaunchdJobs(packageName, tmpPath string) {
+	jobs := config.GetConfig().Launchd
+	if len(jobs) == 0 {
+		return
+	}
+	packageInfo, err := ioutil.ReadFile(filepath.Join(tmpPath, "flat", "base.pkg", "PackageInfo"))
+	if err != nil {
+		log.Errorf("Failed to read PackageInfo: %v", err)
+		os.Exit(1)
+	}
+	if !bytes.Contains(packageInfo, []byte("postinstall")) {
+		log.ErrorCodef(explain.TemplateOutdated, "PackageInfo has no postinstall script, run `%s` to install the launchd jobs of go/hover.yaml.", log.Au().Magenta("hover upgrade-packaging darwin-pkg"))
+		os.Exit(1)
+	}
+	applicationsPath := filepath.Join(tmpPath, "flat", "root", "Applications")
+	bundleName := darwinPkgBundleName(tmpPath)
+
+	postinstall := []string{
+		"#!/bin/sh",
+		"# Loads the launchd jobs of go/hover.yaml",
+		`console_user="$(stat -f %Su /dev/console)"`,
+	}
+	for _, job := range jobs {
+		if job.Label == "" || strings.ContainsAny(job.Label, `/\ `) {
+			log.Errorf("Invalid launchd job label %q in go/hover.yaml", job.Label)
+			os.Exit(1)
+		}
+		program := job.Program
+		if program == "" {
+			program = config.GetConfig().GetExecutableName(pubspec.GetPubSpec().Name)
+		}
+		if strings.ContainsAny(program, `/\`) || !fileutils.IsFileExists(filepath.Join(applicationsPath, bundleName, "Contents", "MacOS", program)) {
+			log.Errorf("The program %s of the launchd job %s must be a file of the build output", program, job.Label)
+			os.Exit(1)
+		}
+		var plistDirectory string
+		switch job.Type {
+		case "", "agent":
+			plistDirectory = "/Library/LaunchAgents"
+		case "daemon":
+			plistDirectory = "/Library/LaunchDaemons"
+		default:
+			log.Errorf("Invalid type %q of the launchd job %s, it must be agent or daemon", job.Type, job.Label)
+			os.Exit(1)
+		}
+
+		plistPath := filepath.Join(tmpPath, "flat", "root", filepath.FromSlash(plistDirectory), job.Label+".plist")
+		err = os.MkdirAll(filepath.Dir(plistPath), 0755)
+		if err != nil {
+			log.Errorf("Failed to create the %s directory: %v", plistDirectory, err)
+			os.Exit(1)
+		}
+		// launchd ignores the plists writable by other users
+		err = ioutil.WriteFile(plistPath, []byte(launchdPlist(job, "/Applications/"+bundleName+"/Contents/MacOS/"+program)), 0644)
+		if err != nil {
+			log.Errorf("Failed to write the plist of the launchd job %s: %v", job.Label, err)
+			os.Exit(1)
+		}
+
+		installedPlistPath := fileutils.ShellQuote(plistDirectory + "/" + job.Label + ".plist")
+		if job.Type == "daemon" {
+			postinstall = append(postinstall,
+				"launchctl bootout system/"+fileutils.ShellQuote(job.Label)+" 2>/dev/null || true",
+				"launchctl bootstrap system "+installedPlistPath,
+			)
+		} else {
+			// the agents of the other sessions are loaded on their next login
+			postinstall = append(postinstall,
+				`if [ -n "$console_user" ] && [ "$console_user" != root ]; then`,
+				`    console_uid="$(id -u "$console_user")"`,
+				`    launchctl bootout "gui/$console_uid/"`+fileutils.ShellQuote(job.Label)+" 2>/dev/null || true",
+				`    launchctl bootstrap "gui/$console_uid" `+installedPlistPath,
+				"fi",
+			)
+		}
+	}
+	postinstall = append(postinstall, "exit 0")
+	err = os.MkdirAll(filepath.Join(tmpPath, "scripts"), 0755)
+	if err != nil {
+		log.Errorf("Failed to create the scripts directory: %v", err)
+		os.Exit(1)
+	}
+	err = ioutil.WriteFile(filepath.Join(tmpPath, "scripts", "postinstall"), []byte(strings.Join(postinstall, "\n")+"\n"), 0755)
+	if err != nil {
+		log.Errorf("Failed to write the postinstall script: %v", err)
+		os.Exit(1)
+	}
+}
+
+// darwinPkgUninstallScript writes the uninstall script of the app to the
+// payload when the uninstall survey of go/hover.yaml is opted in. The pkg
+// installs have no uninstaller, the script removes the files of the package
+// and opens the uninstall URL in the browser of the console user.
+func darwinPkgUninstallScript(packageName, tmpPath string) {
+	_, uninstallURL := config.GetConfig().GetSurveyURLs()
+	if uninstallURL == "" {
+		return
+	}
+	bundleName := darwinPkgBundleName(tmpPath)
+	supportPath := "/Library/Application Support/" + packageName
+	uninstall := []string{
+		"#!/bin/sh",
+		"# Uninstalls " + bundleName + ", run with sudo",
+		`if [ "$(id -u)" != 0 ]; then`,
+		`    echo "Run $0 with sudo" >&2`,
+		"    exit 1",
+		"fi",
+		`console_user="$(stat -f %Su /dev/console)"`,
+	}
+	for _, job := range config.GetConfig().Launchd {
+		plistPath := "/Library/LaunchAgents/" + job.Label + ".plist"
+		if job.Type == "daemon" {
+			plistPath = "/Library/LaunchDaemons/" + job.Label + ".plist"
+			uninstall = append(uninstall, "launchctl bootout system/"+fileutils.ShellQuote(job.Label)+" 2>/dev/null || true")
+		} else {
+			uninstall = append(uninstall,
+				`if [ -n "$console_user" ] && [ "$console_user" != root ]; then`,
+				`    launchctl bootout "gui/$(id -u "$console_user")/"`+fileutils.ShellQuote(job.Label)+" 2>/dev/null || true",
+				"fi",
+			)
+		}
+		uninstall = append(uninstall, "rm -f "+fileutils.ShellQuote(plistPath))
+	}
+	uninstall = append(uninstall,
+		"rm -rf "+fileutils.ShellQuote("/Applications/"+bundleName)+" "+fileutils.ShellQuote(supportPath),
+		"pkgutil --forget "+fileutils.ShellQuote(androidmanifest.AndroidOrganizationName()+".base.pkg")+" >/dev/null 2>&1 || true",
+		"# Uninstall survey, opted in with survey.opt-in in go/hover.yaml",
+		`if [ -n "$console_user" ] && [ "$console_user" != root ]; then`,
+		`    launchctl asuser "$(id -u "$console_user")" sudo -u "$console_user" open `+fileutils.ShellQuote(uninstallURL)+" || true",
+		"fi",
+		"exit 0",
+	)
+	uninstallPath := filepath.Join(tmpPath, "flat", "root", filepath.FromSlash(supportPath), "uninstall.sh")
+	err := os.MkdirAll(filepath.Dir(uninstallPath), 0755)
+	if err != nil {
+		log.Errorf("Failed to create the %s directory: %v", supportPath, err)
+		os.Exit(1)
+	}
+	err = ioutil.WriteFile(uninstallPath, []byte(strings.Join(uninstall, "\n")+"\n"), 0755)
+	if err != nil {
+		log.Errorf("Failed to write the uninstall script: %v", err)
+		os.Exit(1)
+	}
+}
+
+// launchdPlist returns the plist of a launchd job.
+func launchdPlist(job config.LaunchdJobConfig, programPath string) string {
+	plist := []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">`,
+		`<plist version="1.0">`,
+		`<dict>`,
+		`	<key>Label</key>`,
+		`	<string>` + fileutils.XMLEscape(job.Label) + `</string>`,
+		`	<key>ProgramArguments</key>`,
+		`	<array>`,
+		`		<string>` + fileutils.XMLEscape(programPath) + `</string>`,
+	}
+	for _, argument := range job.Arguments {
+		plist = append(plist, `		<string>`+fileutils.XMLEscape(argument)+`</string>`)
+	}
+	plist = append(plist, `	</array>`)
+	if job.RunAtLoad {
+		plist = append(plist, `	<key>RunAtLoad</key>`, `	<true/>`)
+	}
+	if job.KeepAlive {
+		plist = append(plist, `	<key>KeepAlive</key>`, `	<true/>`)
+	}
+	if job.StartInterval > 0 {
+		plist = append(plist, `	<key>StartInterval</key>`, fmt.Sprintf(`	<integer>%d</integer>`, job.StartInterval))
+	}
+	if len(job.Environment) > 0 {
+		names := make([]string, 0, len(job.Environment))
+		for name := range job.Environment {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		plist = append(plist, `	<key>EnvironmentVariables</key>`, `	<dict>`)
+		for _, name := range names {
+			plist = append(plist, `		<key>`+fileutils.XMLEscape(name)+`</key>`, `		<string>`+fileutils.XMLEscape(job.Environment[name])+`</string>`)
+		}
+		plist = append(plist, `	</dict>`)
+	}
+	plist = append(plist, `</dict>`, `</plist>`)
+	return strings.Join(plist, "\n") + "\n"
 }
@@ -0,0 +1,113 @@
+package packaging
+
+import (
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// desktopMainCategories are the main categories of the freedesktop menu
+// specification, the menus of the desktops place the apps by them.
+var desktopMainCategories = []string{"AudioVideo", "Audio", "Video", "Development", "Education", "Game", "Graphics", "Network", "Office", "Science", "Settings", "System", "Utility"}
+
+// desktopEntryKeys are the keys of the .desktop file set by desktop in
+// go/hover.yaml, by template data.
+var desktopEntryKeys = map[string]string{
+	"desktopGenericName": "GenericName",
+	"desktopCategories":  "Categories",
+	"desktopKeywords":    "Keywords",
+	"desktopMimeType":    "MimeType",
+	"desktopTerminal":    "Terminal",
+}
+
+// desktopEntryData returns the template data of the .desktop file of the
+// linux packages: desktopGenericName, desktopCategories, desktopKeywords,
+// desktopMimeType and desktopTerminal. The lists are formatted as in the
+// .desktop file, separated and terminated by semicolons.
+func desktopEntryData(desktop config.DesktopConfig) map[string]string {
+	if strings.ContainsAny(desktop.GenericName, "\n\r") {
+		log.Errorf("The desktop.generic-name %q in go/hover.yaml must be a single line.", desktop.GenericName)
+		os.Exit(1)
+	}
+	for _, category := range desktop.Categories {
+		if !regexp.MustCompile(`^[A-Za-z0-9-]+$`).MatchString(category) {
+			log.Errorf("Invalid desktop.categories entry %q in go/hover.yaml, use the categories of the freedesktop menu specification, e.g. Utility.", category)
+			os.Exit(1)
+		}
+	}
+	if len(desktop.Categories) > 0 && !hasMainCategory(desktop.Categories) {
+		log.Warnf("The desktop.categories of go/hover.yaml have no main category, the app may be listed under Other in the menus. Add one of %s.", strings.Join(desktopMainCategories, ", "))
+	}
+	for _, mimeType := range desktop.MimeType {
+		if !regexp.MustCompile(`^[\w.+-]+/[\w.+-]+$`).MatchString(mimeType) {
+			log.Errorf("Invalid desktop.mime-type entry %q in go/hover.yaml, e.g. text/markdown.", mimeType)
+			os.Exit(1)
+		}
+	}
+	return map[string]string{
+		"desktopGenericName": desktop.GenericName,
+		"desktopCategories":  desktopList(desktop.Categories),
+		"desktopKeywords":    desktopList(desktop.Keywords),
+		"desktopMimeType":    desktopList(desktop.MimeType),
+		"desktopTerminal":    strconv.FormatBool(desktop.Terminal),
+	}
+}
+
+func hasMainCategory(categories []string) bool {
+	for _, category := range categories {
+		for _, main := range desktopMainCategories {
+			if category == main {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// desktopList formats a list value of a .desktop file, escaping the
+// semicolons of the values.
+func desktopList(values []string) string {
+	var list strings.Builder
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		value = strings.NewReplacer(`\`, `\\`, ";", `\;`, "\n", " ", "\r", "").Replace(value)
+		list.WriteString(value + ";")
+	}
+	return list.String()
+}
+
+// checkDesktopEntry warns when the .desktop file of a packaging format
+// doesn't have the entries of desktop in go/hover.yaml, its template
+// predating them.
+func checkDesktopEntry(packagingFormat, path string, data map[string]string) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		// the file doesn't exist when it was removed from the configuration
+		return
+	}
+	if err != nil {
+		log.Errorf("Failed to read the %s .desktop file: %v", packagingFormat, err)
+		os.Exit(1)
+	}
+	for _, key := range []string{"desktopGenericName", "desktopCategories", "desktopKeywords", "desktopMimeType", "desktopTerminal"} {
+		// the values of the older templates, an empty Categories and
+		// Terminal=false, aren't checked
+		if data[key] == "" || data[key] == "false" {
+			continue
+		}
+		if !regexp.MustCompile(`(?m)^` + desktopEntryKeys[key] + `=` + regexp.QuoteMeta(data[key]) + `$`).Match(content) {
+			log.Warnf("The .desktop file of %s doesn't have the %s of go/hover.yaml, add `%s={{.%s}}` to go/packaging/linux/app.desktop.tmpl.", packagingFormat, desktopEntryKeys[key], desktopEntryKeys[key], key)
+		}
+	}
+	if data["urlSchemes"] != "" && !regexp.MustCompile(`(?m)^Exec=.* %U$`).Match(content) {
+		log.Warnf("The .desktop file of %s doesn't pass the opened URLs to the app, end the Exec of go/packaging/linux/app.desktop.tmpl with `{{if .urlSchemes}} %%U{{else if .desktopMimeType}} %%F{{end}}`.", packagingFormat)
+	}
+}
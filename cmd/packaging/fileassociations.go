@@ -0,0 +1,293 @@
+package packaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/explain"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+var (
+	fileExtensionPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_+-]*(\.[A-Za-z0-9_+-]+)*$`)
+	mimeTypePattern      = regexp.MustCompile(`^[\w.+-]+/[\w.+-]+$`)
+	urlSchemePattern     = regexp.MustCompile(`^[a-z][a-z0-9+.-]*$`)
+)
+
+// reservedURLSchemes are the URL schemes the app can't take over from the
+// browsers and the file managers.
+var reservedURLSchemes = map[string]bool{"http": true, "https": true, "file": true}
+
+// fileAssociations returns the file associations of go/hover.yaml, the
+// extensions without their leading dot.
+func fileAssociations() []config.FileAssociationConfig {
+	var associations []config.FileAssociationConfig
+	extensions := map[string]bool{}
+	for _, association := range config.GetConfig().FileAssociations {
+		association.Extension = strings.TrimPrefix(association.Extension, ".")
+		if !fileExtensionPattern.MatchString(association.Extension) {
+			log.ErrorCodef(explain.FileAssociationsInvalid, "Invalid file-associations extension %q in go/hover.yaml, e.g. md.", association.Extension)
+			os.Exit(1)
+		}
+		if extensions[strings.ToLower(association.Extension)] {
+			log.ErrorCodef(explain.FileAssociationsInvalid, "The extension %s is listed twice in file-associations of go/hover.yaml.", association.Extension)
+			os.Exit(1)
+		}
+		extensions[strings.ToLower(association.Extension)] = true
+		if !mimeTypePattern.MatchString(association.MimeType) {
+			log.ErrorCodef(explain.FileAssociationsInvalid, "Invalid mime-type %q of the file association .%s in go/hover.yaml, e.g. text/markdown.", association.MimeType, association.Extension)
+			os.Exit(1)
+		}
+		if strings.ContainsAny(association.Description, "\n\r") {
+			log.ErrorCodef(explain.FileAssociationsInvalid, "The description of the file association .%s in go/hover.yaml must be a single line.", association.Extension)
+			os.Exit(1)
+		}
+		if association.Description == "" {
+			association.Description = strings.ToUpper(association.Extension) + " file"
+		}
+		associations = append(associations, association)
+	}
+	return associations
+}
+
+// urlSchemes returns the URL schemes of go/hover.yaml opened by the app.
+func urlSchemes() []string {
+	var schemes []string
+	for _, scheme := range config.GetConfig().URLSchemes {
+		scheme = strings.TrimSuffix(scheme, "://")
+		if !urlSchemePattern.MatchString(scheme) {
+			log.ErrorCodef(explain.FileAssociationsInvalid, "Invalid url-schemes entry %q in go/hover.yaml, use lowercase letters, digits, +, . and -, starting with a letter, e.g. myapp.", scheme)
+			os.Exit(1)
+		}
+		if reservedURLSchemes[scheme] {
+			log.ErrorCodef(explain.FileAssociationsInvalid, "The url-schemes of go/hover.yaml can't take over the %s URLs.", scheme)
+			os.Exit(1)
+		}
+		schemes = append(schemes, scheme)
+	}
+	return schemes
+}
+
+// fileAssociationIcon returns the icon of a file association, scaled down to
+// 256x256 pixels.
+func fileAssociationIcon(association config.FileAssociationConfig) image.Image {
+	f, err := os.Open(association.Icon)
+	if err != nil {
+		log.ErrorCodef(explain.FileAssociationsInvalid, "Failed to open the icon of the file association .%s: %v", association.Extension, err)
+		os.Exit(1)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		log.ErrorCodef(explain.FileAssociationsInvalid, "Failed to decode the icon %s of the file association .%s, it must be a PNG: %v", association.Icon, association.Extension, err)
+		os.Exit(1)
+	}
+	if size := img.Bounds().Size(); size.X != size.Y || size.X < 256 {
+		log.ErrorCodef(explain.FileAssociationsInvalid, "The icon %s of the file association .%s must be a square PNG of at least 256x256 pixels.", association.Icon, association.Extension)
+		os.Exit(1)
+	}
+	return scaleDown(img, 256)
+}
+
+// fileAssociationsData returns the template data of the file associations:
+// mimePackage, the name of the shared-mime-info package of the linux packages,
+// mimeIcons, whether they install icons for the file types, and
+// darwinDocumentTypes, the CFBundleDocumentTypes of the darwin Info.plist.
+func fileAssociationsData(packageName string) map[string]string {
+	associations := fileAssociations()
+	data := map[string]string{
+		"mimePackage":         "",
+		"mimeIcons":           "",
+		"darwinDocumentTypes": "",
+	}
+	if len(associations) == 0 {
+		return data
+	}
+	data["mimePackage"] = appstreamID(packageName)
+	documentTypes := []string{`        <array>`}
+	for _, association := range associations {
+		documentTypes = append(documentTypes,
+			`            <dict>`,
+			`                <key>CFBundleTypeName</key>`,
+			`                <string>`+fileutils.XMLEscape(association.Description)+`</string>`,
+			`                <key>CFBundleTypeRole</key>`,
+			`                <string>Editor</string>`,
+			`                <key>CFBundleTypeExtensions</key>`,
+			`                <array>`,
+			`                    <string>`+fileutils.XMLEscape(association.Extension)+`</string>`,
+			`                </array>`,
+			`                <key>CFBundleTypeMIMETypes</key>`,
+			`                <array>`,
+			`                    <string>`+fileutils.XMLEscape(association.MimeType)+`</string>`,
+			`                </array>`,
+		)
+		if association.Icon != "" {
+			data["mimeIcons"] = "true"
+			documentTypes = append(documentTypes,
+				`                <key>CFBundleTypeIconFile</key>`,
+				`                <string>`+fileutils.XMLEscape(association.Extension)+`.icns</string>`,
+			)
+		}
+		documentTypes = append(documentTypes, `            </dict>`)
+	}
+	documentTypes = append(documentTypes, `        </array>`)
+	data["darwinDocumentTypes"] = strings.Join(documentTypes, "\n")
+	return data
+}
+
+// desktopMimeTypes returns the mime types of the .desktop file: the ones of
+// desktop.mime-type in go/hover.yaml followed by the ones of the file
+// associations and the x-scheme-handler of the URL schemes.
+func desktopMimeTypes(mimeTypes []string) []string {
+	listed := map[string]bool{}
+	for _, mimeType := range mimeTypes {
+		listed[mimeType] = true
+	}
+	for _, association := range config.GetConfig().FileAssociations {
+		if !listed[association.MimeType] {
+			listed[association.MimeType] = true
+			mimeTypes = append(mimeTypes, association.MimeType)
+		}
+	}
+	for _, scheme := range urlSchemes() {
+		if !listed["x-scheme-handler/"+scheme] {
+			listed["x-scheme-handler/"+scheme] = true
+			mimeTypes = append(mimeTypes, "x-scheme-handler/"+scheme)
+		}
+	}
+	return mimeTypes
+}
+
+// writeSharedMimeInfo writes the shared-mime-info package of the file
+// associations, which maps their extensions to their mime types, to the
+// mime/packages directory of a share directory, and their icons to the
+// hicolor icon theme.
+func writeSharedMimeInfo(shareDir, mimePackage string) {
+	content := []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<mime-info xmlns="http://www.freedesktop.org/standards/shared-mime-info">`,
+	}
+	for _, association := range fileAssociations() {
+		content = append(content,
+			`  <mime-type type="`+fileutils.XMLEscape(association.MimeType)+`">`,
+			`    <comment>`+fileutils.XMLEscape(association.Description)+`</comment>`,
+		)
+		if association.Icon != "" {
+			iconName := mimePackage + "-" + association.Extension
+			content = append(content, `    <icon name="`+fileutils.XMLEscape(iconName)+`"/>`)
+			writeFileAssociationIcon(association, filepath.Join(shareDir, "icons", "hicolor", "256x256", "mimetypes", iconName+".png"), png.Encode)
+		}
+		content = append(content,
+			`    <glob pattern="*.`+fileutils.XMLEscape(association.Extension)+`"/>`,
+			`  </mime-type>`,
+		)
+	}
+	content = append(content, `</mime-info>`)
+
+	dir := filepath.Join(shareDir, "mime", "packages")
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		log.Errorf("Failed to create %s: %v", dir, err)
+		os.Exit(1)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, mimePackage+".xml"), []byte(strings.Join(content, "\n")+"\n"), 0644)
+	if err != nil {
+		log.Errorf("Could not write the shared-mime-info package: %v", err)
+		os.Exit(1)
+	}
+}
+
+// writeDarwinDocumentIcons writes the .icns icons of the file associations,
+// the CFBundleTypeIconFile of the Info.plist, to the Resources directory of a
+// bundle.
+func writeDarwinDocumentIcons(bundle string) {
+	for _, association := range fileAssociations() {
+		if association.Icon != "" {
+			writeFileAssociationIcon(association, filepath.Join(bundle, "Contents", "Resources", association.Extension+".icns"), encodeIcns)
+		}
+	}
+}
+
+func writeFileAssociationIcon(association config.FileAssociationConfig, path string, encode func(w io.Writer, img image.Image) error) {
+	var data bytes.Buffer
+	err := encode(&data, fileAssociationIcon(association))
+	if err != nil {
+		log.Errorf("Failed to encode the icon of the file association .%s: %v", association.Extension, err)
+		os.Exit(1)
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		log.Errorf("Failed to create %s: %v", filepath.Dir(path), err)
+		os.Exit(1)
+	}
+	err = ioutil.WriteFile(path, data.Bytes(), 0644)
+	if err != nil {
+		log.Errorf("Could not write the icon of the file association .%s: %v", association.Extension, err)
+		os.Exit(1)
+	}
+}
+
+// encodeIcns encodes a 256x256 image as an .icns file, holding a single PNG
+// of the ic08 type.
+func encodeIcns(w io.Writer, img image.Image) error {
+	var data bytes.Buffer
+	err := png.Encode(&data, img)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the icon")
+	}
+	// the lengths include the 8 bytes of the headers
+	icns := []interface{}{
+		[4]byte{'i', 'c', 'n', 's'}, uint32(16 + data.Len()),
+		[4]byte{'i', 'c', '0', '8'}, uint32(8 + data.Len()),
+	}
+	for _, field := range icns {
+		if err := binary.Write(w, binary.BigEndian, field); err != nil {
+			return err
+		}
+	}
+	_, err = w.Write(data.Bytes())
+	return err
+}
+
+// checkSharedMimeInfoInstalled warns when the template of a packaging format
+// doesn't install the shared-mime-info package of the file associations, as
+// it predates them.
+func checkSharedMimeInfoInstalled(packagingFormat, path string) {
+	if len(config.GetConfig().FileAssociations) == 0 {
+		return
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Errorf("Failed to read the %s template output: %v", packagingFormat, err)
+		os.Exit(1)
+	}
+	if !strings.Contains(string(content), "mime/packages/") {
+		log.Warnf("The %s package doesn't install the shared-mime-info package of the file associations, the files won't be associated with the app. Run `%s` to add it to the template.", packagingFormat, log.Au().Magenta("hover upgrade-packaging "+packagingFormat))
+	}
+}
+
+// checkDarwinDocumentTypes fails when the Info.plist of a bundle doesn't
+// declare the file associations, its template predating them.
+func checkDarwinDocumentTypes(path string) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Errorf("Failed to read the Info.plist: %v", err)
+		os.Exit(1)
+	}
+	if !strings.Contains(string(content), "CFBundleDocumentTypes") {
+		log.ErrorCodef(explain.TemplateOutdated, "The Info.plist doesn't declare the file-associations of go/hover.yaml, run `%s` to add them to the template.", log.Au().Magenta("hover upgrade-packaging darwin-bundle"))
+		os.Exit(1)
+	}
+}
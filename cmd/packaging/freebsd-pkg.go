@@ -0,0 +1,32 @@
+package packaging
+
+import "regexp"
+
+// FreebsdPkgTask packaging for freebsd as pkg
+// NOTE: pkg create sets the ABI of the host in the package, the freebsd
+// builds aren't cross-compiled.
+var FreebsdPkgTask = &packagingTask{
+	packagingFormatName: "freebsd-pkg",
+	templateFiles: map[string]string{
+		"freebsd-pkg/+MANIFEST.tmpl": "+MANIFEST.tmpl",
+		"freebsd-pkg/bin.tmpl":       "root/usr/local/bin/{{.executableName}}.tmpl",
+		"linux/app.desktop.tmpl":     "root/usr/local/share/applications/{{.executableName}}.desktop.tmpl",
+	},
+	executableFiles: []string{
+		"root/usr/local/bin/{{.executableName}}",
+	},
+	linuxDesktopFileExecutablePath: "/usr/local/lib/{{.packageName}}/{{.executableName}}",
+	linuxDesktopFileIconPath:       "/usr/local/lib/{{.packageName}}/assets/icon.png",
+	buildOutputDirectory:           "root/usr/local/lib/{{.packageName}}",
+	launcherFile:                   "root/usr/local/bin/{{.executableName}}",
+	// the packing list has the files of the root directory, and the directories
+	// of the app removed when the package is deleted
+	packagingScriptTemplate:       "(cd root && find . ! -type d | sed 's|^\\.||' && find ./usr/local/lib/{{shellquote .packageName}} -type d | sort -r | sed 's|^\\.|@dir |') > plist && pkg create -M +MANIFEST -p plist -r root -o .",
+	outputFileExtension:           "pkg",
+	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
+	outputFileUsesApplicationName: false,
+	identity: append([]identityProperty{
+		{"+MANIFEST", "name", IdentityPackageName, regexp.MustCompile(`(?m)^name: *"?([^"\n]*)`), false},
+	}, desktopFileIdentity("root/usr/local/share/applications/{{.executableName}}.desktop")...),
+}
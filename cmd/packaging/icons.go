@@ -0,0 +1,150 @@
+package packaging
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// hicolorIconSizes are the sizes of the icon of the app installed in the
+// hicolor icon theme. The sizes larger than the icon are left out, scaling
+// it up would blur it.
+var hicolorIconSizes = []int{16, 22, 24, 32, 48, 64, 128, 256, 512}
+
+// isSvgIcon reports whether an icon is an SVG, installed as the scalable icon
+// of the hicolor icon theme.
+func isSvgIcon(icon string) bool {
+	return strings.EqualFold(filepath.Ext(icon), ".svg")
+}
+
+// hicolorIconFiles returns the files of the icon of the app in the hicolor
+// icon theme, relative to the icons directory, smallest first. They are
+// empty when the icon can't be read, the packaging fails on it later.
+func hicolorIconFiles(icon, iconName string) []string {
+	if isSvgIcon(icon) {
+		return []string{"hicolor/scalable/apps/" + iconName + ".svg"}
+	}
+	f, err := os.Open(icon)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	imageConfig, err := png.DecodeConfig(f)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, size := range iconSizes(imageConfig.Width, imageConfig.Height) {
+		files = append(files, hicolorPngIconFile(size, iconName))
+	}
+	return files
+}
+
+// iconSizes returns the hicolorIconSizes an icon can be scaled down to.
+func iconSizes(width, height int) []int {
+	var sizes []int
+	for _, size := range hicolorIconSizes {
+		if size <= width && size <= height {
+			sizes = append(sizes, size)
+		}
+	}
+	return sizes
+}
+
+func hicolorPngIconFile(size int, iconName string) string {
+	return fmt.Sprintf("hicolor/%dx%d/apps/%s.png", size, size, iconName)
+}
+
+// writeHicolorIcons writes the icon of the app to the hicolor icon theme of
+// an icons directory: a PNG icon scaled down to the hicolorIconSizes up to
+// its size, or an SVG icon as is.
+func writeHicolorIcons(iconsDir, icon, iconName string) {
+	if isSvgIcon(icon) {
+		path := filepath.Join(iconsDir, "hicolor", "scalable", "apps", iconName+".svg")
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			log.Errorf("Failed to create %s: %v", filepath.Dir(path), err)
+			os.Exit(1)
+		}
+		fileutils.CopyFile(icon, path)
+		return
+	}
+	f, err := os.Open(icon)
+	if err != nil {
+		log.Errorf("Failed to open the icon %s: %v", icon, err)
+		os.Exit(1)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		log.Errorf("Failed to decode the icon %s, it must be a PNG or an SVG: %v", icon, err)
+		os.Exit(1)
+	}
+	if size := img.Bounds().Size(); size.X != size.Y {
+		log.Errorf("The icon %s must be square to be installed in the icon theme, it is %dx%d pixels.", icon, size.X, size.Y)
+		os.Exit(1)
+	}
+	if img.Bounds().Dx() < 256 {
+		log.Warnf("The icon %s is %dx%d pixels, it looks blurry on high density screens. Use a square icon of 512x512 pixels, or an SVG.", icon, img.Bounds().Dx(), img.Bounds().Dy())
+	}
+	for _, size := range iconSizes(img.Bounds().Dx(), img.Bounds().Dy()) {
+		writePngIcon(scaleDown(img, size), filepath.Join(iconsDir, filepath.FromSlash(hicolorPngIconFile(size, iconName))))
+	}
+}
+
+func writePngIcon(img image.Image, path string) {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		log.Errorf("Failed to create %s: %v", filepath.Dir(path), err)
+		os.Exit(1)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		log.Errorf("Failed to create the icon %s: %v", path, err)
+		os.Exit(1)
+	}
+	defer f.Close()
+	err = png.Encode(f, img)
+	if err != nil {
+		log.Errorf("Failed to encode the icon %s: %v", path, err)
+		os.Exit(1)
+	}
+}
+
+// writeAppDirIcon copies the largest icon of the hicolor icon theme of an
+// AppDir to its root, where appimagetool looks for the icon named by the
+// .desktop file, and to its .DirIcon.
+func writeAppDirIcon(packagingFormat, packageName, appDir string) {
+	icon, _ := config.GetConfig().GetIcon(packagingFormat)
+	files := hicolorIconFiles(icon, appstreamID(packageName))
+	if len(files) == 0 {
+		log.Errorf("The icon %s of %s is missing, or isn't a PNG or an SVG.", icon, packagingFormat)
+		os.Exit(1)
+	}
+	largest := filepath.Join(appDir, "usr", "share", "icons", filepath.FromSlash(files[len(files)-1]))
+	fileutils.CopyFile(largest, filepath.Join(appDir, filepath.Base(largest)))
+	fileutils.CopyFile(largest, filepath.Join(appDir, ".DirIcon"))
+}
+
+// checkHicolorIconsInstalled fails when the template of a packaging format
+// doesn't install the icons of the hicolor icon theme the .desktop file
+// refers to, as it predates them.
+func checkHicolorIconsInstalled(packagingFormat, path, installed string) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Errorf("Failed to read the %s template output: %v", packagingFormat, err)
+		os.Exit(1)
+	}
+	if !strings.Contains(string(content), installed) {
+		log.Errorf("The %s package doesn't install the icons of the app in the hicolor icon theme. Run `%s` to add them to the template.", packagingFormat, log.Au().Magenta("hover upgrade-packaging "+packagingFormat))
+		os.Exit(1)
+	}
+}
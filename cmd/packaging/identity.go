@@ -0,0 +1,209 @@
+package packaging
+
+import (
+	"html"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+)
+
+// Kinds of identity values, named after the template data they should be
+// equal to, except for the bundle identifier which is derived from it.
+const (
+	IdentityApplicationName  = "applicationName"
+	IdentityPackageName      = "packageName"
+	IdentityExecutableName   = "executableName"
+	IdentityBundleIdentifier = "bundleIdentifier"
+	IdentityURLSchemes       = "urlSchemes"
+)
+
+// identityProperty is a value identifying the app in a configuration file of
+// a packaging format.
+type identityProperty struct {
+	file    string         // Template path of the file, relative to the packaging format directory, without .tmpl
+	name    string         // Name of the property in the file, used in the warnings
+	kind    string         // Kind of identity value
+	pattern *regexp.Regexp // The first submatch is the value, of every match for the URL schemes
+	xml     bool           // Whether the value is XML escaped
+}
+
+// IdentityValue is an identity value found in the configuration of a
+// packaging format.
+type IdentityValue struct {
+	Kind     string
+	Expected string // Value derived from go/hover.yaml and pubspec.yaml
+	Value    string
+	Location string // Format, file and property the value was read from
+	file     string // Template path of the file, relative to the packaging format directory
+}
+
+// plistStrings matches the string elements of a plist array.
+var plistStrings = regexp.MustCompile(`</?string>`)
+
+// desktopFileIdentity returns the identity properties of a .desktop file.
+func desktopFileIdentity(file string) []identityProperty {
+	return []identityProperty{
+		{file, "Name", IdentityApplicationName, regexp.MustCompile(`(?m)^Name=(.*)$`), false},
+		{file, "Exec", IdentityExecutableName, regexp.MustCompile(`(?m)^Exec="?(?:[^ "\n]*/)?([^/ "\n]+)"?`), false},
+		{file, "MimeType x-scheme-handler", IdentityURLSchemes, regexp.MustCompile(`x-scheme-handler/([^;\s]+)`), false},
+	}
+}
+
+func (t *packagingTask) Identity(buildVersion string) []IdentityValue {
+	return t.identityValues(buildVersion, "")
+}
+
+// identityValues returns the identity values of a kind, or of all kinds for
+// an empty kind. The URL schemes of a file are a single value, the schemes
+// sorted and separated by spaces like the urlSchemes template data.
+func (t *packagingTask) identityValues(buildVersion, kind string) []IdentityValue {
+	if !t.IsInitialized() {
+		return nil
+	}
+	projectName := pubspec.GetPubSpec().Name
+	templateData := t.getTemplateData(projectName, buildVersion)
+	expected := map[string]string{
+		IdentityApplicationName:  templateData["applicationName"],
+		IdentityPackageName:      templateData["packageName"],
+		IdentityExecutableName:   templateData["executableName"],
+		IdentityBundleIdentifier: templateData["organizationName"] + "." + templateData["packageName"],
+		IdentityURLSchemes:       sortedFields(templateData["urlSchemes"]),
+	}
+
+	var values []IdentityValue
+	for _, property := range t.identity {
+		if kind != "" && property.kind != kind {
+			continue
+		}
+		// the names of the template files are templates themselves
+		templatePath := filepath.Join(packagingFormatPath(t.packagingFormatName), property.file+".tmpl")
+		content, err := ioutil.ReadFile(templatePath)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			log.Errorf("Failed to read %s: %v", templatePath, err)
+			os.Exit(1)
+		}
+		rendered := executeStringTemplate(templatePath, string(content), templateData)
+		file := executeStringTemplate(t.packagingFormatName+" identity file", property.file, templateData)
+		location := t.packagingFormatName + " " + file + " " + property.name
+		if property.kind == IdentityURLSchemes {
+			// no declaration is the value of an app without URL schemes
+			values = append(values, IdentityValue{
+				Kind:     property.kind,
+				Expected: expected[property.kind],
+				Value:    declaredURLSchemes(property, rendered),
+				Location: location,
+				file:     property.file,
+			})
+			continue
+		}
+		match := property.pattern.FindStringSubmatch(rendered)
+		if match == nil {
+			log.Warnf("%s: not found", location)
+			continue
+		}
+		value := match[1]
+		if property.xml {
+			value = html.UnescapeString(value)
+		}
+		values = append(values, IdentityValue{
+			Kind:     property.kind,
+			Expected: expected[property.kind],
+			Value:    value,
+			Location: location,
+			file:     property.file,
+		})
+	}
+	return values
+}
+
+// declaredURLSchemes returns the URL schemes declared in a rendered file,
+// sorted and separated by spaces. The first submatch of each match of the
+// pattern is a scheme, or the string elements of a plist array of schemes.
+func declaredURLSchemes(property identityProperty, rendered string) string {
+	var schemes []string
+	for _, match := range property.pattern.FindAllStringSubmatch(rendered, -1) {
+		for _, scheme := range strings.Fields(plistStrings.ReplaceAllString(match[1], " ")) {
+			if property.xml {
+				scheme = html.UnescapeString(scheme)
+			}
+			schemes = append(schemes, scheme)
+		}
+	}
+	return sortedFields(strings.Join(schemes, " "))
+}
+
+// sortedFields sorts the fields of a list separated by spaces.
+func sortedFields(list string) string {
+	fields := strings.Fields(list)
+	sort.Strings(fields)
+	return strings.Join(fields, " ")
+}
+
+// RenameIdentity returns the template files of the packaging format in which
+// identity values, written as the current value, are replaced by the values
+// of renamed, by kind. The values written as template data (e.g.
+// {{.packageName}}) follow go/hover.yaml and are left as is. Only the changed
+// files are returned, by path, the files are not written.
+func (t *packagingTask) RenameIdentity(buildVersion string, renamed map[string]string) map[string][]byte {
+	if !t.IsInitialized() {
+		return nil
+	}
+	projectName := pubspec.GetPubSpec().Name
+	templateData := t.getTemplateData(projectName, buildVersion)
+	current := map[string]string{
+		IdentityApplicationName:  templateData["applicationName"],
+		IdentityPackageName:      templateData["packageName"],
+		IdentityExecutableName:   templateData["executableName"],
+		IdentityBundleIdentifier: templateData["organizationName"] + "." + templateData["packageName"],
+	}
+
+	files := make(map[string][]byte)
+	for _, property := range t.identity {
+		value, ok := renamed[property.kind]
+		if !ok {
+			continue
+		}
+		templatePath := filepath.Join(packagingFormatPath(t.packagingFormatName), property.file+".tmpl")
+		content, edited := files[templatePath]
+		if !edited {
+			var err error
+			content, err = ioutil.ReadFile(templatePath)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				log.Errorf("Failed to read %s: %v", templatePath, err)
+				os.Exit(1)
+			}
+		}
+		match := property.pattern.FindSubmatchIndex(content)
+		if match == nil {
+			continue
+		}
+		written := string(content[match[2]:match[3]])
+		if strings.Contains(written, "{{") {
+			continue
+		}
+		if property.xml {
+			written = html.UnescapeString(written)
+			value = html.EscapeString(value)
+		}
+		if written != current[property.kind] {
+			continue
+		}
+		var out []byte
+		out = append(out, content[:match[2]]...)
+		out = append(out, value...)
+		files[templatePath] = append(out, content[match[3]:]...)
+	}
+	return files
+}
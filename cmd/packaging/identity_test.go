@@ -0,0 +1,65 @@
+package packaging
+
+import "testing"
+
+func TestDeclaredURLSchemes(t *testing.T) {
+	tests := []struct {
+		name     string
+		property identityProperty
+		rendered string
+		want     string
+	}{
+		{
+			name:     "desktop file",
+			property: desktopFileIdentity("app.desktop")[2],
+			rendered: "[Desktop Entry]\nName=App\nMimeType=text/plain;x-scheme-handler/myapp;x-scheme-handler/beta+app;\n",
+			want:     "beta+app myapp",
+		},
+		{
+			name:     "desktop file without schemes",
+			property: desktopFileIdentity("app.desktop")[2],
+			rendered: "[Desktop Entry]\nName=App\nMimeType=text/plain;\n",
+			want:     "",
+		},
+		{
+			name:     "darwin-bundle",
+			property: DarwinBundleTask.identity[3],
+			rendered: "<key>CFBundleURLSchemes</key>\n<array>\n    <string>myapp</string>\n    <string>app.beta</string>\n</array>",
+			want:     "app.beta myapp",
+		},
+		{
+			name:     "windows-inno",
+			property: WindowsInnoTask.identity[1],
+			rendered: "Root: HKA; Subkey: \"Software\\Classes\\myapp\"; ValueType: string; ValueName: \"\"; ValueData: \"URL:App\"\nRoot: HKA; Subkey: \"Software\\Classes\\myapp\"; ValueType: string; ValueName: \"URL Protocol\"; ValueData: \"\"\n",
+			want:     "myapp",
+		},
+		{
+			name:     "windows-nsis",
+			property: WindowsNsisTask.identity[1],
+			rendered: "    WriteRegStr HKLM \"Software\\Classes\\myapp\" \"URL Protocol\" \"\"\n    WriteRegStr HKLM \"Software\\Classes\\myapp\\DefaultIcon\" \"\" \"app.exe,0\"\n",
+			want:     "myapp",
+		},
+		{
+			name:     "windows-msi",
+			property: WindowsMsiTask.identity[2],
+			rendered: "<RegistryKey Root=\"HKCR\" Key=\"myapp\">\n    <RegistryValue Type=\"string\" Value=\"URL:App\"/>\n",
+			want:     "myapp",
+		},
+		{
+			name:     "windows-msix",
+			property: WindowsMsixTask.identity[1],
+			rendered: "<uap:Extension Category=\"windows.protocol\">\n    <uap:Protocol Name=\"myapp\"/>\n</uap:Extension>",
+			want:     "myapp",
+		},
+	}
+	for _, test := range tests {
+		if test.property.kind != IdentityURLSchemes {
+			t.Errorf("%s: the property is %s, not the URL schemes", test.name, test.property.kind)
+			continue
+		}
+		got := declaredURLSchemes(test.property, test.rendered)
+		if got != test.want {
+			t.Errorf("%s: declaredURLSchemes() = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package packaging
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/provenance"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+)
+
+// The integrity manifest and its signature, next to the executable.
+const (
+	integrityManifestName  = "integrity.json"
+	integritySignatureName = "integrity.json.sig"
+)
+
+// integrityManifest lists the sha256 of the files of the build output, by
+// slash separated path relative to the executable.
+type integrityManifest struct {
+	Files map[string]string `json:"files"`
+}
+
+var (
+	integrityKey     crypto.Signer
+	integrityKeyOnce sync.Once
+)
+
+// IntegrityKey returns the key signing the integrity manifests, nil when
+// integrity.key isn't set in go/hover.yaml.
+func IntegrityKey() crypto.Signer {
+	integrityKeyOnce.Do(func() {
+		keyPem := []byte(os.Getenv("HOVER_INTEGRITY_KEY"))
+		if len(keyPem) == 0 {
+			keyPath := config.GetConfig().Integrity.Key
+			if keyPath == "" {
+				return
+			}
+			var err error
+			keyPem, err = ioutil.ReadFile(keyPath)
+			if err != nil {
+				log.Errorf("Failed to read the integrity key: %v", err)
+				os.Exit(1)
+			}
+		}
+		key, err := provenance.ParseKey(keyPem)
+		if err != nil {
+			log.Errorf("Invalid integrity key: %v", err)
+			os.Exit(1)
+		}
+		integrityKey = key
+	})
+	return integrityKey
+}
+
+// IntegrityPublicKey returns the base64 of the DER encoded public key the
+// integrity manifests are signed with, empty when they aren't signed.
+func IntegrityPublicKey() string {
+	key := IntegrityKey()
+	if key == nil {
+		return ""
+	}
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		log.Errorf("Failed to encode the integrity public key: %v", err)
+		os.Exit(1)
+	}
+	return base64.StdEncoding.EncodeToString(der)
+}
+
+// WriteIntegrityManifest writes the integrity manifest of a build output
+// directory, signed when integrity.key is set. The executable and the engine
+// aren't listed, the code signatures of the platforms cover them and
+// codesign modifies them on darwin.
+func WriteIntegrityManifest(dir, targetOS string) error {
+	executableName := build.OutputBinary(config.GetConfig().GetExecutableName(pubspec.GetPubSpec().Name), targetOS)
+	engineName := build.EngineFilename(targetOS)
+	manifest := integrityManifest{Files: map[string]string{}}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		relativePath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		relativePath = filepath.ToSlash(relativePath)
+		switch {
+		case relativePath == integrityManifestName, relativePath == integritySignatureName, relativePath == executableName:
+			return nil
+		case relativePath == engineName, strings.HasPrefix(relativePath, engineName+"/"):
+			return nil
+		}
+		sum, err := fileutils.SHA256File(path)
+		if err != nil {
+			return err
+		}
+		manifest.Files[relativePath] = sum
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to hash the build output")
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	// the files of a packaging directory may be read-only copies of the build
+	// output, they are replaced rather than written to
+	os.Remove(filepath.Join(dir, integrityManifestName))
+	os.Remove(filepath.Join(dir, integritySignatureName))
+	err = ioutil.WriteFile(filepath.Join(dir, integrityManifestName), data, 0644)
+	if err != nil {
+		return err
+	}
+	key := IntegrityKey()
+	if key == nil {
+		return nil
+	}
+	sig, err := provenance.SignBlob(data, key)
+	if err != nil {
+		return errors.Wrap(err, "failed to sign the integrity manifest")
+	}
+	return ioutil.WriteFile(filepath.Join(dir, integritySignatureName), []byte(base64.StdEncoding.EncodeToString(sig)), 0644)
+}
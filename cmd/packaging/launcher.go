@@ -0,0 +1,132 @@
+package packaging
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+var environmentVariableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// launcherSetup returns the shell commands of the launcher configuration,
+// run by the linux launcher scripts before starting the app. They expect the
+// app directory in $app_dir.
+func launcherSetup(c config.LauncherConfig, displayServer string) string {
+	var lines []string
+	if displayServer == config.DisplayServerWayland {
+		// the sessions started without a login manager may not set it
+		lines = append(lines, "export WAYLAND_DISPLAY=\"${WAYLAND_DISPLAY:-wayland-0}\"")
+	}
+	for _, name := range launcherEnvNames(c) {
+		lines = append(lines, "export "+name+"=\""+shellEscapeDoubleQuoted(c.Env[name])+"\"")
+	}
+	if len(c.LibraryPath) > 0 {
+		var dirs []string
+		for _, dir := range c.LibraryPath {
+			dirs = append(dirs, launcherShellPath(dir))
+		}
+		lines = append(lines, "export LD_LIBRARY_PATH=\""+strings.Join(dirs, ":")+"${LD_LIBRARY_PATH:+:$LD_LIBRARY_PATH}\"")
+	}
+	if c.WorkingDirectory != "" {
+		lines = append(lines, "cd \""+launcherShellPath(c.WorkingDirectory)+"\"")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// launcherSetupCmd returns the cmd commands of the launcher configuration,
+// run by the windows launcher scripts from the app directory before starting
+// the app.
+func launcherSetupCmd(c config.LauncherConfig) string {
+	var lines []string
+	for _, name := range launcherEnvNames(c) {
+		lines = append(lines, "set \""+name+"="+c.Env[name]+"\"")
+	}
+	if len(c.LibraryPath) > 0 {
+		var dirs []string
+		for _, dir := range c.LibraryPath {
+			dirs = append(dirs, launcherCmdPath(dir))
+		}
+		lines = append(lines, "set \"PATH="+strings.Join(dirs, ";")+";%PATH%\"")
+	}
+	if c.WorkingDirectory != "" {
+		lines = append(lines, "cd /d \""+launcherCmdPath(c.WorkingDirectory)+"\"")
+	}
+	return strings.Join(lines, "\r\n")
+}
+
+func launcherEnvNames(c config.LauncherConfig) []string {
+	names := make([]string, 0, len(c.Env))
+	for name := range c.Env {
+		if !environmentVariableName.MatchString(name) {
+			log.Errorf("Invalid environment variable name %q in the launcher section of go/hover.yaml", name)
+			os.Exit(1)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// launcherShellPath returns a path relative to the app directory for the
+// linux launcher scripts.
+func launcherShellPath(p string) string {
+	if path.IsAbs(p) {
+		return shellEscapeDoubleQuoted(p)
+	}
+	return "$app_dir/" + shellEscapeDoubleQuoted(p)
+}
+
+// launcherCmdPath returns a path relative to the app directory for the
+// windows launcher scripts, which run from the app directory.
+func launcherCmdPath(p string) string {
+	p = strings.ReplaceAll(p, "/", `\`)
+	if filepath.IsAbs(p) || strings.HasPrefix(p, `\`) || (len(p) > 1 && p[1] == ':') {
+		return p
+	}
+	return `%CD%\` + p
+}
+
+// shellEscapeDoubleQuoted escapes a value for a double quoted shell string,
+// keeping the variables expanded.
+func shellEscapeDoubleQuoted(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`").Replace(value)
+}
+
+// writeLauncher replaces the launcher script of the packaging task by the
+// template configured in go/hover.yaml.
+func (t *packagingTask) writeLauncher(tmpPath string, templateData map[string]string) {
+	templatePath, ok := config.GetConfig().GetLauncherTemplate(t.packagingFormatName)
+	if !ok {
+		return
+	}
+	if t.launcherFile == "" {
+		// a template set for the platform only applies to the formats with a
+		// launcher script
+		if _, ok := config.GetConfig().Launcher.Templates[t.packagingFormatName]; ok {
+			log.Errorf("%s has no launcher script to replace with %s", t.packagingFormatName, templatePath)
+			os.Exit(1)
+		}
+		return
+	}
+	content, err := ioutil.ReadFile(templatePath)
+	if err != nil {
+		log.Errorf("Failed to read the launcher template of %s: %v", t.packagingFormatName, err)
+		os.Exit(1)
+	}
+	launcherPath := fileutils.LongPath(filepath.Join(tmpPath, executeStringTemplate(t.packagingFormatName+" launcher file", t.launcherFile, templateData)))
+	log.Printf("Using launcher template %s", templatePath)
+	launcher := executeStringTemplate(templatePath, string(content), templateData)
+	err = ioutil.WriteFile(launcherPath, []byte(launcher), 0755)
+	if err != nil {
+		log.Errorf("Failed to write the launcher of %s: %v", t.packagingFormatName, err)
+		os.Exit(1)
+	}
+}
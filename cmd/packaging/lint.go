@@ -0,0 +1,126 @@
+package packaging
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+)
+
+// LintProblem is a problem found in the configuration files of a packaging
+// format, which would make the packaging fail or produce a broken package.
+type LintProblem struct {
+	File    string // Path of the file, relative to the go directory
+	Message string
+}
+
+// Lint checks the configuration files of the packaging format without
+// packaging the app. The names and contents of the files are executed with
+// the template data, the files referenced by the packaging script must
+// exist, and the scripts of the format must be executable in the package.
+func (t *packagingTask) Lint(buildVersion string) []LintProblem {
+	if !t.IsInitialized() {
+		return nil
+	}
+	projectName := pubspec.GetPubSpec().Name
+	data := t.getTemplateData(projectName, buildVersion)
+	dir := packagingFormatPath(t.packagingFormatName)
+	var problems []LintProblem
+	problem := func(file, message string) {
+		problems = append(problems, LintProblem{File: filepath.ToSlash(filepath.Join("packaging", t.packagingFormatName, file)), Message: message})
+	}
+
+	executableFiles := make(map[string]bool)
+	for _, file := range t.executableFiles {
+		executableFiles[executeStringTemplate(t.packagingFormatName+" executable file", file, data)] = true
+	}
+	script := executeStringTemplate(t.packagingFormatName+" packaging script", t.packagingScriptTemplate, data)
+
+	renderedFiles := make(map[string]bool)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		relativePath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		relativePath = filepath.ToSlash(relativePath)
+		rendered, err := lintTemplate(relativePath, relativePath, data)
+		if err != nil {
+			problem(relativePath, "invalid file name: "+err.Error())
+			return nil
+		}
+		rendered = strings.TrimSuffix(rendered, ".tmpl")
+		renderedFiles[rendered] = true
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		_, err = lintTemplate(relativePath, string(content), data)
+		if err != nil {
+			problem(relativePath, err.Error())
+		}
+		// the files are rendered without their permissions, the scripts only
+		// run from the package when hover makes them executable, or when the
+		// packaging script uses them.
+		isScript := info.Mode()&0111 != 0 || bytes.HasPrefix(content, []byte("#!"))
+		if isScript && !executableFiles[rendered] && !strings.Contains(script, rendered) {
+			problem(relativePath, "looks like a script, but it isn't executable in the package, hover only makes the scripts of the format executable")
+		}
+		return nil
+	})
+	if err != nil {
+		log.Errorf("Failed to read the files of %s: %v", t.packagingFormatName, err)
+		os.Exit(1)
+	}
+
+	var templateFiles []string
+	for _, destination := range t.templateFiles {
+		templateFiles = append(templateFiles, strings.TrimSuffix(executeStringTemplate(t.packagingFormatName+" template file", destination, data), ".tmpl"))
+	}
+	sort.Strings(templateFiles)
+	for _, file := range templateFiles {
+		if !renderedFiles[file] && strings.Contains(script, file) {
+			problem(file+".tmpl", "is missing, the packaging script uses it")
+		}
+	}
+	// the URL schemes are only registered by the files declaring them. The
+	// files are rendered to find them, which the template errors prevent.
+	if len(problems) == 0 {
+		for _, value := range t.identityValues(buildVersion, IdentityURLSchemes) {
+			if value.Value != value.Expected {
+				problem(value.file+".tmpl", "declares the URL schemes '"+value.Value+"', but url-schemes of go/hover.yaml is '"+value.Expected+"', run `hover upgrade-packaging` for the templates of older projects")
+			}
+		}
+	}
+	if icon, ok := config.GetConfig().GetIcon(t.packagingFormatName); ok && !fileutils.IsFileExists(icon) {
+		problems = append(problems, LintProblem{File: "hover.yaml", Message: "the icon " + icon + " configured for " + t.packagingFormatName + " doesn't exist"})
+	}
+	return problems
+}
+
+// lintTemplate executes a template like the packaging, and returns the
+// result.
+func lintTemplate(name, text string, data map[string]string) (string, error) {
+	tmpl, err := fileutils.ParseTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	err = tmpl.Execute(&out, data)
+	return out.String(), err
+}
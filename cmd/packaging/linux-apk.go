@@ -0,0 +1,94 @@
+package packaging
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// LinuxApkTask packaging for linux as an Alpine apk, built with abuild in an
+// alpine container
+var LinuxApkTask = &packagingTask{
+	packagingFormatName: "linux-apk",
+	templateFiles: map[string]string{
+		"linux-apk/APKBUILD.tmpl":      "APKBUILD.tmpl",
+		"linux-apk/post-install.tmpl":  "{{.packageName}}.post-install.tmpl",
+		"linux-apk/post-upgrade.tmpl":  "{{.packageName}}.post-upgrade.tmpl",
+		"linux-apk/pre-deinstall.tmpl": "{{.packageName}}.pre-deinstall.tmpl",
+		"linux/bin.tmpl":               "root/usr/bin/{{.executableName}}.tmpl",
+		"linux/app.desktop.tmpl":       "root/usr/share/applications/{{.executableName}}.desktop.tmpl",
+	},
+	executableFiles: []string{
+		"{{.packageName}}.post-install",
+		"{{.packageName}}.post-upgrade",
+		"{{.packageName}}.pre-deinstall",
+		"root/usr/bin/{{.executableName}}",
+		"root/usr/share/applications/{{.executableName}}.desktop",
+	},
+	linuxDesktopFileExecutablePath: "/usr/lib/{{.packageName}}/{{.executableName}}",
+	linuxDesktopFileIconPath:       "/usr/lib/{{.packageName}}/assets/icon.png",
+	buildOutputDirectory:           "root/usr/lib/{{.packageName}}",
+	gsettingsSchemaDirectory:       "root/usr/share/glib-2.0/schemas",
+	fileAssociationsDirectory:      "root/usr/share",
+	linuxDesktopFile:               "root/usr/share/applications/{{.executableName}}.desktop",
+	dbusServiceDirectory:           "root/usr/share/dbus-1/services",
+	launcherFile:                   "root/usr/bin/{{.executableName}}",
+	generateBuildFiles:             assertApkbuild,
+	// abuild runs as root in the container with a throwaway signing key, the
+	// files it creates are given back to the user running hover
+	packagingScriptTemplate:       "docker run --rm --platform linux/{{.arch}} -e HOST_USER=\"$(id -u):$(id -g)\" -v \"$PWD\":/home/apk/{{shellquote .packageName}} -w /home/apk/{{shellquote .packageName}} alpine:latest sh -c 'apk add -q abuild && abuild-keygen -a -n >/dev/null 2>&1 && abuild -F -d -q -P \"$PWD/packages\"; status=$?; chown -R \"$HOST_USER\" .; exit $status' && mv -n {{shellquote \"packages/apk/\" .gnuArch \"/\" .packageName \"-\" .apkVersion \"-r0.apk\"}} {{shellquote .packageName \"-\" .version \".apk\"}}",
+	outputFileExtension:           "apk",
+	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
+	outputFileUsesApplicationName: false,
+	smokeTest: &smokeTest{
+		image:  "alpine:latest",
+		script: "apk add -q --allow-untrusted xvfb-run coreutils mesa-dri-gallium \"$ARTIFACT\" && smoke_run {{shellquote .executableName}}",
+	},
+	identity: append([]identityProperty{
+		{"APKBUILD", "pkgname", IdentityPackageName, regexp.MustCompile(`(?m)^pkgname=['"]?([^'"\n]*)`), false},
+	}, desktopFileIdentity("root/usr/share/applications/{{.executableName}}.desktop")...),
+}
+
+var (
+	apkbuildArchitecture = regexp.MustCompile(`(?m)^arch=["']?([^"'\s]*)`)
+	apkbuildURL          = regexp.MustCompile(`(?m)^url=["']?([^"'\n]*)`)
+	apkPreRelease        = regexp.MustCompile(`^(alpha|beta|pre|rc)\.?(\d*)`)
+)
+
+// assertApkbuild checks the architecture of the APKBUILD, and that its url is
+// set, which abuild requires.
+func assertApkbuild(packageName, tmpPath string) {
+	assertTemplateArch("linux-apk", "APKBUILD", apkbuildArchitecture, true)(packageName, tmpPath)
+	content, err := ioutil.ReadFile(filepath.Join(tmpPath, "APKBUILD"))
+	if err != nil {
+		log.Errorf("Failed to read APKBUILD: %v", err)
+		os.Exit(1)
+	}
+	if match := apkbuildURL.FindSubmatch(content); match == nil || len(match[1]) == 0 {
+		log.Errorf("The url of the APKBUILD is empty, set the homepage of the app in pubspec.yaml or the url in go/packaging/linux-apk/APKBUILD.")
+		os.Exit(1)
+	}
+}
+
+// apkVersion returns the version of an apk package: the numeric part of the
+// version, followed by its alpha, beta, pre or rc pre-release, e.g. 1.2.3_rc1
+// for 1.2.3-rc.1+4. The other pre-releases and the build metadata aren't
+// part of the version.
+func apkVersion(version string) string {
+	parts := strings.SplitN(strings.SplitN(version, "+", 2)[0], "-", 2)
+	apkVersion := msixNumericVersion.FindString(parts[0])
+	if apkVersion == "" {
+		apkVersion = "0"
+	}
+	if len(parts) == 2 {
+		if match := apkPreRelease.FindStringSubmatch(strings.ToLower(parts[1])); match != nil {
+			apkVersion += "_" + match[1] + match[2]
+		}
+	}
+	return apkVersion
+}
@@ -0,0 +1,28 @@
+package packaging
+
+// LinuxAppDirTask packaging for linux as the AppDir of an AppImage, without
+// running appimagetool, to be post-processed by linuxdeploy or other AppImage
+// tooling
+var LinuxAppDirTask = &packagingTask{
+	packagingFormatName: "linux-appdir",
+	templateFiles: map[string]string{
+		"linux-appimage/AppRun.tmpl": "{{.packageName}}-{{.version}}.AppDir/AppRun.tmpl",
+		"linux/app.desktop.tmpl":     "{{.packageName}}-{{.version}}.AppDir/{{.executableName}}.desktop.tmpl",
+	},
+	executableFiles: []string{
+		"{{.packageName}}-{{.version}}.AppDir/AppRun",
+		"{{.packageName}}-{{.version}}.AppDir/{{.executableName}}.desktop",
+	},
+	linuxDesktopFileExecutablePath: "{{.executableName}}",
+	linuxDesktopFileIconPath:       "{{.executableName}}",
+	buildOutputDirectory:           "{{.packageName}}-{{.version}}.AppDir/build",
+	launcherFile:                   "{{.packageName}}-{{.version}}.AppDir/AppRun",
+	// the icon named by the .desktop file and the .DirIcon are at the root of
+	// the AppDir
+	packagingScriptTemplate:       "cd {{shellquote .packageName \"-\" .version \".AppDir\"}} && cp build/assets/icon.png {{shellquote .executableName \".png\"}} && cp build/assets/icon.png .DirIcon",
+	outputFileExtension:           "AppDir",
+	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
+	outputFileUsesApplicationName: false,
+	identity:                      desktopFileIdentity("{{.packageName}}-{{.version}}.AppDir/{{.executableName}}.desktop"),
+}
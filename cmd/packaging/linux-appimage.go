@@ -11,10 +11,22 @@ var LinuxAppImageTask = &packagingTask{
 		"AppRun",
 		"{{.executableName}}.desktop",
 	},
-	linuxDesktopFileIconPath:      "/build/assets/icon",
-	buildOutputDirectory:          "build",
-	packagingScriptTemplate:       "appimagetool . && mv -n {{.executableName}}-x86_64.AppImage {{.packageName}}-{{.version}}.AppImage",
+	linuxDesktopFileIconPath: "{{.iconName}}",
+	buildOutputDirectory:     "build",
+	hicolorIconsDirectory:    "usr/share",
+	launcherFile:             "AppRun",
+	generateBuildFiles: func(packageName, tmpPath string) {
+		writeAppDirIcon("linux-appimage", packageName, tmpPath)
+	},
+	packagingScriptTemplate:       "{{if .appimageExtractAndRun}}APPIMAGE_EXTRACT_AND_RUN=1 {{end}}ARCH={{shellquote .gnuArch}} appimagetool . && mv -n {{shellquote .executableName \"-\" .gnuArch \".AppImage\"}} {{shellquote .packageName \"-\" .version \".AppImage\"}}",
+	packagingScriptDownloads:      true,
 	outputFileExtension:           "AppImage",
 	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
 	outputFileUsesApplicationName: false,
+	smokeTest: &smokeTest{
+		image: "ubuntu:24.04",
+		// FUSE isn't available in the container
+		script: smokeTestUbuntuLibraries + "cp \"$ARTIFACT\" /tmp/app.AppImage && chmod +x /tmp/app.AppImage && export APPIMAGE_EXTRACT_AND_RUN=1 && smoke_run /tmp/app.AppImage",
+	},
 }
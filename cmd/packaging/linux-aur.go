@@ -0,0 +1,69 @@
+package packaging
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+)
+
+// LinuxAurTask packaging for linux as a tarball, the source of the AUR
+// package written by hover publish
+var LinuxAurTask = &packagingTask{
+	packagingFormatName: "linux-aur",
+	templateFiles: map[string]string{
+		"linux-aur/PKGBUILD.tmpl": "PKGBUILD.tmpl",
+		"linux/bin.tmpl":          "src/usr/bin/{{.executableName}}.tmpl",
+		"linux/app.desktop.tmpl":  "src/usr/share/applications/{{.executableName}}.desktop.tmpl",
+	},
+	executableFiles: []string{
+		"src/usr/bin/{{.executableName}}",
+		"src/usr/share/applications/{{.executableName}}.desktop",
+	},
+	linuxDesktopFileExecutablePath: "/usr/lib/{{.packageName}}/{{.executableName}}",
+	linuxDesktopFileIconPath:       "/usr/lib/{{.packageName}}/assets/icon.png",
+	buildOutputDirectory:           "src/usr/lib/{{.packageName}}",
+	gsettingsSchemaDirectory:       "src/usr/share/glib-2.0/schemas",
+	fileAssociationsDirectory:      "src/usr/share",
+	linuxDesktopFile:               "src/usr/share/applications/{{.executableName}}.desktop",
+	dbusServiceDirectory:           "src/usr/share/dbus-1/services",
+	launcherFile:                   "src/usr/bin/{{.executableName}}",
+	// the source and its checksum are only known when publishing
+	templateData: map[string]string{
+		"aurSourceURL": "",
+		"aurSha256":    "SKIP",
+	},
+	generateBuildFiles:            assertTemplateArch("linux-aur", "PKGBUILD", pkgbuildArchitecture, true),
+	packagingScriptTemplate:       "tar -czf {{shellquote .packageName \"-\" .version \".tar.gz\"}} --owner=0 --group=0 -C src .",
+	outputFileExtension:           "tar.gz",
+	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
+	outputFileUsesApplicationName: false,
+	identity: append([]identityProperty{
+		{"PKGBUILD", "pkgname", IdentityPackageName, regexp.MustCompile(`(?m)^pkgname=['"]?([^'"\n]*?)(?:-bin)?['"]?$`), false},
+	}, desktopFileIdentity("src/usr/share/applications/{{.executableName}}.desktop")...),
+}
+
+// WriteAurPackage writes the PKGBUILD and the .SRCINFO of the AUR package to
+// a directory, for the linux-aur tarball published at sourceURL.
+func WriteAurPackage(dir, sourceURL, sha256 string) error {
+	projectName := pubspec.GetPubSpec().Name
+	data := LinuxAurTask.getTemplateData(projectName, pubspec.GetPubSpec().GetVersion())
+	data["aurSourceURL"] = sourceURL
+	data["aurSha256"] = sha256
+	fileutils.ExecuteTemplateFromFile(filepath.Join(packagingFormatPath(LinuxAurTask.packagingFormatName), "PKGBUILD.tmpl"), filepath.Join(dir, "PKGBUILD"), data)
+
+	cmdPrintSrcinfo := exec.Command(build.MakepkgBin(), "--printsrcinfo")
+	cmdPrintSrcinfo.Dir = dir
+	srcinfo, err := cmdPrintSrcinfo.Output()
+	if err != nil {
+		return errors.Wrap(err, "failed to generate .SRCINFO")
+	}
+	return ioutil.WriteFile(filepath.Join(dir, ".SRCINFO"), srcinfo, 0644)
+}
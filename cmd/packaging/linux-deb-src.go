@@ -0,0 +1,77 @@
+package packaging
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// LinuxDebSrcTask packaging for linux as a debian source package of the
+// build output, to upload to a Launchpad PPA
+var LinuxDebSrcTask = &packagingTask{
+	packagingFormatName: "linux-deb-src",
+	templateFiles: map[string]string{
+		"linux-deb-src/control.tmpl":   "src/debian/control.tmpl",
+		"linux-deb-src/rules.tmpl":     "src/debian/rules.tmpl",
+		"linux-deb-src/changelog.tmpl": "src/debian/changelog.tmpl",
+		"linux-deb-src/compat.tmpl":    "src/debian/compat.tmpl",
+		"linux-deb-src/install.tmpl":   "src/debian/install.tmpl",
+		"linux-deb-src/format.tmpl":    "src/debian/source/format.tmpl",
+		"linux/bin.tmpl":               "src/usr/bin/{{.executableName}}.tmpl",
+		"linux/app.desktop.tmpl":       "src/usr/share/applications/{{.executableName}}.desktop.tmpl",
+	},
+	executableFiles: []string{
+		"src/debian/rules",
+		"src/usr/bin/{{.executableName}}",
+		"src/usr/share/applications/{{.executableName}}.desktop",
+	},
+	linuxDesktopFileExecutablePath: "/usr/lib/{{.packageName}}/{{.executableName}}",
+	linuxDesktopFileIconPath:       "/usr/lib/{{.packageName}}/assets/icon.png",
+	buildOutputDirectory:           "src/usr/lib/{{.packageName}}",
+	gsettingsSchemaDirectory:       "src/usr/share/glib-2.0/schemas",
+	fileAssociationsDirectory:      "src/usr/share",
+	linuxDesktopFile:               "src/usr/share/applications/{{.executableName}}.desktop",
+	dbusServiceDirectory:           "src/usr/share/dbus-1/services",
+	launcherFile:                   "src/usr/bin/{{.executableName}}",
+	generateBuildFiles: func(packageName, tmpPath string) {
+		assertTemplateArch("linux-deb-src", "src/debian/control", debArchitecture, false)(packageName, tmpPath)
+		assertDebMaintainer(filepath.Join(tmpPath, "src", "debian", "control"))
+		checkRelationships("linux-deb-src", filepath.Join(tmpPath, "src", "debian", "control"), debRelationshipFields, debRelationships(config.GetConfig().Deb))
+	},
+	// the upload files of the source package are archived together, the
+	// changes are signed with debsign before uploading them with dput. The
+	// source tree is new, it isn't cleaned with debhelper.
+	packagingScriptTemplate:       "tar -czf {{shellquote .packageName \"_\" .version \".orig.tar.gz\"}} --owner=0 --group=0 --exclude=src/debian src && (cd src && debuild -S -us -uc -d -nc) && tar -czf {{shellquote .packageName \"-\" .version \".source.tar.gz\"}} --owner=0 --group=0 {{shellquote .packageName \"_\"}}*",
+	outputFileExtension:           "source.tar.gz",
+	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
+	outputFileUsesApplicationName: false,
+	identity: append([]identityProperty{
+		{"src/debian/control", "Source", IdentityPackageName, regexp.MustCompile(`(?m)^Source: *(.*)$`), false},
+		{"src/debian/control", "Package", IdentityPackageName, regexp.MustCompile(`(?m)^Package: *(.*)$`), false},
+	}, desktopFileIdentity("src/usr/share/applications/{{.executableName}}.desktop")...),
+}
+
+var debMaintainer = regexp.MustCompile(`(?m)^Maintainer: *(.*)$`)
+
+// assertDebMaintainer checks that the maintainer of a debian source package
+// has a name and an email address, Launchpad rejects the uploads otherwise.
+func assertDebMaintainer(controlPath string) {
+	content, err := ioutil.ReadFile(controlPath)
+	if err != nil {
+		log.Errorf("Failed to read %s: %v", filepath.Base(controlPath), err)
+		os.Exit(1)
+	}
+	match := debMaintainer.FindSubmatch(content)
+	if match == nil {
+		return
+	}
+	if !regexp.MustCompile(`^[^<>]+ <[^<>\s]+@[^<>\s]+>$`).Match(match[1]) {
+		log.Errorf("The Maintainer '%s' of the debian source package must be a name and an email address, e.g. 'Jane Doe <jane@example.com>'. Set the author of pubspec.yaml, or the maintainer in the control and changelog of go/packaging/linux-deb-src.", match[1])
+		os.Exit(1)
+	}
+}
@@ -1,22 +1,54 @@
 package packaging
 
+import (
+	"path/filepath"
+	"regexp"
+
+	"github.com/go-flutter-desktop/hover/internal/config"
+)
+
 // LinuxDebTask packaging for linux as deb
 var LinuxDebTask = &packagingTask{
 	packagingFormatName: "linux-deb",
 	templateFiles: map[string]string{
-		"linux-deb/control.tmpl": "DEBIAN/control.tmpl",
-		"linux/bin.tmpl":         "usr/bin/{{.executableName}}.tmpl",
-		"linux/app.desktop.tmpl": "usr/share/applications/{{.executableName}}.desktop.tmpl",
+		"linux-deb/control.tmpl":  "DEBIAN/control.tmpl",
+		"linux-deb/prerm.tmpl":    "DEBIAN/prerm.tmpl",
+		"linux-deb/postinst.tmpl": "DEBIAN/postinst.tmpl",
+		"linux/bin.tmpl":          "usr/bin/{{.executableName}}.tmpl",
+		"linux/app.desktop.tmpl":  "usr/share/applications/{{.executableName}}.desktop.tmpl",
 	},
 	executableFiles: []string{
+		"DEBIAN/prerm",
+		"DEBIAN/postinst",
 		"usr/bin/{{.executableName}}",
 		"usr/share/applications/{{.executableName}}.desktop",
 	},
 	linuxDesktopFileExecutablePath: "/usr/lib/{{.packageName}}/{{.executableName}}",
-	linuxDesktopFileIconPath:       "/usr/lib/{{.packageName}}/assets/icon.png",
+	linuxDesktopFileIconPath:       "{{.iconName}}",
 	buildOutputDirectory:           "usr/lib/{{.packageName}}",
-	packagingScriptTemplate:        "dpkg-deb --build . {{.packageName}}-{{.version}}.deb",
-	outputFileExtension:            "deb",
-	outputFileContainsVersion:      true,
-	outputFileUsesApplicationName:  false,
+	gsettingsSchemaDirectory:       "usr/share/glib-2.0/schemas",
+	fileAssociationsDirectory:      "usr/share",
+	hicolorIconsDirectory:          "usr/share",
+	linuxDesktopFile:               "usr/share/applications/{{.executableName}}.desktop",
+	dbusServiceDirectory:           "usr/share/dbus-1/services",
+	appstreamDirectory:             "usr/share/metainfo",
+	appstreamDesktopID:             "{{.desktopFileName}}.desktop",
+	launcherFile:                   "usr/bin/{{.executableName}}",
+	generateBuildFiles: func(packageName, tmpPath string) {
+		assertTemplateArch("linux-deb", "DEBIAN/control", debArchitecture, false)(packageName, tmpPath)
+		checkRelationships("linux-deb", filepath.Join(tmpPath, "DEBIAN", "control"), debRelationshipFields, debRelationships(config.GetConfig().Deb))
+	},
+	splitPackages:                 splitDebPackages,
+	packagingScriptTemplate:       "{{.fakeroot}}dpkg-deb {{if not .fakeroot}}--root-owner-group {{end}}--build . {{shellquote .packageName \"-\" .version \".deb\"}}",
+	outputFileExtension:           "deb",
+	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
+	outputFileUsesApplicationName: false,
+	smokeTest: &smokeTest{
+		image:  "ubuntu:24.04",
+		script: smokeTestUbuntuLibraries + "apt-get install -y -qq \"$ARTIFACT\" >/dev/null && smoke_run {{shellquote .executableName}}",
+	},
+	identity: append([]identityProperty{
+		{"DEBIAN/control", "Package", IdentityPackageName, regexp.MustCompile(`(?m)^Package: *(.*)$`), false},
+	}, desktopFileIdentity("usr/share/applications/{{.executableName}}.desktop")...),
 }
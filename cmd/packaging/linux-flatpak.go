@@ -0,0 +1,40 @@
+package packaging
+
+import (
+	"path/filepath"
+	"regexp"
+)
+
+// LinuxFlatpakTask packaging for linux as flatpak
+var LinuxFlatpakTask = &packagingTask{
+	packagingFormatName: "linux-flatpak",
+	templateFiles: map[string]string{
+		"linux-flatpak/manifest.yml.tmpl": "{{.organizationName}}.{{.packageName}}.yml.tmpl",
+		"linux-flatpak/bin.tmpl":          "files/bin/{{.executableName}}.tmpl",
+		"linux/app.desktop.tmpl":          "files/{{.organizationName}}.{{.packageName}}.desktop.tmpl",
+	},
+	executableFiles: []string{
+		"files/bin/{{.executableName}}",
+	},
+	linuxDesktopFileExecutablePath: "{{.executableName}}",
+	linuxDesktopFileIconPath:       "{{.organizationName}}.{{.packageName}}",
+	buildOutputDirectory:           "files/build",
+	linuxDesktopFile:               "files/{{.organizationName}}.{{.packageName}}.desktop",
+	dbusServiceDirectory:           "files",
+	appstreamDirectory:             "files",
+	appstreamDesktopID:             "{{.organizationName}}.{{.packageName}}.desktop",
+	generateBuildFiles: func(packageName, tmpPath string) {
+		checkAppStreamInstalled("linux-flatpak", filepath.Join(tmpPath, appstreamID(packageName)+".yml"), packageName)
+	},
+	launcherFile:                  "files/bin/{{.executableName}}",
+	packagingScriptTemplate:       "flatpak-builder --force-clean --arch={{shellquote .gnuArch}} --repo=repo build-dir {{shellquote .organizationName \".\" .packageName \".yml\"}} && flatpak build-bundle --arch={{shellquote .gnuArch}} repo {{shellquote .packageName \"-\" .version \".flatpak\"}} {{shellquote .organizationName \".\" .packageName}}",
+	packagingScriptDownloads:      true,
+	outputFileExtension:           "flatpak",
+	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
+	outputFileUsesApplicationName: false,
+	identity: append([]identityProperty{
+		{"{{.organizationName}}.{{.packageName}}.yml", "app-id", IdentityBundleIdentifier, regexp.MustCompile(`(?m)^app-id: *['"]?([^'"\n]*)`), false},
+		{"{{.organizationName}}.{{.packageName}}.yml", "command", IdentityExecutableName, regexp.MustCompile(`(?m)^command: *['"]?([^'"\n]*)`), false},
+	}, desktopFileIdentity("files/{{.organizationName}}.{{.packageName}}.desktop")...),
+}
@@ -0,0 +1,34 @@
+package packaging
+
+import "regexp"
+
+// LinuxKioskTask packaging for linux as a deb running the app fullscreen in a kiosk session
+var LinuxKioskTask = &packagingTask{
+	packagingFormatName: "linux-kiosk",
+	templateFiles: map[string]string{
+		"linux-kiosk/control.tmpl":       "DEBIAN/control.tmpl",
+		"linux-kiosk/postinst.tmpl":      "DEBIAN/postinst.tmpl",
+		"linux-kiosk/prerm.tmpl":         "DEBIAN/prerm.tmpl",
+		"linux-kiosk/kiosk.service.tmpl": "lib/systemd/system/{{.packageName}}-kiosk.service.tmpl",
+		"linux-kiosk/pam.tmpl":           "etc/pam.d/{{.packageName}}-kiosk.tmpl",
+		"linux/bin.tmpl":                 "usr/bin/{{.executableName}}.tmpl",
+	},
+	executableFiles: []string{
+		"DEBIAN/postinst",
+		"DEBIAN/prerm",
+		"usr/bin/{{.executableName}}",
+	},
+	linuxDesktopFileExecutablePath: "/usr/lib/{{.packageName}}/{{.executableName}}",
+	linuxDesktopFileIconPath:       "/usr/lib/{{.packageName}}/assets/icon.png",
+	buildOutputDirectory:           "usr/lib/{{.packageName}}",
+	launcherFile:                   "usr/bin/{{.executableName}}",
+	generateBuildFiles:             assertTemplateArch("linux-kiosk", "DEBIAN/control", debArchitecture, false),
+	packagingScriptTemplate:        "{{.fakeroot}}dpkg-deb {{if not .fakeroot}}--root-owner-group {{end}}--build . {{shellquote .packageName \"-\" .version \".deb\"}}",
+	outputFileExtension:            "deb",
+	outputFileContainsVersion:      true,
+	outputFileContainsArch:         true,
+	outputFileUsesApplicationName:  false,
+	identity: []identityProperty{
+		{"DEBIAN/control", "Package", IdentityPackageName, regexp.MustCompile(`(?m)^Package: *(.*)$`), false},
+	},
+}
@@ -0,0 +1,28 @@
+package packaging
+
+import "regexp"
+
+// LinuxNixTask packaging for linux as a tar.gz of a nix derivation and flake
+// installing the build output
+var LinuxNixTask = &packagingTask{
+	packagingFormatName: "linux-nix",
+	templateFiles: map[string]string{
+		"linux-nix/default.nix.tmpl": "{{.packageName}}-{{.version}}/default.nix.tmpl",
+		"linux-nix/flake.nix.tmpl":   "{{.packageName}}-{{.version}}/flake.nix.tmpl",
+		"linux/app.desktop.tmpl":     "{{.packageName}}-{{.version}}/{{.executableName}}.desktop.tmpl",
+	},
+	// the derivation installs the executable in the bin directory of the
+	// profile, and the icon in its hicolor theme
+	linuxDesktopFileExecutablePath: "{{.executableName}}",
+	linuxDesktopFileIconPath:       "{{.packageName}}",
+	buildOutputDirectory:           "{{.packageName}}-{{.version}}/app",
+	packagingScriptTemplate:        "tar --owner=0 --group=0 --mode=u+rwX,go+rX,go-w -czf {{shellquote .packageName \"-\" .version \".tar.gz\"}} {{shellquote .packageName \"-\" .version}}",
+	outputFileExtension:            "tar.gz",
+	outputFileContainsVersion:      true,
+	outputFileContainsArch:         true,
+	outputFileUsesApplicationName:  false,
+	identity: append([]identityProperty{
+		{"{{.packageName}}-{{.version}}/default.nix", "pname", IdentityPackageName, regexp.MustCompile(`(?m)^\s*pname = "(.*?)";`), false},
+		{"{{.packageName}}-{{.version}}/default.nix", "mainProgram", IdentityExecutableName, regexp.MustCompile(`(?m)^\s*mainProgram = "(.*?)";`), false},
+	}, desktopFileIdentity("{{.packageName}}-{{.version}}/{{.executableName}}.desktop")...),
+}
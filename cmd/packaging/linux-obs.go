@@ -0,0 +1,126 @@
+package packaging
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/otiai10/copy"
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+)
+
+// obsTarballScript merges the BUILD directory of the linux-rpm package with
+// the files its templates write to BUILDROOT, and archives them. The spec
+// installs the extracted tarball like rpmbuild installs the BUILD directory.
+const obsTarballScript = "cp -R {{shellquote \"BUILDROOT/\" .packageName \"-\" .version \"-\" .release \".\" .gnuArch \"/.\"}} {{shellquote \"BUILD/\" .packageName \"-\" .version \"-\" .release \".\" .gnuArch \"/\"}} && tar --owner=0 --group=0 --mode=u+rwX,go+rX,go-w -czf {{shellquote .packageName \"-\" .version \".tar.gz\"}} -C BUILD {{shellquote .packageName \"-\" .version \"-\" .release \".\" .gnuArch}}"
+
+// obsSpecSectionPattern matches the sections of a spec the OBS preamble and
+// the prep section are added before.
+var obsSpecSectionPattern = regexp.MustCompile(`(?m)^%(description|prep|install)\b`)
+
+// WriteObsProject writes an Open Build Service package of the app to dir,
+// from the linux-rpm templates and the linux build output: the spec, the
+// tarball it builds from and a _service file verifying the tarball. When
+// sourceURL is set, the tarball is downloaded by OBS from sourceURL instead
+// of being uploaded. It returns the names of the files.
+func WriteObsProject(dir, buildVersion, sourceURL string) ([]string, error) {
+	projectName := pubspec.GetPubSpec().Name
+	// the spec requires the data package
+	if dataPackageName(projectName) != "" {
+		return nil, errors.New("the data of split-packages in go/hover.yaml isn't supported in OBS packages")
+	}
+	data := LinuxRpmTask.getTemplateData(projectName, buildVersion)
+	tmpPath := getTemporaryBuildDirectory(projectName, "linux-obs")
+	defer os.RemoveAll(tmpPath)
+	LinuxRpmTask.stage(tmpPath, projectName, buildVersion)
+	runPackaging(tmpPath, executeStringTemplate("linux-obs tarball script", obsTarballScript, data))
+
+	tarballName := data["packageName"] + "-" + data["version"] + ".tar.gz"
+	source := tarballName
+	if sourceURL != "" {
+		source = strings.TrimSuffix(sourceURL, "/") + "/" + tarballName
+	}
+	specName := data["packageName"] + ".spec"
+	spec, err := ioutil.ReadFile(filepath.Join(tmpPath, "SPECS", specName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", specName)
+	}
+	obsSpec, err := obsSpec(string(spec), source, data["packageName"]+"-"+data["version"]+"-"+data["release"]+"."+data["gnuArch"], data["gnuArch"])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", specName)
+	}
+	tarballSha256, err := fileutils.SHA256File(filepath.Join(tmpPath, tarballName))
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, specName), []byte(obsSpec), 0644)
+	if err != nil {
+		return nil, err
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, "_service"), []byte(obsService(tarballName, tarballSha256, sourceURL != "")), 0644)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{specName, "_service"}
+	if sourceURL == "" {
+		err = copy.Copy(filepath.Join(tmpPath, tarballName), filepath.Join(dir, tarballName))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, tarballName)
+	}
+	return files, nil
+}
+
+// obsSpec returns the spec of the linux-rpm templates building from the
+// tarball extracted to buildDir: the source and the architecture of the
+// build output are declared before the description, and a prep section
+// extracting the tarball is added before the install section. The debug
+// package is disabled, the executable is already built.
+func obsSpec(spec, source, buildDir, arch string) (string, error) {
+	sections := obsSpecSectionPattern.FindAllStringSubmatchIndex(spec, -1)
+	description, install := -1, -1
+	for _, section := range sections {
+		switch spec[section[2]:section[3]] {
+		case "description":
+			if description == -1 {
+				description = section[0]
+			}
+		case "prep":
+			return "", errors.New("the %prep section extracting the tarball is added for OBS, the template can't have one")
+		case "install":
+			install = section[0]
+		}
+	}
+	if description == -1 || install == -1 {
+		return "", errors.New("the %description and %install sections are required")
+	}
+	preamble := fmt.Sprintf("Source0: %s\nExclusiveArch: %s\n\n", source, arch)
+	prep := fmt.Sprintf("%%prep\n%%setup -q -n %s\n\n", buildDir)
+	macros := "%global debug_package %{nil}\n%define _build_id_links none\n%define _unpackaged_files_terminate_build 0\n"
+	return macros + spec[:description] + preamble + spec[description:install] + prep + spec[install:], nil
+}
+
+// obsService returns the _service file of the OBS package, verifying the
+// checksum of the tarball after downloading the sources of the spec.
+func obsService(tarballName, sha256 string, download bool) string {
+	var services strings.Builder
+	services.WriteString("<services>\n")
+	if download {
+		services.WriteString("  <service name=\"download_files\"/>\n")
+	}
+	fmt.Fprintf(&services, "  <service name=\"verify_file\">\n    <param name=\"file\">%s</param>\n    <param name=\"verifier\">sha256</param>\n    <param name=\"checksum\">%s</param>\n  </service>\n", fileutils.XMLEscape(tarballName), sha256)
+	services.WriteString("</services>\n")
+	return services.String()
+}
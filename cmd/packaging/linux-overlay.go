@@ -0,0 +1,62 @@
+package packaging
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// LinuxOverlayTask packaging for linux as a rootfs overlay to add to images
+// built with pi-gen, Yocto, debos, ...
+// NOTE: the overlay contains the linux build of the target architecture, the
+// image must be built for the same architecture.
+var LinuxOverlayTask = &packagingTask{
+	packagingFormatName: "linux-overlay",
+	templateFiles: map[string]string{
+		"linux-kiosk/kiosk.service.tmpl":   "lib/systemd/system/{{.packageName}}-kiosk.service.tmpl",
+		"linux-kiosk/pam.tmpl":             "etc/pam.d/{{.packageName}}-kiosk.tmpl",
+		"linux-overlay/sysusers.conf.tmpl": "usr/lib/sysusers.d/{{.packageName}}.conf.tmpl",
+		"linux-overlay/tmpfiles.conf.tmpl": "usr/lib/tmpfiles.d/{{.packageName}}.conf.tmpl",
+		"linux/bin.tmpl":                   "usr/bin/{{.executableName}}.tmpl",
+		"linux/app.desktop.tmpl":           "usr/share/applications/{{.executableName}}.desktop.tmpl",
+	},
+	executableFiles: []string{
+		"usr/bin/{{.executableName}}",
+		"usr/share/applications/{{.executableName}}.desktop",
+	},
+	linuxDesktopFileExecutablePath: "/usr/lib/{{.packageName}}/{{.executableName}}",
+	linuxDesktopFileIconPath:       "/usr/lib/{{.packageName}}/assets/icon.png",
+	buildOutputDirectory:           "usr/lib/{{.packageName}}",
+	launcherFile:                   "usr/bin/{{.executableName}}",
+	packagingScriptTemplate:        "tar --owner=0 --group=0 --numeric-owner -czf {{shellquote .packageName \"-\" .version \".tar.gz\"}} etc lib usr",
+	outputFileExtension:            "tar.gz",
+	outputFileContainsVersion:      true,
+	outputFileContainsArch:         true,
+	outputFileUsesApplicationName:  false,
+	// The overlay is applied to an image that isn't running, so the kiosk
+	// session is enabled with the symlinks `systemctl enable` would create.
+	generateBuildFiles: func(packageName, tmpPath string) {
+		service := "/lib/systemd/system/" + packageName + "-kiosk.service"
+		links := map[string]string{
+			"etc/systemd/system/graphical.target.wants/" + packageName + "-kiosk.service": service,
+			"etc/systemd/system/display-manager.service":                                  service,
+			"etc/systemd/system/default.target":                                           "/lib/systemd/system/graphical.target",
+			"etc/systemd/system/getty@tty1.service":                                       "/dev/null",
+		}
+		for link, target := range links {
+			linkPath := filepath.Join(tmpPath, link)
+			err := os.MkdirAll(filepath.Dir(linkPath), 0755)
+			if err != nil {
+				log.Errorf("Failed to create directory %s: %v", filepath.Dir(linkPath), err)
+				os.Exit(1)
+			}
+			err = os.Symlink(target, linkPath)
+			if err != nil {
+				log.Errorf("Failed to create symlink %s: %v", link, err)
+				os.Exit(1)
+			}
+		}
+	},
+	identity: desktopFileIdentity("usr/share/applications/{{.executableName}}.desktop"),
+}
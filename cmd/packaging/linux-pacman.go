@@ -0,0 +1,41 @@
+package packaging
+
+import "regexp"
+
+// LinuxPacmanTask packaging for linux as a pacman package with install
+// scriptlets, compressed with zstd
+var LinuxPacmanTask = &packagingTask{
+	packagingFormatName: "linux-pacman",
+	templateFiles: map[string]string{
+		"linux-pacman/PKGBUILD.tmpl":    "PKGBUILD.tmpl",
+		"linux-pacman/app.install.tmpl": "{{.packageName}}.install.tmpl",
+		"linux/bin.tmpl":                "src/usr/bin/{{.executableName}}.tmpl",
+		"linux/app.desktop.tmpl":        "src/usr/share/applications/{{.executableName}}.desktop.tmpl",
+	},
+	executableFiles: []string{
+		"src/usr/bin/{{.executableName}}",
+		"src/usr/share/applications/{{.executableName}}.desktop",
+	},
+	linuxDesktopFileExecutablePath: "/usr/lib/{{.packageName}}/{{.executableName}}",
+	linuxDesktopFileIconPath:       "/usr/lib/{{.packageName}}/assets/icon.png",
+	buildOutputDirectory:           "src/usr/lib/{{.packageName}}",
+	gsettingsSchemaDirectory:       "src/usr/share/glib-2.0/schemas",
+	fileAssociationsDirectory:      "src/usr/share",
+	linuxDesktopFile:               "src/usr/share/applications/{{.executableName}}.desktop",
+	dbusServiceDirectory:           "src/usr/share/dbus-1/services",
+	launcherFile:                   "src/usr/bin/{{.executableName}}",
+	generateBuildFiles:             assertTemplateArch("linux-pacman", "PKGBUILD", pkgbuildArchitecture, true),
+	packagingScriptTemplate:        "CARCH={{shellquote .gnuArch}} PKGEXT=.pkg.tar.zst makepkg && mv -n {{shellquote .packageName \"-\" .version \"-\" .release \"-\" .gnuArch \".pkg.tar.zst\"}} {{shellquote .packageName \"-\" .version \".pkg.tar.zst\"}}",
+	outputFileExtension:            "pkg.tar.zst",
+	outputFileContainsVersion:      true,
+	outputFileContainsArch:         true,
+	outputFileUsesApplicationName:  false,
+	smokeTest: &smokeTest{
+		image:  "archlinux:latest",
+		script: "pacman -Sy --noconfirm --needed xorg-server-xvfb xorg-xauth mesa libx11 libxrandr libxcursor libxinerama libxi libxxf86vm >/dev/null && pacman -U --noconfirm \"$ARTIFACT\" && smoke_run {{shellquote .executableName}}",
+	},
+	identity: append([]identityProperty{
+		{"PKGBUILD", "pkgname", IdentityPackageName, regexp.MustCompile(`(?m)^pkgname=['"]?([^'"\n]*)`), false},
+		{"PKGBUILD", "install", IdentityPackageName, regexp.MustCompile(`(?m)^install=['"]?([^'"\n]*)\.install`), false},
+	}, desktopFileIdentity("src/usr/share/applications/{{.executableName}}.desktop")...),
+}
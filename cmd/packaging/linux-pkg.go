@@ -1,5 +1,7 @@
 package packaging
 
+import "regexp"
+
 // LinuxPkgTask packaging for linux as pacman pkg
 var LinuxPkgTask = &packagingTask{
 	packagingFormatName: "linux-pkg",
@@ -15,8 +17,18 @@ var LinuxPkgTask = &packagingTask{
 	linuxDesktopFileExecutablePath: "/usr/lib/{{.packageName}}/{{.executableName}}",
 	linuxDesktopFileIconPath:       "/usr/lib/{{.packageName}}/assets/icon.png",
 	buildOutputDirectory:           "src/usr/lib/{{.packageName}}",
-	packagingScriptTemplate:        "makepkg && mv -n {{.packageName}}-{{.version}}-{{.release}}-x86_64.pkg.tar.xz {{.packageName}}-{{.version}}.pkg.tar.xz",
+	gsettingsSchemaDirectory:       "src/usr/share/glib-2.0/schemas",
+	fileAssociationsDirectory:      "src/usr/share",
+	linuxDesktopFile:               "src/usr/share/applications/{{.executableName}}.desktop",
+	dbusServiceDirectory:           "src/usr/share/dbus-1/services",
+	launcherFile:                   "src/usr/bin/{{.executableName}}",
+	generateBuildFiles:             assertTemplateArch("linux-pkg", "PKGBUILD", pkgbuildArchitecture, true),
+	packagingScriptTemplate:        "CARCH={{shellquote .gnuArch}} makepkg && mv -n {{shellquote .packageName \"-\" .version \"-\" .release \"-\" .gnuArch \".pkg.tar.xz\"}} {{shellquote .packageName \"-\" .version \".pkg.tar.xz\"}}",
 	outputFileExtension:            "pkg.tar.xz",
 	outputFileContainsVersion:      true,
+	outputFileContainsArch:         true,
 	outputFileUsesApplicationName:  false,
+	identity: append([]identityProperty{
+		{"PKGBUILD", "pkgname", IdentityPackageName, regexp.MustCompile(`(?m)^pkgname=['"]?([^'"\n]*)`), false},
+	}, desktopFileIdentity("src/usr/share/applications/{{.executableName}}.desktop")...),
 }
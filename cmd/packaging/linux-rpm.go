@@ -1,22 +1,53 @@
 package packaging
 
+import (
+	"path/filepath"
+	"regexp"
+
+	"github.com/go-flutter-desktop/hover/internal/config"
+)
+
 // LinuxRpmTask packaging for linux as rpm
 var LinuxRpmTask = &packagingTask{
 	packagingFormatName: "linux-rpm",
 	templateFiles: map[string]string{
 		"linux-rpm/app.spec.tmpl": "SPECS/{{.packageName}}.spec.tmpl",
-		"linux/bin.tmpl":          "BUILDROOT/{{.packageName}}-{{.version}}-{{.release}}.x86_64/usr/bin/{{.executableName}}.tmpl",
-		"linux/app.desktop.tmpl":  "BUILDROOT/{{.packageName}}-{{.version}}-{{.release}}.x86_64/usr/share/applications/{{.executableName}}.desktop.tmpl",
+		"linux/bin.tmpl":          "BUILDROOT/{{.packageName}}-{{.version}}-{{.release}}.{{.gnuArch}}/usr/bin/{{.executableName}}.tmpl",
+		"linux/app.desktop.tmpl":  "BUILDROOT/{{.packageName}}-{{.version}}-{{.release}}.{{.gnuArch}}/usr/share/applications/{{.executableName}}.desktop.tmpl",
 	},
 	executableFiles: []string{
-		"BUILDROOT/{{.packageName}}-{{.version}}-{{.release}}.x86_64/usr/bin/{{.executableName}}",
-		"BUILDROOT/{{.packageName}}-{{.version}}-{{.release}}.x86_64/usr/share/applications/{{.executableName}}.desktop",
+		"BUILDROOT/{{.packageName}}-{{.version}}-{{.release}}.{{.gnuArch}}/usr/bin/{{.executableName}}",
+		"BUILDROOT/{{.packageName}}-{{.version}}-{{.release}}.{{.gnuArch}}/usr/share/applications/{{.executableName}}.desktop",
 	},
 	linuxDesktopFileExecutablePath: "/usr/lib/{{.packageName}}/{{.executableName}}",
-	linuxDesktopFileIconPath:       "/usr/lib/{{.packageName}}/assets/icon.png",
-	buildOutputDirectory:           "BUILD/{{.packageName}}-{{.version}}-{{.release}}.x86_64/usr/lib/{{.packageName}}",
-	packagingScriptTemplate:        "rpmbuild --define \"_topdir $(pwd)\" --define \"_unpackaged_files_terminate_build 0\" -ba ./SPECS/{{.packageName}}.spec && mv -n RPMS/x86_64/{{.packageName}}-{{.version}}-{{.release}}.x86_64.rpm {{.packageName}}-{{.version}}.rpm",
-	outputFileExtension:            "rpm",
-	outputFileContainsVersion:      true,
-	outputFileUsesApplicationName:  false,
+	linuxDesktopFileIconPath:       "{{.iconName}}",
+	buildOutputDirectory:           "BUILD/{{.packageName}}-{{.version}}-{{.release}}.{{.gnuArch}}/usr/lib/{{.packageName}}",
+	gsettingsSchemaDirectory:       "BUILD/{{.packageName}}-{{.version}}-{{.release}}.{{.gnuArch}}/usr/share/glib-2.0/schemas",
+	fileAssociationsDirectory:      "BUILD/{{.packageName}}-{{.version}}-{{.release}}.{{.gnuArch}}/usr/share",
+	hicolorIconsDirectory:          "BUILD/{{.packageName}}-{{.version}}-{{.release}}.{{.gnuArch}}/usr/share",
+	linuxDesktopFile:               "BUILDROOT/{{.packageName}}-{{.version}}-{{.release}}.{{.gnuArch}}/usr/share/applications/{{.executableName}}.desktop",
+	dbusServiceDirectory:           "BUILD/{{.packageName}}-{{.version}}-{{.release}}.{{.gnuArch}}/usr/share/dbus-1/services",
+	appstreamDirectory:             "BUILD/{{.packageName}}-{{.version}}-{{.release}}.{{.gnuArch}}/usr/share/metainfo",
+	appstreamDesktopID:             "{{.desktopFileName}}.desktop",
+	launcherFile:                   "BUILDROOT/{{.packageName}}-{{.version}}-{{.release}}.{{.gnuArch}}/usr/bin/{{.executableName}}",
+	generateBuildFiles: func(packageName, tmpPath string) {
+		assertTemplateArch("linux-rpm", "SPECS/{{.packageName}}.spec", rpmBuildArchitecture, true)(packageName, tmpPath)
+		checkRelationships("linux-rpm", filepath.Join(tmpPath, "SPECS", packageName+".spec"), rpmRelationshipFields, rpmRelationships(config.GetConfig().Rpm))
+		checkAppStreamInstalled("linux-rpm", filepath.Join(tmpPath, "SPECS", packageName+".spec"), packageName)
+		checkSharedMimeInfoInstalled("linux-rpm", filepath.Join(tmpPath, "SPECS", packageName+".spec"))
+		checkHicolorIconsInstalled("linux-rpm", filepath.Join(tmpPath, "SPECS", packageName+".spec"), "icons/hicolor/*/apps/"+appstreamID(packageName))
+	},
+	splitPackages:                 splitRpmPackages,
+	packagingScriptTemplate:       "{{.fakeroot}}rpmbuild --define \"_topdir $(pwd)\" --define \"_unpackaged_files_terminate_build 0\" --target {{shellquote .gnuArch}} -ba {{shellquote \"./SPECS/\" .packageName \".spec\"}} && mv -n {{shellquote \"RPMS/\" .gnuArch \"/\" .packageName \"-\" .version \"-\" .release \".\" .gnuArch \".rpm\"}} {{shellquote .packageName \"-\" .version \".rpm\"}}",
+	outputFileExtension:           "rpm",
+	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
+	outputFileUsesApplicationName: false,
+	smokeTest: &smokeTest{
+		image:  "fedora:latest",
+		script: "dnf install -y -q xorg-x11-server-Xvfb xorg-x11-xauth mesa-libGL mesa-libEGL libX11 libXrandr libXcursor libXinerama libXi libXxf86vm \"$ARTIFACT\" && smoke_run {{shellquote .executableName}}",
+	},
+	identity: append([]identityProperty{
+		{"SPECS/{{.packageName}}.spec", "Name", IdentityPackageName, regexp.MustCompile(`(?m)^Name: *(.*)$`), false},
+	}, desktopFileIdentity("BUILDROOT/{{.packageName}}-{{.version}}-{{.release}}.{{.gnuArch}}/usr/share/applications/{{.executableName}}.desktop")...),
 }
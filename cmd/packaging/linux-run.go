@@ -0,0 +1,36 @@
+package packaging
+
+import "regexp"
+
+// LinuxRunTask packaging for linux as a makeself self-extracting installer,
+// which installs the app in a prefix with its .desktop file
+var LinuxRunTask = &packagingTask{
+	packagingFormatName: "linux-run",
+	templateFiles: map[string]string{
+		"linux-run/install.sh.tmpl":  "{{.packageName}}/install.sh.tmpl",
+		"linux-tar/launcher.sh.tmpl": "{{.packageName}}/{{.executableName}}.tmpl",
+		"linux/app.desktop.tmpl":     "{{.packageName}}/{{.executableName}}.desktop.tmpl",
+	},
+	executableFiles: []string{
+		"{{.packageName}}/install.sh",
+		"{{.packageName}}/{{.executableName}}",
+	},
+	// the install script replaces the paths of the default prefix with the
+	// ones of the install directory
+	linuxDesktopFileExecutablePath: "/opt/{{.packageName}}/{{.executableName}}",
+	linuxDesktopFileIconPath:       "/opt/{{.packageName}}/app/assets/icon.png",
+	buildOutputDirectory:           "{{.packageName}}/app",
+	launcherFile:                   "{{.packageName}}/{{.executableName}}",
+	packagingScriptTemplate:        "makeself --quiet --nox11 {{shellquote .packageName}} {{shellquote .packageName \"-\" .version \".run\"}} {{shellquote .applicationName \" \" .version}} ./install.sh",
+	outputFileExtension:            "run",
+	outputFileContainsVersion:      true,
+	outputFileContainsArch:         true,
+	outputFileUsesApplicationName:  false,
+	smokeTest: &smokeTest{
+		image:  "ubuntu:24.04",
+		script: smokeTestUbuntuLibraries + "sh \"$ARTIFACT\" -- --prefix /opt && smoke_run {{shellquote \"/opt/\" .packageName \"/\" .executableName}}",
+	},
+	identity: append([]identityProperty{
+		{"{{.packageName}}/{{.executableName}}", "executable", IdentityExecutableName, regexp.MustCompile(`exec "\$app_dir"/'?(.*?)'? "\$@"`), false},
+	}, desktopFileIdentity("{{.packageName}}/{{.executableName}}.desktop")...),
+}
@@ -0,0 +1,24 @@
+package packaging
+
+// LinuxRunImageTask packaging for linux as a loader script followed by a
+// squashfs image, which runs without FUSE by extracting the image
+// (experimental)
+var LinuxRunImageTask = &packagingTask{
+	packagingFormatName: "linux-runimage",
+	templateFiles: map[string]string{
+		"linux-runimage/loader.sh.tmpl": "loader.sh.tmpl",
+		"linux-appimage/AppRun.tmpl":    "image/AppRun.tmpl",
+	},
+	executableFiles: []string{
+		"image/AppRun",
+	},
+	buildOutputDirectory: "image/build",
+	launcherFile:         "image/AppRun",
+	// the offset of the image replaces __OFFSET__, padded to keep the size of
+	// the loader
+	packagingScriptTemplate:       "mksquashfs image image.squashfs -root-owned -noappend -comp xz -quiet && size=$(wc -c < loader.sh) && sed \"s/__OFFSET__/$(printf '%-10s' \"$size\")/\" loader.sh > {{shellquote .packageName \"-\" .version \".run\"}} && cat image.squashfs >> {{shellquote .packageName \"-\" .version \".run\"}} && chmod 755 {{shellquote .packageName \"-\" .version \".run\"}}",
+	outputFileExtension:           "run",
+	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
+	outputFileUsesApplicationName: false,
+}
@@ -1,5 +1,10 @@
 package packaging
 
+import (
+	"path/filepath"
+	"regexp"
+)
+
 // LinuxSnapTask packaging for linux as snap
 var LinuxSnapTask = &packagingTask{
 	packagingFormatName: "linux-snap",
@@ -8,10 +13,29 @@ var LinuxSnapTask = &packagingTask{
 		"linux/app.desktop.tmpl":         "snap/local/{{.executableName}}.desktop.tmpl",
 	},
 	linuxDesktopFileExecutablePath: "/{{.executableName}}",
-	linuxDesktopFileIconPath:       "/icon.png",
+	linuxDesktopFileIconPath:       "/usr/share/icons/{{.iconFile}}",
 	buildOutputDirectory:           "build",
-	packagingScriptTemplate:        "snapcraft && mv -n {{.packageName}}_{{.version}}_{{.arch}}.snap {{.packageName}}-{{.version}}.snap",
-	outputFileExtension:            "snap",
-	outputFileContainsVersion:      true,
-	outputFileUsesApplicationName:  false,
+	hicolorIconsDirectory:          "icons/usr/share",
+	appstreamDirectory:             "metainfo/usr/share/metainfo",
+	appstreamDesktopID:             "{{.packageName}}_{{.packageName}}.desktop",
+	generateBuildFiles: func(packageName, tmpPath string) {
+		checkAppStreamInstalled("linux-snap", filepath.Join(tmpPath, "snap", "snapcraft.yaml"), packageName)
+		checkHicolorIconsInstalled("linux-snap", filepath.Join(tmpPath, "snap", "snapcraft.yaml"), "source: icons")
+	},
+	packagingScriptTemplate:       "{{if .snapcraftBuildEnvironment}}SNAPCRAFT_BUILD_ENVIRONMENT={{shellquote .snapcraftBuildEnvironment}} {{end}}snapcraft && mv -n {{shellquote .packageName \"_\" .version \"_\" .arch \".snap\"}} {{shellquote .packageName \"-\" .version \".snap\"}}",
+	packagingScriptDownloads:      true,
+	outputFileExtension:           "snap",
+	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
+	outputFileUsesApplicationName: false,
+	smokeTest: &smokeTest{
+		image: "ubuntu:24.04",
+		// snapd doesn't run in the container, the app is started from the
+		// content of the snap
+		script: smokeTestUbuntuLibraries + "DEBIAN_FRONTEND=noninteractive apt-get install -y -qq squashfs-tools >/dev/null && unsquashfs -q -d /tmp/snap \"$ARTIFACT\" && smoke_run {{shellquote \"/tmp/snap/\" .executableName}}",
+	},
+	identity: append([]identityProperty{
+		{"snap/snapcraft.yaml", "name", IdentityPackageName, regexp.MustCompile(`(?m)^name: *['"]?([^'"\n]*)`), false},
+		{"snap/snapcraft.yaml", "command", IdentityExecutableName, regexp.MustCompile(`(?m)^ +command: *(?:.*/)?([^/\n]+)$`), false},
+	}, desktopFileIdentity("snap/local/{{.executableName}}.desktop")...),
 }
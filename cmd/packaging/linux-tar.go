@@ -0,0 +1,29 @@
+package packaging
+
+import "regexp"
+
+// LinuxTarTask packaging for linux as a tar.gz archive of a folder to extract
+// anywhere
+var LinuxTarTask = &packagingTask{
+	packagingFormatName: "linux-tar",
+	templateFiles: map[string]string{
+		"linux-tar/launcher.sh.tmpl": "{{.packageName}}-{{.version}}/{{.executableName}}.tmpl",
+	},
+	executableFiles: []string{
+		"{{.packageName}}-{{.version}}/{{.executableName}}",
+	},
+	buildOutputDirectory:          "{{.packageName}}-{{.version}}/app",
+	launcherFile:                  "{{.packageName}}-{{.version}}/{{.executableName}}",
+	packagingScriptTemplate:       "tar --owner=0 --group=0 --mode=u+rwX,go+rX,go-w -czf {{shellquote .packageName \"-\" .version \".tar.gz\"}} {{shellquote .packageName \"-\" .version}}",
+	outputFileExtension:           "tar.gz",
+	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
+	outputFileUsesApplicationName: false,
+	smokeTest: &smokeTest{
+		image:  "ubuntu:24.04",
+		script: smokeTestUbuntuLibraries + "tar -xzf \"$ARTIFACT\" -C /tmp && smoke_run {{shellquote \"/tmp/\" .packageName \"-\" .version \"/\" .executableName}}",
+	},
+	identity: []identityProperty{
+		{"{{.packageName}}-{{.version}}/{{.executableName}}", "executable", IdentityExecutableName, regexp.MustCompile(`exec "\$app_dir"/'?(.*?)'? "\$@"`), false},
+	},
+}
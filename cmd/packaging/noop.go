@@ -1,11 +1,20 @@
 package packaging
 
+import "time"
+
 type noopTask struct{}
 
 var NoopTask Task = &noopTask{}
 
-func (_ *noopTask) Name() string             { return "" }
-func (_ *noopTask) Init()                    {}
-func (_ *noopTask) IsInitialized() bool      { return true }
-func (_ *noopTask) AssertInitialized()       {}
-func (_ *noopTask) Pack(buildVersion string) {}
+func (_ *noopTask) Name() string                                 { return "" }
+func (_ *noopTask) Init()                                        {}
+func (_ *noopTask) IsInitialized() bool                          { return true }
+func (_ *noopTask) AssertInitialized()                           {}
+func (_ *noopTask) Pack(buildVersion string)                     {}
+func (_ *noopTask) Upgrade()                                     {}
+func (_ *noopTask) Identity(buildVersion string) []IdentityValue { return nil }
+func (_ *noopTask) RenameIdentity(buildVersion string, renamed map[string]string) map[string][]byte {
+	return nil
+}
+func (_ *noopTask) Lint(buildVersion string) []LintProblem                  { return nil }
+func (_ *noopTask) Verify(buildVersion string, timeout time.Duration) error { return ErrNoSmokeTest }
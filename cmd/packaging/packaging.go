@@ -3,23 +3,26 @@ package packaging
 import (
 	"bytes"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
-	"text/template"
 
 	"github.com/otiai10/copy"
 
 	"github.com/go-flutter-desktop/hover/internal/androidmanifest"
 	"github.com/go-flutter-desktop/hover/internal/build"
 	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/explain"
 	"github.com/go-flutter-desktop/hover/internal/fileutils"
 	"github.com/go-flutter-desktop/hover/internal/log"
 	"github.com/go-flutter-desktop/hover/internal/pubspec"
+	"github.com/go-flutter-desktop/hover/internal/retry"
+	"github.com/go-flutter-desktop/hover/internal/timings"
 )
 
 var packagingPath = filepath.Join(build.BuildPath, "packaging")
@@ -45,44 +48,110 @@ func createPackagingFormatDirectory(packagingFormat string) {
 	}
 }
 
+// getTemporaryBuildDirectory returns an empty directory named after the
+// project, packaging format, architecture and user, so the builds of other
+// architectures and users sharing the temporary directory don't replace it.
+// The directory of a failed build is kept to be debugged, it is replaced by
+// the next build.
 func getTemporaryBuildDirectory(projectName string, packagingFormat string) string {
-	tmpPath, err := ioutil.TempDir("", "hover-build-"+projectName+"-"+packagingFormat)
+	name := "hover-build-" + projectName + "-" + packagingFormat + "-" + build.TargetArch()
+	// the uid is -1 on windows, where the temporary directory is per user
+	if uid := os.Getuid(); uid >= 0 {
+		name += "-" + strconv.Itoa(uid)
+	}
+	tmpPath := filepath.Join(config.GetConfig().GetTmpDir(), name)
+	err := os.RemoveAll(tmpPath)
+	if err != nil {
+		log.Errorf("Couldn't clean temporary build directory %s: %v", tmpPath, err)
+		os.Exit(1)
+	}
+	err = os.MkdirAll(tmpPath, 0775)
 	if err != nil {
-		log.Errorf("Couldn't get temporary build directory: %v", err)
+		log.Errorf("Couldn't create temporary build directory %s: %v", tmpPath, err)
 		os.Exit(1)
 	}
 	return tmpPath
 }
 
 func runPackaging(path string, command string) {
-	bashCmd := exec.Command("bash", "-c", command)
-	bashCmd.Stderr = os.Stderr
-	bashCmd.Stdout = os.Stdout
-	bashCmd.Dir = path
-	err := bashCmd.Run()
+	runPackagingScript(path, command, false)
+}
+
+// transientNetworkErrors are printed by the packaging tools failing on a
+// network error.
+var transientNetworkErrors = []string{
+	"Could not resolve host",
+	"Temporary failure in name resolution",
+	"Connection timed out",
+	"Connection reset by peer",
+	"Connection refused",
+	"TLS handshake timeout",
+	"i/o timeout",
+	"502 Bad Gateway",
+	"503 Service Unavailable",
+	"504 Gateway Timeout",
+}
+
+// runPackagingScript runs a packaging script. The scripts downloading the
+// dependencies of the packaging tools are retried when they fail on a
+// network error.
+func runPackagingScript(path string, command string, downloads bool) {
+	var bashCmd *exec.Cmd
+	stopTiming := timings.Start(timings.PackagingScript)
+	err := retry.Do("The packaging script", func() error {
+		var output bytes.Buffer
+		bashCmd = exec.Command("bash", "-c", command)
+		bashCmd.Stderr = os.Stderr
+		bashCmd.Stdout = os.Stdout
+		if downloads {
+			bashCmd.Stderr = io.MultiWriter(os.Stderr, &output)
+			bashCmd.Stdout = io.MultiWriter(os.Stdout, &output)
+		}
+		bashCmd.Dir = path
+		err := bashCmd.Run()
+		if err != nil && !isTransientNetworkError(output.String()) {
+			return retry.Permanent(err)
+		}
+		return err
+	})
+	stopTiming()
 	if err != nil {
 		log.Warnf("Packaging is very experimental and has only been tested on Linux.")
 		log.Infof("To help us debuging this error, please zip the content of:\n       \"%s\"\n       %s",
 			log.Au().Blue(path),
 			log.Au().Green("and try to package on another OS. You can also share this zip with the go-flutter team."))
 		log.Infof("You can package the app without hover by running:")
-		log.Infof("  `%s`", log.Au().Magenta("cd "+path))
+		log.Infof("  `%s`", log.Au().Magenta("cd \""+path+"\""))
 		log.Infof("  executed command: `%s`", log.Au().Magenta(bashCmd.String()))
+		log.ErrorCodef(explain.PackagingFailed, "Packaging failed: %v", err)
 		os.Exit(1)
 	}
 }
 
+func isTransientNetworkError(output string) bool {
+	for _, message := range transientNetworkErrors {
+		if strings.Contains(output, message) {
+			return true
+		}
+	}
+	return false
+}
+
 var templateData map[string]string
 var once sync.Once
 
-func (t *packagingTask) getTemplateData(projectName, buildVersion string) map[string]string {
+// TemplateData returns the template data shared by the packaging formats,
+// including the template-data of go/hover.yaml.
+func TemplateData(projectName, buildVersion string) map[string]string {
 	once.Do(func() {
 		templateData = map[string]string{
 			"projectName":      projectName,
 			"version":          buildVersion,
 			"release":          strings.Split(buildVersion, ".")[0],
-			"arch":             runtime.GOARCH,
+			"arch":             build.TargetArch(),
+			"gnuArch":          build.TargetGnuArch(),
 			"description":      pubspec.GetPubSpec().GetDescription(),
+			"homepage":         pubspec.GetPubSpec().Homepage,
 			"organizationName": androidmanifest.AndroidOrganizationName(),
 			"author":           pubspec.GetPubSpec().GetAuthor(),
 			"applicationName":  config.GetConfig().GetApplicationName(projectName),
@@ -90,10 +159,132 @@ func (t *packagingTask) getTemplateData(projectName, buildVersion string) map[st
 			"packageName":      config.GetConfig().GetPackageName(projectName),
 			"license":          config.GetConfig().GetLicense(),
 		}
-		templateData["iconPath"] = executeStringTemplate(t.linuxDesktopFileIconPath, templateData)
-		templateData["executablePath"] = executeStringTemplate(t.linuxDesktopFileExecutablePath, templateData)
+		templateData["firstRunURL"], templateData["uninstallURL"] = config.GetConfig().GetSurveyURLs()
+		templateData["preferencesID"] = PreferencesID(projectName)
+		if len(config.GetConfig().Preferences) > 0 {
+			templateData["gsettingsSchema"] = templateData["preferencesID"]
+		} else {
+			templateData["gsettingsSchema"] = ""
+		}
+		templateData["displayServer"] = config.GetConfig().GetDisplayServer()
+		templateData["launcherSetup"] = launcherSetup(config.GetConfig().Launcher, templateData["displayServer"])
+		templateData["launcherSetupCmd"] = launcherSetupCmd(config.GetConfig().Launcher)
+		templateData["wmClass"] = config.GetConfig().GetWMClass(projectName)
+		templateData["startupNotify"] = strconv.FormatBool(config.GetConfig().StartupNotify)
+		for key, value := range fileAssociationsData(templateData["packageName"]) {
+			templateData[key] = value
+		}
+		templateData["urlSchemes"] = strings.Join(urlSchemes(), " ")
+		desktop := config.GetConfig().Desktop
+		desktop.MimeType = desktopMimeTypes(desktop.MimeType)
+		for key, value := range desktopEntryData(desktop) {
+			templateData[key] = value
+		}
+		templateData["dataPackageName"] = dataPackageName(templateData["packageName"])
+		templateData["appstreamID"] = appstreamID(templateData["packageName"])
+		templateData["iconName"] = templateData["appstreamID"]
+		for field, relationships := range debRelationships(config.GetConfig().Deb) {
+			templateData["deb"+field] = strings.Join(relationships, ", ")
+		}
+		for field, relationships := range rpmRelationships(config.GetConfig().Rpm) {
+			templateData["rpm"+field] = strings.Join(relationships, ", ")
+		}
+		templateData["msixVersion"] = msixVersion(buildVersion)
+		templateData["chocolateyVersion"] = chocolateyVersion(buildVersion)
+		templateData["apkVersion"] = apkVersion(buildVersion)
+		templateData["flatpakRuntime"], templateData["flatpakSdk"], templateData["flatpakRuntimeVersion"] = config.GetConfig().GetFlatpakRuntime()
+		templateData["innoPrivilegesRequired"], templateData["innoPrivilegesOverridesAllowed"] = config.GetConfig().GetInnoPrivileges()
+		innoStartMenu, innoDesktop := config.GetConfig().GetInnoShortcuts()
+		templateData["innoStartMenuShortcut"] = strconv.FormatBool(innoStartMenu)
+		templateData["innoDesktopShortcut"] = strconv.FormatBool(innoDesktop)
+		templateData["innoAppMutex"] = config.GetConfig().Inno.AppMutex
+		templateData["singleInstance"] = strconv.FormatBool(config.GetConfig().SingleInstance)
+		if templateData["innoAppMutex"] == "" && config.GetConfig().SingleInstance {
+			// the mutex held by the first instance
+			templateData["innoAppMutex"] = SingleInstanceID(projectName)
+		}
+		templateData["dbusName"] = ""
+		templateData["desktopFileName"] = templateData["executableName"]
+		if config.GetConfig().DBusActivatable {
+			templateData["dbusName"] = DBusName(projectName)
+			templateData["desktopFileName"] = templateData["dbusName"]
+		}
+		templateData["wingetPublisher"] = config.GetConfig().GetWingetPublisher(templateData["author"])
+		templateData["wingetPackageIdentifier"] = config.GetConfig().GetWingetPackageIdentifier(templateData["author"], templateData["applicationName"])
+		templateData["wingetInstallerUrl"] = executeStringTemplate("winget installer-url", config.GetConfig().Winget.InstallerURL, templateData)
+		templateData["scoopUrl"] = executeStringTemplate("scoop url", config.GetConfig().Scoop.URL, templateData)
+		templateData["scoopHomepage"] = config.GetConfig().Scoop.Homepage
+		templateData["homebrewUrl"] = executeStringTemplate("homebrew url", config.GetConfig().Homebrew.URL, templateData)
+		templateData["homebrewHomepage"] = config.GetConfig().Homebrew.Homepage
+		templateData["appstoreApplicationIdentity"] = config.GetConfig().AppStore.ApplicationIdentity
+		templateData["appstoreInstallerIdentity"] = config.GetConfig().AppStore.InstallerIdentity
+		templateData["makeselfPrefix"] = config.GetConfig().Makeself.Prefix
+		templateData["makeselfDesktopIntegration"] = strconv.FormatBool(config.GetConfig().GetMakeselfDesktopIntegration())
+		templateData["appstoreTeamID"] = config.GetConfig().GetAppStoreTeamID()
+		templateData["appstoreCategory"] = config.GetConfig().GetAppStoreCategory()
+		var launchdLabels []string
+		for _, job := range config.GetConfig().Launchd {
+			launchdLabels = append(launchdLabels, job.Label)
+		}
+		templateData["launchdJobs"] = strings.Join(launchdLabels, " ")
+		for key, value := range rootlessTemplateData() {
+			templateData[key] = value
+		}
+		for key, value := range config.GetConfig().TemplateData {
+			if _, ok := templateData[key]; ok {
+				log.Warnf("The template-data %s is ignored, it is set by hover.", key)
+				continue
+			}
+			templateData[key] = value
+		}
 	})
-	return templateData
+	data := make(map[string]string, len(templateData)+3)
+	for key, value := range templateData {
+		data[key] = value
+	}
+	return data
+}
+
+func (t *packagingTask) getTemplateData(projectName, buildVersion string) map[string]string {
+	// the paths depend on the packaging format
+	data := TemplateData(projectName, buildVersion)
+	data["iconSourcePath"], _ = config.GetConfig().GetIcon(t.packagingFormatName)
+	// the largest icon of the hicolor icon theme, for the packages referring
+	// to it by path
+	data["iconFile"] = ""
+	if files := hicolorIconFiles(data["iconSourcePath"], data["iconName"]); len(files) > 0 {
+		data["iconFile"] = files[len(files)-1]
+	}
+	data["iconPath"] = executeStringTemplate(t.packagingFormatName+" icon path", t.linuxDesktopFileIconPath, data)
+	data["executablePath"] = executeStringTemplate(t.packagingFormatName+" executable path", t.linuxDesktopFileExecutablePath, data)
+	if t.dbusServiceDirectory == "" {
+		// the package doesn't install a DBus service activating the app
+		data["dbusName"] = ""
+		data["desktopFileName"] = data["executableName"]
+	}
+	data["crashReportsDir"] = crashReportsDir(strings.Split(t.packagingFormatName, "-")[0], projectName)
+	for key, value := range t.templateData {
+		data[key] = value
+	}
+	return data
+}
+
+// crashReportsDir returns the directory the crash handler of the app writes
+// the crash reports to on a target OS, with the environment variables of the
+// platform, e.g. for the uninstallers to remove it. It is empty when the app
+// has no crash handler.
+func crashReportsDir(targetOS, projectName string) string {
+	if !fileutils.IsFileExists(filepath.Join(build.BuildPath, "cmd", "crashhandler.go")) {
+		return ""
+	}
+	switch targetOS {
+	case "windows":
+		return `%LOCALAPPDATA%\` + projectName + `\crashes`
+	case "darwin":
+		return "$HOME/Library/Logs/" + projectName + "/crashes"
+	default:
+		return "${XDG_STATE_HOME:-$HOME/.local/state}/" + projectName + "/crashes"
+	}
 }
 
 type packagingTask struct {
@@ -105,17 +296,27 @@ type packagingTask struct {
 	linuxDesktopFileIconPath       string                         // Path of the icon for linux .desktop file (only set on linux)
 	generateBuildFiles             func(packageName, path string) // Generate dynamic build files. Operates in the temporary directory
 	buildOutputDirectory           string                         // Path to copy the build output of the app to. Operates in the temporary directory
+	templateData                   map[string]string              // Template data of the packaging format only
+	gsettingsSchemaDirectory       string                         // Path to write the GSettings schema of the preferences to. Operates in the temporary directory
+	linuxDesktopFile               string                         // Path of the .desktop file, named after the DBus name when the app is DBus activatable. Operates in the temporary directory
+	dbusServiceDirectory           string                         // Path to write the DBus service of the DBus activatable app to. Operates in the temporary directory
+	appstreamDirectory             string                         // Path to write the AppStream metainfo to. Operates in the temporary directory
+	appstreamDesktopID             string                         // Name of the .desktop file once installed, the launchable of the AppStream metainfo
+	fileAssociationsDirectory      string                         // Path of the share directory to write the shared-mime-info package and the icons of the file associations to. Operates in the temporary directory
+	hicolorIconsDirectory          string                         // Path of the share directory to write the icon of the app to, scaled to the sizes of the hicolor icon theme. Operates in the temporary directory
+	launcherFile                   string                         // Path of the script starting the app, replaced by the launcher template of go/hover.yaml. Operates in the temporary directory
+	splitPackages                  splitPackagesFunc              // Builds the companion packages of the split-packages configuration (deb and rpm only)
 	packagingScriptTemplate        string                         // Template for the command that actually packages the app
+	packagingScriptDownloads       bool                           // Whether the packaging script downloads the dependencies of the packaging tool (runtimes, build bases), it's retried when it fails on a network error
+	windowsPackaging               windowsPackagingFunc           // Packages the app on windows hosts instead of the packaging script, which needs bash
 	outputFileExtension            string                         // File extension of the packaged app
-	// NOTE: outputFileContainsVersion is currently always true, we could
-	// consider adding a flag for it to let users disable it.
-	outputFileContainsVersion bool // Whether the output file name contains the version
-	// NOTE: outputFileUsesApplicationName is always true for darwin-* and
-	// windows-*, and always false for linux-*. We could consider adding a flag
-	// for it to enable and disable at will (defaulting to how it's currently
-	// configured).
-	outputFileUsesApplicationName bool // Uses the application name instead of the package name
-	skipAssertInitialized         bool // Set to true when a task doesn't need to be initialized.
+	outputFileContainsVersion      bool                           // Whether the output file name contains the version, the artifact name in the output directory can omit it (see versionInArtifactFileName)
+	outputFileContainsArch         bool                           // Whether the artifact name in the output directory contains the target architecture
+	outputFileUsesApplicationName  bool                           // Uses the application name instead of the package name, artifact-names of go/hover.yaml can rename the artifact in the output directory
+	skipAssertInitialized          bool                           // Set to true when a task doesn't need to be initialized.
+	smokeTest                      *smokeTest                     // Installs the artifact and starts the app, for `hover verify-artifact`
+	identity                       []identityProperty             // Values identifying the app in the configuration files, checked by `hover check-identity`
+	customFormatDirectory          string                         // Directory of the hover-format.yaml of a custom packaging format, copied to go/packaging on init
 }
 
 func (t *packagingTask) Name() string {
@@ -131,30 +332,46 @@ func (t *packagingTask) init(ignoreAlreadyExists bool) {
 		task.init(true)
 	}
 	if !t.IsInitialized() {
-		createPackagingFormatDirectory(t.packagingFormatName)
-		dir := packagingFormatPath(t.packagingFormatName)
-		for sourceFile, destinationFile := range t.templateFiles {
-			destinationFile = filepath.Join(dir, destinationFile)
-			err := os.MkdirAll(filepath.Dir(destinationFile), 0775)
-			if err != nil {
-				log.Errorf("Failed to create directory %s: %v", filepath.Dir(destinationFile), err)
-				os.Exit(1)
-			}
-			fileutils.CopyAsset(fmt.Sprintf("packaging/%s", sourceFile), destinationFile, fileutils.AssetsBox())
+		if t.customFormatDirectory != "" {
+			t.initCustomFormat()
+		} else {
+			t.initTemplateFiles()
 		}
 		log.Infof("go/packaging/%s has been created. You can modify the configuration files and add it to git.", t.packagingFormatName)
-		log.Infof(fmt.Sprintf("You now can package the %s using `%s`", strings.Split(t.packagingFormatName, "-")[0], log.Au().Magenta("hover build "+t.packagingFormatName)))
+		log.Infof("You now can package the %s using `%s`", strings.Split(t.packagingFormatName, "-")[0], log.Au().Magenta("hover build "+t.packagingFormatName))
 	} else if !ignoreAlreadyExists {
 		log.Errorf("%s is already initialized for packaging.", t.packagingFormatName)
 		os.Exit(1)
 	}
 }
 
+// initTemplateFiles copies the template files of the packaging format to
+// go/packaging, and keeps them as the base of `hover upgrade-packaging`.
+func (t *packagingTask) initTemplateFiles() {
+	createPackagingFormatDirectory(t.packagingFormatName)
+	dir := packagingFormatPath(t.packagingFormatName)
+	for sourceFile, destinationFile := range t.templateFiles {
+		err := os.MkdirAll(filepath.Dir(filepath.Join(dir, destinationFile)), 0775)
+		if err != nil {
+			log.Errorf("Failed to create directory %s: %v", filepath.Dir(filepath.Join(dir, destinationFile)), err)
+			os.Exit(1)
+		}
+		fileutils.CopyAsset(fmt.Sprintf("packaging/%s", sourceFile), filepath.Join(dir, destinationFile), fileutils.AssetsBox())
+		content, err := fileutils.AssetsBox().Bytes(fmt.Sprintf("packaging/%s", sourceFile))
+		if err != nil {
+			log.Errorf("Failed to find boxed file %s: %v", sourceFile, err)
+			os.Exit(1)
+		}
+		writeTemplateBase(t.packagingFormatName, destinationFile, content)
+	}
+}
+
 func (t *packagingTask) Pack(buildVersion string) {
 	for task := range t.dependsOn {
 		task.Pack(buildVersion)
 	}
 	projectName := pubspec.GetPubSpec().Name
+	outputFileName := t.outputFileName(projectName, buildVersion)
 	tmpPath := getTemporaryBuildDirectory(projectName, t.packagingFormatName)
 	defer func() {
 		err := os.RemoveAll(tmpPath)
@@ -165,43 +382,175 @@ func (t *packagingTask) Pack(buildVersion string) {
 	}()
 	log.Infof("Packaging %s in %s", strings.Split(t.packagingFormatName, "-")[1], tmpPath)
 
+	t.stage(tmpPath, projectName, buildVersion)
+
+	var inputsHash string
+	if !NoCache {
+		var err error
+		inputsHash, err = t.inputsHash(tmpPath, t.getTemplateData(projectName, buildVersion))
+		if err != nil {
+			log.Errorf("Failed to hash the inputs of %s: %v", t.packagingFormatName, err)
+			os.Exit(1)
+		}
+		if t.restoreCachedArtifact(inputsHash, t.artifactFileName(outputFileName, buildVersion)) {
+			log.Infof("Packaging %s skipped, the inputs didn't change since a previous run", strings.Split(t.packagingFormatName, "-")[1])
+			return
+		}
+	}
+
+	var splitPath string
+	var splitOutputFileNames []string
+	if t.splitPackages != nil && splitPackagesEnabled() {
+		splitPath = getTemporaryBuildDirectory(projectName, t.packagingFormatName+"-split")
+		defer os.RemoveAll(splitPath)
+		log.Infof("Building the companion packages in %s", splitPath)
+		splitOutputFileNames = t.splitPackages(tmpPath, splitPath, t.getTemplateData(projectName, buildVersion))
+	}
+
+	err := os.RemoveAll(build.OutputDirectoryPath(t.packagingFormatName))
+	log.Printf("Cleaning the build directory")
+	if err != nil {
+		log.Errorf("Failed to clean output directory %s: %v", build.OutputDirectoryPath(t.packagingFormatName), err)
+		os.Exit(1)
+	}
+
+	if t.windowsPackaging != nil && runtime.GOOS == "windows" {
+		stopTiming := timings.Start(timings.PackagingScript)
+		err = t.windowsPackaging(tmpPath, t.getTemplateData(projectName, buildVersion))
+		stopTiming()
+		if err != nil {
+			log.ErrorCodef(explain.PackagingFailed, "Packaging failed: %v", err)
+			os.Exit(1)
+		}
+	} else {
+		packagingScript := executeStringTemplate(t.packagingFormatName+" packaging script", t.packagingScriptTemplate, t.getTemplateData(projectName, buildVersion))
+		runPackagingScript(tmpPath, packagingScript, t.packagingScriptDownloads)
+	}
+	artifactFileNames := []string{t.copyOutput(tmpPath, outputFileName, buildVersion)}
+	for _, splitOutputFileName := range splitOutputFileNames {
+		artifactFileNames = append(artifactFileNames, t.copyOutput(splitPath, splitOutputFileName, buildVersion))
+	}
+	if !NoCache {
+		t.cacheArtifact(inputsHash, artifactFileNames...)
+	}
+}
+
+// stage writes the files of the package to the temporary directory, before
+// the packaging script runs: the build output, the executed templates, the
+// launcher and the generated files.
+func (t *packagingTask) stage(tmpPath, projectName, buildVersion string) {
 	if t.buildOutputDirectory != "" {
-		err := copy.Copy(build.OutputDirectoryPath(strings.Split(t.packagingFormatName, "-")[0]), executeStringTemplate(filepath.Join(tmpPath, t.buildOutputDirectory), t.getTemplateData(projectName, buildVersion)))
+		err := fileutils.CloneDir(build.OutputDirectoryPath(strings.Split(t.packagingFormatName, "-")[0]), filepath.Join(tmpPath, executeStringTemplate(t.packagingFormatName+" build output directory", t.buildOutputDirectory, t.getTemplateData(projectName, buildVersion))))
 		if err != nil {
 			log.Errorf("Could not copy build folder: %v", err)
 			os.Exit(1)
 		}
 	}
+	if icon, ok := config.GetConfig().GetIcon(t.packagingFormatName); ok && t.buildOutputDirectory != "" {
+		if !fileutils.IsFileExists(icon) {
+			log.Errorf("The icon %s configured for %s doesn't exist", icon, t.packagingFormatName)
+			os.Exit(1)
+		}
+		log.Printf("Using icon %s", icon)
+		// the window icon of the app stays go/assets/icon.png with an SVG
+		// icon, only the icon theme of the linux packages takes it
+		if !isSvgIcon(icon) {
+			fileutils.CopyFile(icon, fileutils.LongPath(filepath.Join(tmpPath, executeStringTemplate(t.packagingFormatName+" build output directory", t.buildOutputDirectory, t.getTemplateData(projectName, buildVersion)), "assets", "icon.png")))
+		}
+	}
 	for task, destination := range t.dependsOn {
-		err := copy.Copy(build.OutputDirectoryPath(task.packagingFormatName), filepath.Join(tmpPath, destination))
+		err := fileutils.CloneDir(build.OutputDirectoryPath(task.packagingFormatName), filepath.Join(tmpPath, destination))
 		if err != nil {
 			log.Errorf("Could not copy build folder of %s: %v", task.packagingFormatName, err)
 			os.Exit(1)
 		}
 	}
 	fileutils.CopyTemplateDir(packagingFormatPath(t.packagingFormatName), filepath.Join(tmpPath), t.getTemplateData(projectName, buildVersion))
+	if t.customFormatDirectory != "" {
+		// the definition of the custom format isn't part of the package
+		err := os.Remove(filepath.Join(tmpPath, CustomFormatFile))
+		if err != nil && !os.IsNotExist(err) {
+			log.Errorf("Failed to remove %s from the temporary directory: %v", CustomFormatFile, err)
+			os.Exit(1)
+		}
+	}
+	t.writeLauncher(tmpPath, t.getTemplateData(projectName, buildVersion))
+	if t.gsettingsSchemaDirectory != "" && len(config.GetConfig().Preferences) > 0 {
+		writeGSettingsSchema(filepath.Join(tmpPath, executeStringTemplate(t.packagingFormatName+" GSettings schema directory", t.gsettingsSchemaDirectory, t.getTemplateData(projectName, buildVersion))), PreferencesID(projectName))
+	}
+	if t.appstreamDirectory != "" {
+		data := t.getTemplateData(projectName, buildVersion)
+		writeAppStreamMetainfo(filepath.Join(tmpPath, executeStringTemplate(t.packagingFormatName+" AppStream directory", t.appstreamDirectory, data)), data, executeStringTemplate(t.packagingFormatName+" AppStream desktop id", t.appstreamDesktopID, data))
+	}
+	if t.fileAssociationsDirectory != "" && len(config.GetConfig().FileAssociations) > 0 {
+		data := t.getTemplateData(projectName, buildVersion)
+		writeSharedMimeInfo(filepath.Join(tmpPath, executeStringTemplate(t.packagingFormatName+" file associations directory", t.fileAssociationsDirectory, data)), data["mimePackage"])
+	}
+	if t.hicolorIconsDirectory != "" {
+		data := t.getTemplateData(projectName, buildVersion)
+		writeHicolorIcons(filepath.Join(tmpPath, executeStringTemplate(t.packagingFormatName+" hicolor icons directory", t.hicolorIconsDirectory, data), "icons"), data["iconSourcePath"], data["iconName"])
+	}
 	if t.generateBuildFiles != nil {
 		log.Infof("Generating dynamic build files")
 		t.generateBuildFiles(config.GetConfig().GetPackageName(projectName), tmpPath)
 	}
 
 	for _, file := range t.executableFiles {
-		err := os.Chmod(executeStringTemplate(filepath.Join(tmpPath, file), t.getTemplateData(projectName, buildVersion)), 0777)
+		executablePath := fileutils.LongPath(filepath.Join(tmpPath, executeStringTemplate(t.packagingFormatName+" executable file", file, t.getTemplateData(projectName, buildVersion))))
+		// the file doesn't exist when it was removed from the configuration, or
+		// added to the templates after the format was initialized.
+		if _, err := os.Stat(executablePath); os.IsNotExist(err) {
+			continue
+		}
+		// not writable by the group and others, which dpkg rejects for the
+		// maintainer scripts
+		err := os.Chmod(executablePath, 0755)
 		if err != nil {
 			log.Errorf("Failed to change file permissions for %s file: %v", file, err)
 			os.Exit(1)
 		}
 	}
+	if t.linuxDesktopFile != "" {
+		data := t.getTemplateData(projectName, buildVersion)
+		checkDesktopEntry(t.packagingFormatName, fileutils.LongPath(filepath.Join(tmpPath, executeStringTemplate(t.packagingFormatName+" .desktop file", t.linuxDesktopFile, data))), data)
+	}
+	if data := t.getTemplateData(projectName, buildVersion); data["dbusName"] != "" {
+		renameDesktopFile(fileutils.LongPath(filepath.Join(tmpPath, executeStringTemplate(t.packagingFormatName+" .desktop file", t.linuxDesktopFile, data))), data["dbusName"])
+		writeDBusService(filepath.Join(tmpPath, executeStringTemplate(t.packagingFormatName+" DBus service directory", t.dbusServiceDirectory, data)), data["dbusName"], data["executablePath"])
+	}
+	// written again, the icon configured for the packaging format replaced
+	// the one listed in the manifest of the build output
+	if t.buildOutputDirectory != "" && config.GetConfig().Integrity.Manifest {
+		err := WriteIntegrityManifest(filepath.Join(tmpPath, executeStringTemplate(t.packagingFormatName+" build output directory", t.buildOutputDirectory, t.getTemplateData(projectName, buildVersion))), strings.Split(t.packagingFormatName, "-")[0])
+		if err != nil {
+			log.Errorf("Failed to write the integrity manifest: %v", err)
+			os.Exit(1)
+		}
+	}
+}
 
-	err := os.RemoveAll(build.OutputDirectoryPath(t.packagingFormatName))
-	log.Printf("Cleaning the build directory")
+// copyOutput copies a package to the output directory of the packaging task,
+// and returns its name there. The output is renamed once complete, a failing
+// copy doesn't leave a truncated package in the output directory.
+func (t *packagingTask) copyOutput(path, outputFileName, buildVersion string) string {
+	artifactFileName := t.artifactFileName(outputFileName, buildVersion)
+	outputFilePath := fileutils.LongPath(filepath.Join(build.OutputDirectoryPath(t.packagingFormatName), artifactFileName))
+	err := copy.Copy(fileutils.LongPath(filepath.Join(path, outputFileName)), outputFilePath+".partial")
 	if err != nil {
-		log.Errorf("Failed to clean output directory %s: %v", build.OutputDirectoryPath(t.packagingFormatName), err)
+		os.RemoveAll(outputFilePath + ".partial")
+		log.Errorf("Could not move %s file: %v", outputFileName, err)
 		os.Exit(1)
 	}
+	err = os.Rename(outputFilePath+".partial", outputFilePath)
+	if err != nil {
+		log.Errorf("Could not move %s file: %v", outputFileName, err)
+		os.Exit(1)
+	}
+	return artifactFileName
+}
 
-	packagingScript := executeStringTemplate(t.packagingScriptTemplate, t.getTemplateData(projectName, buildVersion))
-	runPackaging(tmpPath, packagingScript)
+// outputFileName returns the name of the artifact of the packaging task.
+func (t *packagingTask) outputFileName(projectName, buildVersion string) string {
 	var outputFileName string
 	if t.outputFileUsesApplicationName {
 		outputFileName += config.GetConfig().GetApplicationName(projectName)
@@ -209,20 +558,65 @@ func (t *packagingTask) Pack(buildVersion string) {
 		outputFileName += config.GetConfig().GetPackageName(projectName)
 	}
 	if t.outputFileContainsVersion {
-		if t.outputFileUsesApplicationName {
-			outputFileName += " "
-		} else {
-			outputFileName += "-"
+		outputFileName += fileNameSeparator(t.outputFileUsesApplicationName) + buildVersion
+	}
+	return outputFileName + "." + t.outputFileExtension
+}
+
+// artifactFileName returns the name in the output directory of a file made by
+// the packaging script, with the target architecture before the extension, so
+// the packages of several architectures can be published side by side. The
+// version is left out when versionInArtifactFileName is false, for stable
+// names such as myapp-amd64.deb, and the name starts with the application
+// name or the package name as set by artifact-names in go/hover.yaml.
+func (t *packagingTask) artifactFileName(outputFileName, buildVersion string) string {
+	separator := fileNameSeparator(t.outputFileUsesApplicationName)
+	extension := "." + t.outputFileExtension
+	name := strings.TrimSuffix(outputFileName, extension)
+	var version string
+	if t.outputFileContainsVersion && strings.HasSuffix(name, separator+buildVersion) {
+		name = strings.TrimSuffix(name, separator+buildVersion)
+		version = buildVersion
+	}
+	usesApplicationName := config.GetConfig().UsesApplicationNameInFilename(t.packagingFormatName, t.outputFileUsesApplicationName)
+	if usesApplicationName != t.outputFileUsesApplicationName {
+		projectName := pubspec.GetPubSpec().Name
+		applicationName := config.GetConfig().GetApplicationName(projectName)
+		packageName := config.GetConfig().GetPackageName(projectName)
+		// the companion packages are named after the package, e.g.
+		// myapp-dbgsym, their suffix is kept
+		if usesApplicationName && strings.HasPrefix(name, packageName) {
+			name = applicationName + strings.TrimPrefix(name, packageName)
+		} else if !usesApplicationName && strings.HasPrefix(name, applicationName) {
+			name = packageName + strings.TrimPrefix(name, applicationName)
 		}
-		outputFileName += buildVersion
+		separator = fileNameSeparator(usesApplicationName)
 	}
-	outputFileName += "." + t.outputFileExtension
-	outputFilePath := executeStringTemplate(filepath.Join(build.OutputDirectoryPath(t.packagingFormatName), outputFileName), t.getTemplateData(projectName, buildVersion))
-	err = copy.Copy(filepath.Join(tmpPath, outputFileName), outputFilePath)
-	if err != nil {
-		log.Errorf("Could not move %s file: %v", outputFileName, err)
-		os.Exit(1)
+	if version != "" && t.versionInArtifactFileName() {
+		name += separator + version
 	}
+	if t.outputFileContainsArch {
+		name += build.TargetArchSuffix(separator)
+	}
+	return name + extension
+}
+
+// fileNameSeparator returns the separator of the parts of the file names
+// starting with the application name, which may contain spaces, or with the
+// package name.
+func fileNameSeparator(usesApplicationName bool) string {
+	if usesApplicationName {
+		return " "
+	}
+	return "-"
+}
+
+// versionInArtifactFileName returns whether the artifact names in the output
+// directory contain the version. It's disabled for all the formats of a build
+// by --no-version-in-filename, or per format (linux-deb) or platform (linux)
+// with omit-version-in-filename in go/hover.yaml.
+func (t *packagingTask) versionInArtifactFileName() bool {
+	return !NoVersionInFilename && !config.GetConfig().OmitsVersionInFilename(t.packagingFormatName)
 }
 
 func (t *packagingTask) AssertInitialized() {
@@ -230,7 +624,7 @@ func (t *packagingTask) AssertInitialized() {
 		return
 	}
 	if !t.IsInitialized() {
-		log.Errorf("%s is not initialized for packaging. Please run `hover init-packaging %s` first.", t.packagingFormatName, t.packagingFormatName)
+		log.ErrorCodef(explain.PackagingNotInitialized, "%s is not initialized for packaging. Please run `hover init-packaging %s` first.", t.packagingFormatName, t.packagingFormatName)
 		os.Exit(1)
 	}
 }
@@ -240,16 +634,19 @@ func (t *packagingTask) IsInitialized() bool {
 	return !os.IsNotExist(err)
 }
 
-func executeStringTemplate(t string, data map[string]string) string {
-	tmplFile, err := template.New("").Option("missingkey=error").Parse(t)
+// executeStringTemplate executes a template string of a packaging task. The
+// name is used to report which template failed.
+func executeStringTemplate(name, t string, data map[string]string) string {
+	tmplFile, err := fileutils.ParseTemplate(name, t)
 	if err != nil {
-		log.Errorf("Failed to parse template string: %v\n", err)
+		log.ErrorCodef(explain.TemplateFailed, "Failed to parse template string: %v", err)
 		os.Exit(1)
 	}
 	var tmplBytes bytes.Buffer
 	err = tmplFile.Execute(&tmplBytes, data)
 	if err != nil {
-		panic(err)
+		log.ErrorCodef(explain.TemplateFailed, "Failed to execute template string: %v", err)
+		os.Exit(1)
 	}
 	return tmplBytes.String()
 }
@@ -0,0 +1,182 @@
+package packaging
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-flutter-desktop/hover/internal/androidmanifest"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// PreferencesID returns the identifier of the preferences of the app: the
+// GSettings schema, the defaults domain and the registry key.
+func PreferencesID(projectName string) string {
+	return androidmanifest.AndroidOrganizationName() + "." + config.GetConfig().GetPackageName(projectName)
+}
+
+// preferenceKeyPattern matches the keys of the preferences, as GSettings
+// requires them.
+var preferenceKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// gsettingsTypes are the GVariant types of the types of the preferences.
+var gsettingsTypes = map[string]string{
+	"string": "s",
+	"bool":   "b",
+	"int":    "i",
+}
+
+// preferences returns the preferences of go/hover.yaml, with their type and
+// default value set.
+func preferences() []config.PreferenceConfig {
+	var preferences []config.PreferenceConfig
+	for _, preference := range config.GetConfig().Preferences {
+		if !preferenceKeyPattern.MatchString(preference.Key) {
+			log.Errorf("Invalid preference key %q in go/hover.yaml, use lowercase letters, digits and dashes.", preference.Key)
+			os.Exit(1)
+		}
+		if preference.Type == "" {
+			preference.Type = "string"
+		}
+		switch preference.Type {
+		case "string":
+		case "bool":
+			if preference.Default == "" {
+				preference.Default = "false"
+			}
+			if _, err := strconv.ParseBool(preference.Default); err != nil {
+				log.Errorf("The default of the preference %s must be true or false.", preference.Key)
+				os.Exit(1)
+			}
+		case "int":
+			if preference.Default == "" {
+				preference.Default = "0"
+			}
+			if _, err := strconv.ParseInt(preference.Default, 10, 32); err != nil {
+				log.Errorf("The default of the preference %s must be an integer.", preference.Key)
+				os.Exit(1)
+			}
+		default:
+			log.Errorf("Unknown type %s of the preference %s, use string, bool or int.", preference.Type, preference.Key)
+			os.Exit(1)
+		}
+		preferences = append(preferences, preference)
+	}
+	return preferences
+}
+
+// writeGSettingsSchema writes the GSettings schema of the preferences to a
+// directory, the schema id is the preferencesID.
+func writeGSettingsSchema(dir, preferencesID string) {
+	content := []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<schemalist>`,
+		`  <schema id="` + fileutils.XMLEscape(preferencesID) + `" path="/` + strings.ReplaceAll(preferencesID, ".", "/") + `/">`,
+	}
+	for _, preference := range preferences() {
+		value := preference.Default
+		if preference.Type == "string" {
+			value = "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+		}
+		content = append(content,
+			`    <key name="`+preference.Key+`" type="`+gsettingsTypes[preference.Type]+`">`,
+			`      <default>`+fileutils.XMLEscape(value)+`</default>`,
+		)
+		if preference.Summary != "" {
+			content = append(content, `      <summary>`+fileutils.XMLEscape(preference.Summary)+`</summary>`)
+		}
+		content = append(content, `    </key>`)
+	}
+	content = append(content, `  </schema>`, `</schemalist>`)
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		log.Errorf("Failed to create %s: %v", dir, err)
+		os.Exit(1)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, preferencesID+".gschema.xml"), []byte(strings.Join(content, "\n")+"\n"), 0644)
+	if err != nil {
+		log.Errorf("Could not write the GSettings schema: %v", err)
+		os.Exit(1)
+	}
+}
+
+// writeDefaultsPlist writes the defaults of the preferences to a property
+// list, read by go/cmd/preferences.go when the defaults domain of the app has
+// no value.
+func writeDefaultsPlist(path string) {
+	content := []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<!DOCTYPE plist PUBLIC "-//Apple Computer//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">`,
+		`<plist version="1.0">`,
+		`<dict>`,
+	}
+	for _, preference := range preferences() {
+		content = append(content, `    <key>`+preference.Key+`</key>`)
+		switch preference.Type {
+		case "bool":
+			content = append(content, `    <`+preference.Default+`/>`)
+		case "int":
+			content = append(content, `    <integer>`+preference.Default+`</integer>`)
+		default:
+			content = append(content, `    <string>`+fileutils.XMLEscape(preference.Default)+`</string>`)
+		}
+	}
+	content = append(content, `</dict>`, `</plist>`)
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		log.Errorf("Failed to create %s: %v", filepath.Dir(path), err)
+		os.Exit(1)
+	}
+	err = ioutil.WriteFile(path, []byte(strings.Join(content, "\n")+"\n"), 0644)
+	if err != nil {
+		log.Errorf("Could not write Defaults.plist: %v", err)
+		os.Exit(1)
+	}
+}
+
+// windowsMsiPreferences writes preferences.wxi, which installs the defaults
+// of the preferences in the Defaults subkey of the registry key of the app,
+// and adds its component to component_refs.wxi.
+func windowsMsiPreferences(packageName, tmpPath, preferencesID string) {
+	preferences := preferences()
+	content := []string{`<Include>`}
+	if len(preferences) > 0 {
+		wxs, err := ioutil.ReadFile(filepath.Join(tmpPath, packageName+".wxs"))
+		if err != nil {
+			log.Errorf("Failed to read %s.wxs: %v", packageName, err)
+			os.Exit(1)
+		}
+		if !strings.Contains(string(wxs), "preferences.wxi") {
+			log.Errorf("%s.wxs doesn't include preferences.wxi, run `%s` to install the preferences of go/hover.yaml.", packageName, log.Au().Magenta("hover upgrade-packaging windows-msi"))
+			os.Exit(1)
+		}
+		content = append(content,
+			`<DirectoryRef Id="APPLICATIONROOTDIRECTORY">`,
+			`<Component Id="Preferences" Guid="*">`,
+		)
+		for i, preference := range preferences {
+			valueType := "string"
+			if preference.Type == "int" {
+				valueType = "integer"
+			}
+			value := `<RegistryValue Root="HKCU" Key="Software\` + fileutils.XMLEscape(preferencesID) + `\Defaults" Name="` + preference.Key + `" Type="` + valueType + `" Value="` + fileutils.XMLEscape(preference.Default) + `"`
+			if i == 0 {
+				value += ` KeyPath="yes"`
+			}
+			content = append(content, value+`/>`)
+		}
+		content = append(content, `</Component>`, `</DirectoryRef>`)
+		componentRefsFileContent = append(componentRefsFileContent, `<ComponentRef Id="Preferences"/>`)
+	}
+	content = append(content, `</Include>`)
+	err := ioutil.WriteFile(filepath.Join(tmpPath, "preferences.wxi"), []byte(strings.Join(content, "\n")+"\n"), 0644)
+	if err != nil {
+		log.Errorf("Could not write preferences.wxi: %v", err)
+		os.Exit(1)
+	}
+}
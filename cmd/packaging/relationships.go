@@ -0,0 +1,87 @@
+package packaging
+
+import (
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// debRelationshipFields are the control fields of deb in go/hover.yaml.
+var debRelationshipFields = []string{"Depends", "Recommends", "Suggests", "Conflicts", "Provides"}
+
+// rpmRelationshipFields are the spec tags of rpm in go/hover.yaml.
+var rpmRelationshipFields = []string{"Requires", "BuildRequires", "Provides", "Obsoletes"}
+
+// debRelationships returns the relationship fields of the deb packages set
+// in go/hover.yaml, by control field name. The template data has them as
+// debDepends, debRecommends, debSuggests, debConflicts and debProvides.
+func debRelationships(deb config.DebConfig) map[string][]string {
+	return validRelationships("deb", "libgtk-3-0 (>= 3.22)", map[string][]string{
+		"Depends":    deb.Depends,
+		"Recommends": deb.Recommends,
+		"Suggests":   deb.Suggests,
+		"Conflicts":  deb.Conflicts,
+		"Provides":   deb.Provides,
+	})
+}
+
+// rpmRelationships returns the dependency tags of the rpm package set in
+// go/hover.yaml, by spec tag name. The template data has them as
+// rpmRequires, rpmBuildRequires, rpmProvides and rpmObsoletes.
+func rpmRelationships(rpm config.RpmConfig) map[string][]string {
+	return validRelationships("rpm", "gstreamer1 >= 1.16", map[string][]string{
+		"Requires":      rpm.Requires,
+		"BuildRequires": rpm.BuildRequires,
+		"Provides":      rpm.Provides,
+		"Obsoletes":     rpm.Obsoletes,
+	})
+}
+
+// validRelationships trims the relationships of a section of go/hover.yaml,
+// and exits when one would break the control file or spec.
+func validRelationships(section, example string, fields map[string][]string) map[string][]string {
+	for field, relationships := range fields {
+		for i, relationship := range relationships {
+			relationship = strings.TrimSpace(relationship)
+			if relationship == "" || strings.ContainsAny(relationship, "\n\r") {
+				log.Errorf("Invalid %s.%s entry %q in go/hover.yaml, use one package relationship per entry, e.g. '%s'.", section, relationshipKey(field), relationships[i], example)
+				os.Exit(1)
+			}
+			relationships[i] = relationship
+		}
+	}
+	return fields
+}
+
+// relationshipKey returns the key of go/hover.yaml of a field, e.g.
+// build-requires for BuildRequires.
+func relationshipKey(field string) string {
+	return strings.ToLower(regexp.MustCompile(`([a-z])([A-Z])`).ReplaceAllString(field, "$1-$2"))
+}
+
+// checkRelationships warns when a control file or spec doesn't have the
+// relationships of go/hover.yaml, its template predating them.
+func checkRelationships(packagingFormat, path string, fields []string, relationships map[string][]string) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Errorf("Failed to read the %s template output: %v", packagingFormat, err)
+		os.Exit(1)
+	}
+	for _, field := range fields {
+		var values []string
+		for _, match := range regexp.MustCompile(`(?m)^`+field+`: *(.*)$`).FindAllSubmatch(content, -1) {
+			values = append(values, string(match[1]))
+		}
+		value := strings.Join(values, ", ")
+		for _, relationship := range relationships[field] {
+			if !strings.Contains(value, relationship) {
+				log.Warnf("The %s of %s doesn't have '%s' of go/hover.yaml, run `%s` to add the relationships of go/hover.yaml to the template.", field, packagingFormat, relationship, log.Au().Magenta("hover upgrade-packaging "+packagingFormat))
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package packaging
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// rootlessTemplateData returns the template data selecting how the packaging
+// scripts run without root, FUSE or a VM, as in the containers of the CIs:
+//
+//   - fakeroot is the prefix running a command as fake root ("fakeroot "),
+//     empty when hover runs as root or fakeroot isn't installed.
+//   - appimageExtractAndRun is set when FUSE isn't available, appimagetool
+//     then runs extracted instead of mounted.
+//   - snapcraftBuildEnvironment is host in the containers, snapcraft then
+//     builds in the container instead of a VM it can't start. The
+//     SNAPCRAFT_BUILD_ENVIRONMENT environment variable takes precedence.
+func rootlessTemplateData() map[string]string {
+	data := map[string]string{
+		"fakeroot":                  "",
+		"appimageExtractAndRun":     "",
+		"snapcraftBuildEnvironment": os.Getenv("SNAPCRAFT_BUILD_ENVIRONMENT"),
+	}
+	if runtime.GOOS == "windows" {
+		return data
+	}
+	if _, err := exec.LookPath("fakeroot"); err == nil && os.Geteuid() != 0 {
+		data["fakeroot"] = "fakeroot "
+	}
+	if !fuseAvailable() {
+		data["appimageExtractAndRun"] = "1"
+	}
+	if data["snapcraftBuildEnvironment"] == "" && inContainer() {
+		data["snapcraftBuildEnvironment"] = "host"
+	}
+	return data
+}
+
+// fuseAvailable returns whether FUSE filesystems can be mounted by the user.
+func fuseAvailable() bool {
+	if runtime.GOOS != "linux" {
+		return true
+	}
+	fuse, err := os.OpenFile("/dev/fuse", os.O_RDWR, 0)
+	if err != nil {
+		return false
+	}
+	fuse.Close()
+	_, err = exec.LookPath("fusermount")
+	if err != nil {
+		_, err = exec.LookPath("fusermount3")
+	}
+	return err == nil
+}
+
+// inContainer returns whether hover runs in a docker, podman or systemd-nspawn
+// container.
+func inContainer() bool {
+	for _, path := range []string{"/.dockerenv", "/run/.containerenv"} {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	return os.Getenv("container") != ""
+}
@@ -0,0 +1,78 @@
+package packaging
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-flutter-desktop/hover/internal/androidmanifest"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// SingleInstanceID returns the identifier of the first instance of the app,
+// when single-instance is set: its socket, its DBus name on linux and its
+// mutex on windows.
+func SingleInstanceID(projectName string) string {
+	return androidmanifest.AndroidOrganizationName() + "." + config.GetConfig().GetPackageName(projectName)
+}
+
+// dbusNameElementPattern matches the elements of a DBus name.
+var dbusNameElementPattern = regexp.MustCompile(`^[A-Za-z_-][A-Za-z0-9_-]*$`)
+
+// DBusName returns the DBus name of the app when dbus-activatable is set, the
+// single instance identifier. The .desktop files of the linux packages are
+// named after it, as the DBus activation requires.
+func DBusName(projectName string) string {
+	if !config.GetConfig().SingleInstance {
+		log.Errorf("dbus-activatable requires single-instance in go/hover.yaml, the DBus activations are forwarded to the first instance.")
+		os.Exit(1)
+	}
+	name := SingleInstanceID(projectName)
+	for _, element := range strings.Split(name, ".") {
+		if !dbusNameElementPattern.MatchString(element) {
+			log.Errorf("The DBus name %s of the app is invalid, its elements must not start with a digit and contain letters, digits, underscores and dashes only. Change the organization or the package-name.", name)
+			os.Exit(1)
+		}
+	}
+	return name
+}
+
+// writeDBusService writes the DBus service starting the app when it's
+// activated through DBus to a directory.
+func writeDBusService(dir, name, executablePath string) {
+	content := []string{
+		"[D-BUS Service]",
+		"Name=" + name,
+		"Exec=" + executablePath,
+	}
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		log.Errorf("Failed to create %s: %v", dir, err)
+		os.Exit(1)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, name+".service"), []byte(strings.Join(content, "\n")+"\n"), 0644)
+	if err != nil {
+		log.Errorf("Could not write the DBus service: %v", err)
+		os.Exit(1)
+	}
+}
+
+// renameDesktopFile names a .desktop file after the DBus name of the app.
+func renameDesktopFile(desktopFilePath, dbusName string) {
+	dbusDesktopFilePath := filepath.Join(filepath.Dir(desktopFilePath), dbusName+".desktop")
+	if desktopFilePath == dbusDesktopFilePath {
+		return
+	}
+	// the file doesn't exist when it was removed from the configuration
+	if _, err := os.Stat(desktopFilePath); os.IsNotExist(err) {
+		return
+	}
+	err := os.Rename(desktopFilePath, dbusDesktopFilePath)
+	if err != nil {
+		log.Errorf("Could not rename the .desktop file after the DBus name: %v", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,283 @@
+package packaging
+
+import (
+	"debug/elf"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// splitPackagesFunc builds the companion packages of the split-packages
+// configuration in splitPath, from the package prepared in tmpPath. It
+// returns the file names of the companion packages.
+type splitPackagesFunc func(tmpPath, splitPath string, templateData map[string]string) []string
+
+// splitPackagesEnabled returns whether companion packages are configured in
+// the split-packages section of go/hover.yaml.
+func splitPackagesEnabled() bool {
+	c := config.GetConfig().SplitPackages
+	return c.DebugSymbols || len(c.Data) > 0
+}
+
+// dataPackageName returns the name of the package containing the data files
+// of the split-packages configuration, empty when there is none.
+func dataPackageName(packageName string) string {
+	if len(config.GetConfig().SplitPackages.Data) == 0 {
+		return ""
+	}
+	return packageName + "-data"
+}
+
+// moveSplitData moves the data paths of the split-packages configuration from
+// the app directory of a package to the app directory of its data package.
+func moveSplitData(appPath, dataAppPath string) {
+	for _, dataPath := range config.GetConfig().SplitPackages.Data {
+		cleanPath := filepath.Clean(filepath.FromSlash(dataPath))
+		if filepath.IsAbs(cleanPath) || cleanPath == "." || cleanPath == ".." || strings.HasPrefix(cleanPath, ".."+string(filepath.Separator)) {
+			log.Errorf("The split-packages data path %s must be a path of the build output", dataPath)
+			os.Exit(1)
+		}
+		sourcePath := filepath.Join(appPath, cleanPath)
+		if _, err := os.Lstat(sourcePath); err != nil {
+			log.Errorf("The split-packages data path %s isn't in the build output: %v", dataPath, err)
+			os.Exit(1)
+		}
+		destinationPath := filepath.Join(dataAppPath, cleanPath)
+		err := os.MkdirAll(filepath.Dir(destinationPath), 0755)
+		if err != nil {
+			log.Errorf("Failed to create the data package directory: %v", err)
+			os.Exit(1)
+		}
+		err = os.Rename(sourcePath, destinationPath)
+		if err != nil {
+			log.Errorf("Failed to move %s to the data package: %v", dataPath, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// splitDebugSymbols moves the debug symbols of an executable to a separate
+// file, which gdb finds by the debug link added to the executable.
+func splitDebugSymbols(executablePath, debugFilePath string) {
+	objcopyBin, err := exec.LookPath("objcopy")
+	if err != nil {
+		log.Errorf("Failed to find objcopy to split the debug symbols, it is part of binutils: %v", err)
+		os.Exit(1)
+	}
+	executable, err := elf.Open(executablePath)
+	if err != nil {
+		log.Errorf("Failed to read the executable %s: %v", executablePath, err)
+		os.Exit(1)
+	}
+	hasDebugSymbols := executable.Section(".debug_info") != nil || executable.Section(".zdebug_info") != nil
+	executable.Close()
+	if !hasDebugSymbols {
+		log.Errorf("The executable %s has no debug symbols. Build it again with split-packages.debug-symbols set in go/hover.yaml.", filepath.Base(executablePath))
+		os.Exit(1)
+	}
+	err = os.MkdirAll(filepath.Dir(debugFilePath), 0755)
+	if err != nil {
+		log.Errorf("Failed to create the debug symbols directory: %v", err)
+		os.Exit(1)
+	}
+	runObjcopy(objcopyBin, "--only-keep-debug", executablePath, debugFilePath)
+	err = os.Chmod(debugFilePath, 0644)
+	if err != nil {
+		log.Errorf("Failed to change file permissions for %s: %v", debugFilePath, err)
+		os.Exit(1)
+	}
+	// objcopy can't write its output to its input, the stripped copy
+	// replaces the executable.
+	strippedPath := executablePath + ".stripped"
+	runObjcopy(objcopyBin, "--strip-all", "--add-gnu-debuglink="+debugFilePath, executablePath, strippedPath)
+	err = os.Rename(strippedPath, executablePath)
+	if err != nil {
+		log.Errorf("Failed to replace the executable by its stripped copy: %v", err)
+		os.Exit(1)
+	}
+}
+
+func runObjcopy(objcopyBin string, args ...string) {
+	out, err := exec.Command(objcopyBin, args...).CombinedOutput()
+	if err != nil {
+		log.Errorf("Failed to split the debug symbols: %v\n%s", err, out)
+		os.Exit(1)
+	}
+}
+
+// splitDebPackages builds the dbgsym and data packages of a deb package.
+func splitDebPackages(tmpPath, splitPath string, templateData map[string]string) []string {
+	packageName := templateData["packageName"]
+	version := templateData["version"]
+	appPath := filepath.Join("usr", "lib", packageName)
+	control, err := ioutil.ReadFile(filepath.Join(tmpPath, "DEBIAN", "control"))
+	if err != nil {
+		log.Errorf("Failed to read the control file of the package: %v", err)
+		os.Exit(1)
+	}
+	architecture := debControlField(control, "Architecture", templateData["arch"])
+	maintainer := debControlField(control, "Maintainer", "@"+templateData["author"])
+
+	var outputFileNames []string
+	if config.GetConfig().SplitPackages.DebugSymbols {
+		name := packageName + "-dbgsym"
+		executableName := templateData["executableName"]
+		splitDebugSymbols(
+			filepath.Join(tmpPath, appPath, executableName),
+			filepath.Join(splitPath, name, "usr", "lib", "debug", appPath, executableName+".debug"),
+		)
+		writeDebControl(filepath.Join(splitPath, name), []string{
+			"Package: " + name,
+			"Source: " + packageName,
+			"Architecture: " + architecture,
+			"Maintainer: " + maintainer,
+			"Priority: optional",
+			"Section: debug",
+			"Version: " + version,
+			"Depends: " + packageName + " (= " + version + ")",
+			"Description: debug symbols for " + packageName,
+		})
+		outputFileNames = append(outputFileNames, buildSplitDeb(splitPath, name, version))
+	}
+	if name := dataPackageName(packageName); name != "" {
+		if !regexp.MustCompile(`(?m)^(Pre-)?Depends:.*\b` + regexp.QuoteMeta(name) + `\b`).Match(control) {
+			log.Warnf("The control file of linux-deb doesn't depend on %s, run `%s` to add the dependency.", name, log.Au().Magenta("hover upgrade-packaging linux-deb"))
+		}
+		moveSplitData(filepath.Join(tmpPath, appPath), filepath.Join(splitPath, name, appPath))
+		writeDebControl(filepath.Join(splitPath, name), []string{
+			"Package: " + name,
+			"Source: " + packageName,
+			"Architecture: all",
+			"Maintainer: " + maintainer,
+			"Priority: optional",
+			"Version: " + version,
+			"Description: data files of " + packageName,
+		})
+		outputFileNames = append(outputFileNames, buildSplitDeb(splitPath, name, version))
+	}
+	return outputFileNames
+}
+
+// debControlField returns the value of a field of a deb control file.
+func debControlField(control []byte, field, defaultValue string) string {
+	match := regexp.MustCompile(`(?m)^` + field + `: *(.*)$`).FindSubmatch(control)
+	if match == nil {
+		return defaultValue
+	}
+	return strings.TrimSpace(string(match[1]))
+}
+
+func writeDebControl(packagePath string, fields []string) {
+	err := os.MkdirAll(filepath.Join(packagePath, "DEBIAN"), 0755)
+	if err != nil {
+		log.Errorf("Failed to create the DEBIAN directory: %v", err)
+		os.Exit(1)
+	}
+	err = ioutil.WriteFile(filepath.Join(packagePath, "DEBIAN", "control"), []byte(strings.Join(fields, "\n")+"\n"), 0644)
+	if err != nil {
+		log.Errorf("Failed to write the control file: %v", err)
+		os.Exit(1)
+	}
+}
+
+func buildSplitDeb(splitPath, name, version string) string {
+	data := map[string]string{"name": name, "version": version, "fakeroot": rootlessTemplateData()["fakeroot"]}
+	runPackaging(splitPath, executeStringTemplate("linux-deb split package script", "{{.fakeroot}}dpkg-deb {{if not .fakeroot}}--root-owner-group {{end}}--build {{shellquote .name}} {{shellquote .name \"-\" .version \".deb\"}}", data))
+	return name + "-" + version + ".deb"
+}
+
+// splitRpmPackages builds the debuginfo and data packages of a rpm package.
+func splitRpmPackages(tmpPath, splitPath string, templateData map[string]string) []string {
+	packageName := templateData["packageName"]
+	release := templateData["release"]
+	version := templateData["version"]
+	appPath := filepath.Join("usr", "lib", packageName)
+	buildPath := filepath.Join(tmpPath, "BUILD", packageName+"-"+version+"-"+release+"."+templateData["gnuArch"])
+
+	var outputFileNames []string
+	if config.GetConfig().SplitPackages.DebugSymbols {
+		name := packageName + "-debuginfo"
+		executableName := templateData["executableName"]
+		debugFilePath := filepath.Join("usr", "lib", "debug", appPath, executableName+".debug")
+		splitDebugSymbols(
+			filepath.Join(buildPath, appPath, executableName),
+			filepath.Join(splitPath, "BUILD", name+"-"+version+"-"+release+"."+templateData["gnuArch"], debugFilePath),
+		)
+		outputFileNames = append(outputFileNames, buildSplitRpm(splitPath, name, templateData["gnuArch"], templateData, []string{
+			"Summary: Debug symbols for " + packageName,
+			"Requires: " + packageName + " = " + version + "-" + release,
+		}, []string{"/" + filepath.ToSlash(debugFilePath)}))
+	}
+	if name := dataPackageName(packageName); name != "" {
+		spec, err := ioutil.ReadFile(filepath.Join(tmpPath, "SPECS", packageName+".spec"))
+		if err != nil {
+			log.Errorf("Failed to read the spec file of the package: %v", err)
+			os.Exit(1)
+		}
+		if !regexp.MustCompile(`(?m)^Requires:.*\b` + regexp.QuoteMeta(name) + `\b`).Match(spec) {
+			log.Warnf("The spec file of linux-rpm doesn't require %s, run `%s` to add the dependency.", name, log.Au().Magenta("hover upgrade-packaging linux-rpm"))
+		}
+		moveSplitData(filepath.Join(buildPath, appPath), filepath.Join(splitPath, "BUILD", name+"-"+version+"-"+release+".noarch", appPath))
+		var files []string
+		for _, dataPath := range config.GetConfig().SplitPackages.Data {
+			files = append(files, "/"+filepath.ToSlash(filepath.Join(appPath, filepath.Clean(filepath.FromSlash(dataPath)))))
+		}
+		outputFileNames = append(outputFileNames, buildSplitRpm(splitPath, name, "noarch", templateData, []string{
+			"Summary: Data files of " + packageName,
+		}, files))
+	}
+	return outputFileNames
+}
+
+// buildSplitRpm writes the spec of a companion package, whose files are in
+// BUILD/<name>-<version>-<release>.<arch> of the split directory, and builds
+// it.
+func buildSplitRpm(splitPath, name, arch string, templateData map[string]string, header, files []string) string {
+	version := templateData["version"]
+	release := templateData["release"]
+	var spec []string
+	spec = append(spec,
+		// the debug symbols must not be stripped by the post install scripts
+		"%global __os_install_post %{nil}",
+		"%global debug_package %{nil}",
+		"Name: "+name,
+		"Version: "+version,
+		"Release: "+release,
+		"License: "+templateData["license"],
+		"BuildArch: "+arch,
+	)
+	spec = append(spec, header...)
+	spec = append(spec,
+		"",
+		"%description",
+		strings.TrimPrefix(header[0], "Summary: "),
+		"",
+		"%install",
+		"mkdir -p $RPM_BUILD_ROOT",
+		"cp -R $RPM_BUILD_DIR/"+name+"-"+version+"-"+release+"."+arch+"/* $RPM_BUILD_ROOT",
+		"",
+		"%files",
+	)
+	for _, file := range files {
+		spec = append(spec, `"`+file+`"`)
+	}
+	err := os.MkdirAll(filepath.Join(splitPath, "SPECS"), 0755)
+	if err != nil {
+		log.Errorf("Failed to create the SPECS directory: %v", err)
+		os.Exit(1)
+	}
+	err = ioutil.WriteFile(filepath.Join(splitPath, "SPECS", name+".spec"), []byte(strings.Join(spec, "\n")+"\n"), 0644)
+	if err != nil {
+		log.Errorf("Failed to write the spec file: %v", err)
+		os.Exit(1)
+	}
+	data := map[string]string{"name": name, "version": version, "release": release, "arch": arch}
+	runPackaging(splitPath, executeStringTemplate("linux-rpm split package script", "rpmbuild --define \"_topdir $(pwd)\" --target {{shellquote .arch}} -bb {{shellquote \"./SPECS/\" .name \".spec\"}} && mv -n {{shellquote \"RPMS/\" .arch \"/\" .name \"-\" .version \"-\" .release \".\" .arch \".rpm\"}} {{shellquote .name \"-\" .version \".rpm\"}}", data))
+	return name + "-" + version + ".rpm"
+}
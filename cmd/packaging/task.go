@@ -1,5 +1,7 @@
 package packaging
 
+import "time"
+
 // Task contains all configuration options for a given packaging method.
 // TODO: Rename to something that suits it more? Mabe Executor?
 type Task interface {
@@ -8,4 +10,9 @@ type Task interface {
 	IsInitialized() bool
 	AssertInitialized()
 	Pack(buildVersion string)
+	Upgrade()
+	Identity(buildVersion string) []IdentityValue
+	RenameIdentity(buildVersion string, renamed map[string]string) map[string][]byte
+	Lint(buildVersion string) []LintProblem
+	Verify(buildVersion string, timeout time.Duration) error
 }
@@ -0,0 +1,173 @@
+package packaging
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// packagingTemplatesPath contains a copy of the templates as they were when a
+// packaging format was initialized or last upgraded. It is used as the base
+// of the three-way merge done by upgrade-packaging.
+var packagingTemplatesPath = filepath.Join(packagingPath, ".templates")
+
+func packagingFormatTemplatesPath(packagingFormat string) string {
+	directoryPath, err := filepath.Abs(filepath.Join(packagingTemplatesPath, packagingFormat))
+	if err != nil {
+		log.Errorf("Failed to resolve absolute path for %s templates directory: %v", packagingFormat, err)
+		os.Exit(1)
+	}
+	return directoryPath
+}
+
+// writeTemplateBase stores the hover template used for a packaging file.
+func writeTemplateBase(packagingFormat, destinationFile string, content []byte) {
+	basePath := filepath.Join(packagingFormatTemplatesPath(packagingFormat), destinationFile)
+	err := os.MkdirAll(filepath.Dir(basePath), 0775)
+	if err != nil {
+		log.Errorf("Failed to create directory %s: %v", filepath.Dir(basePath), err)
+		os.Exit(1)
+	}
+	err = ioutil.WriteFile(basePath, content, 0664)
+	if err != nil {
+		log.Errorf("Failed to write %s: %v", basePath, err)
+		os.Exit(1)
+	}
+}
+
+func (t *packagingTask) Upgrade() {
+	if !t.IsInitialized() {
+		log.Errorf("%s is not initialized for packaging. Please run `hover init-packaging %s` first.", t.packagingFormatName, t.packagingFormatName)
+		os.Exit(1)
+	}
+	if t.customFormatDirectory != "" {
+		log.Infof("%s is a custom packaging format, it has no hover templates to upgrade to.", t.packagingFormatName)
+		return
+	}
+	if len(t.templateFiles) == 0 {
+		log.Infof("%s has no configuration files to upgrade.", t.packagingFormatName)
+		return
+	}
+	dir := packagingFormatPath(t.packagingFormatName)
+	var conflicts []string
+	for sourceFile, destinationFile := range t.templateFiles {
+		hoverContent, err := fileutils.AssetsBox().Bytes(fmt.Sprintf("packaging/%s", sourceFile))
+		if err != nil {
+			log.Errorf("Failed to find boxed file %s: %v", sourceFile, err)
+			os.Exit(1)
+		}
+		currentPath := filepath.Join(dir, destinationFile)
+		displayPath := filepath.Join(build.BuildPath, "packaging", t.packagingFormatName, destinationFile)
+		currentContent, err := ioutil.ReadFile(currentPath)
+		if os.IsNotExist(err) {
+			err = os.MkdirAll(filepath.Dir(currentPath), 0775)
+			if err != nil {
+				log.Errorf("Failed to create directory %s: %v", filepath.Dir(currentPath), err)
+				os.Exit(1)
+			}
+			err = ioutil.WriteFile(currentPath, hoverContent, 0664)
+			if err != nil {
+				log.Errorf("Failed to write %s: %v", currentPath, err)
+				os.Exit(1)
+			}
+			writeTemplateBase(t.packagingFormatName, destinationFile, hoverContent)
+			log.Infof("       %s: added", displayPath)
+			continue
+		}
+		if err != nil {
+			log.Errorf("Failed to read %s: %v", currentPath, err)
+			os.Exit(1)
+		}
+		basePath := filepath.Join(packagingFormatTemplatesPath(t.packagingFormatName), destinationFile)
+		baseContent, err := ioutil.ReadFile(basePath)
+		if err != nil && !os.IsNotExist(err) {
+			log.Errorf("Failed to read %s: %v", basePath, err)
+			os.Exit(1)
+		}
+
+		switch {
+		case bytes.Equal(currentContent, hoverContent):
+			log.Printf("       %s: up to date", displayPath)
+		case baseContent != nil && bytes.Equal(baseContent, hoverContent):
+			log.Printf("       %s: modified, no hover update", displayPath)
+		case baseContent != nil && bytes.Equal(currentContent, baseContent):
+			err = ioutil.WriteFile(currentPath, hoverContent, 0664)
+			if err != nil {
+				log.Errorf("Failed to write %s: %v", currentPath, err)
+				os.Exit(1)
+			}
+			log.Infof("       %s: updated", displayPath)
+		default:
+			if mergeTemplate(currentPath, baseContent, hoverContent, displayPath) {
+				log.Infof("       %s: merged", displayPath)
+			} else {
+				log.Warnf("       %s: conflict", displayPath)
+				conflicts = append(conflicts, displayPath)
+			}
+		}
+		writeTemplateBase(t.packagingFormatName, destinationFile, hoverContent)
+	}
+
+	if len(conflicts) > 0 {
+		log.Warnf("The hover templates changed in a way that conflicts with your modifications.")
+		log.Warnf("Resolve the conflict markers (<<<<<<<, =======, >>>>>>>) in these files before packaging:")
+		for _, conflict := range conflicts {
+			log.Warnf("       %s", conflict)
+		}
+		os.Exit(1)
+	}
+	log.Infof("%s has been upgraded. Review the changes and add them to git, including %s.", t.packagingFormatName, filepath.Join(build.BuildPath, "packaging", ".templates"))
+}
+
+// mergeTemplate does a three-way merge of the upstream changes into the
+// current file using `git merge-file`. Projects initialized before hover kept
+// a copy of the templates have no base, which makes the whole file a
+// conflict. Returns false when there are conflicts left to resolve.
+func mergeTemplate(currentPath string, baseContent, hoverContent []byte, displayPath string) bool {
+	tmpDir, err := ioutil.TempDir(config.GetConfig().GetTmpDir(), "hover-upgrade-packaging")
+	if err != nil {
+		log.Errorf("Couldn't create temporary directory: %v", err)
+		os.Exit(1)
+	}
+	defer os.RemoveAll(tmpDir)
+	basePath := filepath.Join(tmpDir, "base")
+	hoverPath := filepath.Join(tmpDir, "hover")
+	err = ioutil.WriteFile(basePath, baseContent, 0664)
+	if err != nil {
+		log.Errorf("Failed to write %s: %v", basePath, err)
+		os.Exit(1)
+	}
+	err = ioutil.WriteFile(hoverPath, hoverContent, 0664)
+	if err != nil {
+		log.Errorf("Failed to write %s: %v", hoverPath, err)
+		os.Exit(1)
+	}
+
+	cmdMergeFile := exec.Command(build.GitBin(), "merge-file",
+		"-L", displayPath,
+		"-L", "base",
+		"-L", "hover",
+		currentPath, basePath, hoverPath,
+	)
+	cmdMergeFile.Stderr = os.Stderr
+	err = cmdMergeFile.Run()
+	if err == nil {
+		return true
+	}
+	// git merge-file exits with the number of conflicts, or a negative
+	// value on errors.
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128 {
+		return false
+	}
+	log.Errorf("Failed to merge %s: %v", displayPath, err)
+	os.Exit(1)
+	return false
+}
@@ -0,0 +1,90 @@
+package packaging
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+)
+
+// ErrNoSmokeTest is returned by Verify for the packaging formats that can't
+// be installed in a container.
+var ErrNoSmokeTest = errors.New("no smoke test for this packaging format")
+
+// ErrNoArtifact is returned by Verify when the packaging format wasn't built
+// for the version.
+var ErrNoArtifact = errors.New("not built")
+
+// smokeTest installs the artifact of a packaging format and starts the app.
+// The script is run with sh in a disposable container of the image, or with
+// bash on the host when there's no image. The artifact is at $ARTIFACT, and
+// smoke_run starts the app in a virtual display: it passes when the app exits
+// successfully, or is still running after the timeout.
+type smokeTest struct {
+	image  string
+	script string
+}
+
+// smokeTestPrelude defines smoke_run for the smoke test scripts.
+const smokeTestPrelude = `smoke_run() {
+	status=0
+	timeout "$SMOKE_TIMEOUT" xvfb-run -a "$@" || status=$?
+	if [ $status -eq 124 ]; then
+		echo "The app is still running after ${SMOKE_TIMEOUT}s"
+		return 0
+	fi
+	return $status
+}
+`
+
+// smokeTestUbuntuLibraries are the libraries of a desktop the go-flutter apps
+// are linked with, and xvfb-run, installed in the ubuntu image for the
+// packages without dependencies.
+const smokeTestUbuntuLibraries = "apt-get update -qq && DEBIAN_FRONTEND=noninteractive apt-get install -y -qq --no-install-recommends xvfb xauth libgl1 libegl1 libx11-6 libxrandr2 libxcursor1 libxinerama1 libxi6 libxxf86vm1 >/dev/null && "
+
+// Verify installs the artifact of the version and starts the app, reporting
+// the output of the smoke test when it fails.
+func (t *packagingTask) Verify(buildVersion string, timeout time.Duration) error {
+	if t.smokeTest == nil {
+		return ErrNoSmokeTest
+	}
+	projectName := pubspec.GetPubSpec().Name
+	artifactPath := filepath.Join(build.OutputDirectoryPath(t.packagingFormatName), t.artifactFileName(t.outputFileName(projectName, buildVersion), buildVersion))
+	if _, err := os.Stat(artifactPath); os.IsNotExist(err) {
+		return ErrNoArtifact
+	}
+	artifactPath, err := filepath.Abs(artifactPath)
+	if err != nil {
+		return err
+	}
+	script := smokeTestPrelude + executeStringTemplate(t.packagingFormatName+" smoke test", t.smokeTest.script, t.getTemplateData(projectName, buildVersion))
+	seconds := strconv.Itoa(int(timeout / time.Second))
+
+	var cmd *exec.Cmd
+	if t.smokeTest.image == "" {
+		cmd = exec.Command("bash", "-c", script)
+		cmd.Env = append(os.Environ(), "ARTIFACT="+artifactPath, "SMOKE_TIMEOUT="+seconds)
+	} else {
+		cmd = exec.Command(build.DockerBin(), "run", "--rm",
+			"--platform", "linux/"+build.TargetArch(),
+			"-v", filepath.Dir(artifactPath)+":/artifact:ro",
+			"-e", "ARTIFACT=/artifact/"+filepath.Base(artifactPath),
+			"-e", "SMOKE_TIMEOUT="+seconds,
+			t.smokeTest.image, "sh", "-c", script)
+	}
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	err = cmd.Run()
+	if err != nil {
+		return errors.Errorf("%v\n%s", err, output.String())
+	}
+	return nil
+}
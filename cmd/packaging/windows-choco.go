@@ -0,0 +1,33 @@
+package packaging
+
+import (
+	"regexp"
+	"strings"
+)
+
+// WindowsChocoTask packaging for windows as a Chocolatey package installing
+// the msi
+var WindowsChocoTask = &packagingTask{
+	packagingFormatName: "windows-choco",
+	dependsOn: map[*packagingTask]string{
+		WindowsMsiTask: "tools",
+	},
+	templateFiles: map[string]string{
+		"windows-choco/package.nuspec.tmpl":        "{{.packageName}}.nuspec.tmpl",
+		"windows-choco/chocolateyInstall.ps1.tmpl": "tools/chocolateyInstall.ps1.tmpl",
+	},
+	packagingScriptTemplate:       "choco pack {{shellquote .packageName \".nuspec\"}} --out . && mv -n *.nupkg {{shellquote .packageName \"-\" .version \".nupkg\"}}",
+	outputFileExtension:           "nupkg",
+	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
+	outputFileUsesApplicationName: false,
+	identity: []identityProperty{
+		{"{{.packageName}}.nuspec", "title", IdentityApplicationName, regexp.MustCompile(`<title>(.*?)</title>`), true},
+	},
+}
+
+// chocolateyVersion returns the version of a Chocolatey package, which
+// doesn't support the build metadata of semver, e.g. 1.2.3 for 1.2.3+4.
+func chocolateyVersion(version string) string {
+	return strings.SplitN(version, "+", 2)[0]
+}
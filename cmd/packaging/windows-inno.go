@@ -0,0 +1,43 @@
+package packaging
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// WindowsInnoTask packaging for windows as an Inno Setup installer
+var WindowsInnoTask = &packagingTask{
+	packagingFormatName: "windows-inno",
+	templateFiles: map[string]string{
+		"windows-inno/installer.iss.tmpl": "{{.packageName}}.iss.tmpl",
+	},
+	buildOutputDirectory: "build",
+	generateBuildFiles: func(packageName, tmpPath string) {
+		// the license page is only shown when there is a license file, from the
+		// project or added to go/packaging/windows-inno
+		for _, licenseFile := range licenseFiles {
+			if fileutils.IsFileExists(licenseFile) {
+				fileutils.CopyFile(licenseFile, filepath.Join(tmpPath, "LICENSE.txt"))
+				return
+			}
+		}
+		if _, err := os.Stat(filepath.Join(tmpPath, "LICENSE.txt")); os.IsNotExist(err) {
+			log.Printf("No license file in the project, the installer has no license page")
+		}
+	},
+	// ISCC runs natively on windows, with wine when ISCC is set to the path
+	// of ISCC.exe, and in the amake/innosetup docker image otherwise
+	packagingScriptTemplate:       "convert -resize x16 build/assets/icon.png build/assets/icon.ico && if command -v iscc >/dev/null 2>&1; then iscc /Q {{shellquote .packageName \".iss\"}}; elif [ -n \"$ISCC\" ]; then wine \"$ISCC\" /Q {{shellquote .packageName \".iss\"}}; else docker run --rm -v \"$PWD:/work\" amake/innosetup /Q {{shellquote .packageName \".iss\"}}; fi && mv -n setup.exe {{shellquote .applicationName \" \" .version \".exe\"}}",
+	outputFileExtension:           "exe",
+	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
+	outputFileUsesApplicationName: true,
+	identity: []identityProperty{
+		{"{{.packageName}}.iss", "AppName", IdentityApplicationName, regexp.MustCompile(`(?m)^AppName=(.*)$`), false},
+		{"{{.packageName}}.iss", "URL Protocol registry keys", IdentityURLSchemes, regexp.MustCompile(`Subkey: "Software\\Classes\\([^"\\]+)"; ValueType: string; ValueName: "URL Protocol"`), false},
+	},
+}
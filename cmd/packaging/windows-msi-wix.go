@@ -0,0 +1,277 @@
+package packaging
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/enginecache"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/retry"
+)
+
+// wixVersion is the version of the WiX toolset downloaded from nuget when
+// candle and light aren't installed.
+const wixVersion = "3.14.1"
+
+// windowsPackagingFunc packages the app in the temporary directory on
+// windows hosts, without the packaging script.
+type windowsPackagingFunc func(tmpPath string, templateData map[string]string) error
+
+// windowsMsiNativePackaging builds the msi on windows hosts with candle and
+// light of the WiX toolset, without bash, wixl and imagemagick.
+func windowsMsiNativePackaging(tmpPath string, templateData map[string]string) error {
+	err := writeIcoFile(filepath.Join(tmpPath, "build", "assets", "icon.png"), filepath.Join(tmpPath, "build", "assets", "icon.ico"))
+	if err != nil {
+		return err
+	}
+	wxsFileName := templateData["packageName"] + ".wxs"
+	wxs, err := ioutil.ReadFile(filepath.Join(tmpPath, wxsFileName))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", wxsFileName)
+	}
+	// wixl accepts the * of the template, candle requires a guid. It's derived
+	// from the identifier of the app so the packages upgrade each other.
+	wxs = bytes.Replace(wxs, []byte(`UpgradeCode="*"`), []byte(`UpgradeCode="`+windowsMsiUpgradeCode(templateData["preferencesID"])+`"`), 1)
+	err = ioutil.WriteFile(filepath.Join(tmpPath, wxsFileName), wxs, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write %s", wxsFileName)
+	}
+
+	candle, light, err := wixTools()
+	if err != nil {
+		return err
+	}
+	wixobjFileName := templateData["packageName"] + ".wixobj"
+	err = runWixTool(tmpPath, candle, "-nologo", "-arch", wixArch(build.TargetArch()), "-out", wixobjFileName, wxsFileName)
+	if err != nil {
+		return err
+	}
+	return runWixTool(tmpPath, light, "-nologo", "-out", templateData["applicationName"]+" "+templateData["version"]+".msi", wixobjFileName)
+}
+
+func runWixTool(dir, tool string, args ...string) error {
+	cmd := exec.Command(tool, args...)
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "%s failed", filepath.Base(tool))
+	}
+	return nil
+}
+
+// wixArch returns the -arch of candle for a target architecture.
+func wixArch(arch string) string {
+	switch arch {
+	case "386":
+		return "x86"
+	case "arm64":
+		return "arm64"
+	default:
+		return "x64"
+	}
+}
+
+// windowsMsiUpgradeCode returns a name-based (version 5) UUID of the
+// identifier of the app.
+func windowsMsiUpgradeCode(id string) string {
+	h := sha1.New()
+	// the namespace is the URL namespace of RFC 4122
+	h.Write([]byte{0x6b, 0xa7, 0xb8, 0x11, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8})
+	h.Write([]byte("hover:" + id))
+	sum := h.Sum(nil)
+	sum[6] = sum[6]&0x0f | 0x50
+	sum[8] = sum[8]&0x3f | 0x80
+	return strings.ToUpper(fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16]))
+}
+
+// wixTools returns the paths of candle and light: the ones in the PATH, else
+// the ones of the WiX installer, which sets the WIX environment variable,
+// else the ones of the WiX nuget package, downloaded to the hover cache.
+func wixTools() (candle, light string, err error) {
+	candle, candleErr := exec.LookPath("candle.exe")
+	light, lightErr := exec.LookPath("light.exe")
+	if candleErr == nil && lightErr == nil {
+		return candle, light, nil
+	}
+	if wix := os.Getenv("WIX"); wix != "" {
+		candle, light = filepath.Join(wix, "bin", "candle.exe"), filepath.Join(wix, "bin", "light.exe")
+		if fileutils.IsFileExists(candle) && fileutils.IsFileExists(light) {
+			return candle, light, nil
+		}
+	}
+	dir := filepath.Join(enginecache.DefaultCachePath(), "hover", "wix", wixVersion)
+	candle, light = filepath.Join(dir, "candle.exe"), filepath.Join(dir, "light.exe")
+	if fileutils.IsFileExists(candle) && fileutils.IsFileExists(light) {
+		return candle, light, nil
+	}
+	log.Infof("Downloading the WiX toolset %s to %s", wixVersion, dir)
+	err = retry.Do("Downloading the WiX toolset", func() error {
+		return downloadWix(dir)
+	})
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to download the WiX toolset, install it and add its bin directory to the PATH")
+	}
+	return candle, light, nil
+}
+
+// downloadWix extracts the tools directory of the WiX nuget package to dir.
+// The tools are extracted next to it first, an interrupted download doesn't
+// leave an incomplete toolset behind.
+func downloadWix(dir string) error {
+	resp, err := http.Get("https://www.nuget.org/api/v2/package/wix/" + wixVersion)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		err = errors.Errorf("nuget responded %s", resp.Status)
+		if !retry.IsTransientStatus(resp.StatusCode) {
+			return retry.Permanent(err)
+		}
+		return err
+	}
+	nupkg, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	archive, err := zip.NewReader(bytes.NewReader(nupkg), int64(len(nupkg)))
+	if err != nil {
+		return errors.Wrap(err, "invalid nuget package")
+	}
+	partialDir := dir + ".partial"
+	err = os.RemoveAll(partialDir)
+	if err != nil {
+		return err
+	}
+	for _, file := range archive.File {
+		if !strings.HasPrefix(file.Name, "tools/") || file.FileInfo().IsDir() {
+			continue
+		}
+		path := filepath.Join(partialDir, filepath.FromSlash(strings.TrimPrefix(file.Name, "tools/")))
+		if !strings.HasPrefix(path, partialDir+string(filepath.Separator)) {
+			return errors.Errorf("invalid file %s in the nuget package", file.Name)
+		}
+		err = extractZipFile(file, path)
+		if err != nil {
+			return err
+		}
+	}
+	err = os.RemoveAll(dir)
+	if err != nil {
+		return err
+	}
+	return os.Rename(partialDir, dir)
+}
+
+func extractZipFile(file *zip.File, path string) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	in, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// writeIcoFile writes an icon with a single png image, scaled down to the 256
+// pixels icons are limited to.
+func writeIcoFile(pngPath, icoPath string) error {
+	f, err := os.Open(pngPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to open the icon")
+	}
+	img, err := png.Decode(f)
+	f.Close()
+	if err != nil {
+		return errors.Wrapf(err, "failed to decode %s", filepath.Base(pngPath))
+	}
+	img = scaleDown(img, 256)
+	var data bytes.Buffer
+	err = png.Encode(&data, img)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the icon")
+	}
+	var ico bytes.Buffer
+	// the width and height of 256 pixels are written as 0
+	size := img.Bounds().Size()
+	binary.Write(&ico, binary.LittleEndian, struct {
+		Reserved, Type, Count uint16
+		Width, Height         uint8
+		Colors, Reserved2     uint8
+		Planes, BitsPerPixel  uint16
+		Size, Offset          uint32
+	}{0, 1, 1, uint8(size.X), uint8(size.Y), 0, 0, 1, 32, uint32(data.Len()), 22})
+	ico.Write(data.Bytes())
+	err = ioutil.WriteFile(icoPath, ico.Bytes(), 0644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write %s", filepath.Base(icoPath))
+	}
+	return nil
+}
+
+// scaleDown returns the image scaled down to fit in a square of max pixels,
+// averaging the pixels, or the image when it fits already.
+func scaleDown(img image.Image, max int) image.Image {
+	bounds := img.Bounds()
+	if bounds.Dx() <= max && bounds.Dy() <= max {
+		return img
+	}
+	width, height := max, max
+	if bounds.Dx() > bounds.Dy() {
+		height = bounds.Dy() * max / bounds.Dx()
+	} else {
+		width = bounds.Dx() * max / bounds.Dy()
+	}
+	scaled := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var r, g, b, a, n uint64
+			for sy := bounds.Min.Y + y*bounds.Dy()/height; sy < bounds.Min.Y+(y+1)*bounds.Dy()/height; sy++ {
+				for sx := bounds.Min.X + x*bounds.Dx()/width; sx < bounds.Min.X+(x+1)*bounds.Dx()/width; sx++ {
+					c := color.NRGBA64Model.Convert(img.At(sx, sy)).(color.NRGBA64)
+					// weighted by the alpha, transparent pixels don't darken the edges
+					r += uint64(c.R) * uint64(c.A)
+					g += uint64(c.G) * uint64(c.A)
+					b += uint64(c.B) * uint64(c.A)
+					a += uint64(c.A)
+					n++
+				}
+			}
+			if a > 0 {
+				scaled.SetNRGBA(x, y, color.NRGBA{uint8(r / a >> 8), uint8(g / a >> 8), uint8(b / a >> 8), uint8(a / n >> 8)})
+			}
+		}
+	}
+	return scaled
+}
@@ -1,12 +1,19 @@
 package packaging
 
 import (
+	"bytes"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/explain"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
 	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
 )
 
 var directoriesFileContent []string
@@ -20,10 +27,17 @@ var WindowsMsiTask = &packagingTask{
 		"windows-msi/app.wxs.tmpl": "{{.packageName}}.wxs.tmpl",
 	},
 	buildOutputDirectory:          "build",
-	packagingScriptTemplate:       "convert -resize x16 build/assets/icon.png build/assets/icon.ico && wixl -v {{.packageName}}.wxs && mv -n {{.packageName}}.msi \"{{.applicationName}} {{.version}}.msi\"",
+	packagingScriptTemplate:       "convert -resize x16 build/assets/icon.png build/assets/icon.ico && wixl -v {{shellquote .packageName \".wxs\"}} && mv -n {{shellquote .packageName \".msi\"}} {{shellquote .applicationName \" \" .version \".msi\"}}",
 	outputFileExtension:           "msi",
 	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
 	outputFileUsesApplicationName: true,
+	windowsPackaging:              windowsMsiNativePackaging,
+	smokeTest: &smokeTest{
+		// the app isn't started, only installed and uninstalled with wine on
+		// the host
+		script: "(command -v wine >/dev/null || { echo \"wine isn't installed\" && false; }) && export WINEPREFIX=\"$(mktemp -d)\" WINEDEBUG=-all && msi=\"$(winepath -w \"$ARTIFACT\")\" && wine msiexec /i \"$msi\" /qn && find \"$WINEPREFIX/drive_c\" -name {{shellquote .executableName \".exe\"}} | grep -q . && wine msiexec /x \"$msi\" /qn && ! find \"$WINEPREFIX/drive_c\" -name {{shellquote .executableName \".exe\"}} | grep -q . && rm -rf \"$WINEPREFIX\"",
+	},
 	generateBuildFiles: func(packageName, tmpPath string) {
 		directoriesFilePath, err := filepath.Abs(filepath.Join(tmpPath, "directories.wxi"))
 		if err != nil {
@@ -59,6 +73,9 @@ var WindowsMsiTask = &packagingTask{
 		directoryRefsFileContent = append(directoryRefsFileContent, `<Include>`)
 		componentRefsFileContent = append(componentRefsFileContent, `<Include>`)
 		windowsMsiProcessFiles(filepath.Join(tmpPath, "build", "flutter_assets"))
+		windowsMsiServices(packageName, tmpPath)
+		windowsMsiPreferences(packageName, tmpPath, PreferencesID(pubspec.GetPubSpec().Name))
+		windowsMsiFileAssociations(packageName, tmpPath, PreferencesID(pubspec.GetPubSpec().Name))
 		directoriesFileContent = append(directoriesFileContent, `</Include>`)
 		directoryRefsFileContent = append(directoryRefsFileContent, `</Include>`)
 		componentRefsFileContent = append(componentRefsFileContent, `</Include>`)
@@ -97,10 +114,15 @@ var WindowsMsiTask = &packagingTask{
 			os.Exit(1)
 		}
 	},
+	identity: []identityProperty{
+		{"{{.packageName}}.wxs", "Product Name", IdentityApplicationName, regexp.MustCompile(`<Product [^>]*Name="(.*?)"`), true},
+		{"{{.packageName}}.wxs", "executable File", IdentityExecutableName, regexp.MustCompile(`<File [^>]*Source="build/(.*?)\.exe"`), true},
+		{"{{.packageName}}.wxs", "URL Protocol RegistryKey", IdentityURLSchemes, regexp.MustCompile(`<RegistryKey Root="HKCR" Key="([^"\\]+)">\s*<RegistryValue Type="string" Value="URL:`), true},
+	},
 }
 
 func windowsMsiProcessFiles(path string) {
-	files, err := ioutil.ReadDir(path)
+	files, err := ioutil.ReadDir(fileutils.LongPath(path))
 	if err != nil {
 		log.Errorf("Failed to read directory %s: %v", path, err)
 		os.Exit(1)
@@ -139,3 +161,210 @@ func windowsMsiProcessFiles(path string) {
 		}
 	}
 }
+
+var wixIDInvalidCharacters = regexp.MustCompile(`[^A-Za-z0-9_.]`)
+
+// windowsMsiServiceAccounts are the built-in accounts running windows
+// services, by their name in go/hover.yaml.
+var windowsMsiServiceAccounts = map[string]string{
+	"":               "",
+	"LocalSystem":    "",
+	"LocalService":   `NT AUTHORITY\LocalService`,
+	"NetworkService": `NT AUTHORITY\NetworkService`,
+}
+
+// windowsMsiServices writes services.wxi, which installs the windows services
+// of go/hover.yaml with their executables, and adds their components to
+// component_refs.wxi.
+func windowsMsiServices(packageName, tmpPath string) {
+	services := config.GetConfig().WindowsServices
+	content := []string{`<Include>`}
+	if len(services) > 0 {
+		wxs, err := ioutil.ReadFile(filepath.Join(tmpPath, packageName+".wxs"))
+		if err != nil {
+			log.Errorf("Failed to read %s.wxs: %v", packageName, err)
+			os.Exit(1)
+		}
+		if !bytes.Contains(wxs, []byte("services.wxi")) {
+			log.Errorf("%s.wxs doesn't include services.wxi, run `%s` to install the windows services of go/hover.yaml.", packageName, log.Au().Magenta("hover upgrade-packaging windows-msi"))
+			os.Exit(1)
+		}
+	}
+	for _, service := range services {
+		if service.Name == "" || strings.ContainsAny(service.Name, `/\`) {
+			log.Errorf("Invalid windows service name %q in go/hover.yaml", service.Name)
+			os.Exit(1)
+		}
+		if service.Executable == "" || strings.ContainsAny(service.Executable, `/\`) {
+			log.Errorf("The executable of the windows service %s must be the name of a file of the build output", service.Name)
+			os.Exit(1)
+		}
+		if !fileutils.IsFileExists(filepath.Join(tmpPath, "build", service.Executable)) {
+			log.Errorf("The executable %s of the windows service %s isn't in the build output", service.Executable, service.Name)
+			os.Exit(1)
+		}
+		if service.Executable == config.GetConfig().GetExecutableName(pubspec.GetPubSpec().Name)+".exe" {
+			log.Errorf("The windows service %s must run a companion executable, not the app", service.Name)
+			os.Exit(1)
+		}
+		start := service.Start
+		if start == "" {
+			start = "auto"
+		}
+		if start != "auto" && start != "demand" && start != "disabled" {
+			log.Errorf("Invalid start type %q of the windows service %s, it must be auto, demand or disabled", start, service.Name)
+			os.Exit(1)
+		}
+		id := "Service_" + wixIDInvalidCharacters.ReplaceAllString(service.Name, "_")
+		displayName := service.DisplayName
+		if displayName == "" {
+			displayName = service.Name
+		}
+
+		serviceInstall := `<ServiceInstall Id="` + id + `" Name="` + fileutils.XMLEscape(service.Name) + `" DisplayName="` + fileutils.XMLEscape(displayName) + `"`
+		if service.Description != "" {
+			serviceInstall += ` Description="` + fileutils.XMLEscape(service.Description) + `"`
+		}
+		serviceInstall += ` Type="ownProcess" Start="` + start + `" ErrorControl="normal" Vital="yes"`
+		if account, ok := windowsMsiServiceAccounts[service.Account]; !ok {
+			// the password of a user account is set when installing the package
+			passwordProperty := strings.ToUpper(strings.TrimPrefix(id, "Service_")) + "_PASSWORD"
+			serviceInstall += ` Account="` + fileutils.XMLEscape(service.Account) + `" Password="[` + passwordProperty + `]"`
+		} else if account != "" {
+			serviceInstall += ` Account="` + account + `"`
+		}
+		if service.Arguments != "" {
+			serviceInstall += ` Arguments="` + fileutils.XMLEscape(service.Arguments) + `"`
+		}
+		serviceInstall += `/>`
+		serviceControl := `<ServiceControl Id="` + id + `" Name="` + fileutils.XMLEscape(service.Name) + `"`
+		if start == "auto" {
+			serviceControl += ` Start="install"`
+		}
+		serviceControl += ` Stop="both" Remove="uninstall" Wait="yes"/>`
+
+		content = append(content,
+			`<DirectoryRef Id="APPLICATIONROOTDIRECTORY">`,
+			`<Component Id="`+id+`" Guid="*">`,
+			`<File Id="`+id+`.exe" Source="build/`+fileutils.XMLEscape(service.Executable)+`" KeyPath="yes"/>`,
+			serviceInstall,
+			serviceControl,
+			`</Component>`,
+			`</DirectoryRef>`,
+		)
+		if failureActions := windowsMsiServiceFailureActions(service); failureActions != "" {
+			// wixl doesn't support the recovery options of ServiceInstall, they
+			// are set with sc.exe once the service is installed.
+			content = append(content,
+				`<CustomAction Id="`+id+`_Recovery" Directory="TARGETDIR" ExeCommand="`+fileutils.XMLEscape(`[SystemFolder]sc.exe failure "`+service.Name+`" `+failureActions)+`" Execute="deferred" Impersonate="no" Return="ignore"/>`,
+				`<InstallExecuteSequence>`,
+				`<Custom Action="`+id+`_Recovery" After="InstallServices">NOT REMOVE="ALL"</Custom>`,
+				`</InstallExecuteSequence>`,
+			)
+		}
+		componentRefsFileContent = append(componentRefsFileContent,
+			`<ComponentRef Id="`+id+`"/>`,
+		)
+	}
+	content = append(content, `</Include>`)
+	err := ioutil.WriteFile(filepath.Join(tmpPath, "services.wxi"), []byte(strings.Join(content, "\n")+"\n"), 0644)
+	if err != nil {
+		log.Errorf("Could not write services.wxi: %v", err)
+		os.Exit(1)
+	}
+}
+
+// windowsMsiServiceFailureActions returns the arguments of sc.exe failure
+// setting the recovery options of a windows service, empty when it has none.
+func windowsMsiServiceFailureActions(service config.WindowsServiceConfig) string {
+	recovery := service.Recovery
+	if len(recovery.Actions) == 0 {
+		return ""
+	}
+	if len(recovery.Actions) > 3 {
+		log.Errorf("The windows service %s has more than 3 recovery actions, windows takes actions on the first, second and subsequent failures", service.Name)
+		os.Exit(1)
+	}
+	restartDelay := recovery.RestartDelay
+	if restartDelay == 0 {
+		restartDelay = 60
+	}
+	resetPeriod := recovery.ResetPeriod
+	if resetPeriod == 0 {
+		resetPeriod = 24 * 60 * 60
+	}
+	var actions []string
+	for _, action := range recovery.Actions {
+		switch action {
+		case "none":
+			actions = append(actions, `""/0`)
+		case "restart", "reboot":
+			actions = append(actions, fmt.Sprintf("%s/%d", action, restartDelay*1000))
+		default:
+			log.Errorf("Invalid recovery action %q of the windows service %s, it must be none, restart or reboot", action, service.Name)
+			os.Exit(1)
+		}
+	}
+	return fmt.Sprintf("reset= %d actions= %s", resetPeriod, strings.Join(actions, "/"))
+}
+
+// windowsMsiFileAssociations writes file_associations.wxi, which registers the
+// file associations of go/hover.yaml with a ProgId opening the files with the
+// app, and adds their components to component_refs.wxi.
+func windowsMsiFileAssociations(packageName, tmpPath, preferencesID string) {
+	associations := fileAssociations()
+	content := []string{`<Include>`}
+	if len(associations) > 0 {
+		wxs, err := ioutil.ReadFile(filepath.Join(tmpPath, packageName+".wxs"))
+		if err != nil {
+			log.Errorf("Failed to read %s.wxs: %v", packageName, err)
+			os.Exit(1)
+		}
+		if !bytes.Contains(wxs, []byte("file_associations.wxi")) {
+			log.ErrorCodef(explain.TemplateOutdated, "%s.wxs doesn't include file_associations.wxi, run `%s` to register the file-associations of go/hover.yaml.", packageName, log.Au().Magenta("hover upgrade-packaging windows-msi"))
+			os.Exit(1)
+		}
+	}
+	executableFileID := config.GetConfig().GetExecutableName(pubspec.GetPubSpec().Name) + ".exe"
+	for _, association := range associations {
+		id := "FileAssociation_" + wixIDInvalidCharacters.ReplaceAllString(association.Extension, "_")
+		progID := preferencesID + "." + association.Extension
+		content = append(content,
+			`<DirectoryRef Id="APPLICATIONROOTDIRECTORY">`,
+			`<Component Id="`+id+`" Guid="*">`,
+		)
+		progIDElement := `<ProgId Id="` + fileutils.XMLEscape(progID) + `" Description="` + fileutils.XMLEscape(association.Description) + `" Advertise="no"`
+		if association.Icon != "" {
+			// checks the size of the icon
+			fileAssociationIcon(association)
+			iconPath := filepath.Join(tmpPath, "build", "assets", "file-"+association.Extension+".ico")
+			err := writeIcoFile(association.Icon, iconPath)
+			if err != nil {
+				log.Errorf("Failed to write the icon of the file association .%s: %v", association.Extension, err)
+				os.Exit(1)
+			}
+			content = append(content, `<File Id="`+id+`.ico" Source="build/assets/file-`+fileutils.XMLEscape(association.Extension)+`.ico" KeyPath="yes"/>`)
+			progIDElement += ` Icon="` + id + `.ico" IconIndex="0"`
+		} else {
+			content = append(content, `<RegistryValue Root="HKCU" Key="Software\`+fileutils.XMLEscape(preferencesID)+`\FileAssociations" Name="`+fileutils.XMLEscape(association.Extension)+`" Type="integer" Value="1" KeyPath="yes"/>`)
+		}
+		content = append(content,
+			progIDElement+`>`,
+			`<Extension Id="`+fileutils.XMLEscape(association.Extension)+`" ContentType="`+fileutils.XMLEscape(association.MimeType)+`">`,
+			`<Verb Id="open" Command="Open" TargetFile="`+fileutils.XMLEscape(executableFileID)+`" Argument="&quot;%1&quot;"/>`,
+			`</Extension>`,
+			`</ProgId>`,
+			`</Component>`,
+			`</DirectoryRef>`,
+		)
+		componentRefsFileContent = append(componentRefsFileContent,
+			`<ComponentRef Id="`+id+`"/>`,
+		)
+	}
+	content = append(content, `</Include>`)
+	err := ioutil.WriteFile(filepath.Join(tmpPath, "file_associations.wxi"), []byte(strings.Join(content, "\n")+"\n"), 0644)
+	if err != nil {
+		log.Errorf("Could not write file_associations.wxi: %v", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,43 @@
+package packaging
+
+import (
+	"regexp"
+	"strings"
+)
+
+// WindowsMsixTask packaging for windows as msix, for the Microsoft Store
+var WindowsMsixTask = &packagingTask{
+	packagingFormatName: "windows-msix",
+	templateFiles: map[string]string{
+		"windows-msix/AppxManifest.xml.tmpl": "msix/AppxManifest.xml.tmpl",
+	},
+	buildOutputDirectory: "msix/build",
+	// the resources.pri is only generated when makepri is available, the
+	// assets aren't qualified by scale so the package works without it
+	packagingScriptTemplate:       "mkdir -p msix/Assets && convert -resize 50x50 msix/build/assets/icon.png msix/Assets/StoreLogo.png && convert -resize 44x44 msix/build/assets/icon.png msix/Assets/Square44x44Logo.png && convert -resize 150x150 msix/build/assets/icon.png msix/Assets/Square150x150Logo.png && if command -v makepri >/dev/null 2>&1; then makepri createconfig /cf priconfig.xml /dq en-US /o && makepri new /pr msix /cf priconfig.xml /of msix/resources.pri /o; fi && if command -v makeappx >/dev/null 2>&1; then makeappx pack /o /d msix /p app.msix; else makemsix pack -d msix -p app.msix; fi && mv -n app.msix {{shellquote .applicationName \" \" .version \".msix\"}}",
+	outputFileExtension:           "msix",
+	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
+	outputFileUsesApplicationName: true,
+	identity: []identityProperty{
+		{"msix/AppxManifest.xml", "DisplayName", IdentityApplicationName, regexp.MustCompile(`<DisplayName>(.*?)</DisplayName>`), true},
+		{"msix/AppxManifest.xml", "uap:Protocol Name", IdentityURLSchemes, regexp.MustCompile(`<uap:Protocol Name="([^"]+)"`), true},
+	},
+}
+
+// msixNumericVersion matches the numeric parts of a version, e.g. 1.2.3 in
+// 1.2.3-beta+4.
+var msixNumericVersion = regexp.MustCompile(`^\d+(\.\d+){0,3}`)
+
+// msixVersion returns the version of a msix package: four numbers, the last
+// one being 0 as required by the Microsoft Store.
+func msixVersion(version string) string {
+	parts := strings.Split(msixNumericVersion.FindString(version), ".")
+	if parts[0] == "" {
+		parts[0] = "0"
+	}
+	for len(parts) < 3 {
+		parts = append(parts, "0")
+	}
+	return strings.Join(parts[:3], ".") + ".0"
+}
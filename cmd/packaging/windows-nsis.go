@@ -0,0 +1,45 @@
+package packaging
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// licenseFiles are the license files of the project shown by the installers,
+// in order of preference.
+var licenseFiles = []string{"LICENSE", "LICENSE.txt", "LICENSE.md", "COPYING"}
+
+// WindowsNsisTask packaging for windows as a NSIS installer
+var WindowsNsisTask = &packagingTask{
+	packagingFormatName: "windows-nsis",
+	templateFiles: map[string]string{
+		"windows-nsis/installer.nsi.tmpl": "{{.packageName}}.nsi.tmpl",
+	},
+	buildOutputDirectory: "build",
+	generateBuildFiles: func(packageName, tmpPath string) {
+		// the license page is only shown when there is a license file, from the
+		// project or added to go/packaging/windows-nsis
+		for _, licenseFile := range licenseFiles {
+			if fileutils.IsFileExists(licenseFile) {
+				fileutils.CopyFile(licenseFile, filepath.Join(tmpPath, "LICENSE.txt"))
+				return
+			}
+		}
+		if _, err := os.Stat(filepath.Join(tmpPath, "LICENSE.txt")); os.IsNotExist(err) {
+			log.Printf("No license file in the project, the installer has no license page")
+		}
+	},
+	packagingScriptTemplate:       "convert -resize x16 build/assets/icon.png build/assets/icon.ico && makensis -V2 {{shellquote .packageName \".nsi\"}} && mv -n setup.exe {{shellquote .applicationName \" \" .version \".exe\"}}",
+	outputFileExtension:           "exe",
+	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
+	outputFileUsesApplicationName: true,
+	identity: []identityProperty{
+		{"{{.packageName}}.nsi", "Name", IdentityApplicationName, regexp.MustCompile(`(?m)^Name "(.*)"`), false},
+		{"{{.packageName}}.nsi", "URL Protocol registry keys", IdentityURLSchemes, regexp.MustCompile(`WriteRegStr \w+ "Software\\Classes\\([^"\\]+)" "URL Protocol"`), false},
+	},
+}
@@ -0,0 +1,21 @@
+package packaging
+
+import "regexp"
+
+// WindowsPortableTask packaging for windows as a portable folder
+var WindowsPortableTask = &packagingTask{
+	packagingFormatName: "windows-portable",
+	templateFiles: map[string]string{
+		"windows-portable/launcher.cmd.tmpl": "{{.applicationName}}/{{.applicationName}}.cmd.tmpl",
+	},
+	buildOutputDirectory:          "{{.applicationName}}/app",
+	launcherFile:                  "{{.applicationName}}/{{.applicationName}}.cmd",
+	packagingScriptTemplate:       "zip -qr {{shellquote .applicationName \" \" .version \".zip\"}} {{shellquote .applicationName}}",
+	outputFileExtension:           "zip",
+	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
+	outputFileUsesApplicationName: true,
+	identity: []identityProperty{
+		{"{{.applicationName}}/{{.applicationName}}.cmd", "executable", IdentityExecutableName, regexp.MustCompile(`start "" "(?:%~dp0app\\)?(.*?)\.exe"`), false},
+	},
+}
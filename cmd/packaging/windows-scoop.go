@@ -0,0 +1,115 @@
+package packaging
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// WindowsScoopTask packaging for windows as the Scoop manifest of the
+// portable zip, to be added to a bucket
+var WindowsScoopTask = &packagingTask{
+	packagingFormatName: "windows-scoop",
+	dependsOn: map[*packagingTask]string{
+		WindowsPortableTask: "portable",
+	},
+	templateFiles: map[string]string{
+		"windows-scoop/manifest.json.tmpl": "{{.packageName}}.json.tmpl",
+	},
+	generateBuildFiles: windowsScoopManifest,
+	// the manifest is complete once its hash is filled in
+	packagingScriptTemplate:       "",
+	outputFileExtension:           "json",
+	outputFileContainsVersion:     false,
+	outputFileContainsArch:        false,
+	outputFileUsesApplicationName: false,
+	identity: []identityProperty{
+		{"{{.packageName}}.json", "extract_dir", IdentityApplicationName, regexp.MustCompile(`"extract_dir": "(.*?)"`), false},
+	},
+}
+
+// windowsScoopManifest fills in the checksum of the portable zip in the
+// Scoop manifest, and checks the fields Scoop requires.
+func windowsScoopManifest(packageName, tmpPath string) {
+	zipPaths, _ := filepath.Glob(filepath.Join(tmpPath, "portable", "*.zip"))
+	if len(zipPaths) != 1 {
+		log.Errorf("Expected the zip of windows-portable in the scoop portable directory, found %d files", len(zipPaths))
+		os.Exit(1)
+	}
+	checksum, err := fileutils.SHA256File(zipPaths[0])
+	if err != nil {
+		log.Errorf("Failed to compute the checksum of %s: %v", filepath.Base(zipPaths[0]), err)
+		os.Exit(1)
+	}
+
+	manifestPath := filepath.Join(tmpPath, packageName+".json")
+	content, err := ioutil.ReadFile(manifestPath)
+	if err != nil {
+		log.Errorf("Failed to read %s.json: %v", packageName, err)
+		os.Exit(1)
+	}
+	content = regexp.MustCompile(`"hash":\s*"[^"]*"`).ReplaceAll(content, []byte(`"hash": "`+checksum+`"`))
+	err = ioutil.WriteFile(manifestPath, content, 0644)
+	if err != nil {
+		log.Errorf("Failed to write %s.json: %v", packageName, err)
+		os.Exit(1)
+	}
+
+	var manifest struct {
+		Version      string
+		URL          interface{}
+		Architecture map[string]struct {
+			URL interface{}
+		}
+		Bin interface{}
+	}
+	err = json.Unmarshal(content, &manifest)
+	if err != nil {
+		log.Errorf("Failed to decode the scoop manifest %s.json: %v", packageName, err)
+		os.Exit(1)
+	}
+	var errs []string
+	if manifest.Version == "" {
+		errs = append(errs, "the version is empty")
+	}
+	urls := []interface{}{manifest.URL}
+	for _, architecture := range manifest.Architecture {
+		urls = append(urls, architecture.URL)
+	}
+	hasURL := false
+	for _, u := range urls {
+		switch u := u.(type) {
+		case string:
+			hasURL = true
+			if u == "" {
+				errs = append(errs, "the url is empty, set the url of scoop in go/hover.yaml to the URL the zip is published at")
+			} else if parsed, err := url.Parse(u); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+				errs = append(errs, "the url "+u+" isn't an http(s) URL")
+			}
+		case []interface{}:
+			hasURL = true
+		}
+	}
+	if !hasURL {
+		errs = append(errs, "the manifest has no url")
+	}
+	if manifest.Bin == nil {
+		errs = append(errs, "the manifest has no bin")
+	}
+	if len(errs) > 0 {
+		for _, e := range errs {
+			log.Errorf("Invalid scoop manifest: %s", e)
+		}
+		os.Exit(1)
+	}
+	if !strings.Contains(string(content), checksum) {
+		log.Warnf("The scoop manifest has no hash to fill in, it doesn't check the zip it downloads.")
+	}
+}
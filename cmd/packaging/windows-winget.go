@@ -0,0 +1,148 @@
+package packaging
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// WindowsWingetTask packaging for windows as the winget manifests of the
+// msi, ready to be submitted to winget-pkgs
+var WindowsWingetTask = &packagingTask{
+	packagingFormatName: "windows-winget",
+	dependsOn: map[*packagingTask]string{
+		WindowsMsiTask: "installer",
+	},
+	templateFiles: map[string]string{
+		"windows-winget/version.yaml.tmpl":   "manifest/{{.wingetPackageIdentifier}}.yaml.tmpl",
+		"windows-winget/installer.yaml.tmpl": "manifest/{{.wingetPackageIdentifier}}.installer.yaml.tmpl",
+		"windows-winget/locale.yaml.tmpl":    "manifest/{{.wingetPackageIdentifier}}.locale.en-US.yaml.tmpl",
+	},
+	generateBuildFiles:            windowsWingetManifests,
+	packagingScriptTemplate:       "if command -v winget >/dev/null; then winget validate --manifest manifest; fi && (cd manifest && zip -q {{shellquote \"../\" .packageName \"-\" .version \".zip\"}} *.yaml)",
+	outputFileExtension:           "zip",
+	outputFileContainsVersion:     true,
+	outputFileContainsArch:        true,
+	outputFileUsesApplicationName: false,
+	identity: []identityProperty{
+		{"manifest/{{.wingetPackageIdentifier}}.locale.en-US.yaml", "PackageName", IdentityApplicationName, regexp.MustCompile(`(?m)^PackageName: "(.*?)"`), false},
+	},
+}
+
+// wingetPackageIdentifier matches the PackageIdentifier of the winget
+// manifests, two to eight segments without spaces or special characters.
+var wingetPackageIdentifier = regexp.MustCompile(`^[^.\s\\/:*?"<>|\x01-\x1f]{1,32}(\.[^.\s\\/:*?"<>|\x01-\x1f]{1,32}){1,7}$`)
+
+// windowsWingetManifests fills in the checksum of the msi in the installer
+// manifest, and validates the manifests as winget-pkgs does: the winget
+// validation only runs on windows.
+func windowsWingetManifests(packageName, tmpPath string) {
+	msiPaths, _ := filepath.Glob(filepath.Join(tmpPath, "installer", "*.msi"))
+	if len(msiPaths) != 1 {
+		log.Errorf("Expected the msi of windows-msi in the winget installer directory, found %d files", len(msiPaths))
+		os.Exit(1)
+	}
+	checksum, err := fileutils.SHA256File(msiPaths[0])
+	if err != nil {
+		log.Errorf("Failed to compute the checksum of %s: %v", filepath.Base(msiPaths[0]), err)
+		os.Exit(1)
+	}
+	// winget writes the checksums in uppercase
+	checksum = strings.ToUpper(checksum)
+
+	manifestPaths, _ := filepath.Glob(filepath.Join(tmpPath, "manifest", "*.yaml"))
+	manifests := make(map[string]map[string]interface{})
+	for _, path := range manifestPaths {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			log.Errorf("Failed to read %s: %v", filepath.Base(path), err)
+			os.Exit(1)
+		}
+		var manifest map[string]interface{}
+		if strings.HasSuffix(path, ".installer.yaml") {
+			content = regexp.MustCompile(`(?m)^([ \t]*InstallerSha256:).*$`).ReplaceAll(content, []byte("${1} "+checksum))
+			err = ioutil.WriteFile(path, content, 0644)
+			if err != nil {
+				log.Errorf("Failed to write %s: %v", filepath.Base(path), err)
+				os.Exit(1)
+			}
+		}
+		err = yaml.Unmarshal(content, &manifest)
+		if err != nil {
+			log.Errorf("Failed to decode the winget manifest %s: %v", filepath.Base(path), err)
+			os.Exit(1)
+		}
+		manifestType, _ := manifest["ManifestType"].(string)
+		if _, ok := manifests[manifestType]; ok {
+			log.Errorf("Several winget manifests have the ManifestType %s", manifestType)
+			os.Exit(1)
+		}
+		manifests[manifestType] = manifest
+	}
+
+	var errs []string
+	for _, manifestType := range []string{"version", "installer", "defaultLocale"} {
+		if _, ok := manifests[manifestType]; !ok {
+			errs = append(errs, "the manifest with the ManifestType "+manifestType+" is missing")
+		}
+	}
+	identifier, _ := manifests["version"]["PackageIdentifier"].(string)
+	version, _ := manifests["version"]["PackageVersion"].(string)
+	if !wingetPackageIdentifier.MatchString(identifier) {
+		errs = append(errs, "the PackageIdentifier "+identifier+" isn't <Publisher>.<Name>, set the package-identifier of winget in go/hover.yaml")
+	}
+	if version == "" {
+		errs = append(errs, "the PackageVersion is empty")
+	}
+	for manifestType, manifest := range manifests {
+		if manifest["PackageIdentifier"] != identifier || manifest["PackageVersion"] != version {
+			errs = append(errs, "the PackageIdentifier and PackageVersion of the "+manifestType+" manifest don't match the version manifest")
+		}
+	}
+	if !windowsWingetFileNameMatches(manifestPaths, identifier) {
+		errs = append(errs, "the file names of the manifests don't start with the PackageIdentifier "+identifier+", rename them in go/packaging/windows-winget/manifest")
+	}
+	if installers, ok := manifests["installer"]["Installers"].([]interface{}); ok {
+		for _, installer := range installers {
+			installer, _ := installer.(map[interface{}]interface{})
+			installerURL, _ := installer["InstallerUrl"].(string)
+			if installerURL == "" {
+				errs = append(errs, "the InstallerUrl is empty, set the installer-url of winget in go/hover.yaml to the URL the msi is published at")
+			} else if u, err := url.Parse(installerURL); err != nil || u.Scheme != "https" {
+				errs = append(errs, "the InstallerUrl "+installerURL+" isn't an https URL")
+			}
+		}
+	} else if manifests["installer"] != nil {
+		errs = append(errs, "the installer manifest has no Installers")
+	}
+	for _, key := range []string{"Publisher", "PackageName", "License", "ShortDescription"} {
+		if value, _ := manifests["defaultLocale"][key].(string); strings.TrimSpace(value) == "" && manifests["defaultLocale"] != nil {
+			errs = append(errs, "the "+key+" of the defaultLocale manifest is empty")
+		}
+	}
+	if len(errs) > 0 {
+		for _, e := range errs {
+			log.Errorf("Invalid winget manifests: %s", e)
+		}
+		os.Exit(1)
+	}
+}
+
+// windowsWingetFileNameMatches returns whether the manifest files are named
+// after the PackageIdentifier, as winget-pkgs requires.
+func windowsWingetFileNameMatches(manifestPaths []string, identifier string) bool {
+	for _, path := range manifestPaths {
+		if !strings.HasPrefix(filepath.Base(path), identifier+".") {
+			return false
+		}
+	}
+	return true
+}
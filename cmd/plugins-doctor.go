@@ -0,0 +1,319 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+	"github.com/go-flutter-desktop/hover/internal/report"
+)
+
+// dartChannelPattern matches the platform channels created with a literal
+// name in dart code.
+var dartChannelPattern = regexp.MustCompile(`\b(?:Optional)?(?:Method|Event|BasicMessage)Channel\s*(?:<[^>(]*>)?\s*\(\s*(?:name:\s*)?(?:'([^'$]+)'|"([^"$]+)")`)
+
+// goChannelPattern matches the platform channels created by the go-flutter
+// plugins, with a literal name or a constant.
+var goChannelPattern = regexp.MustCompile(`\bplugin\.New(?:Method|Event|BasicMessage)Channel\(\s*[^,]+,\s*(?:"([^"]+)"|(\w+))`)
+
+// goStringConstantPattern matches the string constants of go code, the
+// channel names are often declared as constants.
+var goStringConstantPattern = regexp.MustCompile(`(?m)^\s*(?:const\s+)?(\w+)\s*(?:string\s*)?=\s*"([^"]*)"`)
+
+func init() {
+	addReportFlags(pluginDoctorCmd)
+	pluginCmd.AddCommand(pluginDoctorCmd)
+}
+
+var pluginDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that the platform channels of the dart packages are implemented by go plugins",
+	Long: "Compare the names of the platform channels the dart code of the app and of its pub packages creates with the channels registered by the go code of the app and of its imported go-flutter plugins.\n" +
+		"A channel without go implementation throws a MissingPluginException at runtime. The command exits with an error when channels are missing, so it can be run in CI.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertReportFormat()
+		assertInFlutterProject()
+		assertHoverInitialized()
+
+		dartChannels, err := dartPackageChannels()
+		if err != nil {
+			log.Errorf("%v", err)
+			os.Exit(1)
+		}
+		goChannels, err := goPluginChannels()
+		if err != nil {
+			log.Errorf("%v", err)
+			os.Exit(1)
+		}
+
+		var packages []string
+		for name := range dartChannels {
+			packages = append(packages, name)
+		}
+		sort.Strings(packages)
+		suite := report.Suite{Name: "plugins doctor"}
+		var missing int
+		used := map[string]bool{}
+		for _, name := range packages {
+			for _, channel := range dartChannels[name] {
+				used[channel] = true
+				channelCase := report.Case{Name: name + ": " + channel}
+				if source, ok := goChannels[channel]; ok {
+					log.Infof("     [OK]      %s: %s, registered by %s", name, channel, source)
+					suite.Cases = append(suite.Cases, channelCase)
+					continue
+				}
+				missing++
+				var message string
+				if similar := similarChannel(channel, goChannels); similar != "" {
+					message = fmt.Sprintf("%s isn't registered in go, but %s of %s is. The names must match exactly.", channel, similar, goChannels[similar])
+				} else {
+					message = fmt.Sprintf("%s isn't registered in go, the calls throw a MissingPluginException.", channel)
+				}
+				log.Warnf("     [Missing] %s: %s", name, message)
+				channelCase.Problems = []report.Problem{{Message: message}}
+				suite.Cases = append(suite.Cases, channelCase)
+			}
+		}
+		writeReport(suite)
+
+		var unused []string
+		for channel := range goChannels {
+			if !used[channel] {
+				unused = append(unused, channel)
+			}
+		}
+		sort.Strings(unused)
+		for _, channel := range unused {
+			log.Infof("     [Unused]  %s, registered by %s, isn't created by the dart code", channel, goChannels[channel])
+		}
+
+		if missing > 0 {
+			log.Errorf("%d platform channels have no go implementation. Run `%s` to import the go-flutter plugins, or implement the channels in go/cmd.", missing, log.Au().Magenta("hover plugins get"))
+			os.Exit(1)
+		}
+		log.Infof("The platform channels of the dart code are implemented in go.")
+	},
+}
+
+// dartPackageChannels returns the names of the platform channels created by
+// the dart code of the app and of the packages of pubspec.lock, by package.
+func dartPackageChannels() (map[string][]string, error) {
+	pubcachePath, err := findPubcachePath()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find path for pub-cache")
+	}
+	pubLock, err := readPubSpecLock()
+	if err != nil {
+		log.Infof("Run `%s` (or equivalent) first", log.Au().Magenta("flutter pub get"))
+		return nil, err
+	}
+	packagePaths := map[string]string{"the app": "."}
+	for name, entry := range pubLock.Packages {
+		packagePath, err := entry.locate(name, pubcachePath)
+		if err != nil {
+			return nil, err
+		}
+		if packagePath != "" && !mobileImplementation(packagePath) {
+			packagePaths[name] = packagePath
+		}
+	}
+
+	channels := map[string][]string{}
+	for name, packagePath := range packagePaths {
+		seen := map[string]bool{}
+		err := filepath.Walk(filepath.Join(packagePath, "lib"), func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if info.IsDir() || filepath.Ext(path) != ".dart" {
+				return nil
+			}
+			code, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			for _, match := range dartChannelPattern.FindAllStringSubmatch(string(code), -1) {
+				channel := match[1] + match[2]
+				if !seen[channel] {
+					seen[channel] = true
+					channels[name] = append(channels[name], channel)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the dart code of %s", name)
+		}
+		sort.Strings(channels[name])
+	}
+	return channels, nil
+}
+
+// mobileImplementation returns whether a package is the implementation of a
+// federated plugin for other platforms than the desktop ones, e.g.
+// url_launcher_android, whose channels aren't used on desktop.
+func mobileImplementation(packagePath string) bool {
+	packagePubspec, err := pubspec.ReadPubSpecFile(filepath.Join(packagePath, "pubspec.yaml"))
+	if err != nil {
+		return false
+	}
+	plugin, ok := packagePubspec.Flutter["plugin"].(map[interface{}]interface{})
+	if !ok {
+		return false
+	}
+	if _, ok := plugin["implements"]; !ok {
+		return false
+	}
+	platforms, _ := plugin["platforms"].(map[interface{}]interface{})
+	for _, platform := range []string{"linux", "macos", "windows"} {
+		if _, ok := platforms[platform]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// goPluginChannels returns the names of the platform channels registered by
+// the go code of the app and of its imported go-flutter plugins, with the go
+// package registering them.
+func goPluginChannels() (map[string]string, error) {
+	cmdPath := filepath.Join(build.BuildPath, "cmd")
+	sources := map[string]string{"go/cmd": cmdPath}
+	imports, err := filepath.Glob(filepath.Join(cmdPath, "import-*-plugin.go"))
+	if err != nil {
+		return nil, err
+	}
+	if len(imports) > 0 {
+		moduleDirs, err := goModuleDirs()
+		if err != nil {
+			return nil, err
+		}
+		for _, importFile := range imports {
+			pluginName := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(importFile), "import-"), "-plugin.go")
+			importPath, err := readPluginGoImport(importFile, pluginName)
+			if err != nil {
+				log.Warnf("Skipping the plugin %s: %v", pluginName, err)
+				continue
+			}
+			dir := packageDir(importPath, moduleDirs)
+			if dir == "" {
+				log.Warnf("Skipping the plugin %s, the module of %s isn't downloaded. Run `%s` in go first.", pluginName, importPath, log.Au().Magenta("go mod download"))
+				continue
+			}
+			sources[importPath] = dir
+		}
+	}
+
+	channels := map[string]string{}
+	for source, dir := range sources {
+		files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			return nil, err
+		}
+		var code strings.Builder
+		for _, file := range files {
+			if strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+			content, err := ioutil.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			code.Write(content)
+			code.WriteString("\n")
+		}
+		constants := map[string]string{}
+		for _, match := range goStringConstantPattern.FindAllStringSubmatch(code.String(), -1) {
+			constants[match[1]] = match[2]
+		}
+		for _, match := range goChannelPattern.FindAllStringSubmatch(code.String(), -1) {
+			channel := match[1]
+			if match[2] != "" {
+				channel = constants[match[2]]
+			}
+			if channel != "" {
+				channels[channel] = source
+			}
+		}
+	}
+	return channels, nil
+}
+
+// goModuleDirs returns the directories of the downloaded modules of the go
+// module of the app, by module path.
+func goModuleDirs() (map[string]string, error) {
+	cmdGoList := exec.Command(build.GoBin(), "list", "-m", "-f", "{{.Path}} {{.Dir}}", "all")
+	cmdGoList.Dir = build.BuildPath
+	cmdGoList.Stderr = os.Stderr
+	output, err := cmdGoList.Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the go modules of the app")
+	}
+	dirs := map[string]string{}
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) == 2 && fields[1] != "" {
+			dirs[fields[0]] = fields[1]
+		}
+	}
+	return dirs, nil
+}
+
+// packageDir returns the directory of a go package, in the directory of the
+// module with the longest matching path.
+func packageDir(importPath string, moduleDirs map[string]string) string {
+	var modulePaths []string
+	for modulePath := range moduleDirs {
+		modulePaths = append(modulePaths, modulePath)
+	}
+	module := moduleOf(importPath, modulePaths)
+	if module == "" {
+		return ""
+	}
+	return filepath.Join(moduleDirs[module], filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(importPath, module), "/")))
+}
+
+// moduleOf returns the module of a go package, the one with the longest
+// matching path.
+func moduleOf(importPath string, modulePaths []string) string {
+	var module string
+	for _, modulePath := range modulePaths {
+		if (importPath == modulePath || strings.HasPrefix(importPath, modulePath+"/")) && len(modulePath) > len(module) {
+			module = modulePath
+		}
+	}
+	return module
+}
+
+// similarChannel returns a registered channel whose name differs from a
+// channel of the dart code by its case or its prefix, the usual mistakes.
+func similarChannel(channel string, registered map[string]string) string {
+	var names []string
+	for name := range registered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.EqualFold(name, channel) || path.Base(name) == path.Base(channel) {
+			return name
+		}
+	}
+	return ""
+}
@@ -384,29 +384,12 @@ func listPlatformPlugin() ([]PubDep, error) {
 	}
 
 	for name, entry := range pubLock.Packages {
-		entry.name = name
-
-		switch i := entry.Description.(type) {
-		case string:
-			if i == "flutter" {
-				continue
-			}
-		case map[interface{}]interface{}:
-			if value, ok := i["path"]; ok {
-				entry.path = value.(string)
-			}
-			if value, ok := i["url"]; ok {
-				url, err := url.Parse(value.(string))
-				if err != nil {
-					return nil, errors.Wrap(err, "failed to parse URL from string %s"+value.(string))
-				}
-				entry.host = url.Host
-			}
+		pluginPath, err := entry.locate(name, pubcachePath)
+		if err != nil {
+			return nil, err
 		}
-
-		pluginPath := filepath.Join(pubcachePath, "hosted", entry.host, entry.name+"-"+entry.Version)
-		if entry.path != "" {
-			pluginPath = entry.path
+		if pluginPath == "" {
+			continue
 		}
 
 		pluginPubspecPath := filepath.Join(pluginPath, "pubspec.yaml")
@@ -479,6 +462,34 @@ func listPlatformPlugin() ([]PubDep, error) {
 	return list, nil
 }
 
+// locate sets the name, path and host of a pubspec.lock entry from its
+// description, and returns the directory of the package. The packages of the
+// flutter SDK have no directory.
+func (p *PubDep) locate(name, pubcachePath string) (string, error) {
+	p.name = name
+	switch i := p.Description.(type) {
+	case string:
+		if i == "flutter" {
+			return "", nil
+		}
+	case map[interface{}]interface{}:
+		if value, ok := i["path"]; ok {
+			p.path = value.(string)
+		}
+		if value, ok := i["url"]; ok {
+			url, err := url.Parse(value.(string))
+			if err != nil {
+				return "", errors.Wrap(err, "failed to parse URL from string %s"+value.(string))
+			}
+			p.host = url.Host
+		}
+	}
+	if p.path != "" {
+		return p.path, nil
+	}
+	return filepath.Join(pubcachePath, "hosted", p.host, p.name+"-"+p.Version), nil
+}
+
 // readLocal reads pubspec.lock in the current working directory.
 func readPubSpecLock() (*PubSpecLock, error) {
 	p := &PubSpecLock{}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/go-flutter-desktop/hover/cmd/packaging"
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+var buildSkipPreflight bool
+
+const (
+	// engineSizeEstimate is the size of the engine and its artifacts, when
+	// there is no previous build output to estimate the size of a build.
+	engineSizeEstimate = 100 << 20
+	// preflightMargin is added to the estimates for the go binary and the
+	// files written by the packaging tools.
+	preflightMargin = 100 << 20
+)
+
+// assertBuildPreflight checks that the output and temporary directories are
+// writable and have enough free space for the build, so it doesn't fail
+// halfway through copying the outputs. The sizes are estimated from the
+// previous build output.
+func assertBuildPreflight(targetOS string, packagingTask packaging.Task) {
+	if buildSkipPreflight {
+		return
+	}
+	outputsPath := filepath.Dir(build.OutputDirectoryPath(targetOS))
+	assertWritable(outputsPath, "Check the permissions of the go/build directory.")
+
+	previousBuildSize := dirSize(build.OutputDirectoryPath(targetOS))
+	buildSize := previousBuildSize + preflightMargin
+	if previousBuildSize == 0 {
+		buildSize = engineSizeEstimate + dirSize(build.IntermediatesDirectoryPath(targetOS)) + preflightMargin
+	}
+
+	// the outputs being replaced are removed before writing the new ones
+	var requiredSpace, reclaimedSpace uint64
+	if buildSkipFlutterBuildBundle {
+		requiredSpace += preflightMargin
+	} else {
+		requiredSpace += buildSize
+		reclaimedSpace += previousBuildSize
+	}
+	if packagingTask.Name() != "" {
+		reclaimedSpace += dirSize(build.OutputDirectoryPath(targetName(targetOS, packagingTask)))
+		// the package, and its copy in the packaging cache
+		requiredSpace += buildSize
+		if !packaging.NoCache {
+			requiredSpace += buildSize
+		}
+	}
+	assertFreeSpace(outputsPath, requiredSpace, reclaimedSpace, "Free some space, or use --skip-preflight if the estimate is wrong.")
+
+	if packagingTask.Name() == "" {
+		return
+	}
+	tmpDir := config.GetConfig().GetTmpDir()
+	hint := "Set tmp-dir in go/hover.yaml or HOVER_TMPDIR to package in another directory."
+	assertWritable(tmpDir, hint)
+	assertExecutable(tmpDir, hint)
+	// the build output copied to the packaging directory, and the package
+	assertFreeSpace(tmpDir, 2*buildSize, 0, hint)
+}
+
+func assertWritable(dir, hint string) {
+	err := os.MkdirAll(dir, 0775)
+	if err != nil {
+		log.Errorf("Cannot create %s: %v. %s", dir, err, hint)
+		os.Exit(1)
+	}
+	probe, err := ioutil.TempFile(dir, ".hover-preflight")
+	if err != nil {
+		log.Errorf("Cannot write to %s: %v. %s", dir, err, hint)
+		os.Exit(1)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+}
+
+// assertExecutable checks that the files of a directory can be executed,
+// which isn't the case on filesystems mounted noexec. The packaging tools run
+// scripts of the packaging directory.
+func assertExecutable(dir, hint string) {
+	if runtime.GOOS == "windows" {
+		return
+	}
+	probe, err := ioutil.TempFile(dir, ".hover-preflight")
+	if err != nil {
+		log.Errorf("Cannot write to %s: %v. %s", dir, err, hint)
+		os.Exit(1)
+	}
+	_, err = probe.WriteString("#!/bin/sh\nexit 0\n")
+	probe.Close()
+	if err == nil {
+		err = os.Chmod(probe.Name(), 0755)
+	}
+	if err == nil {
+		err = exec.Command(probe.Name()).Run()
+	}
+	os.Remove(probe.Name())
+	if err != nil {
+		log.Errorf("Cannot execute files in %s, it may be mounted noexec: %v. %s", dir, err, hint)
+		os.Exit(1)
+	}
+}
+
+func assertFreeSpace(dir string, requiredSpace, reclaimedSpace uint64, hint string) {
+	freeSpace, err := fileutils.FreeSpace(dir)
+	if err != nil {
+		log.Warnf("Skipping the free space check: %v", err)
+		return
+	}
+	if freeSpace+reclaimedSpace < requiredSpace {
+		log.Errorf("Not enough free space in %s: the build needs about %s, %s are available. %s", dir, formatSize(requiredSpace), formatSize(freeSpace+reclaimedSpace), hint)
+		os.Exit(1)
+	}
+}
+
+func dirSize(path string) uint64 {
+	size, err := fileutils.DirSize(path)
+	if err != nil {
+		log.Warnf("%v", err)
+	}
+	return size
+}
+
+func formatSize(size uint64) string {
+	return fmt.Sprintf("%.0f MB", float64(size)/(1<<20))
+}
@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/go-flutter-desktop/hover/cmd/packaging"
+	"github.com/go-flutter-desktop/hover/internal/androidmanifest"
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+)
+
+var (
+	renameApplicationName string
+	renamePackageName     string
+	renameExecutableName  string
+	renameBundleID        string
+	renameDryRun          bool
+)
+
+const androidManifestPath = "android/app/src/main/AndroidManifest.xml"
+
+var (
+	executableNamePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+	packageNamePattern    = regexp.MustCompile(`^[a-z0-9]+$`)
+	bundleIDPattern       = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*(\.[A-Za-z][A-Za-z0-9_]*){2,}$`)
+	androidPackagePattern = regexp.MustCompile(`(<manifest\b[^>]*\bpackage=")([^"]*)(")`)
+)
+
+func init() {
+	renameCmd.Flags().StringVar(&renameApplicationName, "application-name", "", "The new application name, shown to the users.")
+	renameCmd.Flags().StringVar(&renamePackageName, "package-name", "", "The new package name. Only lowercase a-z and numbers.")
+	renameCmd.Flags().StringVar(&renameExecutableName, "executable-name", "", "The new executable name. Only lowercase a-z, numbers and underscores.")
+	renameCmd.Flags().StringVar(&renameBundleID, "bundle-id", "", "The new bundle identifier, <organization>.<package name>. The organization is changed in the package of the android manifest.")
+	renameCmd.Flags().BoolVar(&renameDryRun, "dry-run", false, "Print the changes instead of writing them.")
+	rootCmd.AddCommand(renameCmd)
+}
+
+var renameCmd = &cobra.Command{
+	Use:   "rename",
+	Short: "Change the names identifying the app in go/hover.yaml, go/cmd/options.go and the packaging formats",
+	Long: "Change the application name, package name, executable name and bundle identifier of the app consistently in go/hover.yaml, go/cmd/options.go, the android manifest and the configuration files of the initialized packaging formats.\n" +
+		"The values written as template data (e.g. {{.packageName}}) follow go/hover.yaml and are left as is. Use --dry-run to review the changes first.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		assertInFlutterProject()
+		assertHoverInitialized()
+
+		files, err := renameFiles()
+		if err != nil {
+			log.Errorf("%v", err)
+			os.Exit(1)
+		}
+		if len(files) == 0 {
+			log.Infof("Nothing to rename.")
+			return
+		}
+		var paths []string
+		for path := range files {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			if renameDryRun {
+				printDiff(path, files[path].old, files[path].new)
+				continue
+			}
+			err := ioutil.WriteFile(path, files[path].new, 0644)
+			if err != nil {
+				log.Errorf("Failed to write %s: %v", path, err)
+				os.Exit(1)
+			}
+			log.Infof("Updated %s", path)
+		}
+		if !renameDryRun {
+			log.Infof("Run `%s` to find the values renaming didn't change.", log.Au().Magenta("hover check-identity"))
+		}
+	},
+}
+
+// renamedFile is the content of a file before and after renaming.
+type renamedFile struct {
+	old, new []byte
+}
+
+// renameFiles returns the files changed by the rename flags, by path.
+func renameFiles() (map[string]renamedFile, error) {
+	projectName := pubspec.GetPubSpec().Name
+	organizationName := androidmanifest.AndroidOrganizationName()
+	current := map[string]string{
+		packaging.IdentityApplicationName: config.GetConfig().GetApplicationName(projectName),
+		packaging.IdentityPackageName:     config.GetConfig().GetPackageName(projectName),
+		packaging.IdentityExecutableName:  config.GetConfig().GetExecutableName(projectName),
+	}
+	current[packaging.IdentityBundleIdentifier] = organizationName + "." + current[packaging.IdentityPackageName]
+
+	renamed := make(map[string]string)
+	if renameApplicationName != "" {
+		renamed[packaging.IdentityApplicationName] = renameApplicationName
+	}
+	if renameExecutableName != "" {
+		if !executableNamePattern.MatchString(renameExecutableName) {
+			return nil, fmt.Errorf("invalid executable name %s: only lowercase a-z, numbers and underscores are allowed", renameExecutableName)
+		}
+		renamed[packaging.IdentityExecutableName] = renameExecutableName
+	}
+	if renamePackageName != "" {
+		if !packageNamePattern.MatchString(renamePackageName) {
+			return nil, fmt.Errorf("invalid package name %s: only lowercase a-z and numbers are allowed", renamePackageName)
+		}
+		renamed[packaging.IdentityPackageName] = renamePackageName
+	}
+	newOrganizationName := organizationName
+	if renameBundleID != "" {
+		if !bundleIDPattern.MatchString(renameBundleID) {
+			return nil, fmt.Errorf("invalid bundle identifier %s: expected <organization>.<package name>, with an organization of at least two parts (e.g. com.example)", renameBundleID)
+		}
+		i := strings.LastIndex(renameBundleID, ".")
+		newOrganizationName = renameBundleID[:i]
+		packageName := renameBundleID[i+1:]
+		if renamePackageName != "" && renamePackageName != packageName {
+			return nil, fmt.Errorf("the bundle identifier %s doesn't end with the package name %s", renameBundleID, renamePackageName)
+		}
+		if !packageNamePattern.MatchString(packageName) {
+			return nil, fmt.Errorf("invalid package name %s in the bundle identifier: only lowercase a-z and numbers are allowed", packageName)
+		}
+		renamed[packaging.IdentityPackageName] = packageName
+	}
+	if len(renamed) == 0 {
+		return nil, fmt.Errorf("nothing to rename, set at least one of --application-name, --package-name, --executable-name or --bundle-id")
+	}
+	if packageName, ok := renamed[packaging.IdentityPackageName]; ok || newOrganizationName != organizationName {
+		if !ok {
+			packageName = current[packaging.IdentityPackageName]
+		}
+		renamed[packaging.IdentityBundleIdentifier] = newOrganizationName + "." + packageName
+	}
+	for kind, value := range renamed {
+		if value == current[kind] {
+			delete(renamed, kind)
+		}
+	}
+
+	files := make(map[string]renamedFile)
+	edit := func(path string, change func(content []byte) []byte) error {
+		file, ok := files[path]
+		if !ok {
+			content, err := ioutil.ReadFile(path)
+			if os.IsNotExist(err) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %v", path, err)
+			}
+			file = renamedFile{old: content, new: content}
+		}
+		file.new = change(file.new)
+		files[path] = file
+		return nil
+	}
+
+	hoverConfigPath := filepath.Join(build.BuildPath, "hover.yaml")
+	for _, key := range [][2]string{
+		{packaging.IdentityApplicationName, "application-name"},
+		{packaging.IdentityExecutableName, "executable-name"},
+		{packaging.IdentityPackageName, "package-name"},
+	} {
+		value, ok := renamed[key[0]]
+		if !ok {
+			continue
+		}
+		// the hover section of pubspec.yaml takes precedence over hover.yaml
+		inPubspec := false
+		err := edit("pubspec.yaml", func(content []byte) []byte {
+			content, inPubspec = config.SetPubspecValue(content, key[1], value)
+			return content
+		})
+		if err != nil {
+			return nil, err
+		}
+		if inPubspec {
+			continue
+		}
+		err = edit(hoverConfigPath, func(content []byte) []byte {
+			return config.SetValue(content, key[1], value)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if newOrganizationName != organizationName {
+		err := edit(androidManifestPath, func(content []byte) []byte {
+			return androidPackagePattern.ReplaceAllFunc(content, func(match []byte) []byte {
+				parts := androidPackagePattern.FindSubmatch(match)
+				javaPackage := strings.Split(string(parts[2]), ".")
+				return []byte(string(parts[1]) + newOrganizationName + "." + javaPackage[len(javaPackage)-1] + string(parts[3]))
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the options may hardcode the names, e.g. in flutter.WindowTitle
+	err := edit(filepath.Join(build.BuildPath, "cmd", "options.go"), func(content []byte) []byte {
+		for _, kind := range []string{packaging.IdentityApplicationName, packaging.IdentityBundleIdentifier, packaging.IdentityPackageName} {
+			if value, ok := renamed[kind]; ok {
+				content = []byte(strings.ReplaceAll(string(content), strconv.Quote(current[kind]), strconv.Quote(value)))
+			}
+		}
+		return content
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working dir: %v", err)
+	}
+	version := pubspec.GetPubSpec().GetVersion()
+	for _, name := range packagingFormatNames() {
+		for path, content := range packagingTasks[name].RenameIdentity(version, renamed) {
+			if relPath, err := filepath.Rel(wd, path); err == nil {
+				path = relPath
+			}
+			original, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %v", path, err)
+			}
+			files[path] = renamedFile{old: original, new: content}
+		}
+	}
+
+	for path, file := range files {
+		if string(file.old) == string(file.new) {
+			delete(files, path)
+		}
+	}
+	return files, nil
+}
+
+// printDiff prints the lines changed in a file, in the unified diff format
+// without the line numbers.
+func printDiff(path string, old, new []byte) {
+	oldLines := strings.SplitAfter(string(old), "\n")
+	newLines := strings.SplitAfter(string(new), "\n")
+	// longest common subsequence of the lines, the files are small
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	fmt.Printf("--- %s\n+++ %s\n", path, path)
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			i++
+			j++
+		case j == len(newLines) || (i < len(oldLines) && lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Print("-" + strings.TrimSuffix(oldLines[i], "\n") + "\n")
+			i++
+		default:
+			fmt.Print("+" + strings.TrimSuffix(newLines[j], "\n") + "\n")
+			j++
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/report"
+)
+
+var (
+	reportFormat string
+	reportFile   string
+)
+
+// addReportFlags adds the flags choosing the output format of the results of
+// a checking command.
+func addReportFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&reportFormat, "format", report.FormatText, "The output format of the results: "+strings.Join(report.Formats, ", ")+". junit writes a JUnit XML report, github prints GitHub Actions annotations.")
+	cmd.Flags().StringVar(&reportFile, "report-file", "", "The path of the JUnit XML report (defaults to go/build/reports/<command>.xml).")
+}
+
+// assertReportFormat asserts the output format of the results is known.
+func assertReportFormat() {
+	err := report.CheckFormat(reportFormat)
+	if err != nil {
+		log.Errorf("%v", err)
+		os.Exit(1)
+	}
+}
+
+// writeReport writes the results of a checking command in the output format.
+// The text format has nothing more to write, the results are logged.
+func writeReport(suite report.Suite) {
+	switch reportFormat {
+	case report.FormatJUnit:
+		path := reportFile
+		if path == "" {
+			path = filepath.Join(build.BuildPath, "build", "reports", strings.Replace(suite.Name, " ", "-", -1)+".xml")
+		}
+		err := report.WriteJUnit(path, suite)
+		if err != nil {
+			log.Errorf("%v", err)
+			os.Exit(1)
+		}
+		log.Infof("Wrote the JUnit report to %s", path)
+	case report.FormatGitHub:
+		report.WriteGitHubAnnotations(os.Stdout, suite)
+	}
+}
@@ -4,33 +4,121 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/enginecache"
+	"github.com/go-flutter-desktop/hover/internal/i18n"
 	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/retry"
 	"github.com/spf13/cobra"
 )
 
 var colors bool
+var colorMode string
 var docker bool
+var flutterPath string
+var language string
+var retryAttempts int
 
 func init() {
 	rootCmd.PersistentFlags().BoolVar(&colors, "colors", true, "Add colors to log")
+	rootCmd.PersistentFlags().MarkDeprecated("colors", "use --color=never to disable colors")
+	rootCmd.PersistentFlags().StringVar(&colorMode, "color", log.ColorAuto, "When to add colors to log: auto, always or never. 'auto' respects NO_COLOR, TERM=dumb and disables colors when the output isn't a terminal")
 	rootCmd.PersistentFlags().BoolVar(&docker, "docker", false, "Run the command in a docker container for hover")
+	rootCmd.PersistentFlags().StringVar(&flutterPath, "flutter-path", "", "The path of the Flutter SDK to use instead of the flutter found in PATH")
+	rootCmd.PersistentFlags().IntVar(&retryAttempts, "retry-attempts", 0, "The attempts of the downloads, uploads and notarizations failing on transient network errors, defaults to retry.attempts of go/hover.yaml or 3. 1 disables the retries")
+	rootCmd.PersistentFlags().StringVar(&language, "lang", "", "The language of the messages of hover (e.g. fr), defaults to the locale of the system. The messages without translation are in English")
 }
 
 func initHover() {
-	if colors {
-		log.Colorize()
+	if !colors {
+		colorMode = log.ColorNever
 	}
+	err := log.SetColorMode(colorMode)
+	if err != nil {
+		log.Errorf("%v", err)
+		os.Exit(1)
+	}
+	if language != "" && !i18n.SetLocale(language) && language != "en" {
+		log.Warnf("There is no translation for the language '%s', the available translations are: %s", language, strings.Join(i18n.Locales(), ", "))
+	}
+	if flutterPath == "" {
+		flutterPath = config.GetConfig().FlutterPath
+	}
+	if flutterPath != "" {
+		build.SetFlutterSDK(flutterPath)
+	}
+	initRetryPolicy()
+	enginecache.SetReleaseEngineChecksums(config.GetConfig().ReleaseEngines)
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
 	go func() {
 		for range c {
 			fmt.Println("")
+			interruptHandlerMutex.Lock()
+			handler := interruptHandler
+			interruptHandlerMutex.Unlock()
+			if handler != nil {
+				handler()
+			}
 			os.Exit(1)
 		}
 	}()
 }
 
+var (
+	interruptHandler      func()
+	interruptHandlerMutex sync.Mutex
+)
+
+// setInterruptHandler sets a function run when hover is interrupted, before
+// it exits, nil for none.
+func setInterruptHandler(handler func()) {
+	interruptHandlerMutex.Lock()
+	defer interruptHandlerMutex.Unlock()
+	interruptHandler = handler
+}
+
+// initRetryPolicy sets the retry policy of the retry section of
+// go/hover.yaml and --retry-attempts.
+func initRetryPolicy() {
+	retryConfig := config.GetConfig().Retry
+	policy := retry.DefaultPolicy
+	if retryConfig.Attempts != 0 {
+		policy.Attempts = retryConfig.Attempts
+	}
+	if retryAttempts != 0 {
+		policy.Attempts = retryAttempts
+	}
+	if policy.Attempts < 1 {
+		log.Errorf("The retry attempts must be at least 1, got %d.", policy.Attempts)
+		os.Exit(1)
+	}
+	for _, delay := range []struct {
+		name  string
+		value string
+		field *time.Duration
+	}{
+		{"retry.delay", retryConfig.Delay, &policy.Delay},
+		{"retry.max-delay", retryConfig.MaxDelay, &policy.MaxDelay},
+	} {
+		if delay.value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(delay.value)
+		if err != nil || duration < 0 {
+			log.Errorf("Invalid %s %q in go/hover.yaml, e.g. 5s or 1m.", delay.name, delay.value)
+			os.Exit(1)
+		}
+		*delay.field = duration
+	}
+	retry.SetPolicy(policy)
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "hover",
 	Short: "Hover connects Flutter and go-flutter-desktop.",
@@ -40,6 +128,7 @@ var rootCmd = &cobra.Command{
 // Execute executes the rootCmd
 func Execute() {
 	cobra.OnInitialize(initHover)
+	addCustomPackagingFormats()
 	if err := rootCmd.Execute(); err != nil {
 		log.Errorf("Command failed: %v", err)
 		os.Exit(1)
@@ -9,12 +9,15 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/go-flutter-desktop/hover/cmd/packaging"
 	"github.com/go-flutter-desktop/hover/internal/build"
 	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
 	"github.com/go-flutter-desktop/hover/internal/log"
 	"github.com/go-flutter-desktop/hover/internal/pubspec"
 )
@@ -25,13 +28,24 @@ var (
 	runOmitEmbedder      bool
 	runOmitFlutterBundle bool
 	runDocker            bool
+	runProfileName       string
 )
 
+// runProfile is the run profile of hover.yaml selected with --profile.
+var runProfile config.RunProfileConfig
+
+// runProfileShimPath applies the window size of the run profiles, added to
+// the project on the first run of a profile with a window-size.
+var runProfileShimPath = filepath.Join(build.BuildPath, "cmd", "runprofile.go")
+
+var windowSizePattern = regexp.MustCompile(`^[0-9]+x[0-9]+$`)
+
 func init() {
 	runCmd.Flags().StringVarP(&buildTarget, "target", "t", config.BuildTargetDefault, "The main entry-point file of the application.")
 	runCmd.Flags().StringVarP(&buildGoFlutterBranch, "branch", "b", config.BuildBranchDefault, "The 'go-flutter' version to use. (@master or @v0.20.0 for example)")
 	runCmd.Flags().StringVar(&buildCachePath, "cache-path", "", "The path that hover uses to cache dependencies such as the Flutter engine .so/.dll (defaults to the standard user cache directory)")
 	runCmd.PersistentFlags().StringVar(&buildEngineVersion, "engine-version", "", "The flutter engine version to use.")
+	runCmd.Flags().StringVar(&buildLocalEngine, "local-engine", "", "The path of a locally built flutter engine to use instead of downloading it (e.g. engine/src/out/host_release).")
 	runCmd.Flags().StringVar(&buildOpenGlVersion, "opengl", config.BuildOpenGlVersionDefault, "The OpenGL version specified here is only relevant for external texture plugin (i.e. video_plugin).\nIf 'none' is provided, texture won't be supported. Note: the Flutter Engine still needs a OpenGL compatible context.")
 
 	runCmd.Flags().StringVar(&runInitialRoute, "route", "", "Which route to load when running the app.")
@@ -39,6 +53,7 @@ func init() {
 	runCmd.Flags().BoolVar(&runOmitFlutterBundle, "omit-flutter", false, "Don't (re)compile the current Flutter project, useful when only working with Golang code (plugin)")
 	runCmd.Flags().BoolVar(&runOmitEmbedder, "omit-embedder", false, "Don't (re)compile 'go-flutter' source code, useful when only working with Dart code")
 	runCmd.Flags().BoolVar(&runDocker, "docker", false, "Execute the go build in a docker container. The Flutter build is always run locally")
+	runCmd.Flags().StringVar(&runProfileName, "profile", "", "The run profile of go/hover.yaml to use (target, args, env, dart-defines and window-size of the app).")
 	rootCmd.AddCommand(runCmd)
 }
 
@@ -55,8 +70,14 @@ var runCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
+		if runProfileName != "" {
+			selectRunProfile(cmd.Flags().Changed("target"))
+		}
+
 		// Can only run on host OS
 		targetOS := runtime.GOOS
+		warnWindowsFilesystem()
+		displayEnv := runDisplayEnv()
 
 		// forcefully enable --debug as it is not optional for 'hover run'
 		buildDebug = true
@@ -71,7 +92,10 @@ var runCmd = &cobra.Command{
 		if runOmitEmbedder {
 			log.Infof("Omiting build the embedder")
 		} else {
-			vmArguments := []string{"--observatory-port=" + runObservatoryPort, "--enable-service-port-fallback", "--disable-service-auth-codes"}
+			assertBuildInfoShim()
+			assertSingleInstanceShim()
+			assertDBusActivationShim(targetOS)
+			vmArguments := runVMArguments()
 			if runDocker {
 				var buildFlags []string
 				buildFlags = append(buildFlags, commonFlags()...)
@@ -86,15 +110,71 @@ var runCmd = &cobra.Command{
 			}
 		}
 		log.Infof("Build finished, starting app...")
-		runAndAttach(projectName, targetOS)
+		runAndAttach(projectName, targetOS, displayEnv)
 	},
 }
 
-func runAndAttach(projectName string, targetOS string) {
-	cmdApp := exec.Command(dotSlash + filepath.Join(build.BuildPath, "build", "outputs", targetOS, projectName))
+// selectRunProfile applies the run profile selected with --profile. The
+// command-line flags take precedence over the profile.
+func selectRunProfile(targetFlagSet bool) {
+	profile, ok := config.GetConfig().Run[runProfileName]
+	if !ok {
+		var names []string
+		for name := range config.GetConfig().Run {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			log.Errorf("Unknown run profile %s, there are no run profiles in go/hover.yaml.", runProfileName)
+		} else {
+			log.Errorf("Unknown run profile %s, the run profiles of go/hover.yaml are: %s", runProfileName, strings.Join(names, ", "))
+		}
+		os.Exit(1)
+	}
+	if profile.Target != "" && !targetFlagSet {
+		buildTarget = profile.Target
+	}
+	var defines []string
+	for name, value := range profile.DartDefines {
+		defines = append(defines, name+"="+value)
+	}
+	sort.Strings(defines)
+	buildDartDefines = defines
+	if profile.WindowSize != "" {
+		if !windowSizePattern.MatchString(profile.WindowSize) {
+			log.Errorf("Invalid window-size %s in the run profile %s, expected <width>x<height> (e.g. 1280x800).", profile.WindowSize, runProfileName)
+			os.Exit(1)
+		}
+		if !fileutils.IsFileExists(runProfileShimPath) {
+			fileutils.CopyAsset("app/runprofile.go", runProfileShimPath, fileutils.AssetsBox())
+			log.Infof("Added %s, applying the window size of the run profiles. Add it to git too.", runProfileShimPath)
+			if runOmitEmbedder {
+				log.Warnf("The window-size of the run profile is applied once the app is built again, without --omit-embedder.")
+			}
+		}
+	}
+	runProfile = profile
+	log.Infof("Using the run profile %s", runProfileName)
+}
+
+// runVMArguments returns the arguments of the Dart VM of the apps started by
+// hover run, listening on the observatory port hover attaches to.
+func runVMArguments() []string {
+	return []string{"--observatory-port=" + runObservatoryPort, "--enable-service-port-fallback", "--disable-service-auth-codes"}
+}
+
+func runAndAttach(projectName string, targetOS string, displayEnv []string) {
+	cmdApp := exec.Command(build.OutputBinaryPath(projectName, targetOS), runProfile.Args...)
 	cmdApp.Env = append(os.Environ(),
 		"GOFLUTTER_ROUTE="+runInitialRoute)
-	cmdFlutterAttach := exec.Command("flutter", "attach")
+	cmdApp.Env = append(cmdApp.Env, displayEnv...)
+	for name, value := range runProfile.Env {
+		cmdApp.Env = append(cmdApp.Env, name+"="+os.ExpandEnv(value))
+	}
+	if runProfile.WindowSize != "" {
+		cmdApp.Env = append(cmdApp.Env, "HOVER_RUN_WINDOW_SIZE="+runProfile.WindowSize)
+	}
+	cmdFlutterAttach := exec.Command(build.FlutterBin(), "attach")
 
 	stdoutApp, err := cmdApp.StdoutPipe()
 	if err != nil {
@@ -158,6 +238,10 @@ func startHotReloadProcess(cmdFlutterAttach *exec.Cmd, buildTargetMainDart strin
 		"--device-id", "flutter-tester",
 		"--debug-uri", uri,
 	}
+	// hot restart compiles the dart code again
+	for _, define := range buildDartDefines {
+		cmdFlutterAttach.Args = append(cmdFlutterAttach.Args, "--dart-define="+define)
+	}
 	err := cmdFlutterAttach.Start()
 	if err != nil {
 		log.Warnf("The command 'flutter attach' failed: %v hot reload disabled", err)
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/cmd/packaging"
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/explain"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/signing"
+)
+
+var buildSkipSigning bool
+
+// newBuildSigner returns the signer configured in go/hover.yaml, or nil when
+// the build isn't signed. Only the windows builds are signed.
+func newBuildSigner(targetOS string) signing.Signer {
+	signingConfig := config.GetConfig().Signing
+	if buildSkipSigning || targetOS != "windows" || signingConfig.Provider == "" {
+		return nil
+	}
+	signer, err := signing.NewSigner(signingConfig)
+	if err != nil {
+		log.ErrorCodef(explain.SigningNotConfigured, "Failed to configure the signing: %v", err)
+		os.Exit(1)
+	}
+	return signer
+}
+
+// signBuildOutput signs the executable and the libraries of the build output
+// in place: the engine, the plugins and the other DLLs shipped with the app,
+// which windows checks as well as the executable. The darwin builds aren't
+// signed, the signer only does Authenticode.
+func signBuildOutput(signer signing.Signer, targetOS string) {
+	if signer == nil {
+		return
+	}
+	outputDirectoryPath := build.OutputDirectoryPath(targetOS)
+	var signed bool
+	err := filepath.Walk(outputDirectoryPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() || !signing.IsSignable(path) || !signing.IsPortableExecutable(path) {
+			return nil
+		}
+		log.Infof("Signing %s", path)
+		err = signer.Sign(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to sign %s", path)
+		}
+		signed = true
+		return nil
+	})
+	if err != nil {
+		log.ErrorCodef(explain.SigningFailed, "Failed to sign the build output: %v", err)
+		os.Exit(1)
+	}
+	// the signatures changed the libraries listed in the manifest
+	if signed && integrityCheck() {
+		err = packaging.WriteIntegrityManifest(outputDirectoryPath, targetOS)
+		if err != nil {
+			log.Errorf("Failed to write the integrity manifest: %v", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// signArtifacts signs the artifacts of a build target in place.
+func signArtifacts(signer signing.Signer, targetOS, target string) {
+	if signer == nil {
+		return
+	}
+	for _, artifact := range buildArtifacts(targetOS, target, nil) {
+		if !signing.IsSignable(artifact) {
+			continue
+		}
+		log.Infof("Signing %s", artifact)
+		err := signer.Sign(artifact)
+		if err != nil {
+			log.ErrorCodef(explain.SigningFailed, "Failed to sign %s: %v", artifact, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// targetName returns the name of a build target: the OS, or the packaging
+// format.
+func targetName(targetOS string, packagingTask packaging.Task) string {
+	if packagingTask.Name() == "" {
+		return targetOS
+	}
+	return targetOS + "-" + packagingTask.Name()
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// singleInstanceShimPaths forward the later launches of the app to its first
+// instance. They are added by `hover init --single-instance`, and on the
+// first build once single-instance is set in hover.yaml.
+var singleInstanceShimPaths = map[string]string{
+	"app/singleinstance.go":         filepath.Join(build.BuildPath, "cmd", "singleinstance.go"),
+	"app/singleinstance_windows.go": filepath.Join(build.BuildPath, "cmd", "singleinstance_windows.go"),
+}
+
+// addSingleInstanceShim adds the missing single instance shim files to the
+// project.
+func addSingleInstanceShim() {
+	for asset, path := range singleInstanceShimPaths {
+		if fileutils.IsFileExists(path) {
+			continue
+		}
+		fileutils.CopyAsset(asset, path, fileutils.AssetsBox())
+		log.Infof("Added %s, forwarding the later launches of the app to its first instance. Add it to git too.", path)
+	}
+}
+
+// assertSingleInstanceShim adds the single instance shim to the project when
+// single-instance is set.
+func assertSingleInstanceShim() {
+	if !config.GetConfig().SingleInstance {
+		return
+	}
+	addSingleInstanceShim()
+}
+
+// dbusActivationShimPath owns the DBus name of the app on linux, added on the
+// first build once dbus-activatable is set in hover.yaml.
+var dbusActivationShimPath = filepath.Join(build.BuildPath, "cmd", "singleinstance_dbus_linux.go")
+
+// dbusModule implements the DBus protocol for the DBus activation shim.
+const dbusModule = "github.com/godbus/dbus/v5@v5.1.0"
+
+// assertDBusActivationShim adds the DBus activation shim, and the module it
+// uses, to the project when building for linux with dbus-activatable set.
+func assertDBusActivationShim(targetOS string) {
+	if targetOS != "linux" || !config.GetConfig().DBusActivatable || fileutils.IsFileExists(dbusActivationShimPath) {
+		return
+	}
+	if !config.GetConfig().SingleInstance {
+		log.Errorf("dbus-activatable requires single-instance in go/hover.yaml, the DBus activations are forwarded to the first instance.")
+		os.Exit(1)
+	}
+	cmdGoGet := exec.Command(build.GoBin(), "get", dbusModule)
+	cmdGoGet.Dir = build.BuildPath
+	cmdGoGet.Env = append(os.Environ(), "GO111MODULE=on")
+	cmdGoGet.Stderr = os.Stderr
+	cmdGoGet.Stdout = os.Stdout
+	err := cmdGoGet.Run()
+	if err != nil {
+		log.Errorf("Failed to add %s to the go module: %v", dbusModule, err)
+		os.Exit(1)
+	}
+	fileutils.CopyAsset("app/singleinstance_dbus_linux.go", dbusActivationShimPath, fileutils.AssetsBox())
+	log.Infof("Added %s, activating the app through DBus on linux. Add it to git too.", dbusActivationShimPath)
+}
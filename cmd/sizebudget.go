@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/cmd/packaging"
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// sizeBreakdownEntries is the number of largest parts of the build output
+// listed when a size budget is exceeded.
+const sizeBreakdownEntries = 10
+
+var sizePattern = regexp.MustCompile(`^(\d+(?:\.\d+)?) *([KMG]?B)$`)
+
+// parseSize parses a size of the size budgets, e.g. 40MB.
+func parseSize(size string) (uint64, error) {
+	match := sizePattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(size)))
+	if match == nil {
+		return 0, errors.Errorf("invalid size %q, use a number followed by B, KB, MB or GB", size)
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid size %q", size)
+	}
+	unit := map[string]float64{"B": 1, "KB": 1 << 10, "MB": 1 << 20, "GB": 1 << 30}[match[2]]
+	return uint64(value * unit), nil
+}
+
+// formatBudgetSize formats a size with a decimal, the sizes just over their
+// budget would otherwise look equal to it.
+func formatBudgetSize(size uint64) string {
+	return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+}
+
+// checkSizeBudgets checks the sizes of the parts of the build output and of
+// the artifacts of the packaging format against size-budgets of
+// go/hover.yaml. When a budget is exceeded, the build fails, or only warns
+// with size-budgets.warn, and the largest parts of the build output are
+// listed.
+func checkSizeBudgets(targetOS string, packagingTask packaging.Task) {
+	budgets := config.GetConfig().SizeBudgets
+	if len(budgets.Artifacts) == 0 && len(budgets.Components) == 0 {
+		return
+	}
+	outputPath := build.OutputDirectoryPath(targetOS)
+	var exceeded []string
+
+	components := make([]string, 0, len(budgets.Components))
+	for component := range budgets.Components {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+	for _, component := range components {
+		budget := assertSizeBudget("components", component, budgets.Components[component])
+		path := filepath.Join(outputPath, filepath.FromSlash(component))
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			log.Warnf("The size budget of %s doesn't apply, %s isn't part of the %s build output.", component, component, targetOS)
+			continue
+		}
+		if size := dirSize(path); size > budget {
+			exceeded = append(exceeded, fmt.Sprintf("%s is %s, over its budget of %s", component, formatBudgetSize(size), formatBudgetSize(budget)))
+		}
+	}
+
+	if packagingTask.Name() != "" {
+		packagingFormat := targetName(targetOS, packagingTask)
+		if limit, ok := sizeBudgetOf(budgets.Artifacts, packagingFormat); ok {
+			budget := assertSizeBudget("artifacts", packagingFormat, limit)
+			artifactsPath := build.OutputDirectoryPath(packagingFormat)
+			files, err := ioutil.ReadDir(artifactsPath)
+			if err != nil {
+				log.Errorf("Failed to list the %s artifacts: %v", packagingFormat, err)
+				os.Exit(1)
+			}
+			for _, file := range files {
+				if size := dirSize(filepath.Join(artifactsPath, file.Name())); size > budget {
+					exceeded = append(exceeded, fmt.Sprintf("%s is %s, over the %s budget of %s", file.Name(), formatBudgetSize(size), packagingFormat, formatBudgetSize(budget)))
+				}
+			}
+		}
+	}
+
+	if len(exceeded) == 0 {
+		return
+	}
+	message := fmt.Sprintf("Size budgets exceeded:\n  %s\nThe largest parts of the %s build output:\n%s", strings.Join(exceeded, "\n  "), targetOS, sizeBreakdown(outputPath))
+	if budgets.Warn {
+		log.Warnf("%s", message)
+		return
+	}
+	log.Errorf("%s\nReduce the size of the app, or raise the budgets in size-budgets of go/hover.yaml.", message)
+	os.Exit(1)
+}
+
+// sizeBudgetOf returns the budget of a packaging format, set for the format
+// (linux-deb) or for all formats of a platform (linux).
+func sizeBudgetOf(budgets map[string]string, packagingFormat string) (string, bool) {
+	if budget, ok := budgets[packagingFormat]; ok {
+		return budget, true
+	}
+	budget, ok := budgets[strings.Split(packagingFormat, "-")[0]]
+	return budget, ok
+}
+
+func assertSizeBudget(section, name, budget string) uint64 {
+	size, err := parseSize(budget)
+	if err != nil {
+		log.Errorf("The size budget of %s in size-budgets.%s of go/hover.yaml is %v", name, section, err)
+		os.Exit(1)
+	}
+	return size
+}
+
+// sizeBreakdown lists the largest files and directories at the top of the
+// build output, and of its flutter_assets directory, with their share of the
+// build output.
+func sizeBreakdown(outputPath string) string {
+	type part struct {
+		name string
+		size uint64
+	}
+	var parts []part
+	for _, dir := range []string{"", "flutter_assets"} {
+		files, err := ioutil.ReadDir(filepath.Join(outputPath, dir))
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			name := filepath.ToSlash(filepath.Join(dir, file.Name()))
+			if dir == "" && name == "flutter_assets" {
+				continue
+			}
+			parts = append(parts, part{name: name, size: dirSize(filepath.Join(outputPath, name))})
+		}
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].size > parts[j].size })
+	if len(parts) > sizeBreakdownEntries {
+		parts = parts[:sizeBreakdownEntries]
+	}
+	total := dirSize(outputPath)
+	var breakdown strings.Builder
+	for _, part := range parts {
+		var share float64
+		if total > 0 {
+			share = float64(part.size) / float64(total) * 100
+		}
+		fmt.Fprintf(&breakdown, "  %8s %5.1f%%  %s\n", formatBudgetSize(part.size), share, part.name)
+	}
+	fmt.Fprintf(&breakdown, "  %8s %5.1f%%  total", formatBudgetSize(total), 100.0)
+	return breakdown.String()
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/go-flutter-desktop/hover/cmd/packaging"
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/explain"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+	"github.com/go-flutter-desktop/hover/internal/signing"
+)
+
+// The post-build steps of post-build in go/hover.yaml.
+const (
+	stepStrip         = "strip"          // Strips the executable of the build output
+	stepSign          = "sign"           // Signs the executables of the build output (windows)
+	stepPackage       = "package"        // Packages the build output
+	stepSignInstaller = "sign-installer" // Signs the packages (windows)
+	stepNotarize      = "notarize"       // Notarizes the packages (darwin)
+	stepStaple        = "staple"         // Staples the notarization tickets to the packages (darwin)
+)
+
+// postBuildStepOrder lists the steps in the only order they can run in: the
+// executable can't be stripped once signed, nor signed once packaged, and
+// the ticket of the notarization is stapled to the signed package.
+var postBuildStepOrder = []string{stepStrip, stepSign, stepPackage, stepSignInstaller, stepNotarize, stepStaple}
+
+// postBuildSteps returns the post-build steps of a build target, in order:
+// those of post-build in go/hover.yaml, by default the windows builds are
+// signed, then packaged, then their packages are signed.
+func postBuildSteps(targetOS string, packagingTask packaging.Task) []string {
+	target := targetName(targetOS, packagingTask)
+	steps, ok := config.GetConfig().GetPostBuildSteps(target)
+	if !ok {
+		if targetOS == "windows" {
+			steps = append(steps, stepSign)
+		}
+		if packagingTask.Name() != "" {
+			steps = append(steps, stepPackage)
+			if targetOS == "windows" {
+				steps = append(steps, stepSignInstaller)
+			}
+		}
+		return steps
+	}
+
+	position := make(map[string]int, len(postBuildStepOrder))
+	for i, step := range postBuildStepOrder {
+		position[step] = i
+	}
+	listed := map[string]bool{}
+	for i, step := range steps {
+		if _, ok := position[step]; !ok {
+			log.ErrorCodef(explain.PostBuildStepsInvalid, "Unknown post-build step %s of %s in go/hover.yaml, use %s.", step, target, strings.Join(postBuildStepOrder, ", "))
+			os.Exit(1)
+		}
+		if listed[step] {
+			log.ErrorCodef(explain.PostBuildStepsInvalid, "The post-build step %s is listed twice for %s in go/hover.yaml.", step, target)
+			os.Exit(1)
+		}
+		listed[step] = true
+		if i > 0 && position[steps[i-1]] > position[step] {
+			log.ErrorCodef(explain.PostBuildStepsInvalid, "The post-build step %s of %s in go/hover.yaml can't run after %s, the steps run in the order %s.", step, target, steps[i-1], strings.Join(postBuildStepOrder, ", "))
+			os.Exit(1)
+		}
+	}
+	switch {
+	case packagingTask.Name() != "" && !listed[stepPackage]:
+		log.ErrorCodef(explain.PostBuildStepsInvalid, "The post-build steps of %s in go/hover.yaml don't package the app, add %s.", target, stepPackage)
+		os.Exit(1)
+	case targetOS != "windows" && (listed[stepSign] || listed[stepSignInstaller]):
+		log.ErrorCodef(explain.PostBuildStepsInvalid, "The post-build steps of %s in go/hover.yaml sign the windows builds only, the darwin packages are notarized.", target)
+		os.Exit(1)
+	case targetOS != "darwin" && (listed[stepNotarize] || listed[stepStaple]):
+		log.ErrorCodef(explain.PostBuildStepsInvalid, "The post-build steps of %s in go/hover.yaml notarize the darwin packages only.", target)
+		os.Exit(1)
+	case targetOS == "windows" && listed[stepStrip]:
+		log.ErrorCodef(explain.PostBuildStepsInvalid, "The post-build steps of %s in go/hover.yaml can't strip the windows executable.", target)
+		os.Exit(1)
+	case listed[stepStaple] && !listed[stepNotarize]:
+		log.ErrorCodef(explain.PostBuildStepsInvalid, "The post-build steps of %s in go/hover.yaml staple the notarization ticket without notarizing, add %s before %s.", target, stepNotarize, stepStaple)
+		os.Exit(1)
+	case listed[stepStrip] && config.GetConfig().SplitPackages.DebugSymbols && (packagingTask.Name() == "deb" || packagingTask.Name() == "rpm"):
+		log.ErrorCodef(explain.PostBuildStepsInvalid, "The post-build steps of %s in go/hover.yaml strip the debug symbols split-packages.debug-symbols moves to a package, remove %s.", target, stepStrip)
+		os.Exit(1)
+	}
+	if packagingTask.Name() == "" {
+		// the builds without packaging format run the steps of their platform
+		// up to the packaging
+		var buildSteps []string
+		for _, step := range steps {
+			if step == stepStrip || step == stepSign {
+				buildSteps = append(buildSteps, step)
+			}
+		}
+		return buildSteps
+	}
+	return steps
+}
+
+// newBuildNotarizer returns the notarizer of the darwin packages, or nil
+// when the post-build steps don't notarize them. The credentials are checked
+// before anything is built.
+func newBuildNotarizer(steps []string) *signing.Notarizer {
+	if buildSkipSigning || !hasStep(steps, stepNotarize) {
+		return nil
+	}
+	notarizer, err := signing.NewNotarizer()
+	if err != nil {
+		log.ErrorCodef(explain.SigningNotConfigured, "Failed to configure the notarization: %v", err)
+		os.Exit(1)
+	}
+	return notarizer
+}
+
+func hasStep(steps []string, step string) bool {
+	for _, s := range steps {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
+
+// runPostBuildSteps runs the post-build steps of a build target, in order.
+// The steps before the packaging are skipped when packaged is true, the
+// package being built in a docker container.
+func runPostBuildSteps(steps []string, signer signing.Signer, notarizer *signing.Notarizer, targetOS string, packagingTask packaging.Task, packaged bool) {
+	target := targetName(targetOS, packagingTask)
+	for _, step := range steps {
+		switch step {
+		case stepStrip:
+			if !packaged {
+				stripExecutable(targetOS)
+			}
+		case stepSign:
+			if packaged && signer != nil && packagingTask.Name() != "" {
+				log.Warnf("The executable packaged in the docker container isn't signed, only the packages are.")
+			}
+			signBuildOutput(signer, targetOS)
+		case stepPackage:
+			if !packaged {
+				packagingTask.Pack(buildVersionNumber)
+			}
+		case stepSignInstaller:
+			signArtifacts(signer, targetOS, target)
+		case stepNotarize:
+			notarizeArtifacts(notarizer, target)
+		case stepStaple:
+			stapleArtifacts(notarizer, target)
+		}
+	}
+}
+
+// stripExecutable strips the symbols of the executable of the build output,
+// and updates the integrity manifest listing it.
+func stripExecutable(targetOS string) {
+	executable := build.OutputBinaryPath(config.GetConfig().GetExecutableName(pubspec.GetPubSpec().Name), targetOS)
+	// the local symbols of the darwin executables are removed, the global
+	// ones are needed by the dynamic linker
+	flag := "-s"
+	if targetOS == "darwin" {
+		flag = "-x"
+	}
+	log.Infof("Stripping %s", executable)
+	output, err := exec.Command(build.StripBin(), flag, executable).CombinedOutput()
+	if err != nil {
+		log.ErrorCodef(explain.StripFailed, "Failed to strip %s: %v: %s", executable, err, strings.TrimSpace(string(output)))
+		os.Exit(1)
+	}
+	if integrityCheck() {
+		err = packaging.WriteIntegrityManifest(build.OutputDirectoryPath(targetOS), targetOS)
+		if err != nil {
+			log.Errorf("Failed to write the integrity manifest: %v", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// notarizeArtifacts notarizes the packages of a packaging format.
+func notarizeArtifacts(notarizer *signing.Notarizer, target string) {
+	if notarizer == nil {
+		return
+	}
+	var notarized bool
+	for _, artifact := range buildArtifacts("darwin", target, nil) {
+		if !signing.IsNotarizable(artifact) {
+			continue
+		}
+		log.Infof("Notarizing %s", artifact)
+		err := notarizer.Notarize(artifact)
+		if err != nil {
+			log.ErrorCodef(explain.NotarizationFailed, "Failed to notarize %s: %v", artifact, err)
+			os.Exit(1)
+		}
+		notarized = true
+	}
+	if !notarized {
+		log.ErrorCodef(explain.NotarizationFailed, "%s has no dmg, pkg or zip package to notarize.", target)
+		os.Exit(1)
+	}
+}
+
+// stapleArtifacts staples the notarization tickets to the packages of a
+// packaging format.
+func stapleArtifacts(notarizer *signing.Notarizer, target string) {
+	if notarizer == nil {
+		return
+	}
+	for _, artifact := range buildArtifacts("darwin", target, nil) {
+		if !signing.IsStapleable(artifact) {
+			continue
+		}
+		log.Infof("Stapling %s", artifact)
+		err := signing.Staple(artifact)
+		if err != nil {
+			log.ErrorCodef(explain.StaplingFailed, "Failed to staple %s: %v", artifact, err)
+			os.Exit(1)
+		}
+	}
+}
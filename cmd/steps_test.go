@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-flutter-desktop/hover/cmd/packaging"
+)
+
+func TestPostBuildSteps(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hover-steps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	err = os.MkdirAll(filepath.Join(dir, "go"), 0775)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, "go", "hover.yaml"), []byte(`schema-version: 2
+post-build:
+  windows-msi: [sign, package, sign-installer]
+  linux: [strip, package]
+  darwin-dmg: [package, notarize, staple]
+`), 0664)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	err = os.Chdir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		targetOS string
+		task     packaging.Task
+		want     []string
+	}{
+		// defaults
+		{"windows", packaging.NoopTask, []string{stepSign}},
+		{"windows", packaging.WindowsInnoTask, []string{stepSign, stepPackage, stepSignInstaller}},
+		{"darwin", packaging.NoopTask, nil},
+		{"darwin", packaging.DarwinPkgTask, []string{stepPackage}},
+		// go/hover.yaml
+		{"windows", packaging.WindowsMsiTask, []string{stepSign, stepPackage, stepSignInstaller}},
+		{"linux", packaging.NoopTask, []string{stepStrip}},
+		{"linux", packaging.LinuxDebTask, []string{stepStrip, stepPackage}},
+		{"darwin", packaging.DarwinDmgTask, []string{stepPackage, stepNotarize, stepStaple}},
+	}
+	for _, test := range tests {
+		target := targetName(test.targetOS, test.task)
+		if got := postBuildSteps(test.targetOS, test.task); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("postBuildSteps(%s) = %q, want %q", target, got, test.want)
+		}
+	}
+}
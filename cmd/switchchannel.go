@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/enginecache"
+	"github.com/go-flutter-desktop/hover/internal/flutterversion"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+var switchChannelSkipUpgrade bool
+
+func init() {
+	switchChannelCmd.Flags().StringVar(&buildCachePath, "cache-path", "", "The path that hover uses to cache dependencies such as the Flutter engine .so/.dll (defaults to the standard user cache directory)")
+	switchChannelCmd.Flags().StringVarP(&buildGoFlutterBranch, "branch", "b", "", "The 'go-flutter' version to upgrade to. (defaults to @latest)")
+	switchChannelCmd.Flags().BoolVar(&switchChannelSkipUpgrade, "skip-flutter-upgrade", false, "Don't run `flutter upgrade` after switching channel.")
+	rootCmd.AddCommand(switchChannelCmd)
+}
+
+var switchChannelCmd = &cobra.Command{
+	Use:   "switch-channel <channel>",
+	Short: "Switch the Flutter channel and update the engine and 'go-flutter' to match",
+	Long: "Switch the Flutter channel, download the engine of the new Flutter version, upgrade 'go-flutter' and pin the channel and engine version in go/hover.yaml.\n" +
+		"When a step fails, the previous channel, engine, go.mod and go/hover.yaml are restored.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		assertInFlutterProject()
+		assertHoverInitialized()
+		// the engine is downloaded for the current OS, like in bumpversion
+		targetOS := runtime.GOOS
+		if buildCachePath == "" && config.GetConfig().CachePath != "" {
+			buildCachePath = config.GetConfig().CachePath
+		}
+		if buildCachePath == "" {
+			buildCachePath = enginecache.DefaultCachePath()
+		}
+
+		err := switchChannel(targetOS, args[0])
+		if err != nil {
+			log.Errorf("Switching to the %s channel failed: %v", args[0], err)
+			os.Exit(1)
+		}
+		log.Infof("Switched to the %s channel of Flutter", args[0])
+	},
+}
+
+// switchChannel switches the flutter channel and updates the project. The
+// previous state is restored when a step fails.
+func switchChannel(targetOS, channel string) (err error) {
+	previousChannel := flutterversion.FlutterChannel()
+
+	hoverConfigPath := filepath.Join(build.BuildPath, "hover.yaml")
+	backups := make(map[string][]byte)
+	for _, path := range []string{hoverConfigPath, filepath.Join(build.BuildPath, "go.mod"), filepath.Join(build.BuildPath, "go.sum")} {
+		content, err := ioutil.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to back up %s", path)
+		}
+		backups[path] = content
+	}
+	engineCachePath := enginecache.EngineCachePath(targetOS, buildCachePath)
+	engineBackupPath := engineCachePath + ".switch-channel-backup"
+	engineMoved := false
+
+	defer func() {
+		if err == nil {
+			os.RemoveAll(engineBackupPath)
+			return
+		}
+		log.Warnf("Restoring the %s channel, the engine, go.mod and go/hover.yaml", previousChannel)
+		if restoreErr := runFlutter("channel", previousChannel); restoreErr != nil {
+			log.Warnf("Failed to switch back to the %s channel: %v", previousChannel, restoreErr)
+		}
+		for path, content := range backups {
+			if content == nil {
+				os.Remove(path)
+				continue
+			}
+			if restoreErr := ioutil.WriteFile(path, content, 0644); restoreErr != nil {
+				log.Warnf("Failed to restore %s: %v", path, restoreErr)
+			}
+		}
+		if engineMoved {
+			os.RemoveAll(engineCachePath)
+			if restoreErr := os.Rename(engineBackupPath, engineCachePath); restoreErr != nil {
+				log.Warnf("Failed to restore the engine from %s: %v", engineBackupPath, restoreErr)
+			}
+		}
+	}()
+
+	log.Infof("Switching from the %s to the %s channel", previousChannel, channel)
+	err = runFlutter("channel", channel)
+	if err != nil {
+		return err
+	}
+	if !switchChannelSkipUpgrade {
+		err = runFlutter("upgrade")
+		if err != nil {
+			return err
+		}
+	}
+	flutterversion.ReloadFlutterVersion()
+	if flutterversion.FlutterChannel() != channel {
+		return errors.Errorf("flutter is on the %s channel after switching", flutterversion.FlutterChannel())
+	}
+	engineVersion := flutterversion.FlutterRequiredEngineVersion()
+
+	if enginecache.CachedEngineVersion(engineCachePath) != engineVersion {
+		// the engine of the previous channel is kept until the end to be
+		// restored on failure.
+		_, err = os.Stat(engineCachePath)
+		if err == nil {
+			os.RemoveAll(engineBackupPath)
+			err = os.Rename(engineCachePath, engineBackupPath)
+			if err != nil {
+				return errors.Wrap(err, "failed to back up the engine")
+			}
+			engineMoved = true
+		}
+		_, err = enginecache.UpdateEngine(targetOS, buildCachePath, engineVersion)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = upgradeGoFlutter(targetOS, engineCachePath)
+	if err != nil {
+		return errors.Wrap(err, "failed to upgrade 'go-flutter'")
+	}
+
+	hoverConfig := config.SetValue(backups[hoverConfigPath], "flutter-channel", channel)
+	if config.GetConfig().Engine != "" {
+		// the engine version is only pinned when it was pinned before
+		hoverConfig = config.SetValue(hoverConfig, "engine-version", engineVersion)
+	}
+	err = ioutil.WriteFile(hoverConfigPath, hoverConfig, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write %s", hoverConfigPath)
+	}
+	return nil
+}
+
+func runFlutter(args ...string) error {
+	cmdFlutter := exec.Command(build.FlutterBin(), args...)
+	cmdFlutter.Stdout = os.Stdout
+	cmdFlutter.Stderr = os.Stderr
+	err := cmdFlutter.Run()
+	if err != nil {
+		return errors.Wrapf(err, "flutter %s failed", args[0])
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/timings"
+)
+
+// trendRuns is the number of previous builds the timings are compared to.
+const trendRuns = 5
+
+// timingsReport is the JSON output of --timings json. The durations are in
+// seconds, the averages are those of the previous builds of the target.
+type timingsReport struct {
+	Target          string         `json:"target"`
+	Duration        float64        `json:"duration"`
+	AverageDuration float64        `json:"averageDuration,omitempty"`
+	PreviousBuilds  int            `json:"previousBuilds"`
+	Phases          []timingsPhase `json:"phases"`
+}
+
+type timingsPhase struct {
+	Name            string  `json:"name"`
+	Duration        float64 `json:"duration"`
+	Share           float64 `json:"share"` // Of the whole build, between 0 and 1
+	AverageDuration float64 `json:"averageDuration,omitempty"`
+}
+
+// reportTimings records the timings of the phases of the build of a target
+// in go/build/timings.json, and prints them compared to the previous builds.
+func reportTimings(target string, startedOn time.Time) {
+	if buildTimings == "none" {
+		return
+	}
+	run := timings.Run{
+		Target:    target,
+		StartedOn: startedOn,
+		Duration:  time.Since(startedOn),
+		Phases:    timings.Phases(),
+	}
+	var measured time.Duration
+	for _, phase := range run.Phases {
+		measured += phase.Duration
+	}
+	if other := run.Duration - measured; other > 0 {
+		run.Phases = append(run.Phases, timings.Phase{Name: "other", Duration: other})
+	}
+
+	previous, err := timings.Record(filepath.Join(build.BuildPath, "build", "timings.json"), run)
+	if err != nil {
+		log.Warnf("Failed to record the build timings: %v", err)
+	}
+	if len(previous) > trendRuns {
+		previous = previous[len(previous)-trendRuns:]
+	}
+
+	report := timingsReport{
+		Target:         target,
+		Duration:       run.Duration.Seconds(),
+		PreviousBuilds: len(previous),
+	}
+	averageDuration, _ := timings.Average(previous, "")
+	report.AverageDuration = averageDuration.Seconds()
+	for _, phase := range run.Phases {
+		average, _ := timings.Average(previous, phase.Name)
+		report.Phases = append(report.Phases, timingsPhase{
+			Name:            phase.Name,
+			Duration:        phase.Duration.Seconds(),
+			Share:           phase.Duration.Seconds() / run.Duration.Seconds(),
+			AverageDuration: average.Seconds(),
+		})
+	}
+
+	if buildTimings == "json" {
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			log.Errorf("Failed to encode the build timings: %v", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(out))
+		return
+	}
+
+	if len(previous) == 0 {
+		log.Infof("Build timings of %s:", target)
+	} else {
+		log.Infof("Build timings of %s, compared to the average of the last %d builds:", target, len(previous))
+	}
+	for _, phase := range report.Phases {
+		log.Printf("  %-18s %9s %4.0f%%  %s", phase.Name, formatSeconds(phase.Duration), phase.Share*100, trend(phase.Duration, phase.AverageDuration))
+	}
+	log.Printf("  %-18s %9s %5s  %s", "total", formatSeconds(report.Duration), "", trend(report.Duration, report.AverageDuration))
+}
+
+// formatSeconds formats a duration in seconds, rounded to a tenth of second.
+func formatSeconds(seconds float64) string {
+	return time.Duration(seconds * float64(time.Second)).Round(100 * time.Millisecond).String()
+}
+
+// trend returns the change of a duration relative to its average, empty
+// without average.
+func trend(seconds, average float64) string {
+	if average == 0 {
+		return ""
+	}
+	change := (seconds - average) / average * 100
+	switch {
+	case change >= 0.5:
+		return log.Au().Red(fmt.Sprintf("+%.0f%% (%s)", change, formatSeconds(average))).String()
+	case change <= -0.5:
+		return log.Au().Green(fmt.Sprintf("%.0f%% (%s)", change, formatSeconds(average))).String()
+	}
+	return fmt.Sprintf("=  (%s)", formatSeconds(average))
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/go-flutter-desktop/hover/cmd/packaging"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+	"github.com/go-flutter-desktop/hover/internal/report"
+)
+
+var (
+	verifyArtifactTimeout       time.Duration
+	verifyArtifactVersionNumber string
+)
+
+func init() {
+	verifyArtifactCmd.Flags().DurationVar(&verifyArtifactTimeout, "timeout", 15*time.Second, "How long the app must run without crashing for the smoke test to pass.")
+	verifyArtifactCmd.Flags().StringVar(&verifyArtifactVersionNumber, "version-number", "", "Override the version number of the artifacts to verify.")
+	addReportFlags(verifyArtifactCmd)
+	rootCmd.AddCommand(verifyArtifactCmd)
+}
+
+var verifyArtifactCmd = &cobra.Command{
+	Use:   "verify-artifact [format...]",
+	Short: "Install the packaged application in disposable containers and start it",
+	Long: "Install the packaging outputs in go/build/outputs in a disposable docker container per format, and start the app in a virtual display: the smoke test passes when the app exits successfully, or is still running after the timeout. By default all the built formats are verified.\n" +
+		"The linux-deb, linux-rpm, linux-pacman, linux-tar, linux-appimage, linux-run and linux-snap packages are installed in docker, with the distribution of the package manager. The windows-msi package is installed and uninstalled with wine on the host, without starting the app.",
+	ValidArgsFunction: completePackagingFormats,
+	Args: func(cmd *cobra.Command, args []string) error {
+		for _, arg := range args {
+			if _, ok := packagingTasks[arg]; !ok {
+				return errors.Errorf("unknown packaging format '%s'", arg)
+			}
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		assertReportFormat()
+		assertHoverInitialized()
+
+		version := verifyArtifactVersionNumber
+		if version == "" {
+			version = pubspec.GetPubSpec().GetVersion()
+		}
+		names := args
+		if len(names) == 0 {
+			names = packagingFormatNames()
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "FORMAT\tRESULT\t")
+		suite := report.Suite{Name: "verify-artifact"}
+		var verified, failed int
+		for _, name := range names {
+			if len(args) == 0 && !packagingTasks[name].IsInitialized() {
+				continue
+			}
+			log.Printf("Verifying %s", name)
+			startedOn := time.Now()
+			err := packagingTasks[name].Verify(version, verifyArtifactTimeout)
+			verifyCase := report.Case{Name: name, Time: time.Since(startedOn)}
+			switch {
+			case err == packaging.ErrNoArtifact || err == packaging.ErrNoSmokeTest:
+				fmt.Fprintf(w, "%s\tskipped, %v\t\n", name, err)
+				verifyCase.Skipped = err.Error()
+			case err != nil:
+				failed++
+				lines := strings.SplitN(err.Error(), "\n", 2)
+				fmt.Fprintf(w, "%s\tfailed, %s\t\n", name, lines[0])
+				if len(lines) == 2 {
+					log.Warnf("The smoke test of %s failed:\n%s", name, strings.TrimSpace(lines[1]))
+				}
+				verifyCase.Problems = []report.Problem{{Message: "The smoke test failed: " + strings.TrimSpace(err.Error())}}
+			default:
+				verified++
+				fmt.Fprintf(w, "%s\tpassed\t\n", name)
+			}
+			suite.Cases = append(suite.Cases, verifyCase)
+		}
+		w.Flush()
+		writeReport(suite)
+		if failed > 0 {
+			log.Errorf("%d of the %d verified packages failed the smoke test.", failed, failed+verified)
+			os.Exit(1)
+		}
+		if verified == 0 {
+			log.Warnf("No package was verified, run `%s` first.", log.Au().Magenta("hover build <format>"))
+		}
+	},
+}
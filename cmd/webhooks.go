@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-flutter-desktop/hover/cmd/packaging"
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/pubspec"
+	"github.com/go-flutter-desktop/hover/internal/webhook"
+)
+
+// webhooksNotifiedEnv is set for the hover processes building for a hover
+// process notifying the webhooks.
+const webhooksNotifiedEnv = "HOVER_WEBHOOKS_NOTIFIED"
+
+// buildWithWebhooks notifies the webhooks configured in go/hover.yaml of the
+// build. The build runs in a child hover process, as a failing build exits
+// the process. It returns false when there are no webhooks to notify.
+func buildWithWebhooks(targetOS string, packagingTask packaging.Task) bool {
+	webhooks := config.GetConfig().Webhooks
+	if len(webhooks) == 0 || os.Getenv(webhooksNotifiedEnv) == "true" {
+		return false
+	}
+	target := targetName(targetOS, packagingTask)
+	version := buildVersionNumber
+	if version == "" {
+		version = pubspec.GetPubSpec().GetVersion()
+	}
+	event := webhook.Event{
+		Event:   webhook.EventStart,
+		Project: pubspec.GetPubSpec().Name,
+		Version: version,
+		Target:  target,
+	}
+	sendWebhooks(webhooks, event)
+
+	// the failure is sent once, by the build or by the interrupt handler,
+	// whichever sees it first
+	start := time.Now()
+	var failure sync.Once
+	fail := func() {
+		failure.Do(func() {
+			event.Event = webhook.EventFailure
+			event.Duration = time.Since(start).Seconds()
+			sendWebhooks(webhooks, event)
+		})
+	}
+	setInterruptHandler(fail)
+	defer setInterruptHandler(nil)
+
+	outputsBefore := outputFiles(target)
+	executable, err := os.Executable()
+	if err != nil {
+		fail()
+		log.Errorf("Failed to find the hover executable: %v", err)
+		os.Exit(1)
+	}
+	cmdBuild := exec.Command(executable, os.Args[1:]...)
+	cmdBuild.Env = append(os.Environ(), webhooksNotifiedEnv+"=true")
+	cmdBuild.Stdin = os.Stdin
+	cmdBuild.Stdout = os.Stdout
+	cmdBuild.Stderr = os.Stderr
+	err = cmdBuild.Run()
+	if err != nil {
+		fail()
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			os.Exit(exitErr.ExitCode())
+		}
+		log.Errorf("Build failed: %v", err)
+		os.Exit(1)
+	}
+	event.Event = webhook.EventSuccess
+	event.Duration = time.Since(start).Seconds()
+	event.Artifacts = buildArtifacts(targetOS, target, outputsBefore)
+	sendWebhooks(webhooks, event)
+	return true
+}
+
+// outputFiles returns the modification times of the files in the output
+// directory of a target, by name.
+func outputFiles(target string) map[string]time.Time {
+	files, _ := ioutil.ReadDir(build.OutputDirectoryPath(target))
+	modTimes := make(map[string]time.Time, len(files))
+	for _, file := range files {
+		modTimes[file.Name()] = file.ModTime()
+	}
+	return modTimes
+}
+
+// buildArtifacts returns the paths of the artifacts of a build: the
+// executable for an OS, the packages for a packaging format. The files of
+// the output directory that are unchanged since outputsBefore, left over from
+// previous builds, aren't artifacts of the build. A nil outputsBefore takes
+// every file, as right after the packaging cleaned the output directory.
+func buildArtifacts(targetOS, target string, outputsBefore map[string]time.Time) []string {
+	if target == targetOS {
+		executableName := config.GetConfig().GetExecutableName(pubspec.GetPubSpec().Name)
+		return []string{build.OutputBinaryPath(executableName, targetOS)}
+	}
+	files, err := ioutil.ReadDir(build.OutputDirectoryPath(target))
+	if err != nil {
+		log.Warnf("Failed to list the artifacts of %s: %v", target, err)
+		return nil
+	}
+	var artifacts []string
+	for _, file := range files {
+		if modTime, ok := outputsBefore[file.Name()]; ok && modTime.Equal(file.ModTime()) {
+			continue
+		}
+		artifacts = append(artifacts, filepath.Join(build.OutputDirectoryPath(target), file.Name()))
+	}
+	return artifacts
+}
+
+// sendWebhooks sends an event to the webhooks. The build doesn't fail when a
+// webhook can't be notified.
+func sendWebhooks(webhooks []config.WebhookConfig, event webhook.Event) {
+	artifacts := event.Artifacts
+	for _, hook := range webhooks {
+		if !hook.WantsEvent(event.Event) {
+			continue
+		}
+		if hook.ArtifactBaseURL != "" {
+			event.Artifacts = nil
+			for _, artifact := range artifacts {
+				event.Artifacts = append(event.Artifacts, strings.TrimSuffix(hook.ArtifactBaseURL, "/")+"/"+filepath.Base(artifact))
+			}
+		} else {
+			event.Artifacts = artifacts
+		}
+		err := webhook.Send(hook.URL, hook.Kind, event)
+		if err != nil {
+			log.Warnf("Failed to notify a webhook of the build %s: %v", event.Event, err)
+		}
+	}
+}
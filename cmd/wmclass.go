@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// wmClassShimPath sets the window class of the linux builds, matching the
+// StartupWMClass of the .desktop files. It is added on init, and on the first
+// linux build of the projects created before.
+var wmClassShimPath = filepath.Join(build.BuildPath, "cmd", "wmclass.go")
+
+// assertWMClassShim adds the window class shim to the project when building
+// for linux.
+func assertWMClassShim(targetOS string) {
+	if targetOS != "linux" || fileutils.IsFileExists(wmClassShimPath) {
+		return
+	}
+	fileutils.CopyAsset("app/wmclass.go", wmClassShimPath, fileutils.AssetsBox())
+	log.Infof("Added %s, setting the window class the .desktop files refer to. Add it to git too.", wmClassShimPath)
+}
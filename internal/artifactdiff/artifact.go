@@ -0,0 +1,508 @@
+// Package artifactdiff reads the files and the metadata of the packaged
+// artifacts, and compares two artifacts to show what changed between them.
+package artifactdiff
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/bzip2"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+)
+
+// Entry is a file of an artifact.
+type Entry struct {
+	Size   int64
+	Mode   os.FileMode // Permission bits
+	SHA256 string      // Hash of the content, empty for the symbolic links
+	Link   string      // Target of the symbolic links
+}
+
+// Artifact is the content of a packaged artifact.
+type Artifact struct {
+	Path     string
+	Size     int64
+	SHA256   string
+	Format   string           // Format the files were read as, empty when the artifact is compared as a whole
+	Entries  map[string]Entry // Files of the artifact by path, without the directories
+	Metadata map[string]string
+}
+
+// metadataFiles are the files of the artifacts read as metadata, the
+// .PKGINFO of the pacman and apk packages and the snap.yaml of the snaps.
+var metadataFiles = map[string]func(content []byte) map[string]string{
+	".PKGINFO":       pkgInfoMetadata,
+	"meta/snap.yaml": snapMetadata,
+}
+
+// Read reads the files of an artifact, or of a directory such as a build
+// output. The formats whose files can't be read, e.g. msi and dmg, are
+// compared as a whole, by their size and hash.
+func Read(artifactPath string) (*Artifact, error) {
+	info, err := os.Stat(artifactPath)
+	if err != nil {
+		return nil, err
+	}
+	artifact := &Artifact{
+		Path:     artifactPath,
+		Entries:  map[string]Entry{},
+		Metadata: map[string]string{},
+	}
+	if info.IsDir() {
+		artifact.Format = "directory"
+		return artifact, artifact.readDir(artifactPath)
+	}
+	artifact.Size = info.Size()
+	artifact.SHA256, err = fileSHA256(artifactPath)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.ToLower(filepath.Base(artifactPath))
+	switch {
+	case strings.HasSuffix(name, ".deb"):
+		artifact.Format = "deb"
+		err = artifact.readDeb()
+	case strings.HasSuffix(name, ".rpm"):
+		artifact.Format = "rpm"
+		err = artifact.readRpm()
+	case strings.HasSuffix(name, ".snap"):
+		artifact.Format = "snap"
+		err = artifact.readSquashfs(0)
+	case strings.HasSuffix(name, ".appimage"):
+		artifact.Format = "AppImage"
+		var offset int64
+		offset, err = elfSize(artifactPath)
+		if err == nil {
+			err = artifact.readSquashfs(offset)
+		}
+	case strings.HasSuffix(name, ".zip"), strings.HasSuffix(name, ".msix"), strings.HasSuffix(name, ".appx"), strings.HasSuffix(name, ".nupkg"):
+		artifact.Format = "zip"
+		err = artifact.readZip()
+	case strings.HasSuffix(name, ".apk"), strings.Contains(name, ".tar"), strings.HasSuffix(name, ".tgz"), strings.HasSuffix(name, ".txz"):
+		artifact.Format = "tar"
+		err = artifact.readCompressedTar()
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the %s %s", artifact.Format, filepath.Base(artifactPath))
+	}
+	return artifact, nil
+}
+
+// add adds a file of the artifact, and reads its metadata if it's a
+// metadata file.
+func (a *Artifact) add(name string, mode os.FileMode, link string, content io.Reader) error {
+	name = entryName(name)
+	entry := Entry{Mode: mode.Perm(), Link: link}
+	if link == "" {
+		hash := sha256.New()
+		var metadata strings.Builder
+		writer := io.Writer(hash)
+		parse, isMetadata := metadataFiles[name]
+		if isMetadata {
+			writer = io.MultiWriter(hash, &metadata)
+		}
+		size, err := io.Copy(writer, content)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", name)
+		}
+		entry.Size = size
+		entry.SHA256 = hex.EncodeToString(hash.Sum(nil))
+		if isMetadata {
+			for key, value := range parse([]byte(metadata.String())) {
+				a.Metadata[key] = value
+			}
+		}
+	}
+	a.Entries[name] = entry
+	return nil
+}
+
+// entryName returns the path of a file in an artifact, relative to its root
+// and with slashes.
+func entryName(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(name)), "/")
+}
+
+func (a *Artifact) readDir(dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return a.add(name, info.Mode(), link, nil)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return a.add(name, info.Mode(), "", file)
+	})
+}
+
+func (a *Artifact) readTar(reader io.Reader) error {
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeReg, tar.TypeRegA:
+			err = a.add(header.Name, os.FileMode(header.Mode), "", tarReader)
+		case tar.TypeSymlink:
+			err = a.add(header.Name, os.FileMode(header.Mode), header.Linkname, nil)
+		case tar.TypeLink:
+			// the hard links have the content of their target
+			a.Entries[entryName(header.Name)] = a.Entries[entryName(header.Linkname)]
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// readCompressedTar reads a tar archive compressed with gzip, bzip2, xz or
+// zstd, detected by their magic numbers, or uncompressed.
+func (a *Artifact) readCompressedTar() error {
+	file, err := os.Open(a.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	magic, _ := reader.Peek(6)
+	switch {
+	case len(magic) >= 2 && magic[0] == 0x1f && magic[1] == 0x8b:
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		return a.readTar(gzipReader)
+	case len(magic) >= 3 && string(magic[:3]) == "BZh":
+		return a.readTar(bzip2.NewReader(reader))
+	case len(magic) >= 6 && string(magic) == "\xfd7zXZ\x00":
+		return a.readCommandTar(exec.Command(build.XzBin(), "-dc", a.Path))
+	case len(magic) >= 4 && string(magic[:4]) == "\x28\xb5\x2f\xfd":
+		return a.readCommandTar(exec.Command(build.ZstdBin(), "-dcq", a.Path))
+	}
+	return a.readTar(reader)
+}
+
+// readCommandTar reads the tar archive written by a command to its output.
+func (a *Artifact) readCommandTar(cmd *exec.Cmd) error {
+	return readCommandOutput(cmd, a.readTar)
+}
+
+func readCommandOutput(cmd *exec.Cmd, read func(io.Reader) error) error {
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	err = cmd.Start()
+	if err != nil {
+		return err
+	}
+	readErr := read(output)
+	// the rest of the output is discarded for the command to exit
+	io.Copy(ioutil.Discard, output)
+	err = cmd.Wait()
+	if err != nil {
+		return errors.Wrapf(err, "%s failed: %s", filepath.Base(cmd.Path), strings.TrimSpace(stderr.String()))
+	}
+	return readErr
+}
+
+// readDeb reads the data archive and the control fields of a deb package.
+func (a *Artifact) readDeb() error {
+	err := a.readCommandTar(exec.Command(build.DpkgDebBin(), "--fsys-tarfile", a.Path))
+	if err != nil {
+		return err
+	}
+	control, err := exec.Command(build.DpkgDebBin(), "--field", a.Path).Output()
+	if err != nil {
+		return errors.Wrap(err, "failed to read the control fields")
+	}
+	var field string
+	for _, line := range strings.Split(string(control), "\n") {
+		if strings.HasPrefix(line, " ") && field != "" {
+			a.Metadata[field] += "\n" + strings.TrimSpace(line)
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) == 2 {
+			field = parts[0]
+			a.Metadata[field] = strings.TrimSpace(parts[1])
+		}
+	}
+	return nil
+}
+
+// rpmTags are the header tags of the rpm packages read as metadata, by
+// their name in the spec.
+var rpmTags = []struct{ tag, name string }{
+	{"NAME", "Name"},
+	{"VERSION", "Version"},
+	{"RELEASE", "Release"},
+	{"ARCH", "Arch"},
+	{"LICENSE", "License"},
+	{"SUMMARY", "Summary"},
+	{"URL", "URL"},
+}
+
+// readRpm reads the payload and the header of a rpm package.
+func (a *Artifact) readRpm() error {
+	err := readCommandOutput(exec.Command(build.Rpm2cpioBin(), a.Path), a.readCpio)
+	if err != nil {
+		return err
+	}
+	var format []string
+	for _, tag := range rpmTags {
+		format = append(format, "%{"+tag.tag+"}")
+	}
+	header, err := exec.Command(build.RpmBin(), "-qp", "--queryformat", strings.Join(format, `\n`), a.Path).Output()
+	if err != nil {
+		return errors.Wrap(err, "failed to read the header")
+	}
+	for i, value := range strings.SplitN(string(header), "\n", len(rpmTags)) {
+		a.Metadata[rpmTags[i].name] = strings.TrimSpace(value)
+	}
+	for name, option := range map[string]string{"Requires": "--requires", "Provides": "--provides"} {
+		values, err := exec.Command(build.RpmBin(), "-qp", option, a.Path).Output()
+		if err != nil {
+			return errors.Wrapf(err, "failed to read the %s", name)
+		}
+		a.Metadata[name] = strings.Join(strings.Fields(strings.Replace(string(values), "\n", ",", -1)), " ")
+	}
+	return nil
+}
+
+// readCpio reads a cpio archive in the newc format, the payload format of
+// the rpm packages.
+func (a *Artifact) readCpio(reader io.Reader) error {
+	bufReader := bufio.NewReader(reader)
+	var offset int64
+	skip := func(n int64) error {
+		_, err := io.CopyN(ioutil.Discard, bufReader, n)
+		offset += n
+		return err
+	}
+	pad := func() error { return skip((4 - offset%4) % 4) }
+	for {
+		header := make([]byte, 110)
+		_, err := io.ReadFull(bufReader, header)
+		if err != nil {
+			return errors.Wrap(err, "invalid cpio archive")
+		}
+		offset += 110
+		if string(header[:6]) != "070701" && string(header[:6]) != "070702" {
+			return errors.New("unsupported cpio archive, expected the newc format")
+		}
+		field := func(i int) int64 {
+			value, _ := strconv.ParseInt(string(header[6+i*8:14+i*8]), 16, 64)
+			return value
+		}
+		mode, size, nameSize := field(1), field(6), field(11)
+		name := make([]byte, nameSize)
+		_, err = io.ReadFull(bufReader, name)
+		if err != nil {
+			return errors.Wrap(err, "invalid cpio archive")
+		}
+		offset += nameSize
+		if err := pad(); err != nil {
+			return err
+		}
+		fileName := strings.TrimRight(string(name), "\x00")
+		if fileName == "TRAILER!!!" {
+			return nil
+		}
+		content := io.LimitReader(bufReader, size)
+		switch mode & 0170000 {
+		case 0100000:
+			err = a.add(fileName, os.FileMode(mode), "", content)
+		case 0120000:
+			var link []byte
+			link, err = ioutil.ReadAll(content)
+			if err == nil {
+				err = a.add(fileName, os.FileMode(mode), string(link), nil)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		// the rest of the content, when it wasn't read
+		io.Copy(ioutil.Discard, content)
+		offset += size
+		if err := pad(); err != nil {
+			return err
+		}
+	}
+}
+
+func (a *Artifact) readZip() error {
+	zipReader, err := zip.OpenReader(a.Path)
+	if err != nil {
+		return err
+	}
+	defer zipReader.Close()
+	for _, file := range zipReader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		content, err := file.Open()
+		if err != nil {
+			return err
+		}
+		if file.Mode()&os.ModeSymlink != 0 {
+			var link []byte
+			link, err = ioutil.ReadAll(content)
+			if err == nil {
+				err = a.add(file.Name, file.Mode(), string(link), nil)
+			}
+		} else {
+			err = a.add(file.Name, file.Mode(), "", content)
+		}
+		content.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readSquashfs extracts the squashfs filesystem of the snaps, and of the
+// AppImages after their runtime, to read its files.
+func (a *Artifact) readSquashfs(offset int64) error {
+	dir, err := ioutil.TempDir("", "hover-diff-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	root := filepath.Join(dir, "root")
+	output, err := exec.Command(build.UnsquashfsBin(), "-no-xattrs", "-offset", strconv.FormatInt(offset, 10), "-dest", root, a.Path).CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "unsquashfs failed: %s", strings.TrimSpace(string(output)))
+	}
+	return a.readDir(root)
+}
+
+// elfSize returns the size of an ELF executable, from the end of its section
+// header table. The AppImages append their squashfs filesystem to the ELF
+// runtime.
+func elfSize(path string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	header := make([]byte, 64)
+	_, err = io.ReadFull(file, header)
+	if err != nil || string(header[:4]) != "\x7fELF" {
+		return 0, errors.New("the AppImage runtime isn't an ELF executable")
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if header[5] == 2 {
+		order = binary.BigEndian
+	}
+	if header[4] == 1 {
+		// 32-bit
+		return int64(order.Uint32(header[0x20:])) + int64(order.Uint16(header[0x2E:]))*int64(order.Uint16(header[0x30:])), nil
+	}
+	return int64(order.Uint64(header[0x28:])) + int64(order.Uint16(header[0x3A:]))*int64(order.Uint16(header[0x3C:])), nil
+}
+
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// pkgInfoMetadata returns the fields of the .PKGINFO of the pacman and apk
+// packages, the repeated fields such as depend joined by spaces.
+func pkgInfoMetadata(content []byte) map[string]string {
+	metadata := map[string]string{}
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		// the size and build date change with every build
+		if key == "builddate" || key == "size" {
+			continue
+		}
+		if metadata[key] != "" {
+			value = metadata[key] + " " + value
+		}
+		metadata[key] = value
+	}
+	return metadata
+}
+
+// snapMetadata returns the scalar fields of the snap.yaml of the snaps.
+func snapMetadata(content []byte) map[string]string {
+	var fields map[string]interface{}
+	if yaml.Unmarshal(content, &fields) != nil {
+		return nil
+	}
+	metadata := map[string]string{}
+	for key, value := range fields {
+		switch value := value.(type) {
+		case string:
+			metadata[key] = value
+		case int, float64, bool:
+			metadata[key] = strings.TrimSpace(yamlString(value))
+		}
+	}
+	return metadata
+}
+
+func yamlString(value interface{}) string {
+	out, _ := yaml.Marshal(value)
+	return string(out)
+}
@@ -0,0 +1,115 @@
+package artifactdiff
+
+import (
+	"sort"
+)
+
+// Change is a file added, removed or changed between two artifacts.
+type Change struct {
+	Path   string
+	Before *Entry // nil when the file was added
+	After  *Entry // nil when the file was removed
+}
+
+// SizeDelta returns how much the file grew between the artifacts.
+func (c Change) SizeDelta() int64 {
+	var delta int64
+	if c.After != nil {
+		delta += c.After.Size
+	}
+	if c.Before != nil {
+		delta -= c.Before.Size
+	}
+	return delta
+}
+
+// MetadataChange is a metadata field changed between two artifacts, such as
+// the version of a package.
+type MetadataChange struct {
+	Field  string
+	Before string
+	After  string
+}
+
+// Diff is the difference between two artifacts.
+type Diff struct {
+	Metadata  []MetadataChange
+	Added     []Change // Largest first
+	Removed   []Change // Largest first
+	Changed   []Change // Largest size change first
+	Unchanged int
+}
+
+// Identical returns whether the artifacts have the same content.
+func (d Diff) Identical() bool {
+	return len(d.Metadata) == 0 && len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Compare compares the files and the metadata of two artifacts. The files
+// are changed when their content, link target or permissions differ.
+func Compare(before, after *Artifact) Diff {
+	var diff Diff
+	for name, beforeEntry := range before.Entries {
+		beforeEntry := beforeEntry
+		afterEntry, ok := after.Entries[name]
+		switch {
+		case !ok:
+			diff.Removed = append(diff.Removed, Change{Path: name, Before: &beforeEntry})
+		case afterEntry != beforeEntry:
+			diff.Changed = append(diff.Changed, Change{Path: name, Before: &beforeEntry, After: &afterEntry})
+		default:
+			diff.Unchanged++
+		}
+	}
+	for name, afterEntry := range after.Entries {
+		afterEntry := afterEntry
+		if _, ok := before.Entries[name]; !ok {
+			diff.Added = append(diff.Added, Change{Path: name, After: &afterEntry})
+		}
+	}
+	if len(before.Entries) == 0 && len(after.Entries) == 0 && before.SHA256 != after.SHA256 {
+		// the artifacts compared as a whole
+		diff.Changed = append(diff.Changed, Change{
+			Path:   "",
+			Before: &Entry{Size: before.Size, SHA256: before.SHA256},
+			After:  &Entry{Size: after.Size, SHA256: after.SHA256},
+		})
+	}
+	sortChanges(diff.Added)
+	sortChanges(diff.Removed)
+	sortChanges(diff.Changed)
+
+	fields := map[string]bool{}
+	for field := range before.Metadata {
+		fields[field] = true
+	}
+	for field := range after.Metadata {
+		fields[field] = true
+	}
+	for field := range fields {
+		if before.Metadata[field] != after.Metadata[field] {
+			diff.Metadata = append(diff.Metadata, MetadataChange{Field: field, Before: before.Metadata[field], After: after.Metadata[field]})
+		}
+	}
+	sort.Slice(diff.Metadata, func(i, j int) bool { return diff.Metadata[i].Field < diff.Metadata[j].Field })
+	return diff
+}
+
+// sortChanges sorts changes by the size of their change, largest first, and
+// by path.
+func sortChanges(changes []Change) {
+	sort.Slice(changes, func(i, j int) bool {
+		a, b := abs(changes[i].SizeDelta()), abs(changes[j].SizeDelta())
+		if a != b {
+			return a > b
+		}
+		return changes[i].Path < changes[j].Path
+	})
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
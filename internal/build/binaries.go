@@ -3,8 +3,10 @@ package build
 import (
 	"os"
 	"os/exec"
+	"path/filepath"
 	"sync"
 
+	"github.com/go-flutter-desktop/hover/internal/explain"
 	"github.com/go-flutter-desktop/hover/internal/log"
 )
 
@@ -20,7 +22,7 @@ func (b *binLookup) FullPath() string {
 		var err error
 		b.fullPath, err = exec.LookPath(b.Name)
 		if err != nil {
-			log.Errorf("Failed to lookup `%s` executable: %s. %s", b.Name, err, b.InstallInstructions)
+			log.ErrorCodef(explain.MissingExecutable, "Failed to lookup `%s` executable: %s. %s", b.Name, err, b.InstallInstructions)
 			os.Exit(1)
 		}
 	})
@@ -42,12 +44,87 @@ var (
 	dockerBinLookup = binLookup{
 		Name: "docker",
 	}
+	awsBinLookup = binLookup{
+		Name:                "aws",
+		InstallInstructions: "Please install the AWS CLI to publish to s3.\nhttps://aws.amazon.com/cli/",
+	}
+	gpgBinLookup = binLookup{
+		Name:                "gpg",
+		InstallInstructions: "Please install GnuPG to sign the repository metadata.",
+	}
+	jsignBinLookup = binLookup{
+		Name:                "jsign",
+		InstallInstructions: "Please install jsign to sign the windows executables and packages.\nhttps://ebourg.github.io/jsign/",
+	}
+	dpkgDebBinLookup = binLookup{
+		Name:                "dpkg-deb",
+		InstallInstructions: "Please install dpkg to update apt repositories.",
+	}
+	createrepoBinLookup = binLookup{
+		Name:                "createrepo_c",
+		InstallInstructions: "Please install createrepo_c to update yum repositories.",
+	}
+	makepkgBinLookup = binLookup{
+		Name:                "makepkg",
+		InstallInstructions: "Please install pacman to update AUR packages.",
+	}
+	snapcraftBinLookup = binLookup{
+		Name:                "snapcraft",
+		InstallInstructions: "Please install snapcraft to upload snaps to the Snap Store.\nhttps://snapcraft.io/docs/snapcraft-overview",
+	}
+	oscBinLookup = binLookup{
+		Name:                "osc",
+		InstallInstructions: "Please install osc to upload packages to the Open Build Service.\nhttps://openbuildservice.org/help/manuals/obs-user-guide/",
+	}
+	cmakeBinLookup = binLookup{
+		Name:                "cmake",
+		InstallInstructions: "Please install cmake to build the native libraries of the FFI plugins.\nhttps://cmake.org/download/",
+	}
+	xzBinLookup = binLookup{
+		Name:                "xz",
+		InstallInstructions: "Please install xz to read the xz compressed archives.",
+	}
+	zstdBinLookup = binLookup{
+		Name:                "zstd",
+		InstallInstructions: "Please install zstd to read the zstd compressed archives.",
+	}
+	unsquashfsBinLookup = binLookup{
+		Name:                "unsquashfs",
+		InstallInstructions: "Please install squashfs-tools to read the snap and AppImage packages.",
+	}
+	rpmBinLookup = binLookup{
+		Name:                "rpm",
+		InstallInstructions: "Please install rpm to read the rpm packages.",
+	}
+	rpm2cpioBinLookup = binLookup{
+		Name:                "rpm2cpio",
+		InstallInstructions: "Please install rpm2cpio to read the rpm packages.",
+	}
+	stripBinLookup = binLookup{
+		Name:                "strip",
+		InstallInstructions: "Please install binutils to strip the executables.",
+	}
+	xcrunBinLookup = binLookup{
+		Name:                "xcrun",
+		InstallInstructions: "Please install the Xcode command line tools to notarize the darwin packages, with `xcode-select --install`.",
+	}
+	ghBinLookup = binLookup{
+		Name:                "gh",
+		InstallInstructions: "Please install the GitHub CLI to publish GitHub releases.\nhttps://cli.github.com",
+	}
 )
 
 func GoBin() string {
 	return goBinLookup.FullPath()
 }
 
+// SetFlutterSDK makes FlutterBin return the flutter executable of a Flutter
+// SDK instead of the one found in PATH.
+func SetFlutterSDK(sdkPath string) {
+	flutterBinLookup.Name = filepath.Join(sdkPath, "bin", "flutter")
+	flutterBinLookup.InstallInstructions = "Please check the flutter-path in go/hover.yaml or the --flutter-path flag, it must be the path of a Flutter SDK."
+}
+
 func FlutterBin() string {
 	return flutterBinLookup.FullPath()
 }
@@ -59,3 +136,71 @@ func GitBin() string {
 func DockerBin() string {
 	return dockerBinLookup.FullPath()
 }
+
+func AwsBin() string {
+	return awsBinLookup.FullPath()
+}
+
+func GpgBin() string {
+	return gpgBinLookup.FullPath()
+}
+
+func JsignBin() string {
+	return jsignBinLookup.FullPath()
+}
+
+func DpkgDebBin() string {
+	return dpkgDebBinLookup.FullPath()
+}
+
+func CreaterepoBin() string {
+	return createrepoBinLookup.FullPath()
+}
+
+func MakepkgBin() string {
+	return makepkgBinLookup.FullPath()
+}
+
+func SnapcraftBin() string {
+	return snapcraftBinLookup.FullPath()
+}
+
+func GhBin() string {
+	return ghBinLookup.FullPath()
+}
+
+func OscBin() string {
+	return oscBinLookup.FullPath()
+}
+
+func CmakeBin() string {
+	return cmakeBinLookup.FullPath()
+}
+
+func XzBin() string {
+	return xzBinLookup.FullPath()
+}
+
+func ZstdBin() string {
+	return zstdBinLookup.FullPath()
+}
+
+func UnsquashfsBin() string {
+	return unsquashfsBinLookup.FullPath()
+}
+
+func RpmBin() string {
+	return rpmBinLookup.FullPath()
+}
+
+func Rpm2cpioBin() string {
+	return rpm2cpioBinLookup.FullPath()
+}
+
+func StripBin() string {
+	return stripBinLookup.FullPath()
+}
+
+func XcrunBin() string {
+	return xcrunBinLookup.FullPath()
+}
@@ -3,6 +3,11 @@ package build
 import (
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
 
 	"github.com/go-flutter-desktop/hover/internal/log"
 )
@@ -11,6 +16,72 @@ import (
 // Much like android and ios are already used.
 const BuildPath = "go"
 
+// DefaultTargetArch is the architecture built when none is given.
+const DefaultTargetArch = "amd64"
+
+// TargetArchs are the architectures hover can build for, with their GNU
+// names used by the linux packaging tools.
+var TargetArchs = map[string]string{
+	"amd64": "x86_64",
+	"arm64": "aarch64",
+}
+
+var targetArch = DefaultTargetArch
+
+// SetTargetArch sets the architecture of the build, one of TargetArchs.
+func SetTargetArch(arch string) {
+	if _, ok := TargetArchs[arch]; !ok {
+		log.Errorf("Architecture %s is not supported, use amd64 or arm64.", arch)
+		os.Exit(1)
+	}
+	targetArch = arch
+}
+
+// TargetArch returns the architecture of the build, in GOARCH format.
+func TargetArch() string {
+	return targetArch
+}
+
+// TargetGnuArch returns the architecture of the build in GNU format
+// (x86_64, aarch64).
+func TargetGnuArch() string {
+	return TargetArchs[targetArch]
+}
+
+var aot bool
+
+// SetAOT sets whether the dart code is compiled ahead-of-time, to run with a
+// release engine, instead of shipping the kernel to a debug (JIT) engine.
+func SetAOT(enabled bool) {
+	aot = enabled
+}
+
+// AOTLibrary is the name of the dart code compiled ahead-of-time, next to the
+// executable. go-flutter loads it from there on every platform, as an ELF
+// library: the dart runtime of the engine loads ELF snapshots itself, on
+// darwin and windows too, like the flutter runners of windows do.
+const AOTLibrary = "libapp.so"
+
+// AOT returns whether the dart code is compiled ahead-of-time.
+func AOT() bool {
+	return aot
+}
+
+// SplitTargetArch splits the architecture suffix from the name of an output
+// directory, e.g. linux-deb-arm64 is the linux-deb target built for arm64.
+// The arch is empty when the name has no architecture suffix, which is the
+// case of the default architecture.
+func SplitTargetArch(name string) (target, arch string) {
+	i := strings.LastIndex(name, "-")
+	if i < 0 {
+		return name, ""
+	}
+	if _, ok := TargetArchs[name[i+1:]]; !ok {
+		return name, ""
+	}
+	return name[:i], name[i+1:]
+}
+
 // buildDirectoryPath returns the path in `BuildPath`/build.
 // If needed, the directory is create at the returned path.
 func buildDirectoryPath(targetOS, path string) string {
@@ -29,11 +100,25 @@ func buildDirectoryPath(targetOS, path string) string {
 	return outputDirectoryPath
 }
 
+// TargetArchSuffix returns the suffix of the output directories and the
+// artifact names for the target architecture, e.g. -arm64. It's empty for the
+// default architecture, whose outputs keep the names they had before hover
+// built for several architectures.
+func TargetArchSuffix(separator string) string {
+	if targetArch == DefaultTargetArch {
+		return ""
+	}
+	return separator + targetArch
+}
+
 // OutputDirectoryPath returns the path where the go-flutter binary and flutter
-// binaries blobs will be stored for a particular platform.
+// binaries blobs will be stored for a particular platform, or the packages of
+// a packaging format. The directory name ends with the target architecture
+// when it isn't the default one (e.g. linux-arm64, linux-deb-arm64), so the
+// builds of several architectures don't overwrite each other.
 // If needed, the directory is create at the returned path.
-func OutputDirectoryPath(targetOS string) string {
-	return buildDirectoryPath(targetOS, "outputs")
+func OutputDirectoryPath(target string) string {
+	return buildDirectoryPath(target+TargetArchSuffix("-"), "outputs")
 }
 
 // IntermediatesDirectoryPath returns the path where the intermediates stored.
@@ -53,7 +138,7 @@ func OutputBinary(executableName, targetOS string) string {
 	switch targetOS {
 	case "darwin":
 		// no special filename
-	case "linux":
+	case "linux", "freebsd":
 		// no special filename
 	case "windows":
 		outputBinaryName += ".exe"
@@ -77,7 +162,7 @@ func EngineFilename(targetOS string) string {
 	switch targetOS {
 	case "darwin":
 		return "FlutterEmbedder.framework"
-	case "linux":
+	case "linux", "freebsd":
 		return "libflutter_engine.so"
 	case "windows":
 		return "flutter_engine.dll"
@@ -87,3 +172,17 @@ func EngineFilename(targetOS string) string {
 		return ""
 	}
 }
+
+// BuildTime returns the time a build is made at in UTC: the timestamp of
+// SOURCE_DATE_EPOCH when it's set, to keep the builds reproducible, or now.
+func BuildTime(now time.Time) (time.Time, error) {
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if epoch == "" {
+		return now.UTC(), nil
+	}
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to parse SOURCE_DATE_EPOCH")
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
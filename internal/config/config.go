@@ -1,8 +1,11 @@
 package config
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -26,37 +29,597 @@ const BuildOpenGlVersionDefault = "3.3"
 
 // Config contains the parsed contents of hover.yaml
 type Config struct {
-	loaded          bool
-	ApplicationName string `yaml:"application-name"`
-	ExecutableName  string `yaml:"executable-name"`
-	PackageName     string `yaml:"package-name"`
-	License         string
-	Target          string
-	Branch          string
-	CachePath       string `yaml:"cache-path"`
-	OpenGL          string
-	Engine          string `yaml:"engine-version"`
+	loaded           bool
+	nightly          bool
+	SchemaVersion    int    `yaml:"schema-version"`
+	ApplicationName  string `yaml:"application-name"`
+	ExecutableName   string `yaml:"executable-name"`
+	PackageName      string `yaml:"package-name"`
+	License          string
+	Target           string
+	Branch           string
+	CachePath        string `yaml:"cache-path"`
+	TmpDir           string `yaml:"tmp-dir"`
+	OpenGL           string
+	Engine           string `yaml:"engine-version"`
+	LocalEngine      string `yaml:"local-engine"`
+	FlutterPath      string `yaml:"flutter-path"`
+	FlutterChannel   string `yaml:"flutter-channel"`
+	Icons            map[string]string
+	OmitVersion      []string          `yaml:"omit-version-in-filename"` // Packaging formats (linux-deb) or platforms (linux) whose artifact names don't contain the version
+	ArtifactNames    map[string]string `yaml:"artifact-names"`           // application-name or package-name, the name the artifacts of a packaging format (linux-deb) or platform (linux) start with
+	Launcher         LauncherConfig
+	ReleaseEngines   EngineChecksums     `yaml:"release-engine-sha256"` // SHA-256 of the release engine archives of --aot
+	SplitPackages    SplitPackagesConfig `yaml:"split-packages"`
+	Deb              DebConfig
+	Rpm              RpmConfig
+	Desktop          DesktopConfig
+	AppStream        AppStreamConfig         `yaml:"appstream"`
+	FileAssociations []FileAssociationConfig `yaml:"file-associations"`
+	URLSchemes       []string                `yaml:"url-schemes"` // Custom URL schemes opened by the app, e.g. myapp for the myapp:// links
+	Flatpak          FlatpakConfig
+	Makeself         MakeselfConfig
+	Inno             InnoConfig
+	Winget           WingetConfig
+	Scoop            ScoopConfig
+	Homebrew         HomebrewConfig
+	AppStore         AppStoreConfig         `yaml:"appstore"`
+	WindowsServices  []WindowsServiceConfig `yaml:"windows-services"`
+	Launchd          []LaunchdJobConfig
+	Repositories     RepositoriesConfig
+	CrashReportURL   string   `yaml:"crash-report-url"`
+	EncryptAssets    bool     `yaml:"encrypt-assets"`
+	DisplayServer    string   `yaml:"display-server"`   // x11 (default) or wayland, the display server of the linux builds and packages
+	WMClass          string   `yaml:"wm-class"`         // Window class of the app on linux, the StartupWMClass of the .desktop files
+	StartupNotify    bool     `yaml:"startup-notify"`   // StartupNotify of the .desktop files
+	WindowsConsole   string   `yaml:"windows-console"`  // debug (default), always or never, when the windows executable opens a console window
+	SingleInstance   bool     `yaml:"single-instance"`  // Forward the later launches of the app to its first instance, with go/cmd/singleinstance.go
+	DBusActivatable  bool     `yaml:"dbus-activatable"` // Activate the first instance through DBus on linux, requires single-instance
+	Locales          []string // Supported locales of the app, checked against the translations before packaging
+	Survey           SurveyConfig
+	LicensePolicy    LicensePolicyConfig `yaml:"license-policy"`
+	Webhooks         []WebhookConfig
+	Signing          SigningConfig
+	PostBuild        map[string][]string `yaml:"post-build"` // Ordered post-build steps of a packaging format (windows-msi) or platform (windows): strip, sign, package, sign-installer, notarize and staple
+	Retry            RetryConfig
+	Provenance       ProvenanceConfig
+	Integrity        IntegrityConfig
+	Nightly          NightlyConfig
+	SizeBudgets      SizeBudgetsConfig           `yaml:"size-budgets"`
+	Preferences      []PreferenceConfig          // Preferences of the app, installed with their defaults by the packages
+	TemplateData     map[string]string           `yaml:"template-data"` // Custom template data of the packaging templates
+	Run              map[string]RunProfileConfig // Named profiles of hover run, selected with --profile
+}
+
+// RunProfileConfig is a named setup of hover run, shared in hover.yaml
+// instead of long command lines.
+type RunProfileConfig struct {
+	Target      string            // Overrides the target of hover.yaml, the --target flag takes precedence
+	Args        []string          // Command-line arguments of the app
+	Env         map[string]string // Set before starting the app, the values are expanded ($HOME)
+	DartDefines map[string]string `yaml:"dart-defines"` // Passed to flutter with --dart-define
+	WindowSize  string            `yaml:"window-size"`  // <width>x<height>, applied by go/cmd/runprofile.go
+}
+
+// PreferenceConfig declares a preference of the app, read and written by
+// go/cmd/preferences.go (added by `hover init --preferences`).
+type PreferenceConfig struct {
+	Key     string // Lowercase letters, digits and dashes
+	Type    string // string (default), bool or int
+	Default string
+	Summary string // Description of the GSettings key
+}
+
+// LauncherConfig customizes the scripts starting the app: the AppRun of
+// linux-appimage, the /usr/bin wrapper of the linux packages, the launcher of
+// linux-tar and the .cmd of windows-portable.
+type LauncherConfig struct {
+	Env              map[string]string // Set before starting the app, the values are expanded by the script ($HOME, %APPDATA%)
+	LibraryPath      []string          `yaml:"library-path"`      // Prepended to LD_LIBRARY_PATH (PATH on windows), relative to the app directory
+	WorkingDirectory string            `yaml:"working-directory"` // Relative to the app directory
+	Templates        map[string]string // Replace the script of a packaging format (linux-appimage) or platform (linux)
+}
+
+// SplitPackagesConfig configures the companion packages built with the
+// linux-deb and linux-rpm packages.
+type SplitPackagesConfig struct {
+	DebugSymbols bool     `yaml:"debug-symbols"` // Move the debug symbols of the executable to a dbgsym (deb) or debuginfo (rpm) package
+	Data         []string // Paths of the build output moved to a data package, which the app package depends on
+}
+
+// FlatpakConfig configures the runtime of the linux-flatpak package.
+type FlatpakConfig struct {
+	Runtime        string // org.freedesktop.Platform (default), org.gnome.Platform or org.kde.Platform
+	RuntimeVersion string `yaml:"runtime-version"`
+}
+
+// FlatpakDefaultRuntimeVersion is the version of the default flatpak
+// runtime, org.freedesktop.Platform.
+const FlatpakDefaultRuntimeVersion = "24.08"
+
+// GetFlatpakRuntime returns the runtime, its sdk and the runtime version of
+// the linux-flatpak package.
+func (c Config) GetFlatpakRuntime() (runtime, sdk, version string) {
+	runtime = c.Flatpak.Runtime
+	if runtime == "" {
+		runtime = "org.freedesktop.Platform"
+	}
+	version = c.Flatpak.RuntimeVersion
+	if version == "" {
+		if runtime != "org.freedesktop.Platform" {
+			log.Errorf("Set the flatpak runtime-version in go/hover.yaml for the %s runtime.", runtime)
+			os.Exit(1)
+		}
+		version = FlatpakDefaultRuntimeVersion
+	}
+	return runtime, strings.TrimSuffix(runtime, ".Platform") + ".Sdk", version
+}
+
+// MakeselfConfig configures the installer of the linux-run package.
+type MakeselfConfig struct {
+	Prefix             string // Directory the app is installed in, in <prefix>/<package>, defaults to /opt as root and ~/.local/opt otherwise
+	DesktopIntegration *bool  `yaml:"desktop-integration"` // Install the .desktop file of the app, defaults to true
+}
+
+// GetMakeselfDesktopIntegration returns whether the linux-run installer
+// installs the .desktop file of the app.
+func (c Config) GetMakeselfDesktopIntegration() bool {
+	return c.Makeself.DesktopIntegration == nil || *c.Makeself.DesktopIntegration
+}
+
+// DebConfig contains the relationships of the linux-deb and linux-deb-src
+// packages with other packages, written to their control files. The entries
+// have the syntax of the control files, e.g. "libgtk-3-0 (>= 3.22)".
+type DebConfig struct {
+	Depends    []string
+	Recommends []string
+	Suggests   []string
+	Conflicts  []string
+	Provides   []string
+}
+
+// DesktopConfig contains the entries of the .desktop file of the linux
+// packages, written by the linux/app.desktop.tmpl template. Its
+// StartupWMClass is the wm-class.
+type DesktopConfig struct {
+	GenericName string   `yaml:"generic-name"` // Generic name of the app, e.g. Text Editor
+	Categories  []string // Categories of the freedesktop menu specification, e.g. [Office, WordProcessor]
+	Keywords    []string // Searched by the menus and launchers in addition to the names
+	MimeType    []string `yaml:"mime-type"` // MIME types opened by the app, e.g. [text/markdown]
+	Terminal    bool     // Whether the app runs in a terminal
+}
+
+// AppStreamConfig contains the AppStream metainfo of the linux-deb,
+// linux-rpm, linux-flatpak and linux-snap packages, which the software
+// centers such as GNOME Software and KDE Discover list the apps by.
+type AppStreamConfig struct {
+	Summary         string                      // One line, defaults to the description of pubspec.yaml
+	Description     []string                    // Paragraphs, defaults to the description of pubspec.yaml
+	Screenshots     []AppStreamScreenshotConfig // The first one is the default screenshot
+	Releases        []AppStreamReleaseConfig    // Newest first, the version being packaged is added when missing
+	MetadataLicense string                      `yaml:"metadata-license"` // License of the metainfo, CC0-1.0 by default
+	ContentRating   map[string]string           `yaml:"content-rating"`   // OARS 1.1 attributes, e.g. violence-cartoon: mild
+}
+
+// FileAssociationConfig is a file type opened by the app, registered by the
+// linux packages, the darwin bundle and the windows msi.
+type FileAssociationConfig struct {
+	Extension   string // Without the dot, e.g. md
+	MimeType    string `yaml:"mime-type"` // e.g. text/markdown
+	Description string // Name of the file type, e.g. Markdown document
+	Icon        string // Path of a square PNG icon of at least 256x256 pixels, the icon of the files
+}
+
+// AppStreamScreenshotConfig is a screenshot of the AppStream metainfo.
+type AppStreamScreenshotConfig struct {
+	Image   string // https URL of the image
+	Caption string
+}
+
+// AppStreamReleaseConfig is a release of the AppStream metainfo.
+type AppStreamReleaseConfig struct {
+	Version     string
+	Date        string   // YYYY-MM-DD
+	Description []string // Paragraphs of the release notes
+}
+
+// RpmConfig contains the dependencies of the linux-rpm package, written to
+// its spec. The entries have the syntax of the spec, e.g. "libGL >= 1.0".
+type RpmConfig struct {
+	Requires      []string
+	BuildRequires []string `yaml:"build-requires"`
+	Provides      []string
+	Obsoletes     []string
+}
+
+// InnoConfig configures the installer of the windows-inno package.
+type InnoConfig struct {
+	InstallMode string   `yaml:"install-mode"` // admin (default, for all users), user (for the current user only) or dialog (asks the user)
+	Shortcuts   []string // start-menu and desktop (default both), an empty list for none
+	AppMutex    string   `yaml:"app-mutex"` // Name of a mutex created by the app, the installer asks to close the app while it's held
+}
+
+// GetInnoPrivileges returns the PrivilegesRequired and the
+// PrivilegesRequiredOverridesAllowed of the windows-inno installer, from its
+// install-mode.
+func (c Config) GetInnoPrivileges() (required, overridesAllowed string) {
+	switch c.Inno.InstallMode {
+	case "", "admin":
+		return "admin", ""
+	case "user":
+		return "lowest", ""
+	case "dialog":
+		return "admin", "dialog"
+	}
+	log.Errorf("Unknown inno install-mode %s in go/hover.yaml, use admin, user or dialog.", c.Inno.InstallMode)
+	os.Exit(1)
+	return "", ""
+}
+
+// GetInnoShortcuts returns whether the windows-inno installer creates the
+// start menu and the desktop shortcuts.
+func (c Config) GetInnoShortcuts() (startMenu, desktop bool) {
+	if c.Inno.Shortcuts == nil {
+		return true, true
+	}
+	for _, shortcut := range c.Inno.Shortcuts {
+		switch shortcut {
+		case "start-menu":
+			startMenu = true
+		case "desktop":
+			desktop = true
+		default:
+			log.Errorf("Unknown inno shortcut %s in go/hover.yaml, use start-menu or desktop.", shortcut)
+			os.Exit(1)
+		}
+	}
+	return startMenu, desktop
+}
+
+// WingetConfig configures the manifests of the windows-winget package.
+type WingetConfig struct {
+	Publisher         string // Defaults to the author of pubspec.yaml
+	PackageIdentifier string `yaml:"package-identifier"` // <Publisher>.<Name>, defaults to the publisher and the application name without spaces
+	InstallerURL      string `yaml:"installer-url"`      // URL the msi is published at, a template of the template data (e.g. {{.version}})
+}
+
+// GetWingetPublisher returns the publisher of the winget manifests.
+func (c Config) GetWingetPublisher(author string) string {
+	if c.Winget.Publisher != "" {
+		return c.Winget.Publisher
+	}
+	// the email of the author isn't part of the publisher
+	return strings.TrimSpace(strings.SplitN(author, "<", 2)[0])
+}
+
+var wingetIdentifierInvalidCharacters = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// GetWingetPackageIdentifier returns the PackageIdentifier of the winget
+// manifests.
+func (c Config) GetWingetPackageIdentifier(author, applicationName string) string {
+	if c.Winget.PackageIdentifier != "" {
+		return c.Winget.PackageIdentifier
+	}
+	return wingetIdentifierInvalidCharacters.ReplaceAllString(c.GetWingetPublisher(author), "") + "." + wingetIdentifierInvalidCharacters.ReplaceAllString(applicationName, "")
+}
+
+// ScoopConfig configures the manifest of the windows-scoop package.
+type ScoopConfig struct {
+	URL      string // URL the windows-portable zip is published at, a template of the template data (e.g. {{.version}})
+	Homepage string
+}
+
+// HomebrewConfig configures the cask of the darwin-cask package.
+type HomebrewConfig struct {
+	URL      string // URL the darwin-dmg is published at, a template of the template data (e.g. {{.version}})
+	Homepage string
+}
+
+// AppStoreConfig configures the signing of the darwin-appstore package
+// submitted to the Mac App Store.
+type AppStoreConfig struct {
+	ProvisioningProfile string `yaml:"provisioning-profile"` // Mac App Store provisioning profile of the app, embedded in the bundle
+	ApplicationIdentity string `yaml:"application-identity"` // Identity of the keychain signing the app, "3rd Party Mac Developer Application: <team> (<team id>)"
+	InstallerIdentity   string `yaml:"installer-identity"`   // Identity of the keychain signing the pkg, "3rd Party Mac Developer Installer: <team> (<team id>)"
+	TeamID              string `yaml:"team-id"`              // Defaults to the team id of the application identity
+	Category            string // LSApplicationCategoryType of the app, defaults to public.app-category.utilities
+}
+
+var appStoreIdentityTeamID = regexp.MustCompile(`\(([A-Z0-9]{10})\)$`)
+
+// GetAppStoreTeamID returns the team id of the darwin-appstore package, empty
+// when it isn't configured.
+func (c Config) GetAppStoreTeamID() string {
+	if c.AppStore.TeamID != "" {
+		return c.AppStore.TeamID
+	}
+	if match := appStoreIdentityTeamID.FindStringSubmatch(c.AppStore.ApplicationIdentity); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// GetAppStoreCategory returns the LSApplicationCategoryType of the
+// darwin-appstore package.
+func (c Config) GetAppStoreCategory() string {
+	if c.AppStore.Category == "" {
+		return "public.app-category.utilities"
+	}
+	return c.AppStore.Category
+}
+
+// WindowsServiceConfig declares a windows service installed by the
+// windows-msi package, such as a companion daemon of the app.
+type WindowsServiceConfig struct {
+	Name        string
+	DisplayName string `yaml:"display-name"`
+	Description string
+	Executable  string // File name of the service executable in the build output
+	Arguments   string
+	Account     string // LocalSystem (default), LocalService, NetworkService or a user account
+	Start       string // auto (default), demand or disabled
+	Recovery    WindowsServiceRecoveryConfig
+}
+
+// WindowsServiceRecoveryConfig contains the actions taken when a windows
+// service fails.
+type WindowsServiceRecoveryConfig struct {
+	Actions      []string // none, restart or reboot, for the first, second and subsequent failures
+	RestartDelay int      `yaml:"restart-delay"` // Seconds before restarting the service or the computer, defaults to 60
+	ResetPeriod  int      `yaml:"reset-period"`  // Seconds without failure after which the failure count is reset, defaults to a day
+}
+
+// LaunchdJobConfig declares a launchd agent or daemon installed by the
+// darwin-pkg package, such as a background helper of the app.
+type LaunchdJobConfig struct {
+	Label         string
+	Type          string // agent (default), loaded in the user sessions, or daemon, loaded system-wide
+	Program       string // File name of the executable in the build output, defaults to the app
+	Arguments     []string
+	RunAtLoad     bool `yaml:"run-at-load"`
+	KeepAlive     bool `yaml:"keep-alive"`
+	StartInterval int  `yaml:"start-interval"` // Seconds between the starts of the job
+	Environment   map[string]string
+}
+
+// SurveyConfig contains the URLs opened on the first launch of the app and
+// requested on uninstall. They are only used when OptIn is set.
+type SurveyConfig struct {
+	OptIn        bool   `yaml:"opt-in"`
+	FirstRunURL  string `yaml:"first-run-url"`
+	UninstallURL string `yaml:"uninstall-url"`
+}
+
+// GetSurveyURLs returns the first-run and uninstall URLs, which are empty
+// unless the survey is opted in.
+func (c Config) GetSurveyURLs() (string, string) {
+	if !c.Survey.OptIn {
+		if c.Survey.FirstRunURL != "" || c.Survey.UninstallURL != "" {
+			log.Warnf("The survey URLs in go/hover.yaml are ignored, set survey.opt-in to true to use them.")
+		}
+		return "", ""
+	}
+	return c.Survey.FirstRunURL, c.Survey.UninstallURL
+}
+
+// LicensePolicyConfig configures the dependency licenses flagged by
+// hover audit licenses.
+type LicensePolicyConfig struct {
+	Deny         []string // SPDX identifiers, defaults to the copyleft licenses
+	AllowUnknown bool     `yaml:"allow-unknown"`
+	Exceptions   []string // Dependencies and fonts (paths in flutter_assets or file names) that were reviewed, they are never flagged
+}
+
+// WebhookConfig is a webhook notified when a build starts, succeeds or
+// fails.
+type WebhookConfig struct {
+	URL             string
+	Kind            string   // slack, discord or generic (default)
+	Events          []string // start, success or failure, defaults to all of them
+	ArtifactBaseURL string   `yaml:"artifact-base-url"` // Base of the artifact links, the local paths are sent otherwise
+}
+
+// WantsEvent reports whether the webhook is notified of an event.
+func (w WebhookConfig) WantsEvent(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// SigningConfig configures the signing of the windows executables and
+// packages. The credentials are read from the environment.
+type SigningConfig struct {
+	Provider     string // file, azure-key-vault, aws-kms or pkcs11
+	Keystore     string // Keystore file, key vault name, AWS region or PKCS#11 configuration file
+	Alias        string // Certificate name, KMS key id or PKCS#11 key label
+	Certificate  string // Certificate chain file, required for aws-kms
+	TimestampURL string `yaml:"timestamp-url"`
+}
+
+// EngineChecksums are the SHA-256 of engine archives, by engine version and
+// target OS.
+type EngineChecksums map[string]map[string]string
+
+// RetryConfig configures the retries of the operations failing on transient
+// network errors: the engine downloads, the uploads of hover publish, the
+// notarization and the packaging tools downloading their dependencies.
+type RetryConfig struct {
+	Attempts int    // Attempts of an operation, defaults to 3, 1 disables the retries
+	Delay    string // Delay before the first retry, doubled after each failed attempt, defaults to 2s
+	MaxDelay string `yaml:"max-delay"` // Longest delay between two attempts, defaults to 1m
+}
+
+// ProvenanceConfig configures the provenance attestations generated by
+// hover build --provenance.
+type ProvenanceConfig struct {
+	Key   string // PEM private key file signing the attestations, HOVER_PROVENANCE_KEY (the PEM content) takes precedence
+	Rekor string // URL of the Rekor transparency log the attestations are uploaded to, they aren't uploaded when empty
+}
+
+// IntegrityConfig configures the manifest of the hashes of the build output,
+// written to the builds and the packages and checked by go/cmd/integrity.go
+// when the app starts.
+type IntegrityConfig struct {
+	Manifest bool
+	Key      string // PEM private key signing the manifest, HOVER_INTEGRITY_KEY (the PEM content) takes precedence
+}
+
+// NightlyEnv is set to true by hover nightly for the builds of the nightly
+// channel, applying the name overrides of NightlyConfig.
+const NightlyEnv = "HOVER_NIGHTLY"
+
+// NightlyConfig configures the builds and the destinations of hover nightly.
+// The nightly channel is installed next to the stable one: its application
+// name, executable name and package name, the identifier of the app, differ.
+type NightlyConfig struct {
+	ApplicationName string   `yaml:"application-name"` // Defaults to the application name followed by " Nightly"
+	ExecutableName  string   `yaml:"executable-name"`  // Defaults to the executable name followed by "-nightly"
+	PackageName     string   `yaml:"package-name"`     // Defaults to the package name followed by "-nightly"
+	Builds          []string // Targets and packaging formats of hover build, e.g. linux-deb
+	Arches          []string // Defaults to amd64
+	Destination     string   // Destination of hover publish
+	SnapChannel     string   `yaml:"snap-channel"`   // Channel the linux-snap is released to, defaults to edge
+	GithubRelease   string   `yaml:"github-release"` // owner/repo the artifacts are uploaded to as a GitHub pre-release
+}
+
+// SizeBudgetsConfig contains the maximum sizes of the artifacts and of the
+// parts of the build output, checked after each build. The sizes are written
+// like 40MB, with the B, KB, MB and GB units.
+type SizeBudgetsConfig struct {
+	Artifacts  map[string]string // By packaging format (linux-deb) or platform (linux)
+	Components map[string]string // By path relative to the build output, e.g. flutter_assets
+	Warn       bool              // Only warn when a budget is exceeded, instead of failing the build
+}
+
+// RepositoriesConfig contains the package repositories updated by hover
+// publish. The locations have the same format as the publish destination.
+type RepositoriesConfig struct {
+	Apt          string
+	Yum          string
+	GpgKey       string `yaml:"gpg-key"`
+	Aur          string // Git remote of the AUR package, e.g. ssh://aur@aur.archlinux.org/myapp-bin.git
+	AurSourceURL string `yaml:"aur-source-url"` // Public URL of the publish destination, the HTTP and WebDAV destinations by default
+	HomebrewTap  string `yaml:"homebrew-tap"`   // Git remote of the Homebrew tap the darwin-cask is pushed to, e.g. git@github.com:me/homebrew-tap.git
+	Winget       string // Git remote of the winget manifests repository the windows-winget manifests are pushed to, e.g. a fork of winget-pkgs
 }
 
 func (c Config) GetApplicationName(projectName string) string {
-	if c.ApplicationName == "" {
-		return projectName
+	name := c.ApplicationName
+	if name == "" {
+		name = projectName
 	}
-	return c.ApplicationName
+	if c.nightly {
+		if c.Nightly.ApplicationName != "" {
+			return c.Nightly.ApplicationName
+		}
+		return name + " Nightly"
+	}
+	return name
 }
 
 func (c Config) GetExecutableName(projectName string) string {
-	if c.ExecutableName == "" {
-		return strings.ReplaceAll(projectName, " ", "")
+	name := c.ExecutableName
+	if name == "" {
+		name = strings.ReplaceAll(projectName, " ", "")
+	}
+	if c.nightly {
+		if c.Nightly.ExecutableName != "" {
+			return c.Nightly.ExecutableName
+		}
+		return name + "-nightly"
 	}
-	return c.ExecutableName
+	return name
 }
 
 func (c Config) GetPackageName(projectName string) string {
-	if c.PackageName == "" {
-		return strings.ReplaceAll(strings.ReplaceAll(strings.ReplaceAll(projectName, "-", ""), "_", ""), " ", "")
+	name := c.PackageName
+	if name == "" {
+		name = strings.ReplaceAll(strings.ReplaceAll(strings.ReplaceAll(projectName, "-", ""), "_", ""), " ", "")
+	}
+	if c.nightly {
+		if c.Nightly.PackageName != "" {
+			return c.Nightly.PackageName
+		}
+		return name + "-nightly"
+	}
+	return name
+}
+
+// GetNightlyArches returns the architectures of the nightly builds.
+func (c Config) GetNightlyArches() []string {
+	if len(c.Nightly.Arches) == 0 {
+		return []string{build.DefaultTargetArch}
+	}
+	return c.Nightly.Arches
+}
+
+// GetNightlySnapChannel returns the channel the nightly linux-snap is
+// released to.
+func (c Config) GetNightlySnapChannel() string {
+	if c.Nightly.SnapChannel == "" {
+		return "edge"
+	}
+	return c.Nightly.SnapChannel
+}
+
+// The display servers of the linux builds.
+const (
+	DisplayServerX11     = "x11"
+	DisplayServerWayland = "wayland"
+)
+
+// GetDisplayServer returns the display server the linux builds and packages
+// are made for, x11 by default.
+func (c Config) GetDisplayServer() string {
+	switch c.DisplayServer {
+	case "", DisplayServerX11:
+		return DisplayServerX11
+	case DisplayServerWayland:
+		return DisplayServerWayland
+	}
+	log.Errorf("Unknown display-server %s in go/hover.yaml, use x11 or wayland.", c.DisplayServer)
+	os.Exit(1)
+	return ""
+}
+
+// The console modes of the windows executable.
+const (
+	WindowsConsoleDebug  = "debug"
+	WindowsConsoleAlways = "always"
+	WindowsConsoleNever  = "never"
+)
+
+// GetWindowsConsole returns when the windows executable opens a console
+// window, only for the debug builds by default.
+func (c Config) GetWindowsConsole() string {
+	switch c.WindowsConsole {
+	case "", WindowsConsoleDebug:
+		return WindowsConsoleDebug
+	case WindowsConsoleAlways, WindowsConsoleNever:
+		return c.WindowsConsole
+	}
+	log.Errorf("Unknown windows-console %s in go/hover.yaml, use debug, always or never.", c.WindowsConsole)
+	os.Exit(1)
+	return ""
+}
+
+// GetWMClass returns the window class of the app on linux, the executable
+// name by default.
+func (c Config) GetWMClass(projectName string) string {
+	if c.WMClass == "" {
+		return c.GetExecutableName(projectName)
+	}
+	if strings.ContainsAny(c.WMClass, " \t\n") {
+		log.Errorf("The wm-class %q in go/hover.yaml must not contain whitespace.", c.WMClass)
+		os.Exit(1)
 	}
-	return c.PackageName
+	return c.WMClass
 }
 
 func (c Config) GetLicense() string {
@@ -67,21 +630,188 @@ func (c Config) GetLicense() string {
 	return c.License
 }
 
+// GetLauncherTemplate returns the template replacing the launcher script of
+// a packaging format, set for the format (linux-appimage) or for all formats
+// of a platform (linux).
+func (c Config) GetLauncherTemplate(packagingFormat string) (string, bool) {
+	if template, ok := c.Launcher.Templates[packagingFormat]; ok {
+		return template, true
+	}
+	template, ok := c.Launcher.Templates[strings.Split(packagingFormat, "-")[0]]
+	return template, ok
+}
+
+// GetTmpDir returns the directory of the temporary build directories:
+// HOVER_TMPDIR, the tmp-dir of go/hover.yaml or the system temporary
+// directory.
+func (c Config) GetTmpDir() string {
+	tmpDir := os.Getenv("HOVER_TMPDIR")
+	if tmpDir == "" {
+		tmpDir = c.TmpDir
+	}
+	if tmpDir == "" {
+		return os.TempDir()
+	}
+	// the packaging commands don't run in the project directory
+	absTmpDir, err := filepath.Abs(tmpDir)
+	if err != nil {
+		log.Errorf("Failed to resolve the temporary directory %s: %v", tmpDir, err)
+		os.Exit(1)
+	}
+	return absTmpDir
+}
+
+// GetIcon returns the path of the icon to use for a packaging format. An
+// icon can be set for a format (linux-snap) or for all formats of a platform
+// (darwin). Defaults to the icon in the assets directory.
+func (c Config) GetIcon(packagingFormat string) (string, bool) {
+	if icon, ok := c.Icons[packagingFormat]; ok {
+		return icon, true
+	}
+	if icon, ok := c.Icons[strings.Split(packagingFormat, "-")[0]]; ok {
+		return icon, true
+	}
+	return filepath.Join(build.BuildPath, "assets", "icon.png"), false
+}
+
+// OmitsVersionInFilename returns whether the artifact names of a packaging
+// format leave out the version, set for the format (linux-deb) or for all
+// formats of a platform (linux).
+func (c Config) OmitsVersionInFilename(packagingFormat string) bool {
+	for _, name := range c.OmitVersion {
+		if name == packagingFormat || name == strings.Split(packagingFormat, "-")[0] {
+			return true
+		}
+	}
+	return false
+}
+
+// The values of artifact-names in go/hover.yaml.
+const (
+	ArtifactNameApplication = "application-name"
+	ArtifactNamePackage     = "package-name"
+)
+
+// UsesApplicationNameInFilename returns whether the artifact names of a
+// packaging format start with the application name rather than the package
+// name, set for the format (linux-deb) or for all formats of a platform
+// (linux). Defaults to how the packaging format names its artifacts.
+func (c Config) UsesApplicationNameInFilename(packagingFormat string, defaultValue bool) bool {
+	name, ok := c.ArtifactNames[packagingFormat]
+	if !ok {
+		name, ok = c.ArtifactNames[strings.Split(packagingFormat, "-")[0]]
+	}
+	switch {
+	case !ok:
+		return defaultValue
+	case name == ArtifactNameApplication:
+		return true
+	case name == ArtifactNamePackage:
+		return false
+	}
+	log.Errorf("Unknown artifact-names value %s of %s in go/hover.yaml, use application-name or package-name.", name, packagingFormat)
+	os.Exit(1)
+	return false
+}
+
+// GetPostBuildSteps returns the ordered post-build steps of a packaging
+// format, set for the format (windows-msi) or for all formats of a platform
+// (windows). The second value is false when post-build doesn't set them.
+func (c Config) GetPostBuildSteps(packagingFormat string) ([]string, bool) {
+	if steps, ok := c.PostBuild[packagingFormat]; ok {
+		return steps, true
+	}
+	steps, ok := c.PostBuild[strings.Split(packagingFormat, "-")[0]]
+	return steps, ok
+}
+
 var config = Config{}
 
-// GetConfig returns the working directory hover.yaml as a Config
+// GetConfig returns the working directory hover.yaml as a Config. The values
+// of the hover section of pubspec.yaml take precedence over hover.yaml, so a
+// project can keep its configuration in pubspec.yaml only.
 func GetConfig() Config {
 	if !config.loaded {
 		c, err := ReadConfigFile(filepath.Join(build.BuildPath, "hover.yaml"))
 		if err != nil {
+			c = &Config{}
+		}
+		inPubspec, pubspecErr := readPubspecSection("pubspec.yaml", c)
+		if pubspecErr != nil {
+			log.Errorf("%v", pubspecErr)
+			os.Exit(1)
+		}
+		if err != nil && !inPubspec {
 			return config
 		}
+		if err == nil {
+			warnOldSchema(c.SchemaVersion)
+		}
 		config = *c
 		config.loaded = true
+		config.nightly = os.Getenv(NightlyEnv) == "true"
 	}
 	return config
 }
 
+// warnOldSchema warns when go/hover.yaml was written for another schema.
+func warnOldSchema(version int) {
+	if version == 0 {
+		version = 1
+	}
+	switch {
+	case version < SchemaVersion:
+		log.Warnf("go/hover.yaml has the schema %d of an older hover, run `%s` to update it to the schema %d.", version, log.Au().Magenta("hover config migrate"), SchemaVersion)
+	case version > SchemaVersion:
+		log.Warnf("go/hover.yaml has the schema %d of a newer hover, this hover only knows the schema %d: upgrade hover.", version, SchemaVersion)
+	}
+}
+
+// Files returns the configuration files read by GetConfig, the last one
+// takes precedence.
+func Files() []string {
+	var files []string
+	if _, err := os.Stat(filepath.Join(build.BuildPath, "hover.yaml")); err == nil {
+		files = append(files, filepath.Join(build.BuildPath, "hover.yaml"))
+	}
+	if inPubspec, _ := readPubspecSection("pubspec.yaml", &Config{}); inPubspec {
+		files = append(files, "pubspec.yaml")
+	}
+	return files
+}
+
+// readPubspecSection decodes the hover section of a pubspec.yaml file over a
+// Config. It has the same keys as hover.yaml. It returns false when there is
+// no hover section.
+func readPubspecSection(pubspecPath string, c *Config) (bool, error) {
+	content, err := ioutil.ReadFile(pubspecPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to read pubspec.yaml")
+	}
+	var pubspec struct {
+		Hover interface{}
+	}
+	err = yaml.Unmarshal(content, &pubspec)
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to decode pubspec.yaml")
+	}
+	if pubspec.Hover == nil {
+		return false, nil
+	}
+	section, err := yaml.Marshal(pubspec.Hover)
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to decode the hover section of pubspec.yaml")
+	}
+	err = yaml.Unmarshal(section, c)
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to decode the hover section of pubspec.yaml")
+	}
+	return true, nil
+}
+
 // ReadConfigFile reads a .yaml file at a path and return a correspond
 // Config struct
 func ReadConfigFile(configPath string) (*Config, error) {
@@ -105,3 +835,51 @@ func ReadConfigFile(configPath string) (*Config, error) {
 func PrintMissingField(name, file, def string) {
 	log.Warnf("Missing/Empty `%s` field in %s. Please add it or otherwise you may publish your app with a wrong %s. Continuing with `%s` as a placeholder %s.", name, file, name, def, name)
 }
+
+// SetValue sets a top-level string value in the content of a hover.yaml
+// file, keeping the comments and the layout. The key is uncommented when it
+// is commented out, and appended when it is missing.
+func SetValue(content []byte, key, value string) []byte {
+	quoted := strconv.Quote(value)
+	re := regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(key) + `:[ \t]*("[^"\n]*"|[^#\s]*)([ \t]+#.*)?$`)
+	if loc := re.FindSubmatchIndex(content); loc != nil {
+		var out []byte
+		out = append(out, content[:loc[2]]...)
+		// an empty value has no space after the colon
+		if content[loc[2]-1] == ':' {
+			out = append(out, ' ')
+		}
+		out = append(out, quoted...)
+		return append(out, content[loc[3]:]...)
+	}
+	re = regexp.MustCompile(`(?m)^#[ \t]*` + regexp.QuoteMeta(key) + `:.*$`)
+	if loc := re.FindIndex(content); loc != nil {
+		var out []byte
+		out = append(out, content[:loc[0]]...)
+		out = append(out, key+": "+quoted...)
+		return append(out, content[loc[1]:]...)
+	}
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		content = append(content, '\n')
+	}
+	return append(content, key+": "+quoted+"\n"...)
+}
+
+// SetPubspecValue sets a string value of the hover section of the content of
+// a pubspec.yaml file, keeping the comments and the layout. It returns false
+// when the key isn't set in the hover section.
+func SetPubspecValue(content []byte, key, value string) ([]byte, bool) {
+	section := regexp.MustCompile(`(?m)^hover:[ \t]*(#.*)?\n((?:[ \t]+.*\n|[ \t]*\n)*)`).FindSubmatchIndex(content)
+	if section == nil {
+		return content, false
+	}
+	re := regexp.MustCompile(`(?m)^[ \t]+` + regexp.QuoteMeta(key) + `:[ \t]*("[^"\n]*"|'[^'\n]*'|[^#\n]*[^#\s])([ \t]+#.*)?$`)
+	loc := re.FindSubmatchIndex(content[section[4]:section[5]])
+	if loc == nil {
+		return content, false
+	}
+	var out []byte
+	out = append(out, content[:section[4]+loc[2]]...)
+	out = append(out, strconv.Quote(value)...)
+	return append(out, content[section[4]+loc[3]:]...), true
+}
@@ -0,0 +1,61 @@
+package config
+
+import "testing"
+
+func TestSetValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		key     string
+		value   string
+		want    string
+	}{
+		{
+			"replaces a value, keeping the comment",
+			"license: MIT # the license\nengine-version: \"\"\n",
+			"license", "Apache-2.0",
+			"license: \"Apache-2.0\" # the license\nengine-version: \"\"\n",
+		},
+		{
+			"replaces a quoted value",
+			"application-name: \"Old name\"\n",
+			"application-name", "New name",
+			"application-name: \"New name\"\n",
+		},
+		{
+			"replaces an empty value",
+			"license:\nfoo: bar\n",
+			"license", "MIT",
+			"license: \"MIT\"\nfoo: bar\n",
+		},
+		{
+			"uncomments a commented out key",
+			"#application-name: \"{{.applicationName}}\" # Uncomment to modify this value.\n",
+			"application-name", "My App",
+			"application-name: \"My App\"\n",
+		},
+		{
+			"appends a missing key",
+			"license: MIT",
+			"package-name", "myapp",
+			"license: MIT\npackage-name: \"myapp\"\n",
+		},
+		{
+			"doesn't match a nested key",
+			"run:\n  license: x\n",
+			"license", "MIT",
+			"run:\n  license: x\nlicense: \"MIT\"\n",
+		},
+		{
+			"quotes the special characters",
+			"license: MIT\n",
+			"license", `a "b" # c`,
+			"license: \"a \\\"b\\\" # c\"\n",
+		},
+	}
+	for _, test := range tests {
+		if got := string(SetValue([]byte(test.content), test.key, test.value)); got != test.want {
+			t.Errorf("%s: SetValue() = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"io/ioutil"
+	"regexp"
+	"strconv"
+
+	"github.com/go-flutter-desktop/hover/internal/licenses"
+)
+
+// SchemaVersion is the version of the hover.yaml schema written by this
+// hover. A hover.yaml without schema-version has the first schema.
+const SchemaVersion = 2
+
+// migration rewrites a hover.yaml from the previous schema to a schema
+// version.
+type migration struct {
+	version  int
+	renamed  map[string]string // old key to new key
+	removed  []string
+	defaults func() map[string]string // values of the new required keys, set when missing or empty
+}
+
+var migrations = []migration{
+	{
+		version: 2,
+		// replaced by the --docker flag
+		removed: []string{"docker"},
+		defaults: func() map[string]string {
+			return map[string]string{"license": projectLicense()}
+		},
+	},
+}
+
+// projectLicenseFiles are the license files of the project whose SPDX
+// identifier is the default license.
+var projectLicenseFiles = []string{"LICENSE", "LICENSE.txt", "LICENSE.md", "COPYING"}
+
+// projectLicense returns the SPDX identifier of the license file of the
+// project, empty when it isn't recognized.
+func projectLicense() string {
+	for _, path := range projectLicenseFiles {
+		text, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if license := licenses.Detect(string(text)); license != licenses.Unknown {
+			return license
+		}
+		return ""
+	}
+	return ""
+}
+
+var schemaVersionPattern = regexp.MustCompile(`(?m)^schema-version:[ \t]*"?([0-9]+)"?[ \t]*(#.*)?$`)
+
+// FileSchemaVersion returns the schema version of the content of a
+// hover.yaml file.
+func FileSchemaVersion(content []byte) int {
+	match := schemaVersionPattern.FindSubmatch(content)
+	if match == nil {
+		return 1
+	}
+	version, _ := strconv.Atoi(string(match[1]))
+	return version
+}
+
+// Migrate rewrites the content of a hover.yaml file to the current schema,
+// keeping the comments and the layout: the renamed keys are renamed, the
+// removed keys are deleted and the new required keys are added.
+func Migrate(content []byte) []byte {
+	version := FileSchemaVersion(content)
+	if version >= SchemaVersion {
+		return content
+	}
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+		for oldKey, newKey := range m.renamed {
+			re := regexp.MustCompile(`(?m)^(#[ \t]*)?` + regexp.QuoteMeta(oldKey) + `:`)
+			content = re.ReplaceAll(content, []byte("${1}"+newKey+":"))
+		}
+		for _, key := range m.removed {
+			// the key, its nested values and its commented out line
+			re := regexp.MustCompile(`(?m)^(#[ \t]*)?` + regexp.QuoteMeta(key) + `:.*(?:\n|$)(?:[ \t]+.*\n)*`)
+			content = re.ReplaceAll(content, nil)
+		}
+		if m.defaults != nil {
+			for key, value := range m.defaults() {
+				isSet := regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(key) + `:`).Match(content)
+				isEmpty := regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(key) + `:[ \t]*(""|'')?[ \t]*(#.*)?$`).Match(content)
+				if !isSet || (isEmpty && value != "") {
+					content = SetValue(content, key, value)
+				}
+			}
+		}
+	}
+	stamp := "schema-version: " + strconv.Itoa(SchemaVersion) + " # Written by hover, updated by `hover config migrate`"
+	if loc := schemaVersionPattern.FindIndex(content); loc != nil {
+		var out []byte
+		out = append(out, content[:loc[0]]...)
+		out = append(out, stamp...)
+		return append(out, content[loc[1]:]...)
+	}
+	return append([]byte(stamp+"\n"), content...)
+}
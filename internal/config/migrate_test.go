@@ -0,0 +1,60 @@
+package config
+
+import "testing"
+
+func TestMigrate(t *testing.T) {
+	stamp := "schema-version: 2 # Written by hover, updated by `hover config migrate`"
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			"removes docker and adds the license",
+			"docker: true\ntarget: lib/main_desktop.dart\n",
+			stamp + "\ntarget: lib/main_desktop.dart\nlicense: \"\"\n",
+		},
+		{
+			"removes the commented out docker",
+			"#docker: false # Uncomment to build in docker\nlicense: MIT\n",
+			stamp + "\nlicense: MIT\n",
+		},
+		{
+			"keeps the license and the comments",
+			"# the license of the app\nlicense: Apache-2.0 # SPDX\n",
+			stamp + "\n# the license of the app\nlicense: Apache-2.0 # SPDX\n",
+		},
+		{
+			"updates the schema-version in place",
+			"license: MIT\nschema-version: 1\ntarget: lib/main.dart\n",
+			"license: MIT\n" + stamp + "\ntarget: lib/main.dart\n",
+		},
+		{
+			"leaves the current schema unchanged",
+			"schema-version: 2\ndocker: true\n",
+			"schema-version: 2\ndocker: true\n",
+		},
+	}
+	for _, test := range tests {
+		if got := string(Migrate([]byte(test.content))); got != test.want {
+			t.Errorf("%s: Migrate() = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestFileSchemaVersion(t *testing.T) {
+	tests := []struct {
+		content string
+		want    int
+	}{
+		{"license: MIT\n", 1},
+		{"schema-version: 2\n", 2},
+		{"license: MIT\nschema-version: \"3\" # comment\n", 3},
+		{"#schema-version: 2\n", 1},
+	}
+	for _, test := range tests {
+		if got := FileSchemaVersion([]byte(test.content)); got != test.want {
+			t.Errorf("FileSchemaVersion(%q) = %d, want %d", test.content, got, test.want)
+		}
+	}
+}
@@ -8,14 +8,17 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/explain"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
 	"github.com/go-flutter-desktop/hover/internal/flutterversion"
 	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/retry"
 )
 
 func createSymLink(oldname, newname string) error {
@@ -106,7 +109,10 @@ func printDownloadPercent(done chan chan struct{}, path string, expectedSize int
 		var percent = float64(size) / float64(expectedSize) * 100
 
 		// We use '\033[2K\r' to avoid carriage return, it will print above previous.
-		fmt.Printf("\033[2K\r %.0f %% / 100 %%", percent)
+		// Escape codes are useless noise when the output isn't a terminal.
+		if log.IsTerminal() {
+			fmt.Printf("\033[2K\r %.0f %% / 100 %%", percent)
+		}
 
 		if completedCh != nil {
 			close(completedCh)
@@ -164,39 +170,159 @@ func downloadFile(filepath string, url string) error {
 	}
 	defer out.Close()
 
-	// Get the data
-	resp, err := http.Get(url)
+	err = retry.Do("Downloading "+url, func() error {
+		// Get the data, from where the previous attempt stopped
+		resp, offset, err := getResumed(url, out)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.ContentLength < 0 {
+			return errors.New("failed to get Content-Length header")
+		}
+
+		doneCh := make(chan chan struct{})
+		go printDownloadPercent(doneCh, filepath, offset+resp.ContentLength)
+		defer func() {
+			// close channel to indicate we're done
+			doneCompletedCh := make(chan struct{})
+			doneCh <- doneCompletedCh // signal that download is done
+			<-doneCompletedCh         // wait for signal that printing has completed
+			if log.IsTerminal() {
+				fmt.Print("\033[2K\r")
+			}
+		}()
+
+		_, err = io.Copy(out, resp.Body)
+		return err
+	})
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
-	expectedSize, err := strconv.Atoi(resp.Header.Get("Content-Length"))
+	elapsed := time.Since(start)
+	log.Printf("Download completed in %.2fs", elapsed.Seconds())
+	return nil
+}
+
+// getResumed requests a file from the end of its partial download in out,
+// left by a failed attempt. The partial download is discarded when the
+// server doesn't support ranges. The body of the response is the rest of the
+// file, starting at the returned offset.
+func getResumed(url string, out *os.File) (*http.Response, int64, error) {
+	offset, err := out.Seek(0, io.SeekEnd)
 	if err != nil {
-		return errors.Wrap(err, "failed to get Content-Length header")
+		return nil, 0, err
 	}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, retry.Permanent(err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	switch {
+	case offset > 0 && resp.StatusCode == http.StatusPartialContent && strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)):
+		return resp, offset, nil
+	case resp.StatusCode == http.StatusOK:
+		// the server ignored the range, the download starts over
+		err = restartDownload(out)
+		if err != nil {
+			resp.Body.Close()
+			return nil, 0, err
+		}
+		return resp, 0, nil
+	case offset > 0 && (resp.StatusCode == http.StatusPartialContent || resp.StatusCode == http.StatusRequestedRangeNotSatisfiable):
+		// the next attempt starts over
+		resp.Body.Close()
+		err = restartDownload(out)
+		if err != nil {
+			return nil, 0, err
+		}
+		return nil, 0, errors.Errorf("the partial download of %s can't be resumed: %s", url, resp.Status)
+	}
+	resp.Body.Close()
+	err = errors.Errorf("failed to download %s: %s", url, resp.Status)
+	if !retry.IsTransientStatus(resp.StatusCode) {
+		return nil, 0, retry.Permanent(err)
+	}
+	return nil, 0, err
+}
 
-	doneCh := make(chan chan struct{})
-	go printDownloadPercent(doneCh, filepath, int64(expectedSize))
-
-	_, err = io.Copy(out, resp.Body)
+// restartDownload discards the partial download of a file.
+func restartDownload(out *os.File) error {
+	err := out.Truncate(0)
 	if err != nil {
 		return err
 	}
+	_, err = out.Seek(0, io.SeekStart)
+	return err
+}
 
-	// close channel to indicate we're done
-	doneCompletedCh := make(chan struct{})
-	doneCh <- doneCompletedCh // signal that download is done
-	<-doneCompletedCh         // wait for signal that printing has completed
+// releaseEngineBuildsURL is where the release engines, running the dart code
+// compiled ahead-of-time, are downloaded from. Flutter only publishes the
+// debug engines of the embedder API. It can be changed with
+// HOVER_RELEASE_ENGINE_BASE_URL.
+const releaseEngineBuildsURL = "https://github.com/flutter-rs/engine-builds/releases/download"
+
+// releaseEngineChecksums are the SHA-256 of the release engine archives, by
+// engine version and target OS.
+var releaseEngineChecksums map[string]map[string]string
+
+// SetReleaseEngineChecksums sets the SHA-256 the downloaded release engine
+// archives are verified against, by engine version and target OS. The
+// release engines aren't published by flutter, they are only used when their
+// checksum is pinned.
+func SetReleaseEngineChecksums(checksums map[string]map[string]string) {
+	releaseEngineChecksums = checksums
+}
 
-	elapsed := time.Since(start)
-	log.Printf("\033[2K\rDownload completed in %.2fs", elapsed.Seconds())
-	return nil
+// releaseEngineChecksum returns the pinned SHA-256 of the release engine
+// archive of a target OS at an engine version.
+func releaseEngineChecksum(targetOS, engineVersion string) (string, error) {
+	checksum := strings.ToLower(strings.TrimSpace(releaseEngineChecksums[engineVersion][targetOS]))
+	if checksum == "" {
+		return "", errors.Errorf("the SHA-256 of the %s release engine at version %s isn't pinned, set it in release-engine-sha256 of go/hover.yaml: the release engines are built by a third party, they aren't run unverified", targetOS, engineVersion)
+	}
+	return checksum, nil
 }
 
+// EngineCachePath returns the path of the engine of a target OS for the target
+// architecture. The amd64 engines keep the directory of the target OS. The
+// release engines of the AOT builds are kept next to the debug engines.
 //noinspection GoNameStartsWithPackageName
 func EngineCachePath(targetOS, cachePath string) string {
-	return filepath.Join(cachePath, "hover", "engine", targetOS)
+	return currentEngine(targetOS).CachePath(cachePath)
+}
+
+// GenSnapshotPath returns the path of gen_snapshot in the cache of a release
+// engine, compiling the dart code ahead-of-time for the engine.
+func GenSnapshotPath(engineCachePath, targetOS string) string {
+	return filepath.Join(engineCachePath, build.OutputBinary("gen_snapshot", targetOS))
+}
+
+// PlatformArch returns an architecture in the names of the flutter engine
+// downloads and artifacts, e.g. x64 for amd64.
+func PlatformArch(arch string) string {
+	if arch == "amd64" {
+		return "x64"
+	}
+	return arch
+}
+
+// CachedEngineVersion returns the version of the engine in an engine cache
+// path, empty when there is no engine.
+func CachedEngineVersion(engineCachePath string) string {
+	cachedEngineVersionBytes, err := ioutil.ReadFile(filepath.Join(engineCachePath, "version"))
+	if err != nil {
+		return ""
+	}
+	return string(cachedEngineVersionBytes)
 }
 
 // ValidateOrUpdateEngineAtPath validates the engine we have cached matches the
@@ -206,18 +332,57 @@ func ValidateOrUpdateEngineAtPath(targetOS, cachePath, requiredEngineVersion str
 	engineCachePath = EngineCachePath(targetOS, cachePath)
 
 	if strings.Contains(engineCachePath, " ") {
-		log.Errorf("Cannot save the engine to '%s', engine cache is not compatible with path containing spaces.", cachePath)
-		log.Errorf("       Please run hover with a another engine cache path. Example:")
-		log.Errorf("              %s", log.Au().Magenta("hover run --cache-path \"C:\\cache\""))
-		log.Errorf("       The --cache-path flag will have to be provided to every build and run command.")
+		log.ErrorCodef(explain.EngineCachePathSpaces, "Cannot save the engine to '%s', engine cache is not compatible with path containing spaces.\n       Please run hover with a another engine cache path. Example:\n              %s\n       The --cache-path flag will have to be provided to every build and run command.", cachePath, log.Au().Magenta("hover run --cache-path \"C:\\cache\""))
 		os.Exit(1)
 	}
 
+	engineCachePath, err := UpdateEngine(targetOS, cachePath, requiredEngineVersion)
+	if err != nil {
+		log.ErrorCodef(explain.EngineDownloadFailed, "%v", err)
+		os.Exit(1)
+	}
+	return engineCachePath
+}
+
+// UpdateEngine downloads the engine to the engine cache, unless the cached
+// engine already matches the required version, which defaults to the engine
+// version of flutter.
+func UpdateEngine(targetOS, cachePath, requiredEngineVersion string) (engineCachePath string, err error) {
+	return updateEngine(currentEngine(targetOS), cachePath, requiredEngineVersion, serialDownloader{})
+}
+
+// downloader downloads the files of the engines, and prints the messages of
+// the engine updates.
+type downloader interface {
+	printf(format string, args ...interface{})
+	warnf(format string, args ...interface{})
+	download(path, url, name string) error
+}
+
+// serialDownloader downloads the files one at a time, printing the progress
+// of the current download.
+type serialDownloader struct{}
+
+func (serialDownloader) printf(format string, args ...interface{}) {
+	log.Printf(format, args...)
+}
+
+func (serialDownloader) warnf(format string, args ...interface{}) {
+	log.Warnf(format, args...)
+}
+
+func (serialDownloader) download(path, url, name string) error {
+	return downloadFile(path, url)
+}
+
+func updateEngine(engine Engine, cachePath, requiredEngineVersion string, d downloader) (engineCachePath string, err error) {
+	targetOS := engine.OS
+	engineCachePath = engine.CachePath(cachePath)
+
 	cachedEngineVersionPath := filepath.Join(engineCachePath, "version")
 	cachedEngineVersionBytes, err := ioutil.ReadFile(cachedEngineVersionPath)
 	if err != nil && !os.IsNotExist(err) {
-		log.Errorf("Failed to read cached engine version: %v", err)
-		os.Exit(1)
+		return "", errors.Wrap(err, "failed to read cached engine version")
 	}
 	cachedEngineVersion := string(cachedEngineVersionBytes)
 	if len(requiredEngineVersion) == 0 {
@@ -226,23 +391,21 @@ func ValidateOrUpdateEngineAtPath(targetOS, cachePath, requiredEngineVersion str
 
 	if cachedEngineVersion != "" {
 		if cachedEngineVersion == requiredEngineVersion {
-			log.Printf("Using engine from cache")
-			return engineCachePath
+			d.printf("Using engine from cache")
+			return engineCachePath, nil
 		}
 
 		// Engine is outdated, we remove the old engine and continue to download
 		// the new engine.
 		err = os.RemoveAll(engineCachePath)
 		if err != nil {
-			log.Errorf("Failed to remove outdated engine: %v", err)
-			os.Exit(1)
+			return "", errors.Wrap(err, "failed to remove outdated engine")
 		}
 	}
 
 	err = os.MkdirAll(engineCachePath, 0775)
 	if err != nil {
-		log.Errorf("Failed to create engine cache directory: %v", err)
-		os.Exit(1)
+		return "", errors.Wrap(err, "failed to create engine cache directory")
 	}
 
 	targetedDomain := "https://storage.googleapis.com"
@@ -251,8 +414,7 @@ func ValidateOrUpdateEngineAtPath(targetOS, cachePath, requiredEngineVersion str
 		targetedDomain = envURLFlutter
 	}
 
-	// TODO: support more arch's than x64?
-	var platform = targetOS + "-x64"
+	var platform = targetOS + "-" + PlatformArch(engine.Arch)
 
 	// Build the URL for downloading the correct engine
 	var engineDownloadURL = fmt.Sprintf(targetedDomain+"/flutter_infra/flutter/%s/%s/", requiredEngineVersion, platform)
@@ -263,64 +425,89 @@ func ValidateOrUpdateEngineAtPath(targetOS, cachePath, requiredEngineVersion str
 		engineDownloadURL += platform + "-embedder"
 	case "windows":
 		engineDownloadURL += platform + "-embedder.zip"
+	case "freebsd":
+		return "", errors.Errorf("flutter doesn't publish the engine for freebsd, build it and use it with --local-engine or local-engine in go/hover.yaml")
 	default:
-		log.Errorf("Cannot run on %s, download engine not implemented.", targetOS)
-		os.Exit(1)
+		return "", errors.Errorf("cannot run on %s, download engine not implemented", targetOS)
+	}
+	var releaseChecksum string
+	if engine.AOT {
+		if engine.Arch != "amd64" {
+			return "", errors.Errorf("the release engines are only available for amd64")
+		}
+		releaseDomain := releaseEngineBuildsURL
+		if envURLRelease := os.Getenv("HOVER_RELEASE_ENGINE_BASE_URL"); envURLRelease != "" {
+			releaseDomain = envURLRelease
+		}
+		engineDownloadURL = fmt.Sprintf("%s/f-%s/%s_x64-host_release.zip", releaseDomain, requiredEngineVersion, targetOS)
+		releaseChecksum, err = releaseEngineChecksum(targetOS, requiredEngineVersion)
+		if err != nil {
+			return "", err
+		}
 	}
 
 	icudtlDownloadURL := fmt.Sprintf(targetedDomain+"/flutter_infra/flutter/%s/%s/artifacts.zip", requiredEngineVersion, platform)
 
 	dir, err := ioutil.TempDir("", "hover-engine-download")
 	if err != nil {
-		log.Errorf("Failed to create tmp dir for engine download: %v", err)
-		os.Exit(1)
+		return "", errors.Wrap(err, "failed to create tmp dir for engine download")
 	}
 	defer os.RemoveAll(dir)
 
 	err = os.MkdirAll(dir, 0700)
 	if err != nil {
-		log.Warnf("%v", err)
+		d.warnf("%v", err)
 	}
 
 	engineZipPath := filepath.Join(dir, "engine.zip")
 	engineExtractPath := filepath.Join(dir, "engine")
 	artifactsZipPath := filepath.Join(dir, "artifacts.zip")
 
-	log.Printf("Downloading engine for platform %s at version %s...", platform, requiredEngineVersion)
-	err = downloadFile(engineZipPath, engineDownloadURL)
+	if engine.AOT {
+		d.printf("Downloading release engine for platform %s at version %s...", platform, requiredEngineVersion)
+	} else {
+		d.printf("Downloading engine for platform %s at version %s...", platform, requiredEngineVersion)
+	}
+	err = d.download(engineZipPath, engineDownloadURL, engine.String())
 	if err != nil {
-		log.Errorf("Failed to download engine: %v", err)
-		os.Exit(1)
+		return "", errors.Wrap(err, "failed to download engine")
+	}
+	if releaseChecksum != "" {
+		checksum, err := fileutils.SHA256File(engineZipPath)
+		if err != nil {
+			return "", err
+		}
+		if checksum != releaseChecksum {
+			return "", errors.Errorf("the SHA-256 of the %s release engine downloaded from %s is %s, not the %s of release-engine-sha256 in go/hover.yaml", targetOS, engineDownloadURL, checksum, releaseChecksum)
+		}
 	}
 
 	// TODO, optimization: make artifacts download a separate function, it doesn't need to be
 	// downloaded with engine because it's OS independent.
-	log.Printf("Downloading artifacts at version %s...", requiredEngineVersion)
-	err = downloadFile(artifactsZipPath, icudtlDownloadURL)
+	d.printf("Downloading artifacts at version %s...", requiredEngineVersion)
+	err = d.download(artifactsZipPath, icudtlDownloadURL, engine.String()+" artifacts")
 	if err != nil {
-		log.Errorf("Failed to download artifacts: %v", err)
-		os.Exit(1)
+		return "", errors.Wrap(err, "failed to download artifacts")
 	}
 
 	_, err = unzip(engineZipPath, engineExtractPath) // engineCachePath)
 	if err != nil {
-		log.Warnf("%v", err)
+		d.warnf("%v", err)
 	}
 
 	artifactsCachePath := filepath.Join(engineCachePath, "artifacts")
 	_, err = unzip(artifactsZipPath, artifactsCachePath) // filepath.Join(engineCachePath, "artifacts"))
 	if err != nil {
-		log.Warnf("%v", err)
+		d.warnf("%v", err)
 	}
 
-	switch platform {
-	case "darwin-x64":
+	switch targetOS {
+	case "darwin":
 		frameworkZipPath := filepath.Join(engineExtractPath, "FlutterEmbedder.framework.zip")
 		frameworkDestPath := filepath.Join(engineCachePath, "FlutterEmbedder.framework")
 		_, err = unzip(frameworkZipPath, frameworkDestPath)
 		if err != nil {
-			log.Errorf("Failed to unzip engine framework: %v", err)
-			os.Exit(1)
+			return "", errors.Wrap(err, "failed to unzip engine framework")
 		}
 
 		createSymLink("A", frameworkDestPath+"/Versions/Current")
@@ -329,34 +516,45 @@ func ValidateOrUpdateEngineAtPath(targetOS, cachePath, requiredEngineVersion str
 		createSymLink("Versions/Current/Modules", frameworkDestPath+"/Modules")
 		createSymLink("Versions/Current/Resources", frameworkDestPath+"/Resources")
 
-	case "linux-x64":
+	case "linux":
 		err := moveFile(
 			filepath.Join(engineExtractPath, "libflutter_engine.so"),
 			filepath.Join(engineCachePath, "/libflutter_engine.so"),
 		)
 		if err != nil {
-			log.Errorf("Failed to move downloaded libflutter_engine.so: %v", err)
-			os.Exit(1)
+			return "", errors.Wrap(err, "failed to move downloaded libflutter_engine.so")
 		}
 
-	case "windows-x64":
+	case "windows":
 		err := moveFile(
 			filepath.Join(engineExtractPath, "flutter_engine.dll"),
 			filepath.Join(engineCachePath, "/flutter_engine.dll"),
 		)
 		if err != nil {
-			log.Errorf("Failed to move downloaded flutter_engine.dll: %v", err)
-			os.Exit(1)
+			return "", errors.Wrap(err, "failed to move downloaded flutter_engine.dll")
+		}
+	}
+
+	if engine.AOT {
+		err := moveFile(
+			filepath.Join(engineExtractPath, build.OutputBinary("gen_snapshot", targetOS)),
+			GenSnapshotPath(engineCachePath, targetOS),
+		)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to move downloaded gen_snapshot")
+		}
+		err = os.Chmod(GenSnapshotPath(engineCachePath, targetOS), 0755)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to make gen_snapshot executable")
 		}
 	}
 
 	err = ioutil.WriteFile(cachedEngineVersionPath, []byte(requiredEngineVersion), 0664)
 	if err != nil {
-		log.Errorf("Failed to write version file: %v", err)
-		os.Exit(1)
+		return "", errors.Wrap(err, "failed to write version file")
 	}
 
-	return engineCachePath
+	return engineCachePath, nil
 }
 
 // ValidateOrUpdateEngine validates the engine we have cached matches the
@@ -0,0 +1,117 @@
+package enginecache
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+	"github.com/go-flutter-desktop/hover/internal/flutterversion"
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// UseLocalEngine copies an engine built from a checkout of the flutter engine
+// (the out/host_release directory for example) to its own directory of the
+// engine cache, the downloaded engine is left as is for the other builds.
+// The engine must be built from the engine version required by flutter, or
+// from a commit based on it.
+func UseLocalEngine(targetOS, cachePath, localEnginePath, requiredEngineVersion string) (engineCachePath string) {
+	localEnginePath, err := filepath.Abs(localEnginePath)
+	if err != nil {
+		log.Errorf("Failed to resolve absolute path for the local engine: %v", err)
+		os.Exit(1)
+	}
+	engineFile := filepath.Join(localEnginePath, build.EngineFilename(targetOS))
+	if _, err := os.Stat(engineFile); err != nil {
+		log.Errorf("The local engine %s doesn't contain %s, is it built for %s?", localEnginePath, build.EngineFilename(targetOS), targetOS)
+		os.Exit(1)
+	}
+	icudtlFile := filepath.Join(localEnginePath, "icudtl.dat")
+	if !fileutils.IsFileExists(icudtlFile) {
+		log.Errorf("The local engine %s doesn't contain icudtl.dat", localEnginePath)
+		os.Exit(1)
+	}
+
+	if len(requiredEngineVersion) == 0 {
+		requiredEngineVersion = flutterversion.FlutterRequiredEngineVersion()
+	}
+	// out/host_release is in the src directory of the engine checkout, next
+	// to the flutter repository.
+	engineRepositoryPath := filepath.Join(localEnginePath, "..", "..", "flutter")
+	localEngineVersion, err := gitOutput(engineRepositoryPath, "rev-parse", "HEAD")
+	if err != nil {
+		log.Warnf("Cannot check the version of the local engine, %s is not a git repository: %v", engineRepositoryPath, err)
+		localEngineVersion = "unknown"
+	} else if localEngineVersion != requiredEngineVersion {
+		_, err = gitOutput(engineRepositoryPath, "merge-base", "--is-ancestor", requiredEngineVersion, "HEAD")
+		if err != nil {
+			log.Errorf("The local engine is built from %s, which isn't based on the engine version %s required by flutter.", localEngineVersion, requiredEngineVersion)
+			log.Errorf("       Checkout the engine at %s and rebuild it, or set the `engine-version` in go/hover.yaml.", requiredEngineVersion)
+			os.Exit(1)
+		}
+		log.Printf("Using local engine built from %s, based on %s", localEngineVersion, requiredEngineVersion)
+	}
+
+	engine := currentEngine(targetOS)
+	engine.Local = true
+	engineCachePath = engine.CachePath(cachePath)
+	err = os.RemoveAll(engineCachePath)
+	if err != nil {
+		log.Errorf("Failed to remove the previous local engine: %v", err)
+		os.Exit(1)
+	}
+	err = os.MkdirAll(filepath.Join(engineCachePath, "artifacts"), 0775)
+	if err != nil {
+		log.Errorf("Failed to create engine cache directory: %v", err)
+		os.Exit(1)
+	}
+	if fileutils.IsDirectory(engineFile) {
+		fileutils.CopyDir(engineFile, filepath.Join(engineCachePath, build.EngineFilename(targetOS)))
+	} else {
+		fileutils.CopyFile(engineFile, filepath.Join(engineCachePath, build.EngineFilename(targetOS)))
+	}
+	fileutils.CopyFile(icudtlFile, filepath.Join(engineCachePath, "artifacts", "icudtl.dat"))
+	if build.AOT() {
+		// gen_snapshot is built for the host, in a toolchain directory of
+		// the output such as clang_x64.
+		genSnapshotFiles, _ := filepath.Glob(filepath.Join(localEnginePath, "*", build.OutputBinary("gen_snapshot", targetOS)))
+		genSnapshotFiles = append([]string{filepath.Join(localEnginePath, build.OutputBinary("gen_snapshot", targetOS))}, genSnapshotFiles...)
+		found := false
+		for _, genSnapshotFile := range genSnapshotFiles {
+			if fileutils.IsFileExists(genSnapshotFile) {
+				fileutils.CopyFile(genSnapshotFile, GenSnapshotPath(engineCachePath, targetOS))
+				err = os.Chmod(GenSnapshotPath(engineCachePath, targetOS), 0755)
+				if err != nil {
+					log.Errorf("Failed to make gen_snapshot executable: %v", err)
+					os.Exit(1)
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			log.Errorf("The local engine %s doesn't contain gen_snapshot, is it a release build?", localEnginePath)
+			os.Exit(1)
+		}
+	}
+
+	// the version tells the local engine apart in the build info and the
+	// provenance
+	err = ioutil.WriteFile(filepath.Join(engineCachePath, "version"), []byte("local "+localEngineVersion+" "+localEnginePath), 0664)
+	if err != nil {
+		log.Errorf("Failed to write version file: %v", err)
+		os.Exit(1)
+	}
+	log.Printf("Using local engine %s", localEnginePath)
+	return engineCachePath
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command(build.GitBin(), args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
@@ -4,7 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 
+	"github.com/go-flutter-desktop/hover/internal/hostenv"
 	"github.com/go-flutter-desktop/hover/internal/log"
 )
 
@@ -18,8 +20,13 @@ func DefaultCachePath() string {
 
 	var p string
 	switch runtime.GOOS {
-	case "linux":
+	case "linux", "freebsd":
 		p = filepath.Join(homePath, ".cache")
+		// the home directory can be on a drive of the windows host in WSL,
+		// which is slow and loses the permissions of the engine files
+		if hostenv.IsWindowsFilesystem(p) {
+			p = filepath.Join("/var/tmp", "hover-cache-"+strconv.Itoa(os.Getuid()))
+		}
 	case "darwin":
 		p = filepath.Join(homePath, "Library", "Caches")
 	case "windows":
@@ -30,3 +37,9 @@ func DefaultCachePath() string {
 	}
 	return p
 }
+
+// EnginesPath returns the directory containing the engines of every target
+// OS in a cache path.
+func EnginesPath(cachePath string) string {
+	return filepath.Join(cachePath, "hover", "engine")
+}
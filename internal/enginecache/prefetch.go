@@ -0,0 +1,287 @@
+package enginecache
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/flutterversion"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/retry"
+)
+
+// Engine is an engine of the cache: the engine of a target OS and
+// architecture, or its release engine for the AOT builds. A local engine is
+// a locally built engine, staged apart from the downloaded engines.
+type Engine struct {
+	OS    string
+	Arch  string
+	AOT   bool
+	Local bool
+}
+
+// currentEngine returns the engine of a target OS for the target
+// architecture of the build.
+func currentEngine(targetOS string) Engine {
+	return Engine{OS: targetOS, Arch: build.TargetArch(), AOT: build.AOT()}
+}
+
+// String returns the name of the engine, e.g. linux-arm64 or
+// windows-amd64-release.
+func (e Engine) String() string {
+	name := e.OS + "-" + e.Arch
+	if e.AOT {
+		name += "-release"
+	}
+	return name
+}
+
+// CachePath returns the path of the engine in a cache path. The amd64
+// engines keep the directory of the target OS. The release engines and the
+// local engines are kept next to the downloaded debug engines.
+func (e Engine) CachePath(cachePath string) string {
+	name := e.OS
+	if e.Arch != build.DefaultTargetArch {
+		name += "-" + e.Arch
+	}
+	if e.AOT {
+		name += "-release"
+	}
+	if e.Local {
+		name += "-local"
+	}
+	return filepath.Join(EnginesPath(cachePath), name)
+}
+
+// Prefetch downloads the engines that aren't cached at the required version,
+// at most jobs at a time. The downloads share the rate limit, in bytes per
+// second (0 for none), and their progress is printed on a single line.
+func Prefetch(engines []Engine, cachePath, requiredEngineVersion string, jobs int, rateLimit int64) error {
+	if len(requiredEngineVersion) == 0 {
+		requiredEngineVersion = flutterversion.FlutterRequiredEngineVersion()
+	}
+	if jobs < 1 {
+		jobs = 1
+	}
+	p := newProgress(rateLimit)
+	go p.run()
+
+	errs := make([]error, len(engines))
+	slots := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	for i, engine := range engines {
+		wg.Add(1)
+		go func(i int, engine Engine) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			_, errs[i] = updateEngine(engine, cachePath, requiredEngineVersion, &engineProgress{p, engine})
+		}(i, engine)
+	}
+	wg.Wait()
+	p.stop()
+
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", engines[i], err))
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("failed to prefetch the engines:\n  %s", strings.Join(failed, "\n  "))
+	}
+	return nil
+}
+
+// progress prints the progress of the concurrent downloads on a single line,
+// below their messages.
+type progress struct {
+	mu        sync.Mutex
+	downloads []*download
+	limiter   *rateLimiter
+	done      chan struct{}
+	stopped   chan struct{}
+}
+
+type download struct {
+	name string
+	size int64 // -1 when unknown
+	read int64 // Accessed atomically
+}
+
+func newProgress(rateLimit int64) *progress {
+	p := &progress{
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	if rateLimit > 0 {
+		p.limiter = &rateLimiter{rate: rateLimit}
+	}
+	return p
+}
+
+func (p *progress) run() {
+	defer close(p.stopped)
+	ticker := time.NewTicker(time.Second / 10)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.mu.Lock()
+			p.redraw()
+			p.mu.Unlock()
+		case <-p.done:
+			return
+		}
+	}
+}
+
+func (p *progress) stop() {
+	close(p.done)
+	<-p.stopped
+	p.clear()
+}
+
+// clear erases the progress line, the escape codes are useless noise when
+// the output isn't a terminal.
+func (p *progress) clear() {
+	if log.IsTerminal() {
+		fmt.Print("\033[2K\r")
+	}
+}
+
+func (p *progress) redraw() {
+	if !log.IsTerminal() || len(p.downloads) == 0 {
+		return
+	}
+	var parts []string
+	for _, d := range p.downloads {
+		read := atomic.LoadInt64(&d.read)
+		if d.size > 0 {
+			parts = append(parts, fmt.Sprintf("%s %.0f %%", d.name, float64(read)/float64(d.size)*100))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s %.1f MB", d.name, float64(read)/1e6))
+		}
+	}
+	fmt.Print("\033[2K\r " + strings.Join(parts, " | "))
+}
+
+func (p *progress) logf(logf func(format string, args ...interface{}), format string, args ...interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+	logf(format, args...)
+	p.redraw()
+}
+
+func (p *progress) download(path, url, name string) error {
+	start := time.Now()
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	d := &download{name: name, size: -1}
+	p.mu.Lock()
+	p.downloads = append(p.downloads, d)
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		for i := range p.downloads {
+			if p.downloads[i] == d {
+				p.downloads = append(p.downloads[:i], p.downloads[i+1:]...)
+				break
+			}
+		}
+		p.mu.Unlock()
+	}()
+
+	warnf := func(format string, args ...interface{}) {
+		p.logf(log.Warnf, format, args...)
+	}
+	err = retry.DoLogf(warnf, "Downloading "+name, func() error {
+		resp, offset, err := getResumed(url, out)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		p.mu.Lock()
+		d.size = -1
+		if resp.ContentLength >= 0 {
+			d.size = offset + resp.ContentLength
+		}
+		atomic.StoreInt64(&d.read, offset)
+		p.mu.Unlock()
+
+		_, err = io.Copy(out, &progressReader{resp.Body, d, p.limiter})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	p.logf(log.Printf, "Downloaded %s in %.2fs", name, time.Since(start).Seconds())
+	return nil
+}
+
+// engineProgress is the downloader of an engine prefetched with others, its
+// messages are prefixed with the name of the engine.
+type engineProgress struct {
+	*progress
+	engine Engine
+}
+
+func (e *engineProgress) printf(format string, args ...interface{}) {
+	e.logf(log.Printf, "%s: %s", e.engine, fmt.Sprintf(format, args...))
+}
+
+func (e *engineProgress) warnf(format string, args ...interface{}) {
+	e.logf(log.Warnf, "%s: %s", e.engine, fmt.Sprintf(format, args...))
+}
+
+// progressReader counts the bytes read by a download, and waits for the rate
+// limit.
+type progressReader struct {
+	r       io.Reader
+	d       *download
+	limiter *rateLimiter
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	atomic.AddInt64(&r.d.read, int64(n))
+	r.limiter.wait(n)
+	return n, err
+}
+
+// rateLimiter limits the combined throughput of the downloads sharing it.
+type rateLimiter struct {
+	mu   sync.Mutex
+	rate int64     // Bytes per second
+	next time.Time // When the bytes read so far are within the rate
+}
+
+// wait waits until n more bytes are within the rate, a nil rateLimiter never
+// waits.
+func (l *rateLimiter) wait(n int) {
+	if l == nil || n <= 0 {
+		return
+	}
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	l.next = l.next.Add(time.Duration(int64(n) * int64(time.Second) / l.rate))
+	delay := l.next.Sub(now)
+	l.mu.Unlock()
+	time.Sleep(delay)
+}
@@ -0,0 +1,87 @@
+package enginecache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// KnownProject is a project that was built using the engine cache, with the
+// engine versions it uses by engine directory, the target OS with the
+// architecture suffix of the non-amd64 engines. hover cache gc keeps the
+// engines used by the known projects.
+type KnownProject struct {
+	Path    string
+	Engines map[string]string
+}
+
+func knownProjectsPath(cachePath string) string {
+	return filepath.Join(cachePath, "hover", "projects.yaml")
+}
+
+// KnownProjects returns the projects that were built using the engine cache
+// of a cache path.
+func KnownProjects(cachePath string) ([]KnownProject, error) {
+	content, err := ioutil.ReadFile(knownProjectsPath(cachePath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the known projects")
+	}
+	var projects []KnownProject
+	err = yaml.Unmarshal(content, &projects)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode the known projects")
+	}
+	return projects, nil
+}
+
+// SaveKnownProjects replaces the known projects of a cache path.
+func SaveKnownProjects(cachePath string, projects []KnownProject) error {
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Path < projects[j].Path })
+	content, err := yaml.Marshal(projects)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the known projects")
+	}
+	err = os.MkdirAll(filepath.Dir(knownProjectsPath(cachePath)), 0755)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the cache directory")
+	}
+	err = ioutil.WriteFile(knownProjectsPath(cachePath), content, 0644)
+	if err != nil {
+		return errors.Wrap(err, "failed to write the known projects")
+	}
+	return nil
+}
+
+// RecordProject records the engine version a project uses for an engine
+// directory of the cache.
+func RecordProject(cachePath, projectPath, engineName, engineVersion string) error {
+	projects, err := KnownProjects(cachePath)
+	if err != nil {
+		return err
+	}
+	for i := range projects {
+		if projects[i].Path != projectPath {
+			continue
+		}
+		if projects[i].Engines[engineName] == engineVersion {
+			return nil
+		}
+		if projects[i].Engines == nil {
+			projects[i].Engines = make(map[string]string)
+		}
+		projects[i].Engines[engineName] = engineVersion
+		return SaveKnownProjects(cachePath, projects)
+	}
+	projects = append(projects, KnownProject{
+		Path:    projectPath,
+		Engines: map[string]string{engineName: engineVersion},
+	})
+	return SaveKnownProjects(cachePath, projects)
+}
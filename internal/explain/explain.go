@@ -0,0 +1,337 @@
+// Package explain contains the extended descriptions of the errors of hover,
+// printed offline by `hover explain <code>`.
+package explain
+
+import (
+	"sort"
+	"strings"
+)
+
+// The codes of the errors of hover. The first digits group them: 01 is the
+// toolchain, 02 the project, 03 the engine, 04 the build, 05 the packaging
+// and 06 the signing and notarization. Codes are never reused. The errors a
+// user can fix have a code, the failures to read and write files, whose
+// message is the system error, don't.
+const (
+	MissingExecutable       = "E0101"
+	FlutterChannelMismatch  = "E0102"
+	FlutterVersionMismatch  = "E0103"
+	NotAFlutterProject      = "E0201"
+	NotInitialized          = "E0202"
+	FileAssociationsInvalid = "E0203"
+	PostBuildStepsInvalid   = "E0204"
+	EngineDownloadFailed    = "E0301"
+	EngineCachePathSpaces   = "E0302"
+	EngineVersionMismatch   = "E0303"
+	FlutterBuildFailed      = "E0401"
+	GoBuildFailed           = "E0402"
+	StripFailed             = "E0403"
+	PackagingFailed         = "E0501"
+	PackagingNotInitialized = "E0502"
+	TemplateFailed          = "E0503"
+	TemplateOutdated        = "E0504"
+	SigningNotConfigured    = "E0601"
+	SigningFailed           = "E0602"
+	NotarizationFailed      = "E0603"
+	StaplingFailed          = "E0604"
+)
+
+// Explanation is the extended description of an error.
+type Explanation struct {
+	Code        string
+	Title       string
+	Description string
+	Causes      []string
+	Fixes       []string
+}
+
+var explanations = map[string]Explanation{
+	MissingExecutable: {
+		Title:       "A required executable isn't installed",
+		Description: "hover runs external tools: go and flutter to build, git to get the go-flutter plugins, and the tools of the packaging formats (dpkg-deb, rpmbuild, wixl, makensis...). The executable named in the error wasn't found in the PATH.",
+		Causes: []string{
+			"The tool isn't installed.",
+			"The tool is installed, but its directory isn't in the PATH of the shell running hover, e.g. ~/go/bin or the bin directory of the Flutter SDK.",
+		},
+		Fixes: []string{
+			"Install the tool with the package manager of the system, or with the instructions of the error.",
+			"Add the directory of the tool to the PATH.",
+			"Build with `--docker`, the hover docker image has the tools of most packaging formats.",
+		},
+	},
+	FlutterChannelMismatch: {
+		Title:       "The Flutter SDK isn't on the channel of the project",
+		Description: "The flutter-channel of go/hover.yaml pins the channel of Flutter the project is built with, and the Flutter SDK hover found is on another channel.",
+		Causes: []string{
+			"The Flutter SDK was switched to another channel for another project.",
+			"Several Flutter SDKs are installed and the first one of the PATH isn't the one of the project.",
+		},
+		Fixes: []string{
+			"Switch the channel with `flutter channel <channel>`, or with `hover switch-channel <channel>` to also update the engine and go-flutter.",
+			"Use another Flutter SDK with the flutter-path of go/hover.yaml or the --flutter-path flag.",
+		},
+	},
+	FlutterVersionMismatch: {
+		Title:       "The Flutter SDK doesn't satisfy the constraint of pubspec.yaml",
+		Description: "The environment.flutter constraint of pubspec.yaml restricts the versions of Flutter the app is built with, and the version of the Flutter SDK hover found is outside of it.",
+		Causes: []string{
+			"The Flutter SDK was upgraded or downgraded since the constraint was written.",
+			"The constraint was raised by a teammate, and the local Flutter SDK is older.",
+		},
+		Fixes: []string{
+			"Upgrade or downgrade the Flutter SDK, e.g. with `flutter upgrade` or `flutter downgrade`.",
+			"Use another Flutter SDK with the flutter-path of go/hover.yaml or the --flutter-path flag.",
+			"Change the constraint once the app was checked with the version of Flutter.",
+		},
+	},
+	NotAFlutterProject: {
+		Title:       "The command isn't run in a Flutter project",
+		Description: "hover reads the name, version and description of the app from pubspec.yaml, which must be in the working directory.",
+		Causes: []string{
+			"hover is run from a subdirectory of the project, e.g. go/ or lib/.",
+			"pubspec.yaml can't be read or isn't valid YAML.",
+		},
+		Fixes: []string{
+			"Run hover from the root of the Flutter project, next to pubspec.yaml.",
+			"Create a project with `flutter create` first.",
+			"Fix the error of pubspec.yaml reported above, e.g. with `flutter pub get`.",
+		},
+	},
+	NotInitialized: {
+		Title:       "The project isn't initialized for hover",
+		Description: "The go-flutter part of the app, its hover.yaml and its packaging configuration live in the go directory of the project, created by `hover init`.",
+		Causes: []string{
+			"`hover init` wasn't run in this project yet.",
+			"The go directory wasn't committed, or is ignored by git.",
+		},
+		Fixes: []string{
+			"Run `hover init` at the root of the project, and commit the go directory.",
+		},
+	},
+	FileAssociationsInvalid: {
+		Title:       "The file-associations or url-schemes of go/hover.yaml are invalid",
+		Description: "The file-associations and url-schemes of go/hover.yaml are registered by the .desktop files and the shared-mime-info package on linux, the Info.plist of the darwin bundle and the windows installers. Their values end up in the configuration files of each platform, hover checks them before packaging.",
+		Causes: []string{
+			"An extension contains a dot or a space, or is listed twice.",
+			"A mime-type isn't a type/subtype, e.g. text/markdown.",
+			"A description spans several lines.",
+			"A URL scheme has characters other than lowercase letters, digits, +, . and -, or takes over http, https or file.",
+			"The icon of a file association isn't a square PNG of at least 256x256 pixels.",
+		},
+		Fixes: []string{
+			"Fix the entry named in the error in go/hover.yaml.",
+			"Export the icon as a PNG of 256x256 pixels or more.",
+		},
+	},
+	PostBuildStepsInvalid: {
+		Title:       "The post-build steps of go/hover.yaml are invalid",
+		Description: "The post-build of go/hover.yaml lists the steps run after the build of a packaging format, or of all the formats of a platform. The steps can only run in the order strip, sign, package, sign-installer, notarize, staple, and some only apply to a platform.",
+		Causes: []string{
+			"A step is unknown, listed twice or listed out of order.",
+			"A packaging format has no package step.",
+			"The windows builds are stripped or notarized, or the builds of the other platforms are signed.",
+			"The ticket is stapled without notarizing, or the debug symbols moved to a package by split-packages are stripped.",
+		},
+		Fixes: []string{
+			"Fix the steps of the format named in the error, in the order strip, sign, package, sign-installer, notarize, staple.",
+			"Remove the entry of the format from post-build to run the default steps.",
+		},
+	},
+	EngineDownloadFailed: {
+		Title:       "The Flutter engine couldn't be downloaded",
+		Description: "hover downloads the engine required by the Flutter SDK, or by the engine-version of go/hover.yaml, to the engine cache before building. The download or the extraction of the engine failed.",
+		Causes: []string{
+			"There is no network access, or a proxy or firewall blocks storage.googleapis.com.",
+			"The engine of the version doesn't exist for the target, e.g. a custom engine-version or an unsupported architecture.",
+			"The cache directory isn't writable or the disk is full.",
+		},
+		Fixes: []string{
+			"Check the network access, or set FLUTTER_STORAGE_BASE_URL to a mirror of the Flutter storage.",
+			"Remove the engine-version of go/hover.yaml to use the engine required by the Flutter SDK.",
+			"Use another cache directory with --cache-path, or free some space with `hover cache gc`.",
+			"Build with an engine built locally with the local-engine of go/hover.yaml.",
+		},
+	},
+	EngineCachePathSpaces: {
+		Title:       "The engine cache path contains spaces",
+		Description: "The path of the engine is passed to the C compiler and linker by cgo, which don't support spaces in the paths of the libraries.",
+		Causes: []string{
+			"The user directory contains a space, e.g. C:\\Users\\Jane Doe on windows.",
+		},
+		Fixes: []string{
+			"Use a cache path without spaces with the --cache-path flag, or the cache-path of go/hover.yaml, e.g. C:\\cache.",
+		},
+	},
+	EngineVersionMismatch: {
+		Title:       "The cached engine isn't the one required by Flutter",
+		Description: "With --skip-engine-download, hover builds with the engine of the cache, which must be the engine required by the Flutter SDK. The flutter assets built for another engine crash the app on launch.",
+		Causes: []string{
+			"The Flutter SDK was upgraded since the engine was downloaded.",
+			"The engine was never downloaded in this cache, e.g. on a new CI runner.",
+		},
+		Fixes: []string{
+			"Build without --skip-engine-download once to download the engine.",
+			"Prefetch the engines with `hover cache prefetch` when the builds must not download them.",
+		},
+	},
+	FlutterBuildFailed: {
+		Title:       "The Flutter build failed",
+		Description: "hover runs `flutter build bundle` to compile the dart code and the flutter assets, its errors are printed above.",
+		Causes: []string{
+			"The dart code doesn't compile.",
+			"The dependencies aren't fetched, or an asset of pubspec.yaml is missing.",
+			"The target (lib/main_desktop.dart by default) doesn't exist.",
+		},
+		Fixes: []string{
+			"Fix the dart errors printed above, `flutter analyze` lists them.",
+			"Run `flutter pub get`, and check the assets of pubspec.yaml.",
+			"Set the target of go/hover.yaml or the --target flag to the entrypoint of the app.",
+		},
+	},
+	GoBuildFailed: {
+		Title:       "The Go build failed",
+		Description: "hover runs `go build` in the go directory to compile go-flutter, the plugins and go/cmd, linked with the Flutter engine. The errors of go and of the C compiler are printed above.",
+		Causes: []string{
+			"The code of go/cmd or of a plugin doesn't compile.",
+			"The C compiler or the development libraries are missing, e.g. the OpenGL and X11 headers on linux.",
+			"The C cross-compiler of the target is missing when building for another OS or architecture.",
+			"The go modules couldn't be downloaded.",
+		},
+		Fixes: []string{
+			"Fix the errors printed above, `hover doctor` checks the toolchain.",
+			"Install the dependencies of go-flutter: https://github.com/go-flutter-desktop/go-flutter/wiki",
+			"Build with `--docker`, the hover docker image has the compilers and libraries of every target.",
+			"Check the network access, or set GOPROXY.",
+		},
+	},
+	StripFailed: {
+		Title:       "The executable couldn't be stripped",
+		Description: "The strip step of the post-build of go/hover.yaml removes the symbols of the executable with the strip of binutils, or of Xcode for darwin.",
+		Causes: []string{
+			"The strip of the host doesn't know the format of the executable, e.g. a darwin executable stripped on linux, or an executable of another architecture.",
+		},
+		Fixes: []string{
+			"Strip on the platform of the build, or install the binutils of the target architecture.",
+			"Remove strip from the post-build steps of go/hover.yaml.",
+		},
+	},
+	PackagingFailed: {
+		Title:       "The packaging command failed",
+		Description: "hover renders the configuration files of the packaging format in a temporary directory, then runs the packaging tool of the format there. The tool failed, its errors are printed above.",
+		Causes: []string{
+			"A value of the configuration files isn't accepted by the tool, e.g. a version or a package name.",
+			"The packaging tool is too old, or a tool it runs is missing.",
+			"The configuration files were initialized by an older hover.",
+		},
+		Fixes: []string{
+			"Inspect the temporary directory printed above, which is kept after a failed packaging, and run the command printed above in it.",
+			"Upgrade the configuration files with `hover upgrade-packaging <format>`.",
+			"Package with `--docker` to use the tools of the hover docker image.",
+		},
+	},
+	PackagingNotInitialized: {
+		Title:       "The packaging format isn't initialized",
+		Description: "The configuration files of a packaging format are created in go/packaging/<format> by `hover init-packaging <format>`, to be adapted and committed.",
+		Causes: []string{
+			"`hover init-packaging <format>` wasn't run for this format.",
+			"The go/packaging/<format> directory wasn't committed.",
+		},
+		Fixes: []string{
+			"Run `hover init-packaging <format>`, and commit go/packaging/<format>.",
+		},
+	},
+	TemplateOutdated: {
+		Title:       "The configuration files of the packaging format are outdated",
+		Description: "Some features of go/hover.yaml need the configuration files of the packaging formats to include what hover generates, e.g. the document types of the Info.plist, the file_associations.wxi of the msi or the postinstall script of the pkg. The files initialized by an older hover don't.",
+		Causes: []string{
+			"The packaging format was initialized before hover supported the feature.",
+			"The part of the configuration file was removed when it was adapted.",
+		},
+		Fixes: []string{
+			"Run the `hover upgrade-packaging <format>` of the error, which merges the new templates with the changes of the files.",
+		},
+	},
+	TemplateFailed: {
+		Title:       "A template couldn't be rendered",
+		Description: "The configuration files of the packaging formats, their file names and the packaging scripts are Go templates rendered with the template data of hover. Using a value that doesn't exist is an error, to catch typos.",
+		Causes: []string{
+			"A template uses a value that doesn't exist, or was added by a newer hover.",
+			"A template has a syntax error, e.g. an unclosed {{if}}.",
+		},
+		Fixes: []string{
+			"Fix the template named in the error, `hover env` lists the template data.",
+			"Add the value to the template-data of go/hover.yaml.",
+			"Start the template with {{/* missingkey=zero */}} to render the missing values as empty.",
+		},
+	},
+	SigningNotConfigured: {
+		Title:       "The signing or the notarization isn't configured",
+		Description: "The windows builds are signed with the signing of go/hover.yaml, and the darwin packages notarized when the post-build steps notarize them. The credentials are read from the environment and checked before the build.",
+		Causes: []string{
+			"The keystore or the alias of signing isn't set in go/hover.yaml.",
+			"The credentials of the provider aren't in the environment, e.g. HOVER_SIGNING_PASSWORD or AZURE_ACCESS_TOKEN.",
+			"Neither HOVER_NOTARY_PROFILE nor APPLE_API_KEY, APPLE_API_KEY_ID and APPLE_API_ISSUER are set to notarize.",
+		},
+		Fixes: []string{
+			"Set the signing of go/hover.yaml and the credentials of its provider.",
+			"Build with --skip-signing, e.g. for the builds of the pull requests without access to the credentials.",
+		},
+	},
+	SigningFailed: {
+		Title:       "A windows executable or package couldn't be signed",
+		Description: "hover signs the executable and the DLLs of the windows builds, and the windows packages, with jsign. Its errors are printed with the file it failed to sign.",
+		Causes: []string{
+			"The credentials are wrong or expired, or the key isn't in the keystore under the alias.",
+			"The timestamping authority can't be reached.",
+			"jsign or the java runtime it needs isn't installed.",
+		},
+		Fixes: []string{
+			"Check the credentials and the alias of the key, and the network access to the timestamp-url.",
+			"Install jsign, https://ebourg.github.io/jsign/",
+		},
+	},
+	NotarizationFailed: {
+		Title:       "A darwin package couldn't be notarized",
+		Description: "The notarize step submits the dmg, pkg and zip packages to the Apple notary service with `xcrun notarytool` and waits for the result. The notary service rejects the packages whose executables aren't signed with a Developer ID and the hardened runtime.",
+		Causes: []string{
+			"The packaging format has no dmg, pkg or zip package.",
+			"The executables of the package aren't signed with a Developer ID, without the hardened runtime or without a secure timestamp.",
+			"The credentials of notarytool are wrong, or the Apple developer agreements must be accepted again.",
+		},
+		Fixes: []string{
+			"Run the `xcrun notarytool log <id>` of the error to list the problems of the package.",
+			"Sign the bundle with a Developer ID Application certificate and --options runtime.",
+			"Notarize a darwin-dmg, darwin-pkg or darwin-bundle package.",
+		},
+	},
+	StaplingFailed: {
+		Title:       "The notarization ticket couldn't be stapled",
+		Description: "The staple step staples the ticket of the notarization to the packages with `xcrun stapler`, so Gatekeeper accepts them offline. The ticket is only available once the notarization was accepted.",
+		Causes: []string{
+			"The package wasn't notarized, or was modified after the notarization.",
+			"The CloudKit service distributing the tickets isn't reachable yet, shortly after the notarization.",
+		},
+		Fixes: []string{
+			"Notarize before stapling, and staple again after a few minutes.",
+		},
+	},
+}
+
+// Lookup returns the explanation of an error code, case insensitive.
+func Lookup(code string) (Explanation, bool) {
+	code = strings.ToUpper(code)
+	explanation, ok := explanations[code]
+	explanation.Code = code
+	return explanation, ok
+}
+
+// All returns the explanations of all the error codes, ordered by code.
+func All() []Explanation {
+	var all []Explanation
+	for code, explanation := range explanations {
+		explanation.Code = code
+		all = append(all, explanation)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Code < all[j].Code })
+	return all
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+// +build !linux
+
+package fileutils
+
+import (
+	"errors"
+	"os"
+)
+
+func cloneFile(dst, src *os.File) error {
+	return errors.New("cloning files is not supported on this platform")
+}
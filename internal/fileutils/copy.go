@@ -0,0 +1,112 @@
+package fileutils
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// parallel runs the jobs on one worker per CPU, and returns the first error.
+func parallel(jobs []func() error) error {
+	workers := runtime.NumCPU()
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+	queue := make(chan func() error)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				if err := job(); err != nil {
+					once.Do(func() { firstErr = err })
+				}
+			}
+		}()
+	}
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+	wg.Wait()
+	return firstErr
+}
+
+// copyTree copies a directory recursively, copying the files in parallel.
+// Files are cloned when the filesystem supports it (reflinks), which is as
+// safe as a copy: the packaging scripts modify the files of the temporary
+// directories in place (chmod, strip, codesign), which would modify the
+// build output through hard links.
+func copyTree(src, dst string) error {
+	var jobs []func() error
+	err := filepath.Walk(LongPath(src), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(LongPath(src), path)
+		if err != nil {
+			return err
+		}
+		target := LongPath(filepath.Join(dst, relativePath))
+		switch mode := info.Mode(); {
+		case mode.IsDir():
+			return os.MkdirAll(target, 0755)
+		case mode&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			os.Remove(target)
+			return os.Symlink(link, target)
+		case mode.IsRegular():
+			jobs = append(jobs, func() error {
+				return copyFile(path, target, mode.Perm())
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to copy directory %s", src)
+	}
+	return parallel(jobs)
+}
+
+// copyFile copies a regular file, cloning it when possible.
+func copyFile(src, dst string, perm os.FileMode) error {
+	// an existing destination may be read-only, it is replaced.
+	err := os.Remove(dst)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to replace %s", dst)
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", src)
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s", dst)
+	}
+	if cloneFile(out, in) == nil {
+		return out.Close()
+	}
+	_, err = io.Copy(out, in)
+	if err != nil {
+		out.Close()
+		return errors.Wrapf(err, "failed to copy %s to %s", src, dst)
+	}
+	return out.Close()
+}
+
+// CloneDir copies a directory recursively, cloning the files on the
+// filesystems supporting it.
+func CloneDir(src, dst string) error {
+	return copyTree(src, dst)
+}
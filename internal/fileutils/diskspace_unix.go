@@ -0,0 +1,21 @@
+//go:build !windows
+// +build !windows
+
+package fileutils
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// FreeSpace returns the number of bytes available to the user on the
+// filesystem of a path.
+func FreeSpace(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	err := syscall.Statfs(path, &stat)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to read the free space of %s", path)
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
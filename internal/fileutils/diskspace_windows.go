@@ -0,0 +1,25 @@
+package fileutils
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+var getDiskFreeSpaceEx = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+// FreeSpace returns the number of bytes available to the user on the
+// filesystem of a path.
+func FreeSpace(path string) (uint64, error) {
+	pathPtr, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to read the free space of %s", path)
+	}
+	var freeBytesAvailable uint64
+	ret, _, err := getDiskFreeSpaceEx.Call(uintptr(unsafe.Pointer(pathPtr)), uintptr(unsafe.Pointer(&freeBytesAvailable)), 0, 0)
+	if ret == 0 {
+		return 0, errors.Wrapf(err, "failed to read the free space of %s", path)
+	}
+	return freeBytesAvailable, nil
+}
@@ -8,14 +8,39 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
-	"text/template"
 
 	rice "github.com/GeertJohan/go.rice"
+	"github.com/pkg/errors"
 
+	"github.com/go-flutter-desktop/hover/internal/explain"
 	"github.com/go-flutter-desktop/hover/internal/log"
 )
 
+// windowsMaxPath is the length from which paths must be prefixed on windows.
+// MAX_PATH is 260, but directories are limited to 248 characters (MAX_PATH
+// minus the 8.3 filename).
+const windowsMaxPath = 248
+
+// LongPath returns a path that is safe to use on windows even when it exceeds
+// MAX_PATH. Long absolute paths are prefixed with `\\?\` and long UNC paths
+// (\\server\share\...) with `\\?\UNC\`. On other platforms, and for short
+// or relative paths, the path is returned as-is.
+func LongPath(path string) string {
+	if runtime.GOOS != "windows" || len(path) < windowsMaxPath || strings.HasPrefix(path, `\\?\`) {
+		return path
+	}
+	if !filepath.IsAbs(path) {
+		return path
+	}
+	path = filepath.Clean(path)
+	if strings.HasPrefix(path, `\\`) {
+		return `\\?\UNC\` + strings.TrimPrefix(path, `\\`)
+	}
+	return `\\?\` + path
+}
+
 // IsFileExists checks if a file exists and is not a directory
 func IsFileExists(filename string) bool {
 	info, err := os.Stat(filename)
@@ -34,6 +59,28 @@ func IsDirectory(path string) bool {
 	return info.IsDir()
 }
 
+// DirSize returns the total size of the files of a directory, 0 when it
+// doesn't exist.
+func DirSize(path string) (uint64, error) {
+	var size uint64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += uint64(info.Size())
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to compute the size of %s", path)
+	}
+	return size, nil
+}
+
 // RemoveLinesFromFile removes lines to a file if the text is present in the line
 func RemoveLinesFromFile(filePath, text string) {
 	input, err := ioutil.ReadFile(filePath)
@@ -89,20 +136,7 @@ func AddLineToFile(filePath, newLine string) {
 
 // CopyFile from one file to another
 func CopyFile(src, to string) {
-	in, err := os.Open(src)
-	if err != nil {
-		log.Errorf("Failed to read %s: %v\n", src, err)
-		os.Exit(1)
-	}
-	defer in.Close()
-	file, err := os.Create(to)
-	if err != nil {
-		log.Errorf("Failed to create %s: %v\n", to, err)
-		os.Exit(1)
-	}
-	defer file.Close()
-
-	_, err = io.Copy(file, in)
+	err := copyFile(LongPath(src), LongPath(to), 0666)
 	if err != nil {
 		log.Errorf("Failed to copy %s to %s: %v\n", src, to, err)
 		os.Exit(1)
@@ -111,33 +145,15 @@ func CopyFile(src, to string) {
 
 // CopyDir copy files from one directory to another directory recursively
 func CopyDir(src, dst string) {
-	var err error
-	var fds []os.FileInfo
-
 	if !IsDirectory(src) {
 		log.Errorf("Failed to copy directory, %s not a directory\n", src)
 		os.Exit(1)
 	}
-
-	if err = os.MkdirAll(dst, 0755); err != nil {
+	err := copyTree(src, dst)
+	if err != nil {
 		log.Errorf("Failed to copy directory %s to %s: %v\n", src, dst, err)
 		os.Exit(1)
 	}
-
-	if fds, err = ioutil.ReadDir(src); err != nil {
-		log.Errorf("Failed to list directory %s: %v\n", src, err)
-		os.Exit(1)
-	}
-
-	for _, fd := range fds {
-		srcPath := filepath.Join(src, fd.Name())
-		dstPath := filepath.Join(dst, fd.Name())
-		if fd.IsDir() {
-			CopyDir(srcPath, dstPath)
-		} else {
-			CopyFile(srcPath, dstPath)
-		}
-	}
 }
 
 // CopyTemplateDir copy files from one directory to another directory recursively
@@ -153,20 +169,30 @@ func CopyTemplateDir(boxed, to string, templateData interface{}) {
 		log.Errorf("Failed to list files in directory %s: %v\n", boxed, err)
 		os.Exit(1)
 	}
+	// The directories are created in order, the files are rendered in
+	// parallel.
+	var jobs []func() error
 	for _, file := range files {
-		newFile := filepath.Join(to, strings.Join(strings.Split(file, "")[len(boxed)+1:], ""))
-		tmplFile, err := template.New("").Option("missingkey=error").Parse(newFile)
+		// Only the path relative to the template directory is executed as a
+		// template, the destination directory may contain arbitrary characters.
+		relativeFile, err := filepath.Rel(boxed, file)
+		if err != nil {
+			log.Errorf("Failed to resolve relative path of %s: %v\n", file, err)
+			os.Exit(1)
+		}
+		tmplFile, err := ParseTemplate(relativeFile, relativeFile)
 		if err != nil {
-			log.Errorf("Failed to parse template string: %v\n", err)
+			log.ErrorCodef(explain.TemplateFailed, "Failed to parse template string: %v", err)
 			os.Exit(1)
 		}
 		var tmplBytes bytes.Buffer
 		err = tmplFile.Execute(&tmplBytes, templateData)
 		if err != nil {
-			panic(err)
+			log.ErrorCodef(explain.TemplateFailed, "Failed to execute template: %v", err)
+			os.Exit(1)
 		}
-		newFile = tmplBytes.String()
-		fi, err := os.Stat(file)
+		newFile := LongPath(filepath.Join(to, tmplBytes.String()))
+		fi, err := os.Stat(LongPath(file))
 		if err != nil {
 			fmt.Println(err)
 			return
@@ -182,26 +208,52 @@ func CopyTemplateDir(boxed, to string, templateData interface{}) {
 			if strings.HasSuffix(newFile, ".tmpl") {
 				newFile = strings.TrimSuffix(newFile, ".tmpl")
 			}
-			ExecuteTemplateFromFile(file, newFile, templateData)
+			file := file
+			jobs = append(jobs, func() error {
+				templateString, err := ioutil.ReadFile(LongPath(file))
+				if err != nil {
+					return errors.Wrap(err, "failed to find template file")
+				}
+				return renderTemplate(file, string(templateString), newFile, templateData)
+			})
 		}
 	}
-}
-
-func executeTemplateFromString(templateString, to string, templateData interface{}) {
-	tmplFile, err := template.New("").Option("missingkey=error").Parse(templateString)
+	err = parallel(jobs)
 	if err != nil {
-		log.Errorf("Failed to parse template string: %v\n", err)
+		log.ErrorCodef(explain.TemplateFailed, "%v", err)
 		os.Exit(1)
 	}
+}
 
+// renderTemplate executes a template string to a file.
+func renderTemplate(name, templateString, to string, templateData interface{}) error {
+	tmplFile, err := ParseTemplate(name, templateString)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse template")
+	}
+	// the destination may be a read-only copy of the build output
+	err = os.Remove(to)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to replace '%s'", to)
+	}
 	toFile, err := os.Create(to)
 	if err != nil {
-		log.Errorf("Failed to create '%s': %v\n", to, err)
-		os.Exit(1)
+		return errors.Wrapf(err, "failed to create '%s'", to)
 	}
 	defer toFile.Close()
+	err = tmplFile.Execute(toFile, templateData)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute template")
+	}
+	return nil
+}
 
-	tmplFile.Execute(toFile, templateData)
+func executeTemplateFromString(name, templateString, to string, templateData interface{}) {
+	err := renderTemplate(name, templateString, to, templateData)
+	if err != nil {
+		log.Errorf("%v\n", err)
+		os.Exit(1)
+	}
 }
 
 // ExecuteTemplateFromFile create file from a template file
@@ -211,7 +263,7 @@ func ExecuteTemplateFromFile(boxed, to string, templateData interface{}) {
 		log.Errorf("Failed to find template file: %v\n", err)
 		os.Exit(1)
 	}
-	executeTemplateFromString(string(templateString), to, templateData)
+	executeTemplateFromString(boxed, string(templateString), to, templateData)
 }
 
 // ExecuteTemplateFromAssetsBox create file from a template asset
@@ -221,7 +273,7 @@ func ExecuteTemplateFromAssetsBox(boxed, to string, assetsBox *rice.Box, templat
 		log.Errorf("Failed to find template file: %v\n", err)
 		os.Exit(1)
 	}
-	executeTemplateFromString(templateString, to, templateData)
+	executeTemplateFromString(boxed, templateString, to, templateData)
 }
 
 // CopyAsset copies a file from asset
@@ -15,139 +15,475 @@ func init() {
 
 		Content: string("# assets\n\nThis directory contains templates and config files that hover uses to initialize apps and packaging structures. When modifying these assets, you need to update the generated code so that the assets are included in the Go build process.\n\n## Installing rice\n\nInstall the rice tool by running `(cd $HOME && GO111MODULE=on go get -u -a github.com/GeertJohan/go.rice/rice)`.\n\n## Updating code\n\nRun `go generate ./...` in the repository to update the generated code.\n"),
 	}
+	file3a := &embedded.EmbeddedFile{
+		Filename:    "app/assetsdecrypt.go",
+		FileModTime: time.Unix(1792090000, 0),
+
+		Content: string("package main\n\nimport (\n\t\"crypto/aes\"\n\t\"crypto/cipher\"\n\t\"crypto/sha256\"\n\t\"encoding/hex\"\n\t\"fmt\"\n\t\"io\"\n\t\"io/ioutil\"\n\t\"os\"\n\t\"path/filepath\"\n\t\"strings\"\n\n\t\"github.com/go-flutter-desktop/go-flutter\"\n\t\"github.com/pkg/errors\"\n)\n\n// assetsKey is set by hover at compile-time when encrypt-assets is enabled in\n// hover.yaml. The kernel_blob.bin of the flutter assets is then shipped\n// encrypted, and decrypted to the user cache directory on launch.\nvar assetsKey string\n\nfunc init() {\n\tif assetsKey == \"\" {\n\t\treturn\n\t}\n\tassetsPath, err := decryptAssets()\n\tif err != nil {\n\t\tfmt.Printf(\"failed to decrypt the flutter assets: %v\\n\", err)\n\t\tos.Exit(1)\n\t}\n\toptions = append(options, flutter.ProjectAssetsPath(assetsPath))\n}\n\n// decryptAssets returns a flutter_assets directory with the decrypted\n// kernel_blob.bin, linking to the other assets. It is reused by the next\n// launches of the same build.\nfunc decryptAssets() (string, error) {\n\texecPath, err := os.Executable()\n\tif err != nil {\n\t\treturn \"\", errors.Wrap(err, \"failed to resolve executable path\")\n\t}\n\texecPath, err = filepath.EvalSymlinks(execPath)\n\tif err != nil {\n\t\treturn \"\", errors.Wrap(err, \"failed to eval symlinks for executable path\")\n\t}\n\tassetsPath := filepath.Join(filepath.Dir(execPath), \"flutter_assets\")\n\tencrypted, err := ioutil.ReadFile(filepath.Join(assetsPath, \"kernel_blob.bin.enc\"))\n\tif err != nil {\n\t\treturn \"\", err\n\t}\n\n\tname := flutter.ProjectName\n\tif name == \"\" {\n\t\tname = strings.TrimSuffix(filepath.Base(execPath), \".exe\")\n\t}\n\tcacheDir, err := os.UserCacheDir()\n\tif err != nil {\n\t\treturn \"\", err\n\t}\n\tsum := sha256.Sum256(encrypted)\n\tdecryptedPath := filepath.Join(cacheDir, name, \"flutter_assets-\"+hex.EncodeToString(sum[:8]))\n\tif _, err := os.Stat(filepath.Join(decryptedPath, \"kernel_blob.bin\")); err == nil {\n\t\treturn decryptedPath, nil\n\t}\n\n\tkey, err := hex.DecodeString(assetsKey)\n\tif err != nil {\n\t\treturn \"\", errors.Wrap(err, \"invalid key\")\n\t}\n\tblock, err := aes.NewCipher(key)\n\tif err != nil {\n\t\treturn \"\", err\n\t}\n\tgcm, err := cipher.NewGCM(block)\n\tif err != nil {\n\t\treturn \"\", err\n\t}\n\tif len(encrypted) < gcm.NonceSize() {\n\t\treturn \"\", errors.New(\"kernel_blob.bin.enc is truncated\")\n\t}\n\tkernel, err := gcm.Open(nil, encrypted[:gcm.NonceSize()], encrypted[gcm.NonceSize():], nil)\n\tif err != nil {\n\t\treturn \"\", errors.Wrap(err, \"failed to decrypt kernel_blob.bin.enc\")\n\t}\n\n\t// the assets of the previous versions of the app\n\tpreviousPaths, _ := filepath.Glob(filepath.Join(cacheDir, name, \"flutter_assets-*\"))\n\tfor _, previousPath := range previousPaths {\n\t\tos.RemoveAll(previousPath)\n\t}\n\terr = os.MkdirAll(filepath.Dir(decryptedPath), 0700)\n\tif err != nil {\n\t\treturn \"\", err\n\t}\n\t// several instances of the app may be starting\n\tpartialPath, err := ioutil.TempDir(filepath.Dir(decryptedPath), \"partial-\")\n\tif err != nil {\n\t\treturn \"\", err\n\t}\n\tdefer os.RemoveAll(partialPath)\n\tentries, err := ioutil.ReadDir(assetsPath)\n\tif err != nil {\n\t\treturn \"\", err\n\t}\n\tfor _, entry := range entries {\n\t\tif entry.Name() == \"kernel_blob.bin.enc\" {\n\t\t\tcontinue\n\t\t}\n\t\terr = linkOrCopy(filepath.Join(assetsPath, entry.Name()), filepath.Join(partialPath, entry.Name()))\n\t\tif err != nil {\n\t\t\treturn \"\", err\n\t\t}\n\t}\n\terr = ioutil.WriteFile(filepath.Join(partialPath, \"kernel_blob.bin\"), kernel, 0600)\n\tif err != nil {\n\t\treturn \"\", err\n\t}\n\terr = os.Rename(partialPath, decryptedPath)\n\tif err != nil {\n\t\tif _, statErr := os.Stat(filepath.Join(decryptedPath, \"kernel_blob.bin\")); statErr == nil {\n\t\t\treturn decryptedPath, nil\n\t\t}\n\t\treturn \"\", err\n\t}\n\treturn decryptedPath, nil\n}\n\n// linkOrCopy links an asset, or copies it when symbolic links aren't\n// available (e.g. on windows without the developer mode).\nfunc linkOrCopy(src, dst string) error {\n\tif os.Symlink(src, dst) == nil {\n\t\treturn nil\n\t}\n\treturn filepath.Walk(src, func(path string, info os.FileInfo, err error) error {\n\t\tif err != nil {\n\t\t\treturn err\n\t\t}\n\t\trelativePath, err := filepath.Rel(src, path)\n\t\tif err != nil {\n\t\t\treturn err\n\t\t}\n\t\ttarget := filepath.Join(dst, relativePath)\n\t\tif info.IsDir() {\n\t\t\treturn os.MkdirAll(target, 0700)\n\t\t}\n\t\tin, err := os.Open(path)\n\t\tif err != nil {\n\t\t\treturn err\n\t\t}\n\t\tdefer in.Close()\n\t\tout, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)\n\t\tif err != nil {\n\t\t\treturn err\n\t\t}\n\t\t_, err = io.Copy(out, in)\n\t\tif closeErr := out.Close(); err == nil {\n\t\t\terr = closeErr\n\t\t}\n\t\treturn err\n\t})\n}\n"),
+	}
+	filedr := &embedded.EmbeddedFile{
+		Filename:    "app/buildinfo.go",
+		FileModTime: time.Unix(1792033322, 0),
+
+		Content: string("package main\n\nimport (\n\t\"encoding/base64\"\n\t\"encoding/json\"\n\t\"fmt\"\n\n\t\"github.com/go-flutter-desktop/go-flutter\"\n\t\"github.com/go-flutter-desktop/go-flutter/plugin\"\n)\n\n// buildInfo is set by hover at compile-time to the base64 of the JSON\n// describing the build: the version, the git commit, the build time and the\n// versions of hover, the engine and go-flutter. The same JSON is written to\n// assets/build_info.json next to the executable.\nvar buildInfo string\n\nfunc init() {\n\tif buildInfo == \"\" {\n\t\treturn\n\t}\n\tdata, err := base64.StdEncoding.DecodeString(buildInfo)\n\tif err != nil {\n\t\tfmt.Printf(\"build info disabled: %v\\n\", err)\n\t\treturn\n\t}\n\tvar info map[string]interface{}\n\terr = json.Unmarshal(data, &info)\n\tif err != nil {\n\t\tfmt.Printf(\"build info disabled: %v\\n\", err)\n\t\treturn\n\t}\n\toptions = append(options, flutter.AddPlugin(&buildInfoPlugin{info: info}))\n}\n\n// buildInfoPlugin returns the build info to dart, for an about or diagnostics\n// screen, with the method get of the hover/build-info channel: a map of\n// version, commit, buildTime, hoverVersion, engineVersion, goFlutterVersion,\n// os, arch and debug.\ntype buildInfoPlugin struct {\n\tinfo map[string]interface{}\n}\n\nfunc (p *buildInfoPlugin) InitPlugin(messenger plugin.BinaryMessenger) error {\n\tchannel := plugin.NewMethodChannel(messenger, \"hover/build-info\", plugin.StandardMethodCodec{})\n\tchannel.HandleFunc(\"get\", p.handleGet)\n\treturn nil\n}\n\nfunc (p *buildInfoPlugin) handleGet(arguments interface{}) (interface{}, error) {\n\t// the standard method codec encodes maps of interface{} keys\n\treply := make(map[interface{}]interface{}, len(p.info))\n\tfor key, value := range p.info {\n\t\treply[key] = value\n\t}\n\treturn reply, nil\n}\n"),
+	}
 	file4 := &embedded.EmbeddedFile{
+		Filename:    "app/crashhandler.go",
+		FileModTime: time.Unix(1792003773, 0),
+
+		Content: string("//go:build go1.23\n\npackage main\n\nimport (\n\t\"bytes\"\n\t\"encoding/json\"\n\t\"fmt\"\n\t\"io/ioutil\"\n\t\"net/http\"\n\t\"os\"\n\t\"path/filepath\"\n\t\"runtime\"\n\t\"runtime/debug\"\n\t\"strings\"\n\t\"time\"\n\n\t\"github.com/go-flutter-desktop/go-flutter\"\n)\n\n// crashReportURL may be set by hover at compile-time, using the\n// crash-report-url of hover.yaml. Crash reports are POSTed to it as JSON on\n// the next launch of the app.\nvar crashReportURL string\n\n// pendingCrashReport receives the output of the go runtime when the app\n// crashes: unrecovered panics, and fatal signals such as a segmentation fault\n// in the flutter engine. It is empty when the app exits normally.\nconst pendingCrashReport = \"pending.log\"\n\nfunc init() {\n\tdir, err := crashReportsDir()\n\tif err != nil {\n\t\tfmt.Printf(\"crash handler disabled: %v\\n\", err)\n\t\treturn\n\t}\n\terr = os.MkdirAll(dir, 0755)\n\tif err != nil {\n\t\tfmt.Printf(\"crash handler disabled: %v\\n\", err)\n\t\treturn\n\t}\n\tpendingPath := filepath.Join(dir, pendingCrashReport)\n\tif info, err := os.Stat(pendingPath); err == nil && info.Size() > 0 {\n\t\treportPath := filepath.Join(dir, \"crash-\"+info.ModTime().UTC().Format(\"20060102T150405Z\")+\".log\")\n\t\terr = os.Rename(pendingPath, reportPath)\n\t\tif err == nil && crashReportURL != \"\" {\n\t\t\tgo uploadCrashReport(reportPath)\n\t\t}\n\t}\n\t// Appending lets several instances of the app share the file.\n\tpending, err := os.OpenFile(pendingPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)\n\tif err != nil {\n\t\tfmt.Printf(\"crash handler disabled: %v\\n\", err)\n\t\treturn\n\t}\n\terr = debug.SetCrashOutput(pending, debug.CrashOptions{})\n\tif err != nil {\n\t\tfmt.Printf(\"crash handler disabled: %v\\n\", err)\n\t}\n\tpending.Close()\n}\n\n// crashReportsDir returns the directory the crash reports are written to:\n// ~/.local/state/<app>/crashes on linux, ~/Library/Logs/<app>/crashes on\n// darwin and %LOCALAPPDATA%\\<app>\\crashes on windows.\nfunc crashReportsDir() (string, error) {\n\tname := flutter.ProjectName\n\tif name == \"\" {\n\t\texecPath, err := os.Executable()\n\t\tif err != nil {\n\t\t\treturn \"\", err\n\t\t}\n\t\tname = strings.TrimSuffix(filepath.Base(execPath), \".exe\")\n\t}\n\tswitch runtime.GOOS {\n\tcase \"windows\":\n\t\tdir, err := os.UserCacheDir()\n\t\tif err != nil {\n\t\t\treturn \"\", err\n\t\t}\n\t\treturn filepath.Join(dir, name, \"crashes\"), nil\n\tcase \"darwin\":\n\t\thome, err := os.UserHomeDir()\n\t\tif err != nil {\n\t\t\treturn \"\", err\n\t\t}\n\t\treturn filepath.Join(home, \"Library\", \"Logs\", name, \"crashes\"), nil\n\tdefault:\n\t\tif state := os.Getenv(\"XDG_STATE_HOME\"); state != \"\" {\n\t\t\treturn filepath.Join(state, name, \"crashes\"), nil\n\t\t}\n\t\thome, err := os.UserHomeDir()\n\t\tif err != nil {\n\t\t\treturn \"\", err\n\t\t}\n\t\treturn filepath.Join(home, \".local\", \"state\", name, \"crashes\"), nil\n\t}\n}\n\n// uploadCrashReport sends a crash report to crashReportURL. Uploaded reports\n// are kept, with an .uploaded suffix.\nfunc uploadCrashReport(reportPath string) {\n\treport, err := ioutil.ReadFile(reportPath)\n\tif err != nil {\n\t\treturn\n\t}\n\tbody, err := json.Marshal(map[string]string{\n\t\t\"name\":    flutter.ProjectName,\n\t\t\"version\": flutter.ProjectVersion,\n\t\t\"os\":      runtime.GOOS,\n\t\t\"arch\":    runtime.GOARCH,\n\t\t\"time\":    time.Now().UTC().Format(time.RFC3339),\n\t\t\"report\":  string(report),\n\t})\n\tif err != nil {\n\t\treturn\n\t}\n\tclient := http.Client{Timeout: 30 * time.Second}\n\tres, err := client.Post(crashReportURL, \"application/json\", bytes.NewReader(body))\n\tif err != nil {\n\t\tfmt.Printf(\"failed to upload crash report: %v\\n\", err)\n\t\treturn\n\t}\n\tres.Body.Close()\n\tif res.StatusCode >= 200 && res.StatusCode < 300 {\n\t\tos.Rename(reportPath, reportPath+\".uploaded\")\n\t}\n}\n"),
+	}
+	file5 := &embedded.EmbeddedFile{
+		Filename:    "app/firstrun.go",
+		FileModTime: time.Unix(1792003832, 0),
+
+		Content: string("package main\n\nimport (\n\t\"os\"\n\t\"os/exec\"\n\t\"path/filepath\"\n\t\"runtime\"\n\n\t\"github.com/go-flutter-desktop/go-flutter\"\n)\n\n// firstRunURL may be set by hover at compile-time, when the survey of\n// hover.yaml is opted in. It is opened in the browser on the first launch of\n// the app by each user.\nvar firstRunURL string\n\nfunc init() {\n\tif firstRunURL == \"\" {\n\t\treturn\n\t}\n\tconfigDir, err := os.UserConfigDir()\n\tif err != nil {\n\t\treturn\n\t}\n\tmarkerPath := filepath.Join(configDir, flutter.ProjectName, \"first-run\")\n\tif _, err := os.Stat(markerPath); !os.IsNotExist(err) {\n\t\treturn\n\t}\n\tif os.MkdirAll(filepath.Dir(markerPath), 0755) != nil {\n\t\treturn\n\t}\n\tmarker, err := os.Create(markerPath)\n\tif err != nil {\n\t\treturn\n\t}\n\tmarker.Close()\n\n\tvar open *exec.Cmd\n\tswitch runtime.GOOS {\n\tcase \"windows\":\n\t\topen = exec.Command(\"rundll32\", \"url.dll,FileProtocolHandler\", firstRunURL)\n\tcase \"darwin\":\n\t\topen = exec.Command(\"open\", firstRunURL)\n\tdefault:\n\t\topen = exec.Command(\"xdg-open\", firstRunURL)\n\t}\n\topen.Start()\n}\n"),
+	}
+	file6 := &embedded.EmbeddedFile{
 		Filename:    "app/gitignore",
 		FileModTime: time.Unix(1571249485, 0),
 
 		Content: string("build\n.last_goflutter_check\n"),
 	}
-	file5 := &embedded.EmbeddedFile{
+	file7 := &embedded.EmbeddedFile{
 		Filename:    "app/go.mod",
 		FileModTime: time.Unix(1571249485, 0),
 
 		Content: string(""),
 	}
-	file6 := &embedded.EmbeddedFile{
+	file8 := &embedded.EmbeddedFile{
 		Filename:    "app/hover.yaml.tmpl",
 		FileModTime: time.Unix(1587497089, 0),
 
-		Content: string("#application-name: \"{{.applicationName}}\" # Uncomment to modify this value.\n#executable-name: \"{{.executableName}}\" # Uncomment to modify this value. Only lowercase a-z, numbers, underscores and no spaces\n#package-name: \"{{.packageName}}\" # Uncomment to modify this value. Only lowercase a-z, numbers and no underscores or spaces\nlicense: \"\" # MANDATORY: Fill in your SPDX license name: https://spdx.org/licenses\ntarget: lib/main_desktop.dart\nbranch: \"\" # Change to \"@latest\" to download the latest go-flutter version on every build\n# cache-path: \"/home/YOURUSERNAME/.cache/\" #  https://github.com/go-flutter-desktop/go-flutter/issues/184\n# opengl: \"none\" # Uncomment this line if you have trouble with your OpenGL driver (https://github.com/go-flutter-desktop/go-flutter/issues/272)\ndocker: false\nengine-version: \"\" # change to a engine version commit\n"),
+		Content: string("schema-version: 2 # Written by hover, updated by `hover config migrate`\n#application-name: \"{{.applicationName}}\" # Uncomment to modify this value.\n#executable-name: \"{{.executableName}}\" # Uncomment to modify this value. Only lowercase a-z, numbers, underscores and no spaces\n#package-name: \"{{.packageName}}\" # Uncomment to modify this value. Only lowercase a-z, numbers and no underscores or spaces\nlicense: \"\" # MANDATORY: Fill in your SPDX license name: https://spdx.org/licenses\ntarget: lib/main_desktop.dart\nbranch: \"\" # Change to \"@latest\" to download the latest go-flutter version on every build\n# cache-path: \"/home/YOURUSERNAME/.cache/\" #  https://github.com/go-flutter-desktop/go-flutter/issues/184\n# tmp-dir: \"/home/YOURUSERNAME/tmp\" # Uncomment to package in this directory instead of the system temporary directory (HOVER_TMPDIR takes precedence)\n# opengl: \"none\" # Uncomment this line if you have trouble with your OpenGL driver (https://github.com/go-flutter-desktop/go-flutter/issues/272)\nengine-version: \"\" # change to a engine version commit\n# local-engine: ../engine/src/out/host_release # Uncomment to use a locally built flutter engine instead of downloading it\n# release-engine-sha256: # Uncomment to pin the SHA-256 of the release engines downloaded by --aot, they are refused without it\n#   <engine-version>:\n#     linux: <sha256 of linux_x64-host_release.zip>\n# flutter-path: /home/YOURUSERNAME/sdks/flutter # Uncomment to use this Flutter SDK instead of the flutter found in PATH\n# flutter-channel: stable # Uncomment to fail the builds using a Flutter SDK on another channel\n# icons: # Uncomment to use a different icon than go/assets/icon.png for a packaging format (e.g. linux-snap) or a platform (e.g. darwin)\n#   linux-snap: go/assets/icon-snap.png\n#   darwin: go/assets/icon-rounded.png\n#   linux: go/assets/icon.svg # the linux packages install the icon in the hicolor icon theme, scaled down to 16-512 pixels from a square PNG, or as is from an SVG\n# omit-version-in-filename: [linux-deb, windows] # Uncomment to leave the version out of the artifact names of packaging formats or platforms, e.g. myapp.deb for \"latest\" download links\n# artifact-names: # Uncomment to name the artifacts of packaging formats or platforms after the application name (e.g. \"My App 1.0.0.deb\") or the package name (e.g. myapp-1.0.0.msi)\n#   linux: application-name\n#   windows: package-name\n# repositories: # Uncomment to update package repositories with the linux-deb, linux-rpm, linux-aur, darwin-cask and windows-winget packages on `hover publish`\n#   apt: s3://my-bucket/apt\n#   yum: s3://my-bucket/yum\n#   aur: ssh://aur@aur.archlinux.org/my-app-bin.git # Pushes the PKGBUILD of the linux-aur tarball\n#   homebrew-tap: git@github.com:me/homebrew-tap.git # Pushes the cask of the darwin-dmg\n#   winget: git@github.com:me/winget-pkgs.git # Pushes the manifests of the windows-winget\n#   gpg-key: \"\" # id of the GnuPG key used to sign the repository metadata\n# crash-report-url: \"https://example.com/crashes\" # Uncomment to upload the crash reports of go/cmd/crashhandler.go (added by `hover init --crash-handler`)\n# encrypt-assets: true # Uncomment to encrypt the compiled dart code of the release builds, decrypted by go/cmd/assetsdecrypt.go when the app starts\n# display-server: wayland # Uncomment to build the linux app with the wayland backend of GLFW, without X11, and package it for wayland sessions\n# wm-class: \"myapp\" # Uncomment to set the window class of the app on linux, the StartupWMClass of the .desktop files (defaults to the executable name)\n# startup-notify: true # Uncomment to show a busy cursor until the window of the app appears, on linux\n# desktop: # Uncomment to set the entries of the .desktop file of the linux packages\n#   generic-name: \"Text Editor\"\n#   categories: [Utility]\n#   keywords: []\n#   mime-type: [] # e.g. text/markdown, the files the app opens\n#   terminal: false\n# appstream: # Uncomment to complete the AppStream metainfo of linux-deb, linux-rpm, linux-flatpak and linux-snap, listed by the software centers\n#   summary: \"\" # one line, defaults to the description of pubspec.yaml\n#   description: [] # paragraphs, default to the description of pubspec.yaml\n#   screenshots:\n#     - image: https://example.com/screenshot.png\n#       caption: The main window\n#   releases: # newest first, the version being packaged is added when missing\n#     - version: 1.0.0\n#       date: \"2024-01-31\"\n#       description: [First release]\n#   content-rating: {} # OARS 1.1, e.g. violence-cartoon: mild\n# file-associations: # Uncomment to open files of these types with the app, registered by the linux packages, the darwin bundle and the windows msi\n#   - extension: md\n#     mime-type: text/markdown\n#     description: Markdown document\n#     icon: go/assets/markdown.png # square PNG of at least 256x256 pixels, optional\n# url-schemes: [myapp] # Uncomment to open the myapp:// links with the app, registered by the linux packages, the darwin bundle and the windows installers\n{{if ne .singleInstance \"true\"}}# {{end}}single-instance: true # Run a single instance of the app: go/cmd/singleinstance.go forwards the later launches to it, and the packages declare it\n# dbus-activatable: true # Uncomment to activate the single instance through DBus on linux, the linux packages install a DBus service and their .desktop file is DBusActivatable\n# windows-console: always # Uncomment to open a console window showing the logs of the windows app in the release builds too: debug (default, only the debug builds), always or never\n# preferences: # Uncomment to install the preferences of go/cmd/preferences.go (added by `hover init --preferences`) with their defaults\n#   - key: theme\n#     type: string # string (default), bool or int\n#     default: light\n# locales: [en, fr] # Uncomment to check that the supported locales have translations (.arb files or translation assets) before building\n# launcher: # Uncomment to customize the launcher scripts of the packages (AppRun, /usr/bin script, windows-portable .cmd)\n#   env:\n#     GDK_BACKEND: x11\n#   library-path: [lib] # relative to the app directory\n#   working-directory: \"\" # relative to the app directory\n#   templates: # replace the launcher script of a packaging format or a platform\n#     linux-appimage: go/packaging/AppRun.tmpl\n# split-packages: # Uncomment to build companion packages with the linux-deb and linux-rpm packages\n#   debug-symbols: true # <package>-dbgsym (deb) or <package>-debuginfo (rpm), requires objcopy\n#   data: [] # paths of the build output moved to <package>-data\n# deb: # Uncomment to add relationships with other packages to the control files of linux-deb and linux-deb-src\n#   depends: [libgtk-3-0]\n#   recommends: []\n#   suggests: []\n#   conflicts: []\n#   provides: []\n# rpm: # Uncomment to add dependencies on other packages to the spec of linux-rpm\n#   requires: [gtk3]\n#   build-requires: []\n#   provides: []\n#   obsoletes: []\n# flatpak: # Uncomment to change the runtime of the linux-flatpak package\n#   runtime: org.gnome.Platform # org.freedesktop.Platform (default), org.gnome.Platform or org.kde.Platform\n#   runtime-version: \"46\"\n# makeself: # Uncomment to configure the installer of the linux-run package\n#   prefix: /opt/my-company # <prefix>/<package> is the install directory, /opt as root and ~/.local/opt otherwise by default\n#   desktop-integration: false # don't install the .desktop file\n# inno: # Uncomment to configure the installer of the windows-inno package\n#   install-mode: dialog # admin (default), user or dialog\n#   shortcuts: [start-menu, desktop]\n#   app-mutex: MyAppMutex\n# winget: # Uncomment to configure the manifests of the windows-winget package\n#   installer-url: \"https://example.com/releases/{{\"{{\"}}.version{{\"}}\"}}/app.msi\" # URL the msi is published at, with the template data\n#   publisher: My Company # defaults to the author of pubspec.yaml\n#   package-identifier: MyCompany.MyApp # defaults to <publisher>.<application name>\n# homebrew: # Uncomment to configure the cask of the darwin-cask package\n#   url: \"https://example.com/releases/{{\"{{\"}}.version{{\"}}\"}}/app.dmg\" # URL the darwin-dmg is published at, with the template data\n#   homepage: https://example.com\n# appstore: # Uncomment to sign the darwin-appstore package submitted to the Mac App Store\n#   provisioning-profile: go/packaging/MyApp.provisionprofile\n#   application-identity: \"3rd Party Mac Developer Application: My Company (ABCDE12345)\"\n#   installer-identity: \"3rd Party Mac Developer Installer: My Company (ABCDE12345)\"\n#   category: public.app-category.productivity # defaults to public.app-category.utilities\n# scoop: # Uncomment to configure the manifest of the windows-scoop package\n#   url: \"https://example.com/releases/{{\"{{\"}}.version{{\"}}\"}}/app.zip\" # URL the windows-portable zip is published at, with the template data\n#   homepage: https://example.com\n# windows-services: # Uncomment to install windows services with the windows-msi package\n#   - name: MyAppDaemon\n#     executable: myapp-daemon.exe # file of the build output, e.g. copied to go/build/intermediates/windows\n#     account: LocalService # LocalSystem (default), LocalService, NetworkService or a user account\n#     start: auto # auto, demand or disabled\n#     recovery:\n#       actions: [restart, restart, none]\n# launchd: # Uncomment to install launchd agents or daemons with the darwin-pkg package\n#   - label: com.example.myapp.helper\n#     type: agent # agent or daemon\n#     program: myapp-helper # file of the build output, defaults to the app\n#     run-at-load: true\n# survey: # Uncomment to open a page on the first launch of the app, and to request a page on uninstall (the installers and the linux packages, an uninstall script for darwin-pkg)\n#   opt-in: true\n#   first-run-url: \"https://example.com/welcome\"\n#   uninstall-url: \"https://example.com/uninstall-survey\"\n# license-policy: # Uncomment to change the dependency licenses flagged by `hover audit licenses`\n#   deny: [\"AGPL-3.0\", \"GPL-2.0\", \"GPL-3.0\", \"LGPL-2.1\", \"LGPL-3.0\", \"MPL-2.0\"] # the default, the copyleft licenses\n#   allow-unknown: false\n#   exceptions: [] # dependencies that were reviewed\n# webhooks: # Uncomment to notify webhooks when the builds start, succeed and fail\n#   - url: \"https://hooks.slack.com/services/...\"\n#     kind: slack # slack, discord or generic\n#     events: [start, success, failure]\n#     artifact-base-url: \"\" # base URL of the artifact links, the local paths are sent otherwise\n# signing: # Uncomment to sign the windows executable and packages with jsign, the credentials are read from the environment (see the README)\n#   provider: file # file, azure-key-vault, aws-kms or pkcs11\n#   keystore: \"\" # keystore file, key vault name, AWS region or PKCS#11 configuration file\n#   alias: \"\" # certificate name, KMS key id or PKCS#11 key label\n#   certificate: \"\" # certificate chain file, required for aws-kms\n#   timestamp-url: http://timestamp.digicert.com\n# post-build: # Uncomment to choose the post-build steps of a packaging format (e.g. windows-msi) or platform (e.g. windows), in the order strip, sign, package, sign-installer, notarize, staple\n#   windows-msi: [package, sign-installer] # only the installer is signed\n#   darwin-dmg: [strip, package, notarize, staple]\n# retry: # Uncomment to change the retries of the downloads, uploads, notarizations and packaging tools failing on network errors\n#   attempts: 3 # 1 disables the retries\n#   delay: 2s # doubled after each failed attempt\n#   max-delay: 1m\n# provenance: # Uncomment to sign the attestations of `hover build --provenance` and upload them to a Rekor transparency log\n#   key: \"\" # PEM ECDSA or Ed25519 private key, HOVER_PROVENANCE_KEY (the content of the key) takes precedence\n#   rekor: https://rekor.sigstore.dev\n# nightly: # Uncomment to build and publish a nightly channel with `hover nightly`, installed next to the stable one\n#   application-name: \"\" # defaults to the application name followed by \" Nightly\"\n#   executable-name: \"\" # defaults to the executable name followed by \"-nightly\"\n#   package-name: \"\" # defaults to the package name followed by \"-nightly\", the identifier of the app\n#   builds: [linux-deb, linux-snap, windows-msi]\n#   arches: [amd64]\n#   destination: s3://my-bucket/nightly # uploaded like `hover publish`\n#   snap-channel: edge # the linux-snap is released to this channel of the Snap Store\n#   github-release: me/my-app # the artifacts are uploaded to a GitHub pre-release of this repository\n# integrity: # Uncomment to write a manifest of the hashes of the build output to the builds and packages, checked by go/cmd/integrity.go when the app starts\n#   manifest: true\n#   key: \"\" # PEM ECDSA or Ed25519 private key signing the manifest, HOVER_INTEGRITY_KEY (the content of the key) takes precedence\n# size-budgets: # Uncomment to fail the builds whose artifacts or parts of the build output exceed their size\n#   artifacts: # by packaging format (e.g. linux-deb) or platform (e.g. windows)\n#     linux-deb: 60MB\n#   components: # by path relative to the build output\n#     flutter_assets: 40MB\n#   warn: false # only warn when a budget is exceeded\n# template-data: # Uncomment to add values to the template data of the packaging templates, e.g. {{\"{{\"}}.homepage{{\"}}\"}}\n#   homepage: https://example.com\n# run: # Uncomment to add run profiles, selected with `hover run --profile <name>`\n#   staging:\n#     target: lib/main_staging.dart\n#     args: [--verbose] # command-line arguments of the app\n#     env:\n#       API_URL: https://staging.example.com\n#     dart-defines:\n#       FLAVOR: staging\n#     window-size: 1280x800 # applied by go/cmd/runprofile.go\n"),
 	}
-	file7 := &embedded.EmbeddedFile{
+	file9 := &embedded.EmbeddedFile{
 		Filename:    "app/icon.png",
 		FileModTime: time.Unix(1571249485, 0),
 
 		Content: string("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x01\x00\x00\x00\x01\x00\b\x06\x00\x00\x00\\r\xa8f\x00\x00\x0f\x93IDATx\x9c\xed\xddk\x93\x1cU\x19\xc0\xf1\xfd\x14\x99\xe5\xf2\xc6J6\xf1\x82%$\x90\x9d\x0e \x97@Hvg6\xbb\v!\xe4\x86\x10\b\x97\xd2\x0f \xc5E\x04/\x94R(J\xc2\xc6@.@@߈VYR\xa5\\\x04\xa4Pdfvg\x92\r\xa5oԲ\xac\x12- \x90d\x03\x99\xde\xc7\x17\t!\xc9\xce\xf4L\xf7\xf4\xe9s\x9e\xd3\xff\u007f\xd5y\xbfs*\xcfo\xceL\xf7t\xfa\xfahN\xc5)\x19\x1c\xac\xc9ն\xff\x0eJ\xa7`R.\b\xaa\xa1x\xbc\ued7d\xc7\xde\x14L\xcaҠ\x1a~\x14\xd4d\xd4\xf6\xdfB\xe9\x04\x00\xd4U'\xfe\xa1|\x14TC\x01\x00\u007f\x02\x00\xeaX0)\x17\x14+\xe1\a'7\x15\x00\xbc\t\x00(\xb2eSr\xdei\xc3\x0f\x00^\x05\x00Զ\x8bjr~P\rߛ\xb3\xa9\x00\xe0M\x00@-+N\xc9\xe2b%<\xd8rS\x01\xc0\x9b\x00\x80\xe6T\x9c\x92\xc1b5<\xd4vS\x01\xc0\x9b\x00\x80NkpR.)V\xc2#\x91\x9b\n\x00\xde\x04\x00t\xb2\xa5\x15\xb92\xa8\x86G;n*\x00x\x13\x00P____\xdf`M\x86\xba\xdeT\x00\xf0&\x00\xa0\xbe\xa0\"\xd7\x14\xab\xe1\xc7\x00\x90\xbf\x00 \xe7\rN\xca\x15\xb1\x86\x1f\x00\xbc\n\x00r\\\xb1&\x17\a\x95p&\xf6\xa6\x02\x807\x01@N+\xd6\xe4\xe2b%<\x9chS\x01\xc0\x9b\x00 \x87\x9d\x18\xfe\xe8K}\x00\x90\x8b\x00 g\x05U\xb9\xac\xa7\xe1\a\x00\xaf\x02\x80\x1cU\xac\xcaUA7\xd7\xf9\x01 7\x01@N\x1a\xac\xc9P\xb1\x12~\x92ʦ\x02\x807\x01@\x0e\nj2\x1aT\xc2c\xa9m*\x00xӉ_|\xda\x1eRc\xabX\rﱽ\xc7V+V\xa4\x1cT\xc2f\xaa\x1b\v\x00\xde\xc4\t\xc0り\\\x93\xea;?\x00x\x17\x00xZ\xec\xdb{\x01 \x97\x01\x80\x87%\xba\xbd\x17\x00r\x19\x00xV\xb1&\x97\x1b\xdfT\x00\xf0&\x00\xf0\xa8Ԯ\xf3\x03@n\x02\x00OJ\xf5:?\x00\xe4&\x00\xf0\xa0ԯ\xf3\x03@n\x02\x00\xe5\x15'\x9b\xd7g\xbe\xa9\x00\xe0M\x00\xa0\xb8\xc1\x9a\xacN\xfd&\x1f\x00\xc8U\x00\xa0\xb4bUVez\xec\a\x00/\x03\x00\x85\x05UYn\xf4:?\x00\xe4&\x00PV&\xd7\xf9\x01 7\x01\x80\xa22\xbb\xce\x0f\x00\xb9\t\x00\x94\x94\xe9u~\x00\xc8M\x00\xa0\xa0̯\xf3\x03@n\x02\x00ǳv\xa9\x0f\x00r\x11\x008\\P\x95\xe5N\xbd\xf3\x03\x80w\x01\x80\xa3-\xadȥ\x89\xfe\xd3\x0e\x00\xa0\x18\xf1H0\a[Z\x95\xa0\xe7Gw\x03\x00u\x11'\x00\xc7ZZ\x95 \xa8\x86\x1f9\xb0q\x00\x90\x83\x00\xc0\xa1R\xf9O;\x00\x80b\x04\x00\x8eT\xac\xca*\a6\v\x00r\x16\x008\x90S7\xf9\x00@\xae\x02\x00\xcb9w\x93\x0f\x00\xe4*\x00\xb0X\xb1\xd6\xdc\xe0\xc0\x06\x01@\x8e\x03\x00K\rV\x9b\x9b\x8a\xd5pց\r\x02\x80\x1c\a\x00\x16*֚[\x1c\xd8\x18\x00 \x00ȺbE\xc6վ\xf3\x03\x80w\x01@\x86\r\xd6\xe4ju_\xf8\x01\x80\xd7\x01@F-\xadȕ*n\xf2\x01\x80\\\x05\x00\x19\xe4̓|\x00\x80\xce\b\x00LopEJ\x0el\x02\x00P\xcb\x00\xc0`\xea\xee\xf0\x03\x80\xdc\x05\x00\x86\xf2\xe6\v?\x00\xf0:\x000\xd0`U\xbe\xe8\xc0\v\a\x00\xea\x18\x0f\x041PPk\xae\xb7\xfd\xc2\x01\x80\xba\x89\x13\x80\x89M\x05\x00R\x12\x00\x98\xd8T\x00 %\x01\x80\x89M\x05\x00R\x12\x00\x98\xd8T\x00 %\x01\x80\x89M\xad6\xd79\xf0\xc2\x01\x80:\x06\x00&6\x95\x13\x00)\t\x00Ll*\x00\x90\x92\x00\xc0Ħ\xfa\x0e@E\xc62\xdfT2\x92\xef7\x02\x01\x00\x00PD\x00` \x00 -\x01\x80\x81\xb8\n@Z\x02\x00\x03q\x02 -\x01\x80\x81\x00\x80\xb4\x04\x00\x06\x02\x00\xd2\x12\x00\x18\b\x00HK\x00` \x00 -\xf9\x0e\x00\x0f\x04\x01\x00\x8a\xc8w\x00\x02N\x00\x00@\xed\x03\x00\x03\x01\x00i\t\x00\f\x04\x00\xa4%\x000\x10\x00\x90\x96\x00\xc0@\x00@Z\x02\x00\x03\x01\x00i\t\x00\f\x04\x00\xa4%\x000\x10\x00\x90\x96\x00\xc0@\x00@Z\x02\x00\x03\xf1<\x00\xd2\x12\x00\x18\x88\x13\x00i\t\x00\f\x04\x00\xa4%\x000\x10\x00\x90\x96\x00\xc0@\x00@Z\x02\x00\x03\x01\x00i\xc9w\x00x\x1e\x00\x00PD\xbe\x03\x10p\x02\x00\x00j\x1f\x00\x18\b\x00HK\x00` \xef\x01\xe0F o\x02\x00\x03y\x0f\x00'\x00o\x02\x00\x03\x01\x00i\t\x00\f\x04\x00\xa4%\x000\x10\x00\x90\x96\x00\xc0@\x00@Z\x02\x00\x03\x01\x00i\t\x00\f\x04\x00\xa4%\x000\x10\x00\x90\x96\x00\xc0@\x00@Z\x02\x00\x03\xf1H0\xd2\x12\x00\x18\x88\x13\x00i\xc9w\x00\xf890\x00PD\xbe\x03\x10p\x02\x00\x00j\x1f\x00\x18\b\x00HK\x00` \x00 -\x01\x80\x81\x00\x80\xb4\x04\x00\x06\x02\x00\xd2\x12\x00\x18hѯ\xde\xfd\xf9\x97^mJ\x1a\xeb\xbc\xd7\xdc[\x03\xdb\xf7\xffc\xde\xe6\xa7^\xcfr\x15ny\xfa\x8f\xb6\xd7Y\xb7\xba\xb1\xce\xde\xf2Lj뜯\xff\xa2\xf6\xb9{_\x10\x1f\xd7\xfco\xff^\x96\xfd\xee\xdd\x1fg\x0e\xc0\xc0\xf3\xffyq\xd1ˡ\xa4\xb1>\xef\xc0\xfa\xc2\x19k\xd1DC\n\xb7<\x9d\xe9\xea\xbf\xd5\xfe:k\xcb3\xd6\xd7\xd9\x0e\xadsnsw\x9d{\xe7s\xf2\xd5\xdf\xfe[\x86\xeaკ\x03\xb0\xf0\xf9\xff\xfa\r\xc0\xf6}\xb9\x1b~\x00\xd0\x03\xc0\xa7\xc3_n\x84\xb6\x00\xf0\xfc\x04\x90C\x00l\x0f\xbek\x00\xd8\x1e\xf2n\x86\xdf\x1a\x00>}\x048s\xf8m|\x04\xb0=\xfc\xae\x00`{\xe8]\a\xe0\xdc;\x9f\x93\xcb^\xf8l\xf8-\x9e\x00\xfc\xf9\b\xd0\x12\x00N\x00\xb9\x06\xc0\xf6\xa0w\xf3\xce\x0f\x00\x00\x00\x009\x01\xa0\xdd\xf0\xab\xff\b`{\xf8\xf9\b\xc0\xf0k\x1e\xfer#\x94U\xf5\xf0\x01\x00P\f\x80\xed\xe1\a\x00\x87\x01\xb8\xe3\xd9\xc8\xe1\xe7\x04\x00\x00\x00\xe0+\x00w<+\x97\xfe\xe6_\x91ï\xfe;\x00\xdb\xc3\xdf\x16\x80\f\xbf\x03\xb0=\xfc\x00\xa0w\xf89\x01\x18\x18\xfe\xbc\x9d\x00l\x0f\xbe+\xc3\xef\f\x00'\x86\xbfT\xef<\xfc\x00`\b\x80\x85\x8f\xd7e\xde\xe6\xa7R]\x00\x00\x00i\x0f?\x1f\x01L\x010\xd1\x00\x80\x9c\x01\xa0q\xf89\x01\x18\x02`Ѷ))ܼ'\xbd\xd5\x06\x00\xdb\xc3\x0f\x00\x8e\x00p\xca\xf0\xab\x00 \xad\xdf\x02\xb8\n\xc0\xc2mSR\xb8iOz\xcb\xd1w\u007f\x17\x00\xb0=\xf8\xd6\x01\xe8a\xf8U\x9f\x00l\x0f\u007f$\x00\x8fMʼ\x9b\xf6\xa4\xb3nv\xf7\xf8\x0f\x00n\r\xbf\x1a\x00\xd28\x01\xd8\x1e\xfeH\x00\xb6Nɼ\xaf\xedIeq\x05\x00\x00L\r?'\x00S\x00\xfctR\n7\xee\xee}E\x1c\xff\x01 \xc7\x00\xb4\x18~\x00pd\xf8\x8f\x03P\x93\u008d\xbbz\\\xbb\xdb~\xf9\a\x009\x06 \xc5\xe1\xe7#\x80)\x00~2)\x85M\xbb{[7E\x0f?\x00\xb8\x01\x80\vï\x0e\x00\xdfO\x00\x03\x8f\xd6d\xde\xc6]\xc9צ]\x1d\xdf\xfd\x01\xc0\xfe\xf0g\n@\xc4\xf0\xab\x03\xc0\xfb\x13\xc0\xa35)lܕ|u\xf8\xf2\x0f\x00r\x06\x80\xa1\xe1\xe7\x04`\n\x80\x1fU\xa4\xb0ag\xb2\xb5q\xa7\x146\xbb?\xfc\x00\x90\x11\x00\x1d\x86_%\x00\xbe\x9f\x00\x06z\x01\xe0\xc6\xdd*\xde\xfd\x01 \x03\x00\xba\x18~\x95\x00\xf8~\x02\x18x\xe4m\x99\xb7~g\xfc\xb5agW\x9f\xfd\x01\xc0\r\x00\xb4\x0f?\x00\x18\x02`\xe1#oKa\xfd\xce\xf8kSw\xef\xfe.\x00`{\xf8\xbd\x06\xa0\xcb\xe1W\v@\x1a\xbf\x06t\x19\x80\x81\x87\xff\"\x85\x1b\x9e\x8c\xb7\xd6=)\x85\x88\xdb~\x01 '\x00\xc4\x18~\xc5\x00\xf8}\x02\x18x\xf8-)\xac{\"\xdeڸ\xab\xeb\xe1\a\x00O\x01\xc8x\xf8\xf9\b`\n\x80\x1f\xbe%\x85\xb5O\xc4[1\xde\xfd\x01\xc0\xfe\xf0\xa7\x0e@\xcc\xe1\a\x00\x97\x01\xf8AL\x006\xc4{\xf7\a\x00\xfbß*\x00\xb7\xef\x8d=\xfc\xaa\x01\xf0\xfe#@\\\x00:\xfc\xe8\a\x00<\x06\xe0\xf6\xbdr\xe9\xaf\xff\x19{\xf8U\x03\xe0\xfd\t\xe0\xa1?I\xe1\xfa\x1dݭ\xf5;c\x0f?\x000\xfci\x01`\xe5?\x06\xf1\xfd*\xc0\x82\x87\xfe,\x855;\xba[\t\xde\xfd\x01\xc0\x03\x00z\x1c\xfe\\\x9f\x00l\x0f\u007f\xc7\x13\xc0\xf7\xdf\xecn\xf8ox2\xd1\xf0\x03\x80r\x00R\x18~\xd5\x00\xf4\xfa\x1d\x80\xed\xe1\xef\b\xc0\xf7ޔ\xc2u;:\xaf.\u007f\xf4\x03\x00\x1e\x01\x90\xd2\xf0\xab\x06\xc0\xf7\x13\xc0\x82\xef\xbe!\x85k\u007f\x16\xbd\xd6>\x91x\xf8\x01@)\x00\x8e\r?\x00\x18\x05`{\xf4\xea\xf2G?\x00\xe0\t\x00)\x0e\xbfz\x00z\xfd\x12\xd0\xf6\xf0w\x04\xe0;oH\xff\xf8D\xdbUX\xb3\xa3\xa7\xe1\a\x00e\x00\xa4<\xfc\x00\xe0\xc0\x8a\x04\xe0\xc17\xa40\xb6\xbd\xfd\x8a\xf1\xa3\x1f\x00P\x0e\x80\x81\xe1W\x0f\x80\xf7\x1f\x01\x1ex]\nc\x13\xadW\n\xef\xfe\x00\xa0\x04\x00C\xc3\x0f\x00\x0e\xac\x8e\x00\x8cN\xb4^\x9b\xe2\xdf\xf6\v\x00\n\x0108\xfc\xea\xaf\x02,\xf2\x1d\x80\xfb_\x97\xfeՏ\xcf]\xe3\xdbS\x19~\x00p\x1c\x80\f\x86_5\x00\xbe\xdf\n\xbc\xe0\xfeפ\xb0zb\xeeJ\xf0\xa3\x1f\x00P\x06@Fß\x16\x00Vn\x05\xf6\x1e\x80o\xb5\x00`l{W\x0f\xfb\x04\x00\xc5\x00d8\xfc\xaaO\x00\xbe\xff\x1ap\xfe}\xafJ\xa1\xfc\xf8\xe9kC\xb2\x1f\xfd\x00\x80\x9b\x00\xccA \xe3\xe1W\r\x80\xf7'\x80\xfb\xfe \xfd#\xdb>[c\x13\xa9\xbe\xfb\x03\x80\xfd\xe1?\r\x00\v\xc3\x0f\x00\x0e\x030\xff\x9eW\xa4\xbf\xb4\xf5\xe4*\xacO\xfe\xa3\x1f\x00p\x1c\x00K\xc3\x0f\x00\x8e\x03P(m;\xbeF&\xba~\xd47\x00\xe8B\xc0\xe6\xf0\xab\x06\xc0\xf7\xe7\x01̿\xfb\x15)\x94\xb6\x1e_=\xfc\xe4\x17\x00\x1c\x06\xe06\xbbß\x16\x02\x00`\x00\x80\x05w\xbf\"\xfd\xc3[\xa5\xbf\xbc\xcdȻ?\x00X\x06ලr\x89\x03ï\x16\x00\xef?\x02\xdc\xf5\xa2\xf4\x0f=\xd6\xf3O~]\x06\xc0\x05\x04l\x0e\xff\xb0\x03ï\x16\x00\xdfO\x00\xf3\xefzI\n\xc3[c?\xea\x1b\x00\x1c\a\xe0\x94\xe1\a\x00\x00h\x0f\xc07_:\xfe\xc0OC\xc3\x0f\x006\x008}\xf8}A\x80;\x01M\x00p\xf7\xcbF\xdf\xfd]A ?\x00\xec\x95e\xbf\xfc\xfb\x9c\xe1\xf7\x01\x00\xbe\x030\x00\xc0\xc0#o\x1b\x1f~\x00\xc8f\xf8\xcf\xda\xf2L\xd8n\xf8]\x01\xa0\x17\x04\xac\x00\x10Ԛ\xeb\x83j(\xbe\xaebE\xc63\xdfT2\xd2\xf8>\xb9\xa8\xdd\xf0\x03@\xc2\x00\x80\xb4\xd4\t\x00W\x10\x00\x00\x87\x16\x00\xf8\x93\x16\x00\x92\"\x00\x00\x00@\x11\x01\x80\x81\x00\x80\xb4\xa4\t\x80$\b\x00\x00\x00PD\xdd\x00\xe0\x12\x02\x00\xe0\xc0\x02\x00\u007fZs@\x96h\x02 .\x02Vn\x04\x02\x00Ғ\xb6\x13@\\\x008\x01\x00\x00E\xd4-\x00Z\x11\x00\x00\x00\xa0\x88\xb4\x02\xd0-\x02\x00\x00\x00\x14\x11\x00\x18\b\x00HKq\x00Ј\x00\x00\x00\x00E\xa4\x1d\x80N\b\x00\x00\x00PD>\x00\x10\x85\x00\x00\x00\x00E\x14\x17\x00m\b\x00\x00\x00PD\x00` \x00 -%\x01@\x13\x02v\x00\xa86\xd7\xd9\x1eR\xa3\xab\"c\x99o*\x19\xc97\x00\xceD\x80\x13\x00'\x00\x8a()\x00\xae#\xf0)\x04\x00\x00\x00\x14\x91\xcf\x00\x94\xea\x00\x00\x00\x14Y/\x00h@\x00\x00\x00\x80\"\x02\x00\x03\x01\x00i\xa9\xdb\xe7\x01hE\x80\xe7\x01\x00\x00E4Ґ\v}\x06\x80\x13\x00\x00PDi\x00\xe02\x02\x00\x00\x00\x14QZ\x00\xb8\x8a\x00\x00\x18X\x83U\xb96\xf3M%#\x8d\xd6{\xff\x0e\x00\x00\xce\b\x00HKi\x02\xe0\"\x02\x00\x00\x00\x14\xd1\xf8\xb4,N\x13\x00\xd7\x10\xe0*\x00\x00PDi\x9f\x00\\\x03\x80\x13\x00\x00PD&\x00p\t\x01\x00\x00\x00\x8a\xc8\x14\x00\xae \x00\x00\x00@\x11\x99\x04\xc0\x05\x04\x00\x00\x00(\"\x000\x10\x00\x90\x96L\\\x05p\n\x81}R\xce|S\x01\x80\xb4d\xfa\x04`\x11\x81c\xa3\xfb\xe5z+\x9b\n\x00\xa4\xa5\xac\x00\xc8\x18\x81c\xab\x1b\x16\x1f[\a\x00\xa4\xa5,>\x02d\x8d@y\xca\xf2\xbfO\x00 -ey\x020\x8e@#<\xbazZ\x86l\xef)\x00\x90\x9a\xb2>\x01\x18C\xa01;3\xb2_V\xda\xdeϾ\xbe>\x00 =\xd98\x01\x18@\xa092-ö\xf7\xf2d\x00@Z\xb2\t@\x1a\b\f\xd7\xc3١Fs\x93\xed}<-\x00 -\xd9\xfa\b\x90\x1e\x02\xf2\r\xdb{8'\x00 -\xd9>\x01$E`\xa8\x1eή\x98ln\xb6\xbd\u007f-\x03\x00Ғ\v'\x80$\b\\5ټ\xd9\xf6\u07b5\r\x00HK\xae\x9c\x00b@\xd0\\Qon\xb0\xbdo\x91\x01\x00i\xc9E\x00\"\x1086\\\x975\xb6\xf7\xacc\x00@Zr\x15\x80V\bX\xbd\xbd7N\x00@Zr\x19\x80\x93\x104£\xd75\xa4d{\xaf\xba\x0e\x00HK\xee\x030{dlZ\xae\xb6\xbdO\xb1\x02\x00Ғ\xdb\x00\xcc\x1e\x19\u007fG\xae\xb0\xbdG\xb1\v\xaa\xcdu\xb6\x87\xd4\xe8\xaa(\xf9,F\x1ds\x16\x80\xc6\xec\xcc\xe8~Yn{\u007f\x12\xc5\t\x80\xb4\xe4(\x00ǜ\xf9aO\x92\x00\x80\xb4\xe4\x1c\x00SasՔ\\g{_z\n\x00HK\x8e\x01p\xacܰ\xf4\x18\xaf4\x03\x00Ғ+\x00\f\xd5\xc3O\xcau\x19\xb5\xbd\x1f\xa9\x04\x00\xa4\xa5Ѻ,\x19\xb2\r\x80+O\xf2I+\x00 -}\n\x805\x04\x8e\u007fۿ\xca\xf6>\xa4\x1a\x00\x90\x96N\x05\xc0\x02\x02\x87\xcb\xfb\xe5\x12\xdb{\x90z\x00@Z:\x13\x80\xac\x10(\xd5\xc3\x0fǧ\xa5h\xfb\xf5\x1b\t\x00HK6\x00(\xd7\xc3\x0fnxG.\xb4\xfdڍ\x05\x00\xa4\xa5\xf1iY|&\x00&\x11(\xd7\xc3\xf7\xd7\x1c\x90%\xb6_\xb7\xd1\x00\x80\xb4\xd4\xea\x04`\n\x81R=<\xb8vZ\x16\xdb~\xcd\xc6\x03\x00\xd2R\x14\x00)#pht\xbf,\xb5\xfdz3\t\x00HK\x9d\x00H\a\x82\xd9\xc3#\xfb\xe4bۯ5\xb3\x00\x80\xb4\xd4-\x00\x89\x11h\xccάj\xc8\nۯ3\xd3\x00\x80\xb4\x14\a\x80\x04\b\xf8y\x9d\xbfS\x00@Z\x8a\v@\xb7\bx}\x9d\xbfS\x00@ZJ\x02@'\x04\xbc\xbf\xce\xdf)\x00 -\xb5\xbb\x0f )\x02\xe5\xc6\xec\xff\xd6\xfb~\x9d\xbfS<\x12\x8c\xb4\x94\xf4\x04\xd0\n\x81R#|o\xcd\x01\xf9\xb2\xed\xd7d=N\x00\xa4\xa5^\x018\x05\x82\x83\xa3\u007f\x95\xf3m\xbf\x1e'\x02\x00\xd2R\x1a\x00\f\xd7Ã\xb9\xb9ɧ\x9b\x00\x80\xb4\x94\xc2G\x80Ck\xf3\xfam\u007f\xbb\x00\x80\xb4\xd4\v\x00\xc3\xf5\xd9#c\a\xe42ۯ\xc1\xb9\x00\x80\xb4\x94\xfc2\xe0\xeca\x95\xffiG\x16\x01\x00i)!\x003\xa5\x86\\n\xfbow6\x00 -ž\x15\xb8\x11άh(\xfb\xbf\xfa\xb2\x0e\x00HK1\u007f\f\xf4q\xf9@\xce~ؓ$\x00 -\xc5\x01\xa0\\\x97ն\xff^\x15\x01\x00i\xa9\x1b\x00\x86\x1b\xe1ѕ\x93r\x8d\xed\xbfUM\x00@Z\xea\b\xc0\xd4쑕|\xe1\x17/\x00 -u\x00\xe0\xc3Uyz\x92OZ\x01\x00i\xa9\x1d\x00\xc3\xf5\xf0\x83\x91\xbf\xc9\x05\xb6\xff>\x95\x01\x00i\xa9\x15\x00\xa5z\xf8\xfeH#ǿ\xe7\xef5\x00 -\xb5\x00\xe0\x90\xf7\xcf\xed7\x1d\x00\x90\x96N\xff\xcfAg\x0f\xf3\x99?\x85\x00\x80\xb4t\xf2\xbf\ao\x843\xa5)Yn\xfb\xef\xf1\"\x00 -\x8d\xd6e\xc9p=\xfcxhJV\xda\xfe[\xbc\t\x00HK\xa5}\xf2\x95\x95\r\x1e\xf1֪\xff\x03ɉ>)\x8dx\xe1\xbb\x00\x00\x00\x00IEND\xaeB`\x82"),
 	}
-	file8 := &embedded.EmbeddedFile{
+	fileds := &embedded.EmbeddedFile{
+		Filename:    "app/integrity.go",
+		FileModTime: time.Unix(1792033704, 0),
+
+		Content: string("package main\n\nimport (\n\t\"crypto/ecdsa\"\n\t\"crypto/ed25519\"\n\t\"crypto/sha256\"\n\t\"crypto/x509\"\n\t\"encoding/asn1\"\n\t\"encoding/base64\"\n\t\"encoding/hex\"\n\t\"encoding/json\"\n\t\"fmt\"\n\t\"io\"\n\t\"io/ioutil\"\n\t\"math/big\"\n\t\"os\"\n\t\"path/filepath\"\n\t\"sort\"\n\t\"strings\"\n\n\t\"github.com/pkg/errors\"\n)\n\n// integrityCheck is set by hover at compile-time when integrity.manifest is\n// enabled in hover.yaml, and integrityPublicKey to the base64 of the public\n// key signing the manifest when integrity.key is set. The files next to the\n// executable are checked against integrity.json when the app starts, so a\n// corrupted or tampered install is reported instead of crashing later.\nvar (\n\tintegrityCheck     string\n\tintegrityPublicKey string\n)\n\nfunc init() {\n\tif integrityCheck != \"true\" {\n\t\treturn\n\t}\n\terr := checkIntegrity()\n\tif err != nil {\n\t\tfmt.Printf(\"the installation of the app is damaged, reinstall it: %v\\n\", err)\n\t\tos.Exit(1)\n\t}\n}\n\nfunc checkIntegrity() error {\n\texecPath, err := os.Executable()\n\tif err != nil {\n\t\treturn errors.Wrap(err, \"failed to resolve executable path\")\n\t}\n\texecPath, err = filepath.EvalSymlinks(execPath)\n\tif err != nil {\n\t\treturn errors.Wrap(err, \"failed to eval symlinks for executable path\")\n\t}\n\tdir := filepath.Dir(execPath)\n\tdata, err := ioutil.ReadFile(filepath.Join(dir, \"integrity.json\"))\n\tif err != nil {\n\t\treturn err\n\t}\n\tif integrityPublicKey != \"\" {\n\t\terr = verifyIntegritySignature(data, filepath.Join(dir, \"integrity.json.sig\"))\n\t\tif err != nil {\n\t\t\treturn err\n\t\t}\n\t}\n\tvar manifest struct {\n\t\tFiles map[string]string `json:\"files\"`\n\t}\n\terr = json.Unmarshal(data, &manifest)\n\tif err != nil {\n\t\treturn errors.Wrap(err, \"invalid integrity.json\")\n\t}\n\tvar damaged []string\n\tfor name, sum := range manifest.Files {\n\t\tactual, err := sha256File(filepath.Join(dir, filepath.FromSlash(name)))\n\t\tif err != nil || actual != sum {\n\t\t\tdamaged = append(damaged, name)\n\t\t}\n\t}\n\tif len(damaged) > 0 {\n\t\tsort.Strings(damaged)\n\t\treturn errors.Errorf(\"%s missing or modified\", strings.Join(damaged, \", \"))\n\t}\n\treturn nil\n}\n\n// verifyIntegritySignature verifies the Ed25519 signature of the manifest, or\n// the ECDSA signature of its sha256.\nfunc verifyIntegritySignature(data []byte, signaturePath string) error {\n\tder, err := base64.StdEncoding.DecodeString(integrityPublicKey)\n\tif err != nil {\n\t\treturn errors.Wrap(err, \"invalid public key\")\n\t}\n\tpublicKey, err := x509.ParsePKIXPublicKey(der)\n\tif err != nil {\n\t\treturn errors.Wrap(err, \"invalid public key\")\n\t}\n\tencoded, err := ioutil.ReadFile(signaturePath)\n\tif err != nil {\n\t\treturn err\n\t}\n\tsig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))\n\tif err != nil {\n\t\treturn errors.Wrap(err, \"invalid integrity.json.sig\")\n\t}\n\tvar valid bool\n\tswitch publicKey := publicKey.(type) {\n\tcase ed25519.PublicKey:\n\t\tvalid = ed25519.Verify(publicKey, data, sig)\n\tcase *ecdsa.PublicKey:\n\t\tvar rs struct{ R, S *big.Int }\n\t\tif _, err := asn1.Unmarshal(sig, &rs); err == nil {\n\t\t\tdigest := sha256.Sum256(data)\n\t\t\tvalid = ecdsa.Verify(publicKey, digest[:], rs.R, rs.S)\n\t\t}\n\t}\n\tif !valid {\n\t\treturn errors.New(\"integrity.json isn't signed by the publisher of the app\")\n\t}\n\treturn nil\n}\n\nfunc sha256File(path string) (string, error) {\n\tf, err := os.Open(path)\n\tif err != nil {\n\t\treturn \"\", err\n\t}\n\tdefer f.Close()\n\th := sha256.New()\n\t_, err = io.Copy(h, f)\n\tif err != nil {\n\t\treturn \"\", err\n\t}\n\treturn hex.EncodeToString(h.Sum(nil)), nil\n}\n"),
+	}
+	filea := &embedded.EmbeddedFile{
 		Filename:    "app/main.go",
 		FileModTime: time.Unix(1571249485, 0),
 
 		Content: string("package main\n\nimport (\n\t\"fmt\"\n\t\"image\"\n\t_ \"image/png\"\n\t\"os\"\n\t\"path/filepath\"\n\t\"strings\"\n\n\t\"github.com/go-flutter-desktop/go-flutter\"\n\t\"github.com/pkg/errors\"\n)\n\n// vmArguments may be set by hover at compile-time\nvar vmArguments string\n\nfunc main() {\n\t// DO NOT EDIT, add options in options.go\n\tmainOptions := []flutter.Option{\n\t\tflutter.OptionVMArguments(strings.Split(vmArguments, \";\")),\n\t\tflutter.WindowIcon(iconProvider),\n\t}\n\terr := flutter.Run(append(options, mainOptions...)...)\n\tif err != nil {\n\t\tfmt.Println(err)\n\t\tos.Exit(1)\n\t}\n}\n\nfunc iconProvider() ([]image.Image, error) {\n\texecPath, err := os.Executable()\n\tif err != nil {\n\t\treturn nil, errors.Wrap(err, \"failed to resolve executable path\")\n\t}\n\texecPath, err = filepath.EvalSymlinks(execPath)\n\tif err != nil {\n\t\treturn nil, errors.Wrap(err, \"failed to eval symlinks for executable path\")\n\t}\n\timgFile, err := os.Open(filepath.Join(filepath.Dir(execPath), \"assets\", \"icon.png\"))\n\tif err != nil {\n\t\treturn nil, errors.Wrap(err, \"failed to open assets/icon.png\")\n\t}\n\timg, _, err := image.Decode(imgFile)\n\tif err != nil {\n\t\treturn nil, errors.Wrap(err, \"failed to decode image\")\n\t}\n\treturn []image.Image{img}, nil\n}\n"),
 	}
-	file9 := &embedded.EmbeddedFile{
+	fileb := &embedded.EmbeddedFile{
 		Filename:    "app/main_desktop.dart",
 		FileModTime: time.Unix(1587299806, 0),
 
 		Content: string("import 'package:flutter/foundation.dart'\n    show debugDefaultTargetPlatformOverride;\nimport 'package:flutter/material.dart';\n\nimport 'main.dart' as original_main;\n\nvoid main() {\n  debugDefaultTargetPlatformOverride = TargetPlatform.fuchsia;\n  original_main.main();\n}\n"),
 	}
-	filea := &embedded.EmbeddedFile{
+	filec := &embedded.EmbeddedFile{
 		Filename:    "app/options.go",
 		FileModTime: time.Unix(1571249485, 0),
 
 		Content: string("package main\n\nimport (\n\t\"github.com/go-flutter-desktop/go-flutter\"\n)\n\nvar options = []flutter.Option{\n\tflutter.WindowInitialDimensions(800, 1280),\n}\n"),
 	}
-	filec := &embedded.EmbeddedFile{
+	filecb := &embedded.EmbeddedFile{
+		Filename:    "app/preferences.go",
+		FileModTime: time.Unix(1792029710, 0),
+
+		Content: string("package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n\t\"os/exec\"\n\t\"path/filepath\"\n\t\"runtime\"\n\t\"strings\"\n\n\t\"github.com/go-flutter-desktop/go-flutter\"\n\t\"github.com/go-flutter-desktop/go-flutter/plugin\"\n\t\"github.com/pkg/errors\"\n)\n\n// preferencesID is set by hover at compile-time to <organization>.<package>:\n// the GSettings schema on linux, the defaults domain on darwin and the\n// registry key in HKEY_CURRENT_USER\\Software on windows. The packages install\n// the defaults of the preferences of hover.yaml: the GSettings schema of the\n// linux packages, Defaults.plist in the darwin bundle and the Defaults subkey\n// in the windows-msi package.\nvar preferencesID string\n\nfunc init() {\n\tif preferencesID == \"\" {\n\t\treturn\n\t}\n\toptions = append(options, flutter.AddPlugin(&preferencesPlugin{}))\n}\n\n// preferencesPlugin reads and writes the preferences of the app from dart,\n// with the methods get ({'key': 'theme'}) and set ({'key': 'theme', 'value':\n// 'dark'}) of the hover/preferences channel. The values are strings, get\n// returns null for the preferences without value.\ntype preferencesPlugin struct{}\n\nfunc (p *preferencesPlugin) InitPlugin(messenger plugin.BinaryMessenger) error {\n\tchannel := plugin.NewMethodChannel(messenger, \"hover/preferences\", plugin.StandardMethodCodec{})\n\tchannel.HandleFunc(\"get\", p.handleGet)\n\tchannel.HandleFunc(\"set\", p.handleSet)\n\treturn nil\n}\n\nfunc (p *preferencesPlugin) handleGet(arguments interface{}) (interface{}, error) {\n\targs, _ := arguments.(map[interface{}]interface{})\n\tkey, _ := args[\"key\"].(string)\n\tif key == \"\" {\n\t\treturn nil, errors.New(\"missing key\")\n\t}\n\tvar out []byte\n\tvar err error\n\tswitch runtime.GOOS {\n\tcase \"linux\":\n\t\tout, err = exec.Command(\"gsettings\", \"get\", preferencesID, key).Output()\n\tcase \"darwin\":\n\t\tout, err = exec.Command(\"defaults\", \"read\", preferencesID, key).Output()\n\t\tif err != nil {\n\t\t\t// the app bundle has the defaults in Contents/Resources/Defaults.plist\n\t\t\tvar execPath string\n\t\t\texecPath, err = os.Executable()\n\t\t\tif err == nil {\n\t\t\t\tdefaultsPath := filepath.Join(filepath.Dir(execPath), \"..\", \"Resources\", \"Defaults\")\n\t\t\t\tout, err = exec.Command(\"defaults\", \"read\", defaultsPath, key).Output()\n\t\t\t}\n\t\t}\n\tcase \"windows\":\n\t\t// the package installs the defaults in a subkey, the upgrades don't\n\t\t// reset the values of the user\n\t\tif value, ok := regQuery(`HKCU\\Software\\`+preferencesID, key); ok {\n\t\t\treturn value, nil\n\t\t}\n\t\tif value, ok := regQuery(`HKCU\\Software\\`+preferencesID+`\\Defaults`, key); ok {\n\t\t\treturn value, nil\n\t\t}\n\t\treturn nil, nil\n\t}\n\tif err != nil {\n\t\t// the key has no value\n\t\treturn nil, nil\n\t}\n\tvalue := strings.TrimSpace(string(out))\n\t// a GVariant on linux, the strings are quoted\n\tif runtime.GOOS == \"linux\" && len(value) >= 2 && value[0] == '\\'' && value[len(value)-1] == '\\'' {\n\t\tvalue = strings.NewReplacer(`\\'`, `'`, `\\\\`, `\\`).Replace(value[1 : len(value)-1])\n\t}\n\treturn value, nil\n}\n\n// regQuery reads a value of a registry key with reg.exe.\nfunc regQuery(registryKey, name string) (string, bool) {\n\tout, err := exec.Command(\"reg\", \"query\", registryKey, \"/v\", name).Output()\n\tif err != nil {\n\t\treturn \"\", false\n\t}\n\t// <name>    REG_SZ    <value>\n\tfor _, line := range strings.Split(string(out), \"\\n\") {\n\t\tfields := strings.SplitN(strings.TrimSpace(line), \"    \", 3)\n\t\tif len(fields) == 3 && fields[0] == name {\n\t\t\treturn strings.TrimSpace(fields[2]), true\n\t\t}\n\t}\n\treturn \"\", false\n}\n\nfunc (p *preferencesPlugin) handleSet(arguments interface{}) (interface{}, error) {\n\targs, _ := arguments.(map[interface{}]interface{})\n\tkey, _ := args[\"key\"].(string)\n\tif key == \"\" {\n\t\treturn nil, errors.New(\"missing key\")\n\t}\n\tvalue := fmt.Sprint(args[\"value\"])\n\tvar cmd *exec.Cmd\n\tswitch runtime.GOOS {\n\tcase \"linux\":\n\t\t// the strings must be quoted, the type is in the schema\n\t\tout, err := exec.Command(\"gsettings\", \"range\", preferencesID, key).Output()\n\t\tif err != nil {\n\t\t\treturn nil, errors.Wrapf(err, \"unknown preference %s\", key)\n\t\t}\n\t\tif strings.TrimSpace(string(out)) == \"type s\" {\n\t\t\tvalue = \"'\" + strings.NewReplacer(`\\`, `\\\\`, `'`, `\\'`).Replace(value) + \"'\"\n\t\t}\n\t\tcmd = exec.Command(\"gsettings\", \"set\", preferencesID, key, value)\n\tcase \"darwin\":\n\t\tcmd = exec.Command(\"defaults\", \"write\", preferencesID, key, value)\n\tcase \"windows\":\n\t\tcmd = exec.Command(\"reg\", \"add\", `HKCU\\Software\\`+preferencesID, \"/v\", key, \"/d\", value, \"/f\")\n\tdefault:\n\t\treturn nil, errors.Errorf(\"preferences aren't supported on %s\", runtime.GOOS)\n\t}\n\tout, err := cmd.CombinedOutput()\n\tif err != nil {\n\t\treturn nil, errors.Errorf(\"failed to set the preference %s: %s\", key, strings.TrimSpace(string(out)))\n\t}\n\treturn nil, nil\n}\n"),
+	}
+	filec1 := &embedded.EmbeddedFile{
+		Filename:    "app/runprofile.go",
+		FileModTime: time.Unix(1792180000, 0),
+
+		Content: string("package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n\n\t\"github.com/go-flutter-desktop/go-flutter\"\n)\n\n// HOVER_RUN_WINDOW_SIZE is set by `hover run --profile` to the window-size of\n// the run profile of hover.yaml, overriding the initial dimensions of\n// options.go.\nfunc init() {\n\tsize := os.Getenv(\"HOVER_RUN_WINDOW_SIZE\")\n\tif size == \"\" {\n\t\treturn\n\t}\n\tvar width, height int\n\t_, err := fmt.Sscanf(size, \"%dx%d\", &width, &height)\n\tif err != nil {\n\t\tfmt.Printf(\"invalid HOVER_RUN_WINDOW_SIZE %s: %v\\n\", size, err)\n\t\treturn\n\t}\n\toptions = append(options, flutter.WindowInitialDimensions(width, height))\n}\n"),
+	}
+	filed2 := &embedded.EmbeddedFile{
+		Filename:    "app/singleinstance.go",
+		FileModTime: time.Unix(1792031458, 0),
+
+		Content: string("package main\n\nimport (\n\t\"encoding/json\"\n\t\"fmt\"\n\t\"net\"\n\t\"os\"\n\t\"path/filepath\"\n\t\"runtime\"\n\n\t\"github.com/go-flutter-desktop/go-flutter\"\n\t\"github.com/go-flutter-desktop/go-flutter/plugin\"\n\t\"github.com/go-gl/glfw/v3.3/glfw\"\n)\n\n// singleInstanceID is set by hover at compile-time to <organization>.<package>\n// when single-instance is set in hover.yaml, followed by .debug for the debug\n// builds so `hover run` doesn't forward to an installed app. The later\n// launches of the app forward their arguments to the first instance through a\n// socket, and exit.\nvar singleInstanceID string\n\nfunc init() {\n\tif singleInstanceID == \"\" {\n\t\treturn\n\t}\n\tpath := singleInstanceSocketPath()\n\tif conn, err := net.Dial(\"unix\", path); err == nil {\n\t\twd, _ := os.Getwd()\n\t\terr = json.NewEncoder(conn).Encode(singleInstanceActivation{Args: os.Args[1:], WorkingDirectory: wd})\n\t\tconn.Close()\n\t\tif err == nil {\n\t\t\tos.Exit(0)\n\t\t}\n\t\tfmt.Printf(\"failed to activate the running instance: %v\\n\", err)\n\t}\n\t// the socket of an instance that crashed is left behind\n\tos.Remove(path)\n\tlistener, err := net.Listen(\"unix\", path)\n\tif err != nil {\n\t\tfmt.Printf(\"single instance disabled: %v\\n\", err)\n\t\treturn\n\t}\n\toptions = append(options, flutter.AddPlugin(&singleInstancePlugin{listener: listener}))\n}\n\n// singleInstanceSocketPath returns the path of the socket of the first\n// instance, in a directory of the user.\nfunc singleInstanceSocketPath() string {\n\tdir := os.Getenv(\"XDG_RUNTIME_DIR\")\n\tname := singleInstanceID + \".sock\"\n\tif dir == \"\" {\n\t\tdir = os.TempDir()\n\t\tif runtime.GOOS == \"linux\" {\n\t\t\t// /tmp is shared by the users\n\t\t\tname = fmt.Sprintf(\"%s-%d.sock\", singleInstanceID, os.Getuid())\n\t\t}\n\t}\n\treturn filepath.Join(dir, name)\n}\n\n// singleInstanceActivation is sent by a later launch to the first instance.\ntype singleInstanceActivation struct {\n\tArgs             []string `json:\"args\"`\n\tWorkingDirectory string   `json:\"workingDirectory\"`\n}\n\n// singleInstancePlugin calls the activate method of the hover/single-instance\n// channel with the arguments ({'args': [...], 'workingDirectory': '...'}) of\n// the later launches. The window is focused by calling the focus method of\n// the channel from dart, the window can only be focused from the main thread.\ntype singleInstancePlugin struct {\n\tlistener net.Listener\n\tchannel  *plugin.MethodChannel\n\twindow   *glfw.Window\n}\n\nfunc (p *singleInstancePlugin) InitPlugin(messenger plugin.BinaryMessenger) error {\n\tp.channel = plugin.NewMethodChannel(messenger, \"hover/single-instance\", plugin.StandardMethodCodec{})\n\tp.channel.HandleFuncSync(\"focus\", p.handleFocus)\n\tgo p.accept()\n\treturn nil\n}\n\nfunc (p *singleInstancePlugin) InitPluginGLFW(window *glfw.Window) error {\n\tp.window = window\n\treturn nil\n}\n\nfunc (p *singleInstancePlugin) accept() {\n\tfor {\n\t\tconn, err := p.listener.Accept()\n\t\tif err != nil {\n\t\t\tfmt.Printf(\"single instance stopped: %v\\n\", err)\n\t\t\treturn\n\t\t}\n\t\tvar activation singleInstanceActivation\n\t\terr = json.NewDecoder(conn).Decode(&activation)\n\t\tconn.Close()\n\t\tif err != nil {\n\t\t\tfmt.Printf(\"invalid activation of the single instance: %v\\n\", err)\n\t\t\tcontinue\n\t\t}\n\t\targs := make([]interface{}, len(activation.Args))\n\t\tfor i, arg := range activation.Args {\n\t\t\targs[i] = arg\n\t\t}\n\t\terr = p.channel.InvokeMethod(\"activate\", map[interface{}]interface{}{\n\t\t\t\"args\":             args,\n\t\t\t\"workingDirectory\": activation.WorkingDirectory,\n\t\t})\n\t\tif err != nil {\n\t\t\tfmt.Printf(\"failed to activate the single instance: %v\\n\", err)\n\t\t}\n\t}\n}\n\nfunc (p *singleInstancePlugin) handleFocus(arguments interface{}) (interface{}, error) {\n\tif p.window == nil {\n\t\treturn nil, nil\n\t}\n\tif p.window.GetAttrib(glfw.Iconified) == glfw.True {\n\t\tp.window.Restore()\n\t}\n\tp.window.Show()\n\tp.window.Focus()\n\treturn nil, nil\n}\n"),
+	}
+	filed4 := &embedded.EmbeddedFile{
+		Filename:    "app/singleinstance_dbus_linux.go",
+		FileModTime: time.Unix(1792031745, 0),
+
+		Content: string("package main\n\nimport (\n\t\"encoding/json\"\n\t\"fmt\"\n\t\"net\"\n\t\"os\"\n\t\"strings\"\n\n\t\"github.com/godbus/dbus/v5\"\n)\n\n// When dbus-activatable is set in hover.yaml, the first instance owns the\n// DBus name singleInstanceID and implements the org.freedesktop.Application\n// interface the desktops call to launch the app, the .desktop files of the\n// linux packages are DBusActivatable. The activations are forwarded to the\n// first instance like the later launches, through its socket: this file sorts\n// after singleinstance.go, whose init listens to the socket first.\nfunc init() {\n\tif singleInstanceID == \"\" {\n\t\treturn\n\t}\n\tconn, err := dbus.ConnectSessionBus()\n\tif err != nil {\n\t\tfmt.Printf(\"dbus activation disabled: %v\\n\", err)\n\t\treturn\n\t}\n\tpath := dbus.ObjectPath(\"/\" + strings.NewReplacer(\".\", \"/\", \"-\", \"_\").Replace(singleInstanceID))\n\terr = conn.Export(dbusApplication{}, path, \"org.freedesktop.Application\")\n\tif err != nil {\n\t\tfmt.Printf(\"dbus activation disabled: %v\\n\", err)\n\t\tconn.Close()\n\t\treturn\n\t}\n\treply, err := conn.RequestName(singleInstanceID, dbus.NameFlagDoNotQueue)\n\tif err != nil || reply != dbus.RequestNameReplyPrimaryOwner {\n\t\tfmt.Printf(\"dbus activation disabled: the name %s is owned by another process\\n\", singleInstanceID)\n\t\tconn.Close()\n\t}\n}\n\n// dbusApplication implements org.freedesktop.Application. The URIs opened\n// with the app are the arguments of the activation, the actions aren't\n// supported and activate the app.\ntype dbusApplication struct{}\n\nfunc (dbusApplication) Activate(platformData map[string]dbus.Variant) *dbus.Error {\n\treturn dbusActivate(nil)\n}\n\nfunc (dbusApplication) Open(uris []string, platformData map[string]dbus.Variant) *dbus.Error {\n\treturn dbusActivate(uris)\n}\n\nfunc (dbusApplication) ActivateAction(action string, parameter []dbus.Variant, platformData map[string]dbus.Variant) *dbus.Error {\n\treturn dbusActivate(nil)\n}\n\nfunc dbusActivate(args []string) *dbus.Error {\n\tconn, err := net.Dial(\"unix\", singleInstanceSocketPath())\n\tif err != nil {\n\t\treturn dbus.MakeFailedError(err)\n\t}\n\tdefer conn.Close()\n\twd, _ := os.Getwd()\n\terr = json.NewEncoder(conn).Encode(singleInstanceActivation{Args: args, WorkingDirectory: wd})\n\tif err != nil {\n\t\treturn dbus.MakeFailedError(err)\n\t}\n\treturn nil\n}\n"),
+	}
+	filed3 := &embedded.EmbeddedFile{
+		Filename:    "app/singleinstance_windows.go",
+		FileModTime: time.Unix(1792031458, 0),
+
+		Content: string("package main\n\nimport (\n\t\"syscall\"\n\t\"unsafe\"\n)\n\n// The first instance holds a mutex named after singleInstanceID while it\n// runs, the app-mutex of the windows-inno installer, which asks to close the\n// app before installing or uninstalling it.\nfunc init() {\n\tif singleInstanceID == \"\" {\n\t\treturn\n\t}\n\tname, err := syscall.UTF16PtrFromString(singleInstanceID)\n\tif err != nil {\n\t\treturn\n\t}\n\t// the handle is released when the app exits\n\tsyscall.NewLazyDLL(\"kernel32.dll\").NewProc(\"CreateMutexW\").Call(0, 0, uintptr(unsafe.Pointer(name)))\n}\n"),
+	}
+	filec5 := &embedded.EmbeddedFile{
+		Filename:    "app/wmclass.go",
+		FileModTime: time.Unix(1792029319, 0),
+
+		Content: string("package main\n\nimport (\n\t\"os\"\n\t\"runtime\"\n)\n\n// wmClass is set by hover at compile-time to the wm-class of hover.yaml, the\n// StartupWMClass of the .desktop file of the linux packages.\nvar wmClass string\n\n// On X11, GLFW names the window instance after RESOURCE_NAME. GNOME and KDE\n// match the StartupWMClass of the .desktop file against it, grouping the\n// windows of the app with its launcher in the dock and the taskbar.\nfunc init() {\n\tif runtime.GOOS != \"linux\" || wmClass == \"\" || os.Getenv(\"RESOURCE_NAME\") != \"\" {\n\t\treturn\n\t}\n\tos.Setenv(\"RESOURCE_NAME\", wmClass)\n}\n"),
+	}
+	filecz := &embedded.EmbeddedFile{
+		Filename:    "ide/goland/hover_debug_go.xml.tmpl",
+		FileModTime: time.Unix(1792031110, 0),
+
+		Content: string("<component name=\"ProjectRunConfigurationManager\">\n  <configuration default=\"false\" name=\"hover debug go\" type=\"GoApplicationRunConfiguration\" factoryName=\"Go Application\">\n    <module name=\"{{xmlescape .module}}\" />\n    <working_directory value=\"$PROJECT_DIR$/go\" />\n    <go_parameters value=\"{{xmlescape .goParameters}}\" />\n    <envs>\n{{- range .env}}\n      <env name=\"{{xmlescape .name}}\" value=\"{{xmlescape .value}}\" />\n{{- end}}\n    </envs>\n    <kind value=\"DIRECTORY\" />\n    <package value=\"\" />\n    <directory value=\"$PROJECT_DIR$/go/cmd\" />\n    <filePath value=\"$PROJECT_DIR$\" />\n    <output_directory value=\"{{xmlescape .outputDir}}\" />\n    <method v=\"2\" />\n  </configuration>\n</component>\n"),
+	}
+	filecy := &embedded.EmbeddedFile{
+		Filename:    "ide/goland/hover_run.xml.tmpl",
+		FileModTime: time.Unix(1792031110, 0),
+
+		Content: string("<component name=\"ProjectRunConfigurationManager\">\n  <configuration default=\"false\" name=\"hover run\" type=\"ShConfigurationType\">\n    <option name=\"SCRIPT_TEXT\" value=\"hover run --observatory-port {{xmlescape .observatoryPort}}\" />\n    <option name=\"INDEPENDENT_SCRIPT_PATH\" value=\"true\" />\n    <option name=\"SCRIPT_PATH\" value=\"\" />\n    <option name=\"SCRIPT_OPTIONS\" value=\"\" />\n    <option name=\"INDEPENDENT_SCRIPT_WORKING_DIRECTORY\" value=\"true\" />\n    <option name=\"SCRIPT_WORKING_DIRECTORY\" value=\"$PROJECT_DIR$\" />\n    <option name=\"INDEPENDENT_INTERPRETER_PATH\" value=\"true\" />\n    <option name=\"INTERPRETER_PATH\" value=\"\" />\n    <option name=\"INTERPRETER_OPTIONS\" value=\"\" />\n    <option name=\"EXECUTE_IN_TERMINAL\" value=\"true\" />\n    <option name=\"EXECUTE_SCRIPT_FILE\" value=\"false\" />\n    <envs />\n    <method v=\"2\" />\n  </configuration>\n</component>\n"),
+	}
+	filee := &embedded.EmbeddedFile{
 		Filename:    "packaging/README.md",
 		FileModTime: time.Unix(1587470036, 0),
 
-		Content: string("# packaging\nThe template files in the subdirectories are only copied on init and then executed on build.\n"),
+		Content: string("# packaging\nThe template files in the subdirectories are only copied on init and then executed on build.\n\nBesides the values provided by hover (`{{.applicationName}}`, `{{.version}}`, ...), the templates can use these functions:\n\n* `shellquote`: concatenates its arguments and quotes the result for a POSIX shell, e.g. `{{shellquote \"/usr/lib/\" .packageName}}`\n* `xmlescape`: escapes a value for XML text and attributes, e.g. `{{xmlescape .applicationName}}`\n* `nsisquote`: concatenates its arguments and puts the result in double quotes for a NSIS script, a leading NSIS variable is kept, e.g. `{{nsisquote \"$INSTDIR\\\\\" .executableName \".exe\"}}`\n* `innoquote`: concatenates its arguments and puts the result in double quotes for the parameters of an Inno Setup script, a leading Inno Setup constant is kept, e.g. `{{innoquote \"{app}\\\\\" .executableName \".exe\"}}`\n* `desktopquote`: quotes a value for the `Exec` key of a `.desktop` file, e.g. `{{desktopquote .executablePath}}`\n* `nixquote`: concatenates its arguments and puts the result in a nix string, e.g. `description = {{nixquote .description}};`\n* `upper`, `lower` and `trim`: change the case of a value or trim its surrounding whitespace, e.g. `{{upper .packageName}}`\n* `replace`: replaces all occurrences of a string, e.g. `{{.packageName | replace \"-\" \"_\"}}`\n* `date`: formats the current time (or `SOURCE_DATE_EPOCH` when set) with a [go time layout](https://golang.org/pkg/time/#pkg-constants), e.g. `{{date \"2006-01-02\"}}`\n* `env`: reads an environment variable, e.g. `{{env \"CI_COMMIT_SHA\"}}`\n* `sha256file`: returns the sha256 checksum of a file, relative to the root of the flutter project, e.g. `{{sha256file \"go/assets/icon.png\"}}`\n* `quote`: puts a value in double quotes and escapes it, e.g. `{{quote .description}}`\n* `toJson`: encodes a value as JSON, which can also be used for YAML values, e.g. `summary: {{toJson .description}}`\n* `default`: returns a fallback when a value is empty or missing, e.g. `{{.customValue | default \"fallback\"}}`\n\nThe `firstRunURL` and `uninstallURL` values are the survey URLs of `go/hover.yaml`, they are empty unless `survey.opt-in` is set.\n\nA template referencing a value that doesn't exist fails the build, and the error names the template file and the missing key.\nStart a template with `{{/* missingkey=zero */}}` to render missing values as empty strings instead, so they can be combined with `default`.\nWithout this comment, `{{index . \"customValue\" | default \"fallback\"}}` can be used for a single optional value.\n"),
 	}
-	filee := &embedded.EmbeddedFile{
+	filedj := &embedded.EmbeddedFile{
+		Filename:    "packaging/darwin-appstore/entitlements.plist.tmpl",
+		FileModTime: time.Unix(1792032547, 0),
+
+		Content: string("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<!DOCTYPE plist PUBLIC \"-//Apple//DTD PLIST 1.0//EN\" \"http://www.apple.com/DTDs/PropertyList-1.0.dtd\">\n<plist version=\"1.0\">\n    <dict>\n        <key>com.apple.security.app-sandbox</key>\n        <true/>\n        <key>com.apple.application-identifier</key>\n        <string>{{.appstoreTeamID}}.{{.organizationName}}.{{.packageName}}</string>\n        <key>com.apple.developer.team-identifier</key>\n        <string>{{.appstoreTeamID}}</string>\n        <key>com.apple.security.network.client</key>\n        <true/>\n        <key>com.apple.security.files.user-selected.read-write</key>\n        <true/>\n    </dict>\n</plist>\n"),
+	}
+	fileg := &embedded.EmbeddedFile{
 		Filename:    "packaging/darwin-bundle/Info.plist.tmpl",
 		FileModTime: time.Unix(1587472853, 0),
 
-		Content: string("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<!DOCTYPE plist PUBLIC \"-//Apple Computer//DTD PLIST 1.0//EN\" \"http://www.apple.com/DTDs/PropertyList-1.0.dtd\">\n<plist version=\"1.0\">\n    <dict>\n        <key>CFBundleDevelopmentRegion</key>\n        <string>English</string>\n        <key>CFBundleExecutable</key>\n        <string>{{.executableName}}</string>\n        <key>CFBundleGetInfoString</key>\n        <string>{{.description}}</string>\n        <key>CFBundleIconFile</key>\n        <string>icon.icns</string>\n        <key>CFBundleIdentifier</key>\n        <string>{{.organizationName}}</string>\n        <key>CFBundleInfoDictionaryVersion</key>\n        <string>6.0</string>\n        <key>CFBundleLongVersionString</key>\n        <string>{{.version}}</string>\n        <key>CFBundleName</key>\n        <string>{{.applicationName}}</string>\n        <key>CFBundlePackageType</key>\n        <string>APPL</string>\n        <key>CFBundleShortVersionString</key>\n        <string>{{.version}}</string>\n        <key>CFBundleSignature</key>\n        <string>{{.organizationName}}.{{.packageName}}</string>\n        <key>CFBundleVersion</key>\n        <string>{{.version}}</string>\n        <key>CSResourcesFileMapped</key>\n        <true/>\n        <key>NSHumanReadableCopyright</key>\n        <string></string>\n    </dict>\n</plist>"),
+		Content: string("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<!DOCTYPE plist PUBLIC \"-//Apple Computer//DTD PLIST 1.0//EN\" \"http://www.apple.com/DTDs/PropertyList-1.0.dtd\">\n<plist version=\"1.0\">\n    <dict>\n        <key>CFBundleDevelopmentRegion</key>\n        <string>English</string>\n        <key>CFBundleExecutable</key>\n        <string>{{.executableName}}</string>\n        <key>CFBundleGetInfoString</key>\n        <string>{{xmlescape .description}}</string>\n        <key>CFBundleIconFile</key>\n        <string>icon.icns</string>\n        <key>CFBundleIdentifier</key>\n        <string>{{.organizationName}}.{{.packageName}}</string>\n        <key>CFBundleInfoDictionaryVersion</key>\n        <string>6.0</string>\n        <key>CFBundleLongVersionString</key>\n        <string>{{.version}}</string>\n        <key>CFBundleName</key>\n        <string>{{xmlescape .applicationName}}</string>\n        <key>CFBundlePackageType</key>\n        <string>APPL</string>\n        <key>CFBundleShortVersionString</key>\n        <string>{{.version}}</string>\n        <key>CFBundleSignature</key>\n        <string>{{.organizationName}}.{{.packageName}}</string>\n        <key>CFBundleVersion</key>\n        <string>{{.version}}</string>\n        <key>CSResourcesFileMapped</key>\n        <true/>\n        <key>NSHumanReadableCopyright</key>\n        <string></string>\n        {{- if eq .singleInstance \"true\"}}\n        <key>LSMultipleInstancesProhibited</key>\n        <true/>\n        {{- end}}\n        {{- if .darwinDocumentTypes}}\n        <key>CFBundleDocumentTypes</key>\n{{.darwinDocumentTypes}}\n        {{- end}}\n        {{- if .urlSchemes}}\n        <key>CFBundleURLTypes</key>\n        <array>\n            <dict>\n                <key>CFBundleURLName</key>\n                <string>{{.organizationName}}.{{.packageName}}</string>\n                <key>CFBundleURLSchemes</key>\n                <array>\n                    {{- range fields .urlSchemes}}\n                    <string>{{.}}</string>\n                    {{- end}}\n                </array>\n            </dict>\n        </array>\n        {{- end}}\n    </dict>\n</plist>\n"),
 	}
-	fileg := &embedded.EmbeddedFile{
+	filecv := &embedded.EmbeddedFile{
+		Filename:    "packaging/darwin-cask/cask.rb.tmpl",
+		FileModTime: time.Unix(1792030915, 0),
+
+		Content: string("cask {{toJson (.packageName | replace \"_\" \"-\" | lower)}} do\n  version {{toJson .version}}\n  sha256 :no_check # Filled in by hover with the checksum of the dmg\n\n  url {{toJson .homebrewUrl}}\n  name {{toJson .applicationName}}\n  desc {{toJson .description}}\n{{- if .homebrewHomepage}}\n  homepage {{toJson .homebrewHomepage}}\n{{- end}}\n{{- if eq .arch \"arm64\"}}\n\n  depends_on arch: :arm64\n{{- end}}\n\n  app {{toJson (print .applicationName \" \" .version \".app\")}}, target: {{toJson (print .applicationName \".app\")}}\nend\n"),
+	}
+	filei := &embedded.EmbeddedFile{
 		Filename:    "packaging/darwin-pkg/Distribution.tmpl",
 		FileModTime: time.Unix(1587472689, 0),
 
-		Content: string("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n<installer-gui-script minSpecVersion=\"1\">\n\t<title>{{.applicationName}}</title>\n\t<background alignment=\"topleft\" file=\"root/Applications/{{.applicationName}} {{.version}}.app/Contents/MacOS/assets/icon.png\"/>\n\t<choices-outline>\n\t    <line choice=\"choiceBase\"/>\n    </choices-outline>\n    <choice id=\"choiceBase\" title=\"base\">\n        <pkg-ref id=\"{{.organizationName}}.base.pkg\"/>\n    </choice>\n    <pkg-ref id=\"{{.organizationName}}.base.pkg\" version=\"{{.version}}\" auth=\"Root\">#base.pkg</pkg-ref>\n</installer-gui-script>\n"),
+		Content: string("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n<installer-gui-script minSpecVersion=\"1\">\n\t<title>{{xmlescape .applicationName}}</title>\n\t<background alignment=\"topleft\" file=\"root/Applications/{{xmlescape .applicationName}} {{.version}}.app/Contents/MacOS/assets/icon.png\"/>\n\t<choices-outline>\n\t    <line choice=\"choiceBase\"/>\n    </choices-outline>\n    <choice id=\"choiceBase\" title=\"base\">\n        <pkg-ref id=\"{{.organizationName}}.base.pkg\"/>\n    </choice>\n    <pkg-ref id=\"{{.organizationName}}.base.pkg\" version=\"{{.version}}\" auth=\"Root\">#base.pkg</pkg-ref>\n</installer-gui-script>\n"),
 	}
-	fileh := &embedded.EmbeddedFile{
+	filej := &embedded.EmbeddedFile{
 		Filename:    "packaging/darwin-pkg/PackageInfo.tmpl",
 		FileModTime: time.Unix(1587473491, 0),
 
-		Content: string("<pkg-info format-version=\"2\" identifier=\"{{.organizationName}}.base.pkg\" version=\"{{.version}}\" install-location=\"/\" auth=\"root\">\n\t<bundle-version>\n\t\t<bundle id=\"{{.organizationName}}\" CFBundleIdentifier=\"{{.organizationName}}.{{.packageName}}\" path=\"./Applications/{{.applicationName}} {{.version}}.app\" CFBundleVersion=\"{{.version}}\"/>\n    </bundle-version>\n</pkg-info>"),
+		Content: string("<pkg-info format-version=\"2\" identifier=\"{{.organizationName}}.base.pkg\" version=\"{{.version}}\" install-location=\"/\" auth=\"root\">\n\t<bundle-version>\n\t\t<bundle id=\"{{.organizationName}}\" CFBundleIdentifier=\"{{.organizationName}}.{{.packageName}}\" path=\"./Applications/{{xmlescape .applicationName}} {{.version}}.app\" CFBundleVersion=\"{{.version}}\"/>\n    </bundle-version>\n{{- if .launchdJobs}}\n\t<scripts>\n\t\t<postinstall file=\"./postinstall\"/>\n\t</scripts>\n{{- end}}\n</pkg-info>\n"),
 	}
-	filej := &embedded.EmbeddedFile{
+	filed6 := &embedded.EmbeddedFile{
+		Filename:    "packaging/freebsd-pkg/+MANIFEST.tmpl",
+		FileModTime: time.Unix(1792031900, 0),
+
+		Content: string("name: {{toJson .packageName}}\nversion: {{toJson .version}}\norigin: {{toJson (print \"x11/\" .packageName)}}\ncomment: {{toJson .description}}\ndesc: {{toJson .description}}\nmaintainer: {{toJson .author}}\n# The homepage of the app\nwww: \"\"\nprefix: \"/usr/local\"\nlicenselogic: \"single\"\nlicenses: [{{toJson .license}}]\ncategories: [\"x11\"]\n"),
+	}
+	filed7 := &embedded.EmbeddedFile{
+		Filename:    "packaging/freebsd-pkg/bin.tmpl",
+		FileModTime: time.Unix(1792031900, 0),
+
+		Content: string("#!/bin/sh\n{{- with .launcherSetup}}\napp_dir={{shellquote \"/usr/local/lib/\" $.packageName}}\n{{.}}\n{{- end}}\nexec {{shellquote \"/usr/local/lib/\" .packageName \"/\" .executableName}} \"$@\"\n"),
+	}
+	filel := &embedded.EmbeddedFile{
 		Filename:    "packaging/linux/app.desktop.tmpl",
 		FileModTime: time.Unix(1587470111, 0),
 
-		Content: string("[Desktop Entry]\nVersion=1.0\nType=Application\nTerminal=false\nCategories=\nName={{.applicationName}}\nIcon={{.iconPath}}\nExec={{.executablePath}}"),
+		Content: string("[Desktop Entry]\nVersion=1.0\nType=Application\nTerminal={{.desktopTerminal}}\nCategories={{.desktopCategories}}\nName={{.applicationName}}\n{{- if .desktopGenericName}}\nGenericName={{.desktopGenericName}}\n{{- end}}\n{{- if .desktopKeywords}}\nKeywords={{.desktopKeywords}}\n{{- end}}\n{{- if .desktopMimeType}}\nMimeType={{.desktopMimeType}}\n{{- end}}\nIcon={{.iconPath}}\nExec={{desktopquote .executablePath}}{{if .urlSchemes}} %U{{else if .desktopMimeType}} %F{{end}}\nStartupWMClass={{.wmClass}}\nStartupNotify={{.startupNotify}}\n{{- if eq .singleInstance \"true\"}}\nSingleMainWindow=true\n{{- end}}\n{{- if .dbusName}}\nDBusActivatable=true\n{{- end}}\n"),
 	}
-	filek := &embedded.EmbeddedFile{
+	filem := &embedded.EmbeddedFile{
 		Filename:    "packaging/linux/bin.tmpl",
 		FileModTime: time.Unix(1587423157, 0),
 
-		Content: string("#!/bin/sh\n/usr/lib/{{.packageName}}/{{.executableName}}"),
+		Content: string("#!/bin/sh\n{{- with .launcherSetup}}\napp_dir={{shellquote \"/usr/lib/\" $.packageName}}\n{{.}}\n{{- end}}\nexec {{shellquote \"/usr/lib/\" .packageName \"/\" .executableName}} \"$@\"\n"),
 	}
-	filem := &embedded.EmbeddedFile{
+	filedn := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-apk/APKBUILD.tmpl",
+		FileModTime: time.Unix(1792033179, 0),
+
+		Content: string("# Maintainer: {{.author}}\npkgname={{.packageName}}\npkgver={{.apkVersion}}\npkgrel=0\npkgdesc={{shellquote .description}}\nurl={{shellquote .homepage}}\narch=\"{{.gnuArch}}\"\nlicense={{shellquote .license}}\n# the app and the flutter engine are linked with glibc, gcompat runs them on\n# musl\ndepends=\"gcompat libgcc libstdc++ mesa-gl mesa-egl libx11 libxrandr libxcursor libxinerama libxi libxxf86vm\"\ninstall=\"$pkgname.post-install $pkgname.post-upgrade $pkgname.pre-deinstall\"\noptions=\"!check !strip !tracedeps\"\n\npackage() {\n\tmkdir -p \"$pkgdir\"\n\tcp -r \"$startdir\"/root/. \"$pkgdir\"/\n}\n"),
+	}
+	filedo := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-apk/post-install.tmpl",
+		FileModTime: time.Unix(1792033179, 0),
+
+		Content: string("#!/bin/sh\nif command -v update-desktop-database >/dev/null 2>&1; then\n\tupdate-desktop-database -q /usr/share/applications\nfi\n{{- if .gsettingsSchema}}\nif command -v glib-compile-schemas >/dev/null 2>&1; then\n\tglib-compile-schemas /usr/share/glib-2.0/schemas\nfi\n{{- end}}\n{{- if .mimePackage}}\nif command -v update-mime-database >/dev/null 2>&1; then\n\tupdate-mime-database /usr/share/mime\nfi\n{{- end}}\nexit 0\n"),
+	}
+	filedp := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-apk/post-upgrade.tmpl",
+		FileModTime: time.Unix(1792033179, 0),
+
+		Content: string("#!/bin/sh\nif command -v update-desktop-database >/dev/null 2>&1; then\n\tupdate-desktop-database -q /usr/share/applications\nfi\n{{- if .gsettingsSchema}}\nif command -v glib-compile-schemas >/dev/null 2>&1; then\n\tglib-compile-schemas /usr/share/glib-2.0/schemas\nfi\n{{- end}}\n{{- if .mimePackage}}\nif command -v update-mime-database >/dev/null 2>&1; then\n\tupdate-mime-database /usr/share/mime\nfi\n{{- end}}\nexit 0\n"),
+	}
+	filedq := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-apk/pre-deinstall.tmpl",
+		FileModTime: time.Unix(1792033179, 0),
+
+		Content: string("#!/bin/sh\n{{- if .uninstallURL}}\n# Uninstall survey, opted in with survey.opt-in in go/hover.yaml\n(curl -fsS -m 5 -o /dev/null {{shellquote .uninstallURL}} || wget -q -T 5 -O /dev/null {{shellquote .uninstallURL}}) >/dev/null 2>&1 || true\n{{- end}}\nexit 0\n"),
+	}
+	fileo := &embedded.EmbeddedFile{
 		Filename:    "packaging/linux-appimage/AppRun.tmpl",
 		FileModTime: time.Unix(1587423157, 0),
 
-		Content: string("#!/bin/sh\ncd \"$(dirname \"$0\")\"\nexec ./build/{{.executableName}}"),
+		Content: string("#!/bin/sh\ncd \"$(dirname \"$0\")\"\napp_dir=\"$(pwd)/build\"\n{{- with .launcherSetup}}\n{{.}}\n{{- end}}\nexec \"$app_dir\"/{{shellquote .executableName}} \"$@\"\n"),
 	}
-	fileo := &embedded.EmbeddedFile{
+	fileca := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-aur/PKGBUILD.tmpl",
+		FileModTime: time.Unix(1792029547, 0),
+
+		Content: string("# The PKGBUILD of the AUR package, rendered by `hover publish` with the URL\n# and the checksum of the published linux-aur tarball.\npkgname={{.packageName}}-bin\npkgver={{.version}}\npkgrel={{.release}}\npkgdesc={{shellquote .description}}\narch=(\"{{.gnuArch}}\")\nlicense=({{shellquote .license}})\nprovides=(\"{{.packageName}}\")\nconflicts=(\"{{.packageName}}\")\noptions=(\"!strip\" \"!debug\")\nsource=({{shellquote .aurSourceURL}})\nsha256sums=({{shellquote .aurSha256}})\n\npackage() {\n    cp -r \"$srcdir\"/usr \"$pkgdir\"/\n}\n"),
+	}
+	fileq := &embedded.EmbeddedFile{
 		Filename:    "packaging/linux-deb/control.tmpl",
 		FileModTime: time.Unix(1587423157, 0),
 
-		Content: string("Package: {{.packageName}}\nArchitecture: amd64\nMaintainer: @{{.author}}\nPriority: optional\nVersion: {{.version}}\nDescription: {{.description}}\n"),
+		Content: string("Package: {{.packageName}}\nArchitecture: {{.arch}}\nMaintainer: @{{.author}}\nPriority: optional\nVersion: {{.version}}\n{{- if or .dataPackageName .debDepends}}\nDepends: {{if .dataPackageName}}{{.dataPackageName}} (= {{.version}}){{if .debDepends}}, {{end}}{{end}}{{.debDepends}}\n{{- end}}\n{{- if .debRecommends}}\nRecommends: {{.debRecommends}}\n{{- end}}\n{{- if .debSuggests}}\nSuggests: {{.debSuggests}}\n{{- end}}\n{{- if .debConflicts}}\nConflicts: {{.debConflicts}}\n{{- end}}\n{{- if .debProvides}}\nProvides: {{.debProvides}}\n{{- end}}\nDescription: {{.description}}\n"),
 	}
-	fileq := &embedded.EmbeddedFile{
+	filecc := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-deb/postinst.tmpl",
+		FileModTime: time.Unix(1792029710, 0),
+
+		Content: string("#!/bin/sh\nset -e\n\nif [ \"$1\" = \"configure\" ] && command -v gtk-update-icon-cache >/dev/null 2>&1; then\n    gtk-update-icon-cache -q -t -f /usr/share/icons/hicolor || true\nfi\n{{- if .gsettingsSchema}}\n\nif [ \"$1\" = \"configure\" ] && command -v glib-compile-schemas >/dev/null 2>&1; then\n    glib-compile-schemas /usr/share/glib-2.0/schemas\nfi\n{{- end}}\n{{- if .mimePackage}}\n\nif [ \"$1\" = \"configure\" ] && command -v update-mime-database >/dev/null 2>&1; then\n    update-mime-database /usr/share/mime\nfi\n{{- end}}\n"),
+	}
+	filer := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-deb/prerm.tmpl",
+		FileModTime: time.Unix(1792003832, 0),
+
+		Content: string("#!/bin/sh\nset -e\n{{- if .uninstallURL}}\n\nif [ \"$1\" = \"remove\" ]; then\n    # Uninstall survey, opted in with survey.opt-in in go/hover.yaml\n    (curl -fsS -m 5 -o /dev/null {{shellquote .uninstallURL}} || wget -q -T 5 -O /dev/null {{shellquote .uninstallURL}}) >/dev/null 2>&1 || true\nfi\n{{- end}}\n"),
+	}
+	filedc := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-deb-src/changelog.tmpl",
+		FileModTime: time.Unix(1792032312, 0),
+
+		Content: string("{{.packageName}} ({{.version}}-1) noble; urgency=medium\n\n  * Release {{.version}}.\n\n -- {{.author}}  {{date \"Mon, 02 Jan 2006 15:04:05 -0700\"}}\n"),
+	}
+	filedd := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-deb-src/compat.tmpl",
+		FileModTime: time.Unix(1792032312, 0),
+
+		Content: string("12\n"),
+	}
+	filede := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-deb-src/control.tmpl",
+		FileModTime: time.Unix(1792032312, 0),
+
+		Content: string("Source: {{.packageName}}\nSection: misc\nPriority: optional\nMaintainer: {{.author}}\nBuild-Depends: debhelper (>= 12)\nStandards-Version: 4.6.2\nRules-Requires-Root: no\n\nPackage: {{.packageName}}\nArchitecture: {{.arch}}\nDepends: ${shlibs:Depends}, ${misc:Depends}{{if .debDepends}}, {{.debDepends}}{{end}}\n{{- if .debRecommends}}\nRecommends: {{.debRecommends}}\n{{- end}}\n{{- if .debSuggests}}\nSuggests: {{.debSuggests}}\n{{- end}}\n{{- if .debConflicts}}\nConflicts: {{.debConflicts}}\n{{- end}}\n{{- if .debProvides}}\nProvides: {{.debProvides}}\n{{- end}}\nDescription: {{.description}}\n {{.description}}\n"),
+	}
+	filedf := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-deb-src/format.tmpl",
+		FileModTime: time.Unix(1792032312, 0),
+
+		Content: string("3.0 (quilt)\n"),
+	}
+	filedg := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-deb-src/install.tmpl",
+		FileModTime: time.Unix(1792032312, 0),
+
+		Content: string("usr\n"),
+	}
+	filedh := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-deb-src/rules.tmpl",
+		FileModTime: time.Unix(1792032312, 0),
+
+		Content: string("#!/usr/bin/make -f\n\n%:\n\tdh $@\n\n# the source contains the build output of hover, the app isn't built again\noverride_dh_auto_build:\n\noverride_dh_strip:\n\noverride_dh_dwz:\n\n# the app links the libraries it ships, like the flutter engine\noverride_dh_shlibdeps:\n\tdh_shlibdeps -l$(CURDIR)/debian/{{.packageName}}/usr/lib/{{.packageName}} -- --ignore-missing-info\n"),
+	}
+	filec3 := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-flatpak/bin.tmpl",
+		FileModTime: time.Unix(1792029173, 0),
+
+		Content: string("#!/bin/sh\n{{- with .launcherSetup}}\napp_dir={{shellquote \"/app/lib/\" $.packageName}}\n{{.}}\n{{- end}}\nexec {{shellquote \"/app/lib/\" .packageName \"/\" .executableName}} \"$@\"\n"),
+	}
+	filec4 := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-flatpak/manifest.yml.tmpl",
+		FileModTime: time.Unix(1792029173, 0),
+
+		Content: string("app-id: {{.organizationName}}.{{.packageName}}\nruntime: {{.flatpakRuntime}}\nruntime-version: '{{.flatpakRuntimeVersion}}'\nsdk: {{.flatpakSdk}}\ncommand: {{.executableName}}\nfinish-args:\n  - --share=ipc\n  - --share=network\n{{- if eq .displayServer \"wayland\"}}\n  - --socket=wayland\n{{- else}}\n  - --socket=x11\n{{- end}}\n  - --device=dri\nmodules:\n  - name: {{.packageName}}\n    buildsystem: simple\n    build-commands:\n      - mkdir -p /app/lib/{{.packageName}}\n      - cp -r build/. /app/lib/{{.packageName}}\n      - install -Dm755 bin/{{.executableName}} /app/bin/{{.executableName}}\n      - install -Dm644 {{.organizationName}}.{{.packageName}}.desktop /app/share/applications/{{.organizationName}}.{{.packageName}}.desktop\n{{- if .dbusName}}\n      - install -Dm644 {{.dbusName}}.service /app/share/dbus-1/services/{{.dbusName}}.service\n{{- end}}\n      - install -Dm644 {{.appstreamID}}.metainfo.xml /app/share/metainfo/{{.appstreamID}}.metainfo.xml\n      - install -Dm644 build/assets/icon.png /app/share/icons/hicolor/512x512/apps/{{.organizationName}}.{{.packageName}}.png\n    sources:\n      - type: dir\n        path: files\n"),
+	}
+	filet := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-kiosk/control.tmpl",
+		FileModTime: time.Unix(1792003605, 0),
+
+		Content: string("Package: {{.packageName}}\nArchitecture: {{.arch}}\nMaintainer: @{{.author}}\nPriority: optional\nVersion: {{.version}}\nDepends: cage, xwayland, adduser\nDescription: {{.description}}\n"),
+	}
+	fileu := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-kiosk/kiosk.service.tmpl",
+		FileModTime: time.Unix(1792003605, 0),
+
+		Content: string("[Unit]\nDescription={{.applicationName}} kiosk session\nAfter=systemd-user-sessions.service plymouth-quit-wait.service dbus.socket systemd-logind.service getty@tty1.service\nWants=dbus.socket systemd-logind.service\nConflicts=getty@tty1.service\nConditionPathExists=/dev/tty1\n# Keep restarting the app, however often it crashes.\nStartLimitIntervalSec=0\n\n[Service]\nType=simple\nUser={{.packageName}}\nPAMName={{.packageName}}-kiosk\nWorkingDirectory=/var/lib/{{.packageName}}\nExecStart=/usr/bin/cage -s -- /usr/bin/{{.executableName}}\nRestart=always\nRestartSec=2\nUtmpIdentifier=tty1\nUtmpMode=user\nTTYPath=/dev/tty1\nTTYReset=yes\nTTYVHangup=yes\nTTYVTDisallocate=yes\nStandardInput=tty-fail\nStandardOutput=journal\nStandardError=journal\n\n[Install]\nWantedBy=graphical.target\nAlias=display-manager.service\n"),
+	}
+	filev := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-kiosk/pam.tmpl",
+		FileModTime: time.Unix(1792003605, 0),
+
+		Content: string("auth    required  pam_unix.so nullok\naccount required  pam_unix.so\nsession required  pam_unix.so\nsession required  pam_systemd.so\n"),
+	}
+	filew := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-kiosk/postinst.tmpl",
+		FileModTime: time.Unix(1792003605, 0),
+
+		Content: string("#!/bin/sh\nset -e\n\nif [ \"$1\" = \"configure\" ]; then\n    # The kiosk session runs as a dedicated user without a password.\n    if ! getent passwd {{shellquote .packageName}} >/dev/null; then\n        adduser --system --group --home {{shellquote \"/var/lib/\" .packageName}} --shell /usr/sbin/nologin {{shellquote .packageName}}\n    fi\n    for group in video input render audio; do\n        if getent group \"$group\" >/dev/null; then\n            adduser {{shellquote .packageName}} \"$group\" >/dev/null\n        fi\n    done\n    if [ -d /run/systemd/system ]; then\n        systemctl daemon-reload\n        # The kiosk session takes over tty1 and replaces the display manager.\n        systemctl disable getty@tty1.service >/dev/null 2>&1 || true\n        systemctl enable {{shellquote .packageName \"-kiosk.service\"}}\n        systemctl set-default graphical.target\n    fi\nfi\n"),
+	}
+	filex := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-kiosk/prerm.tmpl",
+		FileModTime: time.Unix(1792003605, 0),
+
+		Content: string("#!/bin/sh\nset -e\n\nif [ \"$1\" = \"remove\" ] && [ -d /run/systemd/system ]; then\n    systemctl disable --now {{shellquote .packageName \"-kiosk.service\"}} || true\n    systemctl enable getty@tty1.service >/dev/null 2>&1 || true\nfi\n"),
+	}
+	filed9 := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-nix/default.nix.tmpl",
+		FileModTime: time.Unix(1792031986, 0),
+
+		Content: string("# The derivation of the app, made from the build output of hover in ./app.\n# Install it with `nix-env -f . -i`, or with the flake.\n{ pkgs ? import <nixpkgs> { } }:\n\npkgs.stdenv.mkDerivation {\n  pname = {{nixquote .packageName}};\n  version = {{nixquote .version}};\n  src = ./.;\n\n  nativeBuildInputs = [ pkgs.autoPatchelfHook pkgs.makeWrapper ];\n  # the libraries of the engine, go-flutter and GLFW\n  buildInputs = with pkgs; [\n    stdenv.cc.cc.lib\n    libGL\n{{- if eq .displayServer \"wayland\"}}\n    wayland\n    libxkbcommon\n{{- else}}\n    xorg.libX11\n    xorg.libXcursor\n    xorg.libXi\n    xorg.libXinerama\n    xorg.libXrandr\n    xorg.libXxf86vm\n{{- end}}\n  ];\n\n  dontBuild = true;\n\n  installPhase = ''\n    runHook preInstall\n    mkdir -p $out/lib/{{shellquote .packageName}} $out/bin\n    cp -r app/. $out/lib/{{shellquote .packageName}}\n    makeWrapper $out/lib/{{shellquote .packageName \"/\" .executableName}} $out/bin/{{shellquote .executableName}}\n    install -Dm644 {{shellquote .executableName \".desktop\"}} $out/share/applications/{{shellquote .executableName \".desktop\"}}\n    install -Dm644 app/assets/icon.png $out/share/icons/hicolor/512x512/apps/{{shellquote .packageName \".png\"}}\n    runHook postInstall\n  '';\n\n  meta = {\n    description = {{nixquote .description}};\n    license = pkgs.lib.getLicenseFromSpdxId {{nixquote .license}};\n    platforms = [ {{nixquote .gnuArch \"-linux\"}} ];\n    mainProgram = {{nixquote .executableName}};\n    sourceProvenance = [ pkgs.lib.sourceTypes.binaryNativeCode ];\n  };\n}\n"),
+	}
+	fileda := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-nix/flake.nix.tmpl",
+		FileModTime: time.Unix(1792031986, 0),
+
+		Content: string("{\n  description = {{nixquote .description}};\n\n  inputs.nixpkgs.url = \"github:NixOS/nixpkgs/nixos-unstable\";\n\n  outputs = { self, nixpkgs }:\n    let\n      system = {{nixquote .gnuArch \"-linux\"}};\n      pkgs = nixpkgs.legacyPackages.${system};\n    in\n    {\n      packages.${system}.default = import ./default.nix { inherit pkgs; };\n      apps.${system}.default = {\n        type = \"app\";\n        program = pkgs.lib.getExe self.packages.${system}.default;\n      };\n    };\n}\n"),
+	}
+	filez := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-overlay/sysusers.conf.tmpl",
+		FileModTime: time.Unix(1792003671, 0),
+
+		Content: string("# The kiosk session runs as a dedicated user, created by systemd-sysusers on\n# the first boot of the image.\nu {{.packageName}} - \"{{.applicationName}} kiosk session\" /var/lib/{{.packageName}} /usr/sbin/nologin\nm {{.packageName}} video\nm {{.packageName}} input\nm {{.packageName}} render\nm {{.packageName}} audio\n"),
+	}
+	file10 := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-overlay/tmpfiles.conf.tmpl",
+		FileModTime: time.Unix(1792003671, 0),
+
+		Content: string("d /var/lib/{{.packageName}} 0750 {{.packageName}} {{.packageName}} -\n"),
+	}
+	filec7 := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-pacman/PKGBUILD.tmpl",
+		FileModTime: time.Unix(1792029369, 0),
+
+		Content: string("pkgname={{.packageName}}\npkgver={{.version}}\npkgrel={{.release}}\npkgdesc={{shellquote .description}}\narch=(\"{{.gnuArch}}\")\nlicense=({{shellquote .license}})\ninstall={{.packageName}}.install\noptions=(\"!strip\" \"!debug\")\n\npackage() {\n    cp -r \"$srcdir\"/. \"$pkgdir\"/\n}\n"),
+	}
+	filec8 := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-pacman/app.install.tmpl",
+		FileModTime: time.Unix(1792029369, 0),
+
+		Content: string("post_install() {\n    if command -v update-desktop-database >/dev/null 2>&1; then\n        update-desktop-database -q /usr/share/applications\n    fi\n{{- if .gsettingsSchema}}\n    glib-compile-schemas /usr/share/glib-2.0/schemas\n{{- end}}\n{{- if .mimePackage}}\n    if command -v update-mime-database >/dev/null 2>&1; then\n        update-mime-database /usr/share/mime\n    fi\n{{- end}}\n}\n\npost_upgrade() {\n    post_install\n}\n{{- if .uninstallURL}}\n\npre_remove() {\n    # Uninstall survey, opted in with survey.opt-in in go/hover.yaml\n    (curl -fsS -m 5 -o /dev/null {{shellquote .uninstallURL}} || wget -q -T 5 -O /dev/null {{shellquote .uninstallURL}}) >/dev/null 2>&1 || true\n}\n{{- end}}\n\npost_remove() {\n    post_install\n}\n"),
+	}
+	file12 := &embedded.EmbeddedFile{
 		Filename:    "packaging/linux-pkg/PKGBUILD.tmpl",
 		FileModTime: time.Unix(1587471688, 0),
 
-		Content: string("pkgname={{.packageName}}\npkgver={{.version}}\npkgrel={{.release}}\npkgdesc=\"{{.description}}\"\narch=(\"x86_64\")\nlicense=('{{.license}}')\n\npackage() {\n    mkdir -p $pkgdir/\n    cp * $pkgdir/ -r\n}\n"),
+		Content: string("pkgname={{.packageName}}\npkgver={{.version}}\npkgrel={{.release}}\npkgdesc={{shellquote .description}}\narch=(\"{{.gnuArch}}\")\nlicense=({{shellquote .license}})\n\npackage() {\n    mkdir -p $pkgdir/\n    cp * $pkgdir/ -r\n}\n"),
 	}
-	files := &embedded.EmbeddedFile{
+	file14 := &embedded.EmbeddedFile{
 		Filename:    "packaging/linux-rpm/app.spec.tmpl",
 		FileModTime: time.Unix(1587471688, 0),
 
-		Content: string("Name: {{.packageName}}\nVersion: {{.version}}\nRelease: {{.release}}\nSummary: {{.description}}\nLicense: {{.license}}\n\n%description\n{{.description}}\n\n%install\nmkdir -p $RPM_BUILD_ROOT%{_bindir}\nmkdir -p $RPM_BUILD_ROOT/usr/lib/{{.packageName}}\nmkdir -p $RPM_BUILD_ROOT%{_datadir}/applications\ncp -R $RPM_BUILD_DIR/{{.packageName}}-{{.version}}-{{.release}}.x86_64/* $RPM_BUILD_ROOT\nchmod 0755 $RPM_BUILD_ROOT%{_bindir}/{{.executableName}}\nchmod 0755 $RPM_BUILD_ROOT%{_datadir}/applications/{{.executableName}}.desktop\n\n%files\n%{_bindir}/{{.executableName}}\n/usr/lib/{{.packageName}}/\n%{_datadir}/applications/{{.executableName}}.desktop"),
+		Content: string("Name: {{.packageName}}\nVersion: {{.version}}\nRelease: {{.release}}\nSummary: {{.description}}\nLicense: {{.license}}\n{{- if .dataPackageName}}\nRequires: {{.dataPackageName}} = {{.version}}-{{.release}}\n{{- end}}\n{{- if .rpmRequires}}\nRequires: {{.rpmRequires}}\n{{- end}}\n{{- if .rpmBuildRequires}}\nBuildRequires: {{.rpmBuildRequires}}\n{{- end}}\n{{- if .rpmProvides}}\nProvides: {{.rpmProvides}}\n{{- end}}\n{{- if .rpmObsoletes}}\nObsoletes: {{.rpmObsoletes}}\n{{- end}}\n\n%description\n{{.description}}\n\n%install\nmkdir -p $RPM_BUILD_ROOT%{_bindir}\nmkdir -p $RPM_BUILD_ROOT/usr/lib/{{.packageName}}\nmkdir -p $RPM_BUILD_ROOT%{_datadir}/applications\ncp -R $RPM_BUILD_DIR/{{.packageName}}-{{.version}}-{{.release}}.{{.gnuArch}}/* $RPM_BUILD_ROOT\nchmod 0755 $RPM_BUILD_ROOT%{_bindir}/{{.executableName}}\nchmod 0755 $RPM_BUILD_ROOT%{_datadir}/applications/{{.desktopFileName}}.desktop\n\n%files\n%{_bindir}/{{.executableName}}\n/usr/lib/{{.packageName}}/\n%{_datadir}/applications/{{.desktopFileName}}.desktop\n%{_datadir}/icons/hicolor/*/apps/{{.iconName}}.*\n%{_datadir}/metainfo/{{.appstreamID}}.metainfo.xml\n{{- if .dbusName}}\n%{_datadir}/dbus-1/services/{{.dbusName}}.service\n{{- end}}\n{{- if .gsettingsSchema}}\n%{_datadir}/glib-2.0/schemas/{{.gsettingsSchema}}.gschema.xml\n{{- end}}\n{{- if .mimePackage}}\n%{_datadir}/mime/packages/{{.mimePackage}}.xml\n{{- end}}\n{{- if .mimeIcons}}\n%{_datadir}/icons/hicolor/256x256/mimetypes/{{.mimePackage}}-*.png\n{{- end}}\n\n%post\ntouch --no-create %{_datadir}/icons/hicolor &>/dev/null || :\ngtk-update-icon-cache -q %{_datadir}/icons/hicolor &>/dev/null || :\n{{- if .gsettingsSchema}}\nglib-compile-schemas %{_datadir}/glib-2.0/schemas &>/dev/null || :\n{{- end}}\n{{- if .mimePackage}}\nupdate-mime-database %{_datadir}/mime &>/dev/null || :\n{{- end}}\n\n%postun\ntouch --no-create %{_datadir}/icons/hicolor &>/dev/null || :\ngtk-update-icon-cache -q %{_datadir}/icons/hicolor &>/dev/null || :\n{{- if .gsettingsSchema}}\nglib-compile-schemas %{_datadir}/glib-2.0/schemas &>/dev/null || :\n{{- end}}\n{{- if .mimePackage}}\nupdate-mime-database %{_datadir}/mime &>/dev/null || :\n{{- end}}\n{{- if .uninstallURL}}\n\n%preun\n# Uninstall survey, opted in with survey.opt-in in go/hover.yaml\nif [ $1 -eq 0 ]; then\n    (curl -fsS -m 5 -o /dev/null {{shellquote .uninstallURL}} || wget -q -T 5 -O /dev/null {{shellquote .uninstallURL}}) >/dev/null 2>&1 || true\nfi\n{{- end}}\n"),
 	}
-	fileu := &embedded.EmbeddedFile{
+	filedl := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-run/install.sh.tmpl",
+		FileModTime: time.Unix(1792032915, 0),
+
+		Content: string("#!/bin/sh\n# Installs {{.applicationName}}, run by the makeself installer once extracted.\n# The options are given after --, e.g.\n#   ./{{.packageName}}-{{.version}}.run -- --prefix ~/apps --no-desktop\nset -e\n\nprefix={{shellquote .makeselfPrefix}}\ndesktop={{.makeselfDesktopIntegration}}\nwhile [ $# -gt 0 ]; do\n\tcase \"$1\" in\n\t--prefix) prefix=\"$2\"; shift ;;\n\t--prefix=*) prefix=\"${1#--prefix=}\" ;;\n\t--no-desktop) desktop=false ;;\n\t*) echo \"Unknown option $1, the options are --prefix <directory> and --no-desktop\" >&2; exit 1 ;;\n\tesac\n\tshift\ndone\n\nif [ \"$(id -u)\" = 0 ]; then\n\tprefix=\"${prefix:-/opt}\"\n\tbin_dir=/usr/local/bin\n\tdata_dir=/usr/local/share\nelse\n\tprefix=\"${prefix:-$HOME/.local/opt}\"\n\tbin_dir=\"$HOME/.local/bin\"\n\tdata_dir=\"${XDG_DATA_HOME:-$HOME/.local/share}\"\nfi\ncase \"$prefix\" in\n/*) ;;\n# makeself runs the script in the extracted directory\n*) prefix=\"${USER_PWD:-$PWD}/$prefix\" ;;\nesac\ncase \"$prefix\" in\n*[\\\"\\$\\`\\\\\\|\\&]*) echo \"The prefix can't contain \\\", \\$, \\`, \\\\, | or &\" >&2; exit 1 ;;\nesac\napp_dir=\"$prefix\"/{{shellquote .packageName}}\n\necho \"Installing {{.applicationName}} to $app_dir\"\nrm -rf \"$app_dir\"\nmkdir -p \"$app_dir\"\ncp -R app {{shellquote .executableName}} \"$app_dir\"/\nmkdir -p \"$bin_dir\"\nln -sf \"$app_dir\"/{{shellquote .executableName}} \"$bin_dir\"/{{shellquote .executableName}}\n{\n\techo '#!/bin/sh'\n\techo '# Uninstalls {{.applicationName}}'\n\techo \"rm -f \\\"$bin_dir\\\"/{{shellquote .executableName}}\"\n} > \"$app_dir\"/uninstall.sh\n\nif [ \"$desktop\" = true ]; then\n\tmkdir -p \"$data_dir\"/applications\n\t# the paths of the .desktop file are the ones of the install directory\n\tsed -e \"s|^Exec=.*|Exec=\\\"$app_dir/{{.executableName}}\\\"|\" -e \"s|^Icon=.*|Icon=$app_dir/app/assets/icon.png|\" {{shellquote .executableName \".desktop\"}} > \"$data_dir\"/applications/{{shellquote .executableName \".desktop\"}}\n\techo \"rm -f \\\"$data_dir\\\"/applications/{{shellquote .executableName \".desktop\"}}\" >> \"$app_dir\"/uninstall.sh\n\tupdate-desktop-database \"$data_dir\"/applications >/dev/null 2>&1 || true\nfi\necho \"rm -rf \\\"$app_dir\\\"\" >> \"$app_dir\"/uninstall.sh\nchmod 755 \"$app_dir\"/uninstall.sh\n\necho \"{{.applicationName}} is installed, run it with {{.executableName}} and uninstall it with $app_dir/uninstall.sh\"\ncase \":$PATH:\" in\n*:\"$bin_dir\":*) ;;\n*) echo \"Add $bin_dir to the PATH to run {{.executableName}} from a terminal\" ;;\nesac\n"),
+	}
+	filecg := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-runimage/loader.sh.tmpl",
+		FileModTime: time.Unix(1792029897, 0),
+
+		Content: string("#!/bin/sh\n# {{.applicationName}} {{.version}}: this loader followed by a squashfs image of\n# the app. The image is mounted with squashfuse when FUSE is available, and\n# extracted to the user cache otherwise (or with RUNIMAGE_EXTRACT=1).\nset -e\nself=\"$(readlink -f \"$0\")\"\noffset=__OFFSET__\ncache=\"${XDG_CACHE_HOME:-$HOME/.cache}/\"{{shellquote .packageName \"-runimage/\" .version}}\n\nif [ -z \"$RUNIMAGE_EXTRACT\" ] && [ -c /dev/fuse ] && command -v squashfuse >/dev/null 2>&1; then\n    mountpoint=\"$(mktemp -d)\"\n    if squashfuse -o offset=\"$offset\" \"$self\" \"$mountpoint\" 2>/dev/null; then\n        trap 'fusermount -u \"$mountpoint\" 2>/dev/null || umount \"$mountpoint\"; rmdir \"$mountpoint\"' EXIT\n        trap 'exit 130' INT TERM\n        status=0\n        \"$mountpoint/AppRun\" \"$@\" || status=$?\n        exit $status\n    fi\n    rmdir \"$mountpoint\"\nfi\n\nif [ ! -x \"$cache/AppRun\" ]; then\n    if ! command -v unsquashfs >/dev/null 2>&1; then\n        echo \"Running the app requires squashfuse or unsquashfs (squashfs-tools).\" >&2\n        exit 1\n    fi\n    rm -rf \"$cache.partial\"\n    mkdir -p \"$(dirname \"$cache\")\"\n    unsquashfs -quiet -no-progress -offset \"$offset\" -dest \"$cache.partial\" \"$self\" >/dev/null\n    rm -rf \"$cache\"\n    mv \"$cache.partial\" \"$cache\"\nfi\nexec \"$cache/AppRun\" \"$@\"\n"),
+	}
+	file16 := &embedded.EmbeddedFile{
 		Filename:    "packaging/linux-snap/snapcraft.yaml.tmpl",
 		FileModTime: time.Unix(1587423157, 0),
 
-		Content: string("name: {{.packageName}}\nbase: core18\nversion: '{{.version}}'\nsummary: {{.description}}\ndescription: |\n  {{.description}}\nconfinement: devmode\ngrade: devel\napps:\n  {{.packageName}}:\n    command: {{.executableName}}\n    desktop: local/{{.executableName}}.desktop\nparts:\n  desktop:\n    plugin: dump\n    source: snap\n  assets:\n    plugin: dump\n    source: build/assets\n  app:\n    plugin: dump\n    source: build\n    stage-packages:\n      - libx11-6\n      - libxrandr2\n      - libxcursor1\n      - libxinerama1\n"),
+		Content: string("name: {{.packageName}}\nbase: core18\nversion: '{{.version}}'\nsummary: {{toJson .description}}\ndescription: |\n  {{.description}}\nconfinement: devmode\ngrade: devel\nadopt-info: metainfo\napps:\n  {{.packageName}}:\n    command: {{.executableName}}\n    desktop: local/{{.executableName}}.desktop\n    plugs:\n      - opengl\n{{- if eq .displayServer \"wayland\"}}\n      - wayland\n{{- else}}\n      - x11\n{{- end}}\nparts:\n  desktop:\n    plugin: dump\n    source: snap\n  assets:\n    plugin: dump\n    source: build/assets\n  icons:\n    plugin: dump\n    source: icons\n  metainfo:\n    plugin: dump\n    source: metainfo\n    parse-info: [usr/share/metainfo/{{.appstreamID}}.metainfo.xml]\n  app:\n    plugin: dump\n    source: build\n    stage-packages:\n{{- if eq .displayServer \"wayland\"}}\n      - libwayland-client0\n      - libwayland-cursor0\n      - libwayland-egl1\n      - libxkbcommon0\n{{- else}}\n      - libx11-6\n      - libxrandr2\n      - libxcursor1\n      - libxinerama1\n{{- end}}\n"),
 	}
-	filew := &embedded.EmbeddedFile{
+	filed1 := &embedded.EmbeddedFile{
+		Filename:    "packaging/linux-tar/launcher.sh.tmpl",
+		FileModTime: time.Unix(1792031177, 0),
+
+		Content: string("#!/bin/sh\n# Starts {{.applicationName}} from the directory the archive is extracted to,\n# also when the script is linked from another directory.\napp_dir=\"$(dirname \"$(readlink -f \"$0\")\")/app\"\n{{- with .launcherSetup}}\n{{.}}\n{{- end}}\nexec \"$app_dir\"/{{shellquote .executableName}} \"$@\"\n"),
+	}
+	filecn := &embedded.EmbeddedFile{
+		Filename:    "packaging/windows-choco/chocolateyInstall.ps1.tmpl",
+		FileModTime: time.Unix(1792030335, 0),
+
+		Content: string("$ErrorActionPreference = 'Stop'\n$toolsDir = Split-Path -Parent $MyInvocation.MyCommand.Definition\n$msi = Get-ChildItem -Path $toolsDir -Filter '*.msi' | Select-Object -First 1\n\n# The uninstall is handled by the automatic uninstaller of Chocolatey, from\n# the registration of the msi.\n$packageArgs = @{\n    packageName    = $env:ChocolateyPackageName\n    fileType       = 'msi'\n    file64         = $msi.FullName\n    silentArgs     = \"/qn /norestart /l*v `\"$env:TEMP\\$env:ChocolateyPackageName.$env:ChocolateyPackageVersion.MsiInstall.log`\"\"\n    validExitCodes = @(0, 3010, 1641)\n}\nInstall-ChocolateyInstallPackage @packageArgs\n\n# The msi isn't needed once installed\nRemove-Item -Force -ErrorAction SilentlyContinue $msi.FullName\n"),
+	}
+	filecm := &embedded.EmbeddedFile{
+		Filename:    "packaging/windows-choco/package.nuspec.tmpl",
+		FileModTime: time.Unix(1792030335, 0),
+
+		Content: string("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n<package xmlns=\"http://schemas.microsoft.com/packaging/2015/06/nuspec.xsd\">\n    <metadata>\n        <id>{{.packageName | replace \"_\" \"-\" | lower | xmlescape}}</id>\n        <version>{{.chocolateyVersion}}</version>\n        <title>{{xmlescape .applicationName}}</title>\n        <authors>{{xmlescape .author}}</authors>\n        <description>{{xmlescape .description}}</description>\n        <tags>{{.packageName | replace \"_\" \"-\" | lower | xmlescape}} desktop flutter</tags>\n    </metadata>\n    <files>\n        <file src=\"tools\\**\" target=\"tools\"/>\n    </files>\n</package>\n"),
+	}
+	fileci := &embedded.EmbeddedFile{
+		Filename:    "packaging/windows-inno/installer.iss.tmpl",
+		FileModTime: time.Unix(1792029997, 0),
+
+		Content: string("; The install mode, shortcuts and app mutex are configured in the inno section\n; of go/hover.yaml.\n[Setup]\nAppId={{.organizationName}}.{{.packageName}}\nAppName={{.applicationName | replace \"{\" \"{{\"}}\nAppVersion={{.version}}\nAppPublisher={{.author | replace \"{\" \"{{\"}}\nDefaultDirName={autopf}\\{{.applicationName | replace \"{\" \"{{\"}}\nDisableProgramGroupPage=yes\nPrivilegesRequired={{.innoPrivilegesRequired}}\n{{- if .innoPrivilegesOverridesAllowed}}\nPrivilegesRequiredOverridesAllowed={{.innoPrivilegesOverridesAllowed}}\n{{- end}}\n{{- if eq .arch \"arm64\"}}\nArchitecturesAllowed=arm64\nArchitecturesInstallIn64BitMode=arm64\n{{- else}}\nArchitecturesAllowed=x64compatible\nArchitecturesInstallIn64BitMode=x64compatible\n{{- end}}\n{{- if .innoAppMutex}}\nAppMutex={{.innoAppMutex | replace \"{\" \"{{\"}}\n{{- end}}\n#if FileExists(AddBackslash(SourcePath) + \"LICENSE.txt\")\nLicenseFile=LICENSE.txt\n#endif\nSetupIconFile=build\\assets\\icon.ico\nUninstallDisplayIcon={app}\\{{.executableName}}.exe\nOutputDir=.\nOutputBaseFilename=setup\nCompression=lzma2\nSolidCompression=yes\nWizardStyle=modern\n\n[Languages]\nName: \"english\"; MessagesFile: \"compiler:Default.isl\"\n{{- if eq .innoDesktopShortcut \"true\"}}\n\n[Tasks]\nName: \"desktopicon\"; Description: \"{cm:CreateDesktopIcon}\"; GroupDescription: \"{cm:AdditionalIcons}\"\n{{- end}}\n\n[Files]\nSource: \"build\\*\"; DestDir: \"{app}\"; Flags: ignoreversion recursesubdirs createallsubdirs\n\n[Icons]\n{{- if eq .innoStartMenuShortcut \"true\"}}\nName: {{innoquote \"{autoprograms}\\\\\" .applicationName}}; Filename: {{innoquote \"{app}\\\\\" .executableName \".exe\"}}\n{{- end}}\n{{- if eq .innoDesktopShortcut \"true\"}}\nName: {{innoquote \"{autodesktop}\\\\\" .applicationName}}; Filename: {{innoquote \"{app}\\\\\" .executableName \".exe\"}}; Tasks: desktopicon\n{{- end}}\n{{- if .urlSchemes}}\n\n[Registry]\n; URL schemes, url-schemes in go/hover.yaml\n{{- range fields .urlSchemes}}\nRoot: HKA; Subkey: \"Software\\Classes\\{{.}}\"; ValueType: string; ValueName: \"\"; ValueData: {{innoquote \"URL:\" $.applicationName}}; Flags: uninsdeletekey\nRoot: HKA; Subkey: \"Software\\Classes\\{{.}}\"; ValueType: string; ValueName: \"URL Protocol\"; ValueData: \"\"\nRoot: HKA; Subkey: \"Software\\Classes\\{{.}}\\DefaultIcon\"; ValueType: string; ValueName: \"\"; ValueData: {{innoquote \"{app}\\\\\" $.executableName \".exe,0\"}}\nRoot: HKA; Subkey: \"Software\\Classes\\{{.}}\\shell\\open\\command\"; ValueType: string; ValueName: \"\"; ValueData: \"\"\"{app}\\{{$.executableName}}.exe\"\" \"\"%1\"\"\"\n{{- end}}\n{{- end}}\n\n[Run]\nFilename: {{innoquote \"{app}\\\\\" .executableName \".exe\"}}; Description: {{innoquote \"Launch \" .applicationName}}; Flags: nowait postinstall skipifsilent\n{{- if .uninstallURL}}\n\n[Code]\n// Uninstall survey, opted in with survey.opt-in in go/hover.yaml\nprocedure CurUninstallStepChanged(CurUninstallStep: TUninstallStep);\nvar\n  ErrorCode: Integer;\nbegin\n  if (CurUninstallStep = usPostUninstall) and not UninstallSilent then\n    ShellExec('open', '{{.uninstallURL | replace \"'\" \"''\"}}', '', '', SW_SHOWNORMAL, ewNoWait, ErrorCode);\nend;\n{{- end}}\n"),
+	}
+	file18 := &embedded.EmbeddedFile{
 		Filename:    "packaging/windows-msi/app.wxs.tmpl",
 		FileModTime: time.Unix(1587428338, 0),
 
-		Content: string("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<Wix xmlns=\"http://schemas.microsoft.com/wix/2006/wi\">\n    <Product Id=\"*\" UpgradeCode=\"*\" Version=\"{{.version}}\" Language=\"1033\" Name=\"{{.applicationName}}\" Manufacturer=\"{{.author}}\">\n        <Package InstallerVersion=\"300\" Compressed=\"yes\"/>\n        <Media Id=\"1\" Cabinet=\"{{.packageName}}.cab\" EmbedCab=\"yes\" />\n        <Directory Id=\"TARGETDIR\" Name=\"SourceDir\">\n            <Directory Id=\"ProgramFilesFolder\">\n                <Directory Id=\"APPLICATIONROOTDIRECTORY\" Name=\"{{.applicationName}}\">\n                    <Directory Id=\"ASSETSDIRECTORY\" Name=\"assets\"/>\n                    <Directory Id=\"FLUTTERASSETSDIRECTORY\" Name=\"flutter_assets\">\n                        <?include directories.wxi ?>\n                    </Directory>\n                </Directory>\n            </Directory>\n            <Directory Id=\"ProgramMenuFolder\">\n                <Directory Id=\"ApplicationProgramsFolder\" Name=\"{{.applicationName}}\"/>\n            </Directory>\n        </Directory>\n        <Icon Id=\"ShortcutIcon\" SourceFile=\"build/assets/icon.ico\"/>\n        <DirectoryRef Id=\"APPLICATIONROOTDIRECTORY\">\n            <Component Id=\"{{.executableName}}.exe\" Guid=\"*\">\n                <File Id=\"{{.executableName}}.exe\" Source=\"build/{{.executableName}}.exe\" KeyPath=\"yes\"/>\n            </Component>\n            <Component Id=\"flutter_engine.dll\" Guid=\"*\">\n                <File Id=\"flutter_engine.dll\" Source=\"build/flutter_engine.dll\" KeyPath=\"yes\"/>\n            </Component>\n            <Component Id=\"icudtl.dat\" Guid=\"*\">\n                <File Id=\"icudtl.dat\" Source=\"build/icudtl.dat\" KeyPath=\"yes\"/>\n            </Component>\n        </DirectoryRef>\n        <DirectoryRef Id=\"ASSETSDIRECTORY\">\n            <Component Id=\"icon.png\" Guid=\"*\">\n                <File Id=\"icon.png\" Source=\"build/assets/icon.png\" KeyPath=\"yes\"/>\n            </Component>\n        </DirectoryRef>\n        <?include directory_refs.wxi ?>\n        <DirectoryRef Id=\"ApplicationProgramsFolder\">\n            <Component Id=\"ApplicationShortcut\" Guid=\"*\">\n                <Shortcut Id=\"ApplicationStartMenuShortcut\"\n                          Name=\"{{.applicationName}}\"\n                          Description=\"{{.description}}\"\n                          Target=\"[#{{.executableName}}.exe]\"\n                          WorkingDirectory=\"APPLICATIONROOTDIRECTORY\"\n                          Icon=\"ShortcutIcon\"/>\n                <RemoveFolder Id=\"CleanUpShortCut\" On=\"uninstall\"/>\n                <RegistryValue Root=\"HKCU\" Key=\"Software\\{{.author}}\\{{.packageName}}\" Name=\"installed\" Type=\"integer\" Value=\"1\" KeyPath=\"yes\"/>\n            </Component>\n        </DirectoryRef>\n        <Feature Id=\"MainApplication\" Title=\"{{.applicationName}}\" Level=\"1\">\n            <ComponentRef Id=\"{{.executableName}}.exe\"/>\n            <ComponentRef Id=\"flutter_engine.dll\"/>\n            <ComponentRef Id=\"icudtl.dat\"/>\n            <ComponentRef Id=\"icon.png\"/>\n            <ComponentRef Id=\"ApplicationShortcut\"/>\n            <?include component_refs.wxi ?>\n        </Feature>\n    </Product>\n</Wix>"),
+		Content: string("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<Wix xmlns=\"http://schemas.microsoft.com/wix/2006/wi\">\n    <Product Id=\"*\" UpgradeCode=\"*\" Version=\"{{.version}}\" Language=\"1033\" Name=\"{{xmlescape .applicationName}}\" Manufacturer=\"{{xmlescape .author}}\">\n        <Package InstallerVersion=\"300\" Compressed=\"yes\"/>\n        <Media Id=\"1\" Cabinet=\"{{.packageName}}.cab\" EmbedCab=\"yes\" />\n        <Directory Id=\"TARGETDIR\" Name=\"SourceDir\">\n            <Directory Id=\"ProgramFilesFolder\">\n                <Directory Id=\"APPLICATIONROOTDIRECTORY\" Name=\"{{xmlescape .applicationName}}\">\n                    <Directory Id=\"ASSETSDIRECTORY\" Name=\"assets\"/>\n                    <Directory Id=\"FLUTTERASSETSDIRECTORY\" Name=\"flutter_assets\">\n                        <?include directories.wxi ?>\n                    </Directory>\n                </Directory>\n            </Directory>\n            <Directory Id=\"ProgramMenuFolder\">\n                <Directory Id=\"ApplicationProgramsFolder\" Name=\"{{xmlescape .applicationName}}\"/>\n            </Directory>\n        </Directory>\n        <Icon Id=\"ShortcutIcon\" SourceFile=\"build/assets/icon.ico\"/>\n        <DirectoryRef Id=\"APPLICATIONROOTDIRECTORY\">\n            <Component Id=\"{{.executableName}}.exe\" Guid=\"*\">\n                <File Id=\"{{.executableName}}.exe\" Source=\"build/{{.executableName}}.exe\" KeyPath=\"yes\"/>\n            </Component>\n            <Component Id=\"flutter_engine.dll\" Guid=\"*\">\n                <File Id=\"flutter_engine.dll\" Source=\"build/flutter_engine.dll\" KeyPath=\"yes\"/>\n            </Component>\n            <Component Id=\"icudtl.dat\" Guid=\"*\">\n                <File Id=\"icudtl.dat\" Source=\"build/icudtl.dat\" KeyPath=\"yes\"/>\n            </Component>\n        </DirectoryRef>\n        <DirectoryRef Id=\"ASSETSDIRECTORY\">\n            <Component Id=\"icon.png\" Guid=\"*\">\n                <File Id=\"icon.png\" Source=\"build/assets/icon.png\" KeyPath=\"yes\"/>\n            </Component>\n        </DirectoryRef>\n        <?include directory_refs.wxi ?>\n        <?include services.wxi ?>\n        <?include preferences.wxi ?>\n        <?include file_associations.wxi ?>\n{{- if .urlSchemes}}\n        <!-- URL schemes, url-schemes in go/hover.yaml -->\n        <DirectoryRef Id=\"APPLICATIONROOTDIRECTORY\">\n            <Component Id=\"URLSchemes\" Guid=\"*\">\n{{- range fields .urlSchemes}}\n                <RegistryKey Root=\"HKCR\" Key=\"{{.}}\">\n                    <RegistryValue Type=\"string\" Value=\"URL:{{xmlescape $.applicationName}}\"/>\n                    <RegistryValue Name=\"URL Protocol\" Type=\"string\" Value=\"\"/>\n                    <RegistryValue Key=\"DefaultIcon\" Type=\"string\" Value=\"[#{{$.executableName}}.exe],0\"/>\n                    <RegistryValue Key=\"shell\\open\\command\" Type=\"string\" Value=\"&quot;[#{{$.executableName}}.exe]&quot; &quot;%1&quot;\"/>\n                </RegistryKey>\n{{- end}}\n                <RegistryValue Root=\"HKCU\" Key=\"Software\\{{xmlescape .author}}\\{{.packageName}}\" Name=\"urlSchemes\" Type=\"string\" Value=\"{{.urlSchemes}}\" KeyPath=\"yes\"/>\n            </Component>\n        </DirectoryRef>\n{{- end}}\n        <DirectoryRef Id=\"ApplicationProgramsFolder\">\n            <Component Id=\"ApplicationShortcut\" Guid=\"*\">\n                <Shortcut Id=\"ApplicationStartMenuShortcut\"\n                          Name=\"{{xmlescape .applicationName}}\"\n                          Description=\"{{xmlescape .description}}\"\n                          Target=\"[#{{.executableName}}.exe]\"\n                          WorkingDirectory=\"APPLICATIONROOTDIRECTORY\"\n                          Icon=\"ShortcutIcon\"/>\n                <RemoveFolder Id=\"CleanUpShortCut\" On=\"uninstall\"/>\n                <RegistryValue Root=\"HKCU\" Key=\"Software\\{{xmlescape .author}}\\{{.packageName}}\" Name=\"installed\" Type=\"integer\" Value=\"1\" KeyPath=\"yes\"/>\n            </Component>\n        </DirectoryRef>\n{{- if .uninstallURL}}\n        <!-- Uninstall survey, opted in with survey.opt-in in go/hover.yaml -->\n        <CustomAction Id=\"UninstallSurvey\" Directory=\"TARGETDIR\" ExeCommand=\"rundll32.exe url.dll,FileProtocolHandler {{xmlescape .uninstallURL}}\" Execute=\"immediate\" Impersonate=\"yes\" Return=\"asyncNoWait\"/>\n        <InstallExecuteSequence>\n            <Custom Action=\"UninstallSurvey\" After=\"InstallFinalize\">REMOVE=\"ALL\" AND NOT UPGRADINGPRODUCTCODE</Custom>\n        </InstallExecuteSequence>\n{{- end}}\n        <Feature Id=\"MainApplication\" Title=\"{{xmlescape .applicationName}}\" Level=\"1\">\n            <ComponentRef Id=\"{{.executableName}}.exe\"/>\n            <ComponentRef Id=\"flutter_engine.dll\"/>\n            <ComponentRef Id=\"icudtl.dat\"/>\n            <ComponentRef Id=\"icon.png\"/>\n            <ComponentRef Id=\"ApplicationShortcut\"/>\n{{- if .urlSchemes}}\n            <ComponentRef Id=\"URLSchemes\"/>\n{{- end}}\n            <?include component_refs.wxi ?>\n        </Feature>\n    </Product>\n</Wix>\n"),
+	}
+	fileck := &embedded.EmbeddedFile{
+		Filename:    "packaging/windows-msix/AppxManifest.xml.tmpl",
+		FileModTime: time.Unix(1792030177, 0),
+
+		Content: string("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n<Package xmlns=\"http://schemas.microsoft.com/appx/manifest/foundation/windows10\"\n         xmlns:uap=\"http://schemas.microsoft.com/appx/manifest/uap/windows10\"\n         xmlns:rescap=\"http://schemas.microsoft.com/appx/manifest/foundation/windows10/restrictedcapabilities\"\n         IgnorableNamespaces=\"uap rescap\">\n    <!-- For the Microsoft Store, replace the Name and the Publisher with the\n         package identity of the app in Partner Center. Otherwise the\n         Publisher must be the subject of the signing certificate. -->\n    <Identity Name=\"{{xmlescape .organizationName}}.{{.packageName | replace \"_\" \"-\" | xmlescape}}\"\n              Publisher=\"CN={{xmlescape .author}}\"\n              Version=\"{{.msixVersion}}\"\n              ProcessorArchitecture=\"{{if eq .arch \"arm64\"}}arm64{{else}}x64{{end}}\"/>\n    <Properties>\n        <DisplayName>{{xmlescape .applicationName}}</DisplayName>\n        <PublisherDisplayName>{{xmlescape .author}}</PublisherDisplayName>\n        <Description>{{xmlescape .description}}</Description>\n        <Logo>Assets\\StoreLogo.png</Logo>\n    </Properties>\n    <Dependencies>\n        <TargetDeviceFamily Name=\"Windows.Desktop\" MinVersion=\"10.0.17763.0\" MaxVersionTested=\"10.0.22621.0\"/>\n    </Dependencies>\n    <Resources>\n        <Resource Language=\"en-us\"/>\n    </Resources>\n    <Applications>\n        <Application Id=\"App\" Executable=\"build\\{{xmlescape .executableName}}.exe\" EntryPoint=\"Windows.FullTrustApplication\">\n            <uap:VisualElements DisplayName=\"{{xmlescape .applicationName}}\"\n                                Description=\"{{xmlescape .description}}\"\n                                BackgroundColor=\"transparent\"\n                                Square150x150Logo=\"Assets\\Square150x150Logo.png\"\n                                Square44x44Logo=\"Assets\\Square44x44Logo.png\"/>\n            {{- if .urlSchemes}}\n            <Extensions>\n                {{- range fields .urlSchemes}}\n                <uap:Extension Category=\"windows.protocol\">\n                    <uap:Protocol Name=\"{{.}}\"/>\n                </uap:Extension>\n                {{- end}}\n            </Extensions>\n            {{- end}}\n        </Application>\n    </Applications>\n    <Capabilities>\n        <rescap:Capability Name=\"runFullTrust\"/>\n    </Capabilities>\n</Package>\n"),
+	}
+	filece := &embedded.EmbeddedFile{
+		Filename:    "packaging/windows-nsis/installer.nsi.tmpl",
+		FileModTime: time.Unix(1792029795, 0),
+
+		Content: string("Unicode true\n!include \"MUI2.nsh\"\n\n!define UNINSTALL_KEY \"Software\\Microsoft\\Windows\\CurrentVersion\\Uninstall\\{{.packageName}}\"\n\nName {{nsisquote .applicationName}}\nOutFile \"setup.exe\"\nInstallDir {{nsisquote \"$PROGRAMFILES64\\\\\" .applicationName}}\nInstallDirRegKey HKLM \"${UNINSTALL_KEY}\" \"InstallLocation\"\nRequestExecutionLevel admin\nSetCompressor /SOLID lzma\n\n!define MUI_ICON \"build\\assets\\icon.ico\"\n!define MUI_UNICON \"build\\assets\\icon.ico\"\n!define MUI_FINISHPAGE_RUN {{nsisquote \"$INSTDIR\\\\\" .executableName \".exe\"}}\n\n!insertmacro MUI_PAGE_WELCOME\n!if /FileExists \"LICENSE.txt\"\n!insertmacro MUI_PAGE_LICENSE \"LICENSE.txt\"\n!endif\n!insertmacro MUI_PAGE_DIRECTORY\n!insertmacro MUI_PAGE_INSTFILES\n!insertmacro MUI_PAGE_FINISH\n!insertmacro MUI_UNPAGE_CONFIRM\n!insertmacro MUI_UNPAGE_INSTFILES\n!insertmacro MUI_LANGUAGE \"English\"\n\nSection \"Install\"\n    SetRegView 64\n    SetOutPath \"$INSTDIR\"\n    File /r \"build\\*\"\n    WriteUninstaller \"$INSTDIR\\uninstall.exe\"\n\n    CreateDirectory {{nsisquote \"$SMPROGRAMS\\\\\" .applicationName}}\n    CreateShortcut {{nsisquote \"$SMPROGRAMS\\\\\" .applicationName \"\\\\\" .applicationName \".lnk\"}} {{nsisquote \"$INSTDIR\\\\\" .executableName \".exe\"}}\n    CreateShortcut {{nsisquote \"$DESKTOP\\\\\" .applicationName \".lnk\"}} {{nsisquote \"$INSTDIR\\\\\" .executableName \".exe\"}}\n\n    WriteRegStr HKLM \"${UNINSTALL_KEY}\" \"DisplayName\" {{nsisquote .applicationName}}\n    WriteRegStr HKLM \"${UNINSTALL_KEY}\" \"DisplayVersion\" \"{{.version}}\"\n    WriteRegStr HKLM \"${UNINSTALL_KEY}\" \"Publisher\" {{nsisquote .author}}\n    WriteRegStr HKLM \"${UNINSTALL_KEY}\" \"DisplayIcon\" {{nsisquote \"$INSTDIR\\\\\" .executableName \".exe\"}}\n    WriteRegStr HKLM \"${UNINSTALL_KEY}\" \"InstallLocation\" \"$INSTDIR\"\n    WriteRegStr HKLM \"${UNINSTALL_KEY}\" \"UninstallString\" '\"$INSTDIR\\uninstall.exe\"'\n    WriteRegDWORD HKLM \"${UNINSTALL_KEY}\" \"NoModify\" 1\n    WriteRegDWORD HKLM \"${UNINSTALL_KEY}\" \"NoRepair\" 1\n{{- range fields .urlSchemes}}\n\n    ; URL scheme, url-schemes in go/hover.yaml\n    WriteRegStr HKLM \"Software\\Classes\\{{.}}\" \"\" {{nsisquote \"URL:\" $.applicationName}}\n    WriteRegStr HKLM \"Software\\Classes\\{{.}}\" \"URL Protocol\" \"\"\n    WriteRegStr HKLM \"Software\\Classes\\{{.}}\\DefaultIcon\" \"\" {{nsisquote \"$INSTDIR\\\\\" $.executableName \".exe,0\"}}\n    WriteRegStr HKLM \"Software\\Classes\\{{.}}\\shell\\open\\command\" \"\" '\"$INSTDIR\\{{$.executableName}}.exe\" \"%1\"'\n{{- end}}\nSectionEnd\n\nSection \"Uninstall\"\n    SetRegView 64\n    Delete {{nsisquote \"$DESKTOP\\\\\" .applicationName \".lnk\"}}\n    RMDir /r {{nsisquote \"$SMPROGRAMS\\\\\" .applicationName}}\n    RMDir /r \"$INSTDIR\"\n    DeleteRegKey HKLM \"${UNINSTALL_KEY}\"\n{{- range fields .urlSchemes}}\n    DeleteRegKey HKLM \"Software\\Classes\\{{.}}\"\n{{- end}}\n{{- if .uninstallURL}}\n    ; Uninstall survey, opted in with survey.opt-in in go/hover.yaml\n    IfSilent +2\n    ExecShell \"open\" {{nsisquote .uninstallURL}}\n{{- end}}\nSectionEnd\n"),
+	}
+	file1a := &embedded.EmbeddedFile{
+		Filename:    "packaging/windows-portable/launcher.cmd.tmpl",
+		FileModTime: time.Unix(1792003706, 0),
+
+		Content: string("@echo off\r\nrem Starts {{.applicationName}} from the app directory, so it finds its assets\r\nrem wherever the folder is extracted.\r\ncd /d \"%~dp0app\"\r\n{{- with .launcherSetupCmd}}\r\n{{.}}\r\n{{- end}}\r\nstart \"\" \"%~dp0app\\{{.executableName}}.exe\" %*\r\n"),
+	}
+	filect := &embedded.EmbeddedFile{
+		Filename:    "packaging/windows-scoop/manifest.json.tmpl",
+		FileModTime: time.Unix(1792030780, 0),
+
+		Content: string("{\n    \"version\": {{toJson .version}},\n    \"description\": {{toJson .description}},\n    {{- if .scoopHomepage}}\n    \"homepage\": {{toJson .scoopHomepage}},\n    {{- end}}\n    \"license\": {{toJson .license}},\n    \"architecture\": {\n        \"{{if eq .arch \"arm64\"}}arm64{{else}}64bit{{end}}\": {\n            \"url\": {{toJson .scoopUrl}},\n            \"hash\": \"\"\n        }\n    },\n    \"extract_dir\": {{toJson .applicationName}},\n    \"bin\": [\n        [{{toJson (print .applicationName \".cmd\")}}, {{toJson .executableName}}]\n    ],\n    \"shortcuts\": [\n        [{{toJson (print \"app\\\\\" .executableName \".exe\")}}, {{toJson .applicationName}}]\n    ]\n}\n"),
+	}
+	filecq := &embedded.EmbeddedFile{
+		Filename:    "packaging/windows-winget/installer.yaml.tmpl",
+		FileModTime: time.Unix(1792030677, 0),
+
+		Content: string("# yaml-language-server: $schema=https://aka.ms/winget-manifest.installer.1.6.0.schema.json\nPackageIdentifier: {{.wingetPackageIdentifier}}\nPackageVersion: {{toJson .version}}\nInstallerType: wix\nScope: machine\nUpgradeBehavior: install\nInstallers:\n  - Architecture: {{if eq .arch \"arm64\"}}arm64{{else}}x64{{end}}\n    InstallerUrl: {{toJson .wingetInstallerUrl}}\n    InstallerSha256: \"\" # Filled in by hover with the checksum of the msi\nManifestType: installer\nManifestVersion: 1.6.0\n"),
+	}
+	filecr := &embedded.EmbeddedFile{
+		Filename:    "packaging/windows-winget/locale.yaml.tmpl",
+		FileModTime: time.Unix(1792030677, 0),
+
+		Content: string("# yaml-language-server: $schema=https://aka.ms/winget-manifest.defaultLocale.1.6.0.schema.json\nPackageIdentifier: {{.wingetPackageIdentifier}}\nPackageVersion: {{toJson .version}}\nPackageLocale: en-US\nPublisher: {{toJson .wingetPublisher}}\nPackageName: {{toJson .applicationName}}\nLicense: {{toJson .license}}\nShortDescription: {{toJson .description}}\nTags:\n  - flutter\nManifestType: defaultLocale\nManifestVersion: 1.6.0\n"),
+	}
+	filecp := &embedded.EmbeddedFile{
+		Filename:    "packaging/windows-winget/version.yaml.tmpl",
+		FileModTime: time.Unix(1792030677, 0),
+
+		Content: string("# yaml-language-server: $schema=https://aka.ms/winget-manifest.version.1.6.0.schema.json\nPackageIdentifier: {{.wingetPackageIdentifier}}\nPackageVersion: {{toJson .version}}\nDefaultLocale: en-US\nManifestType: version\nManifestVersion: 1.6.0\n"),
 	}
-	filey := &embedded.EmbeddedFile{
+	file1c := &embedded.EmbeddedFile{
 		Filename:    "plugin/README.md.dlib.tmpl",
 		FileModTime: time.Unix(1587299806, 0),
 
 		Content: string("The `dlib` folder is used for the plugins which use `cgo`.\n\nIf your go-flutter plugin dose't use `cgo`, just ignore this file and the `dlib` folder.\n\nWhen you need to link prebuild dynamic libraries and frameworks,\nyou should copy the prebuild dynamic libraries and frameworks to `dlib`/${os} folder.\n\n`hover plugins get` copy this files to path `./go/build/intermediates` of go-flutter app project.\n`hover run` copy files from `./go/build/intermediates/${targetOS}` to `./go/build/outputs/${targetOS}`.\nAnd `-L{./go/build/outputs/${targetOS}}` is appended to `cgoLdflags` automatically.\nAlso `-F{./go/build/outputs/${targetOS}}` is appended to `cgoLdflags` on Mac OS\n\nAttention: `hover` can't resolve the conflicts\nif two different go-flutter plugins have file with the same name in there dlib folder\n"),
 	}
-	filez := &embedded.EmbeddedFile{
+	file1d := &embedded.EmbeddedFile{
 		Filename:    "plugin/README.md.tmpl",
 		FileModTime: time.Unix(1577653312, 0),
 
 		Content: string("# {{.pluginName}}\n\nThis Go package implements the host-side of the Flutter [{{.pluginName}}](https://{{.urlVSCRepo}}) plugin.\n\n## Usage\n\nImport as:\n\n```go\nimport {{.pluginName}} \"{{.urlVSCRepo}}/go\"\n```\n\nThen add the following option to your go-flutter [application options](https://github.com/go-flutter-desktop/go-flutter/wiki/Plugin-info):\n\n```go\nflutter.AddPlugin(&{{.pluginName}}.{{.structName}}{}),\n```\n"),
 	}
-	file10 := &embedded.EmbeddedFile{
+	file1e := &embedded.EmbeddedFile{
 		Filename:    "plugin/import.go.tmpl.tmpl",
 		FileModTime: time.Unix(1577653312, 0),
 
 		Content: string("package main\n\n// DO NOT EDIT, this file is generated by hover at compile-time for the {{.pluginName}} plugin.\n\nimport (\n\tflutter \"github.com/go-flutter-desktop/go-flutter\"\n\t{{.pluginName}} \"{{.urlVSCRepo}}/go\"\n)\n\nfunc init() {\n\t// Only the init function can be tweaked by plugin maker.\n\toptions = append(options, flutter.AddPlugin(&{{.pluginName}}.{{.structName}}{}))\n}\n"),
 	}
-	file11 := &embedded.EmbeddedFile{
+	file1f := &embedded.EmbeddedFile{
 		Filename:    "plugin/plugin.go.tmpl",
 		FileModTime: time.Unix(1577653312, 0),
 
@@ -167,185 +503,529 @@ func init() {
 		Filename:   "app",
 		DirModTime: time.Unix(1587497089, 0),
 		ChildFiles: []*embedded.EmbeddedFile{
-			file4, // "app/gitignore"
-			file5, // "app/go.mod"
-			file6, // "app/hover.yaml.tmpl"
-			file7, // "app/icon.png"
-			file8, // "app/main.go"
-			file9, // "app/main_desktop.dart"
-			filea, // "app/options.go"
+			file3a, // "app/assetsdecrypt.go"
+			filedr, // "app/buildinfo.go"
+			file4, // "app/crashhandler.go"
+			file5, // "app/firstrun.go"
+			file6, // "app/gitignore"
+			file7, // "app/go.mod"
+			file8, // "app/hover.yaml.tmpl"
+			file9, // "app/icon.png"
+			fileds, // "app/integrity.go"
+			filea, // "app/main.go"
+			fileb, // "app/main_desktop.dart"
+			filec, // "app/options.go"
+			filecb, // "app/preferences.go"
+			filec1, // "app/runprofile.go"
+			filed2, // "app/singleinstance.go"
+			filed4, // "app/singleinstance_dbus_linux.go"
+			filed3, // "app/singleinstance_windows.go"
+			filec5, // "app/wmclass.go"
+
+		},
+	}
+	dircw := &embedded.EmbeddedDir{
+		Filename:   "ide",
+		DirModTime: time.Unix(1792031110, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+
+		},
+	}
+	dircx := &embedded.EmbeddedDir{
+		Filename:   "ide/goland",
+		DirModTime: time.Unix(1792031110, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			filecz, // "ide/goland/hover_debug_go.xml.tmpl"
+			filecy, // "ide/goland/hover_run.xml.tmpl"
 
 		},
 	}
-	dirb := &embedded.EmbeddedDir{
+	dird := &embedded.EmbeddedDir{
 		Filename:   "packaging",
 		DirModTime: time.Unix(1587470036, 0),
 		ChildFiles: []*embedded.EmbeddedFile{
-			filec, // "packaging/README.md"
+			filee, // "packaging/README.md"
 
 		},
 	}
-	dird := &embedded.EmbeddedDir{
+	dirdi := &embedded.EmbeddedDir{
+		Filename:   "packaging/darwin-appstore",
+		DirModTime: time.Unix(1792032547, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			filedj, // "packaging/darwin-appstore/entitlements.plist.tmpl"
+
+		},
+	}
+	dirf := &embedded.EmbeddedDir{
 		Filename:   "packaging/darwin-bundle",
 		DirModTime: time.Unix(1587472853, 0),
 		ChildFiles: []*embedded.EmbeddedFile{
-			filee, // "packaging/darwin-bundle/Info.plist.tmpl"
+			fileg, // "packaging/darwin-bundle/Info.plist.tmpl"
 
 		},
 	}
-	dirf := &embedded.EmbeddedDir{
+	dircu := &embedded.EmbeddedDir{
+		Filename:   "packaging/darwin-cask",
+		DirModTime: time.Unix(1792030915, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			filecv, // "packaging/darwin-cask/cask.rb.tmpl"
+
+		},
+	}
+	dirh := &embedded.EmbeddedDir{
 		Filename:   "packaging/darwin-pkg",
 		DirModTime: time.Unix(1587473491, 0),
 		ChildFiles: []*embedded.EmbeddedFile{
-			fileg, // "packaging/darwin-pkg/Distribution.tmpl"
-			fileh, // "packaging/darwin-pkg/PackageInfo.tmpl"
+			filei, // "packaging/darwin-pkg/Distribution.tmpl"
+			filej, // "packaging/darwin-pkg/PackageInfo.tmpl"
+
+		},
+	}
+	dird5 := &embedded.EmbeddedDir{
+		Filename:   "packaging/freebsd-pkg",
+		DirModTime: time.Unix(1792031900, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			filed6, // "packaging/freebsd-pkg/+MANIFEST.tmpl"
+			filed7, // "packaging/freebsd-pkg/bin.tmpl"
 
 		},
 	}
-	diri := &embedded.EmbeddedDir{
+	dirk := &embedded.EmbeddedDir{
 		Filename:   "packaging/linux",
 		DirModTime: time.Unix(1587470111, 0),
 		ChildFiles: []*embedded.EmbeddedFile{
-			filej, // "packaging/linux/app.desktop.tmpl"
-			filek, // "packaging/linux/bin.tmpl"
+			filel, // "packaging/linux/app.desktop.tmpl"
+			filem, // "packaging/linux/bin.tmpl"
 
 		},
 	}
-	dirl := &embedded.EmbeddedDir{
+	dirdm := &embedded.EmbeddedDir{
+		Filename:   "packaging/linux-apk",
+		DirModTime: time.Unix(1792033179, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			filedn, // "packaging/linux-apk/APKBUILD.tmpl"
+			filedo, // "packaging/linux-apk/post-install.tmpl"
+			filedp, // "packaging/linux-apk/post-upgrade.tmpl"
+			filedq, // "packaging/linux-apk/pre-deinstall.tmpl"
+
+		},
+	}
+	dirn := &embedded.EmbeddedDir{
 		Filename:   "packaging/linux-appimage",
 		DirModTime: time.Unix(1587423157, 0),
 		ChildFiles: []*embedded.EmbeddedFile{
-			filem, // "packaging/linux-appimage/AppRun.tmpl"
+			fileo, // "packaging/linux-appimage/AppRun.tmpl"
 
 		},
 	}
-	dirn := &embedded.EmbeddedDir{
+	dirc9 := &embedded.EmbeddedDir{
+		Filename:   "packaging/linux-aur",
+		DirModTime: time.Unix(1792029547, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			fileca, // "packaging/linux-aur/PKGBUILD.tmpl"
+
+		},
+	}
+	dirp := &embedded.EmbeddedDir{
 		Filename:   "packaging/linux-deb",
 		DirModTime: time.Unix(1587423157, 0),
 		ChildFiles: []*embedded.EmbeddedFile{
-			fileo, // "packaging/linux-deb/control.tmpl"
+			fileq, // "packaging/linux-deb/control.tmpl"
+			filecc, // "packaging/linux-deb/postinst.tmpl"
+			filer, // "packaging/linux-deb/prerm.tmpl"
 
 		},
 	}
-	dirp := &embedded.EmbeddedDir{
+	dirdb := &embedded.EmbeddedDir{
+		Filename:   "packaging/linux-deb-src",
+		DirModTime: time.Unix(1792032312, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			filedc, // "packaging/linux-deb-src/changelog.tmpl"
+			filedd, // "packaging/linux-deb-src/compat.tmpl"
+			filede, // "packaging/linux-deb-src/control.tmpl"
+			filedf, // "packaging/linux-deb-src/format.tmpl"
+			filedg, // "packaging/linux-deb-src/install.tmpl"
+			filedh, // "packaging/linux-deb-src/rules.tmpl"
+
+		},
+	}
+	dirc2 := &embedded.EmbeddedDir{
+		Filename:   "packaging/linux-flatpak",
+		DirModTime: time.Unix(1792029173, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			filec3, // "packaging/linux-flatpak/bin.tmpl"
+			filec4, // "packaging/linux-flatpak/manifest.yml.tmpl"
+
+		},
+	}
+	dirs := &embedded.EmbeddedDir{
+		Filename:   "packaging/linux-kiosk",
+		DirModTime: time.Unix(1792003605, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			filet, // "packaging/linux-kiosk/control.tmpl"
+			fileu, // "packaging/linux-kiosk/kiosk.service.tmpl"
+			filev, // "packaging/linux-kiosk/pam.tmpl"
+			filew, // "packaging/linux-kiosk/postinst.tmpl"
+			filex, // "packaging/linux-kiosk/prerm.tmpl"
+
+		},
+	}
+	dird8 := &embedded.EmbeddedDir{
+		Filename:   "packaging/linux-nix",
+		DirModTime: time.Unix(1792031986, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			filed9, // "packaging/linux-nix/default.nix.tmpl"
+			fileda, // "packaging/linux-nix/flake.nix.tmpl"
+
+		},
+	}
+	diry := &embedded.EmbeddedDir{
+		Filename:   "packaging/linux-overlay",
+		DirModTime: time.Unix(1792003671, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			filez,  // "packaging/linux-overlay/sysusers.conf.tmpl"
+			file10, // "packaging/linux-overlay/tmpfiles.conf.tmpl"
+
+		},
+	}
+	dirc6 := &embedded.EmbeddedDir{
+		Filename:   "packaging/linux-pacman",
+		DirModTime: time.Unix(1792029369, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			filec7, // "packaging/linux-pacman/PKGBUILD.tmpl"
+			filec8, // "packaging/linux-pacman/app.install.tmpl"
+
+		},
+	}
+	dir11 := &embedded.EmbeddedDir{
 		Filename:   "packaging/linux-pkg",
 		DirModTime: time.Unix(1587471688, 0),
 		ChildFiles: []*embedded.EmbeddedFile{
-			fileq, // "packaging/linux-pkg/PKGBUILD.tmpl"
+			file12, // "packaging/linux-pkg/PKGBUILD.tmpl"
 
 		},
 	}
-	dirr := &embedded.EmbeddedDir{
+	dir13 := &embedded.EmbeddedDir{
 		Filename:   "packaging/linux-rpm",
 		DirModTime: time.Unix(1587471688, 0),
 		ChildFiles: []*embedded.EmbeddedFile{
-			files, // "packaging/linux-rpm/app.spec.tmpl"
+			file14, // "packaging/linux-rpm/app.spec.tmpl"
+
+		},
+	}
+	dirdk := &embedded.EmbeddedDir{
+		Filename:   "packaging/linux-run",
+		DirModTime: time.Unix(1792032915, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			filedl, // "packaging/linux-run/install.sh.tmpl"
 
 		},
 	}
-	dirt := &embedded.EmbeddedDir{
+	dircf := &embedded.EmbeddedDir{
+		Filename:   "packaging/linux-runimage",
+		DirModTime: time.Unix(1792029897, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			filecg, // "packaging/linux-runimage/loader.sh.tmpl"
+
+		},
+	}
+	dir15 := &embedded.EmbeddedDir{
 		Filename:   "packaging/linux-snap",
 		DirModTime: time.Unix(1587423157, 0),
 		ChildFiles: []*embedded.EmbeddedFile{
-			fileu, // "packaging/linux-snap/snapcraft.yaml.tmpl"
+			file16, // "packaging/linux-snap/snapcraft.yaml.tmpl"
+
+		},
+	}
+	dird0 := &embedded.EmbeddedDir{
+		Filename:   "packaging/linux-tar",
+		DirModTime: time.Unix(1792031177, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			filed1, // "packaging/linux-tar/launcher.sh.tmpl"
+
+		},
+	}
+	dircl := &embedded.EmbeddedDir{
+		Filename:   "packaging/windows-choco",
+		DirModTime: time.Unix(1792030335, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			filecn, // "packaging/windows-choco/chocolateyInstall.ps1.tmpl"
+			filecm, // "packaging/windows-choco/package.nuspec.tmpl"
+
+		},
+	}
+	dirch := &embedded.EmbeddedDir{
+		Filename:   "packaging/windows-inno",
+		DirModTime: time.Unix(1792029997, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			fileci, // "packaging/windows-inno/installer.iss.tmpl"
 
 		},
 	}
-	dirv := &embedded.EmbeddedDir{
+	dir17 := &embedded.EmbeddedDir{
 		Filename:   "packaging/windows-msi",
 		DirModTime: time.Unix(1587428338, 0),
 		ChildFiles: []*embedded.EmbeddedFile{
-			filew, // "packaging/windows-msi/app.wxs.tmpl"
+			file18, // "packaging/windows-msi/app.wxs.tmpl"
+
+		},
+	}
+	dircj := &embedded.EmbeddedDir{
+		Filename:   "packaging/windows-msix",
+		DirModTime: time.Unix(1792030177, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			fileck, // "packaging/windows-msix/AppxManifest.xml.tmpl"
+
+		},
+	}
+	dircd := &embedded.EmbeddedDir{
+		Filename:   "packaging/windows-nsis",
+		DirModTime: time.Unix(1792029795, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			filece, // "packaging/windows-nsis/installer.nsi.tmpl"
 
 		},
 	}
-	dirx := &embedded.EmbeddedDir{
+	dir19 := &embedded.EmbeddedDir{
+		Filename:   "packaging/windows-portable",
+		DirModTime: time.Unix(1792003706, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			file1a, // "packaging/windows-portable/launcher.cmd.tmpl"
+
+		},
+	}
+	dircs := &embedded.EmbeddedDir{
+		Filename:   "packaging/windows-scoop",
+		DirModTime: time.Unix(1792030780, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			filect, // "packaging/windows-scoop/manifest.json.tmpl"
+
+		},
+	}
+	dirco := &embedded.EmbeddedDir{
+		Filename:   "packaging/windows-winget",
+		DirModTime: time.Unix(1792030677, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			filecq, // "packaging/windows-winget/installer.yaml.tmpl"
+			filecr, // "packaging/windows-winget/locale.yaml.tmpl"
+			filecp, // "packaging/windows-winget/version.yaml.tmpl"
+
+		},
+	}
+	dir1b := &embedded.EmbeddedDir{
 		Filename:   "plugin",
 		DirModTime: time.Unix(1587299806, 0),
 		ChildFiles: []*embedded.EmbeddedFile{
-			filey,  // "plugin/README.md.dlib.tmpl"
-			filez,  // "plugin/README.md.tmpl"
-			file10, // "plugin/import.go.tmpl.tmpl"
-			file11, // "plugin/plugin.go.tmpl"
+			file1c, // "plugin/README.md.dlib.tmpl"
+			file1d, // "plugin/README.md.tmpl"
+			file1e, // "plugin/import.go.tmpl.tmpl"
+			file1f, // "plugin/plugin.go.tmpl"
 
 		},
 	}
 
 	// link ChildDirs
 	dir1.ChildDirs = []*embedded.EmbeddedDir{
-		dir3, // "app"
-		dirb, // "packaging"
-		dirx, // "plugin"
+		dir3,  // "app"
+		dircw, // "ide"
+		dird,  // "packaging"
+		dir1b, // "plugin"
 
 	}
 	dir3.ChildDirs = []*embedded.EmbeddedDir{}
-	dirb.ChildDirs = []*embedded.EmbeddedDir{
-		dird, // "packaging/darwin-bundle"
-		dirf, // "packaging/darwin-pkg"
-		diri, // "packaging/linux"
-		dirl, // "packaging/linux-appimage"
-		dirn, // "packaging/linux-deb"
-		dirp, // "packaging/linux-pkg"
-		dirr, // "packaging/linux-rpm"
-		dirt, // "packaging/linux-snap"
-		dirv, // "packaging/windows-msi"
-
-	}
-	dird.ChildDirs = []*embedded.EmbeddedDir{}
+	dird.ChildDirs = []*embedded.EmbeddedDir{
+		dirdi, // "packaging/darwin-appstore"
+		dirf,  // "packaging/darwin-bundle"
+		dircu, // "packaging/darwin-cask"
+		dirh,  // "packaging/darwin-pkg"
+		dird5, // "packaging/freebsd-pkg"
+		dirk,  // "packaging/linux"
+		dirdm, // "packaging/linux-apk"
+		dirn,  // "packaging/linux-appimage"
+		dirc9, // "packaging/linux-aur"
+		dirp,  // "packaging/linux-deb"
+		dirdb, // "packaging/linux-deb-src"
+		dirc2, // "packaging/linux-flatpak"
+		dirs,  // "packaging/linux-kiosk"
+		dird8, // "packaging/linux-nix"
+		diry,  // "packaging/linux-overlay"
+		dirc6, // "packaging/linux-pacman"
+		dir11, // "packaging/linux-pkg"
+		dir13, // "packaging/linux-rpm"
+		dirdk, // "packaging/linux-run"
+		dircf, // "packaging/linux-runimage"
+		dir15, // "packaging/linux-snap"
+		dird0, // "packaging/linux-tar"
+		dircl, // "packaging/windows-choco"
+		dirch, // "packaging/windows-inno"
+		dir17, // "packaging/windows-msi"
+		dircj, // "packaging/windows-msix"
+		dircd, // "packaging/windows-nsis"
+		dir19, // "packaging/windows-portable"
+		dircs, // "packaging/windows-scoop"
+		dirco, // "packaging/windows-winget"
+
+	}
 	dirf.ChildDirs = []*embedded.EmbeddedDir{}
-	diri.ChildDirs = []*embedded.EmbeddedDir{}
-	dirl.ChildDirs = []*embedded.EmbeddedDir{}
+	dirh.ChildDirs = []*embedded.EmbeddedDir{}
+	dirk.ChildDirs = []*embedded.EmbeddedDir{}
 	dirn.ChildDirs = []*embedded.EmbeddedDir{}
 	dirp.ChildDirs = []*embedded.EmbeddedDir{}
-	dirr.ChildDirs = []*embedded.EmbeddedDir{}
-	dirt.ChildDirs = []*embedded.EmbeddedDir{}
-	dirv.ChildDirs = []*embedded.EmbeddedDir{}
-	dirx.ChildDirs = []*embedded.EmbeddedDir{}
+	dirs.ChildDirs = []*embedded.EmbeddedDir{}
+	diry.ChildDirs = []*embedded.EmbeddedDir{}
+	dir11.ChildDirs = []*embedded.EmbeddedDir{}
+	dir13.ChildDirs = []*embedded.EmbeddedDir{}
+	dir15.ChildDirs = []*embedded.EmbeddedDir{}
+	dir17.ChildDirs = []*embedded.EmbeddedDir{}
+	dir19.ChildDirs = []*embedded.EmbeddedDir{}
+	dir1b.ChildDirs = []*embedded.EmbeddedDir{}
+	dirc2.ChildDirs = []*embedded.EmbeddedDir{}
+	dirc6.ChildDirs = []*embedded.EmbeddedDir{}
+	dirc9.ChildDirs = []*embedded.EmbeddedDir{}
+	dircd.ChildDirs = []*embedded.EmbeddedDir{}
+	dircf.ChildDirs = []*embedded.EmbeddedDir{}
+	dirch.ChildDirs = []*embedded.EmbeddedDir{}
+	dircj.ChildDirs = []*embedded.EmbeddedDir{}
+	dircl.ChildDirs = []*embedded.EmbeddedDir{}
+	dirco.ChildDirs = []*embedded.EmbeddedDir{}
+	dircs.ChildDirs = []*embedded.EmbeddedDir{}
+	dircu.ChildDirs = []*embedded.EmbeddedDir{}
+	dircw.ChildDirs = []*embedded.EmbeddedDir{
+		dircx, // "ide/goland"
+
+	}
+	dircx.ChildDirs = []*embedded.EmbeddedDir{}
+	dird0.ChildDirs = []*embedded.EmbeddedDir{}
+	dird5.ChildDirs = []*embedded.EmbeddedDir{}
+	dird8.ChildDirs = []*embedded.EmbeddedDir{}
+	dirdb.ChildDirs = []*embedded.EmbeddedDir{}
+	dirdi.ChildDirs = []*embedded.EmbeddedDir{}
+	dirdk.ChildDirs = []*embedded.EmbeddedDir{}
+	dirdm.ChildDirs = []*embedded.EmbeddedDir{}
 
 	// register embeddedBox
 	embedded.RegisterEmbeddedBox(`../../assets`, &embedded.EmbeddedBox{
 		Name: `../../assets`,
 		Time: time.Unix(1587423146, 0),
 		Dirs: map[string]*embedded.EmbeddedDir{
-			"":                         dir1,
-			"app":                      dir3,
-			"packaging":                dirb,
-			"packaging/darwin-bundle":  dird,
-			"packaging/darwin-pkg":     dirf,
-			"packaging/linux":          diri,
-			"packaging/linux-appimage": dirl,
-			"packaging/linux-deb":      dirn,
-			"packaging/linux-pkg":      dirp,
-			"packaging/linux-rpm":      dirr,
-			"packaging/linux-snap":     dirt,
-			"packaging/windows-msi":    dirv,
-			"plugin":                   dirx,
+			"":                           dir1,
+			"app":                        dir3,
+			"ide":                        dircw,
+			"ide/goland":                 dircx,
+			"packaging":                  dird,
+			"packaging/darwin-appstore":  dirdi,
+			"packaging/darwin-bundle":    dirf,
+			"packaging/darwin-cask":      dircu,
+			"packaging/darwin-pkg":       dirh,
+			"packaging/freebsd-pkg":      dird5,
+			"packaging/linux":            dirk,
+			"packaging/linux-apk":        dirdm,
+			"packaging/linux-appimage":   dirn,
+			"packaging/linux-aur":        dirc9,
+			"packaging/linux-deb":        dirp,
+			"packaging/linux-deb-src":    dirdb,
+			"packaging/linux-flatpak":    dirc2,
+			"packaging/linux-kiosk":      dirs,
+			"packaging/linux-nix":        dird8,
+			"packaging/linux-overlay":    diry,
+			"packaging/linux-pacman":     dirc6,
+			"packaging/linux-pkg":        dir11,
+			"packaging/linux-rpm":        dir13,
+			"packaging/linux-run":        dirdk,
+			"packaging/linux-runimage":   dircf,
+			"packaging/linux-snap":       dir15,
+			"packaging/linux-tar":        dird0,
+			"packaging/windows-choco":    dircl,
+			"packaging/windows-inno":     dirch,
+			"packaging/windows-msi":      dir17,
+			"packaging/windows-msix":     dircj,
+			"packaging/windows-nsis":     dircd,
+			"packaging/windows-portable": dir19,
+			"packaging/windows-scoop":    dircs,
+			"packaging/windows-winget":   dirco,
+			"plugin":                     dir1b,
 		},
 		Files: map[string]*embedded.EmbeddedFile{
-			"README.md":                                file2,
-			"app/gitignore":                            file4,
-			"app/go.mod":                               file5,
-			"app/hover.yaml.tmpl":                      file6,
-			"app/icon.png":                             file7,
-			"app/main.go":                              file8,
-			"app/main_desktop.dart":                    file9,
-			"app/options.go":                           filea,
-			"packaging/README.md":                      filec,
-			"packaging/darwin-bundle/Info.plist.tmpl":  filee,
-			"packaging/darwin-pkg/Distribution.tmpl":   fileg,
-			"packaging/darwin-pkg/PackageInfo.tmpl":    fileh,
-			"packaging/linux/app.desktop.tmpl":         filej,
-			"packaging/linux/bin.tmpl":                 filek,
-			"packaging/linux-appimage/AppRun.tmpl":     filem,
-			"packaging/linux-deb/control.tmpl":         fileo,
-			"packaging/linux-pkg/PKGBUILD.tmpl":        fileq,
-			"packaging/linux-rpm/app.spec.tmpl":        files,
-			"packaging/linux-snap/snapcraft.yaml.tmpl": fileu,
-			"packaging/windows-msi/app.wxs.tmpl":       filew,
-			"plugin/README.md.dlib.tmpl":               filey,
-			"plugin/README.md.tmpl":                    filez,
-			"plugin/import.go.tmpl.tmpl":               file10,
-			"plugin/plugin.go.tmpl":                    file11,
+			"README.md":                                    file2,
+			"app/assetsdecrypt.go":                         file3a,
+			"app/buildinfo.go":                             filedr,
+			"app/crashhandler.go":                          file4,
+			"app/firstrun.go":                              file5,
+			"app/gitignore":                                file6,
+			"app/go.mod":                                   file7,
+			"app/hover.yaml.tmpl":                          file8,
+			"app/icon.png":                                 file9,
+			"app/integrity.go":                             fileds,
+			"app/main.go":                                  filea,
+			"app/main_desktop.dart":                        fileb,
+			"app/options.go":                               filec,
+			"app/preferences.go":                           filecb,
+			"app/runprofile.go":                            filec1,
+			"app/singleinstance.go":                        filed2,
+			"app/singleinstance_dbus_linux.go":             filed4,
+			"app/singleinstance_windows.go":                filed3,
+			"app/wmclass.go":                               filec5,
+			"ide/goland/hover_debug_go.xml.tmpl":           filecz,
+			"ide/goland/hover_run.xml.tmpl":                filecy,
+			"packaging/README.md":                          filee,
+			"packaging/darwin-appstore/entitlements.plist.tmpl": filedj,
+			"packaging/darwin-bundle/Info.plist.tmpl":      fileg,
+			"packaging/darwin-cask/cask.rb.tmpl":           filecv,
+			"packaging/darwin-pkg/Distribution.tmpl":       filei,
+			"packaging/darwin-pkg/PackageInfo.tmpl":        filej,
+			"packaging/freebsd-pkg/+MANIFEST.tmpl":         filed6,
+			"packaging/freebsd-pkg/bin.tmpl":               filed7,
+			"packaging/linux/app.desktop.tmpl":             filel,
+			"packaging/linux/bin.tmpl":                     filem,
+			"packaging/linux-apk/APKBUILD.tmpl":            filedn,
+			"packaging/linux-apk/post-install.tmpl":        filedo,
+			"packaging/linux-apk/post-upgrade.tmpl":        filedp,
+			"packaging/linux-apk/pre-deinstall.tmpl":       filedq,
+			"packaging/linux-appimage/AppRun.tmpl":         fileo,
+			"packaging/linux-aur/PKGBUILD.tmpl":            fileca,
+			"packaging/linux-deb/control.tmpl":             fileq,
+			"packaging/linux-deb/postinst.tmpl":            filecc,
+			"packaging/linux-deb/prerm.tmpl":               filer,
+			"packaging/linux-deb-src/changelog.tmpl":       filedc,
+			"packaging/linux-deb-src/compat.tmpl":          filedd,
+			"packaging/linux-deb-src/control.tmpl":         filede,
+			"packaging/linux-deb-src/format.tmpl":          filedf,
+			"packaging/linux-deb-src/install.tmpl":         filedg,
+			"packaging/linux-deb-src/rules.tmpl":           filedh,
+			"packaging/linux-flatpak/bin.tmpl":             filec3,
+			"packaging/linux-flatpak/manifest.yml.tmpl":    filec4,
+			"packaging/linux-kiosk/control.tmpl":           filet,
+			"packaging/linux-kiosk/kiosk.service.tmpl":     fileu,
+			"packaging/linux-kiosk/pam.tmpl":               filev,
+			"packaging/linux-kiosk/postinst.tmpl":          filew,
+			"packaging/linux-kiosk/prerm.tmpl":             filex,
+			"packaging/linux-nix/default.nix.tmpl":         filed9,
+			"packaging/linux-nix/flake.nix.tmpl":           fileda,
+			"packaging/linux-overlay/sysusers.conf.tmpl":   filez,
+			"packaging/linux-overlay/tmpfiles.conf.tmpl":   file10,
+			"packaging/linux-pacman/PKGBUILD.tmpl":         filec7,
+			"packaging/linux-pacman/app.install.tmpl":      filec8,
+			"packaging/linux-pkg/PKGBUILD.tmpl":            file12,
+			"packaging/linux-rpm/app.spec.tmpl":            file14,
+			"packaging/linux-run/install.sh.tmpl":          filedl,
+			"packaging/linux-runimage/loader.sh.tmpl":      filecg,
+			"packaging/linux-snap/snapcraft.yaml.tmpl":     file16,
+			"packaging/linux-tar/launcher.sh.tmpl":         filed1,
+			"packaging/windows-choco/chocolateyInstall.ps1.tmpl": filecn,
+			"packaging/windows-choco/package.nuspec.tmpl":  filecm,
+			"packaging/windows-inno/installer.iss.tmpl":    fileci,
+			"packaging/windows-msi/app.wxs.tmpl":           file18,
+			"packaging/windows-msix/AppxManifest.xml.tmpl": fileck,
+			"packaging/windows-nsis/installer.nsi.tmpl":    filece,
+			"packaging/windows-portable/launcher.cmd.tmpl": file1a,
+			"packaging/windows-scoop/manifest.json.tmpl":   filect,
+			"packaging/windows-winget/installer.yaml.tmpl": filecq,
+			"packaging/windows-winget/locale.yaml.tmpl":    filecr,
+			"packaging/windows-winget/version.yaml.tmpl":   filecp,
+			"plugin/README.md.dlib.tmpl":                   file1c,
+			"plugin/README.md.tmpl":                        file1d,
+			"plugin/import.go.tmpl.tmpl":                   file1e,
+			"plugin/plugin.go.tmpl":                        file1f,
 		},
 	})
 }
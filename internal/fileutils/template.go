@@ -0,0 +1,218 @@
+package fileutils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+)
+
+// TemplateFuncs returns the functions available in the templates executed by
+// hover, such as the packaging templates and packaging scripts.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"shellquote":   ShellQuote,
+		"xmlescape":    XMLEscape,
+		"nsisquote":    nsisQuote,
+		"innoquote":    innoQuote,
+		"desktopquote": desktopQuote,
+		"nixquote":     nixQuote,
+		"upper":        strings.ToUpper,
+		"lower":        strings.ToLower,
+		"trim":         strings.TrimSpace,
+		"fields":       strings.Fields,
+		"replace":      replace,
+		"date":         date,
+		"env":          os.Getenv,
+		"sha256file":   SHA256File,
+		"quote":        strconv.Quote,
+		"toJson":       toJSON,
+		"default":      defaultValue,
+	}
+}
+
+// missingKeyDirective matches the comment a template can start with to
+// configure how missing keys are handled, e.g. {{/* missingkey=zero */}}.
+// The values are those of the text/template missingkey option.
+var missingKeyDirective = regexp.MustCompile(`^\{\{-?\s*/\*\s*missingkey=(error|zero|default|invalid)\s*\*/\s*-?\}\}`)
+
+// ParseTemplate parses a template with the hover template functions. The
+// name is used in errors, it should be the path of the template file.
+// Missing keys are an error, unless the template declares otherwise using
+// a {{/* missingkey=zero */}} comment at its start.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	missingKey := "error"
+	if match := missingKeyDirective.FindStringSubmatch(text); match != nil {
+		missingKey = match[1]
+	}
+	return template.New(name).Option("missingkey=" + missingKey).Funcs(TemplateFuncs()).Parse(text)
+}
+
+// defaultValue returns value, or def when value is missing or empty. The
+// argument order allows pipelines: {{.optionalValue | default "fallback"}}
+func defaultValue(def interface{}, value ...interface{}) interface{} {
+	if len(value) == 0 || value[0] == nil {
+		return def
+	}
+	v := reflect.ValueOf(value[0])
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		if v.Len() == 0 {
+			return def
+		}
+	}
+	return value[0]
+}
+
+// replace replaces all occurrences of old by new in src. The argument order
+// allows pipelines: {{.packageName | replace "-" "_"}}
+func replace(old, new, src string) string {
+	return strings.ReplaceAll(src, old, new)
+}
+
+// date formats the current time using a go time layout, e.g.
+// {{date "2006-01-02"}}. When SOURCE_DATE_EPOCH is set, that timestamp is
+// used instead of the current time to keep builds reproducible.
+func date(layout string) (string, error) {
+	now, err := build.BuildTime(time.Now())
+	if err != nil {
+		return "", err
+	}
+	return now.Format(layout), nil
+}
+
+// SHA256File returns the hex encoded sha256 checksum of a file. Relative
+// paths are resolved from the root of the flutter project.
+func SHA256File(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to open %s", path)
+	}
+	defer file.Close()
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read %s", path)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// toJSON encodes a value as JSON. As JSON is valid YAML, it can also be used
+// to safely embed values in YAML files.
+func toJSON(value interface{}) (string, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode value to json")
+	}
+	return string(b), nil
+}
+
+// ShellQuote concatenates the parts and quotes the result so it's passed as
+// a single word to a POSIX shell, whatever characters it contains.
+//
+// Usage: {{shellquote .applicationName " " .version ".app"}}
+func ShellQuote(parts ...string) string {
+	return "'" + strings.ReplaceAll(strings.Join(parts, ""), "'", `'"'"'`) + "'"
+}
+
+// nsisQuote concatenates the parts and puts the result in double quotes for
+// a NSIS script, escaping the quotes and the dollar signs that aren't
+// variables. The parts are expected to be values, except for a leading NSIS
+// variable such as $INSTDIR.
+//
+// Usage: {{nsisquote "$INSTDIR\\" .executableName ".exe"}}
+func nsisQuote(parts ...string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, part := range parts {
+		if i == 0 && nsisVariable.MatchString(part) {
+			b.WriteString(part)
+			continue
+		}
+		b.WriteString(strings.NewReplacer("$", "$$", `"`, `$\"`).Replace(part))
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// nsisVariable matches a part of nsisquote starting with a NSIS variable.
+var nsisVariable = regexp.MustCompile(`^\$[A-Z][A-Z0-9_]*\\?$`)
+
+// innoQuote concatenates the parts and puts the result in double quotes for
+// the parameters of an Inno Setup script, doubling the quotes and the braces
+// that aren't constants. The parts are expected to be values, except for a
+// leading Inno Setup constant such as {app}.
+//
+// Usage: {{innoquote "{app}\\" .executableName ".exe"}}
+func innoQuote(parts ...string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, part := range parts {
+		if i == 0 && innoConstant.MatchString(part) {
+			b.WriteString(part)
+			continue
+		}
+		b.WriteString(strings.NewReplacer("{", "{{", `"`, `""`).Replace(part))
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// innoConstant matches a part of innoquote starting with an Inno Setup
+// constant.
+var innoConstant = regexp.MustCompile(`^\{[a-z]+\}\\?$`)
+
+// XMLEscape escapes a value so it can be used in XML text and attributes.
+func XMLEscape(value string) string {
+	var b bytes.Buffer
+	// xml.EscapeText only fails when the writer fails, a bytes.Buffer doesn't.
+	_ = xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
+
+// desktopQuote quotes a value for the Exec key of a .desktop file, according
+// to the desktop entry specification: arguments containing reserved
+// characters are put in double quotes, in which `"`, "`", `$` and `\` are
+// escaped with a backslash. Because the string escaping of the file applies
+// first, the escaping backslash is itself written as `\\`.
+func desktopQuote(value string) string {
+	if !strings.ContainsAny(value, " \t\n\"'\\><~|&;$*?#()`") {
+		return value
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"', '`', '$':
+			b.WriteString(`\\`)
+			b.WriteRune(r)
+		case '\\':
+			b.WriteString(`\\\\`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// nixQuote concatenates the parts and puts the result in a nix string,
+// escaping the backslashes, the quotes and the antiquotations.
+//
+// Usage: {{nixquote .description}}
+func nixQuote(parts ...string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "${", `\${`, "\n", `\n`, "\r", `\r`, "\t", `\t`).Replace(strings.Join(parts, "")) + `"`
+}
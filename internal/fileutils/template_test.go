@@ -0,0 +1,105 @@
+package fileutils
+
+import "testing"
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		parts []string
+		want  string
+	}{
+		{[]string{"myapp"}, `'myapp'`},
+		{[]string{"My App", " ", "1.0.0", ".app"}, `'My App 1.0.0.app'`},
+		{[]string{"it's"}, `'it'"'"'s'`},
+		{[]string{"$HOME `id` \\"}, `'$HOME ` + "`id`" + ` \'`},
+		{nil, `''`},
+	}
+	for _, test := range tests {
+		if got := ShellQuote(test.parts...); got != test.want {
+			t.Errorf("ShellQuote(%q) = %s, want %s", test.parts, got, test.want)
+		}
+	}
+}
+
+func TestNsisQuote(t *testing.T) {
+	tests := []struct {
+		parts []string
+		want  string
+	}{
+		{[]string{"$INSTDIR\\", "myapp", ".exe"}, `"$INSTDIR\myapp.exe"`},
+		{[]string{"My $App"}, `"My $$App"`},
+		{[]string{`say "hi"`}, `"say $\"hi$\""`},
+		{[]string{"app", "$INSTDIR"}, `"app$$INSTDIR"`},
+	}
+	for _, test := range tests {
+		if got := nsisQuote(test.parts...); got != test.want {
+			t.Errorf("nsisQuote(%q) = %s, want %s", test.parts, got, test.want)
+		}
+	}
+}
+
+func TestInnoQuote(t *testing.T) {
+	tests := []struct {
+		parts []string
+		want  string
+	}{
+		{[]string{"{app}\\", "myapp", ".exe"}, `"{app}\myapp.exe"`},
+		{[]string{"{not a constant}"}, `"{{not a constant}"`},
+		{[]string{`say "hi"`}, `"say ""hi"""`},
+	}
+	for _, test := range tests {
+		if got := innoQuote(test.parts...); got != test.want {
+			t.Errorf("innoQuote(%q) = %s, want %s", test.parts, got, test.want)
+		}
+	}
+}
+
+func TestXMLEscape(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"My App", "My App"},
+		{`<a href="x">&'`, "&lt;a href=&#34;x&#34;&gt;&amp;&#39;"},
+		{"line\nbreak", "line&#xA;break"},
+	}
+	for _, test := range tests {
+		if got := XMLEscape(test.value); got != test.want {
+			t.Errorf("XMLEscape(%q) = %s, want %s", test.value, got, test.want)
+		}
+	}
+}
+
+func TestDesktopQuote(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"/usr/bin/myapp", "/usr/bin/myapp"},
+		{"/opt/My App/myapp", `"/opt/My App/myapp"`},
+		{`/opt/$app`, `"/opt/\\$app"`},
+		{`C:\app`, `"C:\\\\app"`},
+		{"say \"hi\" `now`", "\"say \\\\\"hi\\\\\" \\\\`now\\\\`\""},
+	}
+	for _, test := range tests {
+		if got := desktopQuote(test.value); got != test.want {
+			t.Errorf("desktopQuote(%q) = %s, want %s", test.value, got, test.want)
+		}
+	}
+}
+
+func TestNixQuote(t *testing.T) {
+	tests := []struct {
+		parts []string
+		want  string
+	}{
+		{[]string{"myapp"}, `"myapp"`},
+		{[]string{`a "quoted" \ value`}, `"a \"quoted\" \\ value"`},
+		{[]string{"${pkgs.hello}", "\n"}, `"\${pkgs.hello}\n"`},
+		{[]string{"$HOME"}, `"$HOME"`},
+	}
+	for _, test := range tests {
+		if got := nixQuote(test.parts...); got != test.want {
+			t.Errorf("nixQuote(%q) = %s, want %s", test.parts, got, test.want)
+		}
+	}
+}
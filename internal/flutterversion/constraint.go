@@ -0,0 +1,133 @@
+package flutterversion
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SatisfiesConstraint reports whether a version satisfies a pub version
+// constraint, such as the flutter constraint of the environment section of
+// pubspec.yaml: `any`, `1.2.3`, `^1.2.3` or space separated comparisons like
+// `>=1.17.0 <2.0.0`.
+func SatisfiesConstraint(version, constraint string) (bool, error) {
+	v, err := parseVersion(version)
+	if err != nil {
+		return false, err
+	}
+	for _, part := range strings.Fields(constraint) {
+		if part == "any" {
+			continue
+		}
+		versionPart := strings.TrimLeft(part, "<>=^")
+		operator := strings.TrimSuffix(part, versionPart)
+		bound, err := parseVersion(versionPart)
+		if err != nil {
+			return false, errors.Wrapf(err, "invalid constraint %q", constraint)
+		}
+		var ok bool
+		switch c := compareVersions(v, bound); operator {
+		case "":
+			ok = c == 0
+		case ">=":
+			ok = c >= 0
+		case ">":
+			ok = c > 0
+		case "<=":
+			ok = c <= 0
+		case "<":
+			ok = c < 0
+		case "^":
+			// ^1.2.3 allows the versions up to the next major version, ^0.2.3
+			// the versions up to the next minor version.
+			upper := semver{numbers: [3]int{bound.numbers[0] + 1}}
+			if bound.numbers[0] == 0 {
+				upper = semver{numbers: [3]int{0, bound.numbers[1] + 1}}
+			}
+			ok = c >= 0 && compareVersions(v, upper) < 0
+		default:
+			return false, errors.Errorf("invalid constraint %q", constraint)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+type semver struct {
+	numbers    [3]int
+	prerelease []string
+}
+
+// parseVersion parses a semantic version. The build metadata is ignored.
+func parseVersion(version string) (semver, error) {
+	var v semver
+	version = strings.SplitN(version, "+", 2)[0]
+	parts := strings.SplitN(version, "-", 2)
+	if len(parts) == 2 {
+		v.prerelease = strings.Split(parts[1], ".")
+	}
+	numbers := strings.Split(parts[0], ".")
+	if len(numbers) != 3 {
+		return v, errors.Errorf("invalid version %q", version)
+	}
+	for i, number := range numbers {
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return v, errors.Errorf("invalid version %q", version)
+		}
+		v.numbers[i] = n
+	}
+	return v, nil
+}
+
+// compareVersions returns -1, 0 or 1 when a is lower than, equal to or
+// greater than b, following the semantic versioning precedence rules.
+func compareVersions(a, b semver) int {
+	for i := range a.numbers {
+		if a.numbers[i] != b.numbers[i] {
+			return compareInts(a.numbers[i], b.numbers[i])
+		}
+	}
+	// a pre-release version is lower than the release
+	switch {
+	case len(a.prerelease) == 0 && len(b.prerelease) == 0:
+		return 0
+	case len(a.prerelease) == 0:
+		return 1
+	case len(b.prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(a.prerelease) && i < len(b.prerelease); i++ {
+		if a.prerelease[i] == b.prerelease[i] {
+			continue
+		}
+		x, errX := strconv.Atoi(a.prerelease[i])
+		y, errY := strconv.Atoi(b.prerelease[i])
+		switch {
+		case errX == nil && errY == nil:
+			return compareInts(x, y)
+		case errX == nil:
+			return -1
+		case errY == nil:
+			return 1
+		case a.prerelease[i] < b.prerelease[i]:
+			return -1
+		default:
+			return 1
+		}
+	}
+	return compareInts(len(a.prerelease), len(b.prerelease))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
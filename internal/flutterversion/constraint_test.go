@@ -0,0 +1,58 @@
+package flutterversion
+
+import "testing"
+
+func TestSatisfiesConstraint(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"2.10.5", "any", true},
+		{"2.10.5", "", true},
+		{"2.10.5", "2.10.5", true},
+		{"2.10.4", "2.10.5", false},
+		{"2.10.5", ">=2.0.0 <3.0.0", true},
+		{"3.0.0", ">=2.0.0 <3.0.0", false},
+		{"3.0.0-1.0.pre", ">=2.0.0 <3.0.0", true},
+		{"3.0.0-1.0.pre", ">=3.0.0", false},
+		{"2.0.0", ">2.0.0", false},
+		{"2.0.0", "<=2.0.0", true},
+		{"2.10.5", "^2.2.0", true},
+		{"3.0.0", "^2.2.0", false},
+		{"2.1.0", "^2.2.0", false},
+		{"0.2.9", "^0.2.3", true},
+		{"0.3.0", "^0.2.3", false},
+		{"2.10.5+hotfix.1", "2.10.5", true},
+		{"1.22.0-12.1.pre", ">=1.22.0-10.0.pre", true},
+		{"1.22.0-2.0.pre", ">=1.22.0-10.0.pre", false},
+		{"1.22.0-1.0.alpha", ">=1.22.0-1.0.beta", false},
+	}
+	for _, test := range tests {
+		got, err := SatisfiesConstraint(test.version, test.constraint)
+		if err != nil {
+			t.Errorf("SatisfiesConstraint(%q, %q) error = %v", test.version, test.constraint, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("SatisfiesConstraint(%q, %q) = %v, want %v", test.version, test.constraint, got, test.want)
+		}
+	}
+}
+
+func TestSatisfiesConstraintInvalid(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+	}{
+		{"2.10", ">=2.0.0"},
+		{"2.10.5", ">=2.x.0"},
+		{"2.10.5", "~>2.0.0"},
+		{"2.10.5", "!=2.0.0"},
+	}
+	for _, test := range tests {
+		if _, err := SatisfiesConstraint(test.version, test.constraint); err == nil {
+			t.Errorf("SatisfiesConstraint(%q, %q) didn't fail", test.version, test.constraint)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"os"
 	"os/exec"
+	"sync"
 
 	"github.com/go-flutter-desktop/hover/internal/build"
 	"github.com/go-flutter-desktop/hover/internal/log"
@@ -20,7 +21,40 @@ func FlutterChannel() string {
 	return readFlutterVersion().Channel
 }
 
+// FlutterFrameworkVersion returns the version of the flutter installation
+func FlutterFrameworkVersion() string {
+	return readFlutterVersion().FrameworkVersion
+}
+
+// FlutterFrameworkRevision returns the commit hash of the flutter installation
+func FlutterFrameworkRevision() string {
+	return readFlutterVersion().FrameworkRevision
+}
+
+// FlutterRoot returns the path of the flutter installation
+func FlutterRoot() string {
+	return readFlutterVersion().FlutterRoot
+}
+
+var (
+	flutterVersion     flutterVersionResponse
+	flutterVersionOnce sync.Once
+)
+
+// ReloadFlutterVersion discards the version read from the flutter
+// installation, after it changed.
+func ReloadFlutterVersion() {
+	flutterVersionOnce = sync.Once{}
+}
+
 func readFlutterVersion() flutterVersionResponse {
+	flutterVersionOnce.Do(func() {
+		flutterVersion = runFlutterVersion()
+	})
+	return flutterVersion
+}
+
+func runFlutterVersion() flutterVersionResponse {
 	out, err := exec.Command(build.FlutterBin(), "--version", "--machine").Output()
 	if err != nil {
 		log.Errorf("Failed to run %s: %v", log.Au().Magenta("flutter --version --machine"), err)
@@ -53,6 +87,9 @@ func readFlutterVersion() flutterVersionResponse {
 }
 
 type flutterVersionResponse struct {
-	Channel        string
-	EngineRevision string
+	Channel           string
+	EngineRevision    string
+	FrameworkVersion  string
+	FrameworkRevision string
+	FlutterRoot       string
 }
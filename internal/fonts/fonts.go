@@ -0,0 +1,167 @@
+// Package fonts reads the embedding permissions and the licensing metadata of
+// the TrueType and OpenType fonts bundled with an app.
+package fonts
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+)
+
+// The embedding permissions of the fsType field of the OS/2 table.
+const (
+	RestrictedLicense = 0x0002 // Must not be embedded or distributed without the permission of the legal owner
+	PreviewAndPrint   = 0x0004 // May be embedded in documents, read-only
+	Editable          = 0x0008 // May be embedded in documents, which may be edited
+)
+
+// Font is a font of a font file, a collection (.ttc) has several fonts.
+type Font struct {
+	Name       string
+	FsType     uint16
+	Copyright  string
+	License    string
+	LicenseURL string
+}
+
+// Restriction returns the embedding restriction of the font, empty when it
+// is installable.
+func (f Font) Restriction() string {
+	switch {
+	case f.FsType&RestrictedLicense != 0:
+		return "restricted license embedding"
+	case f.FsType&PreviewAndPrint != 0:
+		return "preview & print embedding"
+	case f.FsType&Editable != 0:
+		return "editable embedding"
+	}
+	return ""
+}
+
+// Read reads the fonts of a .ttf, .otf or .ttc file.
+func Read(path string) ([]Font, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 12 {
+		return nil, errors.New("not a font file")
+	}
+	if string(data[:4]) != "ttcf" {
+		font, err := readFont(data, 0)
+		if err != nil {
+			return nil, err
+		}
+		return []Font{font}, nil
+	}
+	numFonts := int(binary.BigEndian.Uint32(data[8:12]))
+	if len(data) < 12+4*numFonts {
+		return nil, errors.New("truncated font collection")
+	}
+	var fonts []Font
+	for i := 0; i < numFonts; i++ {
+		font, err := readFont(data, int(binary.BigEndian.Uint32(data[12+4*i:])))
+		if err != nil {
+			return nil, err
+		}
+		fonts = append(fonts, font)
+	}
+	return fonts, nil
+}
+
+// readFont reads the OS/2 and name tables of the font at an offset of a font
+// file.
+func readFont(data []byte, offset int) (Font, error) {
+	var font Font
+	if offset+12 > len(data) {
+		return font, errors.New("truncated font")
+	}
+	switch string(data[offset : offset+4]) {
+	case "\x00\x01\x00\x00", "OTTO", "true":
+	default:
+		return font, errors.New("not a TrueType or OpenType font")
+	}
+	numTables := int(binary.BigEndian.Uint16(data[offset+4:]))
+	for i := 0; i < numTables; i++ {
+		record := offset + 12 + 16*i
+		if record+16 > len(data) {
+			return font, errors.New("truncated table directory")
+		}
+		tag := string(data[record : record+4])
+		tableOffset := int(binary.BigEndian.Uint32(data[record+8:]))
+		tableLength := int(binary.BigEndian.Uint32(data[record+12:]))
+		if tableOffset+tableLength > len(data) {
+			return font, errors.Errorf("truncated %s table", tag)
+		}
+		table := data[tableOffset : tableOffset+tableLength]
+		switch tag {
+		case "OS/2":
+			if len(table) < 10 {
+				return font, errors.New("truncated OS/2 table")
+			}
+			font.FsType = binary.BigEndian.Uint16(table[8:])
+		case "name":
+			names := readNames(table)
+			font.Name = names[4]
+			if font.Name == "" {
+				font.Name = names[1]
+			}
+			font.Copyright = names[0]
+			font.License = names[13]
+			font.LicenseURL = names[14]
+		}
+	}
+	return font, nil
+}
+
+// readNames returns the strings of the name table by name ID, in english
+// when the table is localized.
+func readNames(table []byte) map[uint16]string {
+	names := make(map[uint16]string)
+	if len(table) < 6 {
+		return names
+	}
+	count := int(binary.BigEndian.Uint16(table[2:]))
+	stringOffset := int(binary.BigEndian.Uint16(table[4:]))
+	english := make(map[uint16]bool)
+	for i := 0; i < count; i++ {
+		record := 6 + 12*i
+		if record+12 > len(table) {
+			break
+		}
+		platformID := binary.BigEndian.Uint16(table[record:])
+		languageID := binary.BigEndian.Uint16(table[record+4:])
+		nameID := binary.BigEndian.Uint16(table[record+6:])
+		length := int(binary.BigEndian.Uint16(table[record+8:]))
+		start := stringOffset + int(binary.BigEndian.Uint16(table[record+10:]))
+		if start+length > len(table) || english[nameID] {
+			continue
+		}
+		var value string
+		switch platformID {
+		case 0, 3: // unicode and windows, UTF-16BE
+			runes := make([]uint16, length/2)
+			for j := range runes {
+				runes[j] = binary.BigEndian.Uint16(table[start+2*j:])
+			}
+			value = string(utf16.Decode(runes))
+		case 1: // macintosh, roman
+			value = string(table[start : start+length])
+		default:
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		isEnglish := (platformID == 3 && languageID&0xff == 0x09) || (platformID == 1 && languageID == 0)
+		if isEnglish || names[nameID] == "" {
+			names[nameID] = value
+			english[nameID] = isEnglish
+		}
+	}
+	return names
+}
@@ -0,0 +1,155 @@
+package fonts
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+// fontName is a record of the name table of a test font.
+type fontName struct {
+	platformID uint16
+	languageID uint16
+	nameID     uint16
+	value      string
+}
+
+// nameTable returns a name table holding the names.
+func nameTable(names []fontName) []byte {
+	var strings []byte
+	table := make([]byte, 6+12*len(names))
+	binary.BigEndian.PutUint16(table[2:], uint16(len(names)))
+	binary.BigEndian.PutUint16(table[4:], uint16(len(table)))
+	for i, name := range names {
+		var value []byte
+		if name.platformID == 1 {
+			value = []byte(name.value)
+		} else {
+			for _, r := range utf16.Encode([]rune(name.value)) {
+				value = append(value, byte(r>>8), byte(r))
+			}
+		}
+		record := table[6+12*i:]
+		binary.BigEndian.PutUint16(record, name.platformID)
+		binary.BigEndian.PutUint16(record[4:], name.languageID)
+		binary.BigEndian.PutUint16(record[6:], name.nameID)
+		binary.BigEndian.PutUint16(record[8:], uint16(len(value)))
+		binary.BigEndian.PutUint16(record[10:], uint16(len(strings)))
+		strings = append(strings, value...)
+	}
+	return append(table, strings...)
+}
+
+// sfnt returns a font with an OS/2 table of the fsType and a name table, at
+// an offset of the file.
+func sfnt(offset int, fsType uint16, names []fontName) []byte {
+	os2 := make([]byte, 10)
+	binary.BigEndian.PutUint16(os2[8:], fsType)
+	name := nameTable(names)
+	font := make([]byte, 12+2*16)
+	copy(font, "\x00\x01\x00\x00")
+	binary.BigEndian.PutUint16(font[4:], 2)
+	tables := []struct {
+		tag  string
+		data []byte
+	}{{"OS/2", os2}, {"name", name}}
+	for i, table := range tables {
+		record := font[12+16*i:]
+		copy(record, table.tag)
+		binary.BigEndian.PutUint32(record[8:], uint32(offset+len(font)))
+		binary.BigEndian.PutUint32(record[12:], uint32(len(table.data)))
+		font = append(font, table.data...)
+	}
+	return font
+}
+
+func TestRead(t *testing.T) {
+	collection := func() []byte {
+		header := make([]byte, 20)
+		copy(header, "ttcf")
+		binary.BigEndian.PutUint32(header[8:], 2)
+		first := sfnt(len(header), 0, []fontName{{3, 0x409, 4, "First"}})
+		binary.BigEndian.PutUint32(header[12:], uint32(len(header)))
+		binary.BigEndian.PutUint32(header[16:], uint32(len(header)+len(first)))
+		second := sfnt(len(header)+len(first), Editable, []fontName{{3, 0x409, 4, "Second"}})
+		return append(append(header, first...), second...)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []Font
+		wantErr bool
+	}{
+		{
+			name: "names and permissions",
+			data: sfnt(0, RestrictedLicense, []fontName{
+				{3, 0x409, 0, "Copyright Foundry"},
+				{3, 0x409, 4, "Font Regular"},
+				{3, 0x409, 13, "Commercial license"},
+				{3, 0x409, 14, "https://example.com/license"},
+			}),
+			want: []Font{{Name: "Font Regular", FsType: RestrictedLicense, Copyright: "Copyright Foundry", License: "Commercial license", LicenseURL: "https://example.com/license"}},
+		},
+		{
+			name: "english name preferred, family name fallback",
+			data: sfnt(0, 0, []fontName{
+				{3, 0x40c, 0, "Droits réservés"},
+				{1, 0, 0, "All rights reserved"},
+				{3, 0x409, 1, "Family"},
+			}),
+			want: []Font{{Name: "Family", Copyright: "All rights reserved"}},
+		},
+		{
+			name: "collection",
+			data: collection(),
+			want: []Font{{Name: "First"}, {Name: "Second", FsType: Editable}},
+		},
+		{name: "too short", data: []byte("OTTO"), wantErr: true},
+		{name: "not a font", data: []byte("<html></html>"), wantErr: true},
+		{name: "truncated table", data: sfnt(0, 0, nil)[:40], wantErr: true},
+	}
+	dir, err := ioutil.TempDir("", "hover-fonts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for i, test := range tests {
+		path := filepath.Join(dir, fmt.Sprintf("font%d.ttf", i))
+		err := ioutil.WriteFile(path, test.data, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Read(path)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: Read() error = %v, want error %t", test.name, err, test.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Read() = %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestRestriction(t *testing.T) {
+	tests := []struct {
+		fsType uint16
+		want   string
+	}{
+		{0, ""},
+		{RestrictedLicense, "restricted license embedding"},
+		{PreviewAndPrint, "preview & print embedding"},
+		{Editable, "editable embedding"},
+		{RestrictedLicense | Editable, "restricted license embedding"},
+	}
+	for _, test := range tests {
+		if got := (Font{FsType: test.fsType}).Restriction(); got != test.want {
+			t.Errorf("Restriction() of fsType %#x = %q, want %q", test.fsType, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Package hostenv detects the environments hover runs in which need
+// adapting to: WSL2 on windows hosts and the dev containers.
+package hostenv
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// wslgPath is the directory WSLg shares its X11 and Wayland sockets in.
+const wslgPath = "/mnt/wslg"
+
+var (
+	wsl     bool
+	wslOnce sync.Once
+)
+
+// IsWSL returns whether hover runs in a WSL distribution.
+func IsWSL() bool {
+	wslOnce.Do(func() {
+		if runtime.GOOS != "linux" {
+			return
+		}
+		if os.Getenv("WSL_DISTRO_NAME") != "" {
+			wsl = true
+			return
+		}
+		release, err := ioutil.ReadFile("/proc/sys/kernel/osrelease")
+		wsl = err == nil && strings.Contains(strings.ToLower(string(release)), "microsoft")
+	})
+	return wsl
+}
+
+// HasWSLg returns whether WSLg, which displays the linux GUI apps of WSL on
+// the windows desktop, is available.
+func HasWSLg() bool {
+	if !IsWSL() {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(wslgPath, ".X11-unix"))
+	return err == nil
+}
+
+// WSLgDisplayEnv returns the environment variables connecting an app to the
+// X11 and Wayland servers of WSLg, for the ones that aren't set.
+func WSLgDisplayEnv() []string {
+	var env []string
+	if os.Getenv("DISPLAY") == "" {
+		env = append(env, "DISPLAY=:0")
+	}
+	if os.Getenv("WAYLAND_DISPLAY") == "" {
+		env = append(env, "WAYLAND_DISPLAY=wayland-0")
+	}
+	if os.Getenv("XDG_RUNTIME_DIR") == "" {
+		env = append(env, "XDG_RUNTIME_DIR="+filepath.Join(wslgPath, "runtime-dir"))
+	}
+	return env
+}
+
+// IsDevContainer returns whether hover runs in a dev container, of VS Code,
+// GitHub Codespaces or the devcontainer CLI.
+func IsDevContainer() bool {
+	for _, name := range []string{"REMOTE_CONTAINERS", "CODESPACES", "DEVCONTAINER"} {
+		if os.Getenv(name) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// HasDisplay returns whether the GUI apps started from hover have a display
+// server to connect to.
+func HasDisplay() bool {
+	return os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != ""
+}
+
+// IsWindowsFilesystem returns whether a path of WSL is on a drive of the
+// windows host, mounted with 9p or drvfs. The files of those drives are much
+// slower to read and write from WSL than the linux filesystem.
+func IsWindowsFilesystem(path string) bool {
+	if !IsWSL() {
+		return false
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	switch mountType(path) {
+	case "9p", "drvfs":
+		return true
+	}
+	return false
+}
+
+// mountType returns the filesystem type of the mount containing a path,
+// empty when it can't be read from /proc/mounts.
+func mountType(path string) string {
+	mounts, err := os.Open("/proc/mounts")
+	if err != nil {
+		return ""
+	}
+	defer mounts.Close()
+	var mountPoint, fsType string
+	scanner := bufio.NewScanner(mounts)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		// the mount points escape their spaces as \040
+		point := strings.Replace(fields[1], `\040`, " ", -1)
+		if path != point && !strings.HasPrefix(path, strings.TrimSuffix(point, "/")+"/") {
+			continue
+		}
+		if len(point) >= len(mountPoint) {
+			mountPoint, fsType = point, fields[2]
+		}
+	}
+	return fsType
+}
@@ -0,0 +1,58 @@
+package i18n
+
+func init() {
+	catalogs["fr"] = map[string]string{
+		// log
+		"For more information about this error, run `%s`.": "Pour plus d'informations sur cette erreur, lancez `%s`.",
+		"Command failed: %v": "La commande a échoué : %v",
+
+		// project
+		"%v\nThis command should be run from the root of your Flutter project.":                                             "%v\nCette commande doit être lancée à la racine du projet Flutter.",
+		"Directory '%s' is missing. Please init go-flutter first: %s":                                                       "Le dossier '%s' est absent. Initialisez d'abord go-flutter : %s",
+		"The directory doesn't appear to contain a plugin package.\nTo create a new plugin, first run `%s`, then run `%s`.": "Le dossier ne semble pas contenir de plugin.\nPour créer un plugin, lancez d'abord `%s`, puis `%s`.",
+		"Failed to lookup `%s` executable: %s. %s":                                                                          "L'exécutable `%s` est introuvable : %s. %s",
+		"A file or directory named '%s' already exists. Cannot continue init.":                                              "Un fichier ou dossier nommé '%s' existe déjà. Impossible de poursuivre l'initialisation.",
+		"You can add the '%s' directory to git.":                                                                            "Vous pouvez ajouter le dossier '%s' à git.",
+		"Available plugin for this project:":                                                                                "Plugins disponibles pour ce projet :",
+
+		// flutter
+		"⚠ The go-flutter project tries to stay compatible with the beta channel of Flutter.": "⚠ Le projet go-flutter s'efforce de rester compatible avec le canal beta de Flutter.",
+		"⚠     It's advised to use the beta channel: `%s`":                                    "⚠     Il est conseillé d'utiliser le canal beta : `%s`",
+		"Cannot check the Flutter version against the `%s` constraint of pubspec.yaml: %v":    "Impossible de vérifier la version de Flutter avec la contrainte `%s` de pubspec.yaml : %v",
+
+		// build
+		"Building flutter bundle":                                      "Construction du bundle flutter",
+		"Flutter build failed: %v":                                     "La construction flutter a échoué : %v",
+		"Compiling 'go-flutter' and plugins":                           "Compilation de 'go-flutter' et des plugins",
+		"Go build failed: %v":                                          "La compilation go a échoué : %v",
+		"Successfully compiled":                                        "Compilation réussie",
+		"Cleaning the build directory":                                 "Nettoyage du dossier de construction",
+		"Downloading 'go-flutter' %s":                                  "Téléchargement de 'go-flutter' %s",
+		"Upgrading 'go-flutter' to the latest release":                 "Mise à jour de 'go-flutter' vers la dernière version",
+		"Upgrade ignored, current 'go-flutter' version: %s":            "Mise à jour ignorée, version actuelle de 'go-flutter' : %s",
+		"Run `%s` to update plugins":                                   "Lancez `%s` pour mettre à jour les plugins",
+		"Target file \"%s\" not found.":                                "Le fichier cible \"%s\" est introuvable.",
+		"You can define a custom traget by using the %s flag.":         "Vous pouvez définir une autre cible avec l'option %s.",
+		"changing the engine version can lead to undesirable behavior": "changer la version du moteur peut entraîner des comportements indésirables",
+
+		// run
+		"Build finished, starting app...":                             "Construction terminée, démarrage de l'application...",
+		"Running %s in debug mode":                                    "Lancement de %s en mode debug",
+		"Connecting hover to '%s' for hot reload":                     "Connexion de hover à '%s' pour le hot reload",
+		"App '%s' exited.":                                            "L'application '%s' s'est arrêtée.",
+		"Closing the flutter attach sub process..":                    "Arrêt du sous-processus flutter attach..",
+		"The command 'flutter attach' failed: %v hot reload disabled": "La commande 'flutter attach' a échoué : %v hot reload désactivé",
+		"Using the run profile %s":                                    "Utilisation du profil de lancement %s",
+
+		// packaging
+		"go/packaging/%s has been created. You can modify the configuration files and add it to git.": "go/packaging/%s a été créé. Vous pouvez modifier les fichiers de configuration et l'ajouter à git.",
+		"You now can package the %s using `%s`":                                                       "Vous pouvez maintenant empaqueter pour %s avec `%s`",
+		"Packaging %s in %s":                                                                          "Empaquetage %s dans %s",
+		"Packaging %s skipped, the inputs didn't change since a previous run":                         "Empaquetage %s ignoré, les entrées n'ont pas changé depuis une exécution précédente",
+		"Using icon %s":                  "Utilisation de l'icône %s",
+		"Generating dynamic build files": "Génération des fichiers de construction dynamiques",
+		"Packaging failed: %v":           "L'empaquetage a échoué : %v",
+		"%s is not initialized for packaging. Please run `hover init-packaging %s` first.": "%s n'est pas initialisé pour l'empaquetage. Lancez d'abord `hover init-packaging %s`.",
+		"You can package the app without hover by running:":                                "Vous pouvez empaqueter l'application sans hover en lançant :",
+	}
+}
@@ -0,0 +1,109 @@
+// Package i18n translates the messages hover prints. The messages are written
+// in English in the code and used as the keys of the catalogs of the other
+// languages, a message without translation is printed in English.
+package i18n
+
+import (
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// catalogs maps the languages (fr) or locales (pt_BR) to their translations,
+// registered by the catalog files of the package.
+var catalogs = map[string]map[string]string{}
+
+var (
+	locale     string
+	catalog    map[string]string
+	detectOnce sync.Once
+)
+
+// SetLocale translates the messages to a locale (fr, pt_BR, fr_FR.UTF-8) in
+// place of the locale of the system. It returns false when the locale has no
+// catalog, the messages are then printed in English.
+func SetLocale(l string) bool {
+	detectOnce.Do(func() {})
+	locale, catalog = lookup(l)
+	return catalog != nil
+}
+
+// Locale returns the locale of the catalog the messages are translated with,
+// empty when they are printed in English.
+func Locale() string {
+	detectOnce.Do(detect)
+	return locale
+}
+
+// Locales returns the locales that have a catalog.
+func Locales() []string {
+	var locales []string
+	for l := range catalogs {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// T returns the translation of a message, a format of the fmt package. The
+// translation is ignored when it doesn't use the verbs of the message.
+func T(message string) string {
+	detectOnce.Do(detect)
+	translation, ok := catalog[message]
+	if !ok || !sameVerbs(message, translation) {
+		return message
+	}
+	return translation
+}
+
+func detect() {
+	locale, catalog = lookup(DetectLocale())
+}
+
+// DetectLocale returns the locale of the user: the HOVER_LANG environment
+// variable, the variables of gettext (LANGUAGE, LC_ALL, LC_MESSAGES and
+// LANG), or the language of the user on windows and darwin.
+func DetectLocale() string {
+	for _, name := range []string{"HOVER_LANG", "LANGUAGE", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		// LANGUAGE is a list of languages in order of preference
+		value := strings.Split(os.Getenv(name), ":")[0]
+		if value != "" {
+			return value
+		}
+	}
+	return systemLocale()
+}
+
+// lookup returns the catalog of a locale, or of its language.
+func lookup(l string) (string, map[string]string) {
+	// fr_FR.UTF-8@euro, fr-FR
+	l = strings.SplitN(l, ".", 2)[0]
+	l = strings.SplitN(l, "@", 2)[0]
+	l = strings.Replace(l, "-", "_", 1)
+	if c, ok := catalogs[l]; ok {
+		return l, c
+	}
+	language := strings.ToLower(strings.SplitN(l, "_", 2)[0])
+	if c, ok := catalogs[language]; ok {
+		return language, c
+	}
+	return "", nil
+}
+
+var verbRegexp = regexp.MustCompile(`%(\[\d+\])?[-+# 0]*\d*(\.\d*)?[a-zA-Z%]`)
+
+// sameVerbs returns whether two formats use the same verbs, the translations
+// can change their order with explicit argument indexes (%[2]s).
+func sameVerbs(message, translation string) bool {
+	verbs := func(format string) string {
+		var v []string
+		for _, verb := range verbRegexp.FindAllString(format, -1) {
+			v = append(v, verb[len(verb)-1:])
+		}
+		sort.Strings(v)
+		return strings.Join(v, "")
+	}
+	return verbs(message) == verbs(translation)
+}
@@ -0,0 +1,16 @@
+package i18n
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// systemLocale returns the locale of the user set in the System Preferences,
+// the environment of the apps started from the Finder has no LANG.
+func systemLocale() string {
+	out, err := exec.Command("defaults", "read", "-g", "AppleLocale").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,10 @@
+//go:build !windows && !darwin
+// +build !windows,!darwin
+
+package i18n
+
+// systemLocale returns nothing, the locale of the user is set by the
+// environment variables on linux and the BSDs.
+func systemLocale() string {
+	return ""
+}
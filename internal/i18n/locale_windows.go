@@ -0,0 +1,19 @@
+package i18n
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var getUserDefaultLocaleName = syscall.NewLazyDLL("kernel32.dll").NewProc("GetUserDefaultLocaleName")
+
+// systemLocale returns the locale of the user (fr-FR).
+func systemLocale() string {
+	// LOCALE_NAME_MAX_LENGTH
+	buffer := make([]uint16, 85)
+	ret, _, _ := getUserDefaultLocaleName.Call(uintptr(unsafe.Pointer(&buffer[0])), uintptr(len(buffer)))
+	if ret == 0 {
+		return ""
+	}
+	return syscall.UTF16ToString(buffer)
+}
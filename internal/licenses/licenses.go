@@ -0,0 +1,207 @@
+// Package licenses finds the licenses of the go modules and pub packages an
+// app depends on.
+package licenses
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+)
+
+// Unknown is the license of the packages whose license couldn't be detected.
+const Unknown = "unknown"
+
+// Copyleft contains the copyleft licenses, which are denied by default.
+var Copyleft = []string{"AGPL-3.0", "GPL-2.0", "GPL-3.0", "LGPL-2.1", "LGPL-3.0", "MPL-2.0"}
+
+// Package is a dependency of the app.
+type Package struct {
+	Source      string // go or pub
+	Name        string
+	Version     string
+	License     string // SPDX identifier, or Unknown
+	LicenseText string
+}
+
+// detectors identify a license by phrases of its text. The order matters:
+// the LGPL and AGPL texts mention the GPL.
+var detectors = []struct {
+	license string
+	phrases []string
+}{
+	{"AGPL-3.0", []string{"GNU AFFERO GENERAL PUBLIC LICENSE"}},
+	{"LGPL-3.0", []string{"GNU LESSER GENERAL PUBLIC LICENSE", "Version 3"}},
+	{"LGPL-2.1", []string{"GNU LESSER GENERAL PUBLIC LICENSE"}},
+	{"GPL-3.0", []string{"GNU GENERAL PUBLIC LICENSE", "Version 3"}},
+	{"GPL-2.0", []string{"GNU GENERAL PUBLIC LICENSE"}},
+	{"MPL-2.0", []string{"Mozilla Public License", "2.0"}},
+	{"Apache-2.0", []string{"Apache License", "Version 2.0"}},
+	{"BSD-3-Clause", []string{"Redistribution and use in source and binary forms", "Neither the name"}},
+	{"BSD-2-Clause", []string{"Redistribution and use in source and binary forms"}},
+	{"ISC", []string{"Permission to use, copy, modify, and/or distribute this software for any"}},
+	{"MIT", []string{"Permission is hereby granted, free of charge"}},
+	{"BSL-1.0", []string{"Boost Software License"}},
+	{"Zlib", []string{"This software is provided 'as-is'", "Altered source versions must be plainly marked"}},
+	{"Unlicense", []string{"This is free and unencumbered software released into the public domain"}},
+}
+
+// Detect returns the SPDX identifier of a license text, or Unknown.
+func Detect(text string) string {
+	// the phrases can be wrapped anywhere
+	normalized := strings.Join(strings.Fields(text), " ")
+	for _, detector := range detectors {
+		matches := true
+		for _, phrase := range detector.phrases {
+			if !strings.Contains(normalized, phrase) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return detector.license
+		}
+	}
+	return Unknown
+}
+
+// readLicense reads the license file at the root of a package directory.
+func readLicense(dir string) (string, string) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return Unknown, ""
+	}
+	for _, file := range files {
+		name := strings.ToUpper(file.Name())
+		if file.IsDir() || !(strings.HasPrefix(name, "LICENSE") || strings.HasPrefix(name, "LICENCE") || strings.HasPrefix(name, "COPYING")) {
+			continue
+		}
+		text, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			continue
+		}
+		return Detect(string(text)), string(text)
+	}
+	return Unknown, ""
+}
+
+// GoModules returns the go modules the packages of the app, in the go
+// directory, are built with.
+func GoModules(goDir string) ([]Package, error) {
+	cmdGoList := exec.Command(build.GoBin(), "list", "-deps",
+		"-f", "{{if and .Module (not .Module.Main)}}{{.Module.Path}}\t{{.Module.Version}}\t{{.Module.Dir}}{{end}}",
+		"./...",
+	)
+	cmdGoList.Dir = goDir
+	cmdGoList.Env = append(os.Environ(), "GO111MODULE=on")
+	var stderr bytes.Buffer
+	cmdGoList.Stderr = &stderr
+	out, err := cmdGoList.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "go list failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	seen := make(map[string]bool)
+	var packages []Package
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) != 3 || seen[fields[0]] {
+			continue
+		}
+		seen[fields[0]] = true
+		license, text := readLicense(fields[2])
+		packages = append(packages, Package{Source: "go", Name: fields[0], Version: fields[1], License: license, LicenseText: text})
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Name < packages[j].Name })
+	return packages, nil
+}
+
+// packageConfig is the .dart_tool/package_config.json file written by
+// `flutter pub get`.
+type packageConfig struct {
+	Packages []struct {
+		Name    string `json:"name"`
+		RootURI string `json:"rootUri"`
+	} `json:"packages"`
+}
+
+// PubPackages returns the pub packages resolved for the flutter project in
+// the current directory, except the project itself.
+func PubPackages(projectName string) ([]Package, error) {
+	roots := make(map[string]string)
+	content, err := ioutil.ReadFile(filepath.Join(".dart_tool", "package_config.json"))
+	if err == nil {
+		var config packageConfig
+		err = json.Unmarshal(content, &config)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode .dart_tool/package_config.json")
+		}
+		for _, p := range config.Packages {
+			roots[p.Name] = resolveURI(p.RootURI, ".dart_tool")
+		}
+	} else if os.IsNotExist(err) {
+		// projects of older flutter versions only have the .packages file,
+		// which maps each package to its lib directory.
+		content, err = ioutil.ReadFile(".packages")
+		if err != nil {
+			return nil, errors.Wrap(err, "no .dart_tool/package_config.json or .packages file found, run `flutter pub get` first")
+		}
+		for _, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			roots[parts[0]] = filepath.Dir(resolveURI(strings.TrimSuffix(parts[1], "/"), "."))
+		}
+	} else {
+		return nil, errors.Wrap(err, "failed to read .dart_tool/package_config.json")
+	}
+
+	var packages []Package
+	for name, root := range roots {
+		if name == projectName {
+			continue
+		}
+		license, text := readLicense(root)
+		packages = append(packages, Package{Source: "pub", Name: name, Version: pubVersion(root), License: license, LicenseText: text})
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Name < packages[j].Name })
+	return packages, nil
+}
+
+// resolveURI returns the path of a file:// or relative URI.
+func resolveURI(uri, base string) string {
+	u, err := url.Parse(uri)
+	if err == nil && u.Scheme == "file" {
+		return filepath.FromSlash(u.Path)
+	}
+	return filepath.Join(base, filepath.FromSlash(uri))
+}
+
+// pubVersion reads the version from the pubspec.yaml of a package.
+func pubVersion(root string) string {
+	content, err := ioutil.ReadFile(filepath.Join(root, "pubspec.yaml"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(line, "version:") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "version:")), `"'`)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,31 @@
+package licenses
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"MIT", "MIT License\n\nPermission is hereby granted, free of charge, to any person obtaining a copy", "MIT"},
+		{"MIT wrapped", "Permission is hereby\n   granted, free of\tcharge", "MIT"},
+		{"Apache", "Apache License\nVersion 2.0, January 2004", "Apache-2.0"},
+		{"BSD-3-Clause", "Redistribution and use in source and binary forms, with or without modification... Neither the name of the copyright holder", "BSD-3-Clause"},
+		{"BSD-2-Clause", "Redistribution and use in source and binary forms, with or without modification", "BSD-2-Clause"},
+		{"GPL-3.0", "GNU GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007", "GPL-3.0"},
+		{"GPL-2.0", "GNU GENERAL PUBLIC LICENSE\nVersion 2, June 1991", "GPL-2.0"},
+		{"LGPL-3.0 mentions the GPL", "GNU LESSER GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007\nthe GNU GENERAL PUBLIC LICENSE", "LGPL-3.0"},
+		{"LGPL-2.1", "GNU LESSER GENERAL PUBLIC LICENSE\nVersion 2.1, February 1999", "LGPL-2.1"},
+		{"AGPL-3.0 mentions the GPL", "GNU AFFERO GENERAL PUBLIC LICENSE\nVersion 3\nGNU GENERAL PUBLIC LICENSE", "AGPL-3.0"},
+		{"MPL-2.0", "Mozilla Public License Version 2.0", "MPL-2.0"},
+		{"Unlicense", "This is free and unencumbered software released into the public domain.", "Unlicense"},
+		{"unknown", "All rights reserved.", Unknown},
+		{"empty", "", Unknown},
+	}
+	for _, test := range tests {
+		if got := Detect(test.text); got != test.want {
+			t.Errorf("%s: Detect() = %s, want %s", test.name, got, test.want)
+		}
+	}
+}
@@ -2,17 +2,75 @@ package log
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/logrusorgru/aurora"
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/i18n"
+)
+
+// Color modes supported by the `--color` flag.
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
 )
 
 // Colorize chnage the logger to support colors printing.
 func Colorize() {
-	au = aurora.NewAurora(true)
+	setColors(true)
+}
+
+// SetColorMode enables or disables colors depending on the given mode.
+// In auto mode, colors are only enabled when stdout is a terminal and neither
+// NO_COLOR is set nor TERM is 'dumb'.
+func SetColorMode(mode string) error {
+	switch mode {
+	case ColorAlways:
+		setColors(true)
+	case ColorNever:
+		setColors(false)
+	case ColorAuto, "":
+		setColors(IsTerminal() && !colorsDisabledByEnv())
+	default:
+		return errors.Errorf("invalid color mode '%s', must be one of %s, %s or %s", mode, ColorAuto, ColorAlways, ColorNever)
+	}
+	return nil
+}
+
+// ColorsEnabled returns whether the logger prints colors.
+func ColorsEnabled() bool {
+	return colors
+}
+
+// IsTerminal returns whether stdout is attached to a terminal. It returns
+// false when the output is piped or redirected to a file (e.g. in CI).
+func IsTerminal() bool {
+	fi, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// colorsDisabledByEnv checks the NO_COLOR (https://no-color.org) and TERM
+// environment variables.
+func colorsDisabledByEnv() bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return true
+	}
+	return os.Getenv("TERM") == "dumb"
 }
 
 // internal colorized
 var au aurora.Aurora
+var colors bool
+
+func setColors(enabled bool) {
+	colors = enabled
+	au = aurora.NewAurora(enabled)
+}
 
 // Au Aurora instance used for colors
 func Au() aurora.Aurora {
@@ -25,25 +83,34 @@ func Au() aurora.Aurora {
 // Printf print a message with formatting
 func Printf(part string, parts ...interface{}) {
 	hoverPrint()
-	fmt.Println(fmt.Sprintf(part, parts...))
+	fmt.Println(fmt.Sprintf(i18n.T(part), parts...))
 }
 
 // Errorf print a error with formatting (red)
 func Errorf(part string, parts ...interface{}) {
 	hoverPrint()
-	fmt.Println(Au().Colorize(fmt.Sprintf(fmt.Sprintf("%v", part), parts...), aurora.RedFg).String())
+	fmt.Println(Au().Colorize(fmt.Sprintf(i18n.T(fmt.Sprintf("%v", part)), parts...), aurora.RedFg).String())
+}
+
+// ErrorCodef print an error identified by a code (red), followed by the
+// command explaining it
+func ErrorCodef(code string, part string, parts ...interface{}) {
+	hoverPrint()
+	fmt.Println(Au().Colorize(fmt.Sprintf("error[%s]: %s", code, fmt.Sprintf(i18n.T(part), parts...)), aurora.RedFg).String())
+	hoverPrint()
+	fmt.Printf(i18n.T("For more information about this error, run `%s`.")+"\n", Au().Magenta("hover explain "+code))
 }
 
 // Warnf print a warning with formatting (yellow)
 func Warnf(part string, parts ...interface{}) {
 	hoverPrint()
-	fmt.Println(Au().Colorize(fmt.Sprintf(fmt.Sprintf("%v", part), parts...), aurora.YellowFg).String())
+	fmt.Println(Au().Colorize(fmt.Sprintf(i18n.T(fmt.Sprintf("%v", part)), parts...), aurora.YellowFg).String())
 }
 
 // Infof print a information with formatting (green)
 func Infof(part string, parts ...interface{}) {
 	hoverPrint()
-	fmt.Println(Au().Colorize(fmt.Sprintf(fmt.Sprintf("%v", part), parts...), aurora.GreenFg).String())
+	fmt.Println(Au().Colorize(fmt.Sprintf(i18n.T(fmt.Sprintf("%v", part)), parts...), aurora.GreenFg).String())
 }
 
 func hoverPrint() {
@@ -6,6 +6,8 @@ import (
 	"log"
 	"os"
 	"strings"
+
+	hoverlog "github.com/go-flutter-desktop/hover/internal/log"
 )
 
 type Logstreamer struct {
@@ -54,7 +56,7 @@ func NewLogstreamer(logger *log.Logger, prefix string, record bool) *Logstreamer
 		colorReset: "",
 	}
 
-	if strings.HasPrefix(os.Getenv("TERM"), "xterm") {
+	if strings.HasPrefix(os.Getenv("TERM"), "xterm") && hoverlog.ColorsEnabled() {
 		streamer.colorOkay = "\x1b[32m"
 		streamer.colorFail = "\x1b[31m"
 		streamer.colorReset = "\x1b[0m"
@@ -0,0 +1,185 @@
+// Package provenance generates in-toto attestations with SLSA provenance
+// predicates describing how artifacts were built. The attestations are
+// signed as DSSE envelopes and can be uploaded to a Rekor transparency log.
+package provenance
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// Types of the in-toto statements, predicates and payloads.
+const (
+	StatementType   = "https://in-toto.io/Statement/v1"
+	PredicateType   = "https://slsa.dev/provenance/v1"
+	PayloadType     = "application/vnd.in-toto+json"
+	BuildType       = "https://github.com/go-flutter-desktop/hover/build/v1"
+	BuilderID       = "https://github.com/go-flutter-desktop/hover"
+	DefaultRekorURL = "https://rekor.sigstore.dev"
+)
+
+// Statement is an in-toto statement about the artifacts of a build.
+type Statement struct {
+	Type          string     `json:"_type"`
+	Subject       []Resource `json:"subject"`
+	PredicateType string     `json:"predicateType"`
+	Predicate     Predicate  `json:"predicate"`
+}
+
+// Predicate is a SLSA provenance predicate.
+type Predicate struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+// BuildDefinition describes the inputs of a build.
+type BuildDefinition struct {
+	BuildType            string                 `json:"buildType"`
+	ExternalParameters   map[string]interface{} `json:"externalParameters"`
+	InternalParameters   map[string]interface{} `json:"internalParameters,omitempty"`
+	ResolvedDependencies []Resource             `json:"resolvedDependencies,omitempty"`
+}
+
+// RunDetails describes the builder and the run of a build.
+type RunDetails struct {
+	Builder  Builder       `json:"builder"`
+	Metadata BuildMetadata `json:"metadata"`
+}
+
+// Builder is the tool that built the artifacts, with the versions of its
+// toolchain.
+type Builder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version,omitempty"`
+}
+
+// BuildMetadata contains the times of a build, in RFC 3339 format.
+type BuildMetadata struct {
+	StartedOn  string `json:"startedOn,omitempty"`
+	FinishedOn string `json:"finishedOn,omitempty"`
+}
+
+// Resource is an artifact or an input of a build.
+type Resource struct {
+	Name   string            `json:"name,omitempty"`
+	URI    string            `json:"uri,omitempty"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// FileResource returns the resource of a file, with its sha256 digest.
+func FileResource(name, path string) (Resource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Resource{}, errors.Wrapf(err, "failed to open %s", path)
+	}
+	defer file.Close()
+	h := sha256.New()
+	_, err = io.Copy(h, file)
+	if err != nil {
+		return Resource{}, errors.Wrapf(err, "failed to hash %s", path)
+	}
+	return Resource{Name: name, Digest: map[string]string{"sha256": hex.EncodeToString(h.Sum(nil))}}, nil
+}
+
+// Envelope is a DSSE envelope.
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+// Signature is a signature of a DSSE envelope.
+type Signature struct {
+	KeyID string `json:"keyid,omitempty"`
+	Sig   string `json:"sig"`
+}
+
+// Sign encodes a statement in a DSSE envelope, signed with the key. Without
+// a key, the envelope isn't signed.
+func Sign(statement Statement, key crypto.Signer) (Envelope, error) {
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return Envelope{}, errors.Wrap(err, "failed to encode the statement")
+	}
+	envelope := Envelope{
+		PayloadType: PayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []Signature{},
+	}
+	if key == nil {
+		return envelope, nil
+	}
+	pae := []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(PayloadType), PayloadType, len(payload), payload))
+	sig, err := SignBlob(pae, key)
+	if err != nil {
+		return Envelope{}, errors.Wrap(err, "failed to sign the statement")
+	}
+	keyID, err := KeyID(key.Public())
+	if err != nil {
+		return Envelope{}, err
+	}
+	envelope.Signatures = append(envelope.Signatures, Signature{KeyID: keyID, Sig: base64.StdEncoding.EncodeToString(sig)})
+	return envelope, nil
+}
+
+// SignBlob signs data with an Ed25519 key, or the sha256 digest of data with
+// an ECDSA key, returning an ASN.1 signature.
+func SignBlob(data []byte, key crypto.Signer) ([]byte, error) {
+	if _, ok := key.(ed25519.PrivateKey); ok {
+		return key.Sign(rand.Reader, data, crypto.Hash(0))
+	}
+	digest := sha256.Sum256(data)
+	return key.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
+
+// KeyID returns the sha256 digest of the DER encoded public key.
+func KeyID(publicKey crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode the public key")
+	}
+	digest := sha256.Sum256(der)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// ParseKey parses an unencrypted PEM encoded ECDSA or Ed25519 private key,
+// which signs the provenance attestations and the integrity manifests.
+func ParseKey(pemBytes []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("the key isn't PEM encoded")
+	}
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, errors.Errorf("unsupported key type %q, must be an unencrypted ECDSA or Ed25519 private key", block.Type)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the key")
+	}
+	switch key := key.(type) {
+	case *ecdsa.PrivateKey:
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	}
+	return nil, errors.New("unsupported key, must be an ECDSA or Ed25519 private key")
+}
@@ -0,0 +1,70 @@
+package provenance
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var rekorClient = &http.Client{Timeout: 30 * time.Second}
+
+// UploadToRekor adds a signed envelope to a Rekor transparency log, as a dsse
+// entry. It returns the index of the entry in the log.
+func UploadToRekor(rekorURL string, envelope Envelope, publicKey crypto.PublicKey) (int64, error) {
+	if len(envelope.Signatures) == 0 {
+		return 0, errors.New("only signed attestations can be uploaded to Rekor")
+	}
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to encode the public key")
+	}
+	publicKeyPem := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	envelopeJSON, err := json.Marshal(envelope)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to encode the envelope")
+	}
+	entry := map[string]interface{}{
+		"apiVersion": "0.0.1",
+		"kind":       "dsse",
+		"spec": map[string]interface{}{
+			"proposedContent": map[string]interface{}{
+				"envelope":  string(envelopeJSON),
+				"verifiers": []string{base64.StdEncoding.EncodeToString(publicKeyPem)},
+			},
+		},
+	}
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to encode the Rekor entry")
+	}
+	resp, err := rekorClient.Post(strings.TrimSuffix(rekorURL, "/")+"/api/v1/log/entries", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to upload to Rekor")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		content, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return 0, errors.Errorf("Rekor responded %s: %s", resp.Status, strings.TrimSpace(string(content)))
+	}
+	var entries map[string]struct {
+		LogIndex int64 `json:"logIndex"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&entries)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to decode the Rekor response")
+	}
+	for _, e := range entries {
+		return e.LogIndex, nil
+	}
+	return 0, errors.New("Rekor didn't return the entry")
+}
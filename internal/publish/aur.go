@@ -0,0 +1,87 @@
+package publish
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+)
+
+// UpdateAurRepository updates the git repository of an AUR package, e.g.
+// ssh://aur@aur.archlinux.org/myapp-bin.git. write writes the PKGBUILD and
+// the .SRCINFO of the version to the clone of the repository, they are
+// committed and pushed. Nothing is pushed when the files didn't change.
+func UpdateAurRepository(remote, version string, write func(dir string) error) error {
+	// the AUR only accepts the master branch
+	return updateGitRepository(remote, "HEAD:master", version, write, "PKGBUILD", ".SRCINFO")
+}
+
+// updateGitRepository clones a git repository, writes files to it with
+// write, and commits and pushes them to a ref of the remote. Nothing is
+// pushed when the files didn't change.
+func updateGitRepository(remote, ref, version string, write func(dir string) error, files ...string) error {
+	tmpDir, err := ioutil.TempDir("", "hover-publish-git")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary directory")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	git := func(args ...string) ([]byte, error) {
+		cmd := exec.Command(build.GitBin(), args...)
+		cmd.Dir = tmpDir
+		cmd.Stderr = os.Stderr
+		return cmd.Output()
+	}
+	// the repository of a new package is empty
+	_, err = git("clone", remote, ".")
+	if err != nil {
+		return errors.Wrapf(err, "failed to clone %s", remote)
+	}
+	err = write(tmpDir)
+	if err != nil {
+		return err
+	}
+	_, err = git(append([]string{"add"}, files...)...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to add %s", strings.Join(files, " and "))
+	}
+	status, err := git("status", "--porcelain")
+	if err != nil {
+		return errors.Wrap(err, "failed to read the status of the repository")
+	}
+	if len(strings.TrimSpace(string(status))) == 0 {
+		return nil
+	}
+	_, err = git("commit", "-m", "Update to "+version)
+	if err != nil {
+		return errors.Wrapf(err, "failed to commit %s", strings.Join(files, " and "))
+	}
+	_, err = git("push", "origin", ref)
+	if err != nil {
+		return errors.Wrapf(err, "failed to push to %s", remote)
+	}
+	return nil
+}
+
+// PublicURL returns the URL the artifacts published to an HTTP or WebDAV
+// destination are downloaded from, without its credentials.
+func PublicURL(destination string) (string, error) {
+	u, err := url.Parse(destination)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse destination %s", destination)
+	}
+	switch u.Scheme {
+	case "http", "https":
+	case "webdav", "webdavs":
+		u.Scheme = strings.Replace(u.Scheme, "webdav", "http", 1)
+	default:
+		return "", errors.Errorf("the artifacts published to %s have no known public URL", destination)
+	}
+	u.User = nil
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
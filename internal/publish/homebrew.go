@@ -0,0 +1,12 @@
+package publish
+
+import "path"
+
+// UpdateHomebrewTap updates the git repository of a Homebrew tap, e.g.
+// git@github.com:me/homebrew-tap.git. write writes the cask of the version
+// to Casks/<token>.rb in the clone of the repository, it's committed and
+// pushed to the current branch. Nothing is pushed when the cask didn't
+// change.
+func UpdateHomebrewTap(remote, token, version string, write func(dir string) error) error {
+	return updateGitRepository(remote, "HEAD", version, write, path.Join("Casks", token+".rb"))
+}
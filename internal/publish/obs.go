@@ -0,0 +1,81 @@
+package publish
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/otiai10/copy"
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/fileutils"
+)
+
+// UpdateObsPackage commits the files of dir to a package of the Open Build
+// Service, e.g. home:me/my-app, with osc and its configured account. The
+// package is created when it doesn't exist, and the files of the previous
+// version that aren't in dir are removed.
+func UpdateObsPackage(project, pkg, title, version, dir string, files []string) error {
+	tmpDir, err := ioutil.TempDir("", "hover-publish-obs")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary directory")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	osc := func(dir string, args ...string) error {
+		cmd := exec.Command(build.OscBin(), args...)
+		cmd.Dir = dir
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+	err = exec.Command(build.OscBin(), "meta", "pkg", project, pkg).Run()
+	if err != nil {
+		meta := fmt.Sprintf("<package name=\"%s\" project=\"%s\">\n  <title>%s</title>\n  <description/>\n</package>\n", fileutils.XMLEscape(pkg), fileutils.XMLEscape(project), fileutils.XMLEscape(title))
+		metaPath := filepath.Join(tmpDir, "meta.xml")
+		err = ioutil.WriteFile(metaPath, []byte(meta), 0644)
+		if err != nil {
+			return err
+		}
+		err = osc(tmpDir, "meta", "pkg", project, pkg, "-F", metaPath)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create the package %s of %s", pkg, project)
+		}
+	}
+	checkoutDir := filepath.Join(tmpDir, "checkout")
+	err = osc(tmpDir, "checkout", project, pkg, "-o", checkoutDir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to checkout %s/%s", project, pkg)
+	}
+	previousFiles, err := ioutil.ReadDir(checkoutDir)
+	if err != nil {
+		return err
+	}
+	for _, file := range previousFiles {
+		if file.Name() == ".osc" {
+			continue
+		}
+		err = os.RemoveAll(filepath.Join(checkoutDir, file.Name()))
+		if err != nil {
+			return err
+		}
+	}
+	for _, file := range files {
+		err = copy.Copy(filepath.Join(dir, file), filepath.Join(checkoutDir, file))
+		if err != nil {
+			return err
+		}
+	}
+	err = osc(checkoutDir, "addremove")
+	if err != nil {
+		return errors.Wrap(err, "failed to add the files to the OBS package")
+	}
+	err = osc(checkoutDir, "commit", "-m", "Update to "+version)
+	if err != nil {
+		return errors.Wrapf(err, "failed to commit to %s/%s", project, pkg)
+	}
+	return nil
+}
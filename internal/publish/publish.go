@@ -0,0 +1,193 @@
+package publish
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+)
+
+// IndexFilename is the name of the file listing the published versions.
+const IndexFilename = "index.json"
+
+// Artifact is a packaged file to publish.
+type Artifact struct {
+	Format    string `json:"format"`
+	Arch      string `json:"arch"`
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	Size      int64  `json:"size"`
+	Sha256    string `json:"sha256"`
+	localPath string
+}
+
+// Version is an entry of the index.
+type Version struct {
+	Version   string     `json:"version"`
+	Published time.Time  `json:"published"`
+	Artifacts []Artifact `json:"artifacts"`
+}
+
+// Index lists the published versions, most recent first.
+type Index struct {
+	Name     string    `json:"name"`
+	Versions []Version `json:"versions"`
+}
+
+var contentTypes = map[string]string{
+	".AppImage":   "application/vnd.appimage",
+	".deb":        "application/vnd.debian.binary-package",
+	".dmg":        "application/x-apple-diskimage",
+	".json":       "application/json",
+	".msi":        "application/x-msi",
+	".pkg":        "application/vnd.apple.installer+xml",
+	".pkg.tar.xz": "application/x-xz",
+	".rpm":        "application/x-rpm",
+	".snap":       "application/vnd.snap",
+	".tar.gz":     "application/gzip",
+	".zip":        "application/zip",
+}
+
+// ContentType returns the MIME type of an artifact, based on its name.
+func ContentType(name string) string {
+	for extension, contentType := range contentTypes {
+		if strings.HasSuffix(name, extension) {
+			return contentType
+		}
+	}
+	if contentType := mime.TypeByExtension(filepath.Ext(name)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// ListArtifacts returns the packaged files in the outputs directory. Only the
+// outputs of packaging formats (e.g. linux-deb) are artifacts, the plain
+// builds (e.g. linux-amd64) are not.
+func ListArtifacts(outputsPath, version string) ([]Artifact, error) {
+	formats, err := ioutil.ReadDir(outputsPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list %s", outputsPath)
+	}
+	var artifacts []Artifact
+	for _, format := range formats {
+		if !format.IsDir() {
+			continue
+		}
+		target, arch := build.SplitTargetArch(format.Name())
+		if !strings.Contains(target, "-") {
+			continue
+		}
+		if arch == "" {
+			arch = build.DefaultTargetArch
+		}
+		files, err := ioutil.ReadDir(filepath.Join(outputsPath, format.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list %s", format.Name())
+		}
+		for _, file := range files {
+			// bundles such as darwin-bundle are directories, they are
+			// published as part of the formats that depend on them.
+			if file.IsDir() {
+				continue
+			}
+			localPath := filepath.Join(outputsPath, format.Name(), file.Name())
+			sum, err := sha256File(localPath)
+			if err != nil {
+				return nil, err
+			}
+			artifacts = append(artifacts, Artifact{
+				Format:    target,
+				Arch:      arch,
+				Name:      file.Name(),
+				Path:      path.Join(version, format.Name(), file.Name()),
+				Size:      file.Size(),
+				Sha256:    sum,
+				localPath: localPath,
+			})
+		}
+	}
+	return artifacts, nil
+}
+
+// LocalPath returns the path of the artifact on disk.
+func (a Artifact) LocalPath() string {
+	return a.localPath
+}
+
+func sha256File(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to open %s", path)
+	}
+	defer file.Close()
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read %s", path)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Publish uploads the artifacts and adds their version to the index of the
+// target.
+func Publish(target Target, name, version string, artifacts []Artifact) error {
+	for _, artifact := range artifacts {
+		err := target.Upload(artifact.localPath, artifact.Path, ContentType(artifact.Name))
+		if err != nil {
+			return err
+		}
+	}
+	return UpdateIndex(target, name, Version{
+		Version:   version,
+		Published: time.Now().UTC(),
+		Artifacts: artifacts,
+	})
+}
+
+// UpdateIndex adds or replaces a version in the index of the target.
+func UpdateIndex(target Target, name string, version Version) error {
+	index := Index{Name: name}
+	content, err := target.Download(IndexFilename)
+	if err != nil {
+		return err
+	}
+	if content != nil {
+		err = json.Unmarshal(content, &index)
+		if err != nil {
+			return errors.Wrapf(err, "failed to decode %s", IndexFilename)
+		}
+	}
+	versions := []Version{version}
+	for _, v := range index.Versions {
+		if v.Version != version.Version {
+			versions = append(versions, v)
+		}
+	}
+	index.Versions = versions
+
+	tmpFile, err := ioutil.TempFile("", "hover-publish-index")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary index file")
+	}
+	defer os.Remove(tmpFile.Name())
+	encoder := json.NewEncoder(tmpFile)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(index)
+	tmpFile.Close()
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode %s", IndexFilename)
+	}
+	return target.Upload(tmpFile.Name(), IndexFilename, ContentType(IndexFilename))
+}
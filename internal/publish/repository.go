@@ -0,0 +1,362 @@
+package publish
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+)
+
+// UpdateAptRepository adds the .deb packages to a flat apt repository
+// (`deb https://example.com/apt ./` in sources.list). The packages are
+// uploaded to the pool directory, and the Packages and Release indexes are
+// regenerated from the existing ones. With a gpgKey, the Release file is
+// signed as InRelease and Release.gpg.
+func UpdateAptRepository(target Target, artifacts []Artifact, gpgKey string) error {
+	tmpDir, err := ioutil.TempDir("", "hover-publish-apt")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary directory")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	content, err := target.Download("Packages")
+	if err != nil {
+		return err
+	}
+	stanzas := debianStanzas(string(content))
+	for _, artifact := range artifacts {
+		control, err := exec.Command(build.DpkgDebBin(), "--field", artifact.localPath).Output()
+		if err != nil {
+			return errors.Wrapf(err, "failed to read the control fields of %s", artifact.Name)
+		}
+		md5sum, err := hashFile(md5.New(), artifact.localPath)
+		if err != nil {
+			return err
+		}
+		sha1sum, err := hashFile(sha1.New(), artifact.localPath)
+		if err != nil {
+			return err
+		}
+		poolPath := path.Join("pool", artifact.Name)
+		stanza := strings.TrimSpace(string(control)) + "\n" +
+			"Filename: " + poolPath + "\n" +
+			fmt.Sprintf("Size: %d\n", artifact.Size) +
+			"MD5sum: " + md5sum + "\n" +
+			"SHA1: " + sha1sum + "\n" +
+			"SHA256: " + artifact.Sha256
+
+		// a package that is published again replaces the previous upload
+		key := debianStanzaKey(stanza)
+		var kept []string
+		for _, s := range stanzas {
+			if debianStanzaKey(s) != key {
+				kept = append(kept, s)
+			}
+		}
+		stanzas = append(kept, stanza)
+
+		err = target.Upload(artifact.localPath, poolPath, ContentType(artifact.Name))
+		if err != nil {
+			return err
+		}
+	}
+
+	packages := []byte(strings.Join(stanzas, "\n\n") + "\n")
+	err = ioutil.WriteFile(filepath.Join(tmpDir, "Packages"), packages, 0644)
+	if err != nil {
+		return errors.Wrap(err, "failed to write Packages")
+	}
+	var packagesGz bytes.Buffer
+	gz := gzip.NewWriter(&packagesGz)
+	_, err = gz.Write(packages)
+	if err == nil {
+		err = gz.Close()
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to compress Packages")
+	}
+	err = ioutil.WriteFile(filepath.Join(tmpDir, "Packages.gz"), packagesGz.Bytes(), 0644)
+	if err != nil {
+		return errors.Wrap(err, "failed to write Packages.gz")
+	}
+
+	err = ioutil.WriteFile(filepath.Join(tmpDir, "Release"), aptRelease(stanzas, tmpDir, "Packages", "Packages.gz"), 0644)
+	if err != nil {
+		return errors.Wrap(err, "failed to write Release")
+	}
+	files := []string{"Packages", "Packages.gz", "Release"}
+	if gpgKey != "" {
+		err = gpgSign(gpgKey, filepath.Join(tmpDir, "Release"), filepath.Join(tmpDir, "InRelease"), "--clearsign")
+		if err != nil {
+			return err
+		}
+		err = gpgSign(gpgKey, filepath.Join(tmpDir, "Release"), filepath.Join(tmpDir, "Release.gpg"), "--detach-sign", "--armor")
+		if err != nil {
+			return err
+		}
+		files = append(files, "InRelease", "Release.gpg")
+	}
+	// the Release files are uploaded last, so clients never get a Release
+	// file referencing indexes that aren't uploaded yet.
+	for _, file := range files {
+		err = target.Upload(filepath.Join(tmpDir, file), file, "text/plain")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// debianStanzas splits a Packages index into its paragraphs.
+func debianStanzas(content string) []string {
+	var stanzas []string
+	for _, stanza := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n\n") {
+		stanza = strings.TrimSpace(stanza)
+		if stanza != "" {
+			stanzas = append(stanzas, stanza)
+		}
+	}
+	return stanzas
+}
+
+func debianField(stanza, name string) string {
+	for _, line := range strings.Split(stanza, "\n") {
+		if strings.HasPrefix(line, name+":") {
+			return strings.TrimSpace(strings.TrimPrefix(line, name+":"))
+		}
+	}
+	return ""
+}
+
+func debianStanzaKey(stanza string) string {
+	return debianField(stanza, "Package") + " " + debianField(stanza, "Version") + " " + debianField(stanza, "Architecture")
+}
+
+// aptRelease generates the Release file of a flat repository, listing the
+// checksums of the index files.
+func aptRelease(stanzas []string, dir string, files ...string) []byte {
+	architectures := make(map[string]bool)
+	for _, stanza := range stanzas {
+		architectures[debianField(stanza, "Architecture")] = true
+	}
+	var architectureList []string
+	for architecture := range architectures {
+		if architecture != "" {
+			architectureList = append(architectureList, architecture)
+		}
+	}
+	sort.Strings(architectureList)
+
+	var b strings.Builder
+	b.WriteString("Date: " + time.Now().UTC().Format(time.RFC1123Z) + "\n")
+	b.WriteString("Architectures: " + strings.Join(architectureList, " ") + "\n")
+	for _, sum := range []struct {
+		field string
+		hash  func() hash.Hash
+	}{
+		{"MD5Sum", md5.New},
+		{"SHA1", sha1.New},
+		{"SHA256", sha256.New},
+	} {
+		b.WriteString(sum.field + ":\n")
+		for _, file := range files {
+			filePath := filepath.Join(dir, file)
+			checksum, err := hashFile(sum.hash(), filePath)
+			if err != nil {
+				continue
+			}
+			info, err := os.Stat(filePath)
+			if err != nil {
+				continue
+			}
+			b.WriteString(fmt.Sprintf(" %s %d %s\n", checksum, info.Size(), file))
+		}
+	}
+	return []byte(b.String())
+}
+
+// repomd is the part of repodata/repomd.xml needed to find the metadata files.
+type repomd struct {
+	Data []struct {
+		Location struct {
+			Href string `xml:"href,attr"`
+		} `xml:"location"`
+	} `xml:"data"`
+}
+
+// UpdateYumRepository adds the .rpm packages to a yum repository. The
+// packages are uploaded to the Packages directory, and the repodata is
+// regenerated by createrepo_c, reusing the existing metadata so the
+// previously published packages don't need to be downloaded. With a gpgKey,
+// repomd.xml is signed as repomd.xml.asc.
+func UpdateYumRepository(target Target, artifacts []Artifact, gpgKey string) error {
+	tmpDir, err := ioutil.TempDir("", "hover-publish-yum")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary directory")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	repodataPath := filepath.Join(tmpDir, "repodata")
+	packagesPath := filepath.Join(tmpDir, "Packages")
+	for _, dir := range []string{repodataPath, packagesPath} {
+		err = os.MkdirAll(dir, 0755)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create directory %s", dir)
+		}
+	}
+
+	createrepoArgs := []string{"--quiet", "--general-compress-type=gz"}
+	content, err := target.Download("repodata/repomd.xml")
+	if err != nil {
+		return err
+	}
+	if content != nil {
+		var existing repomd
+		err = xml.Unmarshal(content, &existing)
+		if err != nil {
+			return errors.Wrap(err, "failed to decode repodata/repomd.xml")
+		}
+		err = ioutil.WriteFile(filepath.Join(repodataPath, "repomd.xml"), content, 0644)
+		if err != nil {
+			return errors.Wrap(err, "failed to write repomd.xml")
+		}
+		for _, data := range existing.Data {
+			href := path.Clean(data.Location.Href)
+			if !strings.HasPrefix(href, "repodata/") {
+				continue
+			}
+			metadata, err := target.Download(href)
+			if err != nil {
+				return err
+			}
+			if metadata == nil {
+				return errors.Errorf("%s is listed in repodata/repomd.xml but doesn't exist", href)
+			}
+			err = ioutil.WriteFile(filepath.Join(tmpDir, filepath.FromSlash(href)), metadata, 0644)
+			if err != nil {
+				return errors.Wrapf(err, "failed to write %s", href)
+			}
+		}
+		// --skip-stat and --recycle-pkglist keep the packages of the existing
+		// metadata, which aren't available locally.
+		createrepoArgs = append(createrepoArgs, "--update", "--skip-stat", "--recycle-pkglist")
+	}
+
+	for _, artifact := range artifacts {
+		err = copyArtifact(artifact, filepath.Join(packagesPath, artifact.Name))
+		if err != nil {
+			return err
+		}
+	}
+
+	cmdCreaterepo := exec.Command(build.CreaterepoBin(), append(createrepoArgs, tmpDir)...)
+	cmdCreaterepo.Stdout = os.Stdout
+	cmdCreaterepo.Stderr = os.Stderr
+	err = cmdCreaterepo.Run()
+	if err != nil {
+		return errors.Wrap(err, "createrepo_c failed")
+	}
+	if gpgKey != "" {
+		err = gpgSign(gpgKey, filepath.Join(repodataPath, "repomd.xml"), filepath.Join(repodataPath, "repomd.xml.asc"), "--detach-sign", "--armor")
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, artifact := range artifacts {
+		err = target.Upload(artifact.localPath, path.Join("Packages", artifact.Name), ContentType(artifact.Name))
+		if err != nil {
+			return err
+		}
+	}
+	files, err := ioutil.ReadDir(repodataPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the generated repodata")
+	}
+	// the metadata files have unique names, repomd.xml references them and
+	// is uploaded last.
+	for _, file := range files {
+		if strings.HasPrefix(file.Name(), "repomd.xml") {
+			continue
+		}
+		err = target.Upload(filepath.Join(repodataPath, file.Name()), path.Join("repodata", file.Name()), ContentType(file.Name()))
+		if err != nil {
+			return err
+		}
+	}
+	err = target.Upload(filepath.Join(repodataPath, "repomd.xml"), "repodata/repomd.xml", "application/xml")
+	if err != nil {
+		return err
+	}
+	if gpgKey != "" {
+		err = target.Upload(filepath.Join(repodataPath, "repomd.xml.asc"), "repodata/repomd.xml.asc", "text/plain")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func gpgSign(gpgKey, inputPath, outputPath string, signArgs ...string) error {
+	args := append([]string{"--batch", "--yes", "--local-user", gpgKey, "--output", outputPath}, signArgs...)
+	cmdGpg := exec.Command(build.GpgBin(), append(args, inputPath)...)
+	cmdGpg.Stderr = os.Stderr
+	err := cmdGpg.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to sign %s", filepath.Base(inputPath))
+	}
+	return nil
+}
+
+func hashFile(h hash.Hash, path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to open %s", path)
+	}
+	defer file.Close()
+	_, err = io.Copy(h, file)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read %s", path)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// copyArtifact copies an artifact to a local repository without loading it in
+// memory.
+func copyArtifact(artifact Artifact, to string) error {
+	src, err := os.Open(artifact.localPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %s", artifact.localPath)
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s", to)
+	}
+	_, err = io.Copy(dst, src)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to copy %s", artifact.Name)
+	}
+	return nil
+}
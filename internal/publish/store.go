@@ -0,0 +1,43 @@
+package publish
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+)
+
+// UploadSnap uploads a snap to the Snap Store and releases it to a channel,
+// e.g. edge. snapcraft reads the credentials of the store from
+// SNAPCRAFT_STORE_CREDENTIALS.
+func UploadSnap(artifact Artifact, channel string) error {
+	cmd := exec.Command(build.SnapcraftBin(), "upload", "--release="+channel, artifact.localPath)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to upload %s to the Snap Store", artifact.Name)
+	}
+	return nil
+}
+
+// CreateGithubPrerelease creates a pre-release of a GitHub repository
+// (owner/repo) with the artifacts as assets. The tag is created on the
+// default branch when it doesn't exist. gh reads the token from GH_TOKEN or
+// GITHUB_TOKEN.
+func CreateGithubPrerelease(repository, tag, title, notes string, artifacts []Artifact) error {
+	args := []string{"release", "create", tag, "--repo", repository, "--prerelease", "--title", title, "--notes", notes}
+	for _, artifact := range artifacts {
+		args = append(args, artifact.localPath)
+	}
+	cmd := exec.Command(build.GhBin(), args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the release %s of %s", tag, repository)
+	}
+	return nil
+}
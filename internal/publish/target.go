@@ -0,0 +1,298 @@
+package publish
+
+import (
+	"bytes"
+	"crypto/x509"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/log"
+	"github.com/go-flutter-desktop/hover/internal/retry"
+)
+
+// Target is a remote location artifacts are published to.
+type Target interface {
+	// Upload uploads a local file to a path relative to the target location.
+	Upload(localPath, remotePath, contentType string) error
+	// Download returns the content of a file relative to the target location,
+	// or nil when the file doesn't exist.
+	Download(remotePath string) ([]byte, error)
+}
+
+// NewTarget returns the target for a destination URL. Supported schemes are
+// s3:// (using the aws CLI), http(s):// (HTTP PUT) and webdav(s):// (HTTP PUT,
+// creating the missing collections).
+func NewTarget(destination string, publicRead bool) (Target, error) {
+	u, err := url.Parse(destination)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse destination %s", destination)
+	}
+	switch u.Scheme {
+	case "s3":
+		return &s3Target{location: strings.TrimSuffix(destination, "/"), publicRead: publicRead}, nil
+	case "http", "https":
+		if publicRead {
+			log.Warnf("--public-read is ignored for %s destinations, access is configured by the server.", u.Scheme)
+		}
+		return &httpTarget{base: u}, nil
+	case "webdav", "webdavs":
+		if publicRead {
+			log.Warnf("--public-read is ignored for %s destinations, access is configured by the server.", u.Scheme)
+		}
+		u.Scheme = strings.Replace(u.Scheme, "webdav", "http", 1)
+		return &httpTarget{base: u, webdav: true}, nil
+	default:
+		return nil, errors.Errorf("unsupported destination '%s', must start with s3://, http(s):// or webdav(s)://", destination)
+	}
+}
+
+type s3Target struct {
+	location   string
+	publicRead bool
+}
+
+func (t *s3Target) Upload(localPath, remotePath, contentType string) error {
+	args := []string{"s3", "cp", localPath, t.location + "/" + remotePath, "--content-type", contentType}
+	if t.publicRead {
+		args = append(args, "--acl", "public-read")
+	}
+	return retry.Do("Uploading "+localPath, func() error {
+		var stderr bytes.Buffer
+		cmdAws := exec.Command(build.AwsBin(), args...)
+		cmdAws.Stderr = io.MultiWriter(os.Stderr, &stderr)
+		err := cmdAws.Run()
+		if err != nil {
+			return s3Error(stderr.String(), errors.Wrapf(err, "aws s3 cp of %s failed", localPath))
+		}
+		return nil
+	})
+}
+
+func (t *s3Target) Download(remotePath string) ([]byte, error) {
+	var out []byte
+	err := retry.Do("Downloading "+remotePath, func() error {
+		var stderr bytes.Buffer
+		cmdAws := exec.Command(build.AwsBin(), "s3", "cp", t.location+"/"+remotePath, "-")
+		cmdAws.Stderr = &stderr
+		var err error
+		out, err = cmdAws.Output()
+		if err != nil {
+			if strings.Contains(stderr.String(), "(404)") {
+				out = nil
+				return nil
+			}
+			return s3Error(stderr.String(), errors.Wrapf(err, "aws s3 cp of %s failed: %s", remotePath, strings.TrimSpace(stderr.String())))
+		}
+		return nil
+	})
+	return out, err
+}
+
+// s3PermanentErrors are the errors of the aws CLI retrying can't fix: the
+// missing or rejected credentials, the denied permissions and the missing
+// buckets.
+var s3PermanentErrors = []string{
+	"(AccessDenied)",
+	"(InvalidAccessKeyId)",
+	"(SignatureDoesNotMatch)",
+	"(ExpiredToken)",
+	"(NoSuchBucket)",
+	"(InvalidBucketName)",
+	"(401)",
+	"(403)",
+	"Unable to locate credentials",
+}
+
+// s3Error returns the error of a failed aws CLI command, permanent when its
+// output shows retrying can't fix it.
+func s3Error(stderr string, err error) error {
+	for _, permanent := range s3PermanentErrors {
+		if strings.Contains(stderr, permanent) {
+			return retry.Permanent(err)
+		}
+	}
+	return err
+}
+
+type httpTarget struct {
+	base   *url.URL
+	webdav bool
+	// collections already created on the webdav server
+	collections map[string]bool
+}
+
+var httpClient = http.Client{
+	Timeout: time.Minute * 30, // artifacts can be large
+}
+
+func (t *httpTarget) url(remotePath string) string {
+	u := *t.base
+	u.User = nil
+	u.Path = path.Join(u.Path, remotePath)
+	return u.String()
+}
+
+// newRequest creates a request authenticated with the credentials of the
+// destination URL, or the HOVER_PUBLISH_TOKEN bearer token. A nil body sends
+// no content.
+func (t *httpTarget) newRequest(method, remotePath string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, t.url(remotePath), body)
+	if err != nil {
+		return nil, err
+	}
+	if t.base.User != nil {
+		password, _ := t.base.User.Password()
+		req.SetBasicAuth(t.base.User.Username(), password)
+	} else if token := os.Getenv("HOVER_PUBLISH_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req, nil
+}
+
+func (t *httpTarget) do(req *http.Request) ([]byte, int, error) {
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, res.StatusCode, err
+	}
+	return body, res.StatusCode, nil
+}
+
+// mkcol creates the collections (directories) of a path on a webdav server.
+func (t *httpTarget) mkcol(remotePath string) error {
+	if t.collections == nil {
+		t.collections = make(map[string]bool)
+	}
+	var collection string
+	for _, part := range strings.Split(path.Dir(remotePath), "/") {
+		if part == "." || part == "" {
+			continue
+		}
+		collection = path.Join(collection, part)
+		if t.collections[collection] {
+			continue
+		}
+		req, err := t.newRequest("MKCOL", collection, nil)
+		if err != nil {
+			return err
+		}
+		_, status, err := t.do(req)
+		if err != nil {
+			return requestError(errors.Wrapf(err, "failed to create collection %s", collection))
+		}
+		// 405 Method Not Allowed is returned when the collection exists
+		if status != http.StatusCreated && status != http.StatusMethodNotAllowed {
+			return statusError(status, errors.Errorf("failed to create collection %s: %s", collection, http.StatusText(status)))
+		}
+		t.collections[collection] = true
+	}
+	return nil
+}
+
+// Upload puts a file, the upload is retried as a whole: putting the same
+// file twice is harmless. The file is streamed from the disk, it's opened
+// again for each attempt.
+func (t *httpTarget) Upload(localPath, remotePath, contentType string) error {
+	return retry.Do("Uploading "+localPath, func() error {
+		if t.webdav {
+			err := t.mkcol(remotePath)
+			if err != nil {
+				return err
+			}
+		}
+		file, err := os.Open(localPath)
+		if err != nil {
+			return retry.Permanent(errors.Wrapf(err, "failed to open %s", localPath))
+		}
+		defer file.Close()
+		info, err := file.Stat()
+		if err != nil {
+			return retry.Permanent(errors.Wrapf(err, "failed to stat %s", localPath))
+		}
+		var content io.Reader = file
+		if info.Size() == 0 {
+			content = http.NoBody
+		}
+		req, err := t.newRequest(http.MethodPut, remotePath, content)
+		if err != nil {
+			return retry.Permanent(err)
+		}
+		// the length of an *os.File isn't known to net/http, without it the
+		// file would be sent chunked, which many servers refuse for a PUT
+		req.ContentLength = info.Size()
+		req.Header.Set("Content-Type", contentType)
+		body, status, err := t.do(req)
+		if err != nil {
+			return requestError(errors.Wrapf(err, "failed to upload %s", localPath))
+		}
+		if status < 200 || status >= 300 {
+			return statusError(status, errors.Errorf("failed to upload %s: %s %s", localPath, http.StatusText(status), strings.TrimSpace(string(body))))
+		}
+		return nil
+	})
+}
+
+func (t *httpTarget) Download(remotePath string) ([]byte, error) {
+	var content []byte
+	err := retry.Do("Downloading "+remotePath, func() error {
+		req, err := t.newRequest(http.MethodGet, remotePath, nil)
+		if err != nil {
+			return retry.Permanent(err)
+		}
+		body, status, err := t.do(req)
+		if err != nil {
+			return requestError(errors.Wrapf(err, "failed to download %s", remotePath))
+		}
+		if status == http.StatusNotFound {
+			content = nil
+			return nil
+		}
+		if status != http.StatusOK {
+			return statusError(status, errors.Errorf("failed to download %s: %s", remotePath, http.StatusText(status)))
+		}
+		content = body
+		return nil
+	})
+	return content, err
+}
+
+// statusError returns the error of an HTTP status, permanent unless the
+// status is transient.
+func statusError(status int, err error) error {
+	if retry.IsTransientStatus(status) {
+		return err
+	}
+	return retry.Permanent(err)
+}
+
+// requestError returns the error of a request that got no response,
+// permanent when retrying can't fix it: an unknown host or a certificate the
+// server can't be trusted with.
+func requestError(err error) error {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return retry.Permanent(err)
+	}
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var certificateErr x509.CertificateInvalidError
+	if errors.As(err, &unknownAuthorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &certificateErr) {
+		return retry.Permanent(err)
+	}
+	return err
+}
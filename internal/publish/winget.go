@@ -0,0 +1,114 @@
+package publish
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// UpdateWingetRepository updates a git repository of winget manifests laid
+// out like winget-pkgs, e.g. a fork of microsoft/winget-pkgs or the manifests
+// a private REST source is indexed from. The manifests of the windows-winget
+// zip are written to manifests/<initial>/<Publisher>/<Name>/<version> in the
+// clone of the repository, committed and pushed to the current branch.
+// Nothing is pushed when the manifests didn't change.
+func UpdateWingetRepository(remote string, manifests Artifact) error {
+	files, err := readWingetManifests(manifests.localPath)
+	if err != nil {
+		return err
+	}
+	identifier, version, err := wingetManifestVersion(files)
+	if err != nil {
+		return errors.Wrapf(err, "invalid winget manifests in %s", manifests.Name)
+	}
+	dir := WingetManifestsPath(identifier, version)
+	return updateGitRepository(remote, "HEAD", identifier+" "+version, func(repositoryPath string) error {
+		manifestsPath := filepath.Join(repositoryPath, filepath.FromSlash(dir))
+		err := os.MkdirAll(manifestsPath, 0755)
+		if err != nil {
+			return errors.Wrap(err, "failed to create the manifests directory")
+		}
+		for name, content := range files {
+			err = ioutil.WriteFile(filepath.Join(manifestsPath, name), content, 0644)
+			if err != nil {
+				return errors.Wrapf(err, "failed to write %s", name)
+			}
+		}
+		return nil
+	}, dir)
+}
+
+// WingetManifestsPath returns the directory of the manifests of a package
+// version in winget-pkgs: the segments of the identifier are directories,
+// under the lowercase initial of the publisher.
+func WingetManifestsPath(identifier, version string) string {
+	return path.Join("manifests", strings.ToLower(identifier[:1]), strings.Replace(identifier, ".", "/", -1), version)
+}
+
+// readWingetManifests returns the yaml files of a windows-winget zip, by name.
+func readWingetManifests(zipPath string) (map[string][]byte, error) {
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open %s", zipPath)
+	}
+	defer reader.Close()
+	files := make(map[string][]byte)
+	for _, file := range reader.File {
+		name := path.Base(file.Name)
+		if file.FileInfo().IsDir() || !strings.HasSuffix(name, ".yaml") {
+			continue
+		}
+		content, err := readZipFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s in %s", file.Name, zipPath)
+		}
+		files[name] = content
+	}
+	return files, nil
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return ioutil.ReadAll(rc)
+}
+
+// wingetManifestVersion returns the PackageIdentifier and PackageVersion of
+// the version manifest.
+func wingetManifestVersion(files map[string][]byte) (identifier, version string, err error) {
+	for name, content := range files {
+		var manifest struct {
+			PackageIdentifier string `yaml:"PackageIdentifier"`
+			PackageVersion    string `yaml:"PackageVersion"`
+			ManifestType      string `yaml:"ManifestType"`
+		}
+		err = yaml.Unmarshal(content, &manifest)
+		if err != nil {
+			return "", "", errors.Wrapf(err, "failed to decode %s", name)
+		}
+		if manifest.ManifestType != "version" {
+			continue
+		}
+		if manifest.PackageIdentifier == "" || manifest.PackageVersion == "" {
+			return "", "", errors.Errorf("%s has no PackageIdentifier or PackageVersion", name)
+		}
+		// the segments of the identifier and the version are directories of
+		// the repository
+		for _, segment := range append(strings.Split(manifest.PackageIdentifier, "."), manifest.PackageVersion) {
+			if segment == "" || segment == ".." || strings.ContainsAny(segment, `/\`) {
+				return "", "", errors.Errorf("the PackageIdentifier %s and PackageVersion %s of %s aren't valid directory names", manifest.PackageIdentifier, manifest.PackageVersion, name)
+			}
+		}
+		return manifest.PackageIdentifier, manifest.PackageVersion, nil
+	}
+	return "", "", errors.New("there is no manifest with the ManifestType version")
+}
@@ -8,6 +8,7 @@ import (
 
 	"gopkg.in/yaml.v2"
 
+	"github.com/go-flutter-desktop/hover/internal/explain"
 	"github.com/go-flutter-desktop/hover/internal/log"
 	"github.com/pkg/errors"
 )
@@ -18,6 +19,8 @@ type PubSpec struct {
 	Description  string
 	Version      string
 	Author       string
+	Homepage     string
+	Environment  map[string]string
 	Dependencies map[string]interface{}
 	Flutter      map[string]interface{}
 }
@@ -58,8 +61,7 @@ func GetPubSpec() PubSpec {
 	if pubspec.Name == "" {
 		pub, err := ReadPubSpecFile("pubspec.yaml")
 		if err != nil {
-			log.Errorf("%v", err)
-			log.Errorf("This command should be run from the root of your Flutter project.")
+			log.ErrorCodef(explain.NotAFlutterProject, "%v\nThis command should be run from the root of your Flutter project.", err)
 			os.Exit(1)
 		}
 		pubspec = *pub
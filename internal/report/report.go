@@ -0,0 +1,161 @@
+// Package report writes the results of the checking commands of hover in the
+// formats the CI platforms render: JUnit XML reports, and GitHub Actions
+// annotations.
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The output formats of the results.
+const (
+	FormatText   = "text"
+	FormatJUnit  = "junit"
+	FormatGitHub = "github"
+)
+
+// Formats lists the output formats of the results.
+var Formats = []string{FormatText, FormatJUnit, FormatGitHub}
+
+// Problem is a failure of a check, in a file when File is set.
+type Problem struct {
+	File    string // Path of the file, relative to the project
+	Message string
+}
+
+// Case is a check, which passed when it has no problems.
+type Case struct {
+	Name     string
+	Problems []Problem
+	Skipped  string // Why the check was skipped
+	Time     time.Duration
+}
+
+// Suite contains the checks of a command.
+type Suite struct {
+	Name  string
+	Cases []Case
+}
+
+// CheckFormat returns an error when the format isn't a known output format.
+func CheckFormat(format string) error {
+	for _, known := range Formats {
+		if format == known {
+			return nil
+		}
+	}
+	return errors.Errorf("invalid format '%s', must be one of %s", format, strings.Join(Formats, ", "))
+}
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the suite to a JUnit XML report. The problems of a check
+// are its failure.
+func WriteJUnit(path string, suite Suite) error {
+	report := junitSuite{Name: suite.Name, Tests: len(suite.Cases)}
+	var total time.Duration
+	for _, c := range suite.Cases {
+		total += c.Time
+		testCase := junitCase{Name: c.Name, ClassName: suite.Name, Time: seconds(c.Time)}
+		switch {
+		case len(c.Problems) > 0:
+			report.Failures++
+			var lines []string
+			for _, problem := range c.Problems {
+				lines = append(lines, problem.String())
+			}
+			testCase.Failure = &junitMessage{Message: c.Problems[0].Message, Text: strings.Join(lines, "\n")}
+			if len(c.Problems) > 1 {
+				testCase.Failure.Message = fmt.Sprintf("%d problems", len(c.Problems))
+			}
+		case c.Skipped != "":
+			report.Skipped++
+			testCase.Skipped = &junitMessage{Message: c.Skipped}
+		}
+		report.Cases = append(report.Cases, testCase)
+	}
+	report.Time = seconds(total)
+
+	content, err := xml.MarshalIndent(junitSuites{Suites: []junitSuite{report}}, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the JUnit report")
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0775)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the directory of %s", path)
+	}
+	err = ioutil.WriteFile(path, append([]byte(xml.Header), append(content, '\n')...), 0664)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write %s", path)
+	}
+	return nil
+}
+
+// WriteGitHubAnnotations writes the problems as the error annotations of
+// GitHub Actions, shown on the files of the pull requests.
+func WriteGitHubAnnotations(w io.Writer, suite Suite) {
+	for _, c := range suite.Cases {
+		for _, problem := range c.Problems {
+			properties := "title=" + escapeProperty(suite.Name+": "+c.Name)
+			if problem.File != "" {
+				properties = "file=" + escapeProperty(filepath.ToSlash(problem.File)) + "," + properties
+			}
+			fmt.Fprintf(w, "::error %s::%s\n", properties, escapeData(problem.Message))
+		}
+	}
+}
+
+func (p Problem) String() string {
+	if p.File == "" {
+		return p.Message
+	}
+	return filepath.ToSlash(p.File) + ": " + p.Message
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// escapeData escapes the message of a workflow command.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes the properties of a workflow command.
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,78 @@
+package report
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSuite = Suite{
+	Name: "lint-packaging",
+	Cases: []Case{
+		{Name: "linux-deb", Time: 1500 * time.Millisecond},
+		{Name: "linux-rpm", Problems: []Problem{
+			{File: "go/packaging/linux-rpm/app.spec.tmpl", Message: "unknown template data .foo"},
+			{Message: "100% broken,\nreally: yes"},
+		}},
+		{Name: "windows-msi", Skipped: "not initialized for packaging"},
+	},
+}
+
+func TestWriteJUnit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hover-report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "reports", "lint-packaging.xml")
+	err = WriteJUnit(path, testSuite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<testsuite name="lint-packaging" tests="3" failures="1" skipped="1" time="1.500">`,
+		`<testcase name="linux-deb" classname="lint-packaging" time="1.500"></testcase>`,
+		`<failure message="2 problems">go/packaging/linux-rpm/app.spec.tmpl: unknown template data .foo&#xA;100% broken,&#xA;really: yes</failure>`,
+		`<skipped message="not initialized for packaging"></skipped>`,
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("the JUnit report doesn't contain %s:\n%s", want, content)
+		}
+	}
+}
+
+func TestWriteGitHubAnnotations(t *testing.T) {
+	var out bytes.Buffer
+	WriteGitHubAnnotations(&out, testSuite)
+	want := "::error file=go/packaging/linux-rpm/app.spec.tmpl,title=lint-packaging%3A linux-rpm::unknown template data .foo\n" +
+		"::error title=lint-packaging%3A linux-rpm::100%25 broken,%0Areally: yes\n"
+	if out.String() != want {
+		t.Errorf("WriteGitHubAnnotations() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestCheckFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		wantErr bool
+	}{
+		{"text", false},
+		{"junit", false},
+		{"github", false},
+		{"xml", true},
+		{"", true},
+	}
+	for _, test := range tests {
+		if err := CheckFormat(test.format); (err != nil) != test.wantErr {
+			t.Errorf("CheckFormat(%q) = %v, want error %v", test.format, err, test.wantErr)
+		}
+	}
+}
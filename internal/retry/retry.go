@@ -0,0 +1,117 @@
+// Package retry retries the operations failing on transient errors, such as
+// the network blips of the downloads and the uploads, waiting longer after
+// each failed attempt.
+package retry
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-flutter-desktop/hover/internal/log"
+)
+
+// Policy is how many times, and how long apart, an operation is attempted.
+type Policy struct {
+	Attempts int           // Attempts of an operation, 1 doesn't retry it
+	Delay    time.Duration // Delay before the first retry, doubled after each failed attempt
+	MaxDelay time.Duration // Longest delay between two attempts
+}
+
+// DefaultPolicy is the policy used when the retry section of go/hover.yaml
+// doesn't set one.
+var DefaultPolicy = Policy{
+	Attempts: 3,
+	Delay:    2 * time.Second,
+	MaxDelay: time.Minute,
+}
+
+var policy = DefaultPolicy
+
+// SetPolicy sets the policy of the operations retried by hover.
+func SetPolicy(p Policy) {
+	if p.Attempts < 1 {
+		p.Attempts = 1
+	}
+	if p.MaxDelay < p.Delay {
+		p.MaxDelay = p.Delay
+	}
+	policy = p
+}
+
+// permanentError is an error retrying can't fix.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+
+// Cause returns the error, for errors.Cause.
+func (e permanentError) Cause() error { return e.err }
+
+// Permanent marks an error retrying can't fix, such as a 404 or a rejected
+// notarization: Do returns it without attempting the operation again.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err}
+}
+
+// IsPermanent reports whether an error, or an error it wraps, was marked
+// Permanent.
+func IsPermanent(err error) bool {
+	for err != nil {
+		if _, ok := err.(permanentError); ok {
+			return true
+		}
+		switch wrapper := err.(type) {
+		case interface{ Cause() error }:
+			err = wrapper.Cause()
+		case interface{ Unwrap() error }:
+			err = wrapper.Unwrap()
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// IsTransientStatus reports whether an HTTP status is worth retrying: the
+// timeouts, the rate limits and the server errors.
+func IsTransientStatus(status int) bool {
+	return status == http.StatusRequestTimeout || status == http.StatusTooManyRequests || status >= 500
+}
+
+// Do runs an operation until it succeeds, fails with a Permanent error or
+// runs out of attempts, and returns its last error. The retries are logged
+// as warnings with the description of the operation, e.g. "Uploading
+// app.deb".
+func Do(description string, operation func() error) error {
+	return DoLogf(log.Warnf, description, operation)
+}
+
+// DoLogf is Do, logging the retries with warnf.
+func DoLogf(warnf func(format string, args ...interface{}), description string, operation func() error) error {
+	delay := policy.Delay
+	for attempt := 1; ; attempt++ {
+		err := operation()
+		if err == nil {
+			return nil
+		}
+		if permanent, ok := err.(permanentError); ok {
+			return permanent.err
+		}
+		if IsPermanent(err) {
+			return err
+		}
+		if attempt >= policy.Attempts {
+			return err
+		}
+		warnf("%s failed (attempt %d of %d): %v. Retrying in %s", description, attempt, policy.Attempts, err, delay)
+		time.Sleep(delay)
+		delay *= 2
+		if delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package retry
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestDo(t *testing.T) {
+	SetPolicy(Policy{Attempts: 3})
+	defer SetPolicy(DefaultPolicy)
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name         string
+		err          func(attempt int) error
+		wantAttempts int
+		wantErr      string
+	}{
+		{"succeeds", func(int) error { return nil }, 1, ""},
+		{"succeeds after a transient error", func(attempt int) error {
+			if attempt == 1 {
+				return errFailed
+			}
+			return nil
+		}, 2, ""},
+		{"runs out of attempts", func(int) error { return errFailed }, 3, "failed"},
+		{"permanent", func(int) error { return Permanent(errFailed) }, 1, "failed"},
+		{"wrapped permanent", func(int) error { return errors.Wrap(Permanent(errFailed), "upload") }, 1, "upload: failed"},
+		{"permanent wrapped by fmt", func(int) error { return fmt.Errorf("upload: %w", Permanent(errFailed)) }, 1, "upload: failed"},
+	}
+	for _, test := range tests {
+		attempts := 0
+		err := DoLogf(func(string, ...interface{}) {}, test.name, func() error {
+			attempts++
+			return test.err(attempts)
+		})
+		if attempts != test.wantAttempts {
+			t.Errorf("%s: %d attempts, want %d", test.name, attempts, test.wantAttempts)
+		}
+		var gotErr string
+		if err != nil {
+			gotErr = err.Error()
+		}
+		if gotErr != test.wantErr {
+			t.Errorf("%s: error = %q, want %q", test.name, gotErr, test.wantErr)
+		}
+	}
+}
+
+func TestIsPermanent(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errFailed, false},
+		{errors.Wrap(errFailed, "upload"), false},
+		{Permanent(errFailed), true},
+		{errors.Wrapf(errors.WithStack(Permanent(errFailed)), "upload %s", "app.deb"), true},
+	}
+	for _, test := range tests {
+		if got := IsPermanent(test.err); got != test.want {
+			t.Errorf("IsPermanent(%v) = %v, want %v", test.err, got, test.want)
+		}
+	}
+}
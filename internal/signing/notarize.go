@@ -0,0 +1,154 @@
+package signing
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/retry"
+)
+
+// notarizableExtensions are the extensions of the packages the Apple notary
+// service accepts.
+var notarizableExtensions = map[string]bool{
+	".dmg": true,
+	".pkg": true,
+	".zip": true,
+}
+
+// stapleableExtensions are the extensions of the packages a notarization
+// ticket can be stapled to, a zip archive can't hold it.
+var stapleableExtensions = map[string]bool{
+	".dmg": true,
+	".pkg": true,
+	".app": true,
+}
+
+// IsNotarizable reports whether a file can be notarized.
+func IsNotarizable(path string) bool {
+	return notarizableExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// IsStapleable reports whether a notarization ticket can be stapled to a
+// file.
+func IsStapleable(path string) bool {
+	return stapleableExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// Notarizer submits the darwin packages to the Apple notary service with
+// notarytool.
+type Notarizer struct {
+	credentials []string
+}
+
+// NewNotarizer returns a notarizer authenticated with the notarytool keychain
+// profile of HOVER_NOTARY_PROFILE, or the App Store Connect API key of
+// APPLE_API_KEY (the path of the .p8 key), APPLE_API_KEY_ID and
+// APPLE_API_ISSUER. The secrets don't appear in the process list this way.
+func NewNotarizer() (*Notarizer, error) {
+	if profile := os.Getenv("HOVER_NOTARY_PROFILE"); profile != "" {
+		return &Notarizer{credentials: []string{"--keychain-profile", profile}}, nil
+	}
+	key := os.Getenv("APPLE_API_KEY")
+	keyID := os.Getenv("APPLE_API_KEY_ID")
+	issuer := os.Getenv("APPLE_API_ISSUER")
+	if key == "" || keyID == "" || issuer == "" {
+		return nil, errors.New("HOVER_NOTARY_PROFILE, or APPLE_API_KEY, APPLE_API_KEY_ID and APPLE_API_ISSUER must be set to notarize")
+	}
+	return &Notarizer{credentials: []string{"--key", key, "--key-id", keyID, "--issuer", issuer}}, nil
+}
+
+// notarySubmission is a submission to the notary service, as printed by
+// notarytool.
+type notarySubmission struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// Notarize submits a package to the notary service and waits for its
+// verdict. The submission and the polling of its verdict are retried
+// separately: the polling resumes with the same submission when the network
+// fails during the wait, the package isn't uploaded again.
+func (n *Notarizer) Notarize(path string) error {
+	var submission notarySubmission
+	err := retry.Do("Submitting "+path+" to the notary service", func() error {
+		var err error
+		submission, err = n.notarytool("submit", path)
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "notarytool failed to notarize %s", path)
+	}
+	err = retry.Do("Waiting for the notarization of "+path, func() error {
+		var err error
+		submission, err = n.notarytool("wait", submission.ID)
+		if err == nil && submission.Status == "In Progress" {
+			err = errors.Errorf("the submission %s is still in progress", submission.ID)
+		}
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "notarytool failed to notarize %s", path)
+	}
+	if submission.Status != "Accepted" {
+		return errors.Errorf("the notarization of %s is %s: %s, run `xcrun notarytool log %s` for the details", path, submission.Status, submission.Message, submission.ID)
+	}
+	return nil
+}
+
+// notarytool runs a notarytool command printing a submission. Its exit code
+// is ignored when it prints the submission, notarytool wait fails when the
+// package is rejected.
+func (n *Notarizer) notarytool(command, arg string) (notarySubmission, error) {
+	args := append([]string{"notarytool", command, arg, "--output-format", "json"}, n.credentials...)
+	output, err := exec.Command(build.XcrunBin(), args...).Output()
+	var submission notarySubmission
+	if jsonErr := json.Unmarshal(output, &submission); jsonErr != nil || submission.ID == "" {
+		if err == nil {
+			err = jsonErr
+		}
+		if err == nil {
+			err = errors.New("no submission id")
+		}
+		details := string(output)
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			details += string(exitErr.Stderr)
+		}
+		err = errors.Wrapf(err, "notarytool %s: %s", command, strings.TrimSpace(details))
+		for _, permanent := range notaryPermanentErrors {
+			if strings.Contains(details, permanent) {
+				return submission, retry.Permanent(err)
+			}
+		}
+		return submission, err
+	}
+	return submission, nil
+}
+
+// notaryPermanentErrors are the failures of notarytool retrying can't fix:
+// the rejected credentials and the missing keychain profile.
+var notaryPermanentErrors = []string{
+	"status code: 401",
+	"status code: 403",
+	"Unable to authenticate",
+	"No Keychain password item found",
+}
+
+// Staple staples the notarization ticket to a package, so gatekeeper
+// accepts it offline. The ticket can take a moment to be published once the
+// package is accepted, the stapling is retried.
+func Staple(path string) error {
+	return retry.Do("Stapling "+path, func() error {
+		output, err := exec.Command(build.XcrunBin(), "stapler", "staple", path).CombinedOutput()
+		if err != nil {
+			return errors.Wrapf(err, "stapler failed to staple %s: %s", path, strings.TrimSpace(string(output)))
+		}
+		return nil
+	})
+}
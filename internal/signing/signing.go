@@ -0,0 +1,263 @@
+// Package signing signs the windows executables and packages (Authenticode)
+// with keys stored in a local keystore, Azure Key Vault, AWS KMS or a PKCS#11
+// token, and notarizes the darwin packages. The signing is done by jsign and
+// the notarization by notarytool, the credentials are read from the
+// environment.
+package signing
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-flutter-desktop/hover/internal/build"
+	"github.com/go-flutter-desktop/hover/internal/config"
+)
+
+// Signing providers.
+const (
+	ProviderFile          = "file"
+	ProviderAzureKeyVault = "azure-key-vault"
+	ProviderAwsKms        = "aws-kms"
+	ProviderPkcs11        = "pkcs11"
+)
+
+// DefaultTimestampURL is the timestamping authority used when none is
+// configured. Without a timestamp, the signatures expire with the
+// certificate.
+const DefaultTimestampURL = "http://timestamp.digicert.com"
+
+// storepassEnv passes the credentials to jsign, they don't appear in the
+// process list this way.
+const storepassEnv = "HOVER_SIGNING_STOREPASS"
+
+// signableExtensions are the extensions of the files jsign can sign.
+var signableExtensions = map[string]bool{
+	".exe":  true,
+	".dll":  true,
+	".msi":  true,
+	".msix": true,
+	".appx": true,
+	".cab":  true,
+	".ps1":  true,
+}
+
+// Signer signs files in place.
+type Signer interface {
+	Sign(path string) error
+}
+
+// IsSignable reports whether a file can be signed.
+func IsSignable(path string) bool {
+	return signableExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// IsPortableExecutable reports whether a file is a windows executable or
+// library (PE), which starts with the MZ header of DOS.
+func IsPortableExecutable(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	header := make([]byte, 2)
+	_, err = io.ReadFull(file, header)
+	return err == nil && string(header) == "MZ"
+}
+
+// NewSigner returns the signer of the provider configured in go/hover.yaml.
+// The credentials are checked before anything is built.
+func NewSigner(c config.SigningConfig) (Signer, error) {
+	if c.Keystore == "" {
+		return nil, errors.New("signing.keystore isn't set")
+	}
+	if c.Alias == "" && c.Provider != ProviderFile {
+		return nil, errors.New("signing.alias isn't set")
+	}
+	s := &jsignSigner{
+		keystore:     c.Keystore,
+		alias:        c.Alias,
+		certificate:  c.Certificate,
+		timestampURL: c.TimestampURL,
+	}
+	if s.timestampURL == "" {
+		s.timestampURL = DefaultTimestampURL
+	}
+	var storepass string
+	var err error
+	switch c.Provider {
+	case ProviderFile:
+		// the keystore is a .pfx, .p12 or .jks file
+		s.storetype = "PKCS12"
+		if strings.HasSuffix(strings.ToLower(c.Keystore), ".jks") {
+			s.storetype = "JKS"
+		}
+		storepass = os.Getenv("HOVER_SIGNING_PASSWORD")
+	case ProviderAzureKeyVault:
+		// the keystore is the name of the key vault, the alias the name of
+		// the certificate. The access token expires, it's requested again
+		// when a long build outlives it.
+		s.storetype = "AZUREKEYVAULT"
+		token := &azureToken{}
+		_, err = token.get()
+		s.storepass = token.get
+	case ProviderAwsKms:
+		// the keystore is the AWS region, the alias the id of the key. KMS
+		// only stores the key, the certificate chain is a local file.
+		s.storetype = "AWS"
+		if c.Certificate == "" {
+			return nil, errors.New("signing.certificate must be set to the certificate chain of the AWS KMS key")
+		}
+		storepass, err = awsCredentials()
+	case ProviderPkcs11:
+		// the keystore is the SunPKCS11 configuration of the token, the alias
+		// the label of the key
+		s.storetype = "PKCS11"
+		storepass = os.Getenv("HOVER_SIGNING_PIN")
+		if storepass == "" {
+			err = errors.New("HOVER_SIGNING_PIN must be set to the PIN of the PKCS#11 token")
+		}
+	case "":
+		return nil, errors.New("signing.provider isn't set")
+	default:
+		return nil, errors.Errorf("unknown signing provider '%s', must be %s, %s, %s or %s", c.Provider, ProviderFile, ProviderAzureKeyVault, ProviderAwsKms, ProviderPkcs11)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.storepass == nil {
+		s.storepass = func() (string, error) { return storepass, nil }
+	}
+	return s, nil
+}
+
+type jsignSigner struct {
+	storetype string
+	keystore  string
+	// storepass returns the credentials of the keystore, when they're used
+	storepass    func() (string, error)
+	alias        string
+	certificate  string
+	timestampURL string
+}
+
+func (s *jsignSigner) Sign(path string) error {
+	args := []string{
+		"--storetype", s.storetype,
+		"--keystore", s.keystore,
+		"--storepass", "env:" + storepassEnv,
+		"--tsaurl", s.timestampURL,
+		"--replace",
+	}
+	if s.alias != "" {
+		args = append(args, "--alias", s.alias)
+	}
+	if s.certificate != "" {
+		args = append(args, "--certfile", s.certificate)
+	}
+	args = append(args, path)
+	storepass, err := s.storepass()
+	if err != nil {
+		return err
+	}
+	cmdJsign := exec.Command(build.JsignBin(), args...)
+	cmdJsign.Env = append(os.Environ(), storepassEnv+"="+storepass)
+	output, err := cmdJsign.CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "jsign failed to sign %s: %s", path, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// azureTokenRefreshMargin is how long before its expiry an Azure access token
+// is renewed, so it doesn't expire while jsign signs a file.
+const azureTokenRefreshMargin = 5 * time.Minute
+
+// azureToken is the key vault access token of the signer, renewed when it
+// expires.
+type azureToken struct {
+	mutex     sync.Mutex
+	value     string
+	expiresOn time.Time
+}
+
+// get returns the access token, requesting a new one when there is none yet
+// or when it is about to expire. The token of AZURE_ACCESS_TOKEN can't be
+// renewed, it is returned as is.
+func (t *azureToken) get() (string, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if token := os.Getenv("AZURE_ACCESS_TOKEN"); token != "" {
+		return token, nil
+	}
+	if t.value != "" && time.Now().Add(azureTokenRefreshMargin).Before(t.expiresOn) {
+		return t.value, nil
+	}
+	value, expiresIn, err := azureAccessToken()
+	if err != nil {
+		return "", err
+	}
+	t.value = value
+	t.expiresOn = time.Now().Add(expiresIn)
+	return t.value, nil
+}
+
+// azureAccessToken requests a key vault access token for the service
+// principal of AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET. It
+// returns the token and its lifetime.
+func azureAccessToken() (string, time.Duration, error) {
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	clientSecret := os.Getenv("AZURE_CLIENT_SECRET")
+	if tenantID == "" || clientID == "" || clientSecret == "" {
+		return "", 0, errors.New("AZURE_ACCESS_TOKEN, or AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET must be set to sign with Azure Key Vault")
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.PostForm("https://login.microsoftonline.com/"+url.PathEscape(tenantID)+"/oauth2/v2.0/token", url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"scope":         {"https://vault.azure.net/.default"},
+	})
+	if err != nil {
+		return "", 0, errors.Wrap(err, "failed to request an Azure access token")
+	}
+	defer resp.Body.Close()
+	var token struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		ErrorDescription string `json:"error_description"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return "", 0, errors.Wrap(err, "failed to decode the Azure access token")
+	}
+	if resp.StatusCode != http.StatusOK || token.AccessToken == "" {
+		return "", 0, errors.Errorf("failed to request an Azure access token: %s %s", resp.Status, token.ErrorDescription)
+	}
+	return token.AccessToken, time.Duration(token.ExpiresIn) * time.Second, nil
+}
+
+// awsCredentials returns the AWS credentials of the standard environment
+// variables in the format of jsign.
+func awsCredentials() (string, error) {
+	accessKeyID := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretAccessKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKeyID == "" || secretAccessKey == "" {
+		return "", errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set to sign with AWS KMS")
+	}
+	credentials := accessKeyID + "|" + secretAccessKey
+	if sessionToken := os.Getenv("AWS_SESSION_TOKEN"); sessionToken != "" {
+		credentials += "|" + sessionToken
+	}
+	return credentials, nil
+}
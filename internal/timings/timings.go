@@ -0,0 +1,147 @@
+// Package timings measures the phases of the builds, and keeps their
+// history to show how the duration of a build evolves across runs.
+package timings
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// The phases of a build.
+const (
+	EngineDownload  = "engine download"
+	FlutterBuild    = "flutter build"
+	Copy            = "copy"
+	GoBuild         = "go build"
+	PackagingScript = "packaging script"
+	DockerBuild     = "docker build"
+)
+
+// historySize is the number of runs kept in the history of a target.
+const historySize = 20
+
+// Phase is the time spent in a phase of a build. A phase run several times,
+// e.g. the packaging script of a format and of the formats it depends on,
+// accumulates.
+type Phase struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Run is a build of a target.
+type Run struct {
+	Target    string        `json:"target"`
+	StartedOn time.Time     `json:"startedOn"`
+	Duration  time.Duration `json:"duration"`
+	Phases    []Phase       `json:"phases"`
+}
+
+// PhaseDuration returns the time spent in a phase of the run.
+func (r Run) PhaseDuration(name string) (time.Duration, bool) {
+	for _, phase := range r.Phases {
+		if phase.Name == name {
+			return phase.Duration, true
+		}
+	}
+	return 0, false
+}
+
+var (
+	mutex  sync.Mutex
+	phases []Phase
+)
+
+// Start starts measuring a phase, until the returned function is called.
+func Start(name string) func() {
+	startedOn := time.Now()
+	return func() {
+		add(name, time.Since(startedOn))
+	}
+}
+
+func add(name string, duration time.Duration) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for i := range phases {
+		if phases[i].Name == name {
+			phases[i].Duration += duration
+			return
+		}
+	}
+	phases = append(phases, Phase{Name: name, Duration: duration})
+}
+
+// Phases returns the phases measured so far, in the order they started.
+func Phases() []Phase {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return append([]Phase(nil), phases...)
+}
+
+// Record appends a run to the history file, keeping the last runs of each
+// target, and returns the previous runs of its target, oldest first.
+func Record(historyPath string, run Run) ([]Run, error) {
+	var history []Run
+	content, err := ioutil.ReadFile(historyPath)
+	if err == nil {
+		// a corrupted history is started again
+		_ = json.Unmarshal(content, &history)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var previous []Run
+	for _, r := range history {
+		if r.Target == run.Target {
+			previous = append(previous, r)
+		}
+	}
+	var kept []Run
+	count := len(previous)
+	for _, r := range history {
+		if r.Target == run.Target && count >= historySize {
+			count--
+			continue
+		}
+		kept = append(kept, r)
+	}
+	kept = append(kept, run)
+
+	content, err = json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	err = os.MkdirAll(filepath.Dir(historyPath), 0755)
+	if err != nil {
+		return nil, err
+	}
+	err = ioutil.WriteFile(historyPath, content, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return previous, nil
+}
+
+// Average returns the average duration of a phase in runs, or of the whole
+// runs when the name is empty, and the number of runs that had the phase.
+func Average(runs []Run, name string) (time.Duration, int) {
+	var total time.Duration
+	var count int
+	for _, r := range runs {
+		if name == "" {
+			total += r.Duration
+			count++
+		} else if duration, ok := r.PhaseDuration(name); ok {
+			total += duration
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, 0
+	}
+	return total / time.Duration(count), count
+}
@@ -0,0 +1,88 @@
+// Package webhook notifies slack, discord or generic HTTP webhooks of build
+// events.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Events of a build.
+const (
+	EventStart   = "start"
+	EventSuccess = "success"
+	EventFailure = "failure"
+)
+
+// Event is sent to the webhooks. Duration and Artifacts are only set once
+// the build finished.
+type Event struct {
+	Event     string   `json:"event"`
+	Project   string   `json:"project"`
+	Version   string   `json:"version"`
+	Target    string   `json:"target"`
+	Duration  float64  `json:"duration,omitempty"` // In seconds
+	Artifacts []string `json:"artifacts,omitempty"`
+}
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+// Send posts an event to a webhook of a kind (slack, discord or generic), as
+// a message for slack and discord, or as JSON for the generic webhooks.
+func Send(webhookURL, kind string, event Event) error {
+	var payload interface{}
+	switch kind {
+	case "slack":
+		payload = map[string]string{"text": event.message()}
+	case "discord":
+		payload = map[string]string{"content": event.message()}
+	case "", "generic":
+		payload = event
+	default:
+		return errors.Errorf("unknown webhook kind '%s', must be slack, discord or generic", kind)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the webhook payload")
+	}
+	resp, err := client.Post(webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// the URL of the webhook is a secret, it isn't part of the error.
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return errors.Wrap(err, "failed to send the webhook")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		content, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return errors.Errorf("the webhook responded %s: %s", resp.Status, strings.TrimSpace(string(content)))
+	}
+	return nil
+}
+
+// message is the human readable text of an event.
+func (e Event) message() string {
+	var text string
+	switch e.Event {
+	case EventStart:
+		text = fmt.Sprintf("Building %s %s for %s", e.Project, e.Version, e.Target)
+	case EventSuccess:
+		text = fmt.Sprintf("Built %s %s for %s in %.0fs", e.Project, e.Version, e.Target, e.Duration)
+	case EventFailure:
+		text = fmt.Sprintf("Failed to build %s %s for %s after %.0fs", e.Project, e.Version, e.Target, e.Duration)
+	}
+	for _, artifact := range e.Artifacts {
+		text += "\n" + artifact
+	}
+	return text
+}